@@ -0,0 +1,151 @@
+// Command loadgen drives concurrent A2A messages against an agent at a fixed
+// arrival rate and reports latency percentiles and errors, so regressions in
+// the agent loop and event pipeline show up before a release.
+//
+// With -llm-script, it also serves a scripted mock LLM (a mockllm config,
+// as used by the e2e tests) for the agent under test to point its model at,
+// which takes provider latency and cost out of the measurement.
+//
+// Usage:
+//
+//	loadgen -target http://localhost:8080 -rate 20 -duration 1m -message "list the pods"
+//
+// It exits non-zero when a -max-* threshold is exceeded.
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/kagent-dev/kagent/go/adk/pkg/loadgen"
+	"github.com/kagent-dev/mockllm"
+)
+
+type stringList []string
+
+func (s *stringList) String() string     { return strings.Join(*s, ",") }
+func (s *stringList) Set(v string) error { *s = append(*s, v); return nil }
+
+func main() {
+	log.SetFlags(log.LstdFlags | log.Lmicroseconds)
+
+	var (
+		cfg          loadgen.Config
+		thresholds   loadgen.Thresholds
+		messages     stringList
+		messagesFile string
+		target       string
+		stream       bool
+		format       string
+		llmScript    string
+		llmListen    string
+	)
+	flag.StringVar(&target, "target", "", "base URL of the agent's A2A endpoint")
+	flag.Float64Var(&cfg.Rate, "rate", 10, "messages started per second")
+	flag.IntVar(&cfg.Concurrency, "concurrency", 50, "maximum messages in flight; arrivals beyond it are dropped")
+	flag.DurationVar(&cfg.Duration, "duration", 30*time.Second, "how long to send messages for (0 = until -requests)")
+	flag.IntVar(&cfg.Requests, "requests", 0, "stop after this many messages (0 = until -duration)")
+	flag.DurationVar(&cfg.Timeout, "timeout", 2*time.Minute, "timeout for each message")
+	flag.Var(&messages, "message", "message text to send; repeat to cycle through several")
+	flag.StringVar(&messagesFile, "messages-file", "", "file with one message per line")
+	flag.BoolVar(&stream, "stream", true, "send with message/stream and report time to first event")
+	flag.StringVar(&format, "format", "text", "report format: text or json")
+	flag.Float64Var(&thresholds.MaxP95Ms, "max-p95", 0, "fail when p95 latency exceeds this many milliseconds")
+	flag.Float64Var(&thresholds.MaxP99Ms, "max-p99", 0, "fail when p99 latency exceeds this many milliseconds")
+	flag.Float64Var(&thresholds.MaxErrorRate, "max-error-rate", 0, "fail when the share of failed messages exceeds this, e.g. 0.01")
+	flag.StringVar(&llmScript, "llm-script", "", "mockllm config file to serve as the agent's model")
+	flag.StringVar(&llmListen, "llm-listen", ":8090", "address for the mock LLM server")
+	flag.Parse()
+
+	if target == "" {
+		log.Fatal("loadgen: -target is required")
+	}
+	if format != "text" && format != "json" {
+		log.Fatalf("loadgen: unknown report format %q", format)
+	}
+	cfg.Messages = messages
+	if messagesFile != "" {
+		fileMessages, err := readMessages(messagesFile)
+		if err != nil {
+			log.Fatalf("loadgen: %v", err)
+		}
+		cfg.Messages = append(cfg.Messages, fileMessages...)
+	}
+	if len(cfg.Messages) == 0 {
+		cfg.Messages = []string{"Hello"}
+	}
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("loadgen: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if llmScript != "" {
+		llmCfg, err := mockllm.LoadConfigFromFile(filepath.Base(llmScript), os.DirFS(filepath.Dir(llmScript)).(fs.ReadFileFS))
+		if err != nil {
+			log.Fatalf("loadgen: failed to load mock LLM script: %v", err)
+		}
+		llmCfg.ListenAddr = llmListen
+		server := mockllm.NewServer(llmCfg)
+		baseURL, err := server.Start(ctx)
+		if err != nil {
+			log.Fatalf("loadgen: failed to start mock LLM server: %v", err)
+		}
+		defer server.Stop(context.Background())
+		log.Printf("loadgen: mock LLM server started at %s", baseURL)
+	}
+
+	sender, err := loadgen.NewA2ASender(ctx, target, nil, stream)
+	if err != nil {
+		log.Fatalf("loadgen: %v", err)
+	}
+	log.Printf("loadgen: sending to %s at %.1f/s (concurrency %d)", target, cfg.Rate, cfg.Concurrency)
+	report, err := loadgen.Run(ctx, cfg, sender)
+	if err != nil {
+		log.Fatalf("loadgen: %v", err)
+	}
+
+	if format == "json" {
+		err = report.WriteJSON(os.Stdout)
+	} else {
+		err = report.WriteText(os.Stdout)
+	}
+	if err != nil {
+		log.Fatalf("loadgen: failed to write report: %v", err)
+	}
+	if err := report.Check(thresholds); err != nil {
+		fmt.Fprintf(os.Stderr, "loadgen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// readMessages returns the non-empty lines of path.
+func readMessages(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open messages file: %w", err)
+	}
+	defer f.Close()
+	var messages []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			messages = append(messages, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read messages file: %w", err)
+	}
+	return messages, nil
+}
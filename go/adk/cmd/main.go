@@ -12,15 +12,24 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/go-logr/zapr"
 	"github.com/kagent-dev/kagent/go/adk/pkg/a2a"
+	"github.com/kagent-dev/kagent/go/adk/pkg/a2a/server"
 	"github.com/kagent-dev/kagent/go/adk/pkg/app"
 	"github.com/kagent-dev/kagent/go/adk/pkg/auth"
 	"github.com/kagent-dev/kagent/go/adk/pkg/config"
+	"github.com/kagent-dev/kagent/go/adk/pkg/langfuse"
+	"github.com/kagent-dev/kagent/go/adk/pkg/mcp"
 	kagentmemory "github.com/kagent-dev/kagent/go/adk/pkg/memory"
+	"github.com/kagent-dev/kagent/go/adk/pkg/prompts"
 	runnerpkg "github.com/kagent-dev/kagent/go/adk/pkg/runner"
 	"github.com/kagent-dev/kagent/go/adk/pkg/session"
+	"github.com/kagent-dev/kagent/go/adk/pkg/skills"
 	"github.com/kagent-dev/kagent/go/adk/pkg/telemetry"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	adkplugin "google.golang.org/adk/plugin"
+	adksession "google.golang.org/adk/session"
+	adktool "google.golang.org/adk/tool"
+	"google.golang.org/genai"
 )
 
 func setupLogger(logLevel string) (logr.Logger, *zap.Logger) {
@@ -138,7 +147,12 @@ func main() {
 	var httpClient *http.Client
 	var tokenService *auth.KAgentTokenService
 	if kagentURL != "" {
-		tokenService = auth.NewKAgentTokenService(appName)
+		audiences, err := auth.TokenAudiencesFromEnv()
+		if err != nil {
+			logger.Error(err, "Invalid token audience configuration")
+			os.Exit(1)
+		}
+		tokenService = auth.NewKAgentTokenServiceWithAudiences(appName, audiences)
 		if err := tokenService.Start(context.Background()); err != nil {
 			logger.Error(err, "Failed to start token service")
 		} else {
@@ -152,7 +166,12 @@ func main() {
 	// (session creation/lookup). This must be created before the executor.
 	var sessionService *session.KAgentSessionService
 	if kagentURL != "" {
-		sessionService = session.NewKAgentSessionService(kagentURL, httpClient)
+		sessionOpts, err := session.ClientOptionsFromEnv()
+		if err != nil {
+			logger.Error(err, "Invalid session client configuration")
+			os.Exit(1)
+		}
+		sessionService = session.NewKAgentSessionServiceWithOptions(kagentURL, httpClient, sessionOpts)
 		logger.Info("Using KAgent session service", "url", kagentURL)
 	} else {
 		logger.Info("No KAGENT_URL set, using in-memory session and no task persistence")
@@ -178,12 +197,72 @@ func main() {
 		logger.Info("Memory service enabled", "appName", appName)
 	}
 
-	runnerConfig, subagentSessionIDs, err := runnerpkg.CreateRunnerConfig(ctx, agentConfig, sessionService, appName, memoryService, kagentURL, httpClient)
+	// Export runs and task feedback to Langfuse when configured.
+	var (
+		extraPlugins      []*adkplugin.Plugin
+		feedbackExporters []server.FeedbackExporter
+	)
+	langfuseConfig, langfuseEnabled, err := langfuse.ConfigFromAgent(agentConfig.Langfuse)
+	if err != nil {
+		logger.Error(err, "Invalid Langfuse configuration")
+		os.Exit(1)
+	}
+	if langfuseEnabled {
+		exporter, err := langfuse.New(langfuseConfig, logger)
+		if err != nil {
+			logger.Error(err, "Failed to create Langfuse exporter")
+			os.Exit(1)
+		}
+		p, err := exporter.ADKPlugin()
+		if err != nil {
+			logger.Error(err, "Failed to create Langfuse ADK plugin")
+			os.Exit(1)
+		}
+		extraPlugins = append(extraPlugins, p)
+		feedbackExporters = append(feedbackExporters, exporter)
+		logger.Info("Exporting traces to Langfuse", "host", langfuseConfig.Host)
+	}
+
+	// MCP servers may be registered at runtime through app.ToolsPattern.
+	var (
+		registeredTools *mcp.DynamicToolsets
+		extraToolsets   []adktool.Toolset
+	)
+	toolRegistration, err := app.ToolRegistrationEnabledFromEnv()
+	if err != nil {
+		logger.Error(err, "Invalid tool registration configuration")
+		os.Exit(1)
+	}
+	if toolRegistration && kagentURL != "" {
+		propagateToken := strings.ToLower(os.Getenv("KAGENT_PROPAGATE_TOKEN")) == "true"
+		registeredTools = mcp.NewDynamicToolsets(propagateToken, logger.WithName("registered-tools"))
+		extraToolsets = append(extraToolsets, registeredTools)
+	}
+
+	runnerConfig, subagentSessionIDs, err := runnerpkg.CreateRunnerConfig(ctx, agentConfig, sessionService, appName, memoryService, kagentURL, httpClient, extraToolsets, extraPlugins...)
 	if err != nil {
 		logger.Error(err, "Failed to create Google ADK Runner config")
 		os.Exit(1)
 	}
 
+	promptRouter, err := prompts.NewRouter(agentConfig.Prompts)
+	if err != nil {
+		logger.Error(err, "Invalid prompt versions")
+		os.Exit(1)
+	}
+
+	workspace, err := skills.NewWorkspaceFromEnv(a2a.SkillsDirectory(""))
+	if err != nil {
+		logger.Error(err, "Invalid session workspace configuration")
+		os.Exit(1)
+	}
+
+	allowAnonymous, err := a2a.AllowAnonymousFromEnv()
+	if err != nil {
+		logger.Error(err, "Invalid anonymous request configuration")
+		os.Exit(1)
+	}
+
 	stream := agentConfig.GetStream()
 	executor := a2a.NewKAgentExecutor(a2a.KAgentExecutorConfig{
 		RunnerConfig:       runnerConfig,
@@ -191,9 +270,67 @@ func main() {
 		SessionService:     sessionService,
 		Stream:             stream,
 		AppName:            appName,
+		AllowAnonymous:     allowAnonymous,
+		Workspace:          workspace,
+		TaskStateRules:     agentConfig.TaskStateRules,
+		Prompts:            promptRouter,
 		Logger:             logger,
 	})
 
+	// Garbage-collect session workspaces when limits are configured.
+	handlers := map[string]http.Handler{}
+	gcConfig, err := skills.GCConfigFromEnv()
+	if err != nil {
+		logger.Error(err, "Invalid workspace garbage collection configuration")
+		os.Exit(1)
+	}
+	if gcConfig.Enabled() {
+		var onDelete skills.SessionDeleteFunc
+		if sessionService != nil {
+			onDelete = func(ctx context.Context, sessionID, userID string) error {
+				return sessionService.Delete(ctx, &adksession.DeleteRequest{AppName: appName, UserID: userID, SessionID: sessionID})
+			}
+		}
+		workspaceGC := skills.NewWorkspaceGC(skills.WorkspaceBaseDir(), gcConfig, onDelete)
+		workspaceGC.Start(ctx)
+		handlers["/admin/workspaces/gc"] = server.DescribeHandler(workspaceGC, map[string]server.OpenAPIOperation{
+			http.MethodPost: {
+				Summary:   "Collect session workspaces",
+				Responses: map[string]string{"200": "The sweep result as JSON"},
+			},
+		})
+		logger.Info("Workspace garbage collection enabled",
+			"maxAge", gcConfig.MaxAge, "maxSessionBytes", gcConfig.MaxSessionBytes, "interval", gcConfig.Interval)
+	}
+
+	// Report workspace file changes into their sessions when enabled.
+	watchConfig, err := skills.WatchConfigFromEnv()
+	if err != nil {
+		logger.Error(err, "Invalid workspace watch configuration")
+		os.Exit(1)
+	}
+	if watchConfig.Enabled {
+		sessions := runnerConfig.SessionService
+		onChange := func(ctx context.Context, sessionID, userID string, changes []skills.FileChange) error {
+			resp, err := sessions.Get(ctx, &adksession.GetRequest{AppName: appName, UserID: userID, SessionID: sessionID})
+			if err != nil {
+				return err
+			}
+			event := adksession.NewEvent("")
+			event.Author = skills.WorkspaceEventAuthor
+			event.Content = genai.NewContentFromText(skills.DescribeFileChanges(changes), genai.RoleUser)
+			return sessions.AppendEvent(ctx, resp.Session, event)
+		}
+		workspaceWatcher, err := skills.NewWorkspaceWatcher(skills.WorkspaceBaseDir(), watchConfig, onChange)
+		if err != nil {
+			logger.Error(err, "Failed to start the workspace watcher")
+			os.Exit(1)
+		}
+		workspaceWatcher.Start(ctx)
+		logger.Info("Workspace watcher enabled",
+			"debounce", watchConfig.Debounce, "include", watchConfig.Include, "exclude", watchConfig.Exclude)
+	}
+
 	// Build the agent card.
 	if agentCard == nil {
 		agentCard = &a2atype.AgentCard{
@@ -207,18 +344,27 @@ func main() {
 		StateTransitionHistory: true,
 	}
 
+	var readinessChecks []server.ReadinessCheck
+	if tokenService != nil {
+		readinessChecks = append(readinessChecks, server.ReadinessCheck{Name: "token", Check: tokenService.Health})
+	}
+
 	// Delegate server, task store, and remaining infrastructure to app.New.
 	// Passing HTTPClient prevents app.New from creating a second token service.
 	kagentApp, err := app.New(app.AppConfig{
-		AgentCard:       *agentCard,
-		Host:            *host,
-		Port:            port,
-		KAgentURL:       kagentURL,
-		AppName:         appName,
-		ShutdownTimeout: 5 * time.Second,
-		Logger:          logger,
-		HTTPClient:      httpClient,
-		Agent:           runnerConfig.Agent,
+		AgentCard:         *agentCard,
+		Host:              *host,
+		Port:              port,
+		KAgentURL:         kagentURL,
+		AppName:           appName,
+		ShutdownTimeout:   5 * time.Second,
+		Logger:            logger,
+		HTTPClient:        httpClient,
+		Agent:             runnerConfig.Agent,
+		Handlers:          handlers,
+		FeedbackExporters: feedbackExporters,
+		ReadinessChecks:   readinessChecks,
+		Tools:             registeredTools,
 	}, executor)
 	if err != nil {
 		logger.Error(err, "Failed to create app")
@@ -0,0 +1,116 @@
+package a2a
+
+import (
+	"encoding/json"
+
+	"github.com/kagent-dev/kagent/go/adk/pkg/knowledge"
+	adksession "google.golang.org/adk/session"
+)
+
+// MetadataKeyCitations carries the sources retrieved during a run on the
+// final artifact and the final status update, under kagent_citations.
+const MetadataKeyCitations = "citations"
+
+// loadMemoryToolName is the name of ADK's load_memory tool.
+const loadMemoryToolName = "load_memory"
+
+// Citation identifies a source that contributed context to a run: a
+// knowledge chunk returned by retrieve_docs or a memory recalled by
+// load_memory. Memories preloaded into the instruction are not tool results
+// and are not cited.
+type Citation struct {
+	// Source is "knowledge" or "memory".
+	Source      string  `json:"source"`
+	Collection  string  `json:"collection,omitempty"`
+	DocumentID  string  `json:"document_id,omitempty"`
+	StartOffset int     `json:"start_offset,omitempty"`
+	EndOffset   int     `json:"end_offset,omitempty"`
+	MemoryID    string  `json:"memory_id,omitempty"`
+	Score       float64 `json:"score,omitempty"`
+}
+
+// citationTracker collects the citations of one run in the order their
+// sources were retrieved, dropping repeats.
+type citationTracker struct {
+	citations []Citation
+	seen      map[Citation]bool
+}
+
+// add records the sources returned by retrieval tool responses in adkEvent.
+func (c *citationTracker) add(adkEvent *adksession.Event) {
+	if adkEvent == nil || adkEvent.Partial || adkEvent.Content == nil {
+		return
+	}
+	for _, part := range adkEvent.Content.Parts {
+		if part == nil || part.FunctionResponse == nil {
+			continue
+		}
+		switch part.FunctionResponse.Name {
+		case knowledge.RetrieveDocsToolName:
+			var resp struct {
+				Results []knowledge.Result `json:"results"`
+			}
+			if roundTripJSON(part.FunctionResponse.Response, &resp) {
+				for _, r := range resp.Results {
+					c.record(Citation{
+						Source:      "knowledge",
+						Collection:  r.Collection,
+						DocumentID:  r.DocumentID,
+						StartOffset: r.StartOffset,
+						EndOffset:   r.EndOffset,
+						Score:       r.Score,
+					})
+				}
+			}
+		case loadMemoryToolName:
+			// memory.Entry has no JSON tags, so its ID encodes as "ID".
+			var resp struct {
+				Memories []struct {
+					ID string `json:"ID"`
+				} `json:"memories"`
+			}
+			if roundTripJSON(part.FunctionResponse.Response, &resp) {
+				for _, m := range resp.Memories {
+					if m.ID != "" {
+						c.record(Citation{Source: "memory", MemoryID: m.ID})
+					}
+				}
+			}
+		}
+	}
+}
+
+func (c *citationTracker) record(citation Citation) {
+	key := citation
+	key.Score = 0
+	if c.seen[key] {
+		return
+	}
+	if c.seen == nil {
+		c.seen = make(map[Citation]bool)
+	}
+	c.seen[key] = true
+	c.citations = append(c.citations, citation)
+}
+
+// stamp adds the collected citations to meta under kagent_citations.
+func (c *citationTracker) stamp(meta map[string]any) {
+	if len(c.citations) == 0 {
+		return
+	}
+	var citations []any
+	if !roundTripJSON(c.citations, &citations) {
+		return
+	}
+	meta[GetKAgentMetadataKey(MetadataKeyCitations)] = citations
+}
+
+// roundTripJSON converts in to out through JSON. Tool responses hold the
+// tool's Go values in process and plain JSON values once persisted.
+func roundTripJSON(in, out any) bool {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, out) == nil
+}
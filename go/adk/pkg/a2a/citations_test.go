@@ -0,0 +1,48 @@
+package a2a
+
+import (
+	"testing"
+
+	"github.com/kagent-dev/kagent/go/adk/pkg/knowledge"
+	"google.golang.org/adk/memory"
+	adksession "google.golang.org/adk/session"
+	"google.golang.org/genai"
+)
+
+func TestCitationTracker(t *testing.T) {
+	var c citationTracker
+	meta := map[string]any{}
+	c.stamp(meta)
+	if len(meta) != 0 {
+		t.Fatalf("stamp() without citations added %v", meta)
+	}
+
+	toolResult := func(name string, response map[string]any) *adksession.Event {
+		ev := adksession.NewEvent("inv")
+		ev.Content = genai.NewContentFromFunctionResponse(name, response, genai.RoleUser)
+		return ev
+	}
+	docs := toolResult(knowledge.RetrieveDocsToolName, map[string]any{"results": []knowledge.Result{
+		{Collection: "runbooks", DocumentID: "restart.md", StartOffset: 0, EndOffset: 120, Score: 0.9},
+		{Collection: "runbooks", DocumentID: "scale.md", StartOffset: 40, EndOffset: 200, Score: 0.7},
+	}})
+	c.add(docs)
+	c.add(docs)
+	// Persisted sessions hold plain JSON values rather than the tool's types.
+	c.add(toolResult("load_memory", map[string]any{"memories": []any{map[string]any{"ID": "mem-1"}}}))
+	c.add(toolResult("load_memory", map[string]any{"memories": []memory.Entry{{ID: "mem-2"}, {}}}))
+	c.add(toolResult("get_weather", map[string]any{"temp": 20}))
+	c.stamp(meta)
+
+	got, ok := meta["kagent_citations"].([]any)
+	if !ok || len(got) != 4 {
+		t.Fatalf("kagent_citations = %v, want 4 citations", meta["kagent_citations"])
+	}
+	first := got[0].(map[string]any)
+	if first["source"] != "knowledge" || first["document_id"] != "restart.md" || first["end_offset"] != float64(120) {
+		t.Errorf("first citation = %v", first)
+	}
+	if mem := got[3].(map[string]any); mem["source"] != "memory" || mem["memory_id"] != "mem-2" {
+		t.Errorf("last citation = %v", mem)
+	}
+}
@@ -0,0 +1,75 @@
+package a2a
+
+import (
+	"time"
+
+	a2atype "github.com/a2aproject/a2a-go/a2a"
+)
+
+// Clock supplies the timestamps of emitted status updates.
+type Clock interface {
+	Now() time.Time
+}
+
+// IDGenerator supplies the IDs of emitted messages and artifacts.
+type IDGenerator interface {
+	NewID() string
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+type uuidGenerator struct{}
+
+func (uuidGenerator) NewID() string { return a2atype.NewMessageID() }
+
+// SystemClock returns the wall clock.
+func SystemClock() Clock { return systemClock{} }
+
+// UUIDGenerator returns a generator of random UUIDs, the IDs a2a-go uses.
+func UUIDGenerator() IDGenerator { return uuidGenerator{} }
+
+// eventFactory builds the A2A messages and events the executor emits. It
+// mirrors the a2a-go constructors but takes timestamps and IDs from an
+// injectable Clock and IDGenerator so event streams can be made
+// deterministic in tests.
+type eventFactory struct {
+	clock Clock
+	ids   IDGenerator
+}
+
+func newEventFactory(clock Clock, ids IDGenerator) eventFactory {
+	if clock == nil {
+		clock = SystemClock()
+	}
+	if ids == nil {
+		ids = UUIDGenerator()
+	}
+	return eventFactory{clock: clock, ids: ids}
+}
+
+// agentMessage creates a message from the agent with a generated ID.
+func (f eventFactory) agentMessage(parts ...a2atype.Part) *a2atype.Message {
+	return &a2atype.Message{
+		ID:    f.ids.NewID(),
+		Role:  a2atype.MessageRoleAgent,
+		Parts: parts,
+	}
+}
+
+// statusUpdate creates a status update event timestamped by the clock.
+func (f eventFactory) statusUpdate(info a2atype.TaskInfoProvider, state a2atype.TaskState, msg *a2atype.Message) *a2atype.TaskStatusUpdateEvent {
+	ev := a2atype.NewStatusUpdateEvent(info, state, msg)
+	now := f.clock.Now()
+	ev.Status.Timestamp = &now
+	return ev
+}
+
+// artifactEvent creates an artifact update event for a new artifact with a
+// generated ID.
+func (f eventFactory) artifactEvent(info a2atype.TaskInfoProvider, parts ...a2atype.Part) *a2atype.TaskArtifactUpdateEvent {
+	ev := a2atype.NewArtifactEvent(info, parts...)
+	ev.Artifact.ID = a2atype.ArtifactID(f.ids.NewID())
+	return ev
+}
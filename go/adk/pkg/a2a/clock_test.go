@@ -0,0 +1,124 @@
+package a2a
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"testing"
+	"time"
+
+	a2atype "github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+	"github.com/a2aproject/a2a-go/a2asrv/eventqueue"
+	"github.com/go-logr/logr"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/runner"
+	adksession "google.golang.org/adk/session"
+	"google.golang.org/genai"
+)
+
+// fixedClock always returns the same instant.
+type fixedClock struct{ t time.Time }
+
+func (c fixedClock) Now() time.Time { return c.t }
+
+// sequentialIDs returns id-1, id-2, ...
+type sequentialIDs struct{ n int }
+
+func (g *sequentialIDs) NewID() string {
+	g.n++
+	return fmt.Sprintf("id-%d", g.n)
+}
+
+// recordingQueue records the events written by the executor.
+type recordingQueue struct {
+	eventqueue.Queue
+	events []a2atype.Event
+}
+
+func (q *recordingQueue) Write(_ context.Context, event a2atype.Event) error {
+	q.events = append(q.events, event)
+	return nil
+}
+
+// textLLM answers every request with a fixed text.
+type textLLM struct{ text string }
+
+func (m *textLLM) Name() string { return "text" }
+
+func (m *textLLM) GenerateContent(_ context.Context, _ *model.LLMRequest, _ bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		yield(&model.LLMResponse{Content: genai.NewContentFromText(m.text, genai.RoleModel)}, nil)
+	}
+}
+
+func TestEventFactory(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	f := newEventFactory(fixedClock{now}, &sequentialIDs{})
+	info := a2atype.TaskInfo{TaskID: "task", ContextID: "ctx"}
+
+	msg := f.agentMessage(a2atype.TextPart{Text: "hi"})
+	if msg.ID != "id-1" || msg.Role != a2atype.MessageRoleAgent {
+		t.Errorf("agentMessage() = %+v", msg)
+	}
+	status := f.statusUpdate(info, a2atype.TaskStateWorking, msg)
+	if !status.Status.Timestamp.Equal(now) || status.TaskID != "task" {
+		t.Errorf("statusUpdate() = %+v", status)
+	}
+	artifact := f.artifactEvent(info, a2atype.TextPart{Text: "result"})
+	if artifact.Artifact.ID != "id-2" {
+		t.Errorf("artifactEvent() ID = %q, want id-2", artifact.Artifact.ID)
+	}
+
+	defaults := newEventFactory(nil, nil)
+	if defaults.agentMessage().ID == "" || defaults.statusUpdate(info, a2atype.TaskStateWorking, nil).Status.Timestamp == nil {
+		t.Error("default factory left IDs or timestamps empty")
+	}
+}
+
+func TestExecutor_DeterministicEventStream(t *testing.T) {
+	run := func() []byte {
+		t.Helper()
+		ctx := context.Background()
+		agent, err := llmagent.New(llmagent.Config{Name: "echo", Model: &textLLM{text: "hello"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		sessions := adksession.InMemoryService()
+		if _, err := sessions.Create(ctx, &adksession.CreateRequest{AppName: "app", UserID: "A2A_USER_ctx-1", SessionID: "ctx-1"}); err != nil {
+			t.Fatal(err)
+		}
+		executor := NewKAgentExecutor(KAgentExecutorConfig{
+			RunnerConfig:    runner.Config{AppName: "app", Agent: agent, SessionService: sessions},
+			AppName:         "app",
+			AllowAnonymous:  true,
+			SkillsDirectory: "",
+			Clock:           fixedClock{time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+			IDGenerator:     &sequentialIDs{},
+			Logger:          logr.Discard(),
+		})
+		executor.skillsDirectory = ""
+
+		queue := &recordingQueue{}
+		reqCtx := &a2asrv.RequestContext{
+			Message:   &a2atype.Message{ID: "msg-1", Role: a2atype.MessageRoleUser, Parts: a2atype.ContentParts{a2atype.TextPart{Text: "hi"}}},
+			TaskID:    "task-1",
+			ContextID: "ctx-1",
+		}
+		if err := executor.Execute(ctx, reqCtx, queue); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		data, err := json.Marshal(queue.events)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+
+	first, second := run(), run()
+	if string(first) != string(second) {
+		t.Errorf("event streams differ between runs:\n%s\n%s", first, second)
+	}
+}
@@ -7,6 +7,68 @@ const (
 	StateKeySource      = "source"
 )
 
+// Message metadata keys for edit-and-regenerate. A user message carrying
+// kagent_edit_event_id replaces the session event with that ID: the event and
+// everything after it are superseded before the agent runs again. The IDs of
+// the superseded events are reported on the working status update under
+// kagent_superseded_event_ids so UIs can drop the old branch.
+const (
+	MetadataKeyEditEventID        = "edit_event_id"
+	MetadataKeySupersededEventIDs = "superseded_event_ids"
+)
+
+// MetadataKeyHistory lets a client bring a conversation started elsewhere.
+// The value under kagent_history is an array of A2A messages, oldest first,
+// each with a messageId. They are appended to the session before the agent
+// runs, skipping messages whose ID the session already has, so resending
+// the same history with later messages is harmless. Each appended event
+// gets an ID of its own and keeps the messageId in its custom metadata
+// under MetadataKeyMessageID.
+const (
+	MetadataKeyHistory   = "history"
+	MetadataKeyMessageID = "message_id"
+)
+
+// Metadata keys for run progress. The final status update carries the token
+// usage summed over the run under kagent_usage_metadata. Artifacts saved by
+// tools are emitted as artifact updates named after the file, with the saved
+// version under kagent_artifact_version. A working status update carrying
+// kagent_iteration marks the start of each model call after tool results.
+const (
+	MetadataKeyUsage           = "usage_metadata"
+	MetadataKeyArtifactVersion = "artifact_version"
+	MetadataKeyIteration       = "iteration"
+)
+
+// MetadataKeyRequestID carries the X-Request-ID of the call that started the
+// run on every event, under kagent_request_id, so a conversation can be
+// correlated across the controller and agents.
+const MetadataKeyRequestID = "request_id"
+
+// MetadataKeyGeneration lets a message override the agent's generation
+// settings for the run it starts. The value under kagent_generation is an
+// object with the fields of the agent's generation config, such as
+// {"temperature": 0.2, "stop_sequences": ["END"]}.
+const MetadataKeyGeneration = "generation"
+
+// Metadata keys for prompt versions. A message may pin the prompt version
+// of the run it starts under kagent_prompt_version. Every event of a run
+// carries the version used under the same key, and the experiment and arm
+// that chose it under kagent_prompt_experiment and kagent_prompt_arm, so
+// feedback and evaluations can be attributed to a version.
+const (
+	MetadataKeyPromptVersion    = "prompt_version"
+	MetadataKeyPromptExperiment = "prompt_experiment"
+	MetadataKeyPromptArm        = "prompt_arm"
+)
+
+// MetadataKeyPlan carries the plan of a plan-execute agent on the events it
+// emits, under kagent_plan: an object with the steps, each with a title and
+// a status of pending, in_progress, done or failed, and the number of
+// re-plans so far. The agent stores it in the event's custom metadata under
+// the same key.
+const MetadataKeyPlan = "plan"
+
 // A2A DataPart metadata keys and type values.
 const (
 	A2ADataPartMetadataTypeKey              = "type"
@@ -3,9 +3,12 @@ package a2a
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"maps"
+	"slices"
 
 	a2atype "github.com/a2aproject/a2a-go/a2a"
+	"google.golang.org/adk/artifact"
 	"google.golang.org/adk/server/adka2a" //nolint:staticcheck // kagent still uses a2a-go v1; this ADK package is the compatibility adapter.
 	adksession "google.golang.org/adk/session"
 	"google.golang.org/genai"
@@ -19,9 +22,19 @@ func isEmptyDataPart(part a2atype.Part) bool {
 	return ok && len(dp.Data) == 0
 }
 
-// filterTextParts returns only TextParts from the given parts.
+// filterTextParts returns only TextParts from the given parts. Parts that
+// are all text, as streamed chunks are, are returned as is without copying.
 func filterTextParts(parts a2atype.ContentParts) a2atype.ContentParts {
-	var out a2atype.ContentParts
+	textCount := 0
+	for _, p := range parts {
+		if _, ok := p.(a2atype.TextPart); ok {
+			textCount++
+		}
+	}
+	if textCount == len(parts) {
+		return parts
+	}
+	out := make(a2atype.ContentParts, 0, textCount)
 	for _, p := range parts {
 		if _, ok := p.(a2atype.TextPart); ok {
 			out = append(out, p)
@@ -30,6 +43,34 @@ func filterTextParts(parts a2atype.ContentParts) a2atype.ContentParts {
 	return out
 }
 
+// convertEventParts converts the content parts of an ADK event to A2A parts,
+// dropping parts that fail to convert and the empty DataParts ADK emits as
+// cleanup signals, and stamping subagent session IDs onto function calls.
+func convertEventParts(adkEvent *adksession.Event, subagentSessionIDs map[string]string) a2atype.ContentParts {
+	if adkEvent == nil || adkEvent.Content == nil {
+		return nil
+	}
+	a2aParts := make(a2atype.ContentParts, 0, len(adkEvent.Content.Parts))
+	for _, genaiPart := range adkEvent.Content.Parts {
+		if genaiPart == nil {
+			continue
+		}
+		a2aPart, err := adka2a.ToA2APart(genaiPart, adkEvent.LongRunningToolIDs)
+		if err != nil {
+			continue
+		}
+		if isEmptyDataPart(a2aPart) {
+			continue
+		}
+		// Stamp kagent_subagent_session_id onto function_call DataParts.
+		if len(subagentSessionIDs) > 0 {
+			a2aPart = stampSubagentSessionID(a2aPart, subagentSessionIDs)
+		}
+		a2aParts = append(a2aParts, a2aPart)
+	}
+	return a2aParts
+}
+
 // messageToGenAIContent converts an A2A message to *genai.Content using kagent
 // a2aPartConverter logic: handle kagent_type and adk_type DataParts explicitly,
 // drop unrecognised DataParts (e.g. HITL decision parts).
@@ -169,28 +210,120 @@ func toA2AMetadataMap(v any) (map[string]any, error) {
 }
 
 // buildEventMeta merges the base metadata with per-event fields such as
-// invocation_id, author, branch, usage_metadata, etc.
+// invocation_id, author, branch, usage_metadata and the plan of plan-execute
+// agents.
 func buildEventMeta(baseMeta map[string]any, adkEvent *adksession.Event) map[string]any {
-	result := maps.Clone(baseMeta)
+	// Room for the per-event keys set below, so adding them never grows
+	// the map.
+	result := make(map[string]any, len(baseMeta)+eventMetaKeys)
+	maps.Copy(result, baseMeta)
 	if adkEvent == nil {
 		return result
 	}
-	for k, v := range map[string]string{
-		"invocation_id": adkEvent.InvocationID,
-		"author":        adkEvent.Author,
-		"branch":        adkEvent.Branch,
-	} {
-		if v != "" {
-			result[adka2a.ToA2AMetaKey(k)] = v
-		}
-	}
+	setIfNotEmpty(result, "invocation_id", adkEvent.InvocationID)
+	setIfNotEmpty(result, "author", adkEvent.Author)
+	setIfNotEmpty(result, "branch", adkEvent.Branch)
 	if adkEvent.UsageMetadata != nil {
 		if um, err := toA2AMetadataMap(adkEvent.UsageMetadata); err == nil && um != nil {
 			result[adka2a.ToA2AMetaKey("usage_metadata")] = um
 		}
 	}
-	if adkEvent.ErrorCode != "" {
-		result[adka2a.ToA2AMetaKey("error_code")] = adkEvent.ErrorCode
+	setIfNotEmpty(result, "error_code", adkEvent.ErrorCode)
+	if p, ok := adkEvent.CustomMetadata[MetadataKeyPlan]; ok {
+		result[GetKAgentMetadataKey(MetadataKeyPlan)] = p
 	}
 	return result
 }
+
+// eventMetaKeys is the most keys buildEventMeta adds to the base metadata,
+// plus the partial flag the executor sets on streamed chunks.
+const eventMetaKeys = 7
+
+func setIfNotEmpty(meta map[string]any, key, value string) {
+	if value != "" {
+		meta[adka2a.ToA2AMetaKey(key)] = value
+	}
+}
+
+// usageTotals sums the token usage reported by the model calls of one run.
+type usageTotals struct {
+	usage genai.GenerateContentResponseUsageMetadata
+	seen  bool
+}
+
+// add accumulates the usage of a final (non-partial) event. Partial events
+// are skipped because streamed chunks repeat the usage of their response.
+func (u *usageTotals) add(adkEvent *adksession.Event) {
+	if adkEvent == nil || adkEvent.Partial || adkEvent.UsageMetadata == nil {
+		return
+	}
+	um := adkEvent.UsageMetadata
+	u.usage.PromptTokenCount += um.PromptTokenCount
+	u.usage.CandidatesTokenCount += um.CandidatesTokenCount
+	u.usage.CachedContentTokenCount += um.CachedContentTokenCount
+	u.usage.ThoughtsTokenCount += um.ThoughtsTokenCount
+	u.usage.ToolUsePromptTokenCount += um.ToolUsePromptTokenCount
+	u.usage.TotalTokenCount += um.TotalTokenCount
+	u.seen = true
+}
+
+// stamp adds the accumulated usage to meta under kagent_usage_metadata, the
+// key read by callers of the agent (remote agent tools, evals).
+func (u *usageTotals) stamp(meta map[string]any) {
+	if !u.seen {
+		return
+	}
+	if um, err := toA2AMetadataMap(&u.usage); err == nil && um != nil {
+		meta[GetKAgentMetadataKey(MetadataKeyUsage)] = um
+	}
+}
+
+// isIterationBoundary reports whether the event carries tool results, after
+// which the agent starts its next model call.
+func isIterationBoundary(adkEvent *adksession.Event) bool {
+	if adkEvent == nil || adkEvent.Partial || adkEvent.Content == nil {
+		return false
+	}
+	for _, part := range adkEvent.Content.Parts {
+		if part != nil && part.FunctionResponse != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// artifactEvents loads the artifacts saved by an event (its ArtifactDelta) and
+// converts each one to a TaskArtifactUpdateEvent named after the file.
+func artifactEvents(ctx context.Context, factory eventFactory, svc artifact.Service, reqCtx a2atype.TaskInfoProvider, appName, userID, sessionID string, adkEvent *adksession.Event, meta map[string]any) ([]*a2atype.TaskArtifactUpdateEvent, error) {
+	if svc == nil || adkEvent == nil || len(adkEvent.Actions.ArtifactDelta) == 0 {
+		return nil, nil
+	}
+	var events []*a2atype.TaskArtifactUpdateEvent
+	for _, fileName := range slices.Sorted(maps.Keys(adkEvent.Actions.ArtifactDelta)) {
+		version := adkEvent.Actions.ArtifactDelta[fileName]
+		resp, err := svc.Load(ctx, &artifact.LoadRequest{
+			AppName:   appName,
+			UserID:    userID,
+			SessionID: sessionID,
+			FileName:  fileName,
+			Version:   version,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to load artifact %s: %w", fileName, err)
+		}
+		if resp == nil || resp.Part == nil {
+			continue
+		}
+		part, err := adka2a.ToA2APart(resp.Part, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert artifact %s: %w", fileName, err)
+		}
+		ev := factory.artifactEvent(reqCtx, part)
+		ev.Artifact.Name = fileName
+		ev.Artifact.Metadata = maps.Clone(meta)
+		ev.Artifact.Metadata[GetKAgentMetadataKey(MetadataKeyArtifactVersion)] = version
+		ev.LastChunk = true
+		events = append(events, ev)
+	}
+	return events, nil
+}
@@ -5,7 +5,9 @@ import (
 	"testing"
 
 	a2atype "github.com/a2aproject/a2a-go/a2a"
+	"google.golang.org/adk/artifact"
 	"google.golang.org/adk/server/adka2a" //nolint:staticcheck // kagent still uses a2a-go v1; this ADK package is the compatibility adapter.
+	adksession "google.golang.org/adk/session"
 	"google.golang.org/genai"
 )
 
@@ -289,3 +291,175 @@ func TestToA2AMetadataMap_nil(t *testing.T) {
 		t.Fatalf("expected nil map, got %#v", m)
 	}
 }
+
+// ---------------------------------------------------------------------------
+// usage, iteration and artifact events
+// ---------------------------------------------------------------------------
+
+func TestUsageTotals(t *testing.T) {
+	var u usageTotals
+	meta := map[string]any{}
+	u.stamp(meta)
+	if len(meta) != 0 {
+		t.Fatalf("stamp() without usage added %v", meta)
+	}
+
+	usageEvent := func(prompt, total int32, partial bool) *adksession.Event {
+		ev := adksession.NewEvent("inv")
+		ev.Partial = partial
+		ev.UsageMetadata = &genai.GenerateContentResponseUsageMetadata{PromptTokenCount: prompt, TotalTokenCount: total}
+		return ev
+	}
+	u.add(usageEvent(10, 15, false))
+	u.add(usageEvent(99, 99, true))
+	u.add(usageEvent(20, 30, false))
+	u.stamp(meta)
+
+	got, ok := meta["kagent_usage_metadata"].(map[string]any)
+	if !ok {
+		t.Fatalf("kagent_usage_metadata = %v", meta["kagent_usage_metadata"])
+	}
+	if got["promptTokenCount"] != float64(30) || got["totalTokenCount"] != float64(45) {
+		t.Errorf("usage = %v, want promptTokenCount 30 and totalTokenCount 45", got)
+	}
+}
+
+func TestIsIterationBoundary(t *testing.T) {
+	toolResult := adksession.NewEvent("inv")
+	toolResult.Content = genai.NewContentFromFunctionResponse("get_weather", map[string]any{"temp": 20}, genai.RoleUser)
+	if !isIterationBoundary(toolResult) {
+		t.Error("isIterationBoundary() = false for a function response event")
+	}
+
+	text := adksession.NewEvent("inv")
+	text.Content = genai.NewContentFromText("hello", genai.RoleModel)
+	if isIterationBoundary(text) {
+		t.Error("isIterationBoundary() = true for a text event")
+	}
+	if isIterationBoundary(nil) {
+		t.Error("isIterationBoundary(nil) = true")
+	}
+}
+
+func TestBuildEventMeta_Plan(t *testing.T) {
+	plan := map[string]any{"steps": []any{map[string]any{"title": "check pods", "status": "done"}}}
+	ev := adksession.NewEvent("inv")
+	ev.CustomMetadata = map[string]any{MetadataKeyPlan: plan}
+
+	meta := buildEventMeta(map[string]any{}, ev)
+	if got, ok := meta["kagent_plan"].(map[string]any); !ok || got["steps"] == nil {
+		t.Errorf("kagent_plan = %v, want the event's plan", meta["kagent_plan"])
+	}
+	if _, ok := buildEventMeta(map[string]any{}, adksession.NewEvent("inv"))["kagent_plan"]; ok {
+		t.Error("kagent_plan set for an event without a plan")
+	}
+}
+
+func TestArtifactEvents(t *testing.T) {
+	ctx := context.Background()
+	svc := artifact.InMemoryService()
+	saved, err := svc.Save(ctx, &artifact.SaveRequest{
+		AppName: "app", UserID: "user", SessionID: "session", FileName: "report.txt",
+		Part: genai.NewPartFromText("quarterly report"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ev := adksession.NewEvent("inv")
+	ev.Actions.ArtifactDelta = map[string]int64{"report.txt": saved.Version}
+	reqCtx := a2atype.TaskInfo{TaskID: "task-1", ContextID: "ctx-1"}
+
+	events, err := artifactEvents(ctx, newEventFactory(nil, nil), svc, reqCtx, "app", "user", "session", ev, map[string]any{"adk_app_name": "app"})
+	if err != nil {
+		t.Fatalf("artifactEvents() error = %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("artifactEvents() returned %d events, want 1", len(events))
+	}
+	got := events[0]
+	if got.TaskID != "task-1" || got.Artifact.Name != "report.txt" || !got.LastChunk {
+		t.Errorf("artifact event = %+v", got)
+	}
+	if tp, ok := got.Artifact.Parts[0].(a2atype.TextPart); !ok || tp.Text != "quarterly report" {
+		t.Errorf("artifact part = %#v", got.Artifact.Parts[0])
+	}
+	if got.Artifact.Metadata["kagent_artifact_version"] != saved.Version {
+		t.Errorf("artifact metadata = %v", got.Artifact.Metadata)
+	}
+
+	if events, err := artifactEvents(ctx, newEventFactory(nil, nil), nil, reqCtx, "app", "user", "session", ev, nil); err != nil || events != nil {
+		t.Errorf("artifactEvents() without a service = %v, %v", events, err)
+	}
+}
+
+func TestConvertEventParts(t *testing.T) {
+	ev := adksession.NewEvent("inv")
+	ev.Content = &genai.Content{Role: string(genai.RoleModel), Parts: []*genai.Part{
+		genai.NewPartFromText("hello"),
+		nil,
+		genai.NewPartFromFunctionCall("lookup", map[string]any{"q": "x"}),
+	}}
+	parts := convertEventParts(ev, map[string]string{"lookup": "sub-session"})
+	if len(parts) != 2 {
+		t.Fatalf("convertEventParts() = %d parts, want 2", len(parts))
+	}
+	if tp, ok := parts[0].(a2atype.TextPart); !ok || tp.Text != "hello" {
+		t.Errorf("parts[0] = %#v, want the text part", parts[0])
+	}
+	dp, ok := parts[1].(a2atype.DataPart)
+	if !ok || dp.Metadata[GetKAgentMetadataKey("subagent_session_id")] != "sub-session" {
+		t.Errorf("parts[1] = %#v, want a function call stamped with the subagent session", parts[1])
+	}
+	if convertEventParts(adksession.NewEvent("inv"), nil) != nil {
+		t.Error("convertEventParts() of an event without content should be nil")
+	}
+}
+
+func TestFilterTextParts_AllTextDoesNotCopy(t *testing.T) {
+	parts := a2atype.ContentParts{a2atype.TextPart{Text: "a"}, a2atype.TextPart{Text: "b"}}
+	if allocs := testing.AllocsPerRun(100, func() { filterTextParts(parts) }); allocs != 0 {
+		t.Errorf("filterTextParts() of text parts made %v allocations, want 0", allocs)
+	}
+	mixed := append(parts, a2atype.DataPart{Data: map[string]any{"k": "v"}})
+	if got := filterTextParts(mixed); len(got) != 2 {
+		t.Errorf("filterTextParts() = %v, want the two text parts", got)
+	}
+}
+
+func benchmarkEvent(parts ...*genai.Part) *adksession.Event {
+	ev := adksession.NewEvent("inv")
+	ev.Author = "agent"
+	ev.Content = &genai.Content{Role: string(genai.RoleModel), Parts: parts}
+	return ev
+}
+
+func BenchmarkBuildEventMeta(b *testing.B) {
+	base := map[string]any{
+		adka2a.ToA2AMetaKey("app_name"):   "app",
+		adka2a.ToA2AMetaKey("user_id"):    "user",
+		adka2a.ToA2AMetaKey("session_id"): "session",
+	}
+	ev := benchmarkEvent(genai.NewPartFromText("hello"))
+	b.ReportAllocs()
+	for b.Loop() {
+		buildEventMeta(base, ev)
+	}
+}
+
+func BenchmarkConvertEventParts_Text(b *testing.B) {
+	ev := benchmarkEvent(genai.NewPartFromText("hello"), genai.NewPartFromText("world"))
+	b.ReportAllocs()
+	for b.Loop() {
+		filterTextParts(convertEventParts(ev, nil))
+	}
+}
+
+func BenchmarkConvertEventParts_FunctionCall(b *testing.B) {
+	ev := benchmarkEvent(genai.NewPartFromFunctionCall("lookup", map[string]any{"q": "x"}))
+	sessions := map[string]string{"lookup": "sub-session"}
+	b.ReportAllocs()
+	for b.Loop() {
+		convertEventParts(ev, sessions)
+	}
+}
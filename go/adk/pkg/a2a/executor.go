@@ -1,7 +1,9 @@
 package a2a
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"maps"
 	"os"
@@ -13,9 +15,12 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/kagent-dev/kagent/go/adk/pkg/auth"
 	"github.com/kagent-dev/kagent/go/adk/pkg/models"
+	"github.com/kagent-dev/kagent/go/adk/pkg/prompts"
 	"github.com/kagent-dev/kagent/go/adk/pkg/session"
 	"github.com/kagent-dev/kagent/go/adk/pkg/skills"
 	"github.com/kagent-dev/kagent/go/adk/pkg/telemetry"
+	"github.com/kagent-dev/kagent/go/api/adk"
+	"github.com/kagent-dev/kagent/go/api/requestid"
 	"go.opentelemetry.io/otel/attribute"
 	adkagent "google.golang.org/adk/agent"
 	"google.golang.org/adk/runner"
@@ -34,9 +39,29 @@ type KAgentExecutorConfig struct {
 	SubagentSessionIDs map[string]string
 	SessionService     *session.KAgentSessionService
 	Stream             bool
-	AppName            string
-	SkillsDirectory    string
-	Logger             logr.Logger
+	// AppName scopes the sessions of the agent. It defaults to the
+	// runner's app name.
+	AppName         string
+	SkillsDirectory string
+	// AllowAnonymous lets requests without an x-user-id header run as the
+	// user A2A_USER_<contextId>. Otherwise they fail with
+	// a2a.ErrUnauthenticated. See AllowAnonymousFromEnv.
+	AllowAnonymous bool
+	// Workspace manages the session workspaces. It defaults to local
+	// directories under skills.WorkspaceBaseDir().
+	Workspace skills.Workspace
+	// TaskStateRules pick the task state reported when a run stops on a
+	// long-running tool call.
+	TaskStateRules []adk.TaskStateRule
+	// Prompts assigns the prompt version of each run. When nil, the agent's
+	// own instruction is used.
+	Prompts *prompts.Router
+	// Clock and IDGenerator supply event timestamps and message/artifact
+	// IDs. They default to the wall clock and random UUIDs; tests inject
+	// fixed ones for deterministic event streams.
+	Clock       Clock
+	IDGenerator IDGenerator
+	Logger      logr.Logger
 }
 
 // KAgentExecutor implements a2asrv.AgentExecutor
@@ -47,6 +72,11 @@ type KAgentExecutor struct {
 	stream             bool
 	appName            string
 	skillsDirectory    string
+	allowAnonymous     bool
+	workspace          skills.Workspace
+	taskStateRules     taskStateRules
+	prompts            *prompts.Router
+	events             eventFactory
 	logger             logr.Logger
 }
 
@@ -54,24 +84,43 @@ var _ a2asrv.AgentExecutor = (*KAgentExecutor)(nil)
 
 // NewKAgentExecutor creates a KAgentExecutor from config
 func NewKAgentExecutor(cfg KAgentExecutorConfig) *KAgentExecutor {
-	skillsDir := cfg.SkillsDirectory
-	if skillsDir == "" {
-		skillsDir = os.Getenv(envSkillsFolder)
+	skillsDir := SkillsDirectory(cfg.SkillsDirectory)
+	workspace := cfg.Workspace
+	if workspace == nil {
+		workspace = skills.NewDirWorkspace(skills.WorkspaceBaseDir(), skillsDir, 0)
 	}
-	if skillsDir == "" {
-		skillsDir = defaultSkillsDirectory
+	appName := cfg.AppName
+	if appName == "" {
+		appName = cfg.RunnerConfig.AppName
 	}
 	return &KAgentExecutor{
 		runnerConfig:       cfg.RunnerConfig,
 		subagentSessionIDs: cfg.SubagentSessionIDs,
 		sessionService:     cfg.SessionService,
 		stream:             cfg.Stream,
-		appName:            cfg.AppName,
+		appName:            appName,
 		skillsDirectory:    skillsDir,
+		allowAnonymous:     cfg.AllowAnonymous,
+		workspace:          workspace,
+		taskStateRules:     newTaskStateRules(cfg.TaskStateRules),
+		prompts:            cfg.Prompts,
+		events:             newEventFactory(cfg.Clock, cfg.IDGenerator),
 		logger:             cfg.Logger.WithName("kagent-executor"),
 	}
 }
 
+// SkillsDirectory returns dir, or $KAGENT_SKILLS_FOLDER or the default
+// skills directory when dir is empty, as NewKAgentExecutor resolves it.
+func SkillsDirectory(dir string) string {
+	if dir == "" {
+		dir = os.Getenv(envSkillsFolder)
+	}
+	if dir == "" {
+		dir = defaultSkillsDirectory
+	}
+	return dir
+}
+
 // UserIDCallInterceptor returns an a2asrv.CallInterceptor that extracts the
 // x-user-id HTTP header from the incoming request metadata and sets it as the
 // authenticated user on the CallContext.
@@ -109,50 +158,125 @@ func (e *KAgentExecutor) Execute(ctx context.Context, reqCtx *a2asrv.RequestCont
 	}
 
 	// 1. Derive userID / sessionID.
-	userID := "A2A_USER_" + reqCtx.ContextID
-	if callCtx, ok := a2asrv.CallContextFrom(ctx); ok {
-		if callCtx.User != nil && callCtx.User.Name() != "" {
-			userID = callCtx.User.Name()
-		}
+	userID, err := e.requestUserID(ctx, reqCtx.ContextID)
+	if err != nil {
+		return err
 	}
 	sessionID := reqCtx.ContextID
 
+	// A message continuing earlier tasks must be sent in their session,
+	// otherwise the agent would silently start over without their history
+	// and workspace.
+	for _, related := range reqCtx.RelatedTasks {
+		if related.ContextID != sessionID {
+			return fmt.Errorf("referenced task %s belongs to context %s: send the message with that contextId to continue it", related.ID, related.ContextID)
+		}
+	}
+
+	requestID := extractRequestID(ctx)
 	ctx = withBearerToken(ctx)
 	ctx = auth.WithUserID(ctx, userID)
+	ctx = requestid.NewContext(ctx, requestID)
+	ctx = models.WithWireLogScope(ctx, sessionID, string(reqCtx.TaskID))
+	generation, err := generationOverrides(reqCtx.Message.Metadata)
+	if err != nil {
+		return err
+	}
+	pinnedVersion, _ := ReadMetadataValue(reqCtx.Message.Metadata, MetadataKeyPromptVersion)
+	pinned, _ := pinnedVersion.(string)
+	prompt, err := e.prompts.Assign(sessionID, pinned)
+	if err != nil {
+		return err
+	}
+	if prompt.Version != nil {
+		// The request's overrides take precedence over the version's.
+		generation = prompt.Version.Generation.Merge(generation)
+	}
+	ctx = models.WithGenerationOverrides(ctx, generation)
+	ctx = prompts.WithAssignment(ctx, prompt)
 
 	e.logger.Info("Execute",
 		"taskID", reqCtx.TaskID,
 		"contextID", reqCtx.ContextID,
 		"appName", e.appName,
 		"userID", userID,
+		"requestID", requestID,
 	)
 
 	// 2. Set up telemetry span attributes.
 	spanAttributes := map[string]string{
 		"kagent.user_id":         userID,
+		"kagent.request_id":      requestID,
 		"gen_ai.task.id":         string(reqCtx.TaskID),
 		"gen_ai.conversation.id": sessionID,
 	}
 	if e.appName != "" {
 		spanAttributes["kagent.app_name"] = e.appName
 	}
+	if prompt.Version != nil {
+		spanAttributes["kagent.prompt_version"] = prompt.Version.Version
+	}
 	ctx = telemetry.SetKAgentSpanAttributes(ctx, spanAttributes)
 	ctx, invocationSpan := telemetry.StartInvocationSpan(ctx)
 	defer invocationSpan.End()
 
 	telemetry.SetMessageMetadataAttributes(ctx, reqCtx.Message.Metadata)
 
-	// 3. Initialize skills session path.
+	// 3. Prepare the session workspace. The skills tools cannot work
+	// without it, so an unavailable or over-quota workspace fails the task.
 	if e.skillsDirectory != "" && sessionID != "" {
-		if _, err := skills.InitializeSessionPath(sessionID, e.skillsDirectory); err != nil {
-			e.logger.V(1).Info("Skills session path init failed (continuing)",
+		dir, err := e.workspace.Prepare(ctx, sessionID)
+		if err != nil {
+			e.logger.Info("Session workspace unavailable, failing task",
+				"error", err, "sessionID", sessionID, "taskID", reqCtx.TaskID)
+			return e.failTask(ctx, reqCtx, queue, fmt.Sprintf("Failed to prepare the session workspace: %v", err))
+		}
+		if err := skills.RecordSessionUser(dir, userID); err != nil {
+			e.logger.V(1).Info("Failed to record session workspace owner",
 				"error", err, "sessionID", sessionID)
 		}
+		defer func() {
+			if err := e.workspace.Finalize(ctx, sessionID); err != nil {
+				e.logger.Info("Session workspace finalize failed", "error", err, "sessionID", sessionID)
+			}
+		}()
 	}
 
-	// 4. Create / lookup session via sessionService.
+	// 4. Create / lookup session via sessionService, superseding the edited
+	// part of the history first when the message edits an earlier one, and
+	// importing the history the message brings, if any.
+	var supersededEventIDs []string
+	if v, ok := ReadMetadataValue(reqCtx.Message.Metadata, MetadataKeyEditEventID); ok {
+		editEventID, _ := v.(string)
+		if editEventID == "" {
+			return fmt.Errorf("invalid %s%s metadata", KAgentMetadataKeyPrefix, MetadataKeyEditEventID)
+		}
+		if e.sessionService == nil {
+			return fmt.Errorf("editing a message requires kagent session persistence")
+		}
+		ids, err := e.sessionService.SupersedeEvents(ctx, userID, sessionID, editEventID)
+		if err != nil {
+			return fmt.Errorf("failed to supersede events after edited message: %w", err)
+		}
+		supersededEventIDs = ids
+	}
+	history, err := parseHistory(reqCtx.Message.Metadata)
+	if err != nil {
+		return err
+	}
+	if len(history) > 0 && e.sessionService == nil {
+		return fmt.Errorf("importing message history requires kagent session persistence")
+	}
 	if e.sessionService != nil {
+		// A session of another agent is never taken over. The backend
+		// looks sessions up by user, so another user's session is not
+		// found and this user gets a session of their own.
 		sess, err := e.sessionService.GetSession(ctx, e.appName, userID, sessionID)
+		if accessErr := sessionAccessError(err); accessErr != nil {
+			e.logger.Info("Rejected request for a session of another agent",
+				"error", err, "sessionID", sessionID, "userID", userID)
+			return accessErr
+		}
 		if err != nil {
 			e.logger.V(1).Info("Session lookup failed, will create", "error", err, "sessionID", sessionID)
 			sess = nil
@@ -175,6 +299,13 @@ func (e *KAgentExecutor) Execute(ctx context.Context, reqCtx *a2asrv.RequestCont
 				return fmt.Errorf("failed to create session: %w", err)
 			}
 		}
+		if len(history) > 0 {
+			n, err := e.ingestHistory(ctx, userID, sessionID, reqCtx.Message, history)
+			if err != nil {
+				return fmt.Errorf("failed to import message history: %w", err)
+			}
+			e.logger.V(1).Info("Imported message history", "sessionID", sessionID, "messages", len(history), "appended", n)
+		}
 	}
 
 	// 5. Detect HITL decision and build the resume message if needed.
@@ -201,7 +332,7 @@ func (e *KAgentExecutor) Execute(ctx context.Context, reqCtx *a2asrv.RequestCont
 	// 9. Emit initial events.
 	if reqCtx.StoredTask == nil {
 		// New task — emit submitted with the user's message
-		submitted := a2atype.NewStatusUpdateEvent(reqCtx, a2atype.TaskStateSubmitted, reqCtx.Message)
+		submitted := e.events.statusUpdate(reqCtx, a2atype.TaskStateSubmitted, reqCtx.Message)
 		if err := queue.Write(ctx, submitted); err != nil {
 			return fmt.Errorf("failed to write submitted event: %w", err)
 		}
@@ -210,21 +341,33 @@ func (e *KAgentExecutor) Execute(ctx context.Context, reqCtx *a2asrv.RequestCont
 		// See https://github.com/a2aproject/a2a-go/blob/v0.3.13/a2asrv/agentexec.go#L188
 		// Remove the pre-appended copy and emit one decision status event.
 		dropPreAppendedDecisionFromHistory(reqCtx.StoredTask, reqCtx.Message)
-		decision := a2atype.NewStatusUpdateEvent(reqCtx, a2atype.TaskStateWorking, reqCtx.Message)
+		decision := e.events.statusUpdate(reqCtx, a2atype.TaskStateWorking, reqCtx.Message)
 		if err := queue.Write(ctx, decision); err != nil {
 			return fmt.Errorf("failed to write HITL decision status event: %w", err)
 		}
 	}
 
-	// Base metadata carried on every event (app_name, user_id, session_id).
+	// Base metadata carried on every event (app_name, user_id, session_id,
+	// request_id).
 	baseMeta := map[string]any{
-		adka2a.ToA2AMetaKey("app_name"):   e.appName,
-		adka2a.ToA2AMetaKey("user_id"):    userID,
-		adka2a.ToA2AMetaKey("session_id"): sessionID,
+		adka2a.ToA2AMetaKey("app_name"):            e.appName,
+		adka2a.ToA2AMetaKey("user_id"):             userID,
+		adka2a.ToA2AMetaKey("session_id"):          sessionID,
+		GetKAgentMetadataKey(MetadataKeyRequestID): requestID,
+	}
+	if prompt.Version != nil {
+		baseMeta[GetKAgentMetadataKey(MetadataKeyPromptVersion)] = prompt.Version.Version
+	}
+	if prompt.Experiment != "" {
+		baseMeta[GetKAgentMetadataKey(MetadataKeyPromptExperiment)] = prompt.Experiment
+		baseMeta[GetKAgentMetadataKey(MetadataKeyPromptArm)] = prompt.Arm
 	}
 
-	working := a2atype.NewStatusUpdateEvent(reqCtx, a2atype.TaskStateWorking, nil)
+	working := e.events.statusUpdate(reqCtx, a2atype.TaskStateWorking, nil)
 	working.Metadata = maps.Clone(baseMeta)
+	if len(supersededEventIDs) > 0 {
+		working.Metadata[KAgentMetadataKeyPrefix+MetadataKeySupersededEventIDs] = supersededEventIDs
+	}
 	if err := queue.Write(ctx, working); err != nil {
 		return fmt.Errorf("failed to write working event: %w", err)
 	}
@@ -241,9 +384,29 @@ func (e *KAgentExecutor) Execute(ctx context.Context, reqCtx *a2asrv.RequestCont
 		lastNonPartialParts a2atype.ContentParts
 		hitlParts           a2atype.ContentParts
 		runErr              error
+		usage               usageTotals
+		citations           citationTracker
+		iteration           = 1
 	)
 
-	for adkEvent, adkErr := range r.Run(ctx, userID, sessionID, content, runConfig) {
+	// Output written by tools while they run is forwarded as working status
+	// updates carrying a tool_output_delta DataPart.
+	runCtx := WithToolOutputSink(ctx, func(callID, toolName, chunk string) {
+		msg := e.events.agentMessage(a2atype.DataPart{
+			Data: map[string]any{PartKeyID: callID, PartKeyName: toolName, PartKeyDelta: chunk},
+			Metadata: map[string]any{
+				GetKAgentMetadataKey(A2ADataPartMetadataTypeKey): A2ADataPartMetadataTypeToolOutputDelta,
+			},
+		})
+		msg.Metadata = maps.Clone(baseMeta)
+		statusEv := e.events.statusUpdate(reqCtx, a2atype.TaskStateWorking, msg)
+		statusEv.Metadata = maps.Clone(baseMeta)
+		if err := queue.Write(ctx, statusEv); err != nil {
+			e.logger.Error(err, "Failed to write tool output delta", "tool", toolName, "functionCallID", callID)
+		}
+	})
+
+	for adkEvent, adkErr := range r.Run(runCtx, userID, sessionID, content, runConfig) {
 		if adkErr != nil {
 			runErr = adkErr
 			break
@@ -260,15 +423,27 @@ func (e *KAgentExecutor) Execute(ctx context.Context, reqCtx *a2asrv.RequestCont
 
 		// Build per-event metadata (inherits baseMeta + adds invocation_id, usage etc.).
 		eventMeta := buildEventMeta(baseMeta, adkEvent)
+		usage.add(adkEvent)
+		citations.add(adkEvent)
+
+		// Emit artifacts saved by tools during this event.
+		artifacts, err := artifactEvents(ctx, e.events, e.runnerConfig.ArtifactService, reqCtx, e.appName, userID, sessionID, adkEvent, baseMeta)
+		if err != nil {
+			e.logger.Error(err, "Failed to convert saved artifacts", "sessionID", sessionID)
+		}
+		for _, artifactEv := range artifacts {
+			if err := queue.Write(ctx, artifactEv); err != nil {
+				return fmt.Errorf("failed to write artifact event: %w", err)
+			}
+		}
 
 		// Convert GenAI parts → A2A parts (with kagent stamping).
 		if adkEvent.Content == nil || len(adkEvent.Content.Parts) == 0 {
 			// Events with no content carry metadata only; still track invocationID/usage.
 			// Check for LLM error.
 			if adkEvent.ErrorCode != "" {
-				errMsg := a2atype.NewMessage(a2atype.MessageRoleAgent,
-					a2atype.TextPart{Text: fmt.Sprintf("LLM error: %s %s", adkEvent.ErrorCode, adkEvent.ErrorMessage)})
-				failed := a2atype.NewStatusUpdateEvent(reqCtx, a2atype.TaskStateFailed, errMsg)
+				errMsg := e.events.agentMessage(a2atype.TextPart{Text: fmt.Sprintf("LLM error: %s %s", adkEvent.ErrorCode, adkEvent.ErrorMessage)})
+				failed := e.events.statusUpdate(reqCtx, a2atype.TaskStateFailed, errMsg)
 				failed.Final = true
 				failed.Metadata = eventMeta
 				return queue.Write(ctx, failed)
@@ -278,33 +453,15 @@ func (e *KAgentExecutor) Execute(ctx context.Context, reqCtx *a2asrv.RequestCont
 
 		// Check for LLM error (even with content present).
 		if adkEvent.ErrorCode != "" {
-			errMsg := a2atype.NewMessage(a2atype.MessageRoleAgent,
-				a2atype.TextPart{Text: fmt.Sprintf("LLM error: %s %s", adkEvent.ErrorCode, adkEvent.ErrorMessage)})
-			failed := a2atype.NewStatusUpdateEvent(reqCtx, a2atype.TaskStateFailed, errMsg)
+			errMsg := e.events.agentMessage(a2atype.TextPart{Text: fmt.Sprintf("LLM error: %s %s", adkEvent.ErrorCode, adkEvent.ErrorMessage)})
+			failed := e.events.statusUpdate(reqCtx, a2atype.TaskStateFailed, errMsg)
 			failed.Final = true
 			failed.Metadata = eventMeta
 			return queue.Write(ctx, failed)
 		}
 
 		// Convert parts.
-		var a2aParts a2atype.ContentParts
-		for _, genaiPart := range adkEvent.Content.Parts {
-			if genaiPart == nil {
-				continue
-			}
-			a2aPart, err := adka2a.ToA2APart(genaiPart, adkEvent.LongRunningToolIDs)
-			if err != nil {
-				continue
-			}
-			if isEmptyDataPart(a2aPart) {
-				continue
-			}
-			// Stamp kagent_subagent_session_id onto function_call DataParts.
-			if len(subagentSessionIDs) > 0 {
-				a2aPart = stampSubagentSessionID(a2aPart, subagentSessionIDs)
-			}
-			a2aParts = append(a2aParts, a2aPart)
-		}
+		a2aParts := convertEventParts(adkEvent, subagentSessionIDs)
 
 		// Collect HITL (input_required) parts from LongRunningToolIDs.
 		isHITLEvent := len(adkEvent.LongRunningToolIDs) > 0
@@ -324,12 +481,12 @@ func (e *KAgentExecutor) Execute(ctx context.Context, reqCtx *a2asrv.RequestCont
 			// Go ADK executor also uses different A2A response formats than Python ADK.
 			textOnly := filterTextParts(a2aParts)
 			if len(textOnly) > 0 {
-				mirrorMeta := maps.Clone(eventMeta)
-				mirrorMeta[adka2a.ToA2AMetaKey("partial")] = true
-				msg := a2atype.NewMessage(a2atype.MessageRoleAgent, textOnly...)
-				msg.Metadata = mirrorMeta
-				statusEv := a2atype.NewStatusUpdateEvent(reqCtx, a2atype.TaskStateWorking, msg)
-				statusEv.Metadata = mirrorMeta
+				// eventMeta is built per event, so it is used without a copy.
+				eventMeta[adka2a.ToA2AMetaKey("partial")] = true
+				msg := e.events.agentMessage(textOnly...)
+				msg.Metadata = eventMeta
+				statusEv := e.events.statusUpdate(reqCtx, a2atype.TaskStateWorking, msg)
+				statusEv.Metadata = eventMeta
 				if err := queue.Write(ctx, statusEv); err != nil {
 					return fmt.Errorf("failed to write partial status event: %w", err)
 				}
@@ -338,9 +495,9 @@ func (e *KAgentExecutor) Execute(ctx context.Context, reqCtx *a2asrv.RequestCont
 			mirrorParts := a2aParts
 			if len(hitlParts) == 0 {
 				// Only mirror when not accumulating HITL parts (those go into input_required).
-				msg := a2atype.NewMessage(a2atype.MessageRoleAgent, mirrorParts...)
-				msg.Metadata = maps.Clone(eventMeta)
-				statusEv := a2atype.NewStatusUpdateEvent(reqCtx, a2atype.TaskStateWorking, msg)
+				msg := e.events.agentMessage(mirrorParts...)
+				msg.Metadata = eventMeta
+				statusEv := e.events.statusUpdate(reqCtx, a2atype.TaskStateWorking, msg)
 				statusEv.Metadata = maps.Clone(eventMeta)
 				if err := queue.Write(ctx, statusEv); err != nil {
 					return fmt.Errorf("failed to write mirror status event: %w", err)
@@ -349,6 +506,17 @@ func (e *KAgentExecutor) Execute(ctx context.Context, reqCtx *a2asrv.RequestCont
 			}
 		}
 
+		// Tool results end an iteration; mark the start of the next model call.
+		if isIterationBoundary(adkEvent) && len(hitlParts) == 0 {
+			iteration++
+			boundary := e.events.statusUpdate(reqCtx, a2atype.TaskStateWorking, nil)
+			boundary.Metadata = maps.Clone(baseMeta)
+			boundary.Metadata[GetKAgentMetadataKey(MetadataKeyIteration)] = iteration
+			if err := queue.Write(ctx, boundary); err != nil {
+				return fmt.Errorf("failed to write iteration boundary event: %w", err)
+			}
+		}
+
 		// Break on confirmation events that have long-running tool IDs.
 		if isHITLEvent {
 			break
@@ -360,19 +528,23 @@ func (e *KAgentExecutor) Execute(ctx context.Context, reqCtx *a2asrv.RequestCont
 	if invocationID != "" {
 		finalMeta[adka2a.ToA2AMetaKey("invocation_id")] = invocationID
 	}
+	usage.stamp(finalMeta)
+	citations.stamp(finalMeta)
 
 	if runErr != nil {
-		errMsg := a2atype.NewMessage(a2atype.MessageRoleAgent, a2atype.TextPart{Text: runErr.Error()})
-		failed := a2atype.NewStatusUpdateEvent(reqCtx, a2atype.TaskStateFailed, errMsg)
+		errMsg := e.events.agentMessage(a2atype.TextPart{Text: runErr.Error()})
+		failed := e.events.statusUpdate(reqCtx, a2atype.TaskStateFailed, errMsg)
 		failed.Final = true
 		failed.Metadata = finalMeta
 		return queue.Write(ctx, failed)
 	}
 
 	if len(hitlParts) > 0 {
-		// input_required: the agent is waiting for HITL decisions.
-		hitlMsg := a2atype.NewMessage(a2atype.MessageRoleAgent, hitlParts...)
-		inputRequired := a2atype.NewStatusUpdateEvent(reqCtx, a2atype.TaskStateInputRequired, hitlMsg)
+		// input_required (or the state chosen by a configured task state
+		// rule, e.g. auth_required for credential requests): the agent is
+		// waiting for HITL decisions.
+		hitlMsg := e.events.agentMessage(hitlParts...)
+		inputRequired := e.events.statusUpdate(reqCtx, e.taskStateRules.stateFor(hitlParts), hitlMsg)
 		inputRequired.Final = true
 		inputRequired.Metadata = finalMeta
 		return queue.Write(ctx, inputRequired)
@@ -380,26 +552,80 @@ func (e *KAgentExecutor) Execute(ctx context.Context, reqCtx *a2asrv.RequestCont
 
 	// Final artifact update with lastChunk=true (if we have parts) and final completed status update (no message payload).
 	if len(lastNonPartialParts) > 0 {
-		finalArtifact := a2atype.NewArtifactEvent(reqCtx, lastNonPartialParts...)
+		finalArtifact := e.events.artifactEvent(reqCtx, lastNonPartialParts...)
 		finalArtifact.LastChunk = true
+		if len(citations.citations) > 0 {
+			finalArtifact.Artifact.Metadata = map[string]any{}
+			citations.stamp(finalArtifact.Artifact.Metadata)
+		}
 		if err := queue.Write(ctx, finalArtifact); err != nil {
 			return fmt.Errorf("failed to write final artifact event: %w", err)
 		}
 	}
 
-	completed := a2atype.NewStatusUpdateEvent(reqCtx, a2atype.TaskStateCompleted, nil)
+	completed := e.events.statusUpdate(reqCtx, a2atype.TaskStateCompleted, nil)
 	completed.Final = true
 	completed.Metadata = finalMeta
 	return queue.Write(ctx, completed)
 }
 
+// failTask ends a task that cannot run with a final failed status update
+// explaining why, emitting the submitted event first for a new task.
+func (e *KAgentExecutor) failTask(ctx context.Context, reqCtx *a2asrv.RequestContext, queue eventqueue.Queue, text string) error {
+	if reqCtx.StoredTask == nil {
+		submitted := e.events.statusUpdate(reqCtx, a2atype.TaskStateSubmitted, reqCtx.Message)
+		if err := queue.Write(ctx, submitted); err != nil {
+			return fmt.Errorf("failed to write submitted event: %w", err)
+		}
+	}
+	failed := e.events.statusUpdate(reqCtx, a2atype.TaskStateFailed, e.events.agentMessage(a2atype.TextPart{Text: text}))
+	failed.Final = true
+	return queue.Write(ctx, failed)
+}
+
 // Cancel implements a2asrv.AgentExecutor.
 func (e *KAgentExecutor) Cancel(ctx context.Context, reqCtx *a2asrv.RequestContext, queue eventqueue.Queue) error {
-	event := a2atype.NewStatusUpdateEvent(reqCtx, a2atype.TaskStateCanceled, nil)
+	event := e.events.statusUpdate(reqCtx, a2atype.TaskStateCanceled, nil)
 	event.Final = true
 	return queue.Write(ctx, event)
 }
 
+// extractRequestID returns the X-Request-ID the caller sent, or a new ID
+// when the agent is called directly without one.
+func extractRequestID(ctx context.Context) string {
+	if callCtx, ok := a2asrv.CallContextFrom(ctx); ok {
+		if meta := callCtx.RequestMeta(); meta != nil {
+			if vals, ok := meta.Get(requestid.Header); ok && len(vals) > 0 && requestid.Valid(vals[0]) {
+				return vals[0]
+			}
+		}
+	}
+	return requestid.New()
+}
+
+// generationOverrides parses and validates the generation settings a
+// message carries under kagent_generation, if any.
+func generationOverrides(metadata map[string]any) (*adk.GenerationConfig, error) {
+	v, ok := ReadMetadataValue(metadata, MetadataKeyGeneration)
+	if !ok || v == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s%s metadata: %w", KAgentMetadataKeyPrefix, MetadataKeyGeneration, err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	var g adk.GenerationConfig
+	if err := dec.Decode(&g); err != nil {
+		return nil, fmt.Errorf("invalid %s%s metadata: %w", KAgentMetadataKeyPrefix, MetadataKeyGeneration, err)
+	}
+	if err := g.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s%s metadata: %w", KAgentMetadataKeyPrefix, MetadataKeyGeneration, err)
+	}
+	return &g, nil
+}
+
 // extractSessionName extracts session name from the first text part of a message.
 func extractSessionName(message *a2atype.Message) string {
 	if message == nil {
@@ -0,0 +1,150 @@
+package a2a
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	a2atype "github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+	"github.com/go-logr/logr"
+	"github.com/kagent-dev/kagent/go/adk/pkg/session"
+	"github.com/kagent-dev/kagent/go/adk/pkg/skills"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/runner"
+	adksession "google.golang.org/adk/session"
+)
+
+// overQuotaWorkspace fails every Prepare with ErrWorkspaceQuotaExceeded.
+type overQuotaWorkspace struct{ finalized int }
+
+func (w *overQuotaWorkspace) Prepare(context.Context, string) (string, error) {
+	return "", fmt.Errorf("%w: 2048 bytes used, quota is 1024 bytes", skills.ErrWorkspaceQuotaExceeded)
+}
+
+func (w *overQuotaWorkspace) Finalize(context.Context, string) error {
+	w.finalized++
+	return nil
+}
+
+func (w *overQuotaWorkspace) Delete(context.Context, string) error { return nil }
+
+func TestExecutor_FailsTaskWhenWorkspaceUnavailable(t *testing.T) {
+	agent, err := llmagent.New(llmagent.Config{Name: "echo", Model: &textLLM{text: "hello"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	workspace := &overQuotaWorkspace{}
+	executor := NewKAgentExecutor(KAgentExecutorConfig{
+		RunnerConfig:    runner.Config{AppName: "app", Agent: agent, SessionService: adksession.InMemoryService()},
+		AppName:         "app",
+		AllowAnonymous:  true,
+		SkillsDirectory: t.TempDir(),
+		Workspace:       workspace,
+		Logger:          logr.Discard(),
+	})
+
+	queue := &recordingQueue{}
+	reqCtx := &a2asrv.RequestContext{
+		Message:   &a2atype.Message{ID: "msg-1", Role: a2atype.MessageRoleUser, Parts: a2atype.ContentParts{a2atype.TextPart{Text: "hi"}}},
+		TaskID:    "task-1",
+		ContextID: "ctx-1",
+	}
+	if err := executor.Execute(context.Background(), reqCtx, queue); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if len(queue.events) != 2 {
+		t.Fatalf("got %d events, want submitted and failed: %+v", len(queue.events), queue.events)
+	}
+	failed, ok := queue.events[1].(*a2atype.TaskStatusUpdateEvent)
+	if !ok || failed.Status.State != a2atype.TaskStateFailed || !failed.Final {
+		t.Fatalf("last event = %+v, want a final failed status update", queue.events[1])
+	}
+	text := failed.Status.Message.Parts[0].(a2atype.TextPart).Text
+	if !strings.Contains(text, "quota exceeded") {
+		t.Errorf("failure message = %q, want it to mention the exceeded quota", text)
+	}
+	if workspace.finalized != 0 {
+		t.Errorf("Finalize called %d times for a workspace that was never prepared", workspace.finalized)
+	}
+}
+
+func TestExtractRequestID(t *testing.T) {
+	ctx, _ := a2asrv.WithCallContext(context.Background(), a2asrv.NewRequestMeta(map[string][]string{"X-Request-ID": {"req-123"}}))
+	if got := extractRequestID(ctx); got != "req-123" {
+		t.Errorf("extractRequestID() = %q, want the caller's req-123", got)
+	}
+	ctx, _ = a2asrv.WithCallContext(context.Background(), a2asrv.NewRequestMeta(map[string][]string{"X-Request-ID": {"not valid"}}))
+	if got := extractRequestID(ctx); got == "" || got == "not valid" {
+		t.Errorf("extractRequestID() = %q, want a generated ID", got)
+	}
+}
+
+func TestGenerationOverrides(t *testing.T) {
+	g, err := generationOverrides(map[string]any{
+		"kagent_generation": map[string]any{"temperature": 0.3, "stop_sequences": []any{"END"}},
+	})
+	if err != nil {
+		t.Fatalf("generationOverrides() error = %v", err)
+	}
+	if g == nil || g.Temperature == nil || *g.Temperature != 0.3 || len(g.StopSequences) != 1 {
+		t.Errorf("generationOverrides() = %+v, want temperature 0.3 and one stop sequence", g)
+	}
+
+	if g, err := generationOverrides(map[string]any{"other": 1}); g != nil || err != nil {
+		t.Errorf("generationOverrides() without the key = %+v, %v, want nil, nil", g, err)
+	}
+
+	for _, bad := range []any{
+		map[string]any{"temperature": 3.0},
+		map[string]any{"temprature": 0.3},
+		"0.3",
+	} {
+		if _, err := generationOverrides(map[string]any{"kagent_generation": bad}); err == nil {
+			t.Errorf("generationOverrides(%v) error = nil, want an error", bad)
+		}
+	}
+}
+
+func TestRequestUserID(t *testing.T) {
+	ctx, callCtx := a2asrv.WithCallContext(context.Background(), a2asrv.NewRequestMeta(nil))
+	strict := NewKAgentExecutor(KAgentExecutorConfig{})
+	if _, err := strict.requestUserID(ctx, "ctx-1"); !errors.Is(err, a2atype.ErrUnauthenticated) {
+		t.Errorf("anonymous requestUserID() error = %v, want ErrUnauthenticated", err)
+	}
+	local := NewKAgentExecutor(KAgentExecutorConfig{AllowAnonymous: true})
+	if userID, err := local.requestUserID(ctx, "ctx-1"); err != nil || userID != "A2A_USER_ctx-1" {
+		t.Errorf("anonymous requestUserID() = %q, %v, want the context's anonymous user", userID, err)
+	}
+
+	callCtx.User = &a2asrv.AuthenticatedUser{UserName: "alice"}
+	if userID, err := strict.requestUserID(ctx, "ctx-1"); err != nil || userID != "alice" {
+		t.Errorf("requestUserID() = %q, %v, want alice", userID, err)
+	}
+}
+
+func TestAllowAnonymousFromEnv(t *testing.T) {
+	for v, want := range map[string]bool{"": true, "true": true, "false": false} {
+		t.Setenv(EnvAllowAnonymous, v)
+		if allow, err := AllowAnonymousFromEnv(); err != nil || allow != want {
+			t.Errorf("AllowAnonymousFromEnv() with %q = %v, %v, want %v", v, allow, err, want)
+		}
+	}
+	t.Setenv(EnvAllowAnonymous, "sometimes")
+	if _, err := AllowAnonymousFromEnv(); err == nil {
+		t.Error("AllowAnonymousFromEnv() error = nil, want an error for an invalid value")
+	}
+}
+
+func TestSessionAccessError(t *testing.T) {
+	err := sessionAccessError(fmt.Errorf("get: %w", &session.AccessError{SessionID: "s", AppName: "a", OwnerAgentID: "b"}))
+	if !errors.Is(err, a2atype.ErrUnauthorized) {
+		t.Errorf("sessionAccessError() = %v, want ErrUnauthorized", err)
+	}
+	if err := sessionAccessError(errors.New("backend down")); err != nil {
+		t.Errorf("sessionAccessError() of another error = %v, want nil", err)
+	}
+}
@@ -0,0 +1,192 @@
+package a2a
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"unicode/utf8"
+
+	a2atype "github.com/a2aproject/a2a-go/a2a"
+	"google.golang.org/adk/server/adka2a" //nolint:staticcheck // kagent still uses a2a-go v1; this ADK package is the compatibility adapter.
+	adksession "google.golang.org/adk/session"
+	"google.golang.org/genai"
+)
+
+// The seed corpora under testdata/fuzz hold A2A messages and ADK events as
+// kagent sends and receives them; `go test` replays them on every run and
+// `go test -fuzz=FuzzX` mutates them.
+
+// wireRoundTrip sends part through the A2A JSON encoding, as it travels
+// between agents.
+func wireRoundTrip(t *testing.T, part a2atype.Part) a2atype.Part {
+	t.Helper()
+	data, err := json.Marshal(&a2atype.Message{ID: "msg", Role: a2atype.MessageRoleAgent, Parts: a2atype.ContentParts{part}})
+	if err != nil {
+		t.Fatalf("marshal %T: %v", part, err)
+	}
+	var msg a2atype.Message
+	if err := json.Unmarshal(data, &msg); err != nil {
+		t.Fatalf("unmarshal %s: %v", data, err)
+	}
+	if len(msg.Parts) != 1 {
+		t.Fatalf("round trip produced %d parts from %s", len(msg.Parts), data)
+	}
+	return msg.Parts[0]
+}
+
+// sameArgs compares function call arguments, treating nil and empty as equal.
+func sameArgs(a, b map[string]any) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// FuzzMessageToGenAIContent checks that any decodable inbound A2A message
+// converts without panicking and never gains parts or nil parts.
+func FuzzMessageToGenAIContent(f *testing.F) {
+	f.Add([]byte(`{"kind":"message","messageId":"m1","role":"user","parts":[{"kind":"text","text":"hi"}]}`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var msg a2atype.Message
+		if err := json.Unmarshal(data, &msg); err != nil {
+			return
+		}
+		content, err := messageToGenAIContent(context.Background(), &msg)
+		if err != nil || content == nil {
+			return
+		}
+		wantRole := string(genai.RoleUser)
+		if msg.Role == a2atype.MessageRoleAgent {
+			wantRole = string(genai.RoleModel)
+		}
+		if content.Role != wantRole {
+			t.Errorf("role = %q, want %q for A2A role %q", content.Role, wantRole, msg.Role)
+		}
+		if len(content.Parts) > len(msg.Parts) {
+			t.Errorf("converted %d A2A parts into %d GenAI parts", len(msg.Parts), len(content.Parts))
+		}
+		for i, p := range content.Parts {
+			if p == nil {
+				t.Errorf("GenAI part %d is nil", i)
+			}
+		}
+	})
+}
+
+// FuzzPartRoundTrip checks that text and function call parts survive
+// GenAI → A2A → JSON → GenAI, and that kagent_type function call DataParts
+// convert to the call they describe.
+func FuzzPartRoundTrip(f *testing.F) {
+	f.Add("hello", "get_pods", "call_1", []byte(`{"namespace":"default"}`))
+	f.Add("", "ask_user", "adk-1", []byte(`{"questions":[{"question":"Proceed?","choices":["yes","no"]}]}`))
+	f.Add("multi\nline ✓", "k8s_apply_manifest", "", []byte(`{}`))
+	f.Add(" ", "tool", "id", []byte(`{"n":1.5,"nested":{"list":[1,"two",null,true]}}`))
+
+	f.Fuzz(func(t *testing.T, text, name, id string, args []byte) {
+		ctx := context.Background()
+
+		if text != "" && utf8.ValidString(text) {
+			a2aPart, err := adka2a.ToA2APart(genai.NewPartFromText(text), nil)
+			if err != nil {
+				t.Fatalf("ToA2APart(text) error = %v", err)
+			}
+			back, err := a2aPartConverter(ctx, nil, wireRoundTrip(t, a2aPart))
+			if err != nil {
+				t.Fatalf("a2aPartConverter(text) error = %v", err)
+			}
+			if back == nil || back.Text != text {
+				t.Errorf("text round trip = %+v, want %q", back, text)
+			}
+		}
+
+		var argMap map[string]any
+		if name == "" || !utf8.ValidString(name) || !utf8.ValidString(id) || json.Unmarshal(args, &argMap) != nil {
+			return
+		}
+
+		call := genai.NewPartFromFunctionCall(name, argMap)
+		call.FunctionCall.ID = id
+		a2aPart, err := adka2a.ToA2APart(call, nil)
+		if err != nil {
+			t.Fatalf("ToA2APart(function call) error = %v", err)
+		}
+		back, err := a2aPartConverter(ctx, nil, wireRoundTrip(t, a2aPart))
+		if err != nil {
+			t.Fatalf("a2aPartConverter(function call) error = %v", err)
+		}
+		if back == nil || back.FunctionCall == nil {
+			t.Fatalf("function call round trip = %+v", back)
+		}
+		if back.FunctionCall.Name != name || back.FunctionCall.ID != id || !sameArgs(back.FunctionCall.Args, argMap) {
+			t.Errorf("function call round trip = %+v, want name=%q id=%q args=%v", back.FunctionCall, name, id, argMap)
+		}
+
+		dp := a2atype.DataPart{
+			Data: map[string]any{PartKeyName: name, PartKeyArgs: argMap, PartKeyID: id},
+			Metadata: map[string]any{
+				GetKAgentMetadataKey(A2ADataPartMetadataTypeKey): A2ADataPartMetadataTypeFunctionCall,
+			},
+		}
+		kagentCall, err := a2aPartConverter(ctx, nil, wireRoundTrip(t, dp))
+		if err != nil {
+			t.Fatalf("a2aPartConverter(kagent function call) error = %v", err)
+		}
+		if kagentCall == nil || kagentCall.FunctionCall == nil || kagentCall.FunctionCall.Name != name || kagentCall.FunctionCall.ID != id {
+			t.Errorf("kagent function call = %+v, want name=%q id=%q", kagentCall, name, id)
+		}
+	})
+}
+
+// FuzzEventConverter runs decodable ADK events through the per-event
+// conversion the executor performs and checks the resulting metadata stays
+// JSON-encodable for the A2A wire.
+func FuzzEventConverter(f *testing.F) {
+	f.Add([]byte(`{"Author":"agent","InvocationID":"inv-1","Content":{"role":"model","parts":[{"text":"hi"}]}}`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var ev adksession.Event
+		if err := json.Unmarshal(data, &ev); err != nil {
+			return
+		}
+		base := map[string]any{adka2a.ToA2AMetaKey("app_name"): "app"}
+		meta := buildEventMeta(base, &ev)
+		if meta[adka2a.ToA2AMetaKey("app_name")] != "app" {
+			t.Errorf("buildEventMeta() dropped base metadata: %v", meta)
+		}
+
+		var usage usageTotals
+		usage.add(&ev)
+		usage.stamp(meta)
+		if _, err := json.Marshal(meta); err != nil {
+			t.Errorf("event metadata is not JSON-encodable: %v", err)
+		}
+
+		boundary := isIterationBoundary(&ev)
+		if boundary && (ev.Partial || ev.Content == nil) {
+			t.Errorf("isIterationBoundary() = true for partial or empty event")
+		}
+		if ev.Content == nil {
+			return
+		}
+
+		var parts a2atype.ContentParts
+		for _, genaiPart := range ev.Content.Parts {
+			if genaiPart == nil {
+				continue
+			}
+			a2aPart, err := adka2a.ToA2APart(genaiPart, ev.LongRunningToolIDs)
+			if err != nil || isEmptyDataPart(a2aPart) {
+				continue
+			}
+			parts = append(parts, stampSubagentSessionID(a2aPart, map[string]string{"agent": "sub-session"}))
+		}
+		if len(filterTextParts(parts)) > len(parts) {
+			t.Errorf("filterTextParts() returned more parts than it was given")
+		}
+		if _, err := json.Marshal(&a2atype.Message{ID: "msg", Role: a2atype.MessageRoleAgent, Parts: parts}); err != nil {
+			t.Errorf("converted parts are not JSON-encodable: %v", err)
+		}
+	})
+}
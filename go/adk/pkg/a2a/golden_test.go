@@ -0,0 +1,166 @@
+package a2a
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	a2atype "github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+	"github.com/go-logr/logr"
+	"github.com/kagent-dev/kagent/go/adk/pkg/models"
+	adkagent "google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/runner"
+	adksession "google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+// Run with -update (or UPDATE_GOLDEN=true) to rewrite the golden event
+// streams under testdata/golden after an intended behaviour change.
+var updateGolden = flag.Bool("update", os.Getenv("UPDATE_GOLDEN") == "true", "update golden event stream files")
+
+// goldenScenario is one scripted executor run recorded as a golden file.
+type goldenScenario struct {
+	name    string
+	turns   []models.ScriptTurn
+	tools   []tool.Tool
+	message string
+}
+
+type addInput struct {
+	A int `json:"a"`
+	B int `json:"b"`
+}
+
+func newAddTool(t *testing.T) tool.Tool {
+	t.Helper()
+	add, err := functiontool.New(functiontool.Config{
+		Name:        "add",
+		Description: "Adds two numbers.",
+	}, func(_ adkagent.ToolContext, in addInput) (map[string]any, error) {
+		return map[string]any{"sum": in.A + in.B}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return add
+}
+
+func TestExecutor_GoldenEventStreams(t *testing.T) {
+	scenarios := []goldenScenario{
+		{
+			name:    "text_reply",
+			message: "hi",
+			turns:   []models.ScriptTurn{{Text: "hello"}},
+		},
+		{
+			name:    "tool_call",
+			message: "what is 2 + 3?",
+			turns: []models.ScriptTurn{
+				{ToolCalls: []models.ScriptToolCall{{Name: "add", Args: map[string]any{"a": 2, "b": 3}}}},
+				{Text: "2 + 3 = 5"},
+			},
+			tools: []tool.Tool{newAddTool(t)},
+		},
+		{
+			// An exhausted script makes the model answer with an error code.
+			name:    "llm_error",
+			message: "hi",
+		},
+	}
+
+	for _, sc := range scenarios {
+		t.Run(sc.name, func(t *testing.T) {
+			assertGolden(t, sc.name, runGoldenScenario(t, sc))
+		})
+	}
+}
+
+// runGoldenScenario executes the scenario with a fixed clock and sequential
+// IDs and returns the recorded event stream as normalized JSON.
+func runGoldenScenario(t *testing.T, sc goldenScenario) []byte {
+	t.Helper()
+	ctx := context.Background()
+
+	agent, err := llmagent.New(llmagent.Config{
+		Name:  "golden",
+		Model: &models.ScriptedModel{Script: &models.Script{Turns: sc.turns}, Logger: logr.Discard()},
+		Tools: sc.tools,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessions := adksession.InMemoryService()
+	if _, err := sessions.Create(ctx, &adksession.CreateRequest{AppName: "app", UserID: "A2A_USER_ctx-1", SessionID: "ctx-1"}); err != nil {
+		t.Fatal(err)
+	}
+	executor := NewKAgentExecutor(KAgentExecutorConfig{
+		RunnerConfig:   runner.Config{AppName: "app", Agent: agent, SessionService: sessions},
+		AppName:        "app",
+		AllowAnonymous: true,
+		Clock:          fixedClock{time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		IDGenerator:    &sequentialIDs{},
+		Logger:         logr.Discard(),
+	})
+	executor.skillsDirectory = ""
+
+	queue := &recordingQueue{}
+	reqCtx := &a2asrv.RequestContext{
+		Message:   &a2atype.Message{ID: "msg-1", Role: a2atype.MessageRoleUser, Parts: a2atype.ContentParts{a2atype.TextPart{Text: sc.message}}},
+		TaskID:    "task-1",
+		ContextID: "ctx-1",
+	}
+	if err := executor.Execute(ctx, reqCtx, queue); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	return normalizeEventStream(t, queue.events)
+}
+
+// uuidPattern matches the IDs ADK generates itself (invocation, event and
+// function call IDs), which the executor's IDGenerator does not control.
+var uuidPattern = regexp.MustCompile(`[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`)
+
+// normalizeEventStream renders events as indented JSON with ADK-generated
+// UUIDs replaced by a placeholder.
+func normalizeEventStream(t *testing.T, events []a2atype.Event) []byte {
+	t.Helper()
+	data, err := json.MarshalIndent(events, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return append(uuidPattern.ReplaceAll(data, []byte("<uuid>")), '\n')
+}
+
+// assertGolden compares got with testdata/golden/<name>.json, rewriting the
+// file instead when -update is set.
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", "golden", name+".json")
+	if *updateGolden {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		t.Fatalf("golden file %s does not exist; run with -update to record it", path)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("event stream differs from %s (run with -update to accept):\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
@@ -0,0 +1,106 @@
+package a2a
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	a2atype "github.com/a2aproject/a2a-go/a2a"
+	adksession "google.golang.org/adk/session"
+	"google.golang.org/genai"
+)
+
+// parseHistory reads the earlier messages of a conversation a message
+// carries under kagent_history. Every message needs a messageId, which its
+// session event keeps under MetadataKeyMessageID.
+func parseHistory(metadata map[string]any) ([]*a2atype.Message, error) {
+	v, ok := ReadMetadataValue(metadata, MetadataKeyHistory)
+	if !ok || v == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s%s metadata: %w", KAgentMetadataKeyPrefix, MetadataKeyHistory, err)
+	}
+	var history []*a2atype.Message
+	if err := json.Unmarshal(raw, &history); err != nil {
+		return nil, fmt.Errorf("invalid %s%s metadata: %w", KAgentMetadataKeyPrefix, MetadataKeyHistory, err)
+	}
+	for i, msg := range history {
+		if msg == nil || msg.ID == "" {
+			return nil, fmt.Errorf("invalid %s%s metadata: message %d has no messageId", KAgentMetadataKeyPrefix, MetadataKeyHistory, i)
+		}
+	}
+	return history, nil
+}
+
+// historyEvents converts history to the session events of one invocation,
+// in order. Messages whose ID an event of sess already carries, or that
+// are in skip, are left out, so a client can resend the same history
+// safely. The events get fresh IDs, since event IDs are unique per user
+// and message IDs are chosen by clients. User messages are authored by the
+// user and agent messages by agentName.
+func (e *KAgentExecutor) historyEvents(ctx context.Context, sess adksession.Session, agentName string, history []*a2atype.Message, skip ...string) ([]*adksession.Event, error) {
+	seen := make(map[string]bool, len(skip))
+	for _, id := range skip {
+		seen[id] = true
+	}
+	for ev := range sess.Events().All() {
+		if id, ok := ev.CustomMetadata[MetadataKeyMessageID].(string); ok {
+			seen[id] = true
+		}
+	}
+
+	invocationID := "e-" + e.events.ids.NewID()
+	var events []*adksession.Event
+	for _, msg := range history {
+		if seen[msg.ID] {
+			continue
+		}
+		seen[msg.ID] = true
+		content, err := messageToGenAIContent(ctx, msg)
+		if err != nil {
+			return nil, fmt.Errorf("history message %s: %w", msg.ID, err)
+		}
+		if len(content.Parts) == 0 {
+			continue
+		}
+		ev := adksession.NewEvent(invocationID)
+		ev.CustomMetadata = map[string]any{MetadataKeyMessageID: msg.ID}
+		ev.Timestamp = e.events.clock.Now()
+		ev.Author = string(genai.RoleUser)
+		if msg.Role == a2atype.MessageRoleAgent {
+			ev.Author = agentName
+		}
+		ev.Content = content
+		events = append(events, ev)
+	}
+	return events, nil
+}
+
+// ingestHistory appends the history a message carries to the session, so
+// a conversation started elsewhere continues with its earlier turns. It
+// returns the number of events appended.
+func (e *KAgentExecutor) ingestHistory(ctx context.Context, userID, sessionID string, msg *a2atype.Message, history []*a2atype.Message) (int, error) {
+	sess, err := e.sessionService.GetSession(ctx, e.appName, userID, sessionID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load session: %w", err)
+	}
+	if sess == nil {
+		return 0, fmt.Errorf("session %s not found", sessionID)
+	}
+	var agentName string
+	if e.runnerConfig.Agent != nil {
+		agentName = e.runnerConfig.Agent.Name()
+	}
+	events, err := e.historyEvents(ctx, sess, agentName, history, msg.ID)
+	if err != nil {
+		return 0, err
+	}
+	for i, ev := range events {
+		if err := e.sessionService.AppendEvent(ctx, sess, ev); err != nil {
+			return i, fmt.Errorf("failed to append history message %v: %w", ev.CustomMetadata[MetadataKeyMessageID], err)
+		}
+	}
+	return len(events), nil
+}
@@ -0,0 +1,77 @@
+package a2a
+
+import (
+	"context"
+	"testing"
+
+	a2atype "github.com/a2aproject/a2a-go/a2a"
+	adksession "google.golang.org/adk/session"
+	"google.golang.org/genai"
+)
+
+func TestParseHistory(t *testing.T) {
+	history, err := parseHistory(map[string]any{
+		"kagent_history": []any{
+			map[string]any{"kind": "message", "messageId": "m1", "role": "user", "parts": []any{map[string]any{"kind": "text", "text": "hi"}}},
+			map[string]any{"kind": "message", "messageId": "m2", "role": "agent", "parts": []any{map[string]any{"kind": "text", "text": "hello"}}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("parseHistory() error = %v", err)
+	}
+	if len(history) != 2 || history[1].ID != "m2" || history[1].Role != a2atype.MessageRoleAgent {
+		t.Errorf("parseHistory() = %+v, want both messages", history)
+	}
+
+	if history, err := parseHistory(map[string]any{"other": 1}); history != nil || err != nil {
+		t.Errorf("parseHistory() without the key = %v, %v, want nil, nil", history, err)
+	}
+	for _, bad := range []any{
+		"hi",
+		[]any{map[string]any{"kind": "message", "role": "user", "parts": []any{}}},
+		[]any{nil},
+	} {
+		if _, err := parseHistory(map[string]any{"kagent_history": bad}); err == nil {
+			t.Errorf("parseHistory(%v) error = nil, want an error", bad)
+		}
+	}
+}
+
+func TestHistoryEvents(t *testing.T) {
+	ctx := context.Background()
+	svc := adksession.InMemoryService()
+	created, err := svc.Create(ctx, &adksession.CreateRequest{AppName: "app", UserID: "user", SessionID: "s1"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	existing := adksession.NewEvent("e-1")
+	existing.CustomMetadata = map[string]any{MetadataKeyMessageID: "m1"}
+	existing.Author = "user"
+	existing.Content = genai.NewContentFromText("hi", genai.RoleUser)
+	if err := svc.AppendEvent(ctx, created.Session, existing); err != nil {
+		t.Fatalf("AppendEvent() error = %v", err)
+	}
+	got, err := svc.Get(ctx, &adksession.GetRequest{AppName: "app", UserID: "user", SessionID: "s1"})
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	history := []*a2atype.Message{
+		a2atype.NewMessage(a2atype.MessageRoleUser, a2atype.TextPart{Text: "hi"}),
+		a2atype.NewMessage(a2atype.MessageRoleAgent, a2atype.TextPart{Text: "hello"}),
+		a2atype.NewMessage(a2atype.MessageRoleUser, a2atype.DataPart{Data: map[string]any{"decision_type": "approve"}}),
+		a2atype.NewMessage(a2atype.MessageRoleUser, a2atype.TextPart{Text: "current"}),
+	}
+	history[0].ID = "m1"
+	e := NewKAgentExecutor(KAgentExecutorConfig{})
+	events, err := e.historyEvents(ctx, got.Session, "helper", history, history[3].ID)
+	if err != nil {
+		t.Fatalf("historyEvents() error = %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("historyEvents() = %d events, want only the agent reply", len(events))
+	}
+	if ev := events[0]; ev.ID == history[1].ID || ev.CustomMetadata[MetadataKeyMessageID] != history[1].ID || ev.Author != "helper" || ev.Content.Role != string(genai.RoleModel) || ev.Content.Parts[0].Text != "hello" {
+		t.Errorf("historyEvents() = %+v", ev)
+	}
+}
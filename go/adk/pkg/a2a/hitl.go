@@ -366,13 +366,13 @@ func ExtractPendingConfirmationsFromParts(parts a2atype.ContentParts) map[string
 
 // BuildResumeHITLMessage converts an inbound user HITL decision into the
 // adk_request_confirmation FunctionResponse message expected by the Go ADK
-// executor for a stored input_required task.
+// executor for a stored task paused in input_required or auth_required.
 func BuildResumeHITLMessage(storedTask *a2atype.Task, incoming *a2atype.Message) *a2atype.Message {
 	decision := ExtractDecisionFromMessage(incoming)
 	if decision == "" {
 		return nil
 	}
-	if storedTask == nil || storedTask.Status.State != a2atype.TaskStateInputRequired || storedTask.Status.Message == nil {
+	if storedTask == nil || !isPausedState(storedTask.Status.State) || storedTask.Status.Message == nil {
 		return nil
 	}
 
@@ -389,6 +389,12 @@ func BuildResumeHITLMessage(storedTask *a2atype.Task, incoming *a2atype.Message)
 	return a2atype.NewMessage(a2atype.MessageRoleUser, responseParts...)
 }
 
+// isPausedState reports whether a task in state is waiting for the user.
+// auth_required is only reported when a task state rule opts into it.
+func isPausedState(state a2atype.TaskState) bool {
+	return state == a2atype.TaskStateInputRequired || state == a2atype.TaskStateAuthRequired
+}
+
 // ProcessHitlDecision processes a HITL decision and returns A2A DataParts
 // representing FunctionResponse(s) with ToolConfirmation payloads.
 func ProcessHitlDecision(
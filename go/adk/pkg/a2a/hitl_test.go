@@ -497,6 +497,19 @@ func TestBuildResumeHITLMessage(t *testing.T) {
 	if dp.Data[PartKeyID] != "confirm_1" {
 		t.Fatalf("resume FunctionResponse id = %#v", dp.Data[PartKeyID])
 	}
+
+	// Tasks opted into auth_required resume the same way.
+	authTask := *storedTask
+	authTask.Status.State = a2atype.TaskStateAuthRequired
+	if BuildResumeHITLMessage(&authTask, incoming) == nil {
+		t.Error("BuildResumeHITLMessage() returned nil for an auth_required task")
+	}
+
+	completedTask := *storedTask
+	completedTask.Status.State = a2atype.TaskStateCompleted
+	if BuildResumeHITLMessage(&completedTask, incoming) != nil {
+		t.Error("BuildResumeHITLMessage() resumed a completed task")
+	}
 }
 
 // ---------------------------------------------------------------------------
@@ -0,0 +1,61 @@
+package a2a
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	a2atype "github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+	"github.com/kagent-dev/kagent/go/adk/pkg/session"
+)
+
+// EnvAllowAnonymous lets requests without an x-user-id header run as an
+// anonymous user scoped to their context. It defaults to true, so callers
+// that do not forward a user keep working; set it to false to reject them.
+const EnvAllowAnonymous = "KAGENT_ALLOW_ANONYMOUS"
+
+// anonymousUserPrefix prefixes the context ID to form the user ID of an
+// anonymous request.
+const anonymousUserPrefix = "A2A_USER_"
+
+// AllowAnonymousFromEnv reports whether KAGENT_ALLOW_ANONYMOUS allows
+// anonymous requests, defaulting to true.
+func AllowAnonymousFromEnv() (bool, error) {
+	v := strings.TrimSpace(os.Getenv(EnvAllowAnonymous))
+	if v == "" {
+		return true, nil
+	}
+	allow, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s %q", EnvAllowAnonymous, v)
+	}
+	return allow, nil
+}
+
+// requestUserID returns the authenticated user of the request, or the
+// anonymous user of contextID when anonymous requests are allowed.
+func (e *KAgentExecutor) requestUserID(ctx context.Context, contextID string) (string, error) {
+	if callCtx, ok := a2asrv.CallContextFrom(ctx); ok {
+		if callCtx.User != nil && callCtx.User.Name() != "" {
+			return callCtx.User.Name(), nil
+		}
+	}
+	if !e.allowAnonymous {
+		return "", fmt.Errorf("%w: the request has no x-user-id header and anonymous requests are not allowed", a2atype.ErrUnauthenticated)
+	}
+	return anonymousUserPrefix + contextID, nil
+}
+
+// sessionAccessError maps a session that belongs to another app to
+// a2a.ErrUnauthorized, keeping the session.AccessError in the chain.
+func sessionAccessError(err error) error {
+	var accessErr *session.AccessError
+	if errors.As(err, &accessErr) {
+		return fmt.Errorf("%w: %w", a2atype.ErrUnauthorized, accessErr)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	a2atype "github.com/a2aproject/a2a-go/a2a"
+)
+
+func TestAgentCardHandler_ETag(t *testing.T) {
+	handler, err := newAgentCardHandler(&a2atype.AgentCard{Name: "test-agent"})
+	if err != nil {
+		t.Fatalf("newAgentCardHandler() error = %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/.well-known/agent-card.json", nil))
+	etag := rec.Header().Get("ETag")
+	if rec.Code != http.StatusOK || etag == "" || rec.Body.Len() == 0 {
+		t.Fatalf("GET = %d, ETag %q, %d bytes; want 200 with an ETag and the card", rec.Code, etag, rec.Body.Len())
+	}
+
+	for _, ifNoneMatch := range []string{etag, "W/" + etag, `"other", ` + etag, "*"} {
+		req := httptest.NewRequest(http.MethodGet, "/.well-known/agent-card.json", nil)
+		req.Header.Set("If-None-Match", ifNoneMatch)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+			t.Errorf("If-None-Match %s = %d with %d bytes, want 304 and no body", ifNoneMatch, rec.Code, rec.Body.Len())
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/agent-card.json", nil)
+	req.Header.Set("If-None-Match", `"stale"`)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("stale If-None-Match = %d, want 200", rec.Code)
+	}
+
+	other, err := newAgentCardHandler(&a2atype.AgentCard{Name: "other-agent"})
+	if err != nil {
+		t.Fatalf("newAgentCardHandler() error = %v", err)
+	}
+	rec = httptest.NewRecorder()
+	other.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/.well-known/agent-card.json", nil))
+	if rec.Header().Get("ETag") == etag {
+		t.Errorf("different cards share ETag %s", etag)
+	}
+}
@@ -0,0 +1,207 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	a2atype "github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+	"github.com/go-logr/logr"
+)
+
+// FeedbackPattern is the mux pattern of the task feedback endpoint.
+const FeedbackPattern = "POST /a2a/tasks/{taskId}/feedback"
+
+// MetadataKeyFeedback is the task metadata key holding the feedback given on
+// a task, oldest first.
+const MetadataKeyFeedback = "kagent_feedback"
+
+// maxFeedbackBytes bounds the body of a feedback request.
+const maxFeedbackBytes = 64 << 10
+
+// anonymousUserPrefix prefixes the context ID to form the user of a request
+// without an X-User-ID header, like the kagent executor does.
+const anonymousUserPrefix = "A2A_USER_"
+
+// Thumbs values of a Feedback.
+const (
+	ThumbsUp   = "up"
+	ThumbsDown = "down"
+)
+
+// Feedback is a user's judgement of a task's answer: thumbs up or down, a
+// rating from 1 to 5, or both, with an optional comment.
+type Feedback struct {
+	Thumbs  string `json:"thumbs,omitempty"`
+	Rating  *int   `json:"rating,omitempty"`
+	Comment string `json:"comment,omitempty"`
+}
+
+// Validate checks that the feedback carries a thumbs value or a rating.
+func (f Feedback) Validate() error {
+	if f.Thumbs == "" && f.Rating == nil {
+		return errors.New("feedback needs thumbs or a rating")
+	}
+	if f.Thumbs != "" && f.Thumbs != ThumbsUp && f.Thumbs != ThumbsDown {
+		return fmt.Errorf("thumbs must be %q or %q", ThumbsUp, ThumbsDown)
+	}
+	if f.Rating != nil && (*f.Rating < 1 || *f.Rating > 5) {
+		return errors.New("rating must be between 1 and 5")
+	}
+	return nil
+}
+
+// TaskFeedback is feedback as stored on a task and passed to the
+// FeedbackExporters.
+type TaskFeedback struct {
+	Feedback
+	TaskID    string `json:"task_id"`
+	ContextID string `json:"context_id,omitempty"`
+	// InvocationID is the agent run that produced the task's answer, when
+	// the executor recorded it.
+	InvocationID string `json:"invocation_id,omitempty"`
+	AgentName    string `json:"agent_name,omitempty"`
+	// PromptVersion, PromptExperiment and PromptArm identify the prompt
+	// version that answered, when the agent has versioned prompts.
+	PromptVersion    string    `json:"prompt_version,omitempty"`
+	PromptExperiment string    `json:"prompt_experiment,omitempty"`
+	PromptArm        string    `json:"prompt_arm,omitempty"`
+	UserID           string    `json:"user_id,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// FeedbackExporter forwards task feedback to a telemetry backend.
+type FeedbackExporter interface {
+	ExportFeedback(ctx context.Context, feedback TaskFeedback) error
+}
+
+// FeedbackHandler serves FeedbackPattern: it appends the feedback to the
+// task's metadata in store, then forwards it to exporters. Export failures
+// are logged; the feedback is already stored.
+//
+// Only the user whose session the task belongs to may give feedback on it;
+// tasks of other users are reported as not found. Requests without an
+// X-User-ID header act as the anonymous user of the task's context when
+// allowAnonymous is set, as the executor does, and are rejected otherwise.
+func FeedbackHandler(store a2asrv.TaskStore, agentName string, exporters []FeedbackExporter, allowAnonymous bool, logger logr.Logger) http.Handler {
+	h := &feedbackHandler{store: store, agentName: agentName, exporters: exporters, allowAnonymous: allowAnonymous, logger: logger}
+	return DescribeHandler(h, map[string]OpenAPIOperation{
+		http.MethodPost: {
+			Summary:     "Give feedback on a task",
+			Description: `Records {"thumbs": "up"|"down", "rating": 1-5, "comment": "..."} on the task. The user is read from the X-User-ID header and must own the task.`,
+			Responses: map[string]string{
+				"201": "The stored feedback as JSON",
+				"400": "Invalid feedback",
+				"401": "No X-User-ID header and anonymous requests are not allowed",
+				"404": "Unknown task, or a task of another user",
+			},
+		},
+	})
+}
+
+type feedbackHandler struct {
+	store          a2asrv.TaskStore
+	agentName      string
+	exporters      []FeedbackExporter
+	allowAnonymous bool
+	logger         logr.Logger
+}
+
+func (h *feedbackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var feedback Feedback
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxFeedbackBytes)).Decode(&feedback); err != nil {
+		http.Error(w, "invalid feedback: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	feedback.Comment = strings.TrimSpace(feedback.Comment)
+	if err := feedback.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" && !h.allowAnonymous {
+		http.Error(w, "the request has no X-User-ID header and anonymous requests are not allowed", http.StatusUnauthorized)
+		return
+	}
+
+	ctx := r.Context()
+	taskID := a2atype.TaskID(r.PathValue("taskId"))
+	task, version, err := h.store.Get(ctx, taskID)
+	if errors.Is(err, a2atype.ErrTaskNotFound) {
+		http.Error(w, "task not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if userID == "" {
+		userID = anonymousUserPrefix + task.ContextID
+	}
+	if owner, _ := task.Metadata[metadataKeyUserID].(string); owner != userID {
+		h.logger.Info("Rejected feedback on a task of another user", "taskID", task.ID, "userID", userID)
+		http.Error(w, "task not found", http.StatusNotFound)
+		return
+	}
+
+	entry := TaskFeedback{
+		Feedback:  feedback,
+		TaskID:    string(task.ID),
+		ContextID: task.ContextID,
+		AgentName: h.agentName,
+		UserID:    userID,
+		CreatedAt: time.Now().UTC(),
+	}
+	for key, field := range map[string]*string{
+		metadataKeyInvocationID:     &entry.InvocationID,
+		metadataKeyPromptVersion:    &entry.PromptVersion,
+		metadataKeyPromptExperiment: &entry.PromptExperiment,
+		metadataKeyPromptArm:        &entry.PromptArm,
+	} {
+		*field, _ = task.Metadata[key].(string)
+	}
+	if err := h.save(ctx, task, version, entry); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	for _, exporter := range h.exporters {
+		if err := exporter.ExportFeedback(ctx, entry); err != nil {
+			h.logger.Error(err, "Failed to export feedback", "taskID", entry.TaskID)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(entry)
+}
+
+// save appends entry to the feedback stored in the task's metadata.
+func (h *feedbackHandler) save(ctx context.Context, task *a2atype.Task, version a2atype.TaskVersion, entry TaskFeedback) error {
+	var stored []any
+	if existing, ok := task.Metadata[MetadataKeyFeedback].([]any); ok {
+		stored = slices.Clone(existing)
+	}
+	value, err := metadataMap(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode feedback: %w", err)
+	}
+	updated := *task
+	updated.Metadata = maps.Clone(task.Metadata)
+	if updated.Metadata == nil {
+		updated.Metadata = map[string]any{}
+	}
+	updated.Metadata[MetadataKeyFeedback] = append(stored, value)
+	if _, err := h.store.Save(ctx, &updated, nil, task, version); err != nil {
+		return fmt.Errorf("failed to save feedback: %w", err)
+	}
+	return nil
+}
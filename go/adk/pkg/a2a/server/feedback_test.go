@@ -0,0 +1,149 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	a2atype "github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+	"github.com/go-logr/logr"
+)
+
+// memTaskStore keeps tasks in a map.
+type memTaskStore struct {
+	a2asrv.TaskStore
+	tasks map[a2atype.TaskID]*a2atype.Task
+}
+
+func (s *memTaskStore) Get(_ context.Context, id a2atype.TaskID) (*a2atype.Task, a2atype.TaskVersion, error) {
+	task, ok := s.tasks[id]
+	if !ok {
+		return nil, a2atype.TaskVersionMissing, a2atype.ErrTaskNotFound
+	}
+	return task, 1, nil
+}
+
+func (s *memTaskStore) Save(_ context.Context, task *a2atype.Task, _ a2atype.Event, _ *a2atype.Task, _ a2atype.TaskVersion) (a2atype.TaskVersion, error) {
+	s.tasks[task.ID] = task
+	return 1, nil
+}
+
+type feedbackRecorder struct {
+	got []TaskFeedback
+	err error
+}
+
+func (r *feedbackRecorder) ExportFeedback(_ context.Context, feedback TaskFeedback) error {
+	r.got = append(r.got, feedback)
+	return r.err
+}
+
+func postFeedback(handler http.Handler, taskID, body string) *httptest.ResponseRecorder {
+	return postFeedbackAs(handler, "alice", taskID, body)
+}
+
+func postFeedbackAs(handler http.Handler, userID, taskID, body string) *httptest.ResponseRecorder {
+	mux := http.NewServeMux()
+	mux.Handle(FeedbackPattern, handler)
+	req := httptest.NewRequest(http.MethodPost, "/a2a/tasks/"+taskID+"/feedback", strings.NewReader(body))
+	if userID != "" {
+		req.Header.Set("X-User-ID", userID)
+	}
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestFeedbackHandler(t *testing.T) {
+	store := &memTaskStore{tasks: map[a2atype.TaskID]*a2atype.Task{
+		"task-1": {ID: "task-1", ContextID: "ctx-1", Metadata: map[string]any{metadataKeyUserID: "alice", metadataKeyInvocationID: "inv-1", metadataKeyPromptVersion: "v2"}},
+	}}
+	exporters := []*feedbackRecorder{{err: errors.New("backend down")}, {}}
+	handler := FeedbackHandler(store, "helper", []FeedbackExporter{exporters[0], exporters[1]}, false, logr.Discard())
+
+	for _, body := range []string{`{"thumbs": "up"}`, `{"rating": 2, "comment": " too slow "}`} {
+		if rec := postFeedback(handler, "task-1", body); rec.Code != http.StatusCreated {
+			t.Fatalf("POST %s = %d %s", body, rec.Code, rec.Body)
+		}
+	}
+
+	stored := store.tasks["task-1"].Metadata[MetadataKeyFeedback].([]any)
+	if len(stored) != 2 {
+		t.Fatalf("stored feedback = %v, want both entries", stored)
+	}
+	second := stored[1].(map[string]any)
+	if second["rating"] != 2.0 || second["comment"] != "too slow" || second["user_id"] != "alice" || second["invocation_id"] != "inv-1" {
+		t.Errorf("stored feedback = %v", second)
+	}
+	for _, exporter := range exporters {
+		if len(exporter.got) != 2 {
+			t.Fatalf("exported %d feedbacks, want 2 despite export errors", len(exporter.got))
+		}
+	}
+	got := exporters[1].got[0]
+	if got.Thumbs != ThumbsUp || got.TaskID != "task-1" || got.ContextID != "ctx-1" || got.AgentName != "helper" || got.PromptVersion != "v2" {
+		t.Errorf("exported feedback = %+v", got)
+	}
+}
+
+func TestFeedbackHandler_Errors(t *testing.T) {
+	store := &memTaskStore{tasks: map[a2atype.TaskID]*a2atype.Task{
+		"task-1": {ID: "task-1", Metadata: map[string]any{metadataKeyUserID: "alice"}},
+		"task-3": {ID: "task-3", Metadata: map[string]any{metadataKeyUserID: "bob"}},
+	}}
+	handler := FeedbackHandler(store, "helper", nil, false, logr.Discard())
+
+	tests := map[string]struct {
+		taskID string
+		body   string
+		want   int
+	}{
+		"unknown task":   {taskID: "task-2", body: `{"thumbs": "up"}`, want: http.StatusNotFound},
+		"other's task":   {taskID: "task-3", body: `{"thumbs": "up"}`, want: http.StatusNotFound},
+		"empty feedback": {taskID: "task-1", body: `{"comment": "meh"}`, want: http.StatusBadRequest},
+		"bad thumbs":     {taskID: "task-1", body: `{"thumbs": "sideways"}`, want: http.StatusBadRequest},
+		"bad rating":     {taskID: "task-1", body: `{"rating": 6}`, want: http.StatusBadRequest},
+		"invalid JSON":   {taskID: "task-1", body: `{`, want: http.StatusBadRequest},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if rec := postFeedback(handler, tt.taskID, tt.body); rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+	if rec := postFeedbackAs(handler, "", "task-1", `{"thumbs": "up"}`); rec.Code != http.StatusUnauthorized {
+		t.Errorf("anonymous status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+	for _, id := range []a2atype.TaskID{"task-1", "task-3"} {
+		if _, ok := store.tasks[id].Metadata[MetadataKeyFeedback]; ok {
+			t.Errorf("rejected feedback was stored on %s", id)
+		}
+	}
+
+	var entry TaskFeedback
+	rec := postFeedback(handler, "task-1", `{"thumbs": "down"}`)
+	if err := json.Unmarshal(rec.Body.Bytes(), &entry); err != nil || entry.Thumbs != ThumbsDown {
+		t.Errorf("response = %s, want the stored feedback", rec.Body)
+	}
+}
+
+func TestFeedbackHandler_Anonymous(t *testing.T) {
+	store := &memTaskStore{tasks: map[a2atype.TaskID]*a2atype.Task{
+		"task-1": {ID: "task-1", ContextID: "ctx-1", Metadata: map[string]any{metadataKeyUserID: anonymousUserPrefix + "ctx-1"}},
+		"task-2": {ID: "task-2", ContextID: "ctx-2", Metadata: map[string]any{metadataKeyUserID: "alice"}},
+	}}
+	handler := FeedbackHandler(store, "helper", nil, true, logr.Discard())
+
+	if rec := postFeedbackAs(handler, "", "task-1", `{"thumbs": "up"}`); rec.Code != http.StatusCreated {
+		t.Errorf("anonymous status = %d %s, want %d", rec.Code, rec.Body, http.StatusCreated)
+	}
+	if rec := postFeedbackAs(handler, "", "task-2", `{"thumbs": "up"}`); rec.Code != http.StatusNotFound {
+		t.Errorf("anonymous status on alice's task = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
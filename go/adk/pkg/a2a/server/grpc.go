@@ -0,0 +1,83 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	a2atype "github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2agrpc"
+	"github.com/a2aproject/a2a-go/a2asrv"
+	"google.golang.org/grpc"
+)
+
+// EnvGRPC enables the A2A gRPC service next to the JSON-RPC handler.
+const EnvGRPC = "KAGENT_A2A_GRPC"
+
+// GRPCEnabledFromEnv reports whether KAGENT_A2A_GRPC enables gRPC.
+func GRPCEnabledFromEnv() (bool, error) {
+	v := strings.TrimSpace(os.Getenv(EnvGRPC))
+	if v == "" {
+		return false, nil
+	}
+	enabled, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s %q", EnvGRPC, v)
+	}
+	return enabled, nil
+}
+
+// newGRPCServer registers the A2A gRPC service (SendMessage,
+// SendStreamingMessage, CancelTask, ...) backed by the same request handler
+// as the JSON-RPC endpoint.
+func newGRPCServer(requestHandler a2asrv.RequestHandler, limits RequestLimits) *grpc.Server {
+	var opts []grpc.ServerOption
+	if limits.MaxBodyBytes > 0 {
+		opts = append(opts, grpc.MaxRecvMsgSize(int(limits.MaxBodyBytes)))
+	}
+	s := grpc.NewServer(opts...)
+	a2agrpc.NewHandler(requestHandler).RegisterWith(s)
+	return s
+}
+
+// withGRPC routes HTTP/2 gRPC requests to grpcServer and everything else to
+// next, so both transports share the server port.
+func withGRPC(grpcServer *grpc.Server, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor == 2 && strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+			grpcServer.ServeHTTP(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// advertiseGRPC adds a gRPC interface to the agent card, using the host and
+// port of the card's URL as the gRPC target.
+func advertiseGRPC(card *a2atype.AgentCard) {
+	u, err := url.Parse(card.URL)
+	if err != nil || u.Host == "" {
+		return
+	}
+	target := u.Host
+	if u.Port() == "" {
+		port := "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+		target = net.JoinHostPort(u.Hostname(), port)
+	}
+	for _, iface := range card.AdditionalInterfaces {
+		if iface.Transport == a2atype.TransportProtocolGRPC {
+			return
+		}
+	}
+	card.AdditionalInterfaces = append(card.AdditionalInterfaces, a2atype.AgentInterface{
+		URL:       target,
+		Transport: a2atype.TransportProtocolGRPC,
+	})
+}
@@ -0,0 +1,101 @@
+package server
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+
+	a2atype "github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2aclient"
+	"github.com/a2aproject/a2a-go/a2asrv"
+	"github.com/a2aproject/a2a-go/a2asrv/eventqueue"
+	"github.com/go-logr/logr"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// echoExecutor replies with the text of the first part of the request.
+type echoExecutor struct{}
+
+func (echoExecutor) Execute(ctx context.Context, reqCtx *a2asrv.RequestContext, queue eventqueue.Queue) error {
+	text := reqCtx.Message.Parts[0].(a2atype.TextPart).Text
+	return queue.Write(ctx, a2atype.NewMessage(a2atype.MessageRoleAgent, a2atype.TextPart{Text: "echo: " + text}))
+}
+
+func (echoExecutor) Cancel(ctx context.Context, reqCtx *a2asrv.RequestContext, queue eventqueue.Queue) error {
+	return nil
+}
+
+func TestA2AServer_GRPC(t *testing.T) {
+	card := a2atype.AgentCard{Name: "echo", URL: "http://echo.test:8080"}
+	srv, err := NewA2AServer(card, echoExecutor{}, logr.Discard(), ServerConfig{GRPC: true})
+	if err != nil {
+		t.Fatalf("NewA2AServer() error = %v", err)
+	}
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() { _ = srv.httpServer.Serve(lis) }()
+	t.Cleanup(func() { _ = srv.httpServer.Close() })
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	transport := a2aclient.NewGRPCTransport(conn)
+	t.Cleanup(func() { _ = transport.Destroy() })
+
+	result, err := transport.SendMessage(t.Context(), &a2atype.MessageSendParams{
+		Message: a2atype.NewMessage(a2atype.MessageRoleUser, a2atype.TextPart{Text: "hi"}),
+	})
+	if err != nil {
+		t.Fatalf("SendMessage() over gRPC error = %v", err)
+	}
+	msg, ok := result.(*a2atype.Message)
+	if !ok {
+		t.Fatalf("SendMessage() result = %T, want *a2a.Message", result)
+	}
+	if got := msg.Parts[0].(a2atype.TextPart).Text; got != "echo: hi" {
+		t.Errorf("reply = %q, want %q", got, "echo: hi")
+	}
+
+	// JSON-RPC over HTTP/1.1 keeps working on the same port.
+	body := `{"jsonrpc":"2.0","id":1,"method":"message/send","params":{"message":{"kind":"message","messageId":"m1","role":"user","parts":[{"kind":"text","text":"hi"}]}}}`
+	resp, err := http.Post("http://"+lis.Addr().String()+"/", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("JSON-RPC request error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("JSON-RPC status = %d, want 200", resp.StatusCode)
+	}
+}
+
+func TestAdvertiseGRPC(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{url: "http://agent.ns:8080", want: "agent.ns:8080"},
+		{url: "https://agent.example.com/a2a", want: "agent.example.com:443"},
+		{url: "", want: ""},
+	}
+	for _, tt := range tests {
+		card := a2atype.AgentCard{URL: tt.url}
+		advertiseGRPC(&card)
+		advertiseGRPC(&card)
+		if tt.want == "" {
+			if len(card.AdditionalInterfaces) != 0 {
+				t.Errorf("advertiseGRPC(%q) added %v", tt.url, card.AdditionalInterfaces)
+			}
+			continue
+		}
+		if len(card.AdditionalInterfaces) != 1 || card.AdditionalInterfaces[0].URL != tt.want ||
+			card.AdditionalInterfaces[0].Transport != a2atype.TransportProtocolGRPC {
+			t.Errorf("advertiseGRPC(%q) = %v, want one gRPC interface at %s", tt.url, card.AdditionalInterfaces, tt.want)
+		}
+	}
+}
@@ -1,16 +1,42 @@
 package server
 
 import (
+	"fmt"
 	"net/http"
+	"strings"
 )
 
+// ReadinessCheck reports whether a dependency of the agent is ready. A nil
+// error means ready.
+type ReadinessCheck struct {
+	Name  string
+	Check func() error
+}
+
 // RegisterHealthEndpoints registers health check endpoints on the given mux.
 // These endpoints are used by Kubernetes for readiness/liveness probes.
-func RegisterHealthEndpoints(mux *http.ServeMux) {
+// /readyz additionally runs the readiness checks and answers 503 listing the
+// failed ones.
+func RegisterHealthEndpoints(mux *http.ServeMux, checks ...ReadinessCheck) {
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write([]byte("OK"))
 	})
 	mux.Handle("/health", handler)
 	mux.Handle("/healthz", handler)
+	mux.Handle("/readyz", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var failures []string
+		for _, c := range checks {
+			if err := c.Check(); err != nil {
+				failures = append(failures, fmt.Sprintf("%s: %v", c.Name, strings.ReplaceAll(err.Error(), "\n", "; ")))
+			}
+		}
+		if len(failures) > 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(strings.Join(failures, "\n")))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("OK"))
+	}))
 }
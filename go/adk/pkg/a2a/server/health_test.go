@@ -0,0 +1,38 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRegisterHealthEndpoints_Readyz(t *testing.T) {
+	var tokenErr error
+	mux := http.NewServeMux()
+	RegisterHealthEndpoints(mux,
+		ReadinessCheck{Name: "token", Check: func() error { return tokenErr }},
+		ReadinessCheck{Name: "other", Check: func() error { return nil }},
+	)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	if rec := get("/readyz"); rec.Code != http.StatusOK {
+		t.Errorf("/readyz status = %d, want 200", rec.Code)
+	}
+
+	tokenErr = errors.New("kagent token expired")
+	rec := get("/readyz")
+	if rec.Code != http.StatusServiceUnavailable || rec.Body.String() != "token: kagent token expired" {
+		t.Errorf("/readyz = %d %q, want 503 naming the failed check", rec.Code, rec.Body.String())
+	}
+	// Liveness is not affected by readiness checks.
+	if rec := get("/healthz"); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "OK") {
+		t.Errorf("/healthz = %d %q, want 200", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,210 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Environment variables configuring RequestLimits.
+const (
+	EnvMaxBodyBytes    = "KAGENT_A2A_MAX_BODY_BYTES"
+	EnvMaxMessageParts = "KAGENT_A2A_MAX_MESSAGE_PARTS"
+	EnvMaxPartBytes    = "KAGENT_A2A_MAX_PART_BYTES"
+	EnvStrictJSON      = "KAGENT_A2A_STRICT_JSON"
+)
+
+// DefaultMaxBodyBytes is the request body limit used when
+// KAGENT_A2A_MAX_BODY_BYTES is not set.
+const DefaultMaxBodyBytes = 32 << 20
+
+// JSON-RPC error code for requests rejected by RequestLimits.
+const jsonrpcInvalidRequest = -32600
+
+// RequestLimits bounds the A2A JSON-RPC requests accepted by the server.
+// Zero values disable the corresponding check.
+type RequestLimits struct {
+	// MaxBodyBytes is the maximum request body size; larger requests get 413.
+	MaxBodyBytes int64
+	// MaxMessageParts is the maximum number of parts in a sent message.
+	MaxMessageParts int
+	// MaxPartBytes is the maximum encoded size of a single message part.
+	MaxPartBytes int
+	// StrictJSON rejects requests carrying fields unknown to the A2A schema.
+	StrictJSON bool
+}
+
+// RequestLimitsFromEnv reads RequestLimits from the KAGENT_A2A_* env vars.
+func RequestLimitsFromEnv() (RequestLimits, error) {
+	limits := RequestLimits{MaxBodyBytes: DefaultMaxBodyBytes}
+	var err error
+	if v := strings.TrimSpace(os.Getenv(EnvMaxBodyBytes)); v != "" {
+		if limits.MaxBodyBytes, err = strconv.ParseInt(v, 10, 64); err != nil || limits.MaxBodyBytes < 0 {
+			return RequestLimits{}, fmt.Errorf("invalid %s %q", EnvMaxBodyBytes, v)
+		}
+	}
+	if v := strings.TrimSpace(os.Getenv(EnvMaxMessageParts)); v != "" {
+		if limits.MaxMessageParts, err = strconv.Atoi(v); err != nil || limits.MaxMessageParts < 0 {
+			return RequestLimits{}, fmt.Errorf("invalid %s %q", EnvMaxMessageParts, v)
+		}
+	}
+	if v := strings.TrimSpace(os.Getenv(EnvMaxPartBytes)); v != "" {
+		if limits.MaxPartBytes, err = strconv.Atoi(v); err != nil || limits.MaxPartBytes < 0 {
+			return RequestLimits{}, fmt.Errorf("invalid %s %q", EnvMaxPartBytes, v)
+		}
+	}
+	if v := strings.TrimSpace(os.Getenv(EnvStrictJSON)); v != "" {
+		if limits.StrictJSON, err = strconv.ParseBool(v); err != nil {
+			return RequestLimits{}, fmt.Errorf("invalid %s %q", EnvStrictJSON, v)
+		}
+	}
+	return limits, nil
+}
+
+func (l RequestLimits) enabled() bool {
+	return l.MaxBodyBytes > 0 || l.MaxMessageParts > 0 || l.MaxPartBytes > 0 || l.StrictJSON
+}
+
+// Middleware enforces the limits on POST requests before they reach next.
+// Rejected requests get a JSON-RPC error with HTTP status 413 or 400.
+func (l RequestLimits) Middleware(next http.Handler) http.Handler {
+	if !l.enabled() {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			next.ServeHTTP(w, r)
+			return
+		}
+		body := io.Reader(r.Body)
+		if l.MaxBodyBytes > 0 {
+			body = http.MaxBytesReader(w, r.Body, l.MaxBodyBytes)
+		}
+		data, err := io.ReadAll(body)
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				writeJSONRPCError(w, http.StatusRequestEntityTooLarge, nil,
+					fmt.Sprintf("request body exceeds %d bytes", maxBytesErr.Limit))
+				return
+			}
+			writeJSONRPCError(w, http.StatusBadRequest, nil, "failed to read request body")
+			return
+		}
+		if status, id, msg := l.validate(data); status != 0 {
+			writeJSONRPCError(w, status, id, msg)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(data))
+		r.ContentLength = int64(len(data))
+		next.ServeHTTP(w, r)
+	})
+}
+
+// validate checks a JSON-RPC request body. It returns a zero status when the
+// request is accepted. Malformed JSON is left to the JSON-RPC handler so the
+// client gets the usual parse error.
+func (l RequestLimits) validate(data []byte) (status int, id json.RawMessage, msg string) {
+	var req struct {
+		JSONRPC string          `json:"jsonrpc"`
+		ID      json.RawMessage `json:"id"`
+		Method  string          `json:"method"`
+		Params  json.RawMessage `json:"params"`
+	}
+	if err := json.Unmarshal(data, &req); err != nil {
+		return 0, nil, ""
+	}
+	if l.StrictJSON {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&req); err != nil {
+			return http.StatusBadRequest, req.ID, fmt.Sprintf("invalid request: %v", err)
+		}
+	}
+	if req.Method != "message/send" && req.Method != "message/stream" {
+		return 0, nil, ""
+	}
+
+	var params struct {
+		Message struct {
+			Parts []json.RawMessage `json:"parts"`
+		} `json:"message"`
+	}
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return 0, nil, ""
+	}
+	if l.StrictJSON {
+		if err := checkKnownFields(req.Params, messageSendParamsFields); err != nil {
+			return http.StatusBadRequest, req.ID, fmt.Sprintf("invalid params: %v", err)
+		}
+		var message struct {
+			Message json.RawMessage `json:"message"`
+		}
+		_ = json.Unmarshal(req.Params, &message)
+		if err := checkKnownFields(message.Message, messageFields); err != nil {
+			return http.StatusBadRequest, req.ID, fmt.Sprintf("invalid message: %v", err)
+		}
+	}
+	if l.MaxMessageParts > 0 && len(params.Message.Parts) > l.MaxMessageParts {
+		return http.StatusBadRequest, req.ID,
+			fmt.Sprintf("message has %d parts, at most %d are allowed", len(params.Message.Parts), l.MaxMessageParts)
+	}
+	if l.MaxPartBytes > 0 {
+		for i, part := range params.Message.Parts {
+			if len(part) > l.MaxPartBytes {
+				return http.StatusRequestEntityTooLarge, req.ID,
+					fmt.Sprintf("message part %d is %d bytes, at most %d are allowed", i, len(part), l.MaxPartBytes)
+			}
+		}
+	}
+	return 0, nil, ""
+}
+
+// Fields of the A2A MessageSendParams and Message objects. The a2a-go types
+// cannot be decoded strictly because they marshal discriminators such as
+// "kind" by hand.
+var (
+	messageSendParamsFields = []string{"message", "configuration", "metadata"}
+	messageFields           = []string{"kind", "messageId", "role", "parts", "contextId", "taskId", "referenceTaskIds", "metadata", "extensions"}
+)
+
+// checkKnownFields reports the first key of the JSON object raw that is not
+// in allowed.
+func checkKnownFields(raw json.RawMessage, allowed []string) error {
+	if len(raw) == 0 {
+		return nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return err
+	}
+	for name := range fields {
+		if !slices.Contains(allowed, name) {
+			return fmt.Errorf("unknown field %q", name)
+		}
+	}
+	return nil
+}
+
+func writeJSONRPCError(w http.ResponseWriter, status int, id json.RawMessage, msg string) {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      id,
+		"error": map[string]any{
+			"code":    jsonrpcInvalidRequest,
+			"message": msg,
+		},
+	})
+}
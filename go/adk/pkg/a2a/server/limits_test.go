@@ -0,0 +1,121 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func sendMessageBody(parts ...string) string {
+	return `{"jsonrpc":"2.0","id":7,"method":"message/send","params":{"message":{"kind":"message","messageId":"m1","role":"user","parts":[` +
+		strings.Join(parts, ",") + `]}}}`
+}
+
+func TestRequestLimits_Middleware(t *testing.T) {
+	textPart := `{"kind":"text","text":"hello"}`
+	tests := []struct {
+		name       string
+		limits     RequestLimits
+		body       string
+		wantStatus int
+	}{
+		{
+			name:       "accepted",
+			limits:     RequestLimits{MaxBodyBytes: 1024, MaxMessageParts: 2, MaxPartBytes: 100, StrictJSON: true},
+			body:       sendMessageBody(textPart, textPart),
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "body too large",
+			limits:     RequestLimits{MaxBodyBytes: 16},
+			body:       sendMessageBody(textPart),
+			wantStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:       "too many parts",
+			limits:     RequestLimits{MaxMessageParts: 1},
+			body:       sendMessageBody(textPart, textPart),
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "part too large",
+			limits:     RequestLimits{MaxPartBytes: 10},
+			body:       sendMessageBody(textPart),
+			wantStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:       "unknown field in strict mode",
+			limits:     RequestLimits{StrictJSON: true},
+			body:       `{"jsonrpc":"2.0","id":7,"method":"message/send","params":{"message":{"kind":"message","messageId":"m1","role":"user","parts":[]},"extra":true}}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "unknown field allowed by default",
+			limits:     RequestLimits{MaxBodyBytes: 1024},
+			body:       `{"jsonrpc":"2.0","id":7,"method":"message/send","params":{"message":{"parts":[]},"extra":true}}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "malformed JSON is left to the handler",
+			limits:     RequestLimits{MaxMessageParts: 1},
+			body:       `{not json`,
+			wantStatus: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotBody string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				b, _ := io.ReadAll(r.Body)
+				gotBody = string(b)
+			})
+			rec := httptest.NewRecorder()
+			tt.limits.Middleware(next).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body)))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus == http.StatusOK {
+				if gotBody != tt.body {
+					t.Errorf("handler body = %q, want %q", gotBody, tt.body)
+				}
+				return
+			}
+			var resp struct {
+				ID    json.RawMessage `json:"id"`
+				Error struct {
+					Code    int    `json:"code"`
+					Message string `json:"message"`
+				} `json:"error"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("invalid error response %q: %v", rec.Body.String(), err)
+			}
+			if resp.Error.Code != jsonrpcInvalidRequest || resp.Error.Message == "" {
+				t.Errorf("error = %+v", resp.Error)
+			}
+		})
+	}
+}
+
+func TestRequestLimitsFromEnv(t *testing.T) {
+	t.Setenv(EnvMaxBodyBytes, "")
+	t.Setenv(EnvMaxMessageParts, "10")
+	t.Setenv(EnvStrictJSON, "true")
+	limits, err := RequestLimitsFromEnv()
+	if err != nil {
+		t.Fatalf("RequestLimitsFromEnv() error = %v", err)
+	}
+	want := RequestLimits{MaxBodyBytes: DefaultMaxBodyBytes, MaxMessageParts: 10, StrictJSON: true}
+	if limits != want {
+		t.Errorf("RequestLimitsFromEnv() = %+v, want %+v", limits, want)
+	}
+
+	t.Setenv(EnvMaxPartBytes, "big")
+	if _, err := RequestLimitsFromEnv(); err == nil {
+		t.Error("expected error for invalid part limit")
+	}
+}
@@ -0,0 +1,678 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	a2atype "github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+	"github.com/kagent-dev/kagent/go/api/adk"
+)
+
+// EnvOpenAICompat serves an OpenAI-compatible chat completions API at
+// /v1/chat/completions, next to /v1/models listing the agent as the only
+// model, so OpenAI SDKs and chat UIs can talk to the agent.
+const EnvOpenAICompat = "KAGENT_OPENAI_COMPAT"
+
+// OpenAICompatEnabledFromEnv reports whether KAGENT_OPENAI_COMPAT enables
+// the OpenAI-compatible endpoints.
+func OpenAICompatEnabledFromEnv() (bool, error) {
+	v := strings.TrimSpace(os.Getenv(EnvOpenAICompat))
+	if v == "" {
+		return false, nil
+	}
+	enabled, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s %q", EnvOpenAICompat, v)
+	}
+	return enabled, nil
+}
+
+// Metadata keys read from the events of kagent executors. They mirror the
+// constants of the a2a package, which this package does not import.
+const (
+	metadataKeyUsage      = "kagent_usage_metadata"
+	metadataKeyGeneration = "kagent_generation"
+	metadataKeyPartial    = "adk_partial"
+	// metadataKeyInvocationID is set on the final event of a run.
+	metadataKeyInvocationID = "adk_invocation_id"
+	// metadataKeyUserID, the user whose session a run belongs to, is set on
+	// all its events.
+	metadataKeyUserID = "adk_user_id"
+	// The prompt version of a run is set on all its events.
+	metadataKeyPromptVersion    = "kagent_prompt_version"
+	metadataKeyPromptExperiment = "kagent_prompt_experiment"
+	metadataKeyPromptArm        = "kagent_prompt_arm"
+)
+
+// toolConfirmationCall is the function call kagent pauses on for tool
+// approvals; it wraps the call awaiting approval.
+const toolConfirmationCall = "adk_request_confirmation"
+
+// openAICompatHandlers returns the OpenAI-compatible endpoints, keyed by mux
+// pattern.
+//
+// A chat completion request runs the agent once, like message/send or
+// message/stream: OpenAI clients are stateless, so the messages before the
+// last one, which must come from the user, are passed to the agent as a
+// transcript, and every request starts a new conversation. The model field
+// is ignored; responses name the agent as the model. The completion ID is
+// chatcmpl- followed by the A2A task ID. Tools run inside the agent, so the
+// response only carries tool_calls when the task pauses on tool approvals
+// (finish_reason tool_calls); the task can then be resumed over A2A.
+// temperature, top_p, max_tokens, max_completion_tokens and stop override
+// the agent's generation settings.
+func openAICompatHandlers(card *a2atype.AgentCard, requestHandler a2asrv.RequestHandler, limits RequestLimits) map[string]http.Handler {
+	completions := &chatCompletionsHandler{model: card.Name, requestHandler: requestHandler, maxBodyBytes: limits.MaxBodyBytes}
+	return map[string]http.Handler{
+		"POST /v1/chat/completions": DescribeHandler(completions, map[string]OpenAPIOperation{
+			http.MethodPost: {
+				Summary:     "OpenAI-compatible chat completion",
+				Description: "Runs the agent on OpenAI chat messages. With stream set, answers with a text/event-stream of chat.completion.chunk objects ending in [DONE].",
+				Responses: map[string]string{
+					"200": "A chat.completion, or a stream of chunks",
+					"400": "Invalid request",
+					"413": "Request body too large",
+					"500": "The agent run failed",
+				},
+			},
+		}),
+		"GET /v1/models": DescribeHandler(modelsHandler(card.Name), map[string]OpenAPIOperation{
+			http.MethodGet: {Summary: "OpenAI-compatible model list", Responses: map[string]string{"200": "The agent, as the only model"}},
+		}),
+	}
+}
+
+func modelsHandler(model string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"object": "list",
+			"data":   []map[string]any{{"id": model, "object": "model", "created": 0, "owned_by": "kagent"}},
+		})
+	})
+}
+
+type chatCompletionRequest struct {
+	Model         string        `json:"model"`
+	Messages      []chatMessage `json:"messages"`
+	Stream        bool          `json:"stream"`
+	StreamOptions *struct {
+		IncludeUsage bool `json:"include_usage"`
+	} `json:"stream_options"`
+	N                   *int          `json:"n"`
+	Temperature         *float64      `json:"temperature"`
+	TopP                *float64      `json:"top_p"`
+	MaxTokens           *int          `json:"max_tokens"`
+	MaxCompletionTokens *int          `json:"max_completion_tokens"`
+	Stop                stopSequences `json:"stop"`
+}
+
+type chatMessage struct {
+	Role       string         `json:"role"`
+	Content    chatContent    `json:"content"`
+	ToolCalls  []chatToolCall `json:"tool_calls"`
+	ToolCallID string         `json:"tool_call_id"`
+}
+
+// chatContent is the content of a request message: a string or an array of
+// content parts.
+type chatContent []chatContentPart
+
+type chatContentPart struct {
+	Type     string `json:"type"`
+	Text     string `json:"text"`
+	ImageURL *struct {
+		URL string `json:"url"`
+	} `json:"image_url"`
+}
+
+func (c *chatContent) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*c = chatContent{{Type: "text", Text: text}}
+		return nil
+	}
+	var parts []chatContentPart
+	if err := json.Unmarshal(data, &parts); err != nil {
+		return errors.New("content must be a string or an array of content parts")
+	}
+	*c = parts
+	return nil
+}
+
+// text returns the text parts of the content.
+func (c chatContent) text() string {
+	var b strings.Builder
+	for _, part := range c {
+		if part.Type == "text" {
+			b.WriteString(part.Text)
+		}
+	}
+	return b.String()
+}
+
+// stopSequences is the stop field: a string or an array of strings.
+type stopSequences []string
+
+func (s *stopSequences) UnmarshalJSON(data []byte) error {
+	var stop string
+	if err := json.Unmarshal(data, &stop); err == nil {
+		*s = stopSequences{stop}
+		return nil
+	}
+	var stops []string
+	if err := json.Unmarshal(data, &stops); err != nil {
+		return errors.New("stop must be a string or an array of strings")
+	}
+	*s = stops
+	return nil
+}
+
+type chatCompletion struct {
+	ID      string       `json:"id"`
+	Object  string       `json:"object"`
+	Created int64        `json:"created"`
+	Model   string       `json:"model"`
+	Choices []chatChoice `json:"choices"`
+	Usage   *chatUsage   `json:"usage,omitempty"`
+}
+
+type chatChoice struct {
+	Index        int                  `json:"index"`
+	Message      *chatResponseMessage `json:"message,omitempty"`
+	Delta        *chatResponseMessage `json:"delta,omitempty"`
+	FinishReason *string              `json:"finish_reason"`
+}
+
+type chatResponseMessage struct {
+	Role      string         `json:"role,omitempty"`
+	Content   *string        `json:"content,omitempty"`
+	ToolCalls []chatToolCall `json:"tool_calls,omitempty"`
+}
+
+type chatToolCall struct {
+	// Index is only set on streamed tool calls.
+	Index    *int   `json:"index,omitempty"`
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Function struct {
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"`
+	} `json:"function"`
+}
+
+type chatUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// Finish reasons.
+const (
+	finishStop      = "stop"
+	finishToolCalls = "tool_calls"
+)
+
+// chatError is an error answered in the OpenAI error format.
+type chatError struct {
+	status  int
+	kind    string
+	message string
+}
+
+func (e *chatError) Error() string { return e.message }
+
+func invalidChatRequest(format string, args ...any) *chatError {
+	return &chatError{status: http.StatusBadRequest, kind: "invalid_request_error", message: fmt.Sprintf(format, args...)}
+}
+
+// chatServerError converts an error of the request handler.
+func chatServerError(err error) *chatError {
+	if errors.Is(err, a2atype.ErrInvalidParams) || errors.Is(err, a2atype.ErrInvalidRequest) {
+		return invalidChatRequest("%s", err.Error())
+	}
+	return &chatError{status: http.StatusInternalServerError, kind: "server_error", message: err.Error()}
+}
+
+func (e *chatError) body() map[string]any {
+	return map[string]any{"error": map[string]any{"message": e.message, "type": e.kind, "code": nil}}
+}
+
+func writeChatError(w http.ResponseWriter, err *chatError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(err.status)
+	_ = json.NewEncoder(w).Encode(err.body())
+}
+
+// chatCompletionsHandler serves /v1/chat/completions.
+type chatCompletionsHandler struct {
+	model          string
+	requestHandler a2asrv.RequestHandler
+	maxBodyBytes   int64
+}
+
+func (h *chatCompletionsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body := r.Body
+	if h.maxBodyBytes > 0 {
+		body = http.MaxBytesReader(w, body, h.maxBodyBytes)
+	}
+	var req chatCompletionRequest
+	if err := json.NewDecoder(body).Decode(&req); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeChatError(w, &chatError{
+				status:  http.StatusRequestEntityTooLarge,
+				kind:    "invalid_request_error",
+				message: fmt.Sprintf("request body exceeds %d bytes", maxBytesErr.Limit),
+			})
+			return
+		}
+		writeChatError(w, invalidChatRequest("invalid request body: %v", err))
+		return
+	}
+	params, chatErr := messageSendParams(&req)
+	if chatErr != nil {
+		writeChatError(w, chatErr)
+		return
+	}
+
+	// Headers such as Authorization and X-User-ID reach the executor like
+	// they do from the JSON-RPC handler.
+	ctx, _ := a2asrv.WithCallContext(r.Context(), a2asrv.NewRequestMeta(r.Header))
+	if req.Stream {
+		includeUsage := req.StreamOptions != nil && req.StreamOptions.IncludeUsage
+		h.stream(ctx, w, params, includeUsage)
+		return
+	}
+	result, err := h.requestHandler.OnSendMessage(ctx, params)
+	if err != nil {
+		writeChatError(w, chatServerError(err))
+		return
+	}
+	completion, chatErr := h.completion(result)
+	if chatErr != nil {
+		writeChatError(w, chatErr)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(completion)
+}
+
+// messageSendParams converts a chat completion request to the A2A message
+// the agent runs on.
+func messageSendParams(req *chatCompletionRequest) (*a2atype.MessageSendParams, *chatError) {
+	if len(req.Messages) == 0 {
+		return nil, invalidChatRequest("messages must not be empty")
+	}
+	if req.N != nil && *req.N != 1 {
+		return nil, invalidChatRequest("n must be 1")
+	}
+	last := req.Messages[len(req.Messages)-1]
+	if last.Role != "user" {
+		return nil, invalidChatRequest("the last message must have the user role, got %q", last.Role)
+	}
+
+	var parts a2atype.ContentParts
+	if transcript := chatTranscript(req.Messages[:len(req.Messages)-1]); transcript != "" {
+		parts = append(parts, a2atype.TextPart{Text: transcript})
+	}
+	for _, part := range last.Content {
+		switch part.Type {
+		case "text":
+			parts = append(parts, a2atype.TextPart{Text: part.Text})
+		case "image_url":
+			if part.ImageURL == nil || part.ImageURL.URL == "" {
+				return nil, invalidChatRequest("image_url parts must have a url")
+			}
+			parts = append(parts, imagePart(part.ImageURL.URL))
+		default:
+			return nil, invalidChatRequest("unsupported content part type %q", part.Type)
+		}
+	}
+	if len(parts) == 0 {
+		return nil, invalidChatRequest("the last message must not be empty")
+	}
+
+	msg := a2atype.NewMessage(a2atype.MessageRoleUser, parts...)
+	generation := adk.GenerationConfig{
+		Temperature:     req.Temperature,
+		TopP:            req.TopP,
+		MaxOutputTokens: req.MaxTokens,
+		StopSequences:   req.Stop,
+	}
+	if req.MaxCompletionTokens != nil {
+		generation.MaxOutputTokens = req.MaxCompletionTokens
+	}
+	if err := generation.Validate(); err != nil {
+		return nil, invalidChatRequest("%s", err.Error())
+	}
+	if overrides, err := metadataMap(generation); err != nil {
+		return nil, invalidChatRequest("%s", err.Error())
+	} else if len(overrides) > 0 {
+		msg.Metadata = map[string]any{metadataKeyGeneration: overrides}
+	}
+
+	params := &a2atype.MessageSendParams{Message: msg}
+	if req.Stream {
+		// Like an OpenAI completion, the run stops when the client goes away.
+		params.Metadata = map[string]any{MetadataKeyExecutionMode: ExecutionModeAttached}
+	}
+	return params, nil
+}
+
+// chatTranscript renders the earlier messages of a conversation as text, one
+// "role: content" entry per message.
+func chatTranscript(messages []chatMessage) string {
+	if len(messages) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("Conversation so far:\n")
+	for _, m := range messages {
+		switch {
+		case m.Role == "tool":
+			fmt.Fprintf(&b, "\ntool (result of %s): %s\n", m.ToolCallID, m.Content.text())
+		case len(m.ToolCalls) > 0:
+			if text := m.Content.text(); text != "" {
+				fmt.Fprintf(&b, "\n%s: %s\n", m.Role, text)
+			}
+			for _, call := range m.ToolCalls {
+				fmt.Fprintf(&b, "\n%s (called %s as %s): %s\n", m.Role, call.Function.Name, call.ID, call.Function.Arguments)
+			}
+		default:
+			fmt.Fprintf(&b, "\n%s: %s\n", m.Role, m.Content.text())
+		}
+	}
+	return b.String()
+}
+
+// imagePart converts an image URL, which may be a base64 data URL, to a
+// file part.
+func imagePart(url string) a2atype.FilePart {
+	if rest, ok := strings.CutPrefix(url, "data:"); ok {
+		if meta, data, ok := strings.Cut(rest, ","); ok {
+			if mimeType, ok := strings.CutSuffix(meta, ";base64"); ok {
+				return a2atype.FilePart{File: a2atype.FileBytes{FileMeta: a2atype.FileMeta{MimeType: mimeType}, Bytes: data}}
+			}
+		}
+	}
+	return a2atype.FilePart{File: a2atype.FileURI{URI: url}}
+}
+
+// metadataMap converts v to its JSON object form for message metadata.
+func metadataMap(v any) (map[string]any, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]any
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// completion converts the result of message/send to a chat completion.
+func (h *chatCompletionsHandler) completion(result a2atype.SendMessageResult) (*chatCompletion, *chatError) {
+	completion := &chatCompletion{
+		Object:  "chat.completion",
+		Created: time.Now().Unix(),
+		Model:   h.model,
+	}
+	message := &chatResponseMessage{Role: "assistant"}
+	finish := finishStop
+	switch r := result.(type) {
+	case *a2atype.Message:
+		completion.ID = "chatcmpl-" + r.ID
+		message.Content = new(partsText(r.Parts))
+	case *a2atype.Task:
+		completion.ID = "chatcmpl-" + string(r.ID)
+		completion.Usage = chatUsageFrom(r.Metadata)
+		var statusParts a2atype.ContentParts
+		if r.Status.Message != nil {
+			statusParts = r.Status.Message.Parts
+		}
+		switch r.Status.State {
+		case a2atype.TaskStateCompleted:
+			var text strings.Builder
+			for _, artifact := range r.Artifacts {
+				text.WriteString(partsText(artifact.Parts))
+			}
+			if text.Len() == 0 {
+				text.WriteString(partsText(statusParts))
+			}
+			message.Content = new(text.String())
+		case a2atype.TaskStateInputRequired, a2atype.TaskStateAuthRequired:
+			if text := partsText(statusParts); text != "" {
+				message.Content = new(text)
+			}
+			if message.ToolCalls = chatToolCalls(statusParts); len(message.ToolCalls) > 0 {
+				finish = finishToolCalls
+			}
+		default:
+			return nil, taskEndedError(r.Status.State, statusParts)
+		}
+	default:
+		return nil, &chatError{status: http.StatusInternalServerError, kind: "server_error", message: fmt.Sprintf("unexpected result %T", result)}
+	}
+	completion.Choices = []chatChoice{{Message: message, FinishReason: &finish}}
+	return completion, nil
+}
+
+// taskEndedError reports a task that failed, was canceled or was rejected.
+func taskEndedError(state a2atype.TaskState, parts a2atype.ContentParts) *chatError {
+	message := fmt.Sprintf("agent task ended in state %s", state)
+	if text := partsText(parts); text != "" {
+		message += ": " + text
+	}
+	return &chatError{status: http.StatusInternalServerError, kind: "server_error", message: message}
+}
+
+// stream answers a streaming chat completion with chat.completion.chunk
+// events. Only the text the agent streams is forwarded; if it streams none,
+// the final text is sent in one chunk.
+func (h *chatCompletionsHandler) stream(ctx context.Context, w http.ResponseWriter, params *a2atype.MessageSendParams, includeUsage bool) {
+	rc := http.NewResponseController(w)
+	var (
+		chunk = chatCompletion{
+			Object:  "chat.completion.chunk",
+			Created: time.Now().Unix(),
+			Model:   h.model,
+		}
+		started  bool
+		streamed bool
+		usage    *chatUsage
+		finish   string
+	)
+	write := func(v any) {
+		raw, _ := json.Marshal(v)
+		_, _ = fmt.Fprintf(w, "data: %s\n\n", raw)
+		_ = rc.Flush()
+	}
+	writeDelta := func(delta *chatResponseMessage) {
+		chunk.Choices = []chatChoice{{Delta: delta}}
+		write(chunk)
+	}
+	start := func(id string) {
+		if started {
+			return
+		}
+		started = true
+		chunk.ID = "chatcmpl-" + id
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		writeDelta(&chatResponseMessage{Role: "assistant", Content: new("")})
+	}
+	fail := func(err *chatError) {
+		if !started {
+			writeChatError(w, err)
+			return
+		}
+		write(err.body())
+	}
+
+	for event, err := range h.requestHandler.OnSendMessageStream(ctx, params) {
+		if err != nil {
+			fail(chatServerError(err))
+			return
+		}
+		switch e := event.(type) {
+		case *a2atype.Message:
+			start(e.ID)
+			if text := partsText(e.Parts); text != "" {
+				writeDelta(&chatResponseMessage{Content: &text})
+			}
+			finish = finishStop
+		case *a2atype.Task:
+			start(string(e.ID))
+		case *a2atype.TaskArtifactUpdateEvent:
+			start(string(e.TaskID))
+			if text := partsText(e.Artifact.Parts); text != "" && !streamed {
+				writeDelta(&chatResponseMessage{Content: &text})
+			}
+		case *a2atype.TaskStatusUpdateEvent:
+			start(string(e.TaskID))
+			var parts a2atype.ContentParts
+			if e.Status.Message != nil {
+				parts = e.Status.Message.Parts
+			}
+			switch e.Status.State {
+			case a2atype.TaskStateWorking:
+				if partial, _ := e.Metadata[metadataKeyPartial].(bool); partial {
+					if text := partsText(parts); text != "" {
+						streamed = true
+						writeDelta(&chatResponseMessage{Content: &text})
+					}
+				}
+			case a2atype.TaskStateCompleted:
+				usage = chatUsageFrom(e.Metadata)
+				finish = finishStop
+			case a2atype.TaskStateInputRequired, a2atype.TaskStateAuthRequired:
+				usage = chatUsageFrom(e.Metadata)
+				finish = finishStop
+				if calls := chatToolCalls(parts); len(calls) > 0 {
+					for i := range calls {
+						calls[i].Index = new(i)
+					}
+					writeDelta(&chatResponseMessage{ToolCalls: calls})
+					finish = finishToolCalls
+				}
+			case a2atype.TaskStateFailed, a2atype.TaskStateCanceled, a2atype.TaskStateRejected:
+				fail(taskEndedError(e.Status.State, parts))
+				return
+			}
+		}
+	}
+	if !started {
+		fail(&chatError{status: http.StatusInternalServerError, kind: "server_error", message: "the agent returned no events"})
+		return
+	}
+	if finish == "" {
+		// The stream ended without a final event, e.g. the client left.
+		return
+	}
+
+	chunk.Choices = []chatChoice{{Delta: &chatResponseMessage{}, FinishReason: &finish}}
+	write(chunk)
+	if includeUsage && usage != nil {
+		chunk.Choices = []chatChoice{}
+		chunk.Usage = usage
+		write(chunk)
+	}
+	_, _ = io.WriteString(w, "data: [DONE]\n\n")
+	_ = rc.Flush()
+}
+
+// partsText concatenates the text parts of parts.
+func partsText(parts a2atype.ContentParts) string {
+	var b strings.Builder
+	for _, part := range parts {
+		switch p := part.(type) {
+		case a2atype.TextPart:
+			b.WriteString(p.Text)
+		case *a2atype.TextPart:
+			b.WriteString(p.Text)
+		}
+	}
+	return b.String()
+}
+
+// chatToolCalls returns the function calls among parts as OpenAI tool calls,
+// unwrapping the calls awaiting approval.
+func chatToolCalls(parts a2atype.ContentParts) []chatToolCall {
+	var calls []chatToolCall
+	for _, part := range parts {
+		var data, meta map[string]any
+		switch p := part.(type) {
+		case a2atype.DataPart:
+			data, meta = p.Data, p.Metadata
+		case *a2atype.DataPart:
+			data, meta = p.Data, p.Metadata
+		default:
+			continue
+		}
+		if meta["adk_type"] != "function_call" && meta["kagent_type"] != "function_call" {
+			continue
+		}
+		name, _ := data["name"].(string)
+		args, _ := data["args"].(map[string]any)
+		id, _ := data["id"].(string)
+		if name == toolConfirmationCall {
+			if original, ok := args["originalFunctionCall"].(map[string]any); ok {
+				name, _ = original["name"].(string)
+				id, _ = original["id"].(string)
+				args, _ = original["args"].(map[string]any)
+			}
+		}
+		if args == nil {
+			args = map[string]any{}
+		}
+		arguments, err := json.Marshal(args)
+		if err != nil {
+			continue
+		}
+		call := chatToolCall{ID: id, Type: "function"}
+		call.Function.Name = name
+		call.Function.Arguments = string(arguments)
+		calls = append(calls, call)
+	}
+	return calls
+}
+
+// chatUsageFrom converts the token usage kagent executors report under
+// kagent_usage_metadata.
+func chatUsageFrom(metadata map[string]any) *chatUsage {
+	um, ok := metadata[metadataKeyUsage].(map[string]any)
+	if !ok {
+		return nil
+	}
+	count := func(key string) int {
+		switch v := um[key].(type) {
+		case float64:
+			return int(v)
+		case int:
+			return v
+		case int32:
+			return int(v)
+		}
+		return 0
+	}
+	usage := &chatUsage{
+		PromptTokens:     count("promptTokenCount"),
+		CompletionTokens: count("candidatesTokenCount") + count("thoughtsTokenCount"),
+		TotalTokens:      count("totalTokenCount"),
+	}
+	if usage.TotalTokens == 0 {
+		usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+	}
+	return usage
+}
@@ -0,0 +1,249 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	a2atype "github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+)
+
+// chatAgent answers messages with fixed results and records what it got.
+type chatAgent struct {
+	a2asrv.RequestHandler
+	result a2atype.SendMessageResult
+	events []a2atype.Event
+	params *a2atype.MessageSendParams
+}
+
+func (a *chatAgent) OnSendMessage(_ context.Context, params *a2atype.MessageSendParams) (a2atype.SendMessageResult, error) {
+	a.params = params
+	return a.result, nil
+}
+
+func (a *chatAgent) OnSendMessageStream(_ context.Context, params *a2atype.MessageSendParams) iter.Seq2[a2atype.Event, error] {
+	a.params = params
+	return func(yield func(a2atype.Event, error) bool) {
+		for _, event := range a.events {
+			if !yield(event, nil) {
+				return
+			}
+		}
+	}
+}
+
+func postChat(t *testing.T, agent *chatAgent, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	card := &a2atype.AgentCard{Name: "helper"}
+	handler := openAICompatHandlers(card, agent, RequestLimits{MaxBodyBytes: 1 << 10})["POST /v1/chat/completions"]
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/chat/completions", strings.NewReader(body)))
+	return rec
+}
+
+var usageMetadata = map[string]any{
+	metadataKeyUsage: map[string]any{"promptTokenCount": 12.0, "candidatesTokenCount": 5.0, "totalTokenCount": 17.0},
+}
+
+func TestChatCompletions(t *testing.T) {
+	agent := &chatAgent{result: &a2atype.Task{
+		ID:        "task-1",
+		Status:    a2atype.TaskStatus{State: a2atype.TaskStateCompleted},
+		Artifacts: []*a2atype.Artifact{{Parts: a2atype.ContentParts{a2atype.TextPart{Text: "Paris."}}}},
+		Metadata:  usageMetadata,
+	}}
+	rec := postChat(t, agent, `{
+		"model": "gpt-4o",
+		"messages": [
+			{"role": "system", "content": "Be brief."},
+			{"role": "user", "content": "Capital of France?"}
+		],
+		"temperature": 0.2,
+		"stop": "END"
+	}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+	var completion chatCompletion
+	if err := json.Unmarshal(rec.Body.Bytes(), &completion); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	if completion.ID != "chatcmpl-task-1" || completion.Model != "helper" || completion.Object != "chat.completion" {
+		t.Errorf("completion = %+v", completion)
+	}
+	choice := completion.Choices[0]
+	if *choice.Message.Content != "Paris." || *choice.FinishReason != finishStop {
+		t.Errorf("choice = %q, %q", *choice.Message.Content, *choice.FinishReason)
+	}
+	if *completion.Usage != (chatUsage{PromptTokens: 12, CompletionTokens: 5, TotalTokens: 17}) {
+		t.Errorf("usage = %+v", *completion.Usage)
+	}
+
+	msg := agent.params.Message
+	if len(msg.Parts) != 2 || !strings.Contains(msg.Parts[0].(a2atype.TextPart).Text, "system: Be brief.") {
+		t.Errorf("message parts = %+v, want the transcript then the question", msg.Parts)
+	}
+	generation := msg.Metadata[metadataKeyGeneration].(map[string]any)
+	if generation["temperature"] != 0.2 || generation["stop_sequences"].([]any)[0] != "END" {
+		t.Errorf("generation overrides = %v", generation)
+	}
+}
+
+func TestChatCompletions_ToolApproval(t *testing.T) {
+	confirmation := a2atype.DataPart{
+		Data: map[string]any{
+			"name": toolConfirmationCall,
+			"id":   "confirm-1",
+			"args": map[string]any{
+				"originalFunctionCall": map[string]any{"name": "delete_pod", "id": "call-1", "args": map[string]any{"pod": "web-0"}},
+			},
+		},
+		Metadata: map[string]any{"adk_type": "function_call"},
+	}
+	agent := &chatAgent{result: &a2atype.Task{
+		ID: "task-1",
+		Status: a2atype.TaskStatus{
+			State:   a2atype.TaskStateInputRequired,
+			Message: a2atype.NewMessage(a2atype.MessageRoleAgent, confirmation),
+		},
+	}}
+	rec := postChat(t, agent, `{"messages": [{"role": "user", "content": "Delete web-0"}]}`)
+	var completion chatCompletion
+	if err := json.Unmarshal(rec.Body.Bytes(), &completion); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	choice := completion.Choices[0]
+	if *choice.FinishReason != finishToolCalls || len(choice.Message.ToolCalls) != 1 {
+		t.Fatalf("choice = %+v, want one tool call", choice)
+	}
+	call := choice.Message.ToolCalls[0]
+	if call.ID != "call-1" || call.Function.Name != "delete_pod" || call.Function.Arguments != `{"pod":"web-0"}` {
+		t.Errorf("tool call = %+v, want the call awaiting approval", call)
+	}
+}
+
+func TestChatCompletions_InvalidRequests(t *testing.T) {
+	tests := map[string]string{
+		"no messages":         `{"messages": []}`,
+		"assistant last":      `{"messages": [{"role": "assistant", "content": "hi"}]}`,
+		"several choices":     `{"n": 2, "messages": [{"role": "user", "content": "hi"}]}`,
+		"invalid temperature": `{"temperature": 3, "messages": [{"role": "user", "content": "hi"}]}`,
+		"unsupported part":    `{"messages": [{"role": "user", "content": [{"type": "input_audio"}]}]}`,
+	}
+	for name, body := range tests {
+		t.Run(name, func(t *testing.T) {
+			rec := postChat(t, &chatAgent{}, body)
+			if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "invalid_request_error") {
+				t.Errorf("status = %d, body = %s; want an invalid_request_error", rec.Code, rec.Body)
+			}
+		})
+	}
+
+	rec := postChat(t, &chatAgent{}, `{"messages": [{"role": "user", "content": "`+strings.Repeat("x", 2<<10)+`"}]}`)
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("oversized request status = %d, want 413", rec.Code)
+	}
+}
+
+func TestChatCompletions_Stream(t *testing.T) {
+	partial := func(text string) a2atype.Event {
+		return &a2atype.TaskStatusUpdateEvent{
+			TaskID:   "task-1",
+			Status:   a2atype.TaskStatus{State: a2atype.TaskStateWorking, Message: a2atype.NewMessage(a2atype.MessageRoleAgent, a2atype.TextPart{Text: text})},
+			Metadata: map[string]any{metadataKeyPartial: true},
+		}
+	}
+	agent := &chatAgent{events: []a2atype.Event{
+		&a2atype.TaskStatusUpdateEvent{TaskID: "task-1", Status: a2atype.TaskStatus{State: a2atype.TaskStateSubmitted}},
+		partial("Par"),
+		partial("is."),
+		&a2atype.TaskArtifactUpdateEvent{TaskID: "task-1", Artifact: &a2atype.Artifact{Parts: a2atype.ContentParts{a2atype.TextPart{Text: "Paris."}}}},
+		&a2atype.TaskStatusUpdateEvent{TaskID: "task-1", Status: a2atype.TaskStatus{State: a2atype.TaskStateCompleted}, Final: true, Metadata: usageMetadata},
+	}}
+	rec := postChat(t, agent, `{"stream": true, "stream_options": {"include_usage": true}, "messages": [{"role": "user", "content": "Capital of France?"}]}`)
+	if ct := rec.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q, body = %s", ct, rec.Body)
+	}
+	if agent.params.Metadata[MetadataKeyExecutionMode] != ExecutionModeAttached {
+		t.Errorf("params metadata = %v, want an attached execution", agent.params.Metadata)
+	}
+
+	var (
+		text   strings.Builder
+		finish string
+		usage  *chatUsage
+		done   bool
+	)
+	for line := range strings.Lines(rec.Body.String()) {
+		data, ok := strings.CutPrefix(strings.TrimSpace(line), "data: ")
+		if !ok {
+			continue
+		}
+		if data == "[DONE]" {
+			done = true
+			continue
+		}
+		var chunk chatCompletion
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			t.Fatalf("invalid chunk %s: %v", data, err)
+		}
+		if chunk.ID != "chatcmpl-task-1" || chunk.Object != "chat.completion.chunk" {
+			t.Errorf("chunk = %+v", chunk)
+		}
+		if chunk.Usage != nil {
+			usage = chunk.Usage
+		}
+		for _, choice := range chunk.Choices {
+			if choice.Delta.Content != nil {
+				text.WriteString(*choice.Delta.Content)
+			}
+			if choice.FinishReason != nil {
+				finish = *choice.FinishReason
+			}
+		}
+	}
+	if text.String() != "Paris." || finish != finishStop || !done {
+		t.Errorf("streamed %q, finish %q, done %v; want the partial text once", text.String(), finish, done)
+	}
+	if usage == nil || usage.TotalTokens != 17 {
+		t.Errorf("usage = %+v, want the run's usage", usage)
+	}
+}
+
+func TestChatCompletions_StreamFailure(t *testing.T) {
+	agent := &chatAgent{events: []a2atype.Event{
+		&a2atype.TaskStatusUpdateEvent{TaskID: "task-1", Status: a2atype.TaskStatus{State: a2atype.TaskStateSubmitted}},
+		&a2atype.TaskStatusUpdateEvent{
+			TaskID: "task-1",
+			Status: a2atype.TaskStatus{State: a2atype.TaskStateFailed, Message: a2atype.NewMessage(a2atype.MessageRoleAgent, a2atype.TextPart{Text: "model unavailable"})},
+			Final:  true,
+		},
+	}}
+	rec := postChat(t, agent, `{"stream": true, "messages": [{"role": "user", "content": "hi"}]}`)
+	body := rec.Body.String()
+	if !strings.Contains(body, "model unavailable") || strings.Contains(body, "[DONE]") {
+		t.Errorf("body = %s, want an error event without [DONE]", body)
+	}
+}
+
+func TestOpenAICompatEndpoints(t *testing.T) {
+	mux := http.NewServeMux()
+	for pattern, handler := range openAICompatHandlers(&a2atype.AgentCard{Name: "helper"}, &chatAgent{}, RequestLimits{}) {
+		mux.Handle(pattern, handler)
+	}
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/models", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"id":"helper"`) {
+		t.Errorf("GET /v1/models = %d %s, want the agent listed", rec.Code, rec.Body)
+	}
+
+	t.Setenv(EnvOpenAICompat, "yes please")
+	if _, err := OpenAICompatEnabledFromEnv(); err == nil {
+		t.Error("OpenAICompatEnabledFromEnv() error = nil for an invalid value")
+	}
+}
@@ -0,0 +1,273 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	a2atype "github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+)
+
+// EnvOpenAPI serves the OpenAPI document at /openapi.json and a Swagger UI
+// at /docs. They describe the agent's endpoints, so they are off by default.
+const EnvOpenAPI = "KAGENT_A2A_OPENAPI"
+
+// swaggerUIVersion is the swagger-ui-dist release loaded by /docs.
+const swaggerUIVersion = "5.17.14"
+
+// OpenAPIEnabledFromEnv reports whether KAGENT_A2A_OPENAPI enables the
+// OpenAPI document and Swagger UI.
+func OpenAPIEnabledFromEnv() (bool, error) {
+	v := strings.TrimSpace(os.Getenv(EnvOpenAPI))
+	if v == "" {
+		return false, nil
+	}
+	enabled, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s %q", EnvOpenAPI, v)
+	}
+	return enabled, nil
+}
+
+// OpenAPIOperation describes one operation of an endpoint in the OpenAPI
+// document.
+type OpenAPIOperation struct {
+	Summary     string
+	Description string
+	// Responses maps status codes to their descriptions.
+	Responses map[string]string
+}
+
+// OpenAPIDescriber is implemented by handlers in ServerConfig.Handlers that
+// describe their operations, keyed by HTTP method. Other handlers are listed
+// as a GET without details.
+type OpenAPIDescriber interface {
+	OpenAPIOperations() map[string]OpenAPIOperation
+}
+
+// DescribeHandler attaches OpenAPI operations, keyed by HTTP method, to a
+// handler that does not describe itself.
+func DescribeHandler(h http.Handler, operations map[string]OpenAPIOperation) http.Handler {
+	return describedHandler{Handler: h, operations: operations}
+}
+
+type describedHandler struct {
+	http.Handler
+	operations map[string]OpenAPIOperation
+}
+
+func (h describedHandler) OpenAPIOperations() map[string]OpenAPIOperation {
+	return h.operations
+}
+
+// a2aMethods are the JSON-RPC methods served by the A2A endpoint.
+var a2aMethods = []string{
+	"message/send",
+	"message/stream",
+	"tasks/get",
+	"tasks/cancel",
+	"tasks/resubscribe",
+	"tasks/pushNotificationConfig/set",
+	"tasks/pushNotificationConfig/get",
+	"tasks/pushNotificationConfig/list",
+	"tasks/pushNotificationConfig/delete",
+	"agent/getAuthenticatedExtendedCard",
+}
+
+// openAPIDocument builds the OpenAPI 3.1 document for the server's
+// endpoints: the A2A JSON-RPC endpoint, the agent card, the health checks
+// and handlers.
+func openAPIDocument(card *a2atype.AgentCard, handlers map[string]http.Handler) map[string]any {
+	paths := map[string]any{
+		"/": map[string]any{
+			"post": map[string]any{
+				"summary":     "A2A JSON-RPC endpoint",
+				"description": "Serves the A2A protocol over JSON-RPC 2.0. message/stream and tasks/resubscribe answer with a text/event-stream of JSON-RPC responses.",
+				"operationId": "a2aJSONRPC",
+				"requestBody": map[string]any{
+					"required": true,
+					"content": map[string]any{
+						"application/json": map[string]any{"schema": map[string]any{"$ref": "#/components/schemas/JSONRPCRequest"}},
+					},
+				},
+				"responses": map[string]any{
+					"200": map[string]any{
+						"description": "JSON-RPC response, or a stream of them for streaming methods",
+						"content": map[string]any{
+							"application/json":  map[string]any{"schema": map[string]any{"$ref": "#/components/schemas/JSONRPCResponse"}},
+							"text/event-stream": map[string]any{"schema": map[string]any{"type": "string"}},
+						},
+					},
+					"400": response("Request rejected by the request limits"),
+					"413": response("Request body or message part too large"),
+				},
+			},
+		},
+		a2asrv.WellKnownAgentCardPath: map[string]any{
+			"get": map[string]any{
+				"summary":     "Agent card",
+				"operationId": "getAgentCard",
+				"responses": map[string]any{
+					"200": map[string]any{
+						"description": "The agent card",
+						"content":     map[string]any{"application/json": map[string]any{"schema": map[string]any{"type": "object"}}},
+					},
+					"304": response("The card matches If-None-Match"),
+				},
+			},
+		},
+		"/health":  healthPath("Liveness check", "getHealth"),
+		"/healthz": healthPath("Liveness check", "getHealthz"),
+		"/readyz": map[string]any{
+			"get": map[string]any{
+				"summary":     "Readiness check",
+				"operationId": "getReadyz",
+				"responses": map[string]any{
+					"200": response("Ready"),
+					"503": response("Not ready; the body lists the failed checks"),
+				},
+			},
+		},
+	}
+	for pattern, handler := range handlers {
+		method, path, ok := strings.Cut(pattern, " ")
+		if !ok {
+			method, path = "", pattern
+		}
+		var operations map[string]OpenAPIOperation
+		switch d, ok := handler.(OpenAPIDescriber); {
+		case ok:
+			operations = d.OpenAPIOperations()
+		case method != "":
+			operations = map[string]OpenAPIOperation{method: {}}
+		default:
+			operations = map[string]OpenAPIOperation{http.MethodGet: {}}
+		}
+		item := map[string]any{}
+		for m, op := range operations {
+			responses := map[string]any{}
+			for status, description := range op.Responses {
+				responses[status] = response(description)
+			}
+			if len(responses) == 0 {
+				responses["default"] = response("Response of the handler")
+			}
+			operation := map[string]any{"responses": responses}
+			if op.Summary != "" {
+				operation["summary"] = op.Summary
+			}
+			if op.Description != "" {
+				operation["description"] = op.Description
+			}
+			item[strings.ToLower(m)] = operation
+		}
+		paths[path] = item
+	}
+
+	return map[string]any{
+		"openapi": "3.1.0",
+		"info": map[string]any{
+			"title":       card.Name,
+			"description": card.Description,
+			"version":     card.Version,
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"schemas": map[string]any{
+				"JSONRPCRequest": map[string]any{
+					"type":     "object",
+					"required": []string{"jsonrpc", "method"},
+					"properties": map[string]any{
+						"jsonrpc": map[string]any{"const": "2.0"},
+						"id":      map[string]any{"type": []string{"string", "integer"}},
+						"method":  map[string]any{"type": "string", "enum": a2aMethods},
+						"params":  map[string]any{"type": "object"},
+					},
+				},
+				"JSONRPCResponse": map[string]any{
+					"type":     "object",
+					"required": []string{"jsonrpc"},
+					"properties": map[string]any{
+						"jsonrpc": map[string]any{"const": "2.0"},
+						"id":      map[string]any{"type": []string{"string", "integer", "null"}},
+						"result":  map[string]any{"type": "object"},
+						"error": map[string]any{
+							"type": "object",
+							"properties": map[string]any{
+								"code":    map[string]any{"type": "integer"},
+								"message": map[string]any{"type": "string"},
+								"data":    map[string]any{},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func response(description string) map[string]any {
+	return map[string]any{"description": description}
+}
+
+func healthPath(summary, operationID string) map[string]any {
+	return map[string]any{
+		"get": map[string]any{
+			"summary":     summary,
+			"operationId": operationID,
+			"responses":   map[string]any{"200": response("OK")},
+		},
+	}
+}
+
+// registerOpenAPIEndpoints serves the OpenAPI document at /openapi.json and
+// a Swagger UI for it at /docs.
+func registerOpenAPIEndpoints(mux *http.ServeMux, card *a2atype.AgentCard, handlers map[string]http.Handler) error {
+	doc, err := json.Marshal(openAPIDocument(card, handlers))
+	if err != nil {
+		return fmt.Errorf("failed to encode OpenAPI document: %w", err)
+	}
+	mux.HandleFunc("GET /openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(doc)
+	})
+	mux.HandleFunc("GET /docs", func(w http.ResponseWriter, r *http.Request) {
+		setSwaggerUIPolicy(w.Header())
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = fmt.Fprintf(w, swaggerUIPage, swaggerUIVersion, swaggerUIVersion)
+	})
+	mux.HandleFunc("GET /docs/init.js", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/javascript; charset=utf-8")
+		_, _ = w.Write([]byte(swaggerUIInit))
+	})
+	return nil
+}
+
+// setSwaggerUIPolicy relaxes the default content security policy just
+// enough for the Swagger UI assets.
+func setSwaggerUIPolicy(h http.Header) {
+	h.Set("Content-Security-Policy", "default-src 'none'; script-src 'self' https://unpkg.com; "+
+		"style-src https://unpkg.com; img-src data: https://unpkg.com; connect-src 'self'; frame-ancestors 'none'")
+}
+
+const swaggerUIPage = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Agent API</title>
+<link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@%s/swagger-ui.css">
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="https://unpkg.com/swagger-ui-dist@%s/swagger-ui-bundle.js"></script>
+<script src="docs/init.js"></script>
+</body>
+</html>
+`
+
+const swaggerUIInit = `window.ui = SwaggerUIBundle({url: "openapi.json", dom_id: "#swagger-ui"});
+`
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	a2atype "github.com/a2aproject/a2a-go/a2a"
+)
+
+func TestOpenAPIEnabledFromEnv(t *testing.T) {
+	if enabled, err := OpenAPIEnabledFromEnv(); err != nil || enabled {
+		t.Errorf("OpenAPIEnabledFromEnv() = %v, %v; want disabled by default", enabled, err)
+	}
+	t.Setenv(EnvOpenAPI, "true")
+	if enabled, err := OpenAPIEnabledFromEnv(); err != nil || !enabled {
+		t.Errorf("OpenAPIEnabledFromEnv() = %v, %v; want enabled", enabled, err)
+	}
+	t.Setenv(EnvOpenAPI, "sure")
+	if _, err := OpenAPIEnabledFromEnv(); err == nil {
+		t.Error("OpenAPIEnabledFromEnv() error = nil for an invalid value")
+	}
+}
+
+func TestOpenAPIEndpoints(t *testing.T) {
+	noop := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+	handlers := map[string]http.Handler{
+		"/metrics": noop,
+		"/admin/sweep": DescribeHandler(noop, map[string]OpenAPIOperation{
+			http.MethodPost: {Summary: "Sweep", Responses: map[string]string{"200": "Swept"}},
+		}),
+		"DELETE /admin/cache": noop,
+	}
+	mux := http.NewServeMux()
+	card := &a2atype.AgentCard{Name: "test-agent", Version: "1.2.3"}
+	if err := registerOpenAPIEndpoints(mux, card, handlers); err != nil {
+		t.Fatalf("registerOpenAPIEndpoints() error = %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /openapi.json = %d", rec.Code)
+	}
+	var doc struct {
+		OpenAPI string `json:"openapi"`
+		Info    struct {
+			Title   string `json:"title"`
+			Version string `json:"version"`
+		} `json:"info"`
+		Paths map[string]map[string]struct {
+			Summary string `json:"summary"`
+		} `json:"paths"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid OpenAPI document: %v", err)
+	}
+	if doc.OpenAPI != "3.1.0" || doc.Info.Title != "test-agent" || doc.Info.Version != "1.2.3" {
+		t.Errorf("document header = %s %+v", doc.OpenAPI, doc.Info)
+	}
+	for path, method := range map[string]string{
+		"/":                            "post",
+		"/.well-known/agent-card.json": "get",
+		"/readyz":                      "get",
+		"/metrics":                     "get",
+		"/admin/sweep":                 "post",
+		"/admin/cache":                 "delete",
+	} {
+		if _, ok := doc.Paths[path][method]; !ok {
+			t.Errorf("document has no %s %s", method, path)
+		}
+	}
+	if got := doc.Paths["/admin/sweep"]["post"].Summary; got != "Sweep" {
+		t.Errorf("described handler summary = %q", got)
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "swagger-ui-bundle.js") {
+		t.Errorf("GET /docs = %d %q", rec.Code, rec.Body.String())
+	}
+	if csp := rec.Header().Get("Content-Security-Policy"); !strings.Contains(csp, "script-src 'self' https://unpkg.com") {
+		t.Errorf("GET /docs CSP = %q", csp)
+	}
+}
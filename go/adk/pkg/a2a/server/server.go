@@ -3,6 +3,7 @@ package server
 import (
 	"context"
 	"fmt"
+	"maps"
 	"net"
 	"net/http"
 	"os"
@@ -13,6 +14,8 @@ import (
 	a2atype "github.com/a2aproject/a2a-go/a2a"
 	"github.com/a2aproject/a2a-go/a2asrv"
 	"github.com/go-logr/logr"
+	"github.com/kagent-dev/kagent/go/adk/pkg/tlsconfig"
+	"github.com/kagent-dev/kagent/go/api/httpsecurity"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
@@ -21,6 +24,29 @@ type ServerConfig struct {
 	Host            string
 	Port            string
 	ShutdownTimeout time.Duration
+	// Handlers are additional endpoints mounted next to the A2A handler,
+	// keyed by mux pattern.
+	Handlers map[string]http.Handler
+	// Limits bounds the requests accepted by the A2A handler.
+	Limits RequestLimits
+	// Stream configures SSE keep-alives and disconnect handling.
+	Stream StreamOptions
+	// Security configures CORS and the security headers added to every
+	// response.
+	Security httpsecurity.Config
+	// TLS enables HTTPS and optional client-certificate verification.
+	TLS tlsconfig.Config
+	// GRPC serves the A2A gRPC service on the same port, over HTTP/2 (h2c
+	// without TLS), and advertises it on the agent card.
+	GRPC bool
+	// OpenAPI serves an OpenAPI document of the server's endpoints at
+	// /openapi.json and a Swagger UI at /docs.
+	OpenAPI bool
+	// OpenAICompat serves an OpenAI-compatible chat completions API at
+	// /v1/chat/completions and /v1/models.
+	OpenAICompat bool
+	// ReadinessChecks are run by the /readyz endpoint.
+	ReadinessChecks []ReadinessCheck
 }
 
 // A2AServer wraps the A2A server with health endpoints and graceful shutdown.
@@ -33,24 +59,51 @@ type A2AServer struct {
 
 // NewA2AServer creates a new A2A server using a2asrv.
 func NewA2AServer(agentCard a2atype.AgentCard, executor a2asrv.AgentExecutor, logger logr.Logger, config ServerConfig, handlerOpts ...a2asrv.RequestHandlerOption) (*A2AServer, error) {
-	requestHandler := a2asrv.NewHandler(executor, handlerOpts...)
-	jsonrpcHandler := a2asrv.NewJSONRPCHandler(requestHandler)
+	requestHandler := executionModeHandler{
+		RequestHandler: a2asrv.NewHandler(executor, handlerOpts...),
+		attached:       config.Stream.CancelOnDisconnect,
+	}
+	jsonrpcHandler := a2asrv.NewJSONRPCHandler(requestHandler, a2asrv.WithKeepAlive(config.Stream.KeepAlive))
+
+	if config.GRPC {
+		advertiseGRPC(&agentCard)
+	}
+
+	cardHandler, err := newAgentCardHandler(&agentCard)
+	if err != nil {
+		return nil, err
+	}
+
+	handlers := config.Handlers
+	if config.OpenAICompat {
+		handlers = make(map[string]http.Handler, len(config.Handlers)+2)
+		maps.Copy(handlers, config.Handlers)
+		maps.Copy(handlers, openAICompatHandlers(&agentCard, requestHandler, config.Limits))
+	}
 
 	mux := http.NewServeMux()
-	RegisterHealthEndpoints(mux)
-	mux.Handle(a2asrv.WellKnownAgentCardPath, a2asrv.NewStaticAgentCardHandler(&agentCard))
-	mux.Handle("/", jsonrpcHandler)
+	RegisterHealthEndpoints(mux, config.ReadinessChecks...)
+	mux.Handle(a2asrv.WellKnownAgentCardPath, cardHandler)
+	for pattern, handler := range handlers {
+		mux.Handle(pattern, handler)
+	}
+	if config.OpenAPI {
+		if err := registerOpenAPIEndpoints(mux, &agentCard, handlers); err != nil {
+			return nil, err
+		}
+	}
+	mux.Handle("/", config.Limits.Middleware(jsonrpcHandler))
 	// Wrap the whole server mux to enable trace context extraction and an inbound
 	// HTTP server span for each request.
 	instrumentedHandler := otelhttp.NewHandler(
-		mux,
+		httpsecurity.Middleware(config.Security, mux),
 		"a2a-server",
 		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
 			return r.Method + " " + r.URL.Path
 		}),
 		otelhttp.WithFilter(func(r *http.Request) bool {
 			switch r.URL.Path {
-			case "/health", "/healthz", a2asrv.WellKnownAgentCardPath:
+			case "/health", "/healthz", "/readyz", "/metrics", a2asrv.WellKnownAgentCardPath:
 				return false
 			default:
 				return true
@@ -63,23 +116,48 @@ func NewA2AServer(agentCard a2atype.AgentCard, executor a2asrv.AgentExecutor, lo
 		addr = net.JoinHostPort(config.Host, config.Port)
 	}
 
+	httpServer := &http.Server{
+		Addr:    addr,
+		Handler: instrumentedHandler,
+	}
+	if config.GRPC {
+		httpServer.Handler = withGRPC(newGRPCServer(requestHandler, config.Limits), instrumentedHandler)
+		protocols := new(http.Protocols)
+		protocols.SetHTTP1(true)
+		protocols.SetHTTP2(true)
+		protocols.SetUnencryptedHTTP2(true)
+		httpServer.Protocols = protocols
+	}
+	if config.TLS.Enabled() {
+		tlsConfig, err := config.TLS.Server()
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure TLS: %w", err)
+		}
+		httpServer.TLSConfig = tlsConfig
+	}
+
 	return &A2AServer{
-		httpServer: &http.Server{
-			Addr:    addr,
-			Handler: instrumentedHandler,
-		},
-		logger: logger,
-		config: config,
+		httpServer: httpServer,
+		logger:     logger,
+		config:     config,
 	}, nil
 }
 
 // Start initializes and starts the HTTP server.
 func (s *A2AServer) Start() error {
-	s.logger.Info("Starting Go ADK server!", "addr", s.httpServer.Addr)
+	s.logger.Info("Starting Go ADK server!", "addr", s.httpServer.Addr,
+		"tls", s.config.TLS.Enabled(), "mtls", s.config.TLS.MutualTLS(), "grpc", s.config.GRPC)
 
 	s.listenErr = make(chan error, 1)
 	go func() {
-		if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		var err error
+		if s.httpServer.TLSConfig != nil {
+			// The certificate comes from TLSConfig so that it can be reloaded.
+			err = s.httpServer.ListenAndServeTLS("", "")
+		} else {
+			err = s.httpServer.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			s.listenErr <- err
 		}
 	}()
@@ -0,0 +1,146 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	a2atype "github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+)
+
+// Environment variables configuring StreamOptions.
+const (
+	EnvKeepAlive          = "KAGENT_A2A_KEEPALIVE"
+	EnvCancelOnDisconnect = "KAGENT_A2A_CANCEL_ON_DISCONNECT"
+)
+
+// DefaultKeepAlive is the SSE keep-alive interval used when
+// KAGENT_A2A_KEEPALIVE is not set. It stays below the common 30s-60s idle
+// timeouts of proxies and load balancers.
+const DefaultKeepAlive = 15 * time.Second
+
+// StreamOptions configure the SSE streams served for message/stream and
+// tasks/resubscribe.
+type StreamOptions struct {
+	// KeepAlive is the interval of the ": keep-alive" comments written while
+	// a stream is quiet, e.g. waiting for a tool approval. Zero disables them.
+	KeepAlive time.Duration
+	// CancelOnDisconnect cancels the task when the client of a message/stream
+	// disconnects before it ends, unless the request asks for a detached
+	// execution. Tasks waiting for input or authorization are left alone so
+	// they can be resumed.
+	CancelOnDisconnect bool
+}
+
+// StreamOptionsFromEnv reads StreamOptions from the KAGENT_A2A_* env vars.
+func StreamOptionsFromEnv() (StreamOptions, error) {
+	opts := StreamOptions{KeepAlive: DefaultKeepAlive}
+	var err error
+	if v := strings.TrimSpace(os.Getenv(EnvKeepAlive)); v != "" {
+		if opts.KeepAlive, err = time.ParseDuration(v); err != nil || opts.KeepAlive < 0 {
+			return StreamOptions{}, fmt.Errorf("invalid %s %q", EnvKeepAlive, v)
+		}
+	}
+	if v := strings.TrimSpace(os.Getenv(EnvCancelOnDisconnect)); v != "" {
+		if opts.CancelOnDisconnect, err = strconv.ParseBool(v); err != nil {
+			return StreamOptions{}, fmt.Errorf("invalid %s %q", EnvCancelOnDisconnect, v)
+		}
+	}
+	return opts, nil
+}
+
+// MetadataKeyExecutionMode selects, per message/stream request, what
+// happens to the task when the client disconnects. It is read from the
+// request params metadata, then from the message metadata, and overrides
+// StreamOptions.CancelOnDisconnect.
+const MetadataKeyExecutionMode = "kagent_execution_mode"
+
+// Values of MetadataKeyExecutionMode.
+const (
+	// ExecutionModeAttached cancels the task when the client disconnects.
+	ExecutionModeAttached = "attached"
+	// ExecutionModeDetached keeps the task running after the client
+	// disconnects; its result can be fetched with tasks/get or followed
+	// again with tasks/resubscribe.
+	ExecutionModeDetached = "detached"
+)
+
+// executionModeHandler cancels the task behind a message/stream whose client
+// goes away, when the execution is attached. The a2asrv handler runs
+// executions detached from the request, so without it a task keeps running
+// after the client has disconnected.
+type executionModeHandler struct {
+	a2asrv.RequestHandler
+	// attached is the mode of requests that do not set one.
+	attached bool
+}
+
+// OnSendMessageStream implements a2asrv.RequestHandler.
+func (h executionModeHandler) OnSendMessageStream(ctx context.Context, params *a2atype.MessageSendParams) iter.Seq2[a2atype.Event, error] {
+	attached, err := h.isAttached(params)
+	if err != nil {
+		return func(yield func(a2atype.Event, error) bool) { yield(nil, err) }
+	}
+	if !attached {
+		return h.RequestHandler.OnSendMessageStream(ctx, params)
+	}
+	return func(yield func(a2atype.Event, error) bool) {
+		var (
+			taskID a2atype.TaskID
+			state  a2atype.TaskState
+		)
+		for event, err := range h.RequestHandler.OnSendMessageStream(ctx, params) {
+			switch e := event.(type) {
+			case *a2atype.Task:
+				taskID, state = e.ID, e.Status.State
+			case *a2atype.TaskStatusUpdateEvent:
+				taskID, state = e.TaskID, e.Status.State
+			}
+			if !yield(event, err) {
+				break
+			}
+		}
+		if ctx.Err() == nil || taskID == "" || !cancelable(state) {
+			return
+		}
+		// The request context is done; cancel in one that is not.
+		_, _ = h.OnCancelTask(context.WithoutCancel(ctx), &a2atype.TaskIDParams{ID: taskID})
+	}
+}
+
+// isAttached returns the execution mode requested by params, or the default.
+func (h executionModeHandler) isAttached(params *a2atype.MessageSendParams) (bool, error) {
+	if params == nil {
+		return h.attached, nil
+	}
+	mode, ok := params.Metadata[MetadataKeyExecutionMode]
+	if !ok && params.Message != nil {
+		mode, ok = params.Message.Metadata[MetadataKeyExecutionMode]
+	}
+	if !ok {
+		return h.attached, nil
+	}
+	switch mode {
+	case ExecutionModeAttached:
+		return true, nil
+	case ExecutionModeDetached:
+		return false, nil
+	}
+	return false, fmt.Errorf("%w: %s must be %q or %q, got %v",
+		a2atype.ErrInvalidParams, MetadataKeyExecutionMode, ExecutionModeAttached, ExecutionModeDetached, mode)
+}
+
+// cancelable reports whether a task in state is still running, rather than
+// finished or paused for the user.
+func cancelable(state a2atype.TaskState) bool {
+	switch state {
+	case a2atype.TaskStateInputRequired, a2atype.TaskStateAuthRequired:
+		return false
+	}
+	return !state.Terminal()
+}
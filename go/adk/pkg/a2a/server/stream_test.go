@@ -0,0 +1,155 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"testing"
+	"time"
+
+	a2atype "github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+)
+
+func TestStreamOptionsFromEnv(t *testing.T) {
+	opts, err := StreamOptionsFromEnv()
+	if err != nil {
+		t.Fatalf("StreamOptionsFromEnv() error = %v", err)
+	}
+	if opts != (StreamOptions{KeepAlive: DefaultKeepAlive}) {
+		t.Errorf("StreamOptionsFromEnv() = %+v, want defaults", opts)
+	}
+
+	t.Setenv(EnvKeepAlive, "5s")
+	t.Setenv(EnvCancelOnDisconnect, "true")
+	opts, err = StreamOptionsFromEnv()
+	if err != nil {
+		t.Fatalf("StreamOptionsFromEnv() error = %v", err)
+	}
+	if opts != (StreamOptions{KeepAlive: 5 * time.Second, CancelOnDisconnect: true}) {
+		t.Errorf("StreamOptionsFromEnv() = %+v", opts)
+	}
+
+	for env, value := range map[string]string{EnvKeepAlive: "-1s", EnvCancelOnDisconnect: "maybe"} {
+		t.Run(env, func(t *testing.T) {
+			t.Setenv(env, value)
+			if _, err := StreamOptionsFromEnv(); err == nil {
+				t.Errorf("StreamOptionsFromEnv() with %s=%q error = nil", env, value)
+			}
+		})
+	}
+}
+
+// streamingHandler streams a fixed sequence of task states and records
+// cancellations.
+type streamingHandler struct {
+	a2asrv.RequestHandler
+	states   []a2atype.TaskState
+	canceled []a2atype.TaskID
+}
+
+func (h *streamingHandler) OnSendMessageStream(context.Context, *a2atype.MessageSendParams) iter.Seq2[a2atype.Event, error] {
+	return func(yield func(a2atype.Event, error) bool) {
+		for _, state := range h.states {
+			event := &a2atype.TaskStatusUpdateEvent{TaskID: "task-1", Status: a2atype.TaskStatus{State: state}}
+			if !yield(event, nil) {
+				return
+			}
+		}
+	}
+}
+
+func (h *streamingHandler) OnCancelTask(_ context.Context, params *a2atype.TaskIDParams) (*a2atype.Task, error) {
+	h.canceled = append(h.canceled, params.ID)
+	return nil, nil
+}
+
+func TestExecutionModeHandler(t *testing.T) {
+	working := []a2atype.TaskState{a2atype.TaskStateSubmitted, a2atype.TaskStateWorking}
+	tests := []struct {
+		name       string
+		attached   bool
+		metadata   map[string]any
+		states     []a2atype.TaskState
+		disconnect bool
+		wantCancel bool
+	}{
+		{
+			name:       "disconnect while working",
+			attached:   true,
+			states:     working,
+			disconnect: true,
+			wantCancel: true,
+		},
+		{
+			name:       "disconnect while waiting for approval",
+			attached:   true,
+			states:     []a2atype.TaskState{a2atype.TaskStateWorking, a2atype.TaskStateInputRequired},
+			disconnect: true,
+		},
+		{
+			name:       "disconnect after completion",
+			attached:   true,
+			states:     []a2atype.TaskState{a2atype.TaskStateWorking, a2atype.TaskStateCompleted},
+			disconnect: true,
+		},
+		{
+			name:     "stream read to the end",
+			attached: true,
+			states:   []a2atype.TaskState{a2atype.TaskStateWorking},
+		},
+		{
+			name:       "detached by default",
+			states:     working,
+			disconnect: true,
+		},
+		{
+			name:       "attached by request",
+			metadata:   map[string]any{MetadataKeyExecutionMode: ExecutionModeAttached},
+			states:     working,
+			disconnect: true,
+			wantCancel: true,
+		},
+		{
+			name:       "detached by request",
+			attached:   true,
+			metadata:   map[string]any{MetadataKeyExecutionMode: ExecutionModeDetached},
+			states:     working,
+			disconnect: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inner := &streamingHandler{states: tt.states}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			handler := executionModeHandler{RequestHandler: inner, attached: tt.attached}
+			params := &a2atype.MessageSendParams{Message: &a2atype.Message{Metadata: tt.metadata}}
+			seen := 0
+			for range handler.OnSendMessageStream(ctx, params) {
+				seen++
+				if tt.disconnect && seen == len(tt.states) {
+					cancel()
+					break
+				}
+			}
+
+			if gotCancel := len(inner.canceled) > 0; gotCancel != tt.wantCancel {
+				t.Errorf("canceled = %v, want cancel %v", inner.canceled, tt.wantCancel)
+			}
+		})
+	}
+}
+
+func TestExecutionModeHandler_InvalidMode(t *testing.T) {
+	handler := executionModeHandler{RequestHandler: &streamingHandler{states: []a2atype.TaskState{a2atype.TaskStateWorking}}}
+	params := &a2atype.MessageSendParams{Metadata: map[string]any{MetadataKeyExecutionMode: "sometimes"}}
+	var errs []error
+	for _, err := range handler.OnSendMessageStream(context.Background(), params) {
+		errs = append(errs, err)
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], a2atype.ErrInvalidParams) {
+		t.Errorf("OnSendMessageStream() errors = %v, want one ErrInvalidParams", errs)
+	}
+}
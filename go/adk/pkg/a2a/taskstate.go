@@ -0,0 +1,69 @@
+package a2a
+
+import (
+	"path"
+
+	a2atype "github.com/a2aproject/a2a-go/a2a"
+	"github.com/kagent-dev/kagent/go/api/adk"
+)
+
+// RequestCredentialFunctionName is the function call ADK emits when a tool
+// needs end-user credentials.
+const RequestCredentialFunctionName = "adk_request_credential"
+
+// RequestCredentialTaskStateRule reports credential requests as
+// auth-required. It is not applied by default: clients that only resume
+// input-required tasks must opt in by adding it to AgentConfig.
+var RequestCredentialTaskStateRule = adk.TaskStateRule{
+	Tool:  RequestCredentialFunctionName,
+	State: string(a2atype.TaskStateAuthRequired),
+}
+
+// taskStateRules decide the task state reported when a run stops on
+// long-running tool calls.
+type taskStateRules []adk.TaskStateRule
+
+func newTaskStateRules(configured []adk.TaskStateRule) taskStateRules {
+	return taskStateRules(configured)
+}
+
+// stateFor returns the state of the first rule matching a long-running
+// function call in parts, or input-required when none matches.
+func (r taskStateRules) stateFor(parts a2atype.ContentParts) a2atype.TaskState {
+	for _, part := range parts {
+		dp := asDataPart(part)
+		if dp == nil {
+			continue
+		}
+		partType, _ := ReadMetadataValue(dp.Metadata, A2ADataPartMetadataTypeKey)
+		if partType != A2ADataPartMetadataTypeFunctionCall {
+			continue
+		}
+		name, _ := dp.Data[PartKeyName].(string)
+		for _, rule := range r {
+			if matchesRule(rule, name, dp.Metadata) {
+				return a2atype.TaskState(rule.State)
+			}
+		}
+	}
+	return a2atype.TaskStateInputRequired
+}
+
+func matchesRule(rule adk.TaskStateRule, name string, metadata map[string]any) bool {
+	if rule.Tool == "" && rule.MetadataKey == "" {
+		return false
+	}
+	if rule.Tool != "" {
+		if ok, err := path.Match(rule.Tool, name); err != nil || !ok {
+			return false
+		}
+	}
+	if rule.MetadataKey != "" {
+		if _, ok := metadata[rule.MetadataKey]; !ok {
+			if _, ok := ReadMetadataValue(metadata, rule.MetadataKey); !ok {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,78 @@
+package a2a
+
+import (
+	"testing"
+
+	a2atype "github.com/a2aproject/a2a-go/a2a"
+	"github.com/kagent-dev/kagent/go/api/adk"
+)
+
+func functionCallPart(name string, metadata map[string]any) a2atype.Part {
+	meta := map[string]any{
+		GetKAgentMetadataKey(A2ADataPartMetadataTypeKey):          A2ADataPartMetadataTypeFunctionCall,
+		GetKAgentMetadataKey(A2ADataPartMetadataIsLongRunningKey): true,
+	}
+	for k, v := range metadata {
+		meta[k] = v
+	}
+	return &a2atype.DataPart{Data: map[string]any{PartKeyName: name}, Metadata: meta}
+}
+
+func TestTaskStateRules_StateFor(t *testing.T) {
+	rules := newTaskStateRules([]adk.TaskStateRule{
+		{Tool: "oauth_*", State: string(a2atype.TaskStateAuthRequired)},
+		{MetadataKey: "needs_login", State: string(a2atype.TaskStateAuthRequired)},
+		{Tool: RequestCredentialFunctionName, State: string(a2atype.TaskStateInputRequired)},
+	})
+
+	tests := []struct {
+		name  string
+		parts a2atype.ContentParts
+		want  a2atype.TaskState
+	}{
+		{
+			name:  "unmatched tool",
+			parts: a2atype.ContentParts{functionCallPart("adk_request_confirmation", nil)},
+			want:  a2atype.TaskStateInputRequired,
+		},
+		{
+			name:  "tool glob",
+			parts: a2atype.ContentParts{functionCallPart("oauth_github", nil)},
+			want:  a2atype.TaskStateAuthRequired,
+		},
+		{
+			name:  "prefixed metadata key",
+			parts: a2atype.ContentParts{functionCallPart("lookup", map[string]any{"kagent_needs_login": true})},
+			want:  a2atype.TaskStateAuthRequired,
+		},
+		{
+			name:  "configured rule overrides default",
+			parts: a2atype.ContentParts{functionCallPart(RequestCredentialFunctionName, nil)},
+			want:  a2atype.TaskStateInputRequired,
+		},
+		{
+			name:  "text parts are ignored",
+			parts: a2atype.ContentParts{a2atype.TextPart{Text: "oauth_github"}},
+			want:  a2atype.TaskStateInputRequired,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rules.stateFor(tt.parts); got != tt.want {
+				t.Errorf("stateFor() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskStateRules_Defaults(t *testing.T) {
+	parts := a2atype.ContentParts{functionCallPart(RequestCredentialFunctionName, nil)}
+	if got := newTaskStateRules(nil).stateFor(parts); got != a2atype.TaskStateInputRequired {
+		t.Errorf("stateFor(%s) = %q, want %q", RequestCredentialFunctionName, got, a2atype.TaskStateInputRequired)
+	}
+
+	optedIn := newTaskStateRules([]adk.TaskStateRule{RequestCredentialTaskStateRule})
+	if got := optedIn.stateFor(parts); got != a2atype.TaskStateAuthRequired {
+		t.Errorf("stateFor(%s) with opt-in rule = %q, want %q", RequestCredentialFunctionName, got, a2atype.TaskStateAuthRequired)
+	}
+}
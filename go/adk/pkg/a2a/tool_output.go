@@ -0,0 +1,60 @@
+package a2a
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"google.golang.org/adk/tool"
+)
+
+// A2ADataPartMetadataTypeToolOutputDelta marks a DataPart carrying output a
+// tool produced while it is still running. The part's data holds the
+// function call id and name (PartKeyID, PartKeyName) and the new output
+// under PartKeyDelta. Deltas are progress only: the model sees the tool's
+// final result, and clients should replace the accumulated deltas with the
+// function response once it arrives.
+const A2ADataPartMetadataTypeToolOutputDelta = "tool_output_delta"
+
+// PartKeyDelta is the DataPart map key of incremental tool output.
+const PartKeyDelta = "delta"
+
+// ToolOutputSink receives output of the tool call callID while it runs.
+type ToolOutputSink func(callID, toolName, chunk string)
+
+type toolOutputSinkKey struct{}
+
+// WithToolOutputSink returns a copy of ctx whose tool calls forward their
+// incremental output to sink.
+func WithToolOutputSink(ctx context.Context, sink ToolOutputSink) context.Context {
+	return context.WithValue(ctx, toolOutputSinkKey{}, sink)
+}
+
+// ToolOutputWriter returns a writer forwarding every write to the run's tool
+// output sink as output of the current tool call, or nil when the run has
+// no sink. The writer is safe for concurrent use, so a command's stdout and
+// stderr can share it.
+func ToolOutputWriter(ctx tool.Context, toolName string) io.Writer {
+	sink, _ := ctx.Value(toolOutputSinkKey{}).(ToolOutputSink)
+	if sink == nil {
+		return nil
+	}
+	return &toolOutputWriter{sink: sink, callID: ctx.FunctionCallID(), toolName: toolName}
+}
+
+type toolOutputWriter struct {
+	mu       sync.Mutex
+	sink     ToolOutputSink
+	callID   string
+	toolName string
+}
+
+func (w *toolOutputWriter) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.sink(w.callID, w.toolName, string(p))
+	return len(p), nil
+}
@@ -4,22 +4,29 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"os"
 	"strings"
 
 	"github.com/go-logr/logr"
 	"github.com/kagent-dev/kagent/go/adk/pkg/mcp"
 	"github.com/kagent-dev/kagent/go/adk/pkg/models"
+	"github.com/kagent-dev/kagent/go/adk/pkg/policy"
+	"github.com/kagent-dev/kagent/go/adk/pkg/prompts"
 	"github.com/kagent-dev/kagent/go/adk/pkg/sts"
+	"github.com/kagent-dev/kagent/go/adk/pkg/tlsconfig"
+	"github.com/kagent-dev/kagent/go/adk/pkg/toolargs"
 	"github.com/kagent-dev/kagent/go/adk/pkg/tools"
 	"github.com/kagent-dev/kagent/go/api/adk"
 	"google.golang.org/adk/agent"
 	"google.golang.org/adk/agent/llmagent"
 	adkmodel "google.golang.org/adk/model"
 	adkgemini "google.golang.org/adk/model/gemini"
+	adkplugin "google.golang.org/adk/plugin"
 	"google.golang.org/adk/tool"
 	"google.golang.org/adk/tool/loadmemorytool"
 	"google.golang.org/adk/tool/preloadmemorytool"
+	"google.golang.org/adk/util/instructionutil"
 	"google.golang.org/genai"
 )
 
@@ -30,11 +37,37 @@ const (
 	DefaultOllamaModel    = "llama3.2"
 )
 
+// Base URLs the provider SDKs use by default; keys of a key pool with their
+// own base URL replace them.
+const (
+	defaultOpenAIBaseURL    = "https://api.openai.com/v1/"
+	defaultAnthropicBaseURL = "https://api.anthropic.com/"
+	defaultGeminiBaseURL    = "https://generativelanguage.googleapis.com/"
+)
+
+// remoteAgentHTTPClient returns the HTTP client for calls to remote agents.
+// With mutual TLS configured (KAGENT_TLS_*), it presents the agent's
+// certificate and verifies peers against the client CA; otherwise it returns
+// nil and the default client is used.
+func remoteAgentHTTPClient() (*http.Client, error) {
+	cfg, err := tlsconfig.FromEnv()
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig, err := cfg.Client()
+	if err != nil || tlsConfig == nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	return &http.Client{Transport: transport}, nil
+}
+
 // CreateGoogleADKAgent creates a Google ADK agent from AgentConfig.
 // agentName is used as the ADK agent identity (appears in event Author field).
 // extraTools are appended to the agent's tool list (e.g. save_memory).
 func CreateGoogleADKAgent(ctx context.Context, agentConfig *adk.AgentConfig, agentName string, extraTools ...tool.Tool) (agent.Agent, error) {
-	a, _, err := CreateGoogleADKAgentWithSubagentSessionIDs(ctx, agentConfig, agentName, nil, extraTools...)
+	a, _, err := CreateGoogleADKAgentWithSubagentSessionIDs(ctx, agentConfig, agentName, nil, nil, nil, extraTools...)
 	return a, err
 }
 
@@ -43,7 +76,11 @@ func CreateGoogleADKAgent(ctx context.Context, agentConfig *adk.AgentConfig, age
 // outbound A2A events). Callers that only need the agent can use
 // CreateGoogleADKAgent.
 // Optional stsPlugin can be provided for token propagation to MCP tools.
-func CreateGoogleADKAgentWithSubagentSessionIDs(ctx context.Context, agentConfig *adk.AgentConfig, agentName string, stsPlugin *sts.TokenPropagationPlugin, extraTools ...tool.Tool) (agent.Agent, map[string]string, error) {
+// plugins are the ADK plugins the runner registers; retried tool calls run
+// through their tool callbacks like the first attempt does. extraToolsets
+// are listed after the configured MCP servers, such as the servers
+// registered at runtime (mcp.DynamicToolsets).
+func CreateGoogleADKAgentWithSubagentSessionIDs(ctx context.Context, agentConfig *adk.AgentConfig, agentName string, stsPlugin *sts.TokenPropagationPlugin, plugins []*adkplugin.Plugin, extraToolsets []tool.Toolset, extraTools ...tool.Tool) (agent.Agent, map[string]string, error) {
 	log := logr.FromContextOrDiscard(ctx)
 
 	if agentConfig == nil {
@@ -56,15 +93,23 @@ func CreateGoogleADKAgentWithSubagentSessionIDs(ctx context.Context, agentConfig
 		dynamicHeaderProvider = stsPlugin.HeaderProvider
 	}
 	toolsets := mcp.CreateToolsets(ctx, agentConfig.HttpTools, agentConfig.SseTools, propagateToken, dynamicHeaderProvider)
+	toolsets = append(toolsets, extraToolsets...)
 	subagentSessionIDs := make(map[string]string)
 
 	var remoteAgentTools []tool.Tool
+	var remoteAgentClient *http.Client
+	if len(agentConfig.RemoteAgents) > 0 {
+		var err error
+		if remoteAgentClient, err = remoteAgentHTTPClient(); err != nil {
+			return nil, nil, err
+		}
+	}
 	for _, remoteAgent := range agentConfig.RemoteAgents {
 		if remoteAgent.Url == "" {
 			log.Info("Skipping remote agent with empty URL", "name", remoteAgent.Name)
 			continue
 		}
-		remoteTool, sessionID, err := tools.NewKAgentRemoteA2ATool(remoteAgent.Name, remoteAgent.Description, remoteAgent.Url, nil, remoteAgent.Headers, propagateToken)
+		remoteTool, sessionID, err := tools.NewKAgentRemoteA2ATool(remoteAgent.Name, remoteAgent.Description, remoteAgent.Url, remoteAgentClient, remoteAgent.Headers, propagateToken)
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to create remote A2A tool for %s: %w", remoteAgent.Name, err)
 		}
@@ -88,60 +133,147 @@ func CreateGoogleADKAgentWithSubagentSessionIDs(ctx context.Context, agentConfig
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to create LLM: %w", err)
 	}
+	baseModel := llmModel
+	if bestOf := agentConfig.BestOf; bestOf != nil {
+		if err := bestOf.Validate(); err != nil {
+			return nil, nil, err
+		}
+		var scorer models.CandidateScorer
+		if bestOf.Scorer == adk.BestOfScorerJudge {
+			scorer = &models.JudgeScorer{Model: baseModel}
+		}
+		log.Info("Sampling several candidates per model call", "candidates", bestOf.Candidates, "scorer", bestOf.Scorer)
+		llmModel = models.NewBestOfModel(llmModel, bestOf.Candidates, scorer)
+	}
+	if reflection := agentConfig.Reflection; reflection != nil {
+		if err := reflection.Validate(); err != nil {
+			return nil, nil, err
+		}
+		critic := baseModel
+		if reflection.CritiqueModel != nil {
+			if critic, err = CreateLLM(ctx, reflection.CritiqueModel, log); err != nil {
+				return nil, nil, fmt.Errorf("failed to create critique LLM: %w", err)
+			}
+		}
+		log.Info("Reviewing final answers before returning them", "maxPasses", reflection.GetMaxPasses())
+		llmModel = models.NewReflectingModel(llmModel, critic, reflection.GetMaxPasses(), reflection.CritiquePrompt)
+	}
 
 	if agentName == "" {
 		agentName = "agent"
 	}
+	if err := agentConfig.Strategy.Validate(); err != nil {
+		return nil, nil, err
+	}
+	planExecute := agentConfig.Strategy == adk.AgentStrategyPlanExecute
 
 	// Collect tool names that require approval from HttpTools and SseTools.
 	approvalSet := make(map[string]bool)
 	for _, ht := range agentConfig.HttpTools {
 		for _, name := range ht.RequireApproval {
-			approvalSet[name] = true
+			approvalSet[mcp.ToolName(ht.ToolPrefix, name)] = true
 		}
 	}
 	for _, st := range agentConfig.SseTools {
 		for _, name := range st.RequireApproval {
-			approvalSet[name] = true
+			approvalSet[mcp.ToolName(st.ToolPrefix, name)] = true
 		}
 	}
 
-	// Build BeforeToolCallbacks. Approval gating runs first.
+	// Build BeforeToolCallbacks. Argument transforms run first, then policy
+	// authorization and approval gating.
 	beforeToolCallbacks := []llmagent.BeforeToolCallback{}
+	argTransformer, err := toolargs.New(agentConfig.ToolArgTransforms)
+	if err != nil {
+		return nil, nil, err
+	}
+	if argTransformer != nil {
+		log.Info("Wiring tool argument transforms", "transformCount", argTransformer.Len())
+		beforeToolCallbacks = append(beforeToolCallbacks, makeToolArgTransformCallback(argTransformer, log))
+	}
 	// Strip synthetic HITL tool messages from the model request to avoid unnecessary token usage.
 	beforeModelCallbacks := []llmagent.BeforeModelCallback{}
 
+	if policyFile := strings.TrimSpace(os.Getenv(policy.EnvPolicyFile)); policyFile != "" {
+		toolPolicy, err := policy.Load(policyFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		log.Info("Wiring tool authorization policy", "path", policyFile, "ruleCount", len(toolPolicy.Rules), "opa", toolPolicy.OPA != nil)
+		beforeToolCallbacks = append(beforeToolCallbacks, makeToolPolicyCallback(policy.NewEngine(toolPolicy), log))
+	}
 	if len(approvalSet) > 0 {
 		log.Info("Wiring approval callback", "toolCount", len(approvalSet))
 		beforeToolCallbacks = append(beforeToolCallbacks, MakeApprovalCallback(approvalSet))
+	}
+	if len(beforeToolCallbacks) > 0 {
 		beforeModelCallbacks = append(beforeModelCallbacks, MakeStripConfirmationPartsCallback())
 	}
+	beforeModelCallbacks = append(beforeModelCallbacks, makeGenerationOverrideCallback())
 	beforeToolCallbacks = append(beforeToolCallbacks, makeBeforeToolCallback(log))
 
+	retryPolicies, err := collectToolRetryPolicies(agentConfig)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build tool retry policies: %w", err)
+	}
+	if retryPolicies.count() > 0 {
+		log.Info("Wiring tool retry policies", "policyCount", retryPolicies.count())
+		toolsets = retryPolicies.track(toolsets)
+	}
+	// Local and MCP tools are listed through one toolset so name collisions
+	// are resolved by the agent's tool conflict policy.
+	agentTools, err := newResolvedToolset(localTools, toolsets, agentConfig.ToolConflictPolicy, log)
+	if err != nil {
+		return nil, nil, err
+	}
+	afterToolCallbacks := []llmagent.AfterToolCallback{
+		makeAfterToolCallback(log),
+	}
+	retryChain := newToolCallChain(plugins, beforeToolCallbacks, afterToolCallbacks)
+
 	llmAgentConfig := llmagent.Config{
-		Name:                 agentName,
-		Description:          agentConfig.Description,
-		Instruction:          agentConfig.Instruction,
-		Model:                llmModel,
-		IncludeContents:      llmagent.IncludeContentsDefault,
-		Tools:                localTools,
-		Toolsets:             toolsets,
-		BeforeToolCallbacks:  beforeToolCallbacks,
-		BeforeModelCallbacks: beforeModelCallbacks,
-		AfterToolCallbacks: []llmagent.AfterToolCallback{
-			makeAfterToolCallback(log),
-		},
+		Name:                  agentName,
+		Description:           agentConfig.Description,
+		Instruction:           agentConfig.Instruction,
+		Model:                 llmModel,
+		GenerateContentConfig: generateContentConfig(agentConfig),
+		IncludeContents:       llmagent.IncludeContentsDefault,
+		Toolsets:              []tool.Toolset{agentTools},
+		BeforeToolCallbacks:   beforeToolCallbacks,
+		BeforeModelCallbacks:  beforeModelCallbacks,
+		AfterToolCallbacks:    afterToolCallbacks,
 		OnToolErrorCallbacks: []llmagent.OnToolErrorCallback{
 			makeOnToolErrorCallback(log),
+			makeToolRetryCallback(retryPolicies, retryChain, log),
 		},
 	}
 
+	if planExecute {
+		// The LLM agent carries out the steps of the plan under the
+		// plan-execute agent, which keeps the configured name.
+		llmAgentConfig.Name = agentName + "_executor"
+		llmAgentConfig.Instruction += executorInstruction
+		llmAgentConfig.DisallowTransferToParent = true
+		llmAgentConfig.DisallowTransferToPeers = true
+	}
+	if agentConfig.Prompts != nil {
+		if err := agentConfig.Prompts.Validate(); err != nil {
+			return nil, nil, err
+		}
+		suffix := ""
+		if planExecute {
+			suffix = executorInstruction
+		}
+		llmAgentConfig.InstructionProvider = makePromptInstructionProvider(agentConfig.Instruction, agentConfig.Prompts.ActiveVersion(), suffix)
+		log.Info("Using versioned prompts", "versions", len(agentConfig.Prompts.Versions))
+	}
+
 	log.Info("Creating Google ADK LLM agent",
 		"name", llmAgentConfig.Name,
 		"hasDescription", llmAgentConfig.Description != "",
 		"hasInstruction", llmAgentConfig.Instruction != "",
-		"toolsCount", len(llmAgentConfig.Tools),
-		"toolsetsCount", len(llmAgentConfig.Toolsets))
+		"toolsCount", len(localTools),
+		"toolsetsCount", len(toolsets))
 
 	llmAgent, err := llmagent.New(llmAgentConfig)
 	if err != nil {
@@ -149,8 +281,19 @@ func CreateGoogleADKAgentWithSubagentSessionIDs(ctx context.Context, agentConfig
 	}
 
 	log.Info("Successfully created Google ADK LLM agent",
-		"toolsCount", len(llmAgentConfig.Tools),
-		"toolsetsCount", len(llmAgentConfig.Toolsets))
+		"toolsCount", len(localTools),
+		"toolsetsCount", len(toolsets))
+
+	if planExecute {
+		// Plans are written by the unwrapped model: sampling several plans
+		// or critiquing them is not worth the extra calls.
+		rootAgent, err := newPlanExecuteAgent(agentName, agentConfig.Description, baseModel, llmAgent)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create plan-execute agent: %w", err)
+		}
+		log.Info("Planning each request before executing it", "executor", llmAgentConfig.Name)
+		return rootAgent, subagentSessionIDs, nil
+	}
 
 	return llmAgent, subagentSessionIDs, nil
 }
@@ -190,6 +333,14 @@ func buildAgentTools(agentConfig *adk.AgentConfig, remoteAgentTools, extraTools
 func CreateLLM(ctx context.Context, m adk.Model, log logr.Logger) (adkmodel.LLM, error) {
 	switch m := m.(type) {
 	case *adk.OpenAI:
+		baseURL := m.BaseUrl
+		if baseURL == "" {
+			baseURL = defaultOpenAIBaseURL
+		}
+		keyPool, err := keyPoolFromBase(m.BaseModel, models.KeyHeaderAuthorization, baseURL, log)
+		if err != nil {
+			return nil, err
+		}
 		cfg := &models.OpenAIConfig{
 			TransportConfig:  transportConfigFromBase(m.BaseModel, m.Timeout),
 			Model:            m.Model,
@@ -203,20 +354,33 @@ func CreateLLM(ctx context.Context, m adk.Model, log logr.Logger) (adkmodel.LLM,
 			Temperature:      m.Temperature,
 			TopP:             m.TopP,
 		}
+		cfg.KeyPool = keyPool
 		return models.NewOpenAIModelWithLogger(cfg, log)
 
 	case *adk.AzureOpenAI:
+		keyPool, err := keyPoolFromBase(m.BaseModel, models.KeyHeaderAzure, os.Getenv("AZURE_OPENAI_ENDPOINT"), log)
+		if err != nil {
+			return nil, err
+		}
 		cfg := &models.AzureOpenAIConfig{
 			TransportConfig: transportConfigFromBase(m.BaseModel, nil),
 			Model:           m.Model,
 		}
+		cfg.KeyPool = keyPool
 		return models.NewAzureOpenAIModelWithLogger(cfg, log)
 
 	case *adk.Gemini:
+		keyPool, err := keyPoolFromBase(m.BaseModel, models.KeyHeaderGemini, defaultGeminiBaseURL, log)
+		if err != nil {
+			return nil, err
+		}
 		apiKey := os.Getenv("GOOGLE_API_KEY")
 		if apiKey == "" {
 			apiKey = os.Getenv("GEMINI_API_KEY")
 		}
+		if keyPool != nil {
+			apiKey = "pool" // placeholder; the key pool sets a key per request
+		}
 		if apiKey == "" {
 			return nil, fmt.Errorf("gemini model requires GOOGLE_API_KEY or GEMINI_API_KEY environment variable")
 		}
@@ -224,7 +388,9 @@ func CreateLLM(ctx context.Context, m adk.Model, log logr.Logger) (adkmodel.LLM,
 		if modelName == "" {
 			modelName = DefaultGeminiModel
 		}
-		httpClient, err := models.BuildHTTPClient(transportConfigFromBase(m.BaseModel, nil))
+		transport := transportConfigFromBase(m.BaseModel, nil)
+		transport.KeyPool = keyPool
+		httpClient, err := models.BuildHTTPClient(transport)
 		if err != nil {
 			return nil, fmt.Errorf("failed to build HTTP client for Gemini: %w", err)
 		}
@@ -257,15 +423,25 @@ func CreateLLM(ctx context.Context, m adk.Model, log logr.Logger) (adkmodel.LLM,
 		if modelName == "" {
 			modelName = DefaultAnthropicModel
 		}
-		cfg := &models.AnthropicConfig{
-			TransportConfig: transportConfigFromBase(m.BaseModel, m.Timeout),
-			Model:           modelName,
-			BaseUrl:         m.BaseUrl,
-			MaxTokens:       m.MaxTokens,
-			Temperature:     m.Temperature,
-			TopP:            m.TopP,
-			TopK:            m.TopK,
+		baseURL := m.BaseUrl
+		if baseURL == "" {
+			baseURL = defaultAnthropicBaseURL
 		}
+		keyPool, err := keyPoolFromBase(m.BaseModel, models.KeyHeaderAnthropic, baseURL, log)
+		if err != nil {
+			return nil, err
+		}
+		cfg := &models.AnthropicConfig{
+			TransportConfig:      transportConfigFromBase(m.BaseModel, m.Timeout),
+			Model:                modelName,
+			BaseUrl:              m.BaseUrl,
+			MaxTokens:            m.MaxTokens,
+			Temperature:          m.Temperature,
+			TopP:                 m.TopP,
+			TopK:                 m.TopK,
+			ThinkingBudgetTokens: m.ThinkingBudgetTokens,
+		}
+		cfg.KeyPool = keyPool
 		return models.NewAnthropicModelWithLogger(cfg, log)
 
 	case *adk.Ollama:
@@ -327,6 +503,11 @@ func CreateLLM(ctx context.Context, m adk.Model, log logr.Logger) (adkmodel.LLM,
 		cfg := &models.AnthropicConfig{
 			TransportConfig: transportConfigFromBase(m.BaseModel, nil),
 			Model:           modelName,
+			MaxTokens:       m.MaxTokens,
+			Temperature:     m.Temperature,
+			TopP:            m.TopP,
+			TopK:            m.TopK,
+			StopSequences:   m.StopSequences,
 		}
 		return models.NewAnthropicVertexAIModelWithLogger(ctx, cfg, region, project, log)
 
@@ -340,11 +521,77 @@ func CreateLLM(ctx context.Context, m adk.Model, log logr.Logger) (adkmodel.LLM,
 		}
 		return models.NewSAPAICoreModelWithLogger(cfg, log)
 
+	case *adk.Fake:
+		if m.ScriptPath == "" {
+			return nil, fmt.Errorf("fake model requires script_path")
+		}
+		return models.NewScriptedModelWithLogger(m.ScriptPath, log)
+
 	default:
 		return nil, fmt.Errorf("unsupported model type: %s", m.GetType())
 	}
 }
 
+// generateContentConfig returns the default generation parameters of the
+// agent: those of a model driven by the genai client, overlaid with the
+// agent's generation settings. Adapters (OpenAI, Anthropic, ...) apply their
+// own sampling settings and let the request config override them, so nil is
+// returned for them when the agent sets none.
+func generateContentConfig(agentConfig *adk.AgentConfig) *genai.GenerateContentConfig {
+	var cfg *genai.GenerateContentConfig
+	if g, ok := agentConfig.Model.(*adk.GeminiVertexAI); ok {
+		cfg = &genai.GenerateContentConfig{
+			StopSequences:    g.StopSequences,
+			ResponseMIMEType: g.ResponseMimeType,
+		}
+		if g.Temperature != nil {
+			cfg.Temperature = genai.Ptr(float32(*g.Temperature))
+		}
+		if g.TopP != nil {
+			cfg.TopP = genai.Ptr(float32(*g.TopP))
+		}
+		if g.TopK != nil {
+			cfg.TopK = genai.Ptr(float32(*g.TopK))
+		}
+		if g.MaxOutputTokens != nil {
+			cfg.MaxOutputTokens = int32(*g.MaxOutputTokens)
+		}
+		if g.CandidateCount != nil {
+			cfg.CandidateCount = int32(*g.CandidateCount)
+		}
+	}
+	return models.ApplyGenerationConfig(cfg, agentConfig.Generation)
+}
+
+// makeGenerationOverrideCallback applies the per-request generation
+// overrides attached to the context by the executor to each model request.
+func makeGenerationOverrideCallback() llmagent.BeforeModelCallback {
+	return func(ctx agent.CallbackContext, req *adkmodel.LLMRequest) (*adkmodel.LLMResponse, error) {
+		if g := models.GenerationOverridesFrom(ctx); g != nil {
+			req.Config = models.ApplyGenerationConfig(req.Config, g)
+		}
+		return nil, nil
+	}
+}
+
+// makePromptInstructionProvider returns the instruction of the prompt
+// version the executor assigned to the run, or of active for runs it did not
+// assign, followed by suffix. A version without an instruction keeps the
+// agent's own. Session state is injected as for a plain instruction.
+func makePromptInstructionProvider(instruction string, active *adk.PromptVersion, suffix string) llmagent.InstructionProvider {
+	return func(ctx agent.ReadonlyContext) (string, error) {
+		version := active
+		if a, ok := prompts.AssignmentFrom(ctx); ok {
+			version = a.Version
+		}
+		text := instruction
+		if version.Instruction != "" {
+			text = version.Instruction
+		}
+		return instructionutil.InjectSessionState(ctx, text+suffix)
+	}
+}
+
 // transportConfigFromBase builds a TransportConfig from the shared BaseModel fields.
 func transportConfigFromBase(b adk.BaseModel, timeout *int) models.TransportConfig {
 	return models.TransportConfig{
@@ -357,6 +604,28 @@ func transportConfigFromBase(b adk.BaseModel, timeout *int) models.TransportConf
 	}
 }
 
+// keyPoolFromBase builds the key pool of a model from its key_pool config,
+// reading each key from its environment variable. baseURL is the base URL
+// of the model's client. It returns nil when the model has no key pool.
+func keyPoolFromBase(b adk.BaseModel, header, baseURL string, log logr.Logger) (*models.KeyPool, error) {
+	if len(b.KeyPool) == 0 {
+		return nil, nil
+	}
+	if err := b.ValidateKeyPool(); err != nil {
+		return nil, err
+	}
+	keys := make([]models.PoolKey, 0, len(b.KeyPool))
+	for _, k := range b.KeyPool {
+		apiKey := os.Getenv(k.APIKeyEnv)
+		if apiKey == "" {
+			return nil, fmt.Errorf("key_pool: %s environment variable is not set", k.APIKeyEnv)
+		}
+		keys = append(keys, models.PoolKey{Name: k.APIKeyEnv, APIKey: apiKey, BaseURL: k.BaseUrl, Weight: k.Weight})
+	}
+	log.Info("Load-balancing model requests over a key pool", "keys", len(keys))
+	return models.NewKeyPool(keys, header, baseURL, log), nil
+}
+
 // extractHeaders returns an empty map if nil, the original map otherwise.
 func extractHeaders(headers map[string]string) map[string]string {
 	if headers == nil {
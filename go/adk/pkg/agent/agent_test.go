@@ -214,6 +214,98 @@ func TestCreateLLMConfig_OpenAI(t *testing.T) {
 	}
 }
 
+// TestGenerateContentConfig_GeminiVertexAI verifies that sampling parameters
+// from a gemini_vertex_ai model config are carried into the agent's default
+// GenerateContentConfig, and that adapter-backed models get none.
+func TestGenerateContentConfig_GeminiVertexAI(t *testing.T) {
+	configJSON := `{
+		"model": {
+			"type": "gemini_vertex_ai",
+			"model": "gemini-2.5-flash",
+			"temperature": 0.2,
+			"top_p": 0.9,
+			"top_k": 40,
+			"stop_sequences": ["END"],
+			"max_output_tokens": 1024,
+			"candidate_count": 1,
+			"response_mime_type": "application/json"
+		},
+		"description": "test",
+		"instruction": "test"
+	}`
+
+	var cfg adk.AgentConfig
+	if err := json.Unmarshal([]byte(configJSON), &cfg); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	gc := generateContentConfig(&cfg)
+	if gc == nil {
+		t.Fatal("generateContentConfig() = nil, want config")
+	}
+	if gc.Temperature == nil || *gc.Temperature != 0.2 {
+		t.Errorf("Temperature = %v, want 0.2", gc.Temperature)
+	}
+	if gc.TopP == nil || *gc.TopP != 0.9 {
+		t.Errorf("TopP = %v, want 0.9", gc.TopP)
+	}
+	if gc.TopK == nil || *gc.TopK != 40 {
+		t.Errorf("TopK = %v, want 40", gc.TopK)
+	}
+	if len(gc.StopSequences) != 1 || gc.StopSequences[0] != "END" {
+		t.Errorf("StopSequences = %v, want [END]", gc.StopSequences)
+	}
+	if gc.MaxOutputTokens != 1024 {
+		t.Errorf("MaxOutputTokens = %d, want 1024", gc.MaxOutputTokens)
+	}
+	if gc.CandidateCount != 1 {
+		t.Errorf("CandidateCount = %d, want 1", gc.CandidateCount)
+	}
+	if gc.ResponseMIMEType != "application/json" {
+		t.Errorf("ResponseMIMEType = %q, want application/json", gc.ResponseMIMEType)
+	}
+
+	if got := generateContentConfig(&adk.AgentConfig{Model: &adk.OpenAI{}}); got != nil {
+		t.Errorf("generateContentConfig(OpenAI) = %+v, want nil", got)
+	}
+}
+
+func TestGenerateContentConfig_AgentGeneration(t *testing.T) {
+	temp, topK, maxTokens := 0.7, 20, 256
+	generation := &adk.GenerationConfig{Temperature: &temp, TopK: &topK, MaxOutputTokens: &maxTokens, StopSequences: []string{"STOP"}}
+
+	gc := generateContentConfig(&adk.AgentConfig{Model: &adk.OpenAI{}, Generation: generation})
+	if gc == nil {
+		t.Fatal("generateContentConfig() = nil, want the agent's generation settings")
+	}
+	if gc.Temperature == nil || *gc.Temperature != 0.7 {
+		t.Errorf("Temperature = %v, want 0.7", gc.Temperature)
+	}
+	if gc.TopK == nil || *gc.TopK != 20 {
+		t.Errorf("TopK = %v, want 20", gc.TopK)
+	}
+	if gc.MaxOutputTokens != 256 {
+		t.Errorf("MaxOutputTokens = %d, want 256", gc.MaxOutputTokens)
+	}
+	if len(gc.StopSequences) != 1 || gc.StopSequences[0] != "STOP" {
+		t.Errorf("StopSequences = %v, want [STOP]", gc.StopSequences)
+	}
+
+	// The agent's settings take precedence over the Gemini model's, which
+	// are kept where the agent sets nothing.
+	geminiTemp, topP := 0.1, 0.5
+	gc = generateContentConfig(&adk.AgentConfig{
+		Model:      &adk.GeminiVertexAI{Temperature: &geminiTemp, TopP: &topP},
+		Generation: generation,
+	})
+	if gc.Temperature == nil || *gc.Temperature != 0.7 {
+		t.Errorf("Temperature = %v, want the agent's 0.7", gc.Temperature)
+	}
+	if gc.TopP == nil || *gc.TopP != 0.5 {
+		t.Errorf("TopP = %v, want the model's 0.5", gc.TopP)
+	}
+}
+
 // TestModelName_ReturnsModelNotProvider verifies that the LLM Name() method
 // returns the actual model name (e.g. "gpt-4o") rather than the provider name
 // (e.g. "openai"). The Google ADK framework uses Name() to set req.Model in
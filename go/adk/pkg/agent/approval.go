@@ -61,35 +61,39 @@ func MakeApprovalCallback(toolsRequiringApproval map[string]bool) llmagent.Befor
 			return nil, nil
 		}
 
-		// On re-invocation after confirmation, ADK populates ToolConfirmation.
-		if confirmation := ctx.ToolConfirmation(); confirmation != nil {
-			if confirmation.Confirmed {
-				// Approved — proceed with tool execution.
-				return nil, nil
-			}
-			// Rejected — extract optional rejection reason from payload.
-			payload, _ := confirmation.Payload.(map[string]any)
-			reason, _ := payload["rejection_reason"].(string)
-			if reason != "" {
-				return map[string]any{
-					"result": fmt.Sprintf("Tool call was rejected by user. Reason: %s", reason),
-				}, nil
-			}
+		return requireConfirmation(ctx, toolName, fmt.Sprintf("Tool '%s' requires approval before execution.", toolName))
+	}
+}
+
+// requireConfirmation blocks a tool call until the user confirms it. On the
+// first invocation it requests confirmation with hint; on re-invocation it
+// returns nil when the call was approved and a rejection result otherwise.
+func requireConfirmation(ctx agent.ToolContext, toolName, hint string) (map[string]any, error) {
+	// On re-invocation after confirmation, ADK populates ToolConfirmation.
+	if confirmation := ctx.ToolConfirmation(); confirmation != nil {
+		if confirmation.Confirmed {
+			// Approved — proceed with tool execution.
+			return nil, nil
+		}
+		// Rejected — extract optional rejection reason from payload.
+		payload, _ := confirmation.Payload.(map[string]any)
+		reason, _ := payload["rejection_reason"].(string)
+		if reason != "" {
 			return map[string]any{
-				"result": "Tool call was rejected by user.",
+				"result": fmt.Sprintf("Tool call was rejected by user. Reason: %s", reason),
 			}, nil
 		}
-
-		// First invocation — request confirmation and block execution.
-		if err := ctx.RequestConfirmation(
-			fmt.Sprintf("Tool '%s' requires approval before execution.", toolName),
-			nil,
-		); err != nil {
-			return nil, fmt.Errorf("failed to request confirmation for tool %s: %w", toolName, err)
-		}
 		return map[string]any{
-			"status": "confirmation_requested",
-			"tool":   toolName,
+			"result": "Tool call was rejected by user.",
 		}, nil
 	}
+
+	// First invocation — request confirmation and block execution.
+	if err := ctx.RequestConfirmation(hint, nil); err != nil {
+		return nil, fmt.Errorf("failed to request confirmation for tool %s: %w", toolName, err)
+	}
+	return map[string]any{
+		"status": "confirmation_requested",
+		"tool":   toolName,
+	}, nil
 }
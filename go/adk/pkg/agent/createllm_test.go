@@ -126,3 +126,9 @@ func TestAgent_Anthropic(t *testing.T) {
 	text := runAgent(t, cfg, "What is 2+2?")
 	assert.Contains(t, text, "4")
 }
+
+func TestAgent_Fake(t *testing.T) {
+	cfg := loadConfig(t, "testdata/config_fake.json", "")
+	text := runAgent(t, cfg, "What is 2+2?")
+	assert.Contains(t, text, "4")
+}
@@ -0,0 +1,380 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"iter"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"google.golang.org/adk/agent"
+	adkmodel "google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+	"google.golang.org/genai"
+)
+
+const (
+	// maxPlanSteps bounds the steps of a plan, including re-planned ones.
+	maxPlanSteps = 10
+	// maxReplans bounds how often the remaining steps are re-planned after
+	// a step fails.
+	maxReplans = 2
+)
+
+// planMetadataKey is the event custom metadata key holding the plan. The
+// A2A converter reports it under kagent_plan (a2a.MetadataKeyPlan).
+const planMetadataKey = "plan"
+
+// stepFailedPrefix starts the reply of an executor that could not complete
+// its step.
+const stepFailedPrefix = "STEP FAILED:"
+
+// planningPrompt is the system instruction of planning calls.
+const planningPrompt = "You plan the work of an assistant before it starts. Break the user's latest request " +
+	"into a short checklist of concrete steps the assistant can carry out one at a time with its tools. " +
+	"Use as few steps as the request needs, at most %d. Reply with only a JSON object of the form " +
+	`{"steps": [{"title": "..."}]}` + ", without any other text."
+
+// executorInstruction is appended to the instruction of the agent carrying
+// out a plan.
+const executorInstruction = "\n\nYou are working through a plan. When told which step to work on, " +
+	"carry out only that step and end with a short summary of its result. If you cannot complete the step, " +
+	"reply with " + stepFailedPrefix + " followed by the reason. When told the plan is complete, " +
+	"write the final answer to the user's request from the results of the steps."
+
+type planStepStatus string
+
+const (
+	planStepPending    planStepStatus = "pending"
+	planStepInProgress planStepStatus = "in_progress"
+	planStepDone       planStepStatus = "done"
+	planStepFailed     planStepStatus = "failed"
+)
+
+type planStep struct {
+	Title  string         `json:"title"`
+	Status planStepStatus `json:"status,omitempty"`
+	// Result summarizes the step's outcome, or why it failed.
+	Result string `json:"result,omitempty"`
+}
+
+type plan struct {
+	Steps   []planStep `json:"steps"`
+	Replans int        `json:"replans,omitempty"`
+}
+
+// metadata returns the plan as plain JSON values, so it survives session
+// storage and A2A metadata unchanged.
+func (p *plan) metadata() map[string]any {
+	steps := make([]any, len(p.Steps))
+	for i, s := range p.Steps {
+		step := map[string]any{"title": s.Title, "status": string(s.Status)}
+		if s.Result != "" {
+			step["result"] = s.Result
+		}
+		steps[i] = step
+	}
+	return map[string]any{"steps": steps, "replans": p.Replans}
+}
+
+// checklist renders the plan for display.
+func (p *plan) checklist() string {
+	var b strings.Builder
+	for i, s := range p.Steps {
+		mark := " "
+		switch s.Status {
+		case planStepDone:
+			mark = "x"
+		case planStepFailed:
+			mark = "!"
+		case planStepInProgress:
+			mark = ">"
+		}
+		fmt.Fprintf(&b, "%d. [%s] %s\n", i+1, mark, s.Title)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// planExecutor runs the plan-execute strategy: planner writes a checklist
+// for the request, and executor carries it out one step at a time.
+type planExecutor struct {
+	planner  adkmodel.LLM
+	executor agent.Agent
+}
+
+// newPlanExecuteAgent returns the root agent of the plan-execute strategy,
+// with executor as its only sub-agent. Each step is announced by an event
+// the executor sees as context, and every plan event carries the plan with
+// the status of each step in its custom metadata.
+//
+// A step paused on a long-running tool call ends the run; the approval
+// resumes the executor directly, which finishes that step only.
+func newPlanExecuteAgent(name, description string, planner adkmodel.LLM, executor agent.Agent) (agent.Agent, error) {
+	p := &planExecutor{planner: planner, executor: executor}
+	return agent.New(agent.Config{
+		Name:        name,
+		Description: description,
+		SubAgents:   []agent.Agent{executor},
+		Run:         p.run,
+	})
+}
+
+func (p *planExecutor) run(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+	return func(yield func(*session.Event, error) bool) {
+		log := logr.FromContextOrDiscard(ctx)
+		conversation := conversationContents(ctx.Session())
+
+		pl, usage, err := p.makePlan(ctx, conversation, nil)
+		if err != nil {
+			log.Info("Planning failed, answering without a plan", "error", err.Error())
+			for ev, err := range p.executor.Run(ctx) {
+				if !yield(ev, err) {
+					return
+				}
+			}
+			return
+		}
+		ev := planEvent(ctx, pl, "Plan:\n"+pl.checklist())
+		ev.UsageMetadata = usage
+		if !yield(ev, nil) {
+			return
+		}
+
+		for i := 0; i < len(pl.Steps); i++ {
+			pl.Steps[i].Status = planStepInProgress
+			if !yield(planEvent(ctx, pl, fmt.Sprintf("Step %d of %d: %s", i+1, len(pl.Steps), pl.Steps[i].Title)), nil) {
+				return
+			}
+			out, ok := p.runStep(ctx, yield)
+			if !ok || out.paused || ctx.Ended() {
+				return
+			}
+
+			if out.failure == "" {
+				pl.Steps[i].Status = planStepDone
+				pl.Steps[i].Result = out.result
+				if !yield(planEvent(ctx, pl, fmt.Sprintf("Step %d of %d done.", i+1, len(pl.Steps))), nil) {
+					return
+				}
+				continue
+			}
+
+			pl.Steps[i].Status = planStepFailed
+			pl.Steps[i].Result = out.failure
+			log.Info("Plan step failed", "step", i+1, "reason", out.failure, "replans", pl.Replans)
+			if pl.Replans >= maxReplans {
+				p.fail(ctx, pl, i, out, yield)
+				return
+			}
+			revised, usage, err := p.makePlan(ctx, conversation, pl)
+			if err != nil {
+				log.Info("Re-planning failed", "error", err.Error())
+				p.fail(ctx, pl, i, out, yield)
+				return
+			}
+			pl.Replans++
+			pl.Steps = append(pl.Steps[:i+1], revised.Steps[:min(len(revised.Steps), maxPlanSteps-i-1)]...)
+			ev := planEvent(ctx, pl, fmt.Sprintf("Step %d failed: %s\nRevised plan:\n%s", i+1, out.failure, pl.checklist()))
+			ev.UsageMetadata = usage
+			if !yield(ev, nil) {
+				return
+			}
+		}
+
+		if !yield(planEvent(ctx, pl, "Plan complete."), nil) {
+			return
+		}
+		for ev, err := range p.executor.Run(ctx) {
+			if !yield(ev, err) {
+				return
+			}
+		}
+	}
+}
+
+// stepOutcome is the result of running the executor on one step.
+type stepOutcome struct {
+	// result is the executor's final reply.
+	result string
+	// failure says why the step failed; it is empty on success.
+	failure string
+	// errEvent and err are the model error or run error that failed the
+	// step. They are held back so a re-plan can recover from them.
+	errEvent *session.Event
+	err      error
+	// paused is set when the executor stopped on a long-running tool call.
+	paused bool
+}
+
+// runStep runs the executor on the current step, forwarding its events. It
+// returns false when the consumer stopped the iteration.
+func (p *planExecutor) runStep(ctx agent.InvocationContext, yield func(*session.Event, error) bool) (stepOutcome, bool) {
+	var out stepOutcome
+	for ev, err := range p.executor.Run(ctx) {
+		if err != nil {
+			out.err = err
+			out.failure = err.Error()
+			return out, true
+		}
+		if ev == nil {
+			continue
+		}
+		if ev.ErrorCode != "" {
+			out.errEvent = ev
+			out.failure = strings.TrimSpace(ev.ErrorCode + " " + ev.ErrorMessage)
+			return out, true
+		}
+		if !yield(ev, nil) {
+			return out, false
+		}
+		if len(ev.LongRunningToolIDs) > 0 {
+			out.paused = true
+		}
+		if !ev.Partial && ev.IsFinalResponse() {
+			if text := contentText(ev.Content); text != "" {
+				out.result = text
+			}
+		}
+	}
+	if reason, failed := strings.CutPrefix(out.result, stepFailedPrefix); failed {
+		out.failure = strings.TrimSpace(reason)
+		if out.failure == "" {
+			out.failure = "the step could not be completed"
+		}
+	}
+	return out, true
+}
+
+// fail ends the run after step i failed for good, surfacing the error that
+// failed it.
+func (p *planExecutor) fail(ctx agent.InvocationContext, pl *plan, i int, out stepOutcome, yield func(*session.Event, error) bool) {
+	switch {
+	case out.err != nil:
+		yield(nil, out.err)
+	case out.errEvent != nil:
+		yield(out.errEvent, nil)
+	default:
+		yield(planEvent(ctx, pl, fmt.Sprintf("Step %d failed: %s\nStopping, the plan could not be completed.", i+1, out.failure)), nil)
+	}
+}
+
+// makePlan asks the planner for the steps of the request in conversation.
+// Given the plan so far, it asks for the steps that remain after its last,
+// failed step instead.
+func (p *planExecutor) makePlan(ctx agent.InvocationContext, conversation []*genai.Content, current *plan) (*plan, *genai.GenerateContentResponseUsageMetadata, error) {
+	contents := conversation
+	if current != nil {
+		var b strings.Builder
+		b.WriteString("The plan so far:\n")
+		for i, s := range current.Steps {
+			fmt.Fprintf(&b, "%d. [%s] %s", i+1, s.Status, s.Title)
+			if s.Result != "" {
+				fmt.Fprintf(&b, ": %s", s.Result)
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("\nThe last step failed. Plan the steps that remain to complete the request, working around the failure. Do not repeat completed steps.")
+		contents = append(contents[:len(contents):len(contents)], genai.NewContentFromText(b.String(), genai.RoleUser))
+	}
+	if len(contents) == 0 {
+		return nil, nil, fmt.Errorf("no request to plan")
+	}
+
+	req := &adkmodel.LLMRequest{
+		Model:    p.planner.Name(),
+		Contents: contents,
+		Config: &genai.GenerateContentConfig{
+			SystemInstruction: genai.NewContentFromText(fmt.Sprintf(planningPrompt, maxPlanSteps), genai.RoleUser),
+			Temperature:       genai.Ptr[float32](0),
+		},
+	}
+	var resp *adkmodel.LLMResponse
+	for r, err := range p.planner.GenerateContent(ctx, req, false) {
+		if err != nil {
+			return nil, nil, fmt.Errorf("planner call failed: %w", err)
+		}
+		if r != nil && !r.Partial {
+			resp = r
+		}
+	}
+	if resp == nil || resp.ErrorCode != "" {
+		return nil, nil, fmt.Errorf("planner returned no plan")
+	}
+	pl, err := parsePlan(contentText(resp.Content))
+	if err != nil {
+		return nil, nil, err
+	}
+	return pl, resp.UsageMetadata, nil
+}
+
+// parsePlan reads the steps of a planner reply, tolerating text or code
+// fences around the JSON object.
+func parsePlan(text string) (*plan, error) {
+	start, end := strings.Index(text, "{"), strings.LastIndex(text, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("planner reply holds no JSON object")
+	}
+	var pl plan
+	if err := json.Unmarshal([]byte(text[start:end+1]), &pl); err != nil {
+		return nil, fmt.Errorf("failed to parse plan: %w", err)
+	}
+	steps := pl.Steps[:0]
+	for _, s := range pl.Steps {
+		if title := strings.TrimSpace(s.Title); title != "" {
+			steps = append(steps, planStep{Title: title, Status: planStepPending})
+		}
+	}
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("plan has no steps")
+	}
+	return &plan{Steps: steps[:min(len(steps), maxPlanSteps)]}, nil
+}
+
+// planEvent returns an event authored by the plan-execute agent that shows
+// text and carries the current plan.
+func planEvent(ctx agent.InvocationContext, pl *plan, text string) *session.Event {
+	ev := session.NewEvent(ctx.InvocationID())
+	ev.Author = ctx.Agent().Name()
+	ev.Branch = ctx.Branch()
+	ev.Content = genai.NewContentFromText(text, genai.RoleModel)
+	ev.CustomMetadata = map[string]any{planMetadataKey: pl.metadata()}
+	return ev
+}
+
+// conversationContents returns the text of the session as planner input:
+// user messages as user turns and agent replies as model turns.
+func conversationContents(sess session.Session) []*genai.Content {
+	if sess == nil {
+		return nil
+	}
+	var contents []*genai.Content
+	for ev := range sess.Events().All() {
+		if ev == nil || ev.Partial {
+			continue
+		}
+		text := contentText(ev.Content)
+		if text == "" {
+			continue
+		}
+		role := genai.RoleModel
+		if ev.Author == "user" {
+			role = genai.RoleUser
+		}
+		contents = append(contents, genai.NewContentFromText(text, role))
+	}
+	return contents
+}
+
+// contentText joins the non-thought text parts of c.
+func contentText(c *genai.Content) string {
+	if c == nil {
+		return ""
+	}
+	var parts []string
+	for _, p := range c.Parts {
+		if p != nil && !p.Thought && strings.TrimSpace(p.Text) != "" {
+			parts = append(parts, p.Text)
+		}
+	}
+	return strings.TrimSpace(strings.Join(parts, "\n"))
+}
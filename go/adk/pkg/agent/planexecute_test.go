@@ -0,0 +1,68 @@
+package agent
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParsePlan(t *testing.T) {
+	reply := "Here is the plan:\n```json\n" +
+		`{"steps": [{"title": "List the pods"}, {"title": "  "}, {"title": "Read the failing pod's logs"}]}` +
+		"\n```"
+	pl, err := parsePlan(reply)
+	if err != nil {
+		t.Fatalf("parsePlan() error = %v", err)
+	}
+	if len(pl.Steps) != 2 || pl.Steps[1].Title != "Read the failing pod's logs" {
+		t.Fatalf("steps = %+v, want the two non-empty steps", pl.Steps)
+	}
+	for _, s := range pl.Steps {
+		if s.Status != planStepPending {
+			t.Errorf("step %q status = %q, want pending", s.Title, s.Status)
+		}
+	}
+
+	for _, bad := range []string{"no plan", `{"steps": []}`, `{"steps": [`} {
+		if _, err := parsePlan(bad); err == nil {
+			t.Errorf("parsePlan(%q) error = nil, want an error", bad)
+		}
+	}
+}
+
+func TestParsePlan_CapsSteps(t *testing.T) {
+	reply := `{"steps": [` + strings.Repeat(`{"title": "step"},`, maxPlanSteps+2) + `{"title": "last"}]}`
+	pl, err := parsePlan(reply)
+	if err != nil {
+		t.Fatalf("parsePlan() error = %v", err)
+	}
+	if len(pl.Steps) != maxPlanSteps {
+		t.Errorf("got %d steps, want %d", len(pl.Steps), maxPlanSteps)
+	}
+}
+
+func TestPlan_ChecklistAndMetadata(t *testing.T) {
+	pl := &plan{Steps: []planStep{
+		{Title: "List the pods", Status: planStepDone, Result: "3 pods"},
+		{Title: "Read the logs", Status: planStepFailed, Result: "forbidden"},
+		{Title: "Describe the pod", Status: planStepInProgress},
+		{Title: "Summarize", Status: planStepPending},
+	}, Replans: 1}
+
+	want := "1. [x] List the pods\n2. [!] Read the logs\n3. [>] Describe the pod\n4. [ ] Summarize"
+	if got := pl.checklist(); got != want {
+		t.Errorf("checklist() = %q, want %q", got, want)
+	}
+
+	meta := pl.metadata()
+	steps, ok := meta["steps"].([]any)
+	if !ok || len(steps) != 4 || meta["replans"] != 1 {
+		t.Fatalf("metadata() = %v", meta)
+	}
+	first := steps[0].(map[string]any)
+	if first["status"] != "done" || first["result"] != "3 pods" {
+		t.Errorf("first step = %v", first)
+	}
+	if _, ok := steps[3].(map[string]any)["result"]; ok {
+		t.Error("pending step should carry no result")
+	}
+}
@@ -0,0 +1,77 @@
+package agent
+
+import (
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"github.com/kagent-dev/kagent/go/adk/pkg/policy"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/tool"
+)
+
+// ToolErrorCodeDenied is returned to the model when the tool authorization
+// policy denies a tool call.
+const ToolErrorCodeDenied = "TOOL_DENIED_BY_POLICY"
+
+// ToolPolicyStateKeyPrefix prefixes the session state key under which the
+// policy decision for a tool call is recorded, followed by the function call
+// ID. The state delta is part of the tool response event, so every decision
+// ends up in the session's event history.
+const ToolPolicyStateKeyPrefix = "kagent_tool_policy:"
+
+// makeToolPolicyCallback returns a BeforeToolCallback that authorizes each
+// tool call against engine before it runs. Denied calls return a structured
+// error to the model; calls requiring approval go through the same
+// confirmation flow as RequireApproval tools. Evaluation errors deny the call.
+func makeToolPolicyCallback(engine policy.Engine, logger logr.Logger) llmagent.BeforeToolCallback {
+	return func(ctx agent.ToolContext, t tool.Tool, args map[string]any) (map[string]any, error) {
+		toolName := t.Name()
+		decision, err := engine.Decide(ctx, policy.Request{
+			User:      ctx.UserID(),
+			AppName:   ctx.AppName(),
+			AgentName: ctx.AgentName(),
+			SessionID: ctx.SessionID(),
+			Tool:      toolName,
+			Args:      args,
+		})
+		if err != nil {
+			logger.Error(err, "Tool policy evaluation failed, denying tool call", "tool", toolName, "user", ctx.UserID())
+			decision = policy.Decision{Effect: policy.EffectDeny, Reason: fmt.Sprintf("policy evaluation failed: %v", err)}
+		}
+		logger.V(1).Info("Tool policy decision",
+			"tool", toolName,
+			"user", ctx.UserID(),
+			"functionCallID", ctx.FunctionCallID(),
+			"decision", decision.String())
+		if err := ctx.State().Set(ToolPolicyStateKeyPrefix+ctx.FunctionCallID(), map[string]any{
+			"tool":   toolName,
+			"user":   ctx.UserID(),
+			"effect": string(decision.Effect),
+			"rule":   decision.Rule,
+			"reason": decision.Reason,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to record tool policy decision: %w", err)
+		}
+
+		switch decision.Effect {
+		case policy.EffectDeny:
+			msg := fmt.Sprintf("Tool '%s' is not permitted for user '%s'", toolName, ctx.UserID())
+			if decision.Reason != "" {
+				msg += ": " + decision.Reason
+			}
+			return map[string]any{
+				"error":      msg,
+				"error_code": ToolErrorCodeDenied,
+				"hint":       "Do not retry this tool call. Tell the user the action is not permitted or use a different approach.",
+			}, nil
+		case policy.EffectRequireApproval:
+			hint := fmt.Sprintf("Tool '%s' requires approval by policy.", toolName)
+			if decision.Reason != "" {
+				hint = fmt.Sprintf("Tool '%s' requires approval by policy: %s", toolName, decision.Reason)
+			}
+			return requireConfirmation(ctx, toolName, hint)
+		}
+		return nil, nil
+	}
+}
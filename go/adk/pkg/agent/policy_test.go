@@ -0,0 +1,120 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/kagent-dev/kagent/go/adk/pkg/policy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	adkagent "google.golang.org/adk/agent"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/toolconfirmation"
+)
+
+type mapState struct {
+	session.State
+	values map[string]any
+}
+
+func (s mapState) Set(key string, value any) error {
+	s.values[key] = value
+	return nil
+}
+
+type policyTestToolContext struct {
+	adkagent.ToolContext
+	state        mapState
+	confirmation *toolconfirmation.ToolConfirmation
+	requested    *string
+}
+
+func (c policyTestToolContext) UserID() string         { return "mallory" }
+func (c policyTestToolContext) AppName() string        { return "app" }
+func (c policyTestToolContext) AgentName() string      { return "agent" }
+func (c policyTestToolContext) SessionID() string      { return "session" }
+func (c policyTestToolContext) FunctionCallID() string { return "call_1" }
+func (c policyTestToolContext) State() session.State   { return c.state }
+func (c policyTestToolContext) ToolConfirmation() *toolconfirmation.ToolConfirmation {
+	return c.confirmation
+}
+func (c policyTestToolContext) RequestConfirmation(hint string, _ any) error {
+	*c.requested = hint
+	return nil
+}
+
+type staticEngine struct {
+	decision policy.Decision
+	err      error
+}
+
+func (e staticEngine) Decide(context.Context, policy.Request) (policy.Decision, error) {
+	return e.decision, e.err
+}
+
+type namedTool struct {
+	tool.Tool
+	name string
+}
+
+func (n namedTool) Name() string { return n.name }
+
+func newPolicyTestToolContext(confirmation *toolconfirmation.ToolConfirmation) policyTestToolContext {
+	return policyTestToolContext{
+		state:        mapState{values: map[string]any{}},
+		confirmation: confirmation,
+		requested:    new(string),
+	}
+}
+
+func TestToolPolicyCallback(t *testing.T) {
+	deleteTool := namedTool{name: "delete_pod"}
+
+	t.Run("allow", func(t *testing.T) {
+		ctx := newPolicyTestToolContext(nil)
+		cb := makeToolPolicyCallback(staticEngine{decision: policy.Decision{Effect: policy.EffectAllow}}, logr.Discard())
+		result, err := cb(ctx, deleteTool, nil)
+		require.NoError(t, err)
+		assert.Nil(t, result)
+		assert.Equal(t, "allow", ctx.state.values[ToolPolicyStateKeyPrefix+"call_1"].(map[string]any)["effect"])
+	})
+
+	t.Run("deny", func(t *testing.T) {
+		ctx := newPolicyTestToolContext(nil)
+		cb := makeToolPolicyCallback(staticEngine{decision: policy.Decision{Effect: policy.EffectDeny, Rule: "no-mutations", Reason: "read-only user"}}, logr.Discard())
+		result, err := cb(ctx, deleteTool, nil)
+		require.NoError(t, err)
+		assert.Equal(t, ToolErrorCodeDenied, result["error_code"])
+		assert.Contains(t, result["error"], "read-only user")
+		recorded := ctx.state.values[ToolPolicyStateKeyPrefix+"call_1"].(map[string]any)
+		assert.Equal(t, "deny", recorded["effect"])
+		assert.Equal(t, "no-mutations", recorded["rule"])
+		assert.Equal(t, "mallory", recorded["user"])
+	})
+
+	t.Run("evaluation error denies", func(t *testing.T) {
+		ctx := newPolicyTestToolContext(nil)
+		cb := makeToolPolicyCallback(staticEngine{err: errors.New("opa unavailable")}, logr.Discard())
+		result, err := cb(ctx, deleteTool, nil)
+		require.NoError(t, err)
+		assert.Equal(t, ToolErrorCodeDenied, result["error_code"])
+	})
+
+	t.Run("require approval", func(t *testing.T) {
+		cb := makeToolPolicyCallback(staticEngine{decision: policy.Decision{Effect: policy.EffectRequireApproval}}, logr.Discard())
+
+		ctx := newPolicyTestToolContext(nil)
+		result, err := cb(ctx, deleteTool, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "confirmation_requested", result["status"])
+		assert.Contains(t, *ctx.requested, "requires approval by policy")
+
+		ctx = newPolicyTestToolContext(&toolconfirmation.ToolConfirmation{Confirmed: true})
+		result, err = cb(ctx, deleteTool, nil)
+		require.NoError(t, err)
+		assert.Nil(t, result)
+	})
+}
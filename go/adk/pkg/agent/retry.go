@@ -0,0 +1,347 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/kagent-dev/kagent/go/adk/pkg/mcp"
+	"github.com/kagent-dev/kagent/go/api/adk"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	adkplugin "google.golang.org/adk/plugin"
+	"google.golang.org/adk/tool"
+)
+
+// Error codes returned to the model in the function response of a failed
+// tool call.
+const (
+	ToolErrorCodeFailed           = "TOOL_EXECUTION_FAILED"
+	ToolErrorCodeRetriesExhausted = "TOOL_RETRIES_EXHAUSTED"
+)
+
+const (
+	defaultToolRetryInitialBackoff = time.Second
+	defaultToolRetryMaxBackoff     = 30 * time.Second
+)
+
+// runnableTool is satisfied by ADK function and MCP tools, which is what the
+// flow passes to OnToolErrorCallbacks.
+type runnableTool interface {
+	Run(ctx tool.Context, args any) (map[string]any, error)
+}
+
+// toolRetryPolicy is the resolved form of adk.ToolRetryPolicy.
+type toolRetryPolicy struct {
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	retryable      []*regexp.Regexp
+}
+
+func newToolRetryPolicy(p *adk.ToolRetryPolicy) (*toolRetryPolicy, error) {
+	out := &toolRetryPolicy{
+		maxAttempts:    max(p.MaxAttempts, 1),
+		initialBackoff: defaultToolRetryInitialBackoff,
+		maxBackoff:     defaultToolRetryMaxBackoff,
+	}
+	if p.InitialBackoff != nil {
+		out.initialBackoff = time.Duration(*p.InitialBackoff * float64(time.Second))
+	}
+	if p.MaxBackoff != nil {
+		out.maxBackoff = time.Duration(*p.MaxBackoff * float64(time.Second))
+	}
+	for _, expr := range p.RetryableErrors {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid retryable error pattern %q: %w", expr, err)
+		}
+		out.retryable = append(out.retryable, re)
+	}
+	return out, nil
+}
+
+// isRetryable reports whether a failed call may be retried. Without
+// retryable error patterns only transient transport failures are retried,
+// and a confirmation outcome is never retried.
+func (p *toolRetryPolicy) isRetryable(err error) bool {
+	if errors.Is(err, tool.ErrConfirmationRequired) || errors.Is(err, tool.ErrConfirmationRejected) {
+		return false
+	}
+	if len(p.retryable) == 0 {
+		return isTransientToolError(err)
+	}
+	msg := err.Error()
+	for _, re := range p.retryable {
+		if re.MatchString(msg) {
+			return true
+		}
+	}
+	return false
+}
+
+// isTransientToolError reports whether err is a timeout or a dropped or
+// refused connection, which a later attempt may not hit.
+func isTransientToolError(err error) bool {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.DeadlineExceeded),
+		errors.Is(err, io.ErrUnexpectedEOF),
+		errors.Is(err, syscall.ECONNREFUSED),
+		errors.Is(err, syscall.ECONNRESET):
+		return true
+	case errors.As(err, &netErr):
+		return netErr.Timeout()
+	}
+	return false
+}
+
+// backoff returns the delay before the given retry (1-based).
+func (p *toolRetryPolicy) backoff(retry int) time.Duration {
+	d := p.initialBackoff
+	for i := 1; i < retry && d < p.maxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, p.maxBackoff)
+}
+
+// toolRetryKey identifies the tools a retry policy applies to. An empty tool
+// name matches every tool of the server.
+type toolRetryKey struct {
+	server string
+	tool   string
+}
+
+// toolRetryPolicies resolves the retry policy of a failed tool call by MCP
+// server and tool name. Tool names are attributed to their server when the
+// server's toolset is listed, so policies on one server never apply to
+// another server's tools.
+type toolRetryPolicies struct {
+	policies map[toolRetryKey]*toolRetryPolicy
+
+	mu          sync.RWMutex
+	toolServers map[string]string
+}
+
+// collectToolRetryPolicies resolves the retry policies of the agent's MCP
+// servers. A policy falls back to the server's tool filter when it names no
+// tools, and to every tool of the server when there is no filter either.
+func collectToolRetryPolicies(agentConfig *adk.AgentConfig) (*toolRetryPolicies, error) {
+	out := &toolRetryPolicies{
+		policies:    make(map[toolRetryKey]*toolRetryPolicy),
+		toolServers: make(map[string]string),
+	}
+	add := func(server, prefix string, p *adk.ToolRetryPolicy, serverTools []string) error {
+		if p == nil {
+			return nil
+		}
+		resolved, err := newToolRetryPolicy(p)
+		if err != nil {
+			return err
+		}
+		names := p.Tools
+		if len(names) == 0 {
+			names = serverTools
+		}
+		if len(names) == 0 {
+			out.policies[toolRetryKey{server: server}] = resolved
+			return nil
+		}
+		for _, name := range names {
+			out.policies[toolRetryKey{server: server, tool: mcp.ToolName(prefix, name)}] = resolved
+		}
+		return nil
+	}
+	for _, ht := range agentConfig.HttpTools {
+		if err := add(ht.Params.Url, ht.ToolPrefix, ht.RetryPolicy, ht.Tools); err != nil {
+			return nil, err
+		}
+	}
+	for _, st := range agentConfig.SseTools {
+		if err := add(st.Params.Url, st.ToolPrefix, st.RetryPolicy, st.Tools); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+func (p *toolRetryPolicies) count() int {
+	return len(p.policies)
+}
+
+func (p *toolRetryPolicies) hasServer(server string) bool {
+	for key := range p.policies {
+		if key.server == server {
+			return true
+		}
+	}
+	return false
+}
+
+// track wraps the toolsets of servers with a retry policy so their tool
+// names are attributed to the server when the toolset is listed.
+func (p *toolRetryPolicies) track(toolsets []tool.Toolset) []tool.Toolset {
+	out := make([]tool.Toolset, 0, len(toolsets))
+	for _, ts := range toolsets {
+		if st, ok := ts.(mcp.ServerToolset); ok && p.hasServer(st.ServerURL()) {
+			ts = &retryTrackedToolset{Toolset: ts, server: st.ServerURL(), policies: p}
+		}
+		out = append(out, ts)
+	}
+	return out
+}
+
+func (p *toolRetryPolicies) setServer(toolName, server string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.toolServers[toolName] = server
+}
+
+// lookup returns the retry policy of a tool, or nil when its server has none.
+func (p *toolRetryPolicies) lookup(toolName string) *toolRetryPolicy {
+	p.mu.RLock()
+	server, ok := p.toolServers[toolName]
+	p.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+	if policy := p.policies[toolRetryKey{server: server, tool: toolName}]; policy != nil {
+		return policy
+	}
+	return p.policies[toolRetryKey{server: server}]
+}
+
+// retryTrackedToolset records which server each listed tool belongs to.
+type retryTrackedToolset struct {
+	tool.Toolset
+	server   string
+	policies *toolRetryPolicies
+}
+
+func (t *retryTrackedToolset) ServerURL() string { return t.server }
+
+func (t *retryTrackedToolset) Tools(ctx agent.ReadonlyContext) ([]tool.Tool, error) {
+	tools, err := t.Toolset.Tools(ctx)
+	for _, tl := range tools {
+		t.policies.setServer(tl.Name(), t.server)
+	}
+	return tools, err
+}
+
+// toolCallChain runs a retried attempt through the same callbacks the flow
+// runs around the first one: plugin callbacks first, then the agent's own,
+// stopping at the first callback that returns a result or an error. That
+// way a retry is authorized, approval-gated, audited and traced like any
+// other tool call.
+type toolCallChain struct {
+	before []llmagent.BeforeToolCallback
+	after  []llmagent.AfterToolCallback
+}
+
+func newToolCallChain(plugins []*adkplugin.Plugin, before []llmagent.BeforeToolCallback, after []llmagent.AfterToolCallback) *toolCallChain {
+	chain := &toolCallChain{}
+	for _, p := range plugins {
+		if cb := p.BeforeToolCallback(); cb != nil {
+			chain.before = append(chain.before, cb)
+		}
+		if cb := p.AfterToolCallback(); cb != nil {
+			chain.after = append(chain.after, cb)
+		}
+	}
+	chain.before = append(chain.before, before...)
+	chain.after = append(chain.after, after...)
+	return chain
+}
+
+// call runs the before-tool callbacks and, unless one of them short-circuits
+// the call, the tool itself. short reports a short-circuited call, whose
+// result must be returned as is.
+func (c *toolCallChain) call(ctx tool.Context, t tool.Tool, rt runnableTool, args map[string]any) (result map[string]any, short bool, err error) {
+	for _, cb := range c.before {
+		result, err := cb(ctx, t, args)
+		if err != nil {
+			return nil, true, err
+		}
+		if result != nil {
+			return result, true, nil
+		}
+	}
+	result, err = rt.Run(ctx, args)
+	return result, false, err
+}
+
+// finish runs the after-tool callbacks of an attempt that failed and is
+// about to be retried. The flow runs them for the final attempt.
+func (c *toolCallChain) finish(ctx tool.Context, t tool.Tool, args map[string]any, err error) {
+	for _, cb := range c.after {
+		if result, cbErr := cb(ctx, t, args, nil, err); result != nil || cbErr != nil {
+			return
+		}
+	}
+}
+
+// makeToolRetryCallback returns an OnToolErrorCallback that retries failed
+// tool calls according to their server's policy and, when the call still
+// fails, returns a structured error to the model with an error code and a
+// hint so it can correct itself. Each retry goes back through chain.
+func makeToolRetryCallback(policies *toolRetryPolicies, chain *toolCallChain, logger logr.Logger) llmagent.OnToolErrorCallback {
+	return func(ctx agent.ToolContext, t tool.Tool, args map[string]any, err error) (map[string]any, error) {
+		policy := policies.lookup(t.Name())
+		attempts := 1
+		if rt, ok := t.(runnableTool); ok && policy != nil {
+			for attempts < policy.maxAttempts && policy.isRetryable(err) {
+				delay := policy.backoff(attempts)
+				logger.Info("Retrying failed tool call",
+					"tool", t.Name(),
+					"functionCallID", ctx.FunctionCallID(),
+					"attempt", attempts+1,
+					"maxAttempts", policy.maxAttempts,
+					"backoff", delay.String(),
+					"error", err.Error())
+				chain.finish(ctx, t, args, err)
+				select {
+				case <-ctx.Done():
+					return toolErrorResponse(ToolErrorCodeFailed, ctx.Err(), attempts), nil
+				case <-time.After(delay):
+				}
+				attempts++
+				result, short, callErr := chain.call(ctx, t, rt, args)
+				if short {
+					return result, callErr
+				}
+				if callErr == nil {
+					return result, nil
+				}
+				err = callErr
+			}
+		}
+		code := ToolErrorCodeFailed
+		if attempts > 1 {
+			code = ToolErrorCodeRetriesExhausted
+		}
+		return toolErrorResponse(code, err, attempts), nil
+	}
+}
+
+// toolErrorResponse builds the function response returned to the model for
+// a failed tool call. The "error" key matches the ADK default so existing
+// consumers keep working.
+func toolErrorResponse(code string, err error, attempts int) map[string]any {
+	hint := "Check the tool arguments against the tool schema and try again with corrected arguments, or use a different approach."
+	if code == ToolErrorCodeRetriesExhausted {
+		hint = "The tool kept failing after automatic retries. Do not call it again with the same arguments; report the failure or try a different approach."
+	}
+	return map[string]any{
+		"error":      err.Error(),
+		"error_code": code,
+		"attempts":   attempts,
+		"hint":       hint,
+	}
+}
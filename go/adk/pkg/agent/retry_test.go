@@ -0,0 +1,168 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"syscall"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/kagent-dev/kagent/go/api/adk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	adkagent "google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/tool"
+)
+
+type retryTestToolContext struct {
+	adkagent.ToolContext
+	ctx context.Context
+}
+
+func (c retryTestToolContext) Done() <-chan struct{}  { return c.ctx.Done() }
+func (c retryTestToolContext) Err() error             { return c.ctx.Err() }
+func (c retryTestToolContext) FunctionCallID() string { return "call_1" }
+
+// flakyTool fails the first failures calls with err, then succeeds.
+type flakyTool struct {
+	tool.Tool
+	name     string
+	failures int
+	err      error
+	calls    int
+}
+
+func (f *flakyTool) Name() string { return f.name }
+
+func (f *flakyTool) Run(_ tool.Context, _ any) (map[string]any, error) {
+	f.calls++
+	if f.calls <= f.failures {
+		return nil, f.err
+	}
+	return map[string]any{"ok": true}, nil
+}
+
+func TestToolRetryCallback(t *testing.T) {
+	zero := 0.0
+	policies, err := collectToolRetryPolicies(&adk.AgentConfig{
+		HttpTools: []adk.HttpMcpServerConfig{
+			{
+				Params: adk.StreamableHTTPConnectionParams{Url: "http://k8s-tools"},
+				Tools:  []string{"get_pods", "delete_pod"},
+				RetryPolicy: &adk.ToolRetryPolicy{
+					MaxAttempts:     3,
+					InitialBackoff:  &zero,
+					RetryableErrors: []string{"(?i)timeout|unavailable"},
+					Tools:           []string{"get_pods"},
+				},
+			},
+			{
+				Params: adk.StreamableHTTPConnectionParams{Url: "http://other-tools"},
+			},
+		},
+	})
+	require.NoError(t, err)
+	policies.setServer("get_pods", "http://k8s-tools")
+	policies.setServer("delete_pod", "http://k8s-tools")
+	policies.setServer("list_files", "http://other-tools")
+
+	var beforeCalls, afterCalls int
+	chain := newToolCallChain(nil,
+		[]llmagent.BeforeToolCallback{func(tool.Context, tool.Tool, map[string]any) (map[string]any, error) {
+			beforeCalls++
+			return nil, nil
+		}},
+		[]llmagent.AfterToolCallback{func(tool.Context, tool.Tool, map[string]any, map[string]any, error) (map[string]any, error) {
+			afterCalls++
+			return nil, nil
+		}},
+	)
+	cb := makeToolRetryCallback(policies, chain, logr.Discard())
+	ctx := retryTestToolContext{ctx: t.Context()}
+
+	t.Run("retries through the callback chain until success", func(t *testing.T) {
+		beforeCalls, afterCalls = 0, 0
+		// calls starts at 1: the flow made the first, failed call before
+		// invoking the callback.
+		ft := &flakyTool{name: "get_pods", failures: 2, err: errors.New("upstream timeout"), calls: 1}
+		result, err := cb(ctx, ft, nil, errors.New("upstream timeout"))
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"ok": true}, result)
+		assert.Equal(t, 3, ft.calls)
+		// Both retries ran the before callbacks; the flow runs the after
+		// callbacks of the last attempt.
+		assert.Equal(t, 2, beforeCalls)
+		assert.Equal(t, 2, afterCalls)
+	})
+
+	t.Run("exhausts retries", func(t *testing.T) {
+		ft := &flakyTool{name: "get_pods", failures: 100, err: errors.New("service unavailable"), calls: 1}
+		result, err := cb(ctx, ft, nil, errors.New("service unavailable"))
+		require.NoError(t, err)
+		assert.Equal(t, ToolErrorCodeRetriesExhausted, result["error_code"])
+		assert.Equal(t, 3, result["attempts"])
+		assert.Equal(t, "service unavailable", result["error"])
+		assert.NotEmpty(t, result["hint"])
+	})
+
+	t.Run("non-retryable error is not retried", func(t *testing.T) {
+		ft := &flakyTool{name: "get_pods", failures: 100, calls: 1}
+		result, err := cb(ctx, ft, nil, errors.New("invalid namespace"))
+		require.NoError(t, err)
+		assert.Equal(t, ToolErrorCodeFailed, result["error_code"])
+		assert.Equal(t, 1, result["attempts"])
+		assert.Equal(t, 1, ft.calls)
+	})
+
+	t.Run("tool without policy gets structured error", func(t *testing.T) {
+		ft := &flakyTool{name: "delete_pod", failures: 100, calls: 1}
+		result, err := cb(ctx, ft, nil, errors.New("upstream timeout"))
+		require.NoError(t, err)
+		assert.Equal(t, ToolErrorCodeFailed, result["error_code"])
+		assert.Equal(t, 1, ft.calls)
+	})
+
+	t.Run("policy does not apply to another server", func(t *testing.T) {
+		ft := &flakyTool{name: "list_files", failures: 100, calls: 1}
+		result, err := cb(ctx, ft, nil, errors.New("upstream timeout"))
+		require.NoError(t, err)
+		assert.Equal(t, ToolErrorCodeFailed, result["error_code"])
+		assert.Equal(t, 1, ft.calls)
+	})
+
+	t.Run("short-circuiting callback stops retries", func(t *testing.T) {
+		denied := map[string]any{"error": "denied by policy"}
+		deny := newToolCallChain(nil, []llmagent.BeforeToolCallback{
+			func(tool.Context, tool.Tool, map[string]any) (map[string]any, error) { return denied, nil },
+		}, nil)
+		ft := &flakyTool{name: "get_pods", failures: 100, err: errors.New("upstream timeout"), calls: 1}
+		result, err := makeToolRetryCallback(policies, deny, logr.Discard())(ctx, ft, nil, errors.New("upstream timeout"))
+		require.NoError(t, err)
+		assert.Equal(t, denied, result)
+		assert.Equal(t, 1, ft.calls)
+	})
+}
+
+func TestToolRetryPolicy_TransientOnlyByDefault(t *testing.T) {
+	p, err := newToolRetryPolicy(&adk.ToolRetryPolicy{MaxAttempts: 3})
+	require.NoError(t, err)
+	assert.True(t, p.isRetryable(fmt.Errorf("failed to call MCP tool: %w", syscall.ECONNRESET)))
+	assert.True(t, p.isRetryable(fmt.Errorf("failed to call MCP tool: %w", context.DeadlineExceeded)))
+	assert.False(t, p.isRetryable(errors.New("Tool execution failed. Details: pod not found")))
+	assert.False(t, p.isRetryable(fmt.Errorf("error tool %q %w", "get_pods", tool.ErrConfirmationRequired)))
+}
+
+func TestToolRetryPolicy_Backoff(t *testing.T) {
+	initial, maxBackoff := 1.0, 5.0
+	p, err := newToolRetryPolicy(&adk.ToolRetryPolicy{MaxAttempts: 5, InitialBackoff: &initial, MaxBackoff: &maxBackoff})
+	require.NoError(t, err)
+	assert.Equal(t, "1s", p.backoff(1).String())
+	assert.Equal(t, "2s", p.backoff(2).String())
+	assert.Equal(t, "4s", p.backoff(3).String())
+	assert.Equal(t, "5s", p.backoff(4).String())
+
+	_, err = newToolRetryPolicy(&adk.ToolRetryPolicy{MaxAttempts: 2, RetryableErrors: []string{"("}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,103 @@
+package agent
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/kagent-dev/kagent/go/adk/pkg/mcp"
+	"github.com/kagent-dev/kagent/go/api/adk"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/tool"
+)
+
+// toolSourceLocal labels local tools in collision errors.
+const toolSourceLocal = "local tools"
+
+// resolvedToolset combines the agent's local tools and its MCP toolsets into
+// one deduplicated tool list. Local tools come first in the order they were
+// configured, followed by each MCP server's tools sorted by name, so the
+// declarations sent to the model are the same on every request.
+type resolvedToolset struct {
+	local    []tool.Tool
+	toolsets []tool.Toolset
+	policy   adk.ToolConflictPolicy
+	log      logr.Logger
+}
+
+func newResolvedToolset(local []tool.Tool, toolsets []tool.Toolset, policy adk.ToolConflictPolicy, log logr.Logger) (*resolvedToolset, error) {
+	switch policy {
+	case "":
+		policy = adk.ToolConflictPolicyError
+	case adk.ToolConflictPolicyError, adk.ToolConflictPolicyPreferLocal, adk.ToolConflictPolicyPreferMCP:
+	default:
+		return nil, fmt.Errorf("unknown tool conflict policy %q", policy)
+	}
+	return &resolvedToolset{local: local, toolsets: toolsets, policy: policy, log: log}, nil
+}
+
+func (r *resolvedToolset) Name() string { return "kagent_tools" }
+
+// resolvedTool is a tool together with where it came from.
+type resolvedTool struct {
+	tool   tool.Tool
+	source string
+	local  bool
+}
+
+func (r *resolvedToolset) Tools(ctx agent.ReadonlyContext) ([]tool.Tool, error) {
+	var all []resolvedTool
+	for _, t := range r.local {
+		all = append(all, resolvedTool{tool: t, source: toolSourceLocal, local: true})
+	}
+	for _, ts := range r.toolsets {
+		tools, err := ts.Tools(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tools of %s: %w", toolsetSource(ts), err)
+		}
+		tools = slices.Clone(tools)
+		slices.SortStableFunc(tools, func(a, b tool.Tool) int { return strings.Compare(a.Name(), b.Name()) })
+		for _, t := range tools {
+			all = append(all, resolvedTool{tool: t, source: toolsetSource(ts)})
+		}
+	}
+
+	kept := make(map[string]int, len(all))
+	var out []resolvedTool
+	for _, candidate := range all {
+		name := candidate.tool.Name()
+		i, seen := kept[name]
+		if !seen {
+			kept[name] = len(out)
+			out = append(out, candidate)
+			continue
+		}
+		existing := out[i]
+		if r.policy == adk.ToolConflictPolicyError || existing.source == candidate.source {
+			return nil, fmt.Errorf("tool %q is provided by both %s and %s; set a tool prefix on the MCP server or a tool conflict policy", name, existing.source, candidate.source)
+		}
+		// Between two MCP servers the first configured one wins under either
+		// preference; only a local tool can be replaced.
+		if r.policy == adk.ToolConflictPolicyPreferMCP && existing.local {
+			out[i] = candidate
+			r.log.V(1).Info("Dropping local tool shadowed by MCP tool", "tool", name, "source", candidate.source)
+			continue
+		}
+		r.log.V(1).Info("Dropping colliding tool", "tool", name, "source", candidate.source, "keptSource", existing.source)
+	}
+
+	tools := make([]tool.Tool, len(out))
+	for i, t := range out {
+		tools[i] = t.tool
+	}
+	return tools, nil
+}
+
+// toolsetSource describes a toolset in collision errors and logs.
+func toolsetSource(ts tool.Toolset) string {
+	if st, ok := ts.(mcp.ServerToolset); ok {
+		return "MCP server " + st.ServerURL()
+	}
+	return "toolset " + ts.Name()
+}
@@ -0,0 +1,84 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/kagent-dev/kagent/go/api/adk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	adkagent "google.golang.org/adk/agent"
+	"google.golang.org/adk/tool"
+)
+
+type namedTool struct {
+	tool.Tool
+	name   string
+	source string
+}
+
+func (n namedTool) Name() string { return n.name }
+
+type fakeServerToolset struct {
+	url   string
+	tools []tool.Tool
+}
+
+func (f *fakeServerToolset) Name() string      { return "mcp_tool_set" }
+func (f *fakeServerToolset) ServerURL() string { return f.url }
+func (f *fakeServerToolset) Tools(adkagent.ReadonlyContext) ([]tool.Tool, error) {
+	return f.tools, nil
+}
+
+func toolNames(tools []tool.Tool) []string {
+	names := make([]string, len(tools))
+	for i, t := range tools {
+		names[i] = t.Name()
+	}
+	return names
+}
+
+func TestResolvedToolset(t *testing.T) {
+	local := []tool.Tool{namedTool{name: "ask_user"}, namedTool{name: "get_pods"}}
+	k8s := &fakeServerToolset{url: "http://k8s", tools: []tool.Tool{namedTool{name: "get_pods"}, namedTool{name: "describe_pod"}}}
+	helm := &fakeServerToolset{url: "http://helm", tools: []tool.Tool{namedTool{name: "list_releases"}, namedTool{name: "describe_pod"}}}
+
+	tests := []struct {
+		policy  adk.ToolConflictPolicy
+		want    []string
+		wantErr string
+	}{
+		{policy: "", wantErr: `tool "get_pods" is provided by both local tools and MCP server http://k8s`},
+		{policy: adk.ToolConflictPolicyPreferLocal, want: []string{"ask_user", "get_pods", "describe_pod", "list_releases"}},
+		{policy: adk.ToolConflictPolicyPreferMCP, want: []string{"ask_user", "get_pods", "describe_pod", "list_releases"}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			ts, err := newResolvedToolset(local, []tool.Toolset{k8s, helm}, tt.policy, logr.Discard())
+			require.NoError(t, err)
+			got, err := ts.Tools(nil)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, toolNames(got))
+		})
+	}
+}
+
+func TestResolvedToolset_PreferMCPReplacesLocalTool(t *testing.T) {
+	localTool := namedTool{name: "get_pods", source: "local"}
+	mcpTool := namedTool{name: "get_pods", source: "mcp"}
+	ts, err := newResolvedToolset([]tool.Tool{localTool}, []tool.Toolset{&fakeServerToolset{url: "http://k8s", tools: []tool.Tool{mcpTool}}}, adk.ToolConflictPolicyPreferMCP, logr.Discard())
+	require.NoError(t, err)
+	got, err := ts.Tools(nil)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "mcp", got[0].(namedTool).source)
+}
+
+func TestResolvedToolset_UnknownPolicy(t *testing.T) {
+	_, err := newResolvedToolset(nil, nil, "prefer-remote", logr.Discard())
+	assert.ErrorContains(t, err, "unknown tool conflict policy")
+}
@@ -0,0 +1,39 @@
+package agent
+
+import (
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"github.com/kagent-dev/kagent/go/adk/pkg/toolargs"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/tool"
+)
+
+// ToolErrorCodeArgsRejected is returned to the model when a tool call's
+// arguments cannot be transformed by the agent's tool arg transforms.
+const ToolErrorCodeArgsRejected = "TOOL_ARGS_REJECTED"
+
+// makeToolArgTransformCallback returns a BeforeToolCallback that rewrites
+// the arguments of each tool call in place with transformer. It runs before
+// the policy and approval callbacks, so they see the arguments that will be
+// used. Calls whose arguments are rejected return a structured error to the
+// model and do not run.
+func makeToolArgTransformCallback(transformer *toolargs.Transformer, logger logr.Logger) llmagent.BeforeToolCallback {
+	return func(ctx agent.ToolContext, t tool.Tool, args map[string]any) (map[string]any, error) {
+		toolName := t.Name()
+		changed, err := transformer.Apply(toolName, args)
+		if err != nil {
+			logger.Info("Rejected tool call arguments", "tool", toolName, "functionCallID", ctx.FunctionCallID(), "error", err.Error())
+			return map[string]any{
+				"error":      fmt.Sprintf("Tool '%s' was not called: %v", toolName, err),
+				"error_code": ToolErrorCodeArgsRejected,
+				"hint":       "Fix the argument and call the tool again.",
+			}, nil
+		}
+		if len(changed) > 0 {
+			logger.V(1).Info("Transformed tool call arguments", "tool", toolName, "functionCallID", ctx.FunctionCallID(), "args", changed)
+		}
+		return nil, nil
+	}
+}
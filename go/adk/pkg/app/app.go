@@ -3,8 +3,10 @@ package app
 import (
 	"context"
 	"fmt"
+	"maps"
 	"net/http"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
@@ -15,8 +17,11 @@ import (
 	"github.com/kagent-dev/kagent/go/adk/pkg/a2a"
 	"github.com/kagent-dev/kagent/go/adk/pkg/a2a/server"
 	"github.com/kagent-dev/kagent/go/adk/pkg/auth"
+	"github.com/kagent-dev/kagent/go/adk/pkg/mcp"
 	"github.com/kagent-dev/kagent/go/adk/pkg/session"
 	"github.com/kagent-dev/kagent/go/adk/pkg/taskstore"
+	"github.com/kagent-dev/kagent/go/adk/pkg/tlsconfig"
+	"github.com/kagent-dev/kagent/go/api/httpsecurity"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	adkagent "google.golang.org/adk/agent"
@@ -65,9 +70,67 @@ type AppConfig struct {
 	// after the ones the builder creates (task store, push notifications, etc.).
 	HandlerOpts []a2asrv.RequestHandlerOption
 
+	// Handlers are additional HTTP endpoints served next to the A2A handler,
+	// keyed by mux pattern (e.g. admin endpoints).
+	Handlers map[string]http.Handler
+
+	// MaxConcurrentExecutions caps the number of executions run at once;
+	// further requests wait in a FIFO queue. Defaults to the
+	// KAGENT_MAX_CONCURRENT_EXECUTIONS env var. Zero means unlimited.
+	MaxConcurrentExecutions int
+
+	// RequestLimits bounds the size and shape of A2A requests. When nil, the
+	// limits are read from the KAGENT_A2A_* env vars.
+	RequestLimits *server.RequestLimits
+
+	// Stream configures SSE keep-alives and whether a disconnecting
+	// message/stream client cancels its task. When nil, it is read from the
+	// KAGENT_A2A_KEEPALIVE and KAGENT_A2A_CANCEL_ON_DISCONNECT env vars.
+	Stream *server.StreamOptions
+
+	// Security configures CORS and security headers. When nil, it is read
+	// from the KAGENT_CORS_* and KAGENT_SECURITY_HEADERS env vars.
+	Security *httpsecurity.Config
+
+	// TLS enables HTTPS with optional client-certificate verification. When
+	// nil, it is read from the KAGENT_TLS_* env vars.
+	TLS *tlsconfig.Config
+
+	// GRPC serves the A2A gRPC service next to JSON-RPC on the same port.
+	// Defaults to the KAGENT_A2A_GRPC env var.
+	GRPC bool
+
+	// OpenAPI serves an OpenAPI document at /openapi.json and a Swagger UI
+	// at /docs. Defaults to the KAGENT_A2A_OPENAPI env var.
+	OpenAPI bool
+
+	// OpenAICompat serves an OpenAI-compatible chat completions API at
+	// /v1/chat/completions. Defaults to the KAGENT_OPENAI_COMPAT env var.
+	OpenAICompat bool
+
 	// Agent is the ADK agent used to enrich the agent card with skills via
 	// adka2a.BuildAgentSkills. Optional; when nil, the card is used as-is.
 	Agent adkagent.Agent
+
+	// Plugins hook into every request; see Plugin for their ordering and
+	// error semantics. Their model and tool hooks must also be registered
+	// with the executor's runner through ADKPlugins.
+	Plugins []Plugin
+
+	// FeedbackExporters receive the feedback given on tasks through
+	// server.FeedbackPattern. The endpoint is served when KAgentURL is set,
+	// since feedback is stored with the tasks.
+	FeedbackExporters []server.FeedbackExporter
+
+	// ReadinessChecks are reported by /readyz. When the builder creates its
+	// own token service, a check of its tokens is added.
+	ReadinessChecks []server.ReadinessCheck
+
+	// Tools receives the MCP servers registered through ToolsPattern; pass
+	// the same value to the agent's toolsets. The endpoint is served when
+	// Tools and KAgentURL are set, since the servers are persisted in the
+	// task store, and the servers persisted earlier are registered by New.
+	Tools *mcp.DynamicToolsets
 }
 
 // KAgentApp wires an AgentExecutor with kagent infrastructure (auth, session,
@@ -94,28 +157,84 @@ func New(cfg AppConfig, executor a2asrv.AgentExecutor) (*KAgentApp, error) {
 		logger: log,
 	}
 
+	handlers := maps.Clone(cfg.Handlers)
+	readinessChecks := slices.Clone(cfg.ReadinessChecks)
+	if cfg.MaxConcurrentExecutions == 0 {
+		limit, err := maxConcurrentExecutionsFromEnv()
+		if err != nil {
+			return nil, err
+		}
+		cfg.MaxConcurrentExecutions = limit
+	}
+	if cfg.MaxConcurrentExecutions > 0 {
+		limited := newLimitedExecutor(executor, cfg.MaxConcurrentExecutions)
+		executor = limited
+		if handlers == nil {
+			handlers = map[string]http.Handler{}
+		}
+		if _, ok := handlers["/metrics"]; !ok {
+			handlers["/metrics"] = limited.metricsHandler()
+		}
+		log.Info("Limiting concurrent executions", "max", cfg.MaxConcurrentExecutions)
+	}
+	if len(cfg.Plugins) > 0 {
+		// Plugins wrap the limiter so rejected requests never queue.
+		executor = newPluginExecutor(executor, cfg.Plugins)
+	}
+
 	// Wire remote infrastructure when KAgentURL is configured.
 	var handlerOpts []a2asrv.RequestHandlerOption
 	if cfg.KAgentURL != "" {
+		sessionOpts, err := session.ClientOptionsFromEnv()
+		if err != nil {
+			return nil, err
+		}
 		httpClient := cfg.HTTPClient
 		if httpClient == nil {
-			tokenService := auth.NewKAgentTokenService(cfg.AppName)
+			audiences, err := auth.TokenAudiencesFromEnv()
+			if err != nil {
+				return nil, err
+			}
+			tokenService := auth.NewKAgentTokenServiceWithAudiences(cfg.AppName, audiences)
 			if err := tokenService.Start(context.Background()); err != nil {
 				log.Error(err, "Failed to start token service")
 			} else {
 				log.Info("Token service started")
 			}
 			app.tokenService = tokenService
+			readinessChecks = append(readinessChecks, server.ReadinessCheck{Name: "token", Check: tokenService.Health})
 			httpClient = newHTTPClient(tokenService)
 		}
 
-		sessionSvc := session.NewKAgentSessionService(cfg.KAgentURL, httpClient)
+		sessionSvc := session.NewKAgentSessionServiceWithOptions(cfg.KAgentURL, httpClient, sessionOpts)
 		app.sessionService = sessionSvc
 		log.Info("Using KAgent session service", "url", cfg.KAgentURL)
 
 		taskStore := taskstore.NewKAgentTaskStoreWithClient(cfg.KAgentURL, httpClient)
 		handlerOpts = append(handlerOpts, a2asrv.WithTaskStore(taskStore))
+		// Load tasks referenced by a message so executors can continue them.
+		handlerOpts = append(handlerOpts, a2asrv.WithRequestContextInterceptor(&a2asrv.ReferencedTasksLoader{Store: taskStore}))
 		log.Info("Using KAgent task store", "url", cfg.KAgentURL)
+
+		if _, ok := handlers[server.FeedbackPattern]; !ok {
+			if handlers == nil {
+				handlers = map[string]http.Handler{}
+			}
+			allowAnonymous, err := a2a.AllowAnonymousFromEnv()
+			if err != nil {
+				return nil, err
+			}
+			handlers[server.FeedbackPattern] = server.FeedbackHandler(taskStore, cfg.AppName, cfg.FeedbackExporters, allowAnonymous, log)
+		}
+		if cfg.Tools != nil {
+			if err := loadRegisteredServers(context.Background(), taskStore, cfg.AppName, cfg.Tools); err != nil {
+				return nil, err
+			}
+			if _, ok := handlers[ToolsPattern]; !ok {
+				handlers[ToolsPattern] = ToolsHandler(taskStore, cfg.AppName, cfg.Tools, log)
+			}
+			log.Info("Serving MCP server registration", "pattern", ToolsPattern, "registered", len(cfg.Tools.Servers()))
+		}
 	} else {
 		log.Info("No KAgentURL configured, using in-memory session and no task persistence")
 	}
@@ -131,10 +250,80 @@ func New(cfg AppConfig, executor a2asrv.AgentExecutor) (*KAgentApp, error) {
 		a2a.EnrichAgentCard(&cfg.AgentCard, cfg.Agent)
 	}
 
+	var limits server.RequestLimits
+	if cfg.RequestLimits != nil {
+		limits = *cfg.RequestLimits
+	} else {
+		var err error
+		if limits, err = server.RequestLimitsFromEnv(); err != nil {
+			return nil, err
+		}
+	}
+
+	var stream server.StreamOptions
+	if cfg.Stream != nil {
+		stream = *cfg.Stream
+	} else {
+		var err error
+		if stream, err = server.StreamOptionsFromEnv(); err != nil {
+			return nil, err
+		}
+	}
+
+	var security httpsecurity.Config
+	if cfg.Security != nil {
+		security = *cfg.Security
+	} else {
+		var err error
+		if security, err = httpsecurity.ConfigFromEnv(); err != nil {
+			return nil, err
+		}
+	}
+
+	var tlsConfig tlsconfig.Config
+	if cfg.TLS != nil {
+		tlsConfig = *cfg.TLS
+	} else {
+		var err error
+		if tlsConfig, err = tlsconfig.FromEnv(); err != nil {
+			return nil, err
+		}
+	}
+
+	if !cfg.GRPC {
+		var err error
+		if cfg.GRPC, err = server.GRPCEnabledFromEnv(); err != nil {
+			return nil, err
+		}
+	}
+
+	if !cfg.OpenAPI {
+		var err error
+		if cfg.OpenAPI, err = server.OpenAPIEnabledFromEnv(); err != nil {
+			return nil, err
+		}
+	}
+
+	if !cfg.OpenAICompat {
+		var err error
+		if cfg.OpenAICompat, err = server.OpenAICompatEnabledFromEnv(); err != nil {
+			return nil, err
+		}
+	}
+
 	serverConfig := server.ServerConfig{
 		Host:            cfg.Host,
 		Port:            cfg.Port,
 		ShutdownTimeout: cfg.ShutdownTimeout,
+		Handlers:        handlers,
+		Limits:          limits,
+		Stream:          stream,
+		Security:        security,
+		TLS:             tlsConfig,
+		GRPC:            cfg.GRPC,
+		OpenAPI:         cfg.OpenAPI,
+		OpenAICompat:    cfg.OpenAICompat,
+		ReadinessChecks: readinessChecks,
 	}
 
 	a2aServer, err := server.NewA2AServer(cfg.AgentCard, executor, log, serverConfig, handlerOpts...)
@@ -229,7 +418,7 @@ func newHTTPClient(tokenService *auth.KAgentTokenService) *http.Client {
 	if tokenService != nil {
 		return auth.NewHTTPClientWithToken(tokenService)
 	}
-	return &http.Client{Timeout: 30 * time.Second}
+	return &http.Client{Transport: auth.NewPooledTransport(), Timeout: 30 * time.Second}
 }
 
 // newDefaultLogger creates a production zap logger wrapped as logr.Logger.
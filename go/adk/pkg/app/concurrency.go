@@ -0,0 +1,189 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	a2atype "github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+	"github.com/a2aproject/a2a-go/a2asrv/eventqueue"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	// envMaxConcurrentExecutions caps the number of executions an agent runs
+	// at once. Zero or unset means unlimited.
+	envMaxConcurrentExecutions = "KAGENT_MAX_CONCURRENT_EXECUTIONS"
+
+	// metadataKeyQueuePosition is set on the status updates emitted while a
+	// request waits for a free execution slot. Position 1 runs next.
+	metadataKeyQueuePosition = "kagent_queue_position"
+)
+
+// maxConcurrentExecutionsFromEnv reads KAGENT_MAX_CONCURRENT_EXECUTIONS.
+func maxConcurrentExecutionsFromEnv() (int, error) {
+	v := strings.TrimSpace(os.Getenv(envMaxConcurrentExecutions))
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid %s %q", envMaxConcurrentExecutions, v)
+	}
+	return n, nil
+}
+
+// executionLimiter is a FIFO semaphore: waiters acquire a slot in arrival
+// order.
+type executionLimiter struct {
+	mu      sync.Mutex
+	max     int
+	running int
+	waiters []*limiterWaiter
+}
+
+type limiterWaiter struct {
+	// ready is closed when the waiter has been handed a slot.
+	ready chan struct{}
+	// moved is signaled whenever the waiter advances in the queue.
+	moved chan struct{}
+}
+
+func newExecutionLimiter(max int) *executionLimiter {
+	return &executionLimiter{max: max}
+}
+
+// acquire blocks until a slot is free or ctx is done. onQueued is called
+// with the current 1-based queue position whenever the caller has to wait
+// or moves up in the queue.
+func (l *executionLimiter) acquire(ctx context.Context, onQueued func(position int)) error {
+	l.mu.Lock()
+	if l.running < l.max && len(l.waiters) == 0 {
+		l.running++
+		l.mu.Unlock()
+		return nil
+	}
+	w := &limiterWaiter{ready: make(chan struct{}), moved: make(chan struct{}, 1)}
+	l.waiters = append(l.waiters, w)
+	position := len(l.waiters)
+	l.mu.Unlock()
+
+	onQueued(position)
+	for {
+		select {
+		case <-w.ready:
+			return nil
+		case <-w.moved:
+			l.mu.Lock()
+			position = slices.Index(l.waiters, w) + 1
+			l.mu.Unlock()
+			if position > 0 {
+				onQueued(position)
+			}
+		case <-ctx.Done():
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			select {
+			case <-w.ready:
+				// The slot was handed over concurrently; pass it on.
+				l.releaseLocked()
+			default:
+				if i := slices.Index(l.waiters, w); i >= 0 {
+					l.waiters = slices.Delete(l.waiters, i, i+1)
+					l.notifyMovedLocked(i)
+				}
+			}
+			return ctx.Err()
+		}
+	}
+}
+
+func (l *executionLimiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.releaseLocked()
+}
+
+// releaseLocked hands the slot to the first waiter, or frees it.
+func (l *executionLimiter) releaseLocked() {
+	if len(l.waiters) == 0 {
+		l.running--
+		return
+	}
+	next := l.waiters[0]
+	l.waiters = l.waiters[1:]
+	close(next.ready)
+	l.notifyMovedLocked(0)
+}
+
+// notifyMovedLocked tells every waiter from index from onwards that it
+// moved up in the queue.
+func (l *executionLimiter) notifyMovedLocked(from int) {
+	for _, w := range l.waiters[from:] {
+		select {
+		case w.moved <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (l *executionLimiter) queueDepth() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.waiters)
+}
+
+func (l *executionLimiter) inFlight() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.running
+}
+
+// limitedExecutor wraps an AgentExecutor so at most max executions run at
+// once. Excess requests wait in a FIFO queue and receive submitted status
+// updates carrying their queue position.
+type limitedExecutor struct {
+	a2asrv.AgentExecutor
+	limiter *executionLimiter
+}
+
+func newLimitedExecutor(executor a2asrv.AgentExecutor, max int) *limitedExecutor {
+	return &limitedExecutor{AgentExecutor: executor, limiter: newExecutionLimiter(max)}
+}
+
+func (e *limitedExecutor) Execute(ctx context.Context, reqCtx *a2asrv.RequestContext, queue eventqueue.Queue) error {
+	err := e.limiter.acquire(ctx, func(position int) {
+		queued := a2atype.NewStatusUpdateEvent(reqCtx, a2atype.TaskStateSubmitted, nil)
+		queued.Metadata = map[string]any{metadataKeyQueuePosition: position}
+		_ = queue.Write(ctx, queued)
+	})
+	if err != nil {
+		return fmt.Errorf("canceled while waiting for an execution slot: %w", err)
+	}
+	defer e.limiter.release()
+	return e.AgentExecutor.Execute(ctx, reqCtx, queue)
+}
+
+// metricsHandler serves the limiter's queue depth and in-flight executions
+// in the Prometheus text format.
+func (e *limitedExecutor) metricsHandler() http.Handler {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "kagent_agent_execution_queue_depth",
+			Help: "Number of requests waiting for an execution slot.",
+		}, func() float64 { return float64(e.limiter.queueDepth()) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "kagent_agent_executions_in_flight",
+			Help: "Number of executions currently running.",
+		}, func() float64 { return float64(e.limiter.inFlight()) }),
+	)
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
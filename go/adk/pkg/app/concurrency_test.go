@@ -0,0 +1,87 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExecutionLimiter_FIFO(t *testing.T) {
+	l := newExecutionLimiter(1)
+	if err := l.acquire(context.Background(), func(int) { t.Error("first acquire should not queue") }); err != nil {
+		t.Fatalf("acquire() error = %v", err)
+	}
+
+	order := make(chan int, 2)
+	positions := make(chan int, 4)
+	for i := 1; i <= 2; i++ {
+		go func() {
+			if err := l.acquire(context.Background(), func(p int) { positions <- p }); err != nil {
+				t.Errorf("acquire() error = %v", err)
+				return
+			}
+			order <- i
+			l.release()
+		}()
+		// Wait until the goroutine is queued so arrival order is deterministic.
+		if p := <-positions; p != i {
+			t.Fatalf("queue position = %d, want %d", p, i)
+		}
+	}
+
+	l.release()
+	if first, second := <-order, <-order; first != 1 || second != 2 {
+		t.Errorf("acquire order = %d, %d, want 1, 2", first, second)
+	}
+	if l.queueDepth() != 0 || l.inFlight() != 0 {
+		t.Errorf("queueDepth() = %d, inFlight() = %d, want 0, 0", l.queueDepth(), l.inFlight())
+	}
+}
+
+func TestExecutionLimiter_CancelWhileQueued(t *testing.T) {
+	l := newExecutionLimiter(1)
+	if err := l.acquire(context.Background(), func(int) {}); err != nil {
+		t.Fatalf("acquire() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.acquire(ctx, func(int) {}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("acquire() error = %v, want DeadlineExceeded", err)
+	}
+	if l.queueDepth() != 0 {
+		t.Errorf("queueDepth() = %d, want 0", l.queueDepth())
+	}
+
+	l.release()
+	if l.inFlight() != 0 {
+		t.Errorf("inFlight() = %d, want 0", l.inFlight())
+	}
+}
+
+func TestLimitedExecutor_Metrics(t *testing.T) {
+	e := newLimitedExecutor(&fakeExecutor{}, 2)
+	rec := httptest.NewRecorder()
+	e.metricsHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "kagent_agent_execution_queue_depth 0") {
+		t.Errorf("metrics output missing queue depth:\n%s", rec.Body.String())
+	}
+}
+
+func TestMaxConcurrentExecutionsFromEnv(t *testing.T) {
+	t.Setenv(envMaxConcurrentExecutions, "3")
+	if n, err := maxConcurrentExecutionsFromEnv(); err != nil || n != 3 {
+		t.Errorf("maxConcurrentExecutionsFromEnv() = %d, %v, want 3", n, err)
+	}
+	t.Setenv(envMaxConcurrentExecutions, "-1")
+	if _, err := maxConcurrentExecutionsFromEnv(); err == nil {
+		t.Error("expected error for negative limit")
+	}
+}
@@ -0,0 +1,119 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	a2atype "github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+	"github.com/a2aproject/a2a-go/a2asrv/eventqueue"
+	"google.golang.org/adk/agent/llmagent"
+	adkplugin "google.golang.org/adk/plugin"
+)
+
+// Plugin hooks into the requests a KAgentApp serves, so embedders can add
+// custom auth, billing or logging without forking the package. Every hook is
+// optional.
+//
+// Plugins run in the order they are registered in AppConfig.Plugins:
+//
+//   - BeforeRequest runs before the executor. The first error stops the
+//     chain: the executor does not run and the task fails with the error's
+//     text.
+//   - BeforeModel and BeforeTool run inside the Go ADK runner, before each
+//     model call and tool call, with the runner's callback semantics: the
+//     first hook returning a response, a result or an error short-circuits
+//     the call. They only take effect when the executor's runner.Config
+//     registers ADKPlugins, and run after kagent's own plugins.
+//   - AfterResponse runs once the executor returns, in reverse order, for
+//     every plugin whose BeforeRequest did not reject the request. It sees
+//     the error the request ended with, if any, and cannot change it.
+type Plugin struct {
+	// Name identifies the plugin to the ADK runner. Required when
+	// BeforeModel or BeforeTool is set.
+	Name string
+
+	BeforeRequest func(ctx context.Context, reqCtx *a2asrv.RequestContext) error
+	BeforeModel   llmagent.BeforeModelCallback
+	BeforeTool    llmagent.BeforeToolCallback
+	AfterResponse func(ctx context.Context, reqCtx *a2asrv.RequestContext, err error)
+}
+
+// ADKPlugins returns the model and tool hooks of plugins as Go ADK plugins,
+// to register with runner.Config.PluginConfig, e.g. through
+// runner.CreateRunnerConfig.
+func ADKPlugins(plugins []Plugin) ([]*adkplugin.Plugin, error) {
+	var adkPlugins []*adkplugin.Plugin
+	for _, p := range plugins {
+		if p.BeforeModel == nil && p.BeforeTool == nil {
+			continue
+		}
+		if p.Name == "" {
+			return nil, errors.New("plugins with model or tool hooks must have a name")
+		}
+		adkPlugin, err := adkplugin.New(adkplugin.Config{
+			Name:                p.Name,
+			BeforeModelCallback: p.BeforeModel,
+			BeforeToolCallback:  p.BeforeTool,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create ADK plugin %s: %w", p.Name, err)
+		}
+		adkPlugins = append(adkPlugins, adkPlugin)
+	}
+	return adkPlugins, nil
+}
+
+// pluginExecutor wraps an AgentExecutor with the request hooks of plugins.
+type pluginExecutor struct {
+	a2asrv.AgentExecutor
+	plugins []Plugin
+}
+
+func newPluginExecutor(executor a2asrv.AgentExecutor, plugins []Plugin) *pluginExecutor {
+	return &pluginExecutor{AgentExecutor: executor, plugins: plugins}
+}
+
+func (e *pluginExecutor) Execute(ctx context.Context, reqCtx *a2asrv.RequestContext, queue eventqueue.Queue) error {
+	var (
+		ran    int
+		result error
+	)
+	defer func() {
+		for i := ran - 1; i >= 0; i-- {
+			if after := e.plugins[i].AfterResponse; after != nil {
+				after(ctx, reqCtx, result)
+			}
+		}
+	}()
+	for _, p := range e.plugins {
+		if p.BeforeRequest != nil {
+			if result = p.BeforeRequest(ctx, reqCtx); result != nil {
+				return rejectRequest(ctx, reqCtx, queue, result)
+			}
+		}
+		ran++
+	}
+	result = e.AgentExecutor.Execute(ctx, reqCtx, queue)
+	return result
+}
+
+// rejectRequest ends the task of a request a plugin rejected with a final
+// failed status update carrying the reason, emitting the submitted event
+// first for a new task.
+func rejectRequest(ctx context.Context, reqCtx *a2asrv.RequestContext, queue eventqueue.Queue, reason error) error {
+	if reqCtx.StoredTask == nil {
+		submitted := a2atype.NewStatusUpdateEvent(reqCtx, a2atype.TaskStateSubmitted, reqCtx.Message)
+		if err := queue.Write(ctx, submitted); err != nil {
+			return fmt.Errorf("failed to write submitted event: %w", err)
+		}
+	}
+	failed := a2atype.NewStatusUpdateEvent(reqCtx, a2atype.TaskStateFailed,
+		a2atype.NewMessage(a2atype.MessageRoleAgent, a2atype.TextPart{Text: reason.Error()}))
+	failed.Final = true
+	if err := queue.Write(ctx, failed); err != nil {
+		return fmt.Errorf("failed to write failed event: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	a2atype "github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+	"github.com/a2aproject/a2a-go/a2asrv/eventqueue"
+	"google.golang.org/adk/tool"
+)
+
+// recordingQueue records the events written to it.
+type recordingQueue struct {
+	eventqueue.Queue
+	events []a2atype.Event
+}
+
+func (q *recordingQueue) Write(_ context.Context, event a2atype.Event) error {
+	q.events = append(q.events, event)
+	return nil
+}
+
+// tenantPlugin is a sample plugin: it rejects requests without a tenant and
+// bills the ones that ran.
+func tenantPlugin(billed map[string]int) Plugin {
+	return Plugin{
+		Name: "tenant",
+		BeforeRequest: func(_ context.Context, reqCtx *a2asrv.RequestContext) error {
+			if _, ok := reqCtx.Message.Metadata["tenant"].(string); !ok {
+				return errors.New("missing tenant")
+			}
+			return nil
+		},
+		AfterResponse: func(_ context.Context, reqCtx *a2asrv.RequestContext, err error) {
+			if err == nil {
+				billed[reqCtx.Message.Metadata["tenant"].(string)]++
+			}
+		},
+	}
+}
+
+func newRequest(metadata map[string]any) *a2asrv.RequestContext {
+	msg := a2atype.NewMessage(a2atype.MessageRoleUser, a2atype.TextPart{Text: "hi"})
+	msg.Metadata = metadata
+	return &a2asrv.RequestContext{Message: msg, TaskID: a2atype.NewTaskID(), ContextID: "ctx-1"}
+}
+
+func TestPluginExecutor_SamplePlugin(t *testing.T) {
+	billed := map[string]int{}
+	executor := newPluginExecutor(&fakeExecutor{}, []Plugin{tenantPlugin(billed)})
+
+	queue := &recordingQueue{}
+	if err := executor.Execute(context.Background(), newRequest(map[string]any{"tenant": "acme"}), queue); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if billed["acme"] != 1 || len(queue.events) != 0 {
+		t.Errorf("billed = %v, events = %d; want acme billed once and no events", billed, len(queue.events))
+	}
+
+	queue = &recordingQueue{}
+	if err := executor.Execute(context.Background(), newRequest(nil), queue); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if len(queue.events) != 2 {
+		t.Fatalf("rejected request wrote %d events, want submitted and failed", len(queue.events))
+	}
+	failed, ok := queue.events[1].(*a2atype.TaskStatusUpdateEvent)
+	if !ok || failed.Status.State != a2atype.TaskStateFailed || !failed.Final {
+		t.Fatalf("last event = %+v, want a final failed status update", queue.events[1])
+	}
+	if text := failed.Status.Message.Parts[0].(a2atype.TextPart).Text; text != "missing tenant" {
+		t.Errorf("failure message = %q, want the plugin's error", text)
+	}
+	if len(billed) != 1 {
+		t.Errorf("billed = %v, want the rejected request not billed", billed)
+	}
+}
+
+func TestPluginExecutor_Ordering(t *testing.T) {
+	var calls []string
+	hooks := func(name string, reject bool) Plugin {
+		return Plugin{
+			Name: name,
+			BeforeRequest: func(context.Context, *a2asrv.RequestContext) error {
+				calls = append(calls, "before "+name)
+				if reject {
+					return errors.New(name + " rejected")
+				}
+				return nil
+			},
+			AfterResponse: func(_ context.Context, _ *a2asrv.RequestContext, err error) {
+				if err != nil {
+					calls = append(calls, "after "+name+": "+err.Error())
+					return
+				}
+				calls = append(calls, "after "+name)
+			},
+		}
+	}
+
+	executor := newPluginExecutor(&fakeExecutor{}, []Plugin{hooks("a", false), hooks("b", false)})
+	if err := executor.Execute(context.Background(), newRequest(nil), &recordingQueue{}); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if want := []string{"before a", "before b", "after b", "after a"}; !slices.Equal(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+
+	calls = nil
+	executor = newPluginExecutor(&fakeExecutor{}, []Plugin{hooks("a", false), hooks("b", true), hooks("c", false)})
+	if err := executor.Execute(context.Background(), newRequest(nil), &recordingQueue{}); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if want := []string{"before a", "before b", "after a: b rejected"}; !slices.Equal(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+}
+
+func TestADKPlugins(t *testing.T) {
+	plugins, err := ADKPlugins([]Plugin{
+		{Name: "request-only", BeforeRequest: func(context.Context, *a2asrv.RequestContext) error { return nil }},
+		{Name: "tools", BeforeTool: func(_ tool.Context, _ tool.Tool, _ map[string]any) (map[string]any, error) { return nil, nil }},
+	})
+	if err != nil {
+		t.Fatalf("ADKPlugins() error = %v", err)
+	}
+	if len(plugins) != 1 || plugins[0].Name() != "tools" {
+		t.Errorf("ADKPlugins() = %v, want the tools plugin only", plugins)
+	}
+
+	if _, err := ADKPlugins([]Plugin{{BeforeTool: func(tool.Context, tool.Tool, map[string]any) (map[string]any, error) { return nil, nil }}}); err == nil {
+		t.Error("ADKPlugins() with an unnamed plugin error = nil")
+	}
+}
@@ -0,0 +1,182 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	a2atype "github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+	"github.com/go-logr/logr"
+	"github.com/kagent-dev/kagent/go/adk/pkg/a2a/server"
+	"github.com/kagent-dev/kagent/go/adk/pkg/mcp"
+)
+
+// ToolsPattern is the mux pattern of the endpoint registering MCP servers
+// while the agent runs.
+const ToolsPattern = "POST /api/v1/tools"
+
+// EnvToolRegistration enables ToolsPattern when set to "true". It is off
+// by default, since a registered server's tools run with the agent's
+// identity and are offered to every user of the agent.
+const EnvToolRegistration = "KAGENT_TOOL_REGISTRATION"
+
+// maxToolRegistrationBytes bounds the body of a tool registration request.
+const maxToolRegistrationBytes = 64 << 10
+
+// metadataKeyRegisteredServers is the task metadata key holding the
+// registered MCP servers in the task store.
+const metadataKeyRegisteredServers = "kagent_registered_servers"
+
+// ToolRegistrationEnabledFromEnv reports whether KAGENT_TOOL_REGISTRATION
+// enables the tool registration endpoint.
+func ToolRegistrationEnabledFromEnv() (bool, error) {
+	v := strings.TrimSpace(os.Getenv(EnvToolRegistration))
+	if v == "" {
+		return false, nil
+	}
+	enabled, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s %q", EnvToolRegistration, v)
+	}
+	return enabled, nil
+}
+
+// registeredServersTaskID is the ID of the task record holding the MCP
+// servers registered with appName. The task store is the agent's only
+// persistent store, so the servers are kept in the metadata of a task
+// outside any session.
+func registeredServersTaskID(appName string) a2atype.TaskID {
+	return a2atype.TaskID("kagent-tools-" + appName)
+}
+
+// loadRegisteredServers registers the servers persisted for appName with
+// tools. A missing record means none were registered yet.
+func loadRegisteredServers(ctx context.Context, store a2asrv.TaskStore, appName string, tools *mcp.DynamicToolsets) error {
+	task, _, err := store.Get(ctx, registeredServersTaskID(appName))
+	if errors.Is(err, a2atype.ErrTaskNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load registered MCP servers: %w", err)
+	}
+	raw, err := json.Marshal(task.Metadata[metadataKeyRegisteredServers])
+	if err != nil {
+		return fmt.Errorf("invalid registered MCP servers: %w", err)
+	}
+	var servers []mcp.RegisteredServer
+	if err := json.Unmarshal(raw, &servers); err != nil {
+		return fmt.Errorf("invalid registered MCP servers: %w", err)
+	}
+	for _, s := range servers {
+		if err := tools.Register(ctx, s); err != nil {
+			return fmt.Errorf("failed to register persisted MCP server: %w", err)
+		}
+	}
+	return nil
+}
+
+// ToolsHandler serves ToolsPattern: it validates the MCP server in the
+// body, registers it with tools, and persists the registered servers in
+// store so they are registered again when the agent restarts.
+func ToolsHandler(store a2asrv.TaskStore, appName string, tools *mcp.DynamicToolsets, logger logr.Logger) http.Handler {
+	h := &toolsHandler{store: store, appName: appName, tools: tools, logger: logger}
+	return server.DescribeHandler(h, map[string]server.OpenAPIOperation{
+		http.MethodPost: {
+			Summary: "Register an MCP server",
+			Description: `Registers {"name": "...", "http": {...}} or {"name": "...", "sse": {...}}, in the format of the agent config's http_tools and sse_tools. ` +
+				"Its tools are available from the next model call on. Registering a name again replaces the server. The user is read from the X-User-ID header.",
+			Responses: map[string]string{
+				"201": "The registered servers as JSON",
+				"400": "Invalid server",
+				"401": "No X-User-ID header",
+			},
+		},
+	})
+}
+
+type toolsHandler struct {
+	store   a2asrv.TaskStore
+	appName string
+	tools   *mcp.DynamicToolsets
+	logger  logr.Logger
+
+	// mu orders registrations, so the persisted servers match the
+	// registered ones.
+	mu sync.Mutex
+}
+
+func (h *toolsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		http.Error(w, "the request has no X-User-ID header", http.StatusUnauthorized)
+		return
+	}
+	var s mcp.RegisteredServer
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxToolRegistrationBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&s); err != nil {
+		http.Error(w, "invalid server: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := s.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	ctx := r.Context()
+	if err := h.tools.Register(ctx, s); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	servers := h.tools.Servers()
+	if err := h.save(ctx, servers); err != nil {
+		h.logger.Error(err, "Failed to persist registered MCP servers", "name", s.Name)
+		http.Error(w, "the server is registered until the agent restarts: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.logger.Info("MCP server registered through the API", "name", s.Name, "userID", userID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(servers)
+}
+
+// save stores servers in the metadata of the app's registered servers task.
+func (h *toolsHandler) save(ctx context.Context, servers []mcp.RegisteredServer) error {
+	id := registeredServersTaskID(h.appName)
+	prev, version, err := h.store.Get(ctx, id)
+	if errors.Is(err, a2atype.ErrTaskNotFound) {
+		prev, version, err = nil, a2atype.TaskVersionMissing, nil
+	}
+	if err != nil {
+		return err
+	}
+	raw, err := json.Marshal(servers)
+	if err != nil {
+		return err
+	}
+	var value []any
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return err
+	}
+	task := &a2atype.Task{ID: id, Status: a2atype.TaskStatus{State: a2atype.TaskStateCompleted}}
+	if prev != nil {
+		task.Metadata = maps.Clone(prev.Metadata)
+	}
+	if task.Metadata == nil {
+		task.Metadata = map[string]any{}
+	}
+	task.Metadata[metadataKeyRegisteredServers] = value
+	_, err = h.store.Save(ctx, task, nil, prev, version)
+	return err
+}
@@ -0,0 +1,86 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	a2atype "github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+	"github.com/go-logr/logr"
+	"github.com/kagent-dev/kagent/go/adk/pkg/mcp"
+)
+
+// memTaskStore keeps tasks in a map.
+type memTaskStore struct {
+	a2asrv.TaskStore
+	tasks map[a2atype.TaskID]*a2atype.Task
+}
+
+func (s *memTaskStore) Get(_ context.Context, id a2atype.TaskID) (*a2atype.Task, a2atype.TaskVersion, error) {
+	task, ok := s.tasks[id]
+	if !ok {
+		return nil, a2atype.TaskVersionMissing, a2atype.ErrTaskNotFound
+	}
+	return task, 1, nil
+}
+
+func (s *memTaskStore) Save(_ context.Context, task *a2atype.Task, _ a2atype.Event, _ *a2atype.Task, _ a2atype.TaskVersion) (a2atype.TaskVersion, error) {
+	s.tasks[task.ID] = task
+	return 1, nil
+}
+
+func TestToolsHandler(t *testing.T) {
+	store := &memTaskStore{tasks: map[a2atype.TaskID]*a2atype.Task{}}
+	tools := mcp.NewDynamicToolsets(false, logr.Discard())
+	mux := http.NewServeMux()
+	mux.Handle(ToolsPattern, ToolsHandler(store, "kagent__NS__helper", tools, logr.Discard()))
+	post := func(userID, body string) int {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/tools", strings.NewReader(body))
+		if userID != "" {
+			req.Header.Set("X-User-ID", userID)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	for _, tt := range []struct {
+		body string
+		want int
+	}{
+		{`{"name": "search", "http": {"params": {"url": "http://search:8080/mcp"}, "tools": ["query"]}}`, http.StatusCreated},
+		{`{"name": "docs", "sse": {"params": {"url": "https://docs/sse"}}}`, http.StatusCreated},
+		{`{"name": "search", "http": {"params": {"url": "http://search:8080/mcp"}, "unknown": true}}`, http.StatusBadRequest},
+		{`{"name": "search", "http": {"params": {"url": "ftp://search"}}}`, http.StatusBadRequest},
+		{`{"name": "search"}`, http.StatusBadRequest},
+	} {
+		if code := post("alice", tt.body); code != tt.want {
+			t.Errorf("POST %s = %d, want %d", tt.body, code, tt.want)
+		}
+	}
+	if code := post("", `{"name": "other", "sse": {"params": {"url": "https://other/sse"}}}`); code != http.StatusUnauthorized {
+		t.Errorf("anonymous POST = %d, want %d", code, http.StatusUnauthorized)
+	}
+	if got := len(tools.Servers()); got != 2 {
+		t.Fatalf("registered %d servers, want 2", got)
+	}
+
+	// The servers are registered again from the task store on restart.
+	restarted := mcp.NewDynamicToolsets(false, logr.Discard())
+	if err := loadRegisteredServers(context.Background(), store, "kagent__NS__helper", restarted); err != nil {
+		t.Fatalf("loadRegisteredServers() error = %v", err)
+	}
+	servers := restarted.Servers()
+	if len(servers) != 2 || servers[0].Name != "search" || servers[0].HTTP.Tools[0] != "query" || servers[1].SSE.Params.Url != "https://docs/sse" {
+		t.Errorf("loaded servers = %+v, want search and docs", servers)
+	}
+
+	empty := mcp.NewDynamicToolsets(false, logr.Discard())
+	if err := loadRegisteredServers(context.Background(), store, "kagent__NS__other", empty); err != nil || len(empty.Servers()) != 0 {
+		t.Errorf("loadRegisteredServers() of another app = %v, %v; want none", empty.Servers(), err)
+	}
+}
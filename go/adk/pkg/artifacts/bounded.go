@@ -0,0 +1,221 @@
+// Package artifacts provides the artifact service the runner hands to tools.
+// Artifacts are kept in memory under a total size cap: when a save pushes
+// the store over the cap, the artifacts of the least recently used sessions
+// are evicted.
+package artifacts
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"google.golang.org/adk/artifact"
+)
+
+// EnvMaxMB caps the total size of the artifacts kept in memory, in
+// megabytes.
+const EnvMaxMB = "KAGENT_ARTIFACT_MAX_MB"
+
+// DefaultMaxBytes is the cap used when EnvMaxMB is unset.
+const DefaultMaxBytes = 64 << 20
+
+// ErrTooLarge is returned when a single artifact is larger than the cap.
+var ErrTooLarge = errors.New("artifact exceeds the artifact store size limit")
+
+// userScopePrefix marks artifacts shared by all sessions of a user.
+const userScopePrefix = "user:"
+
+// scopeKey identifies the artifacts evicted together: those of one session,
+// or the user-scoped artifacts of one user.
+type scopeKey struct {
+	appName, userID, sessionID string
+}
+
+type scopeUsage struct {
+	key   scopeKey
+	bytes int64
+	// files maps a file name to the size of each of its versions.
+	files map[string]map[int64]int64
+	// deleteSessionID is a valid session ID to delete user-scoped files with.
+	deleteSessionID string
+	elem            *list.Element
+}
+
+// BoundedService is an in-memory artifact.Service holding at most maxBytes
+// of artifact data.
+type BoundedService struct {
+	artifact.Service
+
+	maxBytes int64
+
+	mu     sync.Mutex
+	total  int64
+	scopes map[scopeKey]*scopeUsage
+	// lru orders scopes from most to least recently used.
+	lru *list.List
+}
+
+// NewBoundedService returns a BoundedService capped at maxBytes.
+func NewBoundedService(maxBytes int64) *BoundedService {
+	return &BoundedService{
+		Service:  artifact.InMemoryService(),
+		maxBytes: maxBytes,
+		scopes:   make(map[scopeKey]*scopeUsage),
+		lru:      list.New(),
+	}
+}
+
+// NewServiceFromEnv returns a BoundedService capped by KAGENT_ARTIFACT_MAX_MB,
+// or by DefaultMaxBytes when it is unset.
+func NewServiceFromEnv() (*BoundedService, error) {
+	maxBytes := int64(DefaultMaxBytes)
+	if v := strings.TrimSpace(os.Getenv(EnvMaxMB)); v != "" {
+		mb, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || mb <= 0 {
+			return nil, fmt.Errorf("invalid %s %q: must be a positive number of megabytes", EnvMaxMB, v)
+		}
+		maxBytes = mb << 20
+	}
+	return NewBoundedService(maxBytes), nil
+}
+
+// Save stores the artifact, then evicts least recently used scopes until
+// the store is back under its cap. The scope being saved to is never
+// evicted, so the executor can still load what was just saved.
+func (s *BoundedService) Save(ctx context.Context, req *artifact.SaveRequest) (*artifact.SaveResponse, error) {
+	size := partSize(req)
+	if size > s.maxBytes {
+		return nil, fmt.Errorf("%w: %d bytes, limit is %d bytes", ErrTooLarge, size, s.maxBytes)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	resp, err := s.Service.Save(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	usage := s.touch(scopeOf(req.AppName, req.UserID, req.SessionID, req.FileName), req.SessionID)
+	versions := usage.files[req.FileName]
+	if versions == nil {
+		versions = make(map[int64]int64)
+		usage.files[req.FileName] = versions
+	}
+	s.add(usage, size-versions[resp.Version])
+	versions[resp.Version] = size
+
+	for s.total > s.maxBytes {
+		oldest := s.lru.Back()
+		if oldest == nil || oldest.Value.(*scopeUsage) == usage {
+			break
+		}
+		s.evict(ctx, oldest.Value.(*scopeUsage))
+	}
+	return resp, nil
+}
+
+// Load marks the scope as recently used.
+func (s *BoundedService) Load(ctx context.Context, req *artifact.LoadRequest) (*artifact.LoadResponse, error) {
+	s.mu.Lock()
+	if usage, ok := s.scopes[scopeOf(req.AppName, req.UserID, req.SessionID, req.FileName)]; ok {
+		s.lru.MoveToFront(usage.elem)
+	}
+	s.mu.Unlock()
+	return s.Service.Load(ctx, req)
+}
+
+// Delete releases the space of the deleted versions.
+func (s *BoundedService) Delete(ctx context.Context, req *artifact.DeleteRequest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.Service.Delete(ctx, req); err != nil {
+		return err
+	}
+	usage, ok := s.scopes[scopeOf(req.AppName, req.UserID, req.SessionID, req.FileName)]
+	if !ok {
+		return nil
+	}
+	versions := usage.files[req.FileName]
+	if req.Version != 0 {
+		s.add(usage, -versions[req.Version])
+		delete(versions, req.Version)
+	} else {
+		for _, size := range versions {
+			s.add(usage, -size)
+		}
+		versions = nil
+	}
+	if len(versions) == 0 {
+		delete(usage.files, req.FileName)
+	}
+	if len(usage.files) == 0 {
+		s.drop(usage)
+	}
+	return nil
+}
+
+// Size returns the total size of the stored artifacts.
+func (s *BoundedService) Size() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.total
+}
+
+func (s *BoundedService) touch(key scopeKey, sessionID string) *scopeUsage {
+	usage, ok := s.scopes[key]
+	if !ok {
+		usage = &scopeUsage{key: key, files: make(map[string]map[int64]int64), deleteSessionID: sessionID}
+		usage.elem = s.lru.PushFront(usage)
+		s.scopes[key] = usage
+		return usage
+	}
+	s.lru.MoveToFront(usage.elem)
+	return usage
+}
+
+func (s *BoundedService) add(usage *scopeUsage, delta int64) {
+	usage.bytes += delta
+	s.total += delta
+}
+
+func (s *BoundedService) evict(ctx context.Context, usage *scopeUsage) {
+	for name := range usage.files {
+		// Deleting from the in-memory store only fails on invalid requests,
+		// which were validated when the files were saved.
+		_ = s.Service.Delete(ctx, &artifact.DeleteRequest{
+			AppName:   usage.key.appName,
+			UserID:    usage.key.userID,
+			SessionID: usage.deleteSessionID,
+			FileName:  name,
+		})
+	}
+	s.total -= usage.bytes
+	s.drop(usage)
+}
+
+func (s *BoundedService) drop(usage *scopeUsage) {
+	s.lru.Remove(usage.elem)
+	delete(s.scopes, usage.key)
+}
+
+func scopeOf(appName, userID, sessionID, fileName string) scopeKey {
+	if strings.HasPrefix(fileName, userScopePrefix) {
+		sessionID = ""
+	}
+	return scopeKey{appName: appName, userID: userID, sessionID: sessionID}
+}
+
+func partSize(req *artifact.SaveRequest) int64 {
+	if req.Part == nil {
+		return 0
+	}
+	size := int64(len(req.Part.Text))
+	if req.Part.InlineData != nil {
+		size += int64(len(req.Part.InlineData.Data))
+	}
+	return size
+}
@@ -0,0 +1,92 @@
+package artifacts
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"google.golang.org/adk/artifact"
+	"google.golang.org/genai"
+)
+
+func save(t *testing.T, s *BoundedService, session, file string, size int) {
+	t.Helper()
+	_, err := s.Save(context.Background(), &artifact.SaveRequest{
+		AppName: "app", UserID: "user", SessionID: session, FileName: file,
+		Part: genai.NewPartFromText(strings.Repeat("x", size)),
+	})
+	if err != nil {
+		t.Fatalf("Save(%s/%s) error = %v", session, file, err)
+	}
+}
+
+func exists(s *BoundedService, session, file string) bool {
+	_, err := s.Load(context.Background(), &artifact.LoadRequest{AppName: "app", UserID: "user", SessionID: session, FileName: file})
+	return err == nil
+}
+
+func TestBoundedService_EvictsLeastRecentlyUsedSessions(t *testing.T) {
+	s := NewBoundedService(100)
+	save(t, s, "s1", "a.txt", 40)
+	save(t, s, "s2", "b.txt", 40)
+	// Using s1 makes s2 the least recently used session.
+	if !exists(s, "s1", "a.txt") {
+		t.Fatal("s1 artifact missing")
+	}
+	save(t, s, "s3", "c.txt", 40)
+
+	if exists(s, "s2", "b.txt") {
+		t.Error("least recently used session s2 was not evicted")
+	}
+	if !exists(s, "s1", "a.txt") || !exists(s, "s3", "c.txt") {
+		t.Error("recently used sessions were evicted")
+	}
+	if got := s.Size(); got != 80 {
+		t.Errorf("Size() = %d, want 80", got)
+	}
+}
+
+func TestBoundedService_NeverEvictsTheSavingSession(t *testing.T) {
+	s := NewBoundedService(100)
+	save(t, s, "s1", "v.txt", 60)
+	save(t, s, "s1", "v.txt", 60)
+	if !exists(s, "s1", "v.txt") {
+		t.Error("artifact of the saving session was evicted")
+	}
+}
+
+func TestBoundedService_RejectsOversizedArtifacts(t *testing.T) {
+	s := NewBoundedService(10)
+	_, err := s.Save(context.Background(), &artifact.SaveRequest{
+		AppName: "app", UserID: "user", SessionID: "s1", FileName: "big.txt",
+		Part: genai.NewPartFromText(strings.Repeat("x", 11)),
+	})
+	if !errors.Is(err, ErrTooLarge) {
+		t.Errorf("Save() error = %v, want ErrTooLarge", err)
+	}
+}
+
+func TestBoundedService_DeleteReleasesSpace(t *testing.T) {
+	s := NewBoundedService(100)
+	save(t, s, "s1", "a.txt", 30)
+	save(t, s, "s1", "a.txt", 20)
+	if err := s.Delete(context.Background(), &artifact.DeleteRequest{AppName: "app", UserID: "user", SessionID: "s1", FileName: "a.txt"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Size(); got != 0 {
+		t.Errorf("Size() after delete = %d, want 0", got)
+	}
+}
+
+func TestNewServiceFromEnv(t *testing.T) {
+	t.Setenv(EnvMaxMB, "")
+	s, err := NewServiceFromEnv()
+	if err != nil || s.maxBytes != DefaultMaxBytes {
+		t.Errorf("NewServiceFromEnv() = %v, %v, want the default cap", s, err)
+	}
+	t.Setenv(EnvMaxMB, "0")
+	if _, err := NewServiceFromEnv(); err == nil {
+		t.Error("expected an error for a zero cap")
+	}
+}
@@ -0,0 +1,280 @@
+// Package audit writes a tamper-evident, append-only log of tool
+// invocations. Each record carries the SHA-256 hash of the previous record,
+// so deleting or editing any line breaks the chain and is detected by
+// Verify.
+package audit
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	adkplugin "google.golang.org/adk/plugin"
+	"google.golang.org/adk/tool"
+)
+
+// EnvAuditLog is the environment variable that enables the audit log. Its
+// value is the path of the JSONL file records are appended to.
+const EnvAuditLog = "KAGENT_AUDIT_LOG"
+
+// genesisHash is the PrevHash of the first record in a log.
+const genesisHash = "0000000000000000000000000000000000000000000000000000000000000000"
+
+// Approval describes the human approval attached to a tool call, if any.
+type Approval struct {
+	Confirmed bool   `json:"confirmed"`
+	Hint      string `json:"hint,omitempty"`
+	Payload   any    `json:"payload,omitempty"`
+}
+
+// Record is a single audit log entry for a completed tool call.
+type Record struct {
+	Seq          uint64         `json:"seq"`
+	Timestamp    time.Time      `json:"timestamp"`
+	AppName      string         `json:"app_name,omitempty"`
+	AgentName    string         `json:"agent_name,omitempty"`
+	UserID       string         `json:"user_id,omitempty"`
+	SessionID    string         `json:"session_id,omitempty"`
+	InvocationID string         `json:"invocation_id,omitempty"`
+	ToolName     string         `json:"tool_name"`
+	ToolCallID   string         `json:"tool_call_id,omitempty"`
+	Args         map[string]any `json:"args,omitempty"`
+	Result       map[string]any `json:"result,omitempty"`
+	Error        string         `json:"error,omitempty"`
+	Approval     *Approval      `json:"approval,omitempty"`
+	DurationMs   int64          `json:"duration_ms"`
+	PrevHash     string         `json:"prev_hash"`
+	Hash         string         `json:"hash"`
+}
+
+// computeHash returns the hex SHA-256 of the record's JSON encoding with the
+// Hash field cleared.
+func (r Record) computeHash() (string, error) {
+	r.Hash = ""
+	data, err := json.Marshal(r)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Logger is an ADK plugin appending a Record for every tool call.
+type Logger struct {
+	logger logr.Logger
+
+	mu         sync.Mutex
+	file       *os.File
+	seq        uint64
+	lastHash   string
+	toolStarts map[string]time.Time // keyed by function call ID
+}
+
+// New opens (or creates) the audit log at path and resumes its hash chain.
+func New(path string, logger logr.Logger) (*Logger, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+	seq, lastHash, err := tail(path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log %s: %w", path, err)
+	}
+	return &Logger{
+		logger:     logger.WithName("audit"),
+		file:       f,
+		seq:        seq,
+		lastHash:   lastHash,
+		toolStarts: make(map[string]time.Time),
+	}, nil
+}
+
+// NewFromEnv returns a Logger when KAGENT_AUDIT_LOG is set, or nil otherwise.
+func NewFromEnv(logger logr.Logger) (*Logger, error) {
+	path := strings.TrimSpace(os.Getenv(EnvAuditLog))
+	if path == "" {
+		return nil, nil
+	}
+	return New(path, logger)
+}
+
+// ADKPlugin returns the Go ADK plugin registered with runner.PluginConfig.
+func (l *Logger) ADKPlugin() (*adkplugin.Plugin, error) {
+	return adkplugin.New(adkplugin.Config{
+		Name:               "kagent-audit-log",
+		BeforeToolCallback: l.BeforeToolCallback,
+		AfterToolCallback:  l.AfterToolCallback,
+		CloseFunc:          l.Close,
+	})
+}
+
+// BeforeToolCallback records the start time of a tool call.
+func (l *Logger) BeforeToolCallback(ctx tool.Context, _ tool.Tool, _ map[string]any) (map[string]any, error) {
+	l.mu.Lock()
+	l.toolStarts[ctx.FunctionCallID()] = time.Now()
+	l.mu.Unlock()
+	return nil, nil
+}
+
+// AfterToolCallback appends the audit record for a finished tool call.
+// Failing to write the audit log fails the tool call, so no tool runs
+// unaudited.
+func (l *Logger) AfterToolCallback(ctx tool.Context, t tool.Tool, args, result map[string]any, toolErr error) (map[string]any, error) {
+	now := time.Now()
+	rec := Record{
+		Timestamp:    now,
+		AppName:      ctx.AppName(),
+		AgentName:    ctx.AgentName(),
+		UserID:       ctx.UserID(),
+		SessionID:    ctx.SessionID(),
+		InvocationID: ctx.InvocationID(),
+		ToolName:     t.Name(),
+		ToolCallID:   ctx.FunctionCallID(),
+		Args:         args,
+		Result:       result,
+	}
+	if toolErr != nil {
+		rec.Error = toolErr.Error()
+	}
+	if c := ctx.ToolConfirmation(); c != nil {
+		rec.Approval = &Approval{Confirmed: c.Confirmed, Hint: c.Hint, Payload: c.Payload}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if start, ok := l.toolStarts[rec.ToolCallID]; ok {
+		rec.DurationMs = now.Sub(start).Milliseconds()
+		delete(l.toolStarts, rec.ToolCallID)
+	}
+	if err := l.appendLocked(rec); err != nil {
+		l.logger.Error(err, "Failed to write audit record", "tool", rec.ToolName, "functionCallID", rec.ToolCallID)
+		return nil, fmt.Errorf("failed to write audit record: %w", err)
+	}
+	return nil, nil
+}
+
+func (l *Logger) appendLocked(rec Record) error {
+	if l.file == nil {
+		return errors.New("audit log is closed")
+	}
+	rec.Seq = l.seq + 1
+	rec.PrevHash = l.lastHash
+	// Round-trip through JSON so the hash is computed over exactly what
+	// Verify will decode (map key order, number types, time precision).
+	raw, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	rec = Record{}
+	if err := json.Unmarshal(raw, &rec); err != nil {
+		return err
+	}
+	if rec.Hash, err = rec.computeHash(); err != nil {
+		return err
+	}
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	if _, err := l.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	if err := l.file.Sync(); err != nil {
+		return err
+	}
+	l.seq = rec.Seq
+	l.lastHash = rec.Hash
+	return nil
+}
+
+// Close closes the audit log file.
+func (l *Logger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
+
+// tail returns the sequence number and hash of the last record in the log
+// at path, or the genesis values when the log does not exist or is empty.
+func tail(path string) (uint64, string, error) {
+	var seq uint64
+	last := genesisHash
+	err := scan(path, func(_ int, rec Record) error {
+		seq, last = rec.Seq, rec.Hash
+		return nil
+	})
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, genesisHash, nil
+	}
+	return seq, last, err
+}
+
+// Verify checks the hash chain of the audit log at path and returns the
+// number of valid records. It fails on the first record whose hash, sequence
+// number or link to the previous record does not match.
+func Verify(path string) (int, error) {
+	prev := genesisHash
+	var seq uint64
+	count := 0
+	err := scan(path, func(lineNo int, rec Record) error {
+		if rec.PrevHash != prev {
+			return fmt.Errorf("line %d: chain broken: prev_hash %s does not match previous record hash %s", lineNo, rec.PrevHash, prev)
+		}
+		if rec.Seq != seq+1 {
+			return fmt.Errorf("line %d: sequence gap: got %d, want %d", lineNo, rec.Seq, seq+1)
+		}
+		want, err := rec.computeHash()
+		if err != nil {
+			return fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		if rec.Hash != want {
+			return fmt.Errorf("line %d: record hash mismatch, record was modified", lineNo)
+		}
+		prev, seq = rec.Hash, rec.Seq
+		count++
+		return nil
+	})
+	return count, err
+}
+
+func scan(path string, fn func(lineNo int, rec Record) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return fmt.Errorf("failed to parse audit log %s line %d: %w", path, lineNo, err)
+		}
+		if err := fn(lineNo, rec); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
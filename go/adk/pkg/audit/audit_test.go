@@ -0,0 +1,125 @@
+package audit
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/toolconfirmation"
+)
+
+type fakeToolContext struct {
+	agent.ToolContext
+	callID       string
+	confirmation *toolconfirmation.ToolConfirmation
+}
+
+func (f fakeToolContext) AppName() string        { return "kagent__NS__test" }
+func (f fakeToolContext) AgentName() string      { return "test_agent" }
+func (f fakeToolContext) UserID() string         { return "alice@example.com" }
+func (f fakeToolContext) SessionID() string      { return "session-1" }
+func (f fakeToolContext) InvocationID() string   { return "inv-1" }
+func (f fakeToolContext) FunctionCallID() string { return f.callID }
+
+func (f fakeToolContext) ToolConfirmation() *toolconfirmation.ToolConfirmation { return f.confirmation }
+
+type fakeTool struct {
+	tool.Tool
+	name string
+}
+
+func (f fakeTool) Name() string { return f.name }
+
+// record runs a tool call through the logger callbacks.
+func record(t *testing.T, l *Logger, ctx fakeToolContext, name string, args, result map[string]any, err error) {
+	t.Helper()
+	if _, e := l.BeforeToolCallback(ctx, fakeTool{name: name}, args); e != nil {
+		t.Fatal(e)
+	}
+	if _, e := l.AfterToolCallback(ctx, fakeTool{name: name}, args, result, err); e != nil {
+		t.Fatal(e)
+	}
+}
+
+func TestLogger_ChainAndVerify(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit", "tools.jsonl")
+	l, err := New(path, logr.Discard())
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	record(t, l, fakeToolContext{callID: "c1"}, "get_pods", map[string]any{"namespace": "default", "limit": 5}, map[string]any{"pods": []any{"a", "b"}}, nil)
+	record(t, l, fakeToolContext{callID: "c2", confirmation: &toolconfirmation.ToolConfirmation{Confirmed: true, Hint: "delete pod a?"}},
+		"delete_pod", map[string]any{"name": "a"}, nil, errors.New("forbidden"))
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reopening resumes the chain.
+	l, err = New(path, logr.Discard())
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	record(t, l, fakeToolContext{callID: "c3"}, "get_pods", nil, map[string]any{"pods": []any{}}, nil)
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := Verify(path)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if n != 3 {
+		t.Errorf("Verify() = %d records, want 3", n)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(data)
+	for _, want := range []string{`"user_id":"alice@example.com"`, `"approval":{"confirmed":true,"hint":"delete pod a?"}`, `"error":"forbidden"`, `"seq":3`} {
+		if !strings.Contains(content, want) {
+			t.Errorf("audit log missing %s", want)
+		}
+	}
+
+	t.Run("detects modified record", func(t *testing.T) {
+		tampered := filepath.Join(t.TempDir(), "tampered.jsonl")
+		if err := os.WriteFile(tampered, []byte(strings.Replace(content, `"name":"a"`, `"name":"b"`, 1)), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Verify(tampered); err == nil || !strings.Contains(err.Error(), "hash mismatch") {
+			t.Errorf("Verify(tampered) error = %v, want hash mismatch", err)
+		}
+	})
+
+	t.Run("detects deleted record", func(t *testing.T) {
+		lines := strings.SplitAfter(content, "\n")
+		truncated := filepath.Join(t.TempDir(), "deleted.jsonl")
+		if err := os.WriteFile(truncated, []byte(lines[0]+lines[2]), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Verify(truncated); err == nil || !strings.Contains(err.Error(), "chain broken") {
+			t.Errorf("Verify(deleted) error = %v, want chain broken", err)
+		}
+	})
+}
+
+func TestLogger_WriteFailureFailsToolCall(t *testing.T) {
+	l, err := New(filepath.Join(t.TempDir(), "tools.jsonl"), logr.Discard())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	_, err = l.AfterToolCallback(fakeToolContext{callID: "c1"}, fakeTool{name: "get_pods"}, nil, nil, nil)
+	if err == nil {
+		t.Error("AfterToolCallback should fail when the audit record cannot be written")
+	}
+}
@@ -2,10 +2,21 @@ package auth
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math/rand/v2"
 	"net/http"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
+
+	"github.com/kagent-dev/kagent/go/api/requestid"
 )
 
 type contextKey int
@@ -25,32 +36,89 @@ func userIDFromContext(ctx context.Context) string {
 
 const kagentTokenPath = "/var/run/secrets/tokens/kagent-token"
 
-// KAgentTokenService reads a k8s token from a file and reloads it periodically
+// DefaultAudience is the audience of the token the controller projects for
+// calls to the kagent control plane.
+const DefaultAudience = "kagent"
+
+// EnvTokenAudiences lists additional projected tokens as comma-separated
+// audience=path pairs, e.g. "mcp=/var/run/secrets/tokens/mcp-token", for
+// calls to MCP servers or remote agents that expect their own audience. An
+// entry for DefaultAudience replaces its path.
+const EnvTokenAudiences = "KAGENT_TOKEN_AUDIENCES"
+
+const (
+	maxTokenRefreshInterval = 60 * time.Second
+	minTokenRefreshInterval = 5 * time.Second
+)
+
+// cachedToken is the last token read for an audience.
+type cachedToken struct {
+	path  string
+	token string
+	// expiresAt is the exp claim of the token, or zero when the token is
+	// not a JWT with one.
+	expiresAt time.Time
+	// err is the error of the last read, if it failed.
+	err error
+}
+
+// KAgentTokenService reads projected k8s tokens from files, one per
+// audience, and reloads them before they expire
 type KAgentTokenService struct {
-	token    string
 	mu       sync.RWMutex
+	tokens   map[string]*cachedToken
 	appName  string
+	now      func() time.Time
 	stopChan chan struct{}
 	stopOnce sync.Once // guards close(stopChan) to prevent double-close panic
 }
 
-// NewKAgentTokenService creates a new KAgentTokenService
+// NewKAgentTokenService creates a new KAgentTokenService for the control
+// plane token only
 func NewKAgentTokenService(appName string) *KAgentTokenService {
+	return NewKAgentTokenServiceWithAudiences(appName, nil)
+}
+
+// NewKAgentTokenServiceWithAudiences creates a KAgentTokenService that also
+// loads the tokens in audiences, keyed by audience with the path of each
+// token file as value. The control plane token is always loaded.
+func NewKAgentTokenServiceWithAudiences(appName string, audiences map[string]string) *KAgentTokenService {
+	tokens := map[string]*cachedToken{DefaultAudience: {path: kagentTokenPath}}
+	for audience, path := range audiences {
+		tokens[audience] = &cachedToken{path: path}
+	}
 	return &KAgentTokenService{
+		tokens:   tokens,
 		appName:  appName,
+		now:      time.Now,
 		stopChan: make(chan struct{}),
 	}
 }
 
-// Start starts the token update loop
-func (s *KAgentTokenService) Start(ctx context.Context) error {
-	// Read initial token
-	token, err := s.readToken()
-	if err == nil {
-		s.mu.Lock()
-		s.token = token
-		s.mu.Unlock()
+// TokenAudiencesFromEnv parses KAGENT_TOKEN_AUDIENCES.
+func TokenAudiencesFromEnv() (map[string]string, error) {
+	v := strings.TrimSpace(os.Getenv(EnvTokenAudiences))
+	if v == "" {
+		return nil, nil
 	}
+	audiences := map[string]string{}
+	for entry := range strings.SplitSeq(v, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		audience, path, ok := strings.Cut(entry, "=")
+		audience, path = strings.TrimSpace(audience), strings.TrimSpace(path)
+		if !ok || audience == "" || path == "" {
+			return nil, fmt.Errorf("invalid %s entry %q: want audience=path", EnvTokenAudiences, entry)
+		}
+		audiences[audience] = path
+	}
+	return audiences, nil
+}
+
+// Start reads the tokens and starts the token update loop
+func (s *KAgentTokenService) Start(ctx context.Context) error {
+	s.refresh()
 
 	// Start refresh loop
 	go s.refreshTokenLoop(ctx)
@@ -63,67 +131,146 @@ func (s *KAgentTokenService) Stop() {
 	s.stopOnce.Do(func() { close(s.stopChan) })
 }
 
-// GetToken returns the current token
+// GetToken returns the current control plane token
 func (s *KAgentTokenService) GetToken() string {
+	return s.TokenFor(DefaultAudience)
+}
+
+// TokenFor returns the current token for audience, or "" when the audience
+// is not configured or its token could not be read.
+func (s *KAgentTokenService) TokenFor(audience string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if t, ok := s.tokens[audience]; ok {
+		return t.token
+	}
+	return ""
+}
+
+// Health reports an error for every audience whose token is missing or
+// expired.
+func (s *KAgentTokenService) Health() error {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-	return s.token
+	now := s.now()
+	var errs []error
+	for _, audience := range slices.Sorted(maps.Keys(s.tokens)) {
+		t := s.tokens[audience]
+		switch {
+		case t.token == "" && t.err != nil:
+			errs = append(errs, fmt.Errorf("%s token unavailable: %w", audience, t.err))
+		case t.token == "":
+			errs = append(errs, fmt.Errorf("%s token is empty", audience))
+		case !t.expiresAt.IsZero() && !now.Before(t.expiresAt):
+			errs = append(errs, fmt.Errorf("%s token expired at %s", audience, t.expiresAt.Format(time.RFC3339)))
+		}
+	}
+	return errors.Join(errs...)
 }
 
 // AddHeaders adds authorization and agent headers to an HTTP request
 func (s *KAgentTokenService) AddHeaders(req *http.Request) {
+	s.addHeaders(req, DefaultAudience)
+}
+
+func (s *KAgentTokenService) addHeaders(req *http.Request, audience string) {
 	req.Header.Set("X-Agent-Name", s.appName)
-	if token := s.GetToken(); token != "" {
+	if token := s.TokenFor(audience); token != "" {
 		req.Header.Set("Authorization", "Bearer "+token)
 	}
 	if userID := userIDFromContext(req.Context()); userID != "" {
 		req.Header.Set("X-User-Id", userID)
 	}
+	requestid.SetHeader(req.Context(), req)
 }
 
-// readToken reads the token from the file
-func (s *KAgentTokenService) readToken() (string, error) {
-	data, err := os.ReadFile(kagentTokenPath)
-	if err != nil {
-		return "", err
+// refresh rereads every token file. A failed read keeps the previous token,
+// which stays usable until it expires.
+func (s *KAgentTokenService) refresh() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, t := range s.tokens {
+		data, err := os.ReadFile(t.path)
+		if err != nil {
+			t.err = err
+			continue
+		}
+		t.token = strings.TrimSpace(string(data))
+		t.expiresAt = tokenExpiry(t.token)
+		t.err = nil
 	}
-	return string(data), nil
 }
 
-// refreshTokenLoop periodically refreshes the token
-func (s *KAgentTokenService) refreshTokenLoop(ctx context.Context) {
-	ticker := time.NewTicker(60 * time.Second)
-	defer ticker.Stop()
+// nextRefresh returns how long to wait before rereading the token files.
+// Files are polled more often as a token approaches expiry, every fifth of
+// its remaining lifetime between 5s and 60s, so a rotated token is picked up
+// well before the old one expires. Jitter of ±10% keeps replicas from
+// reading in lockstep.
+func (s *KAgentTokenService) nextRefresh() time.Duration {
+	s.mu.RLock()
+	now := s.now()
+	d := maxTokenRefreshInterval
+	for _, t := range s.tokens {
+		if !t.expiresAt.IsZero() {
+			d = min(d, max(t.expiresAt.Sub(now)/5, minTokenRefreshInterval))
+		}
+	}
+	s.mu.RUnlock()
+	return d - d/10 + rand.N(d/5+1)
+}
 
+// refreshTokenLoop refreshes the tokens until ctx is done or Stop is called
+func (s *KAgentTokenService) refreshTokenLoop(ctx context.Context) {
 	for {
+		timer := time.NewTimer(s.nextRefresh())
 		select {
 		case <-ctx.Done():
+			timer.Stop()
 			return
 		case <-s.stopChan:
+			timer.Stop()
 			return
-		case <-ticker.C:
-			token, err := s.readToken()
-			if err == nil {
-				s.mu.Lock()
-				currentToken := s.token
-				if token != currentToken {
-					s.token = token
-				}
-				s.mu.Unlock()
-			}
+		case <-timer.C:
+			s.refresh()
 		}
 	}
 }
 
+// tokenExpiry returns the exp claim of a JWT without verifying it, or zero
+// when token is not a JWT with an exp claim.
+func tokenExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}
+	}
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}
+	}
+	return time.Unix(claims.Exp, 0)
+}
+
 // RoundTripper wraps HTTP transport to add token headers
 type TokenRoundTripper struct {
 	base         http.RoundTripper
 	tokenService *KAgentTokenService
+	// audience selects the token; empty means DefaultAudience.
+	audience string
 }
 
 func (rt *TokenRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
 	if rt.tokenService != nil {
-		rt.tokenService.AddHeaders(req)
+		audience := rt.audience
+		if audience == "" {
+			audience = DefaultAudience
+		}
+		rt.tokenService.addHeaders(req, audience)
 	}
 	base := rt.base
 	if base == nil {
@@ -132,12 +279,48 @@ func (rt *TokenRoundTripper) RoundTrip(req *http.Request) (*http.Response, error
 	return base.RoundTrip(req)
 }
 
+// EnvHTTPMaxIdleConnsPerHost sets how many idle keep-alive connections to
+// each host, such as the control plane, are kept open. Defaults to 32;
+// invalid values are ignored.
+const EnvHTTPMaxIdleConnsPerHost = "KAGENT_HTTP_MAX_IDLE_CONNS_PER_HOST"
+
+const defaultMaxIdleConnsPerHost = 32
+
+// NewPooledTransport returns a copy of http.DefaultTransport that keeps
+// enough idle connections for the concurrent session, task and memory
+// requests of an agent. The default of two per host makes every burst of
+// requests open new connections.
+func NewPooledTransport() *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.MaxIdleConnsPerHost = defaultMaxIdleConnsPerHost
+	if n, err := strconv.Atoi(strings.TrimSpace(os.Getenv(EnvHTTPMaxIdleConnsPerHost))); err == nil && n > 0 {
+		t.MaxIdleConnsPerHost = n
+	}
+	t.MaxIdleConns = max(t.MaxIdleConns, t.MaxIdleConnsPerHost)
+	return t
+}
+
 // NewHTTPClientWithToken creates an HTTP client with token service integration
+// over a pooled transport.
 func NewHTTPClientWithToken(tokenService *KAgentTokenService) *http.Client {
 	return &http.Client{
 		Transport: &TokenRoundTripper{
-			base:         http.DefaultTransport,
+			base:         NewPooledTransport(),
+			tokenService: tokenService,
+		},
+		Timeout: 30 * time.Second,
+	}
+}
+
+// NewHTTPClientForAudience creates an HTTP client that authenticates with
+// the token of audience, for calls to services other than the control
+// plane.
+func NewHTTPClientForAudience(tokenService *KAgentTokenService, audience string) *http.Client {
+	return &http.Client{
+		Transport: &TokenRoundTripper{
+			base:         NewPooledTransport(),
 			tokenService: tokenService,
+			audience:     audience,
 		},
 		Timeout: 30 * time.Second,
 	}
@@ -0,0 +1,115 @@
+package auth
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testJWT(exp time.Time) string {
+	payload := base64.RawURLEncoding.EncodeToString(fmt.Appendf(nil, `{"aud":["kagent"],"exp":%d}`, exp.Unix()))
+	return "eyJhbGciOiJSUzI1NiJ9." + payload + ".c2lnbmF0dXJl"
+}
+
+func writeToken(t *testing.T, path, token string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(token), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestKAgentTokenService_Audiences(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	kagentPath, mcpPath := filepath.Join(dir, "kagent-token"), filepath.Join(dir, "mcp-token")
+	writeToken(t, kagentPath, testJWT(now.Add(time.Hour))+"\n")
+	writeToken(t, mcpPath, "opaque-mcp-token")
+
+	s := NewKAgentTokenServiceWithAudiences("agent", map[string]string{DefaultAudience: kagentPath, "mcp": mcpPath})
+	s.now = func() time.Time { return now }
+	s.refresh()
+
+	if got := s.GetToken(); got != testJWT(now.Add(time.Hour)) {
+		t.Errorf("GetToken() = %q, want the trimmed kagent token", got)
+	}
+	if got := s.TokenFor("mcp"); got != "opaque-mcp-token" {
+		t.Errorf("TokenFor(mcp) = %q", got)
+	}
+	if got := s.TokenFor("unknown"); got != "" {
+		t.Errorf("TokenFor(unknown) = %q, want empty", got)
+	}
+	if err := s.Health(); err != nil {
+		t.Errorf("Health() = %v, want nil", err)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "http://mcp.example", nil)
+	rt := &TokenRoundTripper{tokenService: s, audience: "mcp", base: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: r}, nil
+	})}
+	if _, err := rt.RoundTrip(req); err != nil {
+		t.Fatal(err)
+	}
+	if got := req.Header.Get("Authorization"); got != "Bearer opaque-mcp-token" {
+		t.Errorf("Authorization = %q, want the mcp token", got)
+	}
+
+	// A failed reread keeps the previous token until it expires.
+	os.Remove(kagentPath)
+	s.refresh()
+	if s.GetToken() == "" {
+		t.Error("a failed reread dropped the cached token")
+	}
+	now = now.Add(2 * time.Hour)
+	if err := s.Health(); err == nil || !strings.Contains(err.Error(), "kagent token expired") {
+		t.Errorf("Health() = %v, want the expired kagent token", err)
+	}
+}
+
+func TestKAgentTokenService_NextRefresh(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	path := filepath.Join(dir, "kagent-token")
+	s := NewKAgentTokenServiceWithAudiences("agent", map[string]string{DefaultAudience: path})
+	s.now = func() time.Time { return now }
+
+	for _, tc := range []struct {
+		expiresIn time.Duration
+		want      time.Duration
+	}{
+		{expiresIn: time.Hour, want: maxTokenRefreshInterval},
+		{expiresIn: 2 * time.Minute, want: 24 * time.Second},
+		{expiresIn: time.Second, want: minTokenRefreshInterval},
+	} {
+		writeToken(t, path, testJWT(now.Add(tc.expiresIn)))
+		s.refresh()
+		got := s.nextRefresh()
+		if got < tc.want-tc.want/10 || got > tc.want+tc.want/10 {
+			t.Errorf("expires in %v: nextRefresh() = %v, want %v ±10%%", tc.expiresIn, got, tc.want)
+		}
+	}
+}
+
+func TestTokenAudiencesFromEnv(t *testing.T) {
+	t.Setenv(EnvTokenAudiences, "mcp=/tokens/mcp, agents = /tokens/agents")
+	got, err := TokenAudiencesFromEnv()
+	if err != nil {
+		t.Fatalf("TokenAudiencesFromEnv() error = %v", err)
+	}
+	if len(got) != 2 || got["mcp"] != "/tokens/mcp" || got["agents"] != "/tokens/agents" {
+		t.Errorf("TokenAudiencesFromEnv() = %v", got)
+	}
+
+	t.Setenv(EnvTokenAudiences, "mcp")
+	if _, err := TokenAudiencesFromEnv(); err == nil {
+		t.Error("TokenAudiencesFromEnv() accepted an entry without a path")
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
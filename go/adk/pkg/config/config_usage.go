@@ -2,6 +2,8 @@ package config
 
 import (
 	"fmt"
+	"path"
+	"slices"
 
 	"github.com/go-logr/logr"
 	"github.com/kagent-dev/kagent/go/api/adk"
@@ -42,6 +44,44 @@ import (
 // Agent.Spec.A2AConfig.Skills -> Not in config.json, handled separately
 //   - Skills are added via SkillsPlugin in Python
 //   - In go-adk, skills are handled via KAGENT_SKILLS_FOLDER env var
+//   - Session workspaces are created under KAGENT_WORKSPACE_DIR (default <tmp>/kagent);
+//     point it at a shared volume when running several replicas
+//   - KAGENT_WORKSPACE_DRIVER selects the workspace backend ("local", "pvc" or "s3",
+//     which syncs each session with KAGENT_WORKSPACE_S3_BUCKET) and
+//     KAGENT_WORKSPACE_QUOTA_MB caps the size of a single session workspace
+//   - KAGENT_WORKSPACE_MAX_AGE / KAGENT_WORKSPACE_MAX_SIZE_MB enable a sweeper that
+//     deletes idle or oversized workspaces (and their sessions) every
+//     KAGENT_WORKSPACE_GC_INTERVAL; POST /admin/workspaces/gc runs it on demand
+//   - KAGENT_BASH_PERSISTENT_SHELL=true keeps one bash process per session so cd and
+//     exports carry over; KAGENT_BASH_SHELL_IDLE_TIMEOUT (default 10m) kills idle shells
+//   - KAGENT_PYTHON_PIP_ALLOWLIST lists the packages execute_python may pip install into
+//     the session's .venv (comma-separated); unset disables pip installs
+//   - KAGENT_GIT_AUTHOR_NAME / KAGENT_GIT_AUTHOR_EMAIL set the identity of git_commit
+//     commits; KAGENT_GIT_REQUIRE_APPROVAL=true asks the user before each commit
+//   - KAGENT_WORKSPACE_WATCH=true appends a "workspace" event to a session when files in its
+//     workspace change, after KAGENT_WORKSPACE_WATCH_DEBOUNCE (default 2s) of quiet;
+//     KAGENT_WORKSPACE_WATCH_INCLUDE / _EXCLUDE filter paths with comma-separated globs
+//
+// Control plane client
+//   - KAGENT_HTTP_MAX_IDLE_CONNS_PER_HOST (default 32) sizes the keep-alive pool
+//   - KAGENT_TOKEN_AUDIENCES loads extra projected tokens as comma-separated audience=path
+//     pairs next to the control plane token; tokens are reread more often as they near
+//     expiry, and /readyz answers 503 while one is missing or expired
+//
+// LLM wire logging
+//   - KAGENT_LLM_WIRE_LOG=true writes each provider request and response, with secrets
+//     redacted, to <KAGENT_LLM_WIRE_LOG_DIR>/<session>/.llm-wire/<task>/ (default dir:
+//     the session workspace directory); KAGENT_LLM_WIRE_LOG_SAMPLE_RATE (0-1, default 1)
+//     logs only a fraction of the calls
+//   - KAGENT_SESSION_MAX_RETRIES (default 3) retries transient session API failures
+//     with exponential backoff; POSTs are only retried when the request was not sent
+//   - KAGENT_SESSION_CACHE_TTL (default 2s, 0 disables) reuses fetched sessions
+//   - KAGENT_SESSION_BREAKER_THRESHOLD (default 5, 0 disables) consecutive failures make
+//     session calls fail fast for KAGENT_SESSION_BREAKER_COOLDOWN (default 30s)
+
+// pausedTaskStates are the A2A task states a task state rule may report; the
+// task is resumed by the next message either way.
+var pausedTaskStates = []string{"input-required", "auth-required"}
 
 // ValidateAgentConfigUsage validates that all AgentConfig fields are properly used
 // This is a helper function to ensure we're using all fields correctly
@@ -101,6 +141,17 @@ func ValidateAgentConfigUsageWithLogger(config *adk.AgentConfig, logger logr.Log
 			return fmt.Errorf("remote_agents[%d].name is required", i)
 		}
 	}
+	for i, rule := range config.TaskStateRules {
+		if rule.Tool == "" && rule.MetadataKey == "" {
+			return fmt.Errorf("task_state_rules[%d] requires tool or metadata_key", i)
+		}
+		if _, err := path.Match(rule.Tool, ""); err != nil {
+			return fmt.Errorf("task_state_rules[%d].tool: %w", i, err)
+		}
+		if !slices.Contains(pausedTaskStates, rule.State) {
+			return fmt.Errorf("task_state_rules[%d].state must be one of %v, got %q", i, pausedTaskStates, rule.State)
+		}
+	}
 
 	return nil
 }
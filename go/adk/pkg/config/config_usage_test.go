@@ -109,6 +109,36 @@ func TestValidateAgentConfigUsage_RemoteAgentMissingName(t *testing.T) {
 	}
 }
 
+func TestValidateAgentConfigUsage_TaskStateRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    adk.TaskStateRule
+		wantErr bool
+	}{
+		{name: "tool glob", rule: adk.TaskStateRule{Tool: "oauth_*", State: "auth-required"}},
+		{name: "metadata key", rule: adk.TaskStateRule{MetadataKey: "needs_login", State: "auth-required"}},
+		{name: "no matcher", rule: adk.TaskStateRule{State: "auth-required"}, wantErr: true},
+		{name: "bad glob", rule: adk.TaskStateRule{Tool: "[", State: "auth-required"}, wantErr: true},
+		{name: "terminal state", rule: adk.TaskStateRule{Tool: "x", State: "completed"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &adk.AgentConfig{
+				Model:          &adk.OpenAI{BaseModel: adk.BaseModel{Type: adk.ModelTypeOpenAI, Model: "gpt-4"}},
+				Instruction:    "test",
+				TaskStateRules: []adk.TaskStateRule{tt.rule},
+			}
+			err := ValidateAgentConfigUsage(config)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateAgentConfigUsage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "task_state_rules") {
+				t.Errorf("error should mention task_state_rules: %v", err)
+			}
+		})
+	}
+}
+
 func TestGetAgentConfigSummary_Nil(t *testing.T) {
 	s := GetAgentConfigSummary(nil)
 	if s != "AgentConfig: nil" {
@@ -30,6 +30,12 @@ type provider interface {
 	generate(ctx context.Context, texts []string) ([][]float32, error)
 }
 
+// Embedder generates embedding vectors, one per input text. Memory and
+// retrieval code depend on it rather than on a provider.
+type Embedder interface {
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
 // Client generates embeddings using a configured provider.
 type Client struct {
 	config *adk.EmbeddingConfig
@@ -70,6 +76,8 @@ func newProvider(cfg *adk.EmbeddingConfig, httpClient *http.Client) provider {
 		return &geminiProvider{config: cfg}
 	case "bedrock":
 		return &bedrockProvider{config: cfg}
+	case "voyage":
+		return &voyageProvider{config: cfg, httpClient: httpClient}
 	default: // "openai", "", and unknown providers
 		return &openAIProvider{config: cfg, httpClient: httpClient}
 	}
@@ -86,6 +94,13 @@ func (c *Client) Generate(ctx context.Context, texts []string) ([][]float32, err
 	return c.p.generate(ctx, texts)
 }
 
+// Embed implements Embedder.
+func (c *Client) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	return c.Generate(ctx, texts)
+}
+
+var _ Embedder = (*Client)(nil)
+
 type openAIProvider struct {
 	config     *adk.EmbeddingConfig
 	httpClient *http.Client
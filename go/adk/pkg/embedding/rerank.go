@@ -0,0 +1,93 @@
+package embedding
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+)
+
+// RerankResult scores one document against a query. Index refers to the
+// document's position in the reranked slice.
+type RerankResult struct {
+	Index int
+	Score float64
+}
+
+// Reranker orders documents by relevance to a query, most relevant first.
+type Reranker interface {
+	Rerank(ctx context.Context, query string, docs []string) ([]RerankResult, error)
+}
+
+// NewReranker creates a reranker for cfg. Voyage is reranked by its rerank
+// API; other providers have none, so their documents are ranked by the
+// cosine similarity of their embeddings to the query's.
+func NewReranker(cfg Config) (Reranker, error) {
+	if cfg.EmbeddingConfig == nil {
+		return nil, fmt.Errorf("embedding config is required")
+	}
+	if cfg.EmbeddingConfig.Provider == "voyage" {
+		if cfg.EmbeddingConfig.Model == "" {
+			return nil, fmt.Errorf("rerank model is required")
+		}
+		httpClient := cfg.HTTPClient
+		if httpClient == nil {
+			httpClient = http.DefaultClient
+		}
+		return &voyageReranker{config: cfg.EmbeddingConfig, httpClient: httpClient}, nil
+	}
+	client, err := New(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &EmbeddingReranker{Embedder: client}, nil
+}
+
+// EmbeddingReranker ranks documents by the cosine similarity of their
+// embeddings to the query's embedding.
+type EmbeddingReranker struct {
+	Embedder Embedder
+}
+
+// Rerank implements Reranker.
+func (r *EmbeddingReranker) Rerank(ctx context.Context, query string, docs []string) ([]RerankResult, error) {
+	if len(docs) == 0 {
+		return nil, nil
+	}
+	vectors, err := r.Embedder.Embed(ctx, append([]string{query}, docs...))
+	if err != nil {
+		return nil, err
+	}
+	if len(vectors) != len(docs)+1 {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", len(docs)+1, len(vectors))
+	}
+	results := make([]RerankResult, len(docs))
+	for i, vec := range vectors[1:] {
+		results[i] = RerankResult{Index: i, Score: cosineSimilarity(vectors[0], vec)}
+	}
+	sortResults(results)
+	return results, nil
+}
+
+// sortResults orders results by descending score, keeping the document
+// order for ties.
+func sortResults(results []RerankResult) {
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+}
+
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
@@ -0,0 +1,66 @@
+package embedding
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kagent-dev/kagent/go/api/adk"
+)
+
+type fixedEmbedder map[string][]float32
+
+func (e fixedEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, len(texts))
+	for i, text := range texts {
+		out[i] = e[text]
+	}
+	return out, nil
+}
+
+func TestEmbeddingReranker(t *testing.T) {
+	reranker := &EmbeddingReranker{Embedder: fixedEmbedder{
+		"pods":       {1, 0},
+		"about dogs": {0, 1},
+		"about pods": {0.9, 0.1},
+	}}
+	results, err := reranker.Rerank(context.Background(), "pods", []string{"about dogs", "about pods"})
+	if err != nil {
+		t.Fatalf("Rerank() error = %v", err)
+	}
+	if len(results) != 2 || results[0].Index != 1 || results[1].Index != 0 {
+		t.Fatalf("Rerank() = %+v, want document 1 ranked first", results)
+	}
+}
+
+func TestVoyageReranker(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rerank" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		var req struct {
+			Query     string   `json:"query"`
+			Documents []string `json:"documents"`
+			Model     string   `json:"model"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Model != "rerank-2" || len(req.Documents) != 2 {
+			t.Errorf("unexpected request %+v (%v)", req, err)
+		}
+		_, _ = w.Write([]byte(`{"data":[{"index":0,"relevance_score":0.2},{"index":1,"relevance_score":0.8}]}`))
+	}))
+	defer server.Close()
+
+	reranker, err := NewReranker(Config{EmbeddingConfig: &adk.EmbeddingConfig{Provider: "voyage", Model: "rerank-2", BaseUrl: server.URL}})
+	if err != nil {
+		t.Fatalf("NewReranker() error = %v", err)
+	}
+	results, err := reranker.Rerank(context.Background(), "pods", []string{"about dogs", "about pods"})
+	if err != nil {
+		t.Fatalf("Rerank() error = %v", err)
+	}
+	if len(results) != 2 || results[0] != (RerankResult{Index: 1, Score: 0.8}) {
+		t.Fatalf("Rerank() = %+v, want document 1 ranked first", results)
+	}
+}
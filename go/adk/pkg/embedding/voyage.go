@@ -0,0 +1,119 @@
+package embedding
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/kagent-dev/kagent/go/api/adk"
+)
+
+const defaultVoyageBaseURL = "https://api.voyageai.com/v1"
+
+type voyageProvider struct {
+	config     *adk.EmbeddingConfig
+	httpClient *http.Client
+}
+
+func (p *voyageProvider) generate(ctx context.Context, texts []string) ([][]float32, error) {
+	log := logr.FromContextOrDiscard(ctx)
+
+	var result openAIEmbeddingResponse
+	reqBody := map[string]any{
+		"input": texts,
+		"model": p.config.Model,
+	}
+	if err := postVoyage(ctx, p.httpClient, p.config.BaseUrl, "embeddings", reqBody, &result); err != nil {
+		return nil, err
+	}
+
+	// Voyage does not offer 768 dimensions; its models are trained so that a
+	// truncated, renormalized prefix is still a valid embedding.
+	embeddings := make([][]float32, len(texts))
+	for _, item := range result.Data {
+		if item.Index < 0 || item.Index >= len(texts) {
+			return nil, fmt.Errorf("voyage returned embedding for unknown input %d", item.Index)
+		}
+		embedding := item.Embedding
+		if len(embedding) > TargetDimension {
+			embedding = normalizeL2(embedding[:TargetDimension])
+		} else if len(embedding) < TargetDimension {
+			return nil, fmt.Errorf("embedding dimension %d is less than required %d", len(embedding), TargetDimension)
+		}
+		embeddings[item.Index] = embedding
+	}
+	log.Info("Successfully generated embeddings with Voyage", "count", len(embeddings))
+	return embeddings, nil
+}
+
+type voyageReranker struct {
+	config     *adk.EmbeddingConfig
+	httpClient *http.Client
+}
+
+func (r *voyageReranker) Rerank(ctx context.Context, query string, docs []string) ([]RerankResult, error) {
+	if len(docs) == 0 {
+		return nil, nil
+	}
+	var result struct {
+		Data []struct {
+			Index          int     `json:"index"`
+			RelevanceScore float64 `json:"relevance_score"`
+		} `json:"data"`
+	}
+	reqBody := map[string]any{
+		"query":     query,
+		"documents": docs,
+		"model":     r.config.Model,
+	}
+	if err := postVoyage(ctx, r.httpClient, r.config.BaseUrl, "rerank", reqBody, &result); err != nil {
+		return nil, err
+	}
+	results := make([]RerankResult, 0, len(result.Data))
+	for _, item := range result.Data {
+		results = append(results, RerankResult{Index: item.Index, Score: item.RelevanceScore})
+	}
+	sortResults(results)
+	return results, nil
+}
+
+// postVoyage sends reqBody to the Voyage API method and decodes the response
+// into out.
+func postVoyage(ctx context.Context, httpClient *http.Client, baseURL, method string, reqBody, out any) error {
+	if baseURL == "" {
+		baseURL = defaultVoyageBaseURL
+	}
+	bodyBytes, err := json.Marshal(reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+	url := fmt.Sprintf("%s/%s", strings.TrimSuffix(baseURL, "/"), method)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if apiKey := os.Getenv("VOYAGE_API_KEY"); apiKey != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
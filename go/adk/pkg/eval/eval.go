@@ -0,0 +1,288 @@
+// Package eval runs evaluation suites against an agent and reports pass
+// rates, latency and token usage as JSON or JUnit XML. Suites can target a
+// live agent over A2A, a model.LLM directly, or traces recorded by the
+// recorder package, so agent authors can gate prompt changes in CI.
+package eval
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+	"sigs.k8s.io/yaml"
+)
+
+// AssertionType identifies how an assertion is checked.
+type AssertionType string
+
+const (
+	AssertionContains    AssertionType = "contains"
+	AssertionNotContains AssertionType = "not_contains"
+	AssertionRegex       AssertionType = "regex"
+	AssertionLLMJudge    AssertionType = "llm_judge"
+)
+
+// Assertion is a single expectation on an agent response.
+type Assertion struct {
+	Type AssertionType `json:"type"`
+	// Value is the substring for contains/not_contains, the pattern for
+	// regex, and the grading criteria for llm_judge.
+	Value string `json:"value"`
+	// IgnoreCase makes contains/not_contains case-insensitive.
+	IgnoreCase bool `json:"ignore_case,omitempty"`
+}
+
+// Case is a single evaluation input with its expectations.
+type Case struct {
+	Name       string      `json:"name"`
+	Input      string      `json:"input"`
+	Assertions []Assertion `json:"assertions"`
+}
+
+// Suite is a named collection of cases.
+type Suite struct {
+	Name  string `json:"name"`
+	Cases []Case `json:"cases"`
+}
+
+// LoadSuite reads a suite from a YAML or JSON file and validates it.
+func LoadSuite(path string) (*Suite, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read eval suite %s: %w", path, err)
+	}
+	var s Suite
+	if err := yaml.UnmarshalStrict(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse eval suite %s: %w", path, err)
+	}
+	if err := s.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid eval suite %s: %w", path, err)
+	}
+	return &s, nil
+}
+
+// Validate checks that case names are unique and assertions are well formed.
+func (s *Suite) Validate() error {
+	seen := make(map[string]bool, len(s.Cases))
+	for i, c := range s.Cases {
+		if c.Name == "" {
+			return fmt.Errorf("case %d: name is required", i)
+		}
+		if seen[c.Name] {
+			return fmt.Errorf("case %q: duplicate name", c.Name)
+		}
+		seen[c.Name] = true
+		for j, a := range c.Assertions {
+			switch a.Type {
+			case AssertionContains, AssertionNotContains, AssertionLLMJudge:
+			case AssertionRegex:
+				if _, err := regexp.Compile(a.Value); err != nil {
+					return fmt.Errorf("case %q assertion %d: invalid regex: %w", c.Name, j, err)
+				}
+			default:
+				return fmt.Errorf("case %q assertion %d: unknown type %q", c.Name, j, a.Type)
+			}
+		}
+	}
+	return nil
+}
+
+// Response is what a Target produced for a case input.
+type Response struct {
+	Text             string
+	PromptTokens     int64
+	CompletionTokens int64
+	// PromptVersion is the agent's prompt version that answered, when the
+	// agent reports it.
+	PromptVersion string
+}
+
+// Target produces a response for a case.
+type Target interface {
+	Respond(ctx context.Context, c Case) (*Response, error)
+}
+
+// AssertionResult is the outcome of one assertion.
+type AssertionResult struct {
+	Assertion Assertion `json:"assertion"`
+	Passed    bool      `json:"passed"`
+	Message   string    `json:"message,omitempty"`
+}
+
+// CaseResult is the outcome of one case.
+type CaseResult struct {
+	Name             string            `json:"name"`
+	Passed           bool              `json:"passed"`
+	Error            string            `json:"error,omitempty"`
+	Output           string            `json:"output"`
+	DurationMs       int64             `json:"duration_ms"`
+	PromptTokens     int64             `json:"prompt_tokens,omitempty"`
+	CompletionTokens int64             `json:"completion_tokens,omitempty"`
+	PromptVersion    string            `json:"prompt_version,omitempty"`
+	Assertions       []AssertionResult `json:"assertions"`
+}
+
+// Report aggregates the results of a suite run.
+type Report struct {
+	Suite            string       `json:"suite"`
+	Total            int          `json:"total"`
+	Passed           int          `json:"passed"`
+	Failed           int          `json:"failed"`
+	PassRate         float64      `json:"pass_rate"`
+	DurationMs       int64        `json:"duration_ms"`
+	AvgLatencyMs     int64        `json:"avg_latency_ms"`
+	PromptTokens     int64        `json:"prompt_tokens"`
+	CompletionTokens int64        `json:"completion_tokens"`
+	Cases            []CaseResult `json:"cases"`
+}
+
+// Runner evaluates suites against a target.
+type Runner struct {
+	Target Target
+	// Judge grades llm_judge assertions. Cases using llm_judge fail when it
+	// is nil.
+	Judge model.LLM
+}
+
+// Run evaluates every case in the suite sequentially and aggregates the
+// results. Errors from the target are recorded on the case and do not stop
+// the run; only context cancellation does.
+func (r *Runner) Run(ctx context.Context, s *Suite) (*Report, error) {
+	report := &Report{Suite: s.Name, Total: len(s.Cases)}
+	start := time.Now()
+	for _, c := range s.Cases {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		res := r.runCase(ctx, c)
+		if res.Passed {
+			report.Passed++
+		} else {
+			report.Failed++
+		}
+		report.PromptTokens += res.PromptTokens
+		report.CompletionTokens += res.CompletionTokens
+		report.Cases = append(report.Cases, res)
+	}
+	report.DurationMs = time.Since(start).Milliseconds()
+	if report.Total > 0 {
+		report.PassRate = float64(report.Passed) / float64(report.Total)
+		var total int64
+		for _, c := range report.Cases {
+			total += c.DurationMs
+		}
+		report.AvgLatencyMs = total / int64(report.Total)
+	}
+	return report, nil
+}
+
+func (r *Runner) runCase(ctx context.Context, c Case) CaseResult {
+	res := CaseResult{Name: c.Name}
+	start := time.Now()
+	resp, err := r.Target.Respond(ctx, c)
+	res.DurationMs = time.Since(start).Milliseconds()
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	res.Output = resp.Text
+	res.PromptTokens = resp.PromptTokens
+	res.CompletionTokens = resp.CompletionTokens
+	res.PromptVersion = resp.PromptVersion
+
+	res.Passed = true
+	for _, a := range c.Assertions {
+		ar := r.check(ctx, c, a, resp.Text)
+		if !ar.Passed {
+			res.Passed = false
+		}
+		res.Assertions = append(res.Assertions, ar)
+	}
+	return res
+}
+
+func (r *Runner) check(ctx context.Context, c Case, a Assertion, output string) AssertionResult {
+	ar := AssertionResult{Assertion: a}
+	switch a.Type {
+	case AssertionContains, AssertionNotContains:
+		haystack, needle := output, a.Value
+		if a.IgnoreCase {
+			haystack, needle = strings.ToLower(haystack), strings.ToLower(needle)
+		}
+		found := strings.Contains(haystack, needle)
+		ar.Passed = found == (a.Type == AssertionContains)
+		if !ar.Passed {
+			if found {
+				ar.Message = fmt.Sprintf("output unexpectedly contains %q", a.Value)
+			} else {
+				ar.Message = fmt.Sprintf("output does not contain %q", a.Value)
+			}
+		}
+	case AssertionRegex:
+		re, err := regexp.Compile(a.Value)
+		if err != nil {
+			ar.Message = fmt.Sprintf("invalid regex: %v", err)
+			return ar
+		}
+		ar.Passed = re.MatchString(output)
+		if !ar.Passed {
+			ar.Message = fmt.Sprintf("output does not match /%s/", a.Value)
+		}
+	case AssertionLLMJudge:
+		ar.Passed, ar.Message = r.judge(ctx, c, a.Value, output)
+	default:
+		ar.Message = fmt.Sprintf("unknown assertion type %q", a.Type)
+	}
+	return ar
+}
+
+const judgeInstruction = `You are grading the response of an AI agent.
+Decide whether the response satisfies the criteria.
+Reply with PASS or FAIL on the first line, followed by a one-sentence reason.`
+
+// judge asks the judge model whether output satisfies criteria.
+func (r *Runner) judge(ctx context.Context, c Case, criteria, output string) (bool, string) {
+	if r.Judge == nil {
+		return false, "no judge model configured"
+	}
+	prompt := fmt.Sprintf("Criteria:\n%s\n\nUser input:\n%s\n\nAgent response:\n%s", criteria, c.Input, output)
+	req := &model.LLMRequest{
+		Contents: []*genai.Content{genai.NewContentFromText(prompt, genai.RoleUser)},
+		Config: &genai.GenerateContentConfig{
+			SystemInstruction: genai.NewContentFromText(judgeInstruction, genai.RoleUser),
+		},
+	}
+	var verdict strings.Builder
+	for resp, err := range r.Judge.GenerateContent(ctx, req, false) {
+		if err != nil {
+			return false, fmt.Sprintf("judge error: %v", err)
+		}
+		if resp.ErrorMessage != "" {
+			return false, fmt.Sprintf("judge error: %s", resp.ErrorMessage)
+		}
+		verdict.WriteString(contentText(resp.Content))
+	}
+	text := strings.TrimSpace(verdict.String())
+	first, reason, _ := strings.Cut(text, "\n")
+	passed := strings.HasPrefix(strings.ToUpper(strings.TrimSpace(first)), "PASS")
+	return passed, strings.TrimSpace(reason)
+}
+
+// contentText concatenates the non-thought text parts of c.
+func contentText(c *genai.Content) string {
+	if c == nil {
+		return ""
+	}
+	var b strings.Builder
+	for _, p := range c.Parts {
+		if p != nil && !p.Thought && p.Text != "" {
+			b.WriteString(p.Text)
+		}
+	}
+	return b.String()
+}
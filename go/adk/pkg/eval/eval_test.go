@@ -0,0 +1,211 @@
+package eval
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"iter"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// fakeLLM answers every request with a fixed text.
+type fakeLLM struct {
+	text string
+}
+
+func (f *fakeLLM) Name() string { return "fake" }
+
+func (f *fakeLLM) GenerateContent(_ context.Context, _ *model.LLMRequest, _ bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		yield(&model.LLMResponse{
+			Content:       genai.NewContentFromText(f.text, genai.RoleModel),
+			UsageMetadata: &genai.GenerateContentResponseUsageMetadata{PromptTokenCount: 3, CandidatesTokenCount: 2},
+		}, nil)
+	}
+}
+
+// mapTarget answers cases from a map keyed by case name.
+type mapTarget map[string]string
+
+func (m mapTarget) Respond(_ context.Context, c Case) (*Response, error) {
+	text, ok := m[c.Name]
+	if !ok {
+		return nil, errors.New("agent unavailable")
+	}
+	return &Response{Text: text, PromptTokens: 10, CompletionTokens: 5}, nil
+}
+
+func TestRunner_Run(t *testing.T) {
+	suite := &Suite{
+		Name: "smoke",
+		Cases: []Case{
+			{Name: "greets", Input: "hi", Assertions: []Assertion{
+				{Type: AssertionContains, Value: "HELLO", IgnoreCase: true},
+				{Type: AssertionNotContains, Value: "error"},
+			}},
+			{Name: "lists pods", Input: "list pods", Assertions: []Assertion{
+				{Type: AssertionRegex, Value: `\d+ pods`},
+			}},
+			{Name: "judged", Input: "explain", Assertions: []Assertion{
+				{Type: AssertionLLMJudge, Value: "The answer is polite."},
+			}},
+			{Name: "unreachable", Input: "x"},
+		},
+	}
+	r := &Runner{
+		Target: mapTarget{
+			"greets":     "Hello there",
+			"lists pods": "there are no pods",
+			"judged":     "Sure, happy to help.",
+		},
+		Judge: &fakeLLM{text: "PASS\nIt is polite."},
+	}
+
+	report, err := r.Run(context.Background(), suite)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if report.Total != 4 || report.Passed != 2 || report.Failed != 2 {
+		t.Fatalf("report totals = %d/%d/%d, want 4/2/2", report.Total, report.Passed, report.Failed)
+	}
+	if report.PassRate != 0.5 {
+		t.Errorf("PassRate = %v, want 0.5", report.PassRate)
+	}
+	if report.PromptTokens != 30 || report.CompletionTokens != 15 {
+		t.Errorf("tokens = %d/%d, want 30/15", report.PromptTokens, report.CompletionTokens)
+	}
+
+	byName := map[string]CaseResult{}
+	for _, c := range report.Cases {
+		byName[c.Name] = c
+	}
+	if !byName["greets"].Passed {
+		t.Errorf("greets should pass: %+v", byName["greets"])
+	}
+	if byName["lists pods"].Passed || byName["lists pods"].Assertions[0].Message == "" {
+		t.Errorf("lists pods should fail with a message: %+v", byName["lists pods"])
+	}
+	if !byName["judged"].Passed || byName["judged"].Assertions[0].Message != "It is polite." {
+		t.Errorf("judged should pass with judge reason: %+v", byName["judged"])
+	}
+	if byName["unreachable"].Passed || byName["unreachable"].Error != "agent unavailable" {
+		t.Errorf("unreachable should record target error: %+v", byName["unreachable"])
+	}
+}
+
+func TestRunner_JudgeWithoutModelFails(t *testing.T) {
+	r := &Runner{Target: mapTarget{"c": "ok"}}
+	report, err := r.Run(context.Background(), &Suite{Cases: []Case{
+		{Name: "c", Assertions: []Assertion{{Type: AssertionLLMJudge, Value: "anything"}}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Cases[0].Passed {
+		t.Error("llm_judge without a judge model must fail")
+	}
+}
+
+func TestLoadSuite(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "suite.yaml")
+	data := `name: k8s
+cases:
+  - name: pods
+    input: list pods in default
+    assertions:
+      - type: contains
+        value: pod
+      - type: regex
+        value: "^.+$"
+`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := LoadSuite(path)
+	if err != nil {
+		t.Fatalf("LoadSuite: %v", err)
+	}
+	if s.Name != "k8s" || len(s.Cases) != 1 || len(s.Cases[0].Assertions) != 2 {
+		t.Errorf("unexpected suite: %+v", s)
+	}
+
+	bad := filepath.Join(dir, "bad.yaml")
+	if err := os.WriteFile(bad, []byte("cases:\n  - name: a\n    assertions:\n      - type: fuzzy\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadSuite(bad); err == nil || !strings.Contains(err.Error(), "unknown type") {
+		t.Errorf("LoadSuite(bad) error = %v, want unknown type", err)
+	}
+}
+
+func TestTraceTarget(t *testing.T) {
+	dir := t.TempDir()
+	trace := `{"type":"llm_request","timestamp":"2025-01-01T00:00:00Z","invocation_id":"i","model":"m","contents":[{"role":"user","parts":[{"text":"hi"}]}]}
+{"type":"llm_response","timestamp":"2025-01-01T00:00:01Z","invocation_id":"i","response":{"role":"model","parts":[{"text":"recorded answer"}]},"usage":{"promptTokenCount":7,"candidatesTokenCount":4}}
+`
+	if err := os.WriteFile(filepath.Join(dir, "greet.jsonl"), []byte(trace), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := (&TraceTarget{Dir: dir}).Respond(context.Background(), Case{Name: "greet"})
+	if err != nil {
+		t.Fatalf("Respond: %v", err)
+	}
+	if resp.Text != "recorded answer" || resp.PromptTokens != 7 || resp.CompletionTokens != 4 {
+		t.Errorf("recorded response = %+v", resp)
+	}
+
+	resp, err = (&TraceTarget{Dir: dir, Model: &fakeLLM{text: "replayed answer"}}).Respond(context.Background(), Case{Name: "greet"})
+	if err != nil {
+		t.Fatalf("Respond with replay: %v", err)
+	}
+	if resp.Text != "replayed answer" {
+		t.Errorf("replayed response = %+v", resp)
+	}
+}
+
+func TestReport_Writers(t *testing.T) {
+	report := &Report{
+		Suite: "smoke", Total: 3, Passed: 1, Failed: 2, PassRate: 1.0 / 3, DurationMs: 1500,
+		Cases: []CaseResult{
+			{Name: "ok", Passed: true, DurationMs: 500, Output: "fine"},
+			{Name: "bad", DurationMs: 500, Assertions: []AssertionResult{
+				{Assertion: Assertion{Type: AssertionContains, Value: "x"}, Message: `output does not contain "x"`},
+			}},
+			{Name: "broken", DurationMs: 500, Error: "agent unavailable"},
+		},
+	}
+
+	var js bytes.Buffer
+	if err := report.WriteJSON(&js); err != nil {
+		t.Fatal(err)
+	}
+	var decoded Report
+	if err := json.Unmarshal(js.Bytes(), &decoded); err != nil || decoded.Total != 3 {
+		t.Errorf("JSON round trip failed: %v %+v", err, decoded)
+	}
+
+	var xmlOut bytes.Buffer
+	if err := report.WriteJUnit(&xmlOut); err != nil {
+		t.Fatal(err)
+	}
+	got := xmlOut.String()
+	for _, want := range []string{
+		`<testsuite name="smoke" tests="3" failures="1" errors="1" time="1.500">`,
+		`<testcase name="ok" classname="smoke" time="0.500">`,
+		`<failure message="1 assertion(s) failed">contains: output does not contain &#34;x&#34;</failure>`,
+		`<error message="agent unavailable"></error>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("JUnit output missing %q:\n%s", want, got)
+		}
+	}
+}
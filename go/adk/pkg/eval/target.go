@@ -0,0 +1,190 @@
+package eval
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	a2atype "github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2aclient"
+	"github.com/a2aproject/a2a-go/a2aclient/agentcard"
+	"github.com/kagent-dev/kagent/go/adk/pkg/recorder"
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// A2ATarget sends each case input to a live agent over A2A.
+type A2ATarget struct {
+	client *a2aclient.Client
+	// PromptVersion pins the agent's prompt version for every case, to
+	// evaluate versions side by side. Empty lets the agent choose.
+	PromptVersion string
+}
+
+// NewA2ATarget resolves the agent card at baseURL and creates an A2A client.
+func NewA2ATarget(ctx context.Context, baseURL string, httpClient *http.Client) (*A2ATarget, error) {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	card, err := agentcard.NewResolver(httpClient).Resolve(ctx, baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve agent card for %s: %w", baseURL, err)
+	}
+	client, err := a2aclient.NewFromCard(ctx, card, a2aclient.WithJSONRPCTransport(httpClient))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create A2A client for %s: %w", baseURL, err)
+	}
+	return &A2ATarget{client: client}, nil
+}
+
+// Respond implements Target. Each case runs in a fresh A2A context.
+func (t *A2ATarget) Respond(ctx context.Context, c Case) (*Response, error) {
+	msg := a2atype.NewMessage(a2atype.MessageRoleUser, a2atype.TextPart{Text: c.Input})
+	if t.PromptVersion != "" {
+		msg.Metadata = map[string]any{"kagent_prompt_version": t.PromptVersion}
+	}
+	result, err := t.client.SendMessage(ctx, &a2atype.MessageSendParams{Message: msg})
+	if err != nil {
+		return nil, fmt.Errorf("A2A request failed: %w", err)
+	}
+	switch r := result.(type) {
+	case *a2atype.Message:
+		return &Response{Text: a2aPartsText(r.Parts)}, nil
+	case *a2atype.Task:
+		if r.Status.State == a2atype.TaskStateFailed {
+			text := ""
+			if r.Status.Message != nil {
+				text = a2aPartsText(r.Status.Message.Parts)
+			}
+			return nil, fmt.Errorf("task failed: %s", text)
+		}
+		resp := &Response{}
+		var texts []string
+		for _, a := range r.Artifacts {
+			if text := a2aPartsText(a.Parts); text != "" {
+				texts = append(texts, text)
+			}
+		}
+		resp.Text = strings.Join(texts, "\n")
+		if resp.Text == "" && r.Status.Message != nil {
+			resp.Text = a2aPartsText(r.Status.Message.Parts)
+		}
+		if usage := usageFromMetadata(r.Metadata); usage != nil {
+			resp.PromptTokens = int64(usage.PromptTokenCount)
+			resp.CompletionTokens = int64(usage.CandidatesTokenCount)
+		}
+		resp.PromptVersion, _ = r.Metadata["kagent_prompt_version"].(string)
+		return resp, nil
+	default:
+		return nil, fmt.Errorf("agent returned no result")
+	}
+}
+
+func a2aPartsText(parts a2atype.ContentParts) string {
+	var texts []string
+	for _, part := range parts {
+		if tp, ok := part.(a2atype.TextPart); ok && tp.Text != "" {
+			texts = append(texts, tp.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// usageFromMetadata decodes kagent_usage_metadata from task metadata when
+// the agent reports it.
+func usageFromMetadata(md map[string]any) *genai.GenerateContentResponseUsageMetadata {
+	raw, ok := md["kagent_usage_metadata"]
+	if !ok {
+		return nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil
+	}
+	var usage genai.GenerateContentResponseUsageMetadata
+	if err := json.Unmarshal(data, &usage); err != nil {
+		return nil
+	}
+	return &usage
+}
+
+// ModelTarget sends each case input straight to a model, bypassing the
+// agent. Useful for comparing models or system prompts.
+type ModelTarget struct {
+	Model             model.LLM
+	SystemInstruction string
+}
+
+// Respond implements Target.
+func (t *ModelTarget) Respond(ctx context.Context, c Case) (*Response, error) {
+	req := &model.LLMRequest{
+		Contents: []*genai.Content{genai.NewContentFromText(c.Input, genai.RoleUser)},
+		Config:   &genai.GenerateContentConfig{},
+	}
+	if t.SystemInstruction != "" {
+		req.Config.SystemInstruction = genai.NewContentFromText(t.SystemInstruction, genai.RoleUser)
+	}
+	return collectModelResponse(ctx, t.Model, req)
+}
+
+// TraceTarget answers cases from traces recorded by the recorder package.
+// The trace for a case is read from <Dir>/<case name>.jsonl. When Model is
+// nil the recorded final response is returned as is; otherwise the last
+// recorded LLM request is replayed against Model.
+type TraceTarget struct {
+	Dir   string
+	Model model.LLM
+}
+
+// Respond implements Target.
+func (t *TraceTarget) Respond(ctx context.Context, c Case) (*Response, error) {
+	trace, err := recorder.Load(filepath.Join(t.Dir, c.Name+".jsonl"))
+	if err != nil {
+		return nil, err
+	}
+	if t.Model != nil {
+		reqs := trace.LLMRequests()
+		if len(reqs) == 0 {
+			return nil, fmt.Errorf("trace for case %q has no LLM requests", c.Name)
+		}
+		return collectModelResponse(ctx, t.Model, reqs[len(reqs)-1])
+	}
+
+	resp := &Response{}
+	for _, rec := range trace.Records {
+		if rec.Type != recorder.RecordTypeLLMResponse {
+			continue
+		}
+		if text := contentText(rec.Response); text != "" {
+			resp.Text = text
+		}
+		if rec.Usage != nil {
+			resp.PromptTokens += int64(rec.Usage.PromptTokenCount)
+			resp.CompletionTokens += int64(rec.Usage.CandidatesTokenCount)
+		}
+	}
+	return resp, nil
+}
+
+func collectModelResponse(ctx context.Context, llm model.LLM, req *model.LLMRequest) (*Response, error) {
+	out := &Response{}
+	var text strings.Builder
+	for resp, err := range llm.GenerateContent(ctx, req, false) {
+		if err != nil {
+			return nil, err
+		}
+		if resp.ErrorMessage != "" {
+			return nil, fmt.Errorf("model error: %s", resp.ErrorMessage)
+		}
+		text.WriteString(contentText(resp.Content))
+		if resp.UsageMetadata != nil {
+			out.PromptTokens += int64(resp.UsageMetadata.PromptTokenCount)
+			out.CompletionTokens += int64(resp.UsageMetadata.CandidatesTokenCount)
+		}
+	}
+	out.Text = text.String()
+	return out, nil
+}
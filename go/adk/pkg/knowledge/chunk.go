@@ -0,0 +1,85 @@
+package knowledge
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultChunkSize is the target chunk length in bytes.
+	DefaultChunkSize = 1000
+	// DefaultChunkOverlap is how many bytes consecutive chunks share, so a
+	// passage cut at a chunk boundary is still retrievable whole.
+	DefaultChunkOverlap = 200
+)
+
+// Chunk is a span of a document. StartOffset and EndOffset are byte offsets
+// into the document, so Content == text[StartOffset:EndOffset].
+type Chunk struct {
+	Content     string
+	StartOffset int
+	EndOffset   int
+}
+
+// SplitText splits text into chunks of at most size bytes that overlap by
+// about overlap bytes. Chunks end at a paragraph, line or word break when one
+// falls in the second half of the window, and never inside a UTF-8 sequence.
+// Chunks consisting only of whitespace are dropped.
+func SplitText(text string, size, overlap int) []Chunk {
+	if size <= 0 {
+		size = DefaultChunkSize
+	}
+	if overlap < 0 || overlap >= size {
+		overlap = 0
+	}
+
+	var chunks []Chunk
+	start := 0
+	for start < len(text) {
+		end := len(text)
+		if end-start > size {
+			end = breakPoint(text, start, start+size)
+		}
+		if strings.TrimSpace(text[start:end]) != "" {
+			chunks = append(chunks, Chunk{Content: text[start:end], StartOffset: start, EndOffset: end})
+		}
+		if end == len(text) {
+			break
+		}
+		next := runeStart(text, end-overlap)
+		if next <= start {
+			next = end
+		}
+		start = next
+	}
+	return chunks
+}
+
+// breakPoint returns where a chunk of text starting at start should end,
+// given that it may not extend past limit.
+func breakPoint(text string, start, limit int) int {
+	window := text[start:limit]
+	half := len(window) / 2
+	for _, sep := range []string{"\n\n", "\n"} {
+		if i := strings.LastIndex(window, sep); i >= half {
+			return start + i + len(sep)
+		}
+	}
+	if i := strings.LastIndexFunc(window, unicode.IsSpace); i >= half {
+		_, n := utf8.DecodeRuneInString(window[i:])
+		return start + i + n
+	}
+	return runeStart(text, limit)
+}
+
+// runeStart moves i back to the start of the UTF-8 sequence it falls in.
+func runeStart(text string, i int) int {
+	if i <= 0 {
+		return 0
+	}
+	for i > 0 && i < len(text) && !utf8.RuneStart(text[i]) {
+		i--
+	}
+	return i
+}
@@ -0,0 +1,62 @@
+package knowledge
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSplitText(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		size    int
+		overlap int
+	}{
+		{name: "short text", text: "hello world", size: 100, overlap: 10},
+		{name: "paragraphs", text: strings.Repeat("A paragraph of text.\n\n", 40), size: 100, overlap: 20},
+		{name: "no break points", text: strings.Repeat("x", 250), size: 100, overlap: 20},
+		{name: "multi-byte runes", text: strings.Repeat("héllo wörld ", 30), size: 50, overlap: 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunks := SplitText(tt.text, tt.size, tt.overlap)
+			if len(chunks) == 0 {
+				t.Fatal("SplitText() returned no chunks")
+			}
+			prevStart := -1
+			for i, c := range chunks {
+				if got := tt.text[c.StartOffset:c.EndOffset]; got != c.Content {
+					t.Errorf("chunk %d content %q does not match offsets [%d:%d]", i, c.Content, c.StartOffset, c.EndOffset)
+				}
+				if len(c.Content) > tt.size {
+					t.Errorf("chunk %d has %d bytes, want at most %d", i, len(c.Content), tt.size)
+				}
+				if !utf8.ValidString(c.Content) {
+					t.Errorf("chunk %d splits a UTF-8 sequence: %q", i, c.Content)
+				}
+				if c.StartOffset <= prevStart {
+					t.Errorf("chunk %d starts at %d, not after the previous chunk at %d", i, c.StartOffset, prevStart)
+				}
+				prevStart = c.StartOffset
+			}
+			if last := chunks[len(chunks)-1]; last.EndOffset != len(tt.text) {
+				t.Errorf("last chunk ends at %d, want %d", last.EndOffset, len(tt.text))
+			}
+		})
+	}
+}
+
+func TestSplitTextPrefersParagraphBreaks(t *testing.T) {
+	text := strings.Repeat("a", 60) + "\n\n" + strings.Repeat("b", 60)
+	chunks := SplitText(text, 100, 0)
+	if len(chunks) != 2 || chunks[0].Content != strings.Repeat("a", 60)+"\n\n" {
+		t.Fatalf("SplitText() = %+v, want a break after the first paragraph", chunks)
+	}
+}
+
+func TestSplitTextDropsBlankChunks(t *testing.T) {
+	if chunks := SplitText("   \n\n  ", 100, 0); len(chunks) != 0 {
+		t.Fatalf("SplitText() = %+v, want no chunks", chunks)
+	}
+}
@@ -0,0 +1,183 @@
+package knowledge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/kagent-dev/kagent/go/adk/pkg/embedding"
+	"github.com/kagent-dev/kagent/go/api/adk"
+)
+
+// Client ingests documents into and searches knowledge collections through
+// the Kagent API (backed by pgvector). Embeddings are generated client side.
+type Client struct {
+	apiURL   string
+	client   *http.Client
+	embedder embedding.Embedder
+}
+
+// Config for creating a new Client.
+type Config struct {
+	// APIURL is the base URL of the Kagent API (e.g., "http://kagent-controller:8083")
+	APIURL string
+	// HTTPClient for making requests (optional, uses http.DefaultClient if nil)
+	HTTPClient *http.Client
+	// EmbeddingConfig for generating embeddings. Queries must be embedded
+	// with the model their collection was ingested with.
+	EmbeddingConfig *adk.EmbeddingConfig
+}
+
+// Result is a chunk returned by a search, with the document it came from so
+// that answers can cite their sources.
+type Result struct {
+	Collection  string            `json:"collection"`
+	DocumentID  string            `json:"document_id"`
+	ChunkIndex  int               `json:"chunk_index"`
+	Content     string            `json:"content"`
+	StartOffset int               `json:"start_offset"`
+	EndOffset   int               `json:"end_offset"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	Score       float64           `json:"score"`
+}
+
+type chunkRequest struct {
+	Content     string            `json:"content"`
+	StartOffset int               `json:"start_offset"`
+	EndOffset   int               `json:"end_offset"`
+	Vector      []float32         `json:"vector"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+}
+
+type ingestRequest struct {
+	DocumentID string         `json:"document_id"`
+	Chunks     []chunkRequest `json:"chunks"`
+}
+
+type searchRequest struct {
+	Vector []float32         `json:"vector"`
+	Limit  int               `json:"limit"`
+	Filter map[string]string `json:"filter,omitempty"`
+}
+
+// New creates a new Client.
+func New(cfg Config) (*Client, error) {
+	if cfg.APIURL == "" {
+		return nil, fmt.Errorf("API URL is required")
+	}
+	if cfg.EmbeddingConfig == nil {
+		return nil, fmt.Errorf("embedding config is required")
+	}
+
+	client := cfg.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	embClient, err := embedding.New(embedding.Config{
+		EmbeddingConfig: cfg.EmbeddingConfig,
+		HTTPClient:      client,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create embedding client: %w", err)
+	}
+
+	return &Client{
+		apiURL:   strings.TrimSuffix(cfg.APIURL, "/"),
+		client:   client,
+		embedder: embClient,
+	}, nil
+}
+
+// Ingest splits text into chunks, embeds them and stores them as documentID
+// in collection, replacing any earlier version of the document. metadata is
+// attached to every chunk and can be used to filter searches.
+func (c *Client) Ingest(ctx context.Context, collection, documentID, text string, metadata map[string]string) (int, error) {
+	chunks := SplitText(text, DefaultChunkSize, DefaultChunkOverlap)
+	if len(chunks) == 0 {
+		return 0, fmt.Errorf("document %s has no content", documentID)
+	}
+
+	texts := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		texts[i] = chunk.Content
+	}
+	vectors, err := c.embedder.Embed(ctx, texts)
+	if err != nil {
+		return 0, fmt.Errorf("failed to generate embeddings: %w", err)
+	}
+	if len(vectors) != len(chunks) {
+		return 0, fmt.Errorf("expected %d embeddings, got %d", len(chunks), len(vectors))
+	}
+
+	req := ingestRequest{DocumentID: documentID, Chunks: make([]chunkRequest, len(chunks))}
+	for i, chunk := range chunks {
+		req.Chunks[i] = chunkRequest{
+			Content:     chunk.Content,
+			StartOffset: chunk.StartOffset,
+			EndOffset:   chunk.EndOffset,
+			Vector:      vectors[i],
+			Metadata:    metadata,
+		}
+	}
+	if err := c.post(ctx, collection, "documents", req, nil); err != nil {
+		return 0, err
+	}
+	return len(chunks), nil
+}
+
+// Search returns the limit chunks of collection most similar to query whose
+// metadata contains every entry of filter.
+func (c *Client) Search(ctx context.Context, collection, query string, filter map[string]string, limit int) ([]Result, error) {
+	vectors, err := c.embedder.Embed(ctx, []string{query})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate query embedding: %w", err)
+	}
+	if len(vectors) == 0 || vectors[0] == nil {
+		return nil, fmt.Errorf("embedding generation returned no vectors")
+	}
+
+	var results []Result
+	if err := c.post(ctx, collection, "search", searchRequest{Vector: vectors[0], Limit: limit, Filter: filter}, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// post sends body to the collection endpoint and decodes the response into
+// out when out is not nil.
+func (c *Client) post(ctx context.Context, collection, endpoint string, body, out any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	u := fmt.Sprintf("%s/api/knowledge/%s/%s", c.apiURL, url.PathEscape(collection), endpoint)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+		return fmt.Errorf("API returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
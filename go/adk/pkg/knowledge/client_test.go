@@ -0,0 +1,79 @@
+package knowledge
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type constantEmbedder struct{}
+
+func (constantEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, len(texts))
+	for i := range texts {
+		out[i] = []float32{1, 0}
+	}
+	return out, nil
+}
+
+func TestClientIngest(t *testing.T) {
+	var got ingestRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/knowledge/runbooks/documents" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client := &Client{apiURL: server.URL, client: server.Client(), embedder: constantEmbedder{}}
+	text := strings.Repeat("Restart the pod.\n\n", 100)
+	n, err := client.Ingest(context.Background(), "runbooks", "restart.md", text, map[string]string{"team": "sre"})
+	if err != nil {
+		t.Fatalf("Ingest() error = %v", err)
+	}
+	if n < 2 || len(got.Chunks) != n {
+		t.Fatalf("Ingest() = %d chunks, server received %d", n, len(got.Chunks))
+	}
+	if got.DocumentID != "restart.md" || got.Chunks[0].Metadata["team"] != "sre" || len(got.Chunks[0].Vector) != 2 {
+		t.Fatalf("unexpected ingest request %+v", got.Chunks[0])
+	}
+}
+
+func TestClientSearch(t *testing.T) {
+	scores := map[string]float64{"runbooks": 0.4, "guides": 0.9}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		collection := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/knowledge/"), "/")[0]
+		var req searchRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Limit != 1 || req.Filter["lang"] != "en" {
+			t.Errorf("unexpected search request %+v (%v)", req, err)
+		}
+		_ = json.NewEncoder(w).Encode([]Result{{Collection: collection, DocumentID: collection + ".md", Score: scores[collection]}})
+	}))
+	defer server.Close()
+
+	client := &Client{apiURL: server.URL, client: server.Client(), embedder: constantEmbedder{}}
+	var results []Result
+	for _, collection := range []string{"runbooks", "guides"} {
+		found, err := client.Search(context.Background(), collection, "restart", map[string]string{"lang": "en"}, 1)
+		if err != nil {
+			t.Fatalf("Search() error = %v", err)
+		}
+		results = append(results, found...)
+	}
+	if len(results) != 2 || results[1].DocumentID != "guides.md" || results[1].Score != 0.9 {
+		t.Fatalf("Search() results = %+v", results)
+	}
+}
+
+func TestNewRetrieveDocsToolRequiresCollections(t *testing.T) {
+	if _, err := NewRetrieveDocsTool(&Client{}, nil, 0); err == nil {
+		t.Fatal("NewRetrieveDocsTool() error = nil, want an error without collections")
+	}
+}
@@ -0,0 +1,70 @@
+package knowledge
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	adkagent "google.golang.org/adk/agent"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+const (
+	// RetrieveDocsToolName is the name of the retrieve_docs tool.
+	RetrieveDocsToolName = "retrieve_docs"
+	// DefaultTopK is the number of chunks retrieve_docs returns when no top_k
+	// is configured.
+	DefaultTopK = 5
+)
+
+type retrieveDocsInput struct {
+	Query string `json:"query"`
+	// Collection restricts the search to one of the configured collections.
+	Collection string `json:"collection,omitempty"`
+	// Filter restricts results to chunks whose metadata contains every entry.
+	Filter map[string]string `json:"filter,omitempty"`
+}
+
+// NewRetrieveDocsTool creates a retrieve_docs tool that searches collections
+// and returns the topK most relevant chunks with their source documents.
+func NewRetrieveDocsTool(client *Client, collections []string, topK int) (tool.Tool, error) {
+	if len(collections) == 0 {
+		return nil, fmt.Errorf("at least one collection is required")
+	}
+	if topK <= 0 {
+		topK = DefaultTopK
+	}
+	return functiontool.New(functiontool.Config{
+		Name: RetrieveDocsToolName,
+		Description: fmt.Sprintf("Searches the document collections %v for passages relevant to a query. "+
+			"Use this to answer questions from documentation rather than from memory. "+
+			"Each result carries the document_id and byte offsets of its passage; cite them in your answer. "+
+			"Optionally restrict the search to one collection or to chunks whose metadata matches a filter.", collections),
+	}, func(toolCtx adkagent.ToolContext, in retrieveDocsInput) (map[string]any, error) {
+		if in.Query == "" {
+			return nil, fmt.Errorf("missing required parameter: query")
+		}
+		search := collections
+		if in.Collection != "" {
+			if !slices.Contains(collections, in.Collection) {
+				return nil, fmt.Errorf("unknown collection %q, expected one of %v", in.Collection, collections)
+			}
+			search = []string{in.Collection}
+		}
+
+		var results []Result
+		for _, collection := range search {
+			found, err := client.Search(toolCtx, collection, in.Query, in.Filter, topK)
+			if err != nil {
+				return nil, fmt.Errorf("failed to search collection %s: %w", collection, err)
+			}
+			results = append(results, found...)
+		}
+		sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+		if len(results) > topK {
+			results = results[:topK]
+		}
+		return map[string]any{"results": results}, nil
+	})
+}
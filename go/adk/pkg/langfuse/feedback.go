@@ -0,0 +1,39 @@
+package langfuse
+
+import (
+	"context"
+	"errors"
+
+	"github.com/kagent-dev/kagent/go/adk/pkg/a2a/server"
+)
+
+// Names of the scores created from task feedback.
+const (
+	ScoreThumbs = "user-thumbs"
+	ScoreRating = "user-rating"
+)
+
+// ExportFeedback implements server.FeedbackExporter. Thumbs become a
+// ScoreThumbs score of 1 (up) or 0 (down) and ratings a ScoreRating score,
+// on the trace of the invocation that answered the task, or on its session
+// when the invocation is unknown.
+func (e *Exporter) ExportFeedback(_ context.Context, feedback server.TaskFeedback) error {
+	score := Score{
+		TraceID:   feedback.InvocationID,
+		SessionID: feedback.ContextID,
+		Comment:   feedback.Comment,
+	}
+	var errs []error
+	if feedback.Thumbs != "" {
+		score.Name, score.Value = ScoreThumbs, 0
+		if feedback.Thumbs == server.ThumbsUp {
+			score.Value = 1
+		}
+		errs = append(errs, e.Score(score))
+	}
+	if feedback.Rating != nil {
+		score.Name, score.Value = ScoreRating, float64(*feedback.Rating)
+		errs = append(errs, e.Score(score))
+	}
+	return errors.Join(errs...)
+}
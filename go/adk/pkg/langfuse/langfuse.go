@@ -0,0 +1,314 @@
+// Package langfuse exports agent runs to Langfuse: a trace per invocation,
+// with a generation for each model call (usage and cost included) and a span
+// for each tool call, plus user feedback scores. Events are buffered and
+// sent in batches to the Langfuse ingestion API, with retries.
+package langfuse
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/google/uuid"
+	"github.com/kagent-dev/kagent/go/api/adk"
+)
+
+// Environment variables read when the agent config leaves the corresponding
+// LangfuseConfig field empty. They are the ones the Langfuse SDKs use.
+const (
+	EnvHost      = "LANGFUSE_HOST"
+	EnvPublicKey = "LANGFUSE_PUBLIC_KEY"
+	EnvSecretKey = "LANGFUSE_SECRET_KEY"
+)
+
+// Defaults of the exporter settings.
+const (
+	DefaultHost          = "https://cloud.langfuse.com"
+	DefaultBatchSize     = 50
+	DefaultFlushInterval = 5 * time.Second
+	DefaultMaxRetries    = 3
+)
+
+// maxBufferedEvents bounds the events waiting to be sent; newer events are
+// dropped while Langfuse cannot keep up.
+const maxBufferedEvents = 10000
+
+// Config configures an Exporter.
+type Config struct {
+	Host          string
+	PublicKey     string
+	SecretKey     string
+	BatchSize     int
+	FlushInterval time.Duration
+	MaxRetries    int
+	// InputCostPerMillion and OutputCostPerMillion price tokens in USD per
+	// million. When nil, generations carry no cost and Langfuse prices them
+	// from its model table.
+	InputCostPerMillion  *float64
+	OutputCostPerMillion *float64
+	// HTTPClient sends the ingestion requests. Defaults to a client with a
+	// 30s timeout.
+	HTTPClient *http.Client
+}
+
+// ConfigFromAgent builds the exporter config from the agent's Langfuse
+// config and the LANGFUSE_* env vars. It reports false when neither enables
+// the exporter: the agent config has no langfuse section and the env vars
+// hold no keys.
+func ConfigFromAgent(cfg *adk.LangfuseConfig) (Config, bool, error) {
+	if cfg == nil {
+		if os.Getenv(EnvPublicKey) == "" || os.Getenv(EnvSecretKey) == "" {
+			return Config{}, false, nil
+		}
+		cfg = &adk.LangfuseConfig{}
+	}
+	if err := cfg.Validate(); err != nil {
+		return Config{}, false, err
+	}
+	c := Config{
+		Host:                 firstNonEmpty(cfg.Host, os.Getenv(EnvHost), DefaultHost),
+		PublicKey:            firstNonEmpty(cfg.PublicKey, os.Getenv(EnvPublicKey)),
+		SecretKey:            firstNonEmpty(cfg.SecretKey, os.Getenv(EnvSecretKey)),
+		BatchSize:            cfg.BatchSize,
+		MaxRetries:           DefaultMaxRetries,
+		InputCostPerMillion:  cfg.InputCostPerMillion,
+		OutputCostPerMillion: cfg.OutputCostPerMillion,
+	}
+	if cfg.FlushInterval != nil {
+		c.FlushInterval = time.Duration(*cfg.FlushInterval * float64(time.Second))
+	}
+	if cfg.MaxRetries != nil {
+		c.MaxRetries = *cfg.MaxRetries
+	}
+	return c, true, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// Score is a score attached to a trace or a session, e.g. a user's feedback
+// on an answer.
+type Score struct {
+	// TraceID is the invocation scored. Either TraceID or SessionID is
+	// required.
+	TraceID   string
+	SessionID string
+	Name      string
+	Value     float64
+	Comment   string
+}
+
+// event is one item of an ingestion batch.
+type event struct {
+	ID        string         `json:"id"`
+	Type      string         `json:"type"`
+	Timestamp time.Time      `json:"timestamp"`
+	Body      map[string]any `json:"body"`
+}
+
+// Exporter buffers Langfuse events and sends them in batches, when a batch
+// is full and every FlushInterval. Sending failures are logged and never
+// interrupt the agent.
+type Exporter struct {
+	cfg    Config
+	logger logr.Logger
+
+	mu     sync.Mutex
+	buffer []event
+	runs   map[string]*run // keyed by invocation ID
+
+	full      chan struct{}
+	done      chan struct{}
+	stopped   chan struct{}
+	closeOnce sync.Once
+}
+
+// New creates an Exporter and starts its flush loop. Close stops it.
+func New(cfg Config, logger logr.Logger) (*Exporter, error) {
+	if cfg.PublicKey == "" || cfg.SecretKey == "" {
+		return nil, fmt.Errorf("langfuse public and secret keys are required (%s, %s)", EnvPublicKey, EnvSecretKey)
+	}
+	if cfg.Host == "" {
+		cfg.Host = DefaultHost
+	}
+	cfg.Host = strings.TrimRight(cfg.Host, "/")
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultBatchSize
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = DefaultFlushInterval
+	}
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	e := &Exporter{
+		cfg:     cfg,
+		logger:  logger.WithName("langfuse"),
+		runs:    make(map[string]*run),
+		full:    make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go e.loop()
+	return e, nil
+}
+
+// Score records a score, sent with the next batch.
+func (e *Exporter) Score(s Score) error {
+	if s.TraceID == "" && s.SessionID == "" {
+		return errors.New("a score needs a trace or a session")
+	}
+	if s.Name == "" {
+		return errors.New("a score needs a name")
+	}
+	body := map[string]any{
+		"id":    uuid.NewString(),
+		"name":  s.Name,
+		"value": s.Value,
+	}
+	if s.TraceID != "" {
+		body["traceId"] = s.TraceID
+	}
+	if s.SessionID != "" {
+		body["sessionId"] = s.SessionID
+	}
+	if s.Comment != "" {
+		body["comment"] = s.Comment
+	}
+	e.enqueue("score-create", body)
+	return nil
+}
+
+// enqueue buffers an event, waking the flush loop when a batch is full.
+func (e *Exporter) enqueue(eventType string, body map[string]any) {
+	e.mu.Lock()
+	if len(e.buffer) >= maxBufferedEvents {
+		e.mu.Unlock()
+		e.logger.Info("Dropping Langfuse event, buffer full", "type", eventType)
+		return
+	}
+	e.buffer = append(e.buffer, event{ID: uuid.NewString(), Type: eventType, Timestamp: time.Now().UTC(), Body: body})
+	full := len(e.buffer) >= e.cfg.BatchSize
+	e.mu.Unlock()
+	if full {
+		select {
+		case e.full <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (e *Exporter) loop() {
+	defer close(e.stopped)
+	ticker := time.NewTicker(e.cfg.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-e.full:
+		case <-e.done:
+			return
+		}
+		e.Flush(context.Background())
+	}
+}
+
+// Flush sends the buffered events, one batch at a time. Batches that still
+// fail after the retries are dropped.
+func (e *Exporter) Flush(ctx context.Context) {
+	for {
+		e.mu.Lock()
+		n := min(len(e.buffer), e.cfg.BatchSize)
+		batch := e.buffer[:n:n]
+		e.buffer = e.buffer[n:]
+		e.mu.Unlock()
+		if n == 0 {
+			return
+		}
+		if err := e.send(ctx, batch); err != nil {
+			e.logger.Error(err, "Failed to send events to Langfuse", "events", n)
+		}
+	}
+}
+
+// Close stops the flush loop and sends the remaining events.
+func (e *Exporter) Close() error {
+	e.closeOnce.Do(func() {
+		close(e.done)
+		<-e.stopped
+		e.Flush(context.Background())
+	})
+	return nil
+}
+
+// send posts a batch to the ingestion API, retrying failed connections,
+// 429 and 5xx responses with an exponential backoff.
+func (e *Exporter) send(ctx context.Context, batch []event) error {
+	payload, err := json.Marshal(map[string]any{"batch": batch})
+	if err != nil {
+		return fmt.Errorf("failed to marshal batch: %w", err)
+	}
+	for attempt := 0; ; attempt++ {
+		retry, err := e.post(ctx, payload)
+		if err == nil || !retry || attempt >= e.cfg.MaxRetries {
+			return err
+		}
+		select {
+		case <-time.After(time.Duration(500<<attempt) * time.Millisecond):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// post sends one ingestion request and reports whether a failure is worth
+// retrying.
+func (e *Exporter) post(ctx context.Context, payload []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.cfg.Host+"/api/public/ingestion", bytes.NewReader(payload))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBasicAuth(e.cfg.PublicKey, e.cfg.SecretKey)
+	resp, err := e.cfg.HTTPClient.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return true, fmt.Errorf("langfuse ingestion returned %s", resp.Status)
+	case resp.StatusCode >= 300:
+		return false, fmt.Errorf("langfuse ingestion returned %s: %s", resp.Status, bytes.TrimSpace(body))
+	}
+	// A 207 response lists the events Langfuse rejected.
+	var result struct {
+		Errors []struct {
+			ID      string `json:"id"`
+			Status  int    `json:"status"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if json.Unmarshal(body, &result) == nil {
+		for _, rejected := range result.Errors {
+			e.logger.Info("Langfuse rejected an event", "id", rejected.ID, "status", rejected.Status, "message", rejected.Message)
+		}
+	}
+	return false, nil
+}
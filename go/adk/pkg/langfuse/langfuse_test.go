@@ -0,0 +1,144 @@
+package langfuse
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/kagent-dev/kagent/go/adk/pkg/a2a/server"
+	"github.com/kagent-dev/kagent/go/api/adk"
+)
+
+// ingestion is a fake Langfuse ingestion API failing its first requests.
+type ingestion struct {
+	mu       sync.Mutex
+	failures int
+	requests int
+	batches  [][]event
+}
+
+func (s *ingestion) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+	if user, pass, ok := r.BasicAuth(); !ok || user != "pk" || pass != "sk" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if s.failures > 0 {
+		s.failures--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	var payload struct {
+		Batch []event `json:"batch"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	s.batches = append(s.batches, payload.Batch)
+	w.WriteHeader(http.StatusMultiStatus)
+	_, _ = w.Write([]byte(`{"successes": [], "errors": []}`))
+}
+
+func TestExporter_BatchesAndRetries(t *testing.T) {
+	fake := &ingestion{failures: 1}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	e, err := New(Config{Host: server.URL + "/", PublicKey: "pk", SecretKey: "sk", BatchSize: 2, FlushInterval: time.Hour, MaxRetries: 2}, logr.Discard())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	for _, name := range []string{"helpful", "correct", "polite"} {
+		if err := e.Score(Score{SessionID: "session-1", Name: name, Value: 1}); err != nil {
+			t.Fatalf("Score() error = %v", err)
+		}
+	}
+	if err := e.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	var events []event
+	for _, batch := range fake.batches {
+		if len(batch) > 2 {
+			t.Errorf("batch of %d events, want at most 2", len(batch))
+		}
+		events = append(events, batch...)
+	}
+	if len(events) != 3 || fake.requests != len(fake.batches)+1 {
+		t.Fatalf("got %d events in %d requests, want 3 events and one retried request", len(events), fake.requests)
+	}
+	if events[0].Type != "score-create" || events[0].Body["sessionId"] != "session-1" || events[0].Body["name"] != "helpful" {
+		t.Errorf("first event = %+v", events[0])
+	}
+}
+
+func TestExporter_Score(t *testing.T) {
+	e := &Exporter{cfg: Config{BatchSize: 10}, logger: logr.Discard(), full: make(chan struct{}, 1)}
+	if err := e.Score(Score{Name: "helpful"}); err == nil {
+		t.Error("Score() without a trace or session error = nil")
+	}
+	if err := e.Score(Score{TraceID: "inv-1"}); err == nil {
+		t.Error("Score() without a name error = nil")
+	}
+}
+
+func TestExporter_Cost(t *testing.T) {
+	e := &Exporter{cfg: Config{InputCostPerMillion: new(2.0), OutputCostPerMillion: new(10.0)}}
+	cost := e.cost(1000, 500)
+	if cost["input"] != 0.002 || cost["output"] != 0.005 || cost["total"] != 0.007 {
+		t.Errorf("cost() = %v", cost)
+	}
+	if (&Exporter{}).cost(1000, 500) != nil {
+		t.Error("cost() without prices should be nil")
+	}
+}
+
+func TestConfigFromAgent(t *testing.T) {
+	t.Setenv(EnvPublicKey, "")
+	t.Setenv(EnvSecretKey, "")
+	if _, enabled, err := ConfigFromAgent(nil); err != nil || enabled {
+		t.Errorf("ConfigFromAgent(nil) = %v, %v; want disabled without keys", enabled, err)
+	}
+
+	t.Setenv(EnvPublicKey, "pk-env")
+	t.Setenv(EnvSecretKey, "sk-env")
+	cfg, enabled, err := ConfigFromAgent(&adk.LangfuseConfig{PublicKey: "pk", FlushInterval: new(0.5)})
+	if err != nil || !enabled {
+		t.Fatalf("ConfigFromAgent() = %v, %v", enabled, err)
+	}
+	if cfg.PublicKey != "pk" || cfg.SecretKey != "sk-env" || cfg.Host != DefaultHost || cfg.FlushInterval != 500*time.Millisecond || cfg.MaxRetries != DefaultMaxRetries {
+		t.Errorf("ConfigFromAgent() = %+v, want config keys over env vars", cfg)
+	}
+}
+
+func TestExporter_ExportFeedback(t *testing.T) {
+	e := &Exporter{cfg: Config{BatchSize: 10}, logger: logr.Discard(), full: make(chan struct{}, 1)}
+	feedback := server.TaskFeedback{
+		Feedback:     server.Feedback{Thumbs: server.ThumbsDown, Rating: new(2), Comment: "wrong pod"},
+		InvocationID: "inv-1",
+		ContextID:    "session-1",
+	}
+	if err := e.ExportFeedback(context.Background(), feedback); err != nil {
+		t.Fatalf("ExportFeedback() error = %v", err)
+	}
+	if len(e.buffer) != 2 {
+		t.Fatalf("buffered %d events, want a thumbs and a rating score", len(e.buffer))
+	}
+	thumbs, rating := e.buffer[0].Body, e.buffer[1].Body
+	if thumbs["name"] != ScoreThumbs || thumbs["value"] != 0.0 || thumbs["traceId"] != "inv-1" || thumbs["comment"] != "wrong pod" {
+		t.Errorf("thumbs score = %v", thumbs)
+	}
+	if rating["name"] != ScoreRating || rating["value"] != 2.0 || rating["sessionId"] != "session-1" {
+		t.Errorf("rating score = %v", rating)
+	}
+}
@@ -0,0 +1,241 @@
+package langfuse
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kagent-dev/kagent/go/adk/pkg/prompts"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/model"
+	adkplugin "google.golang.org/adk/plugin"
+	"google.golang.org/adk/tool"
+	"google.golang.org/genai"
+)
+
+// run tracks an invocation until its trace is exported.
+type run struct {
+	start  time.Time
+	input  string
+	output string
+	// generations are the model calls in flight, keyed by agent name: the model
+	// calls of an agent are sequential.
+	generations map[string]*observation
+	// tools are the tool calls in flight, keyed by function call ID.
+	tools map[string]*observation
+}
+
+type observation struct {
+	id    string
+	start time.Time
+	input any
+	model string
+}
+
+// ADKPlugin returns the Go ADK plugin that exports the runs of the runner it
+// is registered with. Closing the plugin closes the exporter.
+func (e *Exporter) ADKPlugin() (*adkplugin.Plugin, error) {
+	return adkplugin.New(adkplugin.Config{
+		Name:                "kagent-langfuse",
+		BeforeRunCallback:   e.beforeRun,
+		AfterRunCallback:    e.afterRun,
+		BeforeModelCallback: e.beforeModel,
+		AfterModelCallback:  e.afterModel,
+		BeforeToolCallback:  e.beforeTool,
+		AfterToolCallback:   e.afterTool,
+		CloseFunc:           e.Close,
+	})
+}
+
+func (e *Exporter) beforeRun(ctx agent.InvocationContext) (*genai.Content, error) {
+	e.mu.Lock()
+	e.runs[ctx.InvocationID()] = &run{
+		start:       time.Now().UTC(),
+		input:       contentText(ctx.UserContent()),
+		generations: make(map[string]*observation),
+		tools:       make(map[string]*observation),
+	}
+	e.mu.Unlock()
+	return nil, nil
+}
+
+// afterRun exports the invocation's trace.
+func (e *Exporter) afterRun(ctx agent.InvocationContext) {
+	e.mu.Lock()
+	r, ok := e.runs[ctx.InvocationID()]
+	delete(e.runs, ctx.InvocationID())
+	e.mu.Unlock()
+	if !ok {
+		return
+	}
+	body := map[string]any{
+		"id":        ctx.InvocationID(),
+		"timestamp": r.start,
+		"input":     r.input,
+		"output":    r.output,
+	}
+	if a := ctx.Agent(); a != nil {
+		body["name"] = a.Name()
+	}
+	metadata := map[string]any{}
+	if s := ctx.Session(); s != nil {
+		body["sessionId"] = s.ID()
+		body["userId"] = s.UserID()
+		metadata["app_name"] = s.AppName()
+	}
+	// Traces carry the prompt version so scores can be compared per version.
+	if a, ok := prompts.AssignmentFrom(ctx); ok {
+		body["version"] = a.Version.Version
+		if a.Experiment != "" {
+			metadata["prompt_experiment"] = a.Experiment
+			metadata["prompt_arm"] = a.Arm
+		}
+	}
+	if len(metadata) > 0 {
+		body["metadata"] = metadata
+	}
+	e.enqueue("trace-create", body)
+}
+
+// current returns the run of an invocation, or nil when it started before
+// the plugin was registered. e.mu must be held.
+func (e *Exporter) current(invocationID string) *run {
+	return e.runs[invocationID]
+}
+
+func (e *Exporter) beforeModel(ctx agent.CallbackContext, req *model.LLMRequest) (*model.LLMResponse, error) {
+	obs := &observation{id: uuid.NewString(), start: time.Now().UTC()}
+	if req != nil {
+		obs.input = req.Contents
+		obs.model = req.Model
+	}
+	e.mu.Lock()
+	if r := e.current(ctx.InvocationID()); r != nil {
+		r.generations[ctx.AgentName()] = obs
+	}
+	e.mu.Unlock()
+	return nil, nil
+}
+
+// afterModel exports a generation once the model's final response arrives.
+func (e *Exporter) afterModel(ctx agent.CallbackContext, resp *model.LLMResponse, respErr error) (*model.LLMResponse, error) {
+	if resp != nil && resp.Partial {
+		return nil, nil
+	}
+	e.mu.Lock()
+	r := e.current(ctx.InvocationID())
+	var obs *observation
+	if r != nil {
+		obs = r.generations[ctx.AgentName()]
+		delete(r.generations, ctx.AgentName())
+		if resp != nil {
+			if text := contentText(resp.Content); text != "" {
+				r.output = text
+			}
+		}
+	}
+	e.mu.Unlock()
+	if obs == nil {
+		return nil, nil
+	}
+
+	body := map[string]any{
+		"id":        obs.id,
+		"traceId":   ctx.InvocationID(),
+		"name":      ctx.AgentName(),
+		"startTime": obs.start,
+		"endTime":   time.Now().UTC(),
+		"model":     obs.model,
+		"input":     obs.input,
+	}
+	if resp != nil {
+		body["output"] = resp.Content
+		if um := resp.UsageMetadata; um != nil {
+			input, output := int(um.PromptTokenCount), int(um.CandidatesTokenCount)+int(um.ThoughtsTokenCount)
+			body["usageDetails"] = map[string]any{"input": input, "output": output, "total": int(um.TotalTokenCount)}
+			if cost := e.cost(input, output); cost != nil {
+				body["costDetails"] = cost
+			}
+		}
+		if resp.ErrorMessage != "" {
+			body["level"] = "ERROR"
+			body["statusMessage"] = resp.ErrorMessage
+		}
+	}
+	if respErr != nil {
+		body["level"] = "ERROR"
+		body["statusMessage"] = respErr.Error()
+	}
+	e.enqueue("generation-create", body)
+	return nil, nil
+}
+
+// cost prices a generation's tokens, or returns nil when no prices are
+// configured.
+func (e *Exporter) cost(inputTokens, outputTokens int) map[string]any {
+	if e.cfg.InputCostPerMillion == nil && e.cfg.OutputCostPerMillion == nil {
+		return nil
+	}
+	var input, output float64
+	if p := e.cfg.InputCostPerMillion; p != nil {
+		input = float64(inputTokens) * *p / 1e6
+	}
+	if p := e.cfg.OutputCostPerMillion; p != nil {
+		output = float64(outputTokens) * *p / 1e6
+	}
+	return map[string]any{"input": input, "output": output, "total": input + output}
+}
+
+func (e *Exporter) beforeTool(ctx tool.Context, t tool.Tool, args map[string]any) (map[string]any, error) {
+	e.mu.Lock()
+	if r := e.current(ctx.InvocationID()); r != nil {
+		r.tools[ctx.FunctionCallID()] = &observation{id: uuid.NewString(), start: time.Now().UTC(), input: args}
+	}
+	e.mu.Unlock()
+	return nil, nil
+}
+
+// afterTool exports a tool call as a span.
+func (e *Exporter) afterTool(ctx tool.Context, t tool.Tool, args, result map[string]any, err error) (map[string]any, error) {
+	e.mu.Lock()
+	var obs *observation
+	if r := e.current(ctx.InvocationID()); r != nil {
+		obs = r.tools[ctx.FunctionCallID()]
+		delete(r.tools, ctx.FunctionCallID())
+	}
+	e.mu.Unlock()
+	if obs == nil {
+		return nil, nil
+	}
+
+	body := map[string]any{
+		"id":        obs.id,
+		"traceId":   ctx.InvocationID(),
+		"name":      t.Name(),
+		"startTime": obs.start,
+		"endTime":   time.Now().UTC(),
+		"input":     obs.input,
+		"output":    result,
+		"metadata":  map[string]any{"agent": ctx.AgentName(), "function_call_id": ctx.FunctionCallID()},
+	}
+	if err != nil {
+		body["level"] = "ERROR"
+		body["statusMessage"] = err.Error()
+	}
+	e.enqueue("span-create", body)
+	return nil, nil
+}
+
+// contentText concatenates the text parts of c, leaving out thoughts.
+func contentText(c *genai.Content) string {
+	if c == nil {
+		return ""
+	}
+	var b strings.Builder
+	for _, part := range c.Parts {
+		if part != nil && !part.Thought {
+			b.WriteString(part.Text)
+		}
+	}
+	return b.String()
+}
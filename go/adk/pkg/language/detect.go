@@ -0,0 +1,104 @@
+// Package language detects the language a user writes in, exposes it to the
+// agent's instruction through session state, and optionally translates tool
+// outputs into that language before the model sees them.
+package language
+
+import (
+	"strings"
+	"unicode"
+)
+
+// minLatinScore is the number of stopword hits required before a text in
+// Latin script is attributed to a language. Shorter texts ("ok", "thanks")
+// are too ambiguous to classify.
+const minLatinScore = 2
+
+// scriptLanguages maps scripts used by a single major language to its
+// BCP 47 code. Han is checked after Hiragana and Katakana so that Japanese
+// text mixing kanji and kana is not classified as Chinese.
+var scriptLanguages = []struct {
+	table *unicode.RangeTable
+	lang  string
+}{
+	{unicode.Hiragana, "ja"},
+	{unicode.Katakana, "ja"},
+	{unicode.Hangul, "ko"},
+	{unicode.Han, "zh"},
+	{unicode.Cyrillic, "ru"},
+	{unicode.Arabic, "ar"},
+	{unicode.Hebrew, "he"},
+	{unicode.Greek, "el"},
+	{unicode.Devanagari, "hi"},
+	{unicode.Thai, "th"},
+}
+
+// stopwords are frequent function words of languages written in Latin script.
+var stopwords = map[string][]string{
+	"en": {"the", "and", "is", "are", "of", "to", "in", "it", "that", "what", "how", "my", "with", "for", "this", "you", "can", "why"},
+	"es": {"el", "la", "los", "las", "es", "de", "que", "y", "en", "por", "para", "una", "con", "cómo", "qué", "mi", "está"},
+	"fr": {"le", "la", "les", "est", "et", "de", "des", "que", "une", "pour", "dans", "avec", "pourquoi", "comment", "mon", "je", "ne", "pas"},
+	"de": {"der", "die", "das", "und", "ist", "nicht", "ein", "eine", "mit", "für", "warum", "wie", "ich", "mein", "auf", "zu"},
+	"it": {"il", "lo", "gli", "è", "e", "di", "che", "una", "per", "con", "perché", "come", "mio", "non", "sono"},
+	"pt": {"o", "os", "as", "é", "e", "de", "que", "uma", "para", "com", "por", "não", "como", "meu", "está", "são"},
+	"nl": {"de", "het", "een", "en", "is", "van", "niet", "met", "voor", "waarom", "hoe", "mijn", "ik", "zijn", "op"},
+}
+
+// Detect returns the BCP 47 code of the language text is written in. It
+// reports false when text is too short or too mixed to tell.
+func Detect(text string) (string, bool) {
+	scripts := make(map[string]int)
+	letters := 0
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		for _, s := range scriptLanguages {
+			if unicode.Is(s.table, r) {
+				scripts[s.lang]++
+				break
+			}
+		}
+	}
+	if letters == 0 {
+		return "", false
+	}
+	if lang, count := mostFrequent(scripts); count*2 > letters {
+		return lang, true
+	}
+
+	scores := make(map[string]int)
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool { return !unicode.IsLetter(r) }) {
+		for lang, words := range stopwords {
+			for _, w := range words {
+				if w == word {
+					scores[lang]++
+					break
+				}
+			}
+		}
+	}
+	lang, best := mostFrequent(scores)
+	if best < minLatinScore {
+		return "", false
+	}
+	for other, score := range scores {
+		if other != lang && score == best {
+			return "", false
+		}
+	}
+	return lang, true
+}
+
+// mostFrequent returns the key with the highest count, preferring the
+// alphabetically first key on ties so results are deterministic.
+func mostFrequent(counts map[string]int) (string, int) {
+	var best string
+	bestCount := 0
+	for key, count := range counts {
+		if count > bestCount || (count == bestCount && key < best) {
+			best, bestCount = key, count
+		}
+	}
+	return best, bestCount
+}
@@ -0,0 +1,197 @@
+package language
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/genai"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		text   string
+		want   string
+		wantOK bool
+	}{
+		{text: "Why is my pod crashing in the default namespace?", want: "en", wantOK: true},
+		{text: "¿Por qué mi pod está fallando en el namespace?", want: "es", wantOK: true},
+		{text: "Pourquoi mon pod ne démarre pas dans le cluster ?", want: "fr", wantOK: true},
+		{text: "Warum startet mein Pod nicht und was ist der Fehler?", want: "de", wantOK: true},
+		{text: "Waarom start mijn pod niet op het cluster?", want: "nl", wantOK: true},
+		{text: "なぜポッドが起動しないのですか", want: "ja", wantOK: true},
+		{text: "为什么我的容器无法启动", want: "zh", wantOK: true},
+		{text: "Почему мой под не запускается?", want: "ru", wantOK: true},
+		{text: "ok", wantOK: false},
+		{text: "kubectl get pods -n kube-system", wantOK: false},
+		{text: "", wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, ok := Detect(tt.text)
+			if ok != tt.wantOK || (ok && got != tt.want) {
+				t.Errorf("Detect(%q) = %q, %v; want %q, %v", tt.text, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+type mapState map[string]any
+
+func (s mapState) Get(key string) (any, error) {
+	v, ok := s[key]
+	if !ok {
+		return nil, session.ErrStateKeyNotExist
+	}
+	return v, nil
+}
+
+func (s mapState) Set(key string, value any) error {
+	s[key] = value
+	return nil
+}
+
+func (s mapState) All() iter.Seq2[string, any] {
+	return maps.All(s)
+}
+
+type fakeContext struct {
+	agent.ToolContext
+	state       mapState
+	userContent *genai.Content
+}
+
+func (f fakeContext) State() session.State        { return f.state }
+func (f fakeContext) UserContent() *genai.Content { return f.userContent }
+func (f fakeContext) SessionID() string           { return "session-1" }
+
+type fakeTool struct {
+	tool.Tool
+	name string
+}
+
+func (f fakeTool) Name() string {
+	if f.name == "" {
+		return "get_events"
+	}
+	return f.name
+}
+
+type prefixTranslator struct {
+	calls int
+	err   error
+}
+
+func (p *prefixTranslator) Translate(_ context.Context, text, target string) (string, error) {
+	p.calls++
+	if p.err != nil {
+		return "", p.err
+	}
+	return target + ": " + text, nil
+}
+
+func TestBeforeAgentCallbackKeepsLanguageForAmbiguousMessages(t *testing.T) {
+	m := New(nil, nil, logr.Discard())
+	state := mapState{}
+	ctx := fakeContext{state: state, userContent: genai.NewContentFromText("¿Por qué mi pod está fallando?", genai.RoleUser)}
+	if _, err := m.BeforeAgentCallback(ctx); err != nil {
+		t.Fatal(err)
+	}
+	ctx.userContent = genai.NewContentFromText("ok", genai.RoleUser)
+	if _, err := m.BeforeAgentCallback(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if state[StateKeyUserLanguage] != "es" {
+		t.Fatalf("user_language = %v, want es", state[StateKeyUserLanguage])
+	}
+}
+
+func TestAfterToolCallbackTranslatesForeignStrings(t *testing.T) {
+	translator := &prefixTranslator{}
+	m := New(translator, nil, logr.Discard())
+	ctx := fakeContext{state: mapState{StateKeyUserLanguage: "es"}}
+	result := map[string]any{
+		"status": "Running",
+		"events": []any{
+			map[string]any{"message": "Back-off restarting the failed container in the pod"},
+			map[string]any{"message": "El contenedor está listo para el servicio"},
+		},
+	}
+
+	got, err := m.AfterToolCallback(ctx, fakeTool{}, nil, result, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	events := got["events"].([]any)
+	if msg := events[0].(map[string]any)["message"]; msg != "es: Back-off restarting the failed container in the pod" {
+		t.Errorf("english event = %v, want it translated", msg)
+	}
+	if msg := events[1].(map[string]any)["message"]; msg != "El contenedor está listo para el servicio" {
+		t.Errorf("spanish event = %v, want it unchanged", msg)
+	}
+	if got["status"] != "Running" || translator.calls != 1 {
+		t.Errorf("status = %v after %d translations, want Running after 1", got["status"], translator.calls)
+	}
+}
+
+func TestAfterToolCallbackKeepsResultOnTranslationError(t *testing.T) {
+	m := New(&prefixTranslator{err: errors.New("unavailable")}, nil, logr.Discard())
+	ctx := fakeContext{state: mapState{StateKeyUserLanguage: "es"}}
+	result := map[string]any{"message": "Back-off restarting the failed container in the pod"}
+	got, err := m.AfterToolCallback(ctx, fakeTool{}, nil, result, nil)
+	if err != nil || got != nil {
+		t.Fatalf("AfterToolCallback() = %v, %v; want the original result kept", got, err)
+	}
+}
+
+func TestAfterToolCallbackSelectsTools(t *testing.T) {
+	ctx := fakeContext{state: mapState{StateKeyUserLanguage: "es"}}
+	result := map[string]any{"content": "Back-off restarting the failed container in the pod"}
+	tests := []struct {
+		tools []string
+		tool  string
+		want  bool
+	}{
+		{tool: "get_events", want: true},
+		{tool: "read_file"},
+		{tool: "bash"},
+		{tool: "git_diff"},
+		{tools: []string{"k8s_*"}, tool: "k8s_get_events", want: true},
+		{tools: []string{"k8s_*"}, tool: "get_events"},
+		{tools: []string{"*", "!k8s_*"}, tool: "k8s_get_events"},
+		{tools: []string{"*"}, tool: "read_file", want: true},
+	}
+	for _, tt := range tests {
+		translator := &prefixTranslator{}
+		m := New(translator, tt.tools, logr.Discard())
+		got, err := m.AfterToolCallback(ctx, fakeTool{name: tt.tool}, nil, result, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if translated := got != nil; translated != tt.want {
+			t.Errorf("tools %v: %s translated = %v, want %v", tt.tools, tt.tool, translated, tt.want)
+		}
+	}
+}
+
+func TestToolPatternsFromEnv(t *testing.T) {
+	t.Setenv(EnvTranslateTools, "")
+	if tools, err := toolPatternsFromEnv(); tools != nil || err != nil {
+		t.Errorf("toolPatternsFromEnv() = %v, %v; want nil for the defaults", tools, err)
+	}
+	t.Setenv(EnvTranslateTools, " k8s_*, !k8s_logs ,")
+	if tools, err := toolPatternsFromEnv(); err != nil || !slices.Equal(tools, []string{"k8s_*", "!k8s_logs"}) {
+		t.Errorf("toolPatternsFromEnv() = %v, %v", tools, err)
+	}
+	t.Setenv(EnvTranslateTools, "k8s_[")
+	if _, err := toolPatternsFromEnv(); err == nil {
+		t.Error("toolPatternsFromEnv() error = nil, want an invalid pattern error")
+	}
+}
@@ -0,0 +1,226 @@
+package language
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"google.golang.org/adk/agent"
+	adkplugin "google.golang.org/adk/plugin"
+	"google.golang.org/adk/tool"
+	"google.golang.org/genai"
+)
+
+const (
+	// EnvLanguageDetection enables language detection when set to "true".
+	EnvLanguageDetection = "KAGENT_LANGUAGE_DETECTION"
+	// EnvTranslateToolOutputs additionally translates tool outputs into the
+	// user's language when set to "true".
+	EnvTranslateToolOutputs = "KAGENT_TRANSLATE_TOOL_OUTPUTS"
+	// EnvTranslateTools selects the tools whose outputs are translated: a
+	// comma-separated list of tool name patterns (path.Match syntax), where
+	// a pattern starting with "!" excludes the tools it matches. Defaults to
+	// DefaultTranslateTools.
+	EnvTranslateTools = "KAGENT_TRANSLATE_TOOLS"
+
+	// StateKeyUserLanguage is the session state key holding the BCP 47 code of
+	// the user's language. Instructions can reference it as {user_language?}.
+	StateKeyUserLanguage = "user_language"
+)
+
+// minTranslateLength is the shortest tool output string worth translating;
+// shorter values are usually identifiers or status words.
+const minTranslateLength = 20
+
+// DefaultTranslateTools translates the outputs of every tool but the file,
+// shell and git tools, whose outputs are file contents, code and command
+// output that must reach the model verbatim.
+var DefaultTranslateTools = []string{
+	"*",
+	"!read_file", "!write_file", "!edit_file", "!multi_edit_file",
+	"!bash", "!execute_python", "!git_*",
+}
+
+// Middleware is an ADK plugin that records the user's language in session
+// state and, with a Translator, translates tool outputs into it.
+type Middleware struct {
+	translator Translator
+	tools      []string
+	logger     logr.Logger
+}
+
+// New creates a Middleware. translator may be nil to only detect the
+// language. tools selects the tools whose outputs are translated, in the
+// format of KAGENT_TRANSLATE_TOOLS; nil means DefaultTranslateTools.
+func New(translator Translator, tools []string, logger logr.Logger) *Middleware {
+	if tools == nil {
+		tools = DefaultTranslateTools
+	}
+	return &Middleware{translator: translator, tools: tools, logger: logger.WithName("language")}
+}
+
+// NewFromEnv returns a Middleware when KAGENT_LANGUAGE_DETECTION is "true",
+// or nil otherwise. newTranslator is only called when
+// KAGENT_TRANSLATE_TOOL_OUTPUTS is also "true".
+func NewFromEnv(logger logr.Logger, newTranslator func() (Translator, error)) (*Middleware, error) {
+	if !envEnabled(EnvLanguageDetection) {
+		return nil, nil
+	}
+	var translator Translator
+	if envEnabled(EnvTranslateToolOutputs) {
+		t, err := newTranslator()
+		if err != nil {
+			return nil, err
+		}
+		translator = t
+	}
+	tools, err := toolPatternsFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	return New(translator, tools, logger), nil
+}
+
+// toolPatternsFromEnv reads KAGENT_TRANSLATE_TOOLS, returning nil when it
+// is unset.
+func toolPatternsFromEnv() ([]string, error) {
+	v := strings.TrimSpace(os.Getenv(EnvTranslateTools))
+	if v == "" {
+		return nil, nil
+	}
+	tools := []string{}
+	for p := range strings.SplitSeq(v, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		if _, err := path.Match(strings.TrimPrefix(p, "!"), ""); err != nil {
+			return nil, fmt.Errorf("invalid %s pattern %q: %w", EnvTranslateTools, p, err)
+		}
+		tools = append(tools, p)
+	}
+	return tools, nil
+}
+
+// translates reports whether the outputs of the named tool are translated:
+// it must match a pattern of m.tools and no excluding one.
+func (m *Middleware) translates(name string) bool {
+	included := false
+	for _, p := range m.tools {
+		if exclude, ok := strings.CutPrefix(p, "!"); ok {
+			if matched, _ := path.Match(exclude, name); matched {
+				return false
+			}
+		} else if matched, _ := path.Match(p, name); matched {
+			included = true
+		}
+	}
+	return included
+}
+
+func envEnabled(name string) bool {
+	return strings.EqualFold(strings.TrimSpace(os.Getenv(name)), "true")
+}
+
+// ADKPlugin returns the Go ADK plugin registered with runner.PluginConfig.
+func (m *Middleware) ADKPlugin() (*adkplugin.Plugin, error) {
+	cfg := adkplugin.Config{
+		Name:                "kagent-language",
+		BeforeAgentCallback: m.BeforeAgentCallback,
+	}
+	if m.translator != nil {
+		cfg.AfterToolCallback = m.AfterToolCallback
+	}
+	return adkplugin.New(cfg)
+}
+
+// BeforeAgentCallback detects the language of the user's message. The
+// detected language is kept for the conversation, so short or ambiguous
+// messages do not reset it.
+func (m *Middleware) BeforeAgentCallback(ctx agent.CallbackContext) (*genai.Content, error) {
+	lang, ok := Detect(contentText(ctx.UserContent()))
+	if !ok {
+		return nil, nil
+	}
+	if current, _ := ctx.State().Get(StateKeyUserLanguage); current == lang {
+		return nil, nil
+	}
+	if err := ctx.State().Set(StateKeyUserLanguage, lang); err != nil {
+		m.logger.Error(err, "Failed to store user language", "language", lang)
+		return nil, nil
+	}
+	m.logger.V(1).Info("Detected user language", "language", lang, "sessionID", ctx.SessionID())
+	return nil, nil
+}
+
+// AfterToolCallback translates the strings of a tool result that are not in
+// the user's language, for the tools selected by the middleware's patterns.
+// Only JSON-shaped values (maps, []any and strings) are walked. The
+// untranslated string is kept when translation fails, so a translation
+// outage never fails the tool call.
+func (m *Middleware) AfterToolCallback(ctx tool.Context, t tool.Tool, _, result map[string]any, toolErr error) (map[string]any, error) {
+	if toolErr != nil || len(result) == 0 || !m.translates(t.Name()) {
+		return nil, nil
+	}
+	v, _ := ctx.State().Get(StateKeyUserLanguage)
+	target, _ := v.(string)
+	if target == "" {
+		return nil, nil
+	}
+
+	translated := false
+	var translate func(any) any
+	translate = func(value any) any {
+		switch v := value.(type) {
+		case string:
+			if len(v) < minTranslateLength {
+				return v
+			}
+			if lang, ok := Detect(v); !ok || lang == target {
+				return v
+			}
+			out, err := m.translator.Translate(ctx, v, target)
+			if err != nil {
+				m.logger.Error(err, "Failed to translate tool output", "tool", t.Name(), "language", target)
+				return v
+			}
+			translated = true
+			return out
+		case map[string]any:
+			out := make(map[string]any, len(v))
+			for key, item := range v {
+				out[key] = translate(item)
+			}
+			return out
+		case []any:
+			out := make([]any, len(v))
+			for i, item := range v {
+				out[i] = translate(item)
+			}
+			return out
+		default:
+			return v
+		}
+	}
+	out := translate(result).(map[string]any)
+	if !translated {
+		return nil, nil
+	}
+	return out, nil
+}
+
+// contentText joins the text parts of content.
+func contentText(content *genai.Content) string {
+	if content == nil {
+		return ""
+	}
+	var texts []string
+	for _, part := range content.Parts {
+		if part != nil && part.Text != "" {
+			texts = append(texts, part.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
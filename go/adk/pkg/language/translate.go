@@ -0,0 +1,54 @@
+package language
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	adkmodel "google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// Translator translates text into the language with BCP 47 code target.
+type Translator interface {
+	Translate(ctx context.Context, text, target string) (string, error)
+}
+
+// ModelTranslator translates with an LLM, typically the agent's own model.
+type ModelTranslator struct {
+	Model adkmodel.LLM
+}
+
+var _ Translator = (*ModelTranslator)(nil)
+
+// Translate implements Translator.
+func (t *ModelTranslator) Translate(ctx context.Context, text, target string) (string, error) {
+	req := &adkmodel.LLMRequest{
+		Contents: []*genai.Content{genai.NewContentFromText(text, genai.RoleUser)},
+		Config: &genai.GenerateContentConfig{
+			SystemInstruction: genai.NewContentFromText(fmt.Sprintf(
+				"Translate the user's text into the language with BCP 47 code %q. "+
+					"Keep code, identifiers, numbers, URLs and formatting unchanged. "+
+					"Reply with the translation only.", target), genai.RoleUser),
+		},
+	}
+
+	var out strings.Builder
+	for resp, err := range t.Model.GenerateContent(ctx, req, false) {
+		if err != nil {
+			return "", fmt.Errorf("failed to translate: %w", err)
+		}
+		if resp == nil || resp.Content == nil {
+			continue
+		}
+		for _, part := range resp.Content.Parts {
+			if part != nil {
+				out.WriteString(part.Text)
+			}
+		}
+	}
+	if out.Len() == 0 {
+		return "", fmt.Errorf("translation returned no text")
+	}
+	return out.String(), nil
+}
@@ -0,0 +1,99 @@
+package loadgen
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	a2atype "github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2aclient"
+	"github.com/a2aproject/a2a-go/a2aclient/agentcard"
+)
+
+// A2ASender sends each message to an agent over A2A, in a fresh context.
+type A2ASender struct {
+	client *a2aclient.Client
+	stream bool
+}
+
+// NewA2ASender resolves the agent card at baseURL and creates an A2A client.
+// With stream set, messages are sent with message/stream and the time to
+// the first event is reported.
+func NewA2ASender(ctx context.Context, baseURL string, httpClient *http.Client, stream bool) (*A2ASender, error) {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	card, err := agentcard.NewResolver(httpClient).Resolve(ctx, baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve agent card for %s: %w", baseURL, err)
+	}
+	client, err := a2aclient.NewFromCard(ctx, card, a2aclient.WithJSONRPCTransport(httpClient))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create A2A client for %s: %w", baseURL, err)
+	}
+	return &A2ASender{client: client, stream: stream}, nil
+}
+
+// Send implements Sender. A task ending failed, rejected or canceled is an
+// error.
+func (s *A2ASender) Send(ctx context.Context, text string) (Result, error) {
+	params := &a2atype.MessageSendParams{Message: a2atype.NewMessage(a2atype.MessageRoleUser, a2atype.TextPart{Text: text})}
+	if !s.stream {
+		result, err := s.client.SendMessage(ctx, params)
+		if err != nil {
+			return Result{}, fmt.Errorf("A2A request failed: %w", err)
+		}
+		if task, ok := result.(*a2atype.Task); ok {
+			return Result{}, taskError(task.Status)
+		}
+		return Result{}, nil
+	}
+
+	var res Result
+	start := time.Now()
+	for event, err := range s.client.SendStreamingMessage(ctx, params) {
+		if err != nil {
+			return Result{}, fmt.Errorf("A2A stream failed: %w", err)
+		}
+		if res.FirstEvent == 0 {
+			res.FirstEvent = time.Since(start)
+		}
+		switch e := event.(type) {
+		case *a2atype.Task:
+			if err := taskError(e.Status); err != nil {
+				return Result{}, err
+			}
+		case *a2atype.TaskStatusUpdateEvent:
+			if err := taskError(e.Status); err != nil {
+				return Result{}, err
+			}
+		}
+	}
+	if res.FirstEvent == 0 {
+		return Result{}, fmt.Errorf("A2A stream ended without events")
+	}
+	return res, nil
+}
+
+// taskError reports a task status that ended the task unsuccessfully.
+func taskError(status a2atype.TaskStatus) error {
+	switch status.State {
+	case a2atype.TaskStateFailed, a2atype.TaskStateRejected, a2atype.TaskStateCanceled:
+	default:
+		return nil
+	}
+	var texts []string
+	if status.Message != nil {
+		for _, part := range status.Message.Parts {
+			if tp, ok := part.(a2atype.TextPart); ok && tp.Text != "" {
+				texts = append(texts, tp.Text)
+			}
+		}
+	}
+	if len(texts) == 0 {
+		return fmt.Errorf("task %s", status.State)
+	}
+	return fmt.Errorf("task %s: %s", status.State, strings.Join(texts, " "))
+}
@@ -0,0 +1,185 @@
+// Package loadgen drives concurrent messages against an agent at a fixed
+// arrival rate and reports latency percentiles and errors.
+package loadgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Sender sends one message to the system under test and waits for its
+// complete reply.
+type Sender interface {
+	Send(ctx context.Context, text string) (Result, error)
+}
+
+// SenderFunc adapts a function to Sender.
+type SenderFunc func(ctx context.Context, text string) (Result, error)
+
+// Send implements Sender.
+func (f SenderFunc) Send(ctx context.Context, text string) (Result, error) {
+	return f(ctx, text)
+}
+
+// Result describes a successful send.
+type Result struct {
+	// FirstEvent is the time until the first streamed event arrived. It is
+	// zero for senders that do not stream.
+	FirstEvent time.Duration
+}
+
+// Config describes a load run. Messages start at Rate per second, open
+// loop, whether or not earlier ones have completed; arrivals finding
+// Concurrency messages in flight are dropped and counted.
+type Config struct {
+	// Rate is the number of messages started per second.
+	Rate float64
+	// Concurrency bounds the messages in flight.
+	Concurrency int
+	// Duration bounds the arrival phase. Zero means until Requests
+	// messages have started.
+	Duration time.Duration
+	// Requests bounds the number of arrivals. Zero means until Duration
+	// has elapsed.
+	Requests int
+	// Messages are sent in turn.
+	Messages []string
+	// Timeout bounds each message. Zero means no timeout.
+	Timeout time.Duration
+}
+
+// Validate reports a config that cannot run or would never stop.
+func (c *Config) Validate() error {
+	switch {
+	case c.Rate <= 0:
+		return fmt.Errorf("rate must be positive, got %v", c.Rate)
+	case c.Concurrency < 1:
+		return fmt.Errorf("concurrency must be at least 1, got %d", c.Concurrency)
+	case c.Duration < 0 || c.Requests < 0 || c.Timeout < 0:
+		return errors.New("duration, requests and timeout must not be negative")
+	case c.Duration == 0 && c.Requests == 0:
+		return errors.New("set a duration or a number of requests")
+	case len(c.Messages) == 0:
+		return errors.New("at least one message is required")
+	}
+	return nil
+}
+
+// maxErrorKinds bounds the distinct error messages kept in a report.
+const maxErrorKinds = 20
+
+// Run sends messages as cfg describes until the arrival phase ends or ctx is
+// done, waits for the messages in flight, and reports on them.
+func Run(ctx context.Context, cfg Config, sender Sender) (*Report, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	var (
+		mu          sync.Mutex
+		latencies   []time.Duration
+		firstEvents []time.Duration
+		report      = &Report{}
+	)
+	record := func(latency time.Duration, res Result, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			report.Failed++
+			report.addError(err)
+			return
+		}
+		report.Succeeded++
+		latencies = append(latencies, latency)
+		if res.FirstEvent > 0 {
+			firstEvents = append(firstEvents, res.FirstEvent)
+		}
+	}
+
+	arrivals := ctx
+	if cfg.Duration > 0 {
+		var cancel context.CancelFunc
+		arrivals, cancel = context.WithTimeout(ctx, cfg.Duration)
+		defer cancel()
+	}
+	interval := time.Duration(float64(time.Second) / cfg.Rate)
+	ticker := time.NewTicker(max(interval, time.Microsecond))
+	defer ticker.Stop()
+
+	slots := make(chan struct{}, cfg.Concurrency)
+	var wg sync.WaitGroup
+	start := time.Now()
+arrive:
+	for i := 0; cfg.Requests == 0 || i < cfg.Requests; i++ {
+		if i > 0 {
+			select {
+			case <-ticker.C:
+			case <-arrivals.Done():
+				break arrive
+			}
+		}
+		select {
+		case slots <- struct{}{}:
+		default:
+			report.Dropped++
+			continue
+		}
+		report.Sent++
+		text := cfg.Messages[i%len(cfg.Messages)]
+		wg.Go(func() {
+			defer func() { <-slots }()
+			sendCtx := ctx
+			if cfg.Timeout > 0 {
+				var cancel context.CancelFunc
+				sendCtx, cancel = context.WithTimeout(ctx, cfg.Timeout)
+				defer cancel()
+			}
+			began := time.Now()
+			res, err := sender.Send(sendCtx, text)
+			record(time.Since(began), res, err)
+		})
+	}
+	wg.Wait()
+
+	elapsed := time.Since(start)
+	report.DurationMs = elapsed.Milliseconds()
+	if elapsed > 0 {
+		report.Throughput = float64(report.Succeeded) / elapsed.Seconds()
+	}
+	report.Latency = percentiles(latencies)
+	if len(firstEvents) > 0 {
+		fe := percentiles(firstEvents)
+		report.FirstEvent = &fe
+	}
+	return report, nil
+}
+
+// percentiles returns the nearest-rank percentiles of durations, in
+// milliseconds.
+func percentiles(durations []time.Duration) Percentiles {
+	if len(durations) == 0 {
+		return Percentiles{}
+	}
+	sorted := slices.Clone(durations)
+	slices.Sort(sorted)
+	at := func(p float64) float64 {
+		rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+		return ms(sorted[min(max(rank, 0), len(sorted)-1)])
+	}
+	return Percentiles{
+		P50: at(0.50),
+		P90: at(0.90),
+		P95: at(0.95),
+		P99: at(0.99),
+		Max: ms(sorted[len(sorted)-1]),
+	}
+}
+
+func ms(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,144 @@
+package loadgen
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRun_CountsOutcomes(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		seen []string
+	)
+	sender := SenderFunc(func(_ context.Context, text string) (Result, error) {
+		mu.Lock()
+		seen = append(seen, text)
+		mu.Unlock()
+		if text == "fail" {
+			return Result{}, errors.New("boom")
+		}
+		return Result{FirstEvent: time.Millisecond}, nil
+	})
+
+	report, err := Run(context.Background(), Config{
+		Rate:        1000,
+		Concurrency: 4,
+		Requests:    6,
+		Messages:    []string{"hello", "fail", "again"},
+	}, sender)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if report.Sent != 6 || report.Succeeded != 4 || report.Failed != 2 || report.Dropped != 0 {
+		t.Errorf("report = %+v, want 6 sent, 4 succeeded, 2 failed", report)
+	}
+	if report.Errors["boom"] != 2 {
+		t.Errorf("errors = %v, want boom twice", report.Errors)
+	}
+	if report.FirstEvent == nil || report.FirstEvent.P50 != 1 {
+		t.Errorf("first event = %+v, want 1ms", report.FirstEvent)
+	}
+	if len(seen) != 6 {
+		t.Errorf("sent %d messages, want 6", len(seen))
+	}
+}
+
+func TestRun_DropsArrivalsOverConcurrency(t *testing.T) {
+	release := make(chan struct{})
+	sender := SenderFunc(func(context.Context, string) (Result, error) {
+		<-release
+		return Result{}, nil
+	})
+
+	done := make(chan *Report)
+	go func() {
+		report, _ := Run(context.Background(), Config{Rate: 1000, Concurrency: 1, Requests: 5, Messages: []string{"hi"}}, sender)
+		done <- report
+	}()
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+
+	report := <-done
+	if report.Sent != 1 || report.Dropped != 4 {
+		t.Errorf("sent %d, dropped %d; want 1 and 4", report.Sent, report.Dropped)
+	}
+}
+
+func TestRun_StopsAfterDuration(t *testing.T) {
+	sender := SenderFunc(func(context.Context, string) (Result, error) { return Result{}, nil })
+	start := time.Now()
+	report, err := Run(context.Background(), Config{Rate: 100, Concurrency: 10, Duration: 100 * time.Millisecond, Messages: []string{"hi"}}, sender)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Run() took %v, want about 100ms", elapsed)
+	}
+	if report.Sent == 0 || report.Sent > 15 {
+		t.Errorf("sent %d messages in 100ms at 100/s", report.Sent)
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	valid := Config{Rate: 1, Concurrency: 1, Requests: 1, Messages: []string{"hi"}}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	for name, mutate := range map[string]func(*Config){
+		"no rate":        func(c *Config) { c.Rate = 0 },
+		"no concurrency": func(c *Config) { c.Concurrency = 0 },
+		"never stops":    func(c *Config) { c.Requests = 0 },
+		"no messages":    func(c *Config) { c.Messages = nil },
+	} {
+		cfg := valid
+		mutate(&cfg)
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("%s: Validate() error = nil", name)
+		}
+	}
+}
+
+func TestPercentiles(t *testing.T) {
+	var durations []time.Duration
+	for i := 100; i >= 1; i-- {
+		durations = append(durations, time.Duration(i)*time.Millisecond)
+	}
+	p := percentiles(durations)
+	if p.P50 != 50 || p.P90 != 90 || p.P95 != 95 || p.P99 != 99 || p.Max != 100 {
+		t.Errorf("percentiles = %+v", p)
+	}
+	if got := percentiles(nil); got != (Percentiles{}) {
+		t.Errorf("percentiles(nil) = %+v, want zero", got)
+	}
+}
+
+func TestReport_Check(t *testing.T) {
+	r := &Report{Sent: 100, Failed: 5, Latency: Percentiles{P95: 200, P99: 400}}
+	if err := r.Check(Thresholds{MaxP95Ms: 250, MaxErrorRate: 0.1}); err != nil {
+		t.Errorf("Check() error = %v, want none", err)
+	}
+	err := r.Check(Thresholds{MaxP95Ms: 100, MaxP99Ms: 300, MaxErrorRate: 0.01})
+	if err == nil {
+		t.Fatal("Check() error = nil, want exceeded thresholds")
+	}
+	for _, want := range []string{"p95", "p99", "error rate"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Check() error = %q, want it to mention %s", err, want)
+		}
+	}
+}
+
+func TestReport_WriteText(t *testing.T) {
+	r := &Report{Sent: 2, Succeeded: 1, Failed: 1, Errors: map[string]int{"boom": 1}}
+	var b strings.Builder
+	if err := r.WriteText(&b); err != nil {
+		t.Fatalf("WriteText() error = %v", err)
+	}
+	if !strings.Contains(b.String(), "failed 1 (50.00%)") || !strings.Contains(b.String(), "boom") {
+		t.Errorf("WriteText() = %q", b.String())
+	}
+}
@@ -0,0 +1,109 @@
+package loadgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Report summarizes a load run. Latencies cover successful messages only.
+type Report struct {
+	Sent       int          `json:"sent"`
+	Succeeded  int          `json:"succeeded"`
+	Failed     int          `json:"failed"`
+	Dropped    int          `json:"dropped"`
+	DurationMs int64        `json:"duration_ms"`
+	Throughput float64      `json:"throughput_per_sec"`
+	Latency    Percentiles  `json:"latency_ms"`
+	FirstEvent *Percentiles `json:"first_event_ms,omitempty"`
+	// Errors counts failures by error message.
+	Errors map[string]int `json:"errors,omitempty"`
+}
+
+// Percentiles are latency percentiles in milliseconds.
+type Percentiles struct {
+	P50 float64 `json:"p50"`
+	P90 float64 `json:"p90"`
+	P95 float64 `json:"p95"`
+	P99 float64 `json:"p99"`
+	Max float64 `json:"max"`
+}
+
+// ErrorRate is the share of sent messages that failed.
+func (r *Report) ErrorRate() float64 {
+	if r.Sent == 0 {
+		return 0
+	}
+	return float64(r.Failed) / float64(r.Sent)
+}
+
+func (r *Report) addError(err error) {
+	if r.Errors == nil {
+		r.Errors = make(map[string]int)
+	}
+	msg := err.Error()
+	if _, ok := r.Errors[msg]; !ok && len(r.Errors) >= maxErrorKinds {
+		msg = "other errors"
+	}
+	r.Errors[msg]++
+}
+
+// Thresholds are the limits a run must stay within. Zero values are not
+// checked.
+type Thresholds struct {
+	MaxP95Ms     float64
+	MaxP99Ms     float64
+	MaxErrorRate float64
+}
+
+// Check returns an error naming every threshold the report exceeds.
+func (r *Report) Check(t Thresholds) error {
+	var exceeded []string
+	if t.MaxP95Ms > 0 && r.Latency.P95 > t.MaxP95Ms {
+		exceeded = append(exceeded, fmt.Sprintf("p95 latency %.1fms exceeds %.1fms", r.Latency.P95, t.MaxP95Ms))
+	}
+	if t.MaxP99Ms > 0 && r.Latency.P99 > t.MaxP99Ms {
+		exceeded = append(exceeded, fmt.Sprintf("p99 latency %.1fms exceeds %.1fms", r.Latency.P99, t.MaxP99Ms))
+	}
+	if t.MaxErrorRate > 0 && r.ErrorRate() > t.MaxErrorRate {
+		exceeded = append(exceeded, fmt.Sprintf("error rate %.2f%% exceeds %.2f%%", 100*r.ErrorRate(), 100*t.MaxErrorRate))
+	}
+	if len(exceeded) == 0 {
+		return nil
+	}
+	return fmt.Errorf("thresholds exceeded: %s", strings.Join(exceeded, "; "))
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteText writes the report as a human-readable summary.
+func (r *Report) WriteText(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "sent %d, succeeded %d, failed %d (%.2f%%), dropped %d in %.1fs\n",
+		r.Sent, r.Succeeded, r.Failed, 100*r.ErrorRate(), r.Dropped, float64(r.DurationMs)/1000)
+	fmt.Fprintf(&b, "throughput %.2f/s\n", r.Throughput)
+	writePercentiles(&b, "latency", r.Latency)
+	if r.FirstEvent != nil {
+		writePercentiles(&b, "first event", *r.FirstEvent)
+	}
+	if len(r.Errors) > 0 {
+		b.WriteString("errors:\n")
+		for _, msg := range slices.Sorted(maps.Keys(r.Errors)) {
+			fmt.Fprintf(&b, "  %6d  %s\n", r.Errors[msg], msg)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writePercentiles(b *strings.Builder, name string, p Percentiles) {
+	fmt.Fprintf(b, "%s ms: p50 %.1f  p90 %.1f  p95 %.1f  p99 %.1f  max %.1f\n", name, p.P50, p.P90, p.P95, p.P99, p.Max)
+}
@@ -0,0 +1,164 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"github.com/kagent-dev/kagent/go/api/adk"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/tool"
+)
+
+// RegisteredServer is an MCP server registered while the agent runs, rather
+// than declared in its config. Exactly one of HTTP and SSE is set.
+type RegisteredServer struct {
+	// Name identifies the server; registering a name again replaces it.
+	Name string                   `json:"name"`
+	HTTP *adk.HttpMcpServerConfig `json:"http,omitempty"`
+	SSE  *adk.SseMcpServerConfig  `json:"sse,omitempty"`
+}
+
+var registeredServerName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,62}$`)
+
+// Validate checks that s describes one MCP server reached over http or
+// https. Approval and retry settings are rejected: they are wired when the
+// agent is created, so they would not apply to a server registered later.
+func (s RegisteredServer) Validate() error {
+	if !registeredServerName.MatchString(s.Name) {
+		return fmt.Errorf("server name %q must be 1 to 63 letters, digits, _ or -, starting with a letter or digit", s.Name)
+	}
+	var (
+		rawURL          string
+		tools, approval []string
+		retry           *adk.ToolRetryPolicy
+	)
+	switch {
+	case s.HTTP != nil && s.SSE == nil:
+		rawURL, tools, approval, retry = s.HTTP.Params.Url, s.HTTP.Tools, s.HTTP.RequireApproval, s.HTTP.RetryPolicy
+	case s.SSE != nil && s.HTTP == nil:
+		rawURL, tools, approval, retry = s.SSE.Params.Url, s.SSE.Tools, s.SSE.RequireApproval, s.SSE.RetryPolicy
+	default:
+		return fmt.Errorf("server %s needs exactly one of http and sse", s.Name)
+	}
+	if u, err := url.Parse(rawURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("server %s needs an http or https url, got %q", s.Name, rawURL)
+	}
+	if slices.Contains(tools, "") {
+		return fmt.Errorf("server %s lists an empty tool name", s.Name)
+	}
+	if len(approval) > 0 {
+		return fmt.Errorf("server %s: require_approval is not supported for registered servers; declare the server in the agent config instead", s.Name)
+	}
+	if retry != nil {
+		return fmt.Errorf("server %s: retry_policy is not supported for registered servers; declare the server in the agent config instead", s.Name)
+	}
+	return nil
+}
+
+// DynamicToolsets is a toolset listing the tools of the MCP servers
+// registered at runtime. The agent lists its tools before each model call,
+// so the tools of a registered server are available from the next call on,
+// without a restart. Add it to the agent's toolsets next to the configured
+// MCP servers.
+type DynamicToolsets struct {
+	propagateToken bool
+	log            logr.Logger
+
+	mu       sync.RWMutex
+	servers  []RegisteredServer
+	toolsets map[string]tool.Toolset
+}
+
+// NewDynamicToolsets returns an empty DynamicToolsets. When propagateToken
+// is true, Authorization is forwarded to the registered servers, as
+// CreateToolsets does for the configured ones.
+func NewDynamicToolsets(propagateToken bool, log logr.Logger) *DynamicToolsets {
+	return &DynamicToolsets{propagateToken: propagateToken, log: log, toolsets: map[string]tool.Toolset{}}
+}
+
+// Name implements tool.Toolset.
+func (d *DynamicToolsets) Name() string { return "registered_mcp_servers" }
+
+// Register validates s and adds its tools, replacing a server registered
+// under the same name.
+func (d *DynamicToolsets) Register(ctx context.Context, s RegisteredServer) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	var (
+		params mcpServerParams
+		tools  []string
+	)
+	if s.HTTP != nil {
+		params, tools = httpServerParams(*s.HTTP, d.propagateToken, nil), s.HTTP.Tools
+	} else {
+		params, tools = sseServerParams(*s.SSE, d.propagateToken, nil), s.SSE.Tools
+	}
+	if params.Headers == nil {
+		params.Headers = map[string]string{}
+	}
+	toolFilter := make(map[string]bool, len(tools))
+	for _, name := range tools {
+		toolFilter[name] = true
+	}
+	ts, err := initializeToolSet(ctx, params, toolFilter)
+	if err != nil {
+		return fmt.Errorf("server %s: %w", s.Name, err)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if i := slices.IndexFunc(d.servers, func(r RegisteredServer) bool { return r.Name == s.Name }); i >= 0 {
+		d.servers[i] = s
+	} else {
+		d.servers = append(d.servers, s)
+	}
+	d.toolsets[s.Name] = ts
+	d.log.Info("Registered MCP server", "name", s.Name, "url", params.URL)
+	return nil
+}
+
+// Servers returns the registered servers in the order they were first
+// registered.
+func (d *DynamicToolsets) Servers() []RegisteredServer {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return slices.Clone(d.servers)
+}
+
+// Tools implements tool.Toolset. A server whose tools cannot be listed is
+// logged and skipped, and a tool name already listed by an earlier server
+// is dropped, so a bad registration never stops the agent.
+func (d *DynamicToolsets) Tools(ctx agent.ReadonlyContext) ([]tool.Tool, error) {
+	d.mu.RLock()
+	servers := slices.Clone(d.servers)
+	toolsets := make([]tool.Toolset, len(servers))
+	for i, s := range servers {
+		toolsets[i] = d.toolsets[s.Name]
+	}
+	d.mu.RUnlock()
+
+	var out []tool.Tool
+	seen := map[string]string{}
+	for i, ts := range toolsets {
+		tools, err := ts.Tools(ctx)
+		if err != nil {
+			d.log.Error(err, "Failed to list the tools of a registered MCP server", "name", servers[i].Name)
+			continue
+		}
+		for _, t := range tools {
+			if first, ok := seen[t.Name()]; ok {
+				d.log.V(1).Info("Dropping colliding tool of a registered MCP server", "tool", t.Name(), "name", servers[i].Name, "keptServer", first)
+				continue
+			}
+			seen[t.Name()] = servers[i].Name
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,60 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/kagent-dev/kagent/go/api/adk"
+)
+
+func TestRegisteredServer_Validate(t *testing.T) {
+	valid := RegisteredServer{Name: "search", HTTP: &adk.HttpMcpServerConfig{Params: adk.StreamableHTTPConnectionParams{Url: "http://search:8080/mcp"}}}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	for name, s := range map[string]RegisteredServer{
+		"bad name":  {Name: "-search", HTTP: valid.HTTP},
+		"no server": {Name: "search"},
+		"both":      {Name: "search", HTTP: valid.HTTP, SSE: &adk.SseMcpServerConfig{Params: adk.SseConnectionParams{Url: "http://search:8080/sse"}}},
+		"bad url":   {Name: "search", SSE: &adk.SseMcpServerConfig{Params: adk.SseConnectionParams{Url: "file:///etc/passwd"}}},
+		"empty tool": {Name: "search", HTTP: &adk.HttpMcpServerConfig{
+			Params: valid.HTTP.Params, Tools: []string{""},
+		}},
+		"approval": {Name: "search", HTTP: &adk.HttpMcpServerConfig{
+			Params: valid.HTTP.Params, RequireApproval: []string{"delete"},
+		}},
+		"retry": {Name: "search", HTTP: &adk.HttpMcpServerConfig{
+			Params: valid.HTTP.Params, RetryPolicy: &adk.ToolRetryPolicy{MaxAttempts: 3},
+		}},
+	} {
+		if err := s.Validate(); err == nil {
+			t.Errorf("%s: Validate() error = nil", name)
+		}
+	}
+}
+
+func TestDynamicToolsets_Register(t *testing.T) {
+	ctx := context.Background()
+	d := NewDynamicToolsets(false, logr.Discard())
+	search := RegisteredServer{Name: "search", HTTP: &adk.HttpMcpServerConfig{Params: adk.StreamableHTTPConnectionParams{Url: "http://search:8080/mcp"}}}
+	docs := RegisteredServer{Name: "docs", SSE: &adk.SseMcpServerConfig{Params: adk.SseConnectionParams{Url: "https://docs/sse"}}}
+	for _, s := range []RegisteredServer{search, docs} {
+		if err := d.Register(ctx, s); err != nil {
+			t.Fatalf("Register(%s) error = %v", s.Name, err)
+		}
+	}
+	if err := d.Register(ctx, RegisteredServer{Name: "broken"}); err == nil {
+		t.Error("Register() of an invalid server error = nil")
+	}
+
+	search.HTTP = &adk.HttpMcpServerConfig{Params: adk.StreamableHTTPConnectionParams{Url: "http://search-v2:8080/mcp"}}
+	if err := d.Register(ctx, search); err != nil {
+		t.Fatalf("Register() again error = %v", err)
+	}
+	servers := d.Servers()
+	if len(servers) != 2 || servers[0].Name != "search" || servers[0].HTTP.Params.Url != "http://search-v2:8080/mcp" || servers[1].Name != "docs" {
+		t.Errorf("Servers() = %+v, want search replaced in place, then docs", servers)
+	}
+}
@@ -0,0 +1,100 @@
+package mcp
+
+import (
+	"fmt"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+	"google.golang.org/genai"
+)
+
+// ToolPrefixSeparator joins a server's tool prefix and a tool name.
+const ToolPrefixSeparator = "__"
+
+// ToolName returns the name the model sees for an MCP server tool: name
+// itself without a prefix, or <prefix>__<name> with one.
+func ToolName(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + ToolPrefixSeparator + name
+}
+
+// prefixedToolset exposes the tools of an MCP toolset under
+// <prefix>__<tool> names. Calls still reach the server with the original
+// tool name.
+type prefixedToolset struct {
+	tool.Toolset
+	prefix string
+}
+
+func (p *prefixedToolset) Tools(ctx agent.ReadonlyContext) ([]tool.Tool, error) {
+	tools, err := p.Toolset.Tools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]tool.Tool, 0, len(tools))
+	for _, t := range tools {
+		ft, ok := t.(functionTool)
+		if !ok {
+			return nil, fmt.Errorf("MCP tool %q cannot be prefixed: it has no function declaration", t.Name())
+		}
+		out = append(out, &prefixedTool{functionTool: ft, name: ToolName(p.prefix, t.Name())})
+	}
+	return out, nil
+}
+
+// functionTool is satisfied by the tools of an mcptoolset.
+type functionTool interface {
+	tool.Tool
+	Declaration() *genai.FunctionDeclaration
+	Run(ctx tool.Context, args any) (map[string]any, error)
+}
+
+// prefixedTool renames an MCP tool. Run is delegated unchanged, so the MCP
+// server is called with the tool's own name.
+type prefixedTool struct {
+	functionTool
+	name string
+}
+
+func (t *prefixedTool) Name() string { return t.name }
+
+func (t *prefixedTool) Declaration() *genai.FunctionDeclaration {
+	decl := t.functionTool.Declaration()
+	if decl == nil {
+		return nil
+	}
+	renamed := *decl
+	renamed.Name = t.name
+	return &renamed
+}
+
+// ProcessRequest adds the renamed declaration to the request. The wrapped
+// tool's ProcessRequest would register it under its original name.
+func (t *prefixedTool) ProcessRequest(_ tool.Context, req *model.LLMRequest) error {
+	if req.Tools == nil {
+		req.Tools = make(map[string]any)
+	}
+	if _, ok := req.Tools[t.name]; ok {
+		return fmt.Errorf("duplicate tool: %q", t.name)
+	}
+	req.Tools[t.name] = t
+
+	decl := t.Declaration()
+	if decl == nil {
+		return nil
+	}
+	if req.Config == nil {
+		req.Config = &genai.GenerateContentConfig{}
+	}
+	for _, gt := range req.Config.Tools {
+		if gt != nil && gt.FunctionDeclarations != nil {
+			gt.FunctionDeclarations = append(gt.FunctionDeclarations, decl)
+			return nil
+		}
+	}
+	req.Config.Tools = append(req.Config.Tools, &genai.Tool{FunctionDeclarations: []*genai.FunctionDeclaration{decl}})
+	return nil
+}
@@ -0,0 +1,70 @@
+package mcp
+
+import (
+	"testing"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+	"google.golang.org/genai"
+)
+
+type stubTool struct {
+	tool.Tool
+	name string
+}
+
+func (s stubTool) Name() string { return s.name }
+
+func (s stubTool) Declaration() *genai.FunctionDeclaration {
+	return &genai.FunctionDeclaration{Name: s.name, Description: "stub"}
+}
+
+func (s stubTool) Run(tool.Context, any) (map[string]any, error) {
+	return map[string]any{"called": s.name}, nil
+}
+
+type stubToolset struct {
+	tool.Toolset
+	tools []tool.Tool
+}
+
+func (s stubToolset) Tools(agent.ReadonlyContext) ([]tool.Tool, error) { return s.tools, nil }
+
+func TestToolName(t *testing.T) {
+	if got := ToolName("", "get_pods"); got != "get_pods" {
+		t.Errorf("ToolName without prefix = %q, want get_pods", got)
+	}
+	if got := ToolName("k8s", "get_pods"); got != "k8s__get_pods" {
+		t.Errorf("ToolName with prefix = %q, want k8s__get_pods", got)
+	}
+}
+
+func TestPrefixedToolset(t *testing.T) {
+	ts := &prefixedToolset{Toolset: stubToolset{tools: []tool.Tool{stubTool{name: "get_pods"}}}, prefix: "k8s"}
+	tools, err := ts.Tools(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tools) != 1 || tools[0].Name() != "k8s__get_pods" {
+		t.Fatalf("tools = %v, want one k8s__get_pods tool", tools)
+	}
+
+	req := &model.LLMRequest{}
+	if err := tools[0].(*prefixedTool).ProcessRequest(nil, req); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := req.Tools["k8s__get_pods"]; !ok {
+		t.Errorf("request tools = %v, want k8s__get_pods", req.Tools)
+	}
+	decls := req.Config.Tools[0].FunctionDeclarations
+	if len(decls) != 1 || decls[0].Name != "k8s__get_pods" {
+		t.Errorf("declarations = %v, want k8s__get_pods", decls)
+	}
+
+	// The server is still called with the tool's own name.
+	result, err := tools[0].(*prefixedTool).Run(nil, nil)
+	if err != nil || result["called"] != "get_pods" {
+		t.Errorf("Run() = %v, %v; want the get_pods tool called", result, err)
+	}
+}
@@ -62,6 +62,20 @@ func allowedRequestHeaders(ctx context.Context, allowed []string) map[string]str
 	return result
 }
 
+// ServerToolset is a toolset backed by a single MCP server.
+type ServerToolset interface {
+	tool.Toolset
+	// ServerURL returns the URL of the MCP server the tools come from.
+	ServerURL() string
+}
+
+type serverToolset struct {
+	tool.Toolset
+	url string
+}
+
+func (s *serverToolset) ServerURL() string { return s.url }
+
 // mcpServerParams groups connection parameters for an MCP server,
 // reducing parameter sprawl across createTransport / initializeToolSet.
 type mcpServerParams struct {
@@ -76,6 +90,7 @@ type mcpServerParams struct {
 	TLSInsecureSkipVerify *bool
 	TLSCACertPath         *string
 	TLSDisableSystemCAs   *bool
+	ToolPrefix            string // exposes tools as <ToolPrefix>__<tool> when set
 }
 
 // CreateToolsets creates toolsets from all configured HTTP and SSE MCP servers,
@@ -100,19 +115,7 @@ func CreateToolsets(
 
 	log.Info("Processing HTTP MCP tools", "httpToolsCount", len(httpTools))
 	for i, httpTool := range httpTools {
-		params := mcpServerParams{
-			URL:                   httpTool.Params.Url,
-			Headers:               httpTool.Params.Headers,
-			AllowedHeaders:        httpTool.AllowedHeaders,
-			PropagateToken:        propagateToken,
-			HeaderProvider:        headerProvider,
-			ServerType:            "http",
-			Timeout:               httpTool.Params.Timeout,
-			SseReadTimeout:        httpTool.Params.SseReadTimeout,
-			TLSInsecureSkipVerify: httpTool.Params.TLSInsecureSkipVerify,
-			TLSCACertPath:         httpTool.Params.TLSCACertPath,
-			TLSDisableSystemCAs:   httpTool.Params.TLSDisableSystemCAs,
-		}
+		params := httpServerParams(httpTool, propagateToken, headerProvider)
 		ts, err := addToolset(ctx, log, params, httpTool.Tools, "HTTP", i+1)
 		if err != nil {
 			continue
@@ -122,19 +125,7 @@ func CreateToolsets(
 
 	log.Info("Processing SSE MCP tools", "sseToolsCount", len(sseTools))
 	for i, sseTool := range sseTools {
-		params := mcpServerParams{
-			URL:                   sseTool.Params.Url,
-			Headers:               sseTool.Params.Headers,
-			AllowedHeaders:        sseTool.AllowedHeaders,
-			PropagateToken:        propagateToken,
-			HeaderProvider:        headerProvider,
-			ServerType:            "sse",
-			Timeout:               sseTool.Params.Timeout,
-			SseReadTimeout:        sseTool.Params.SseReadTimeout,
-			TLSInsecureSkipVerify: sseTool.Params.TLSInsecureSkipVerify,
-			TLSCACertPath:         sseTool.Params.TLSCACertPath,
-			TLSDisableSystemCAs:   sseTool.Params.TLSDisableSystemCAs,
-		}
+		params := sseServerParams(sseTool, propagateToken, headerProvider)
 		ts, err := addToolset(ctx, log, params, sseTool.Tools, "SSE", i+1)
 		if err != nil {
 			continue
@@ -145,6 +136,43 @@ func CreateToolsets(
 	return toolsets
 }
 
+// httpServerParams returns the connection parameters of a streamable HTTP
+// MCP server.
+func httpServerParams(cfg adk.HttpMcpServerConfig, propagateToken bool, headerProvider DynamicHeaderProvider) mcpServerParams {
+	return mcpServerParams{
+		URL:                   cfg.Params.Url,
+		Headers:               cfg.Params.Headers,
+		AllowedHeaders:        cfg.AllowedHeaders,
+		PropagateToken:        propagateToken,
+		HeaderProvider:        headerProvider,
+		ServerType:            "http",
+		Timeout:               cfg.Params.Timeout,
+		SseReadTimeout:        cfg.Params.SseReadTimeout,
+		TLSInsecureSkipVerify: cfg.Params.TLSInsecureSkipVerify,
+		TLSCACertPath:         cfg.Params.TLSCACertPath,
+		TLSDisableSystemCAs:   cfg.Params.TLSDisableSystemCAs,
+		ToolPrefix:            cfg.ToolPrefix,
+	}
+}
+
+// sseServerParams returns the connection parameters of an SSE MCP server.
+func sseServerParams(cfg adk.SseMcpServerConfig, propagateToken bool, headerProvider DynamicHeaderProvider) mcpServerParams {
+	return mcpServerParams{
+		URL:                   cfg.Params.Url,
+		Headers:               cfg.Params.Headers,
+		AllowedHeaders:        cfg.AllowedHeaders,
+		PropagateToken:        propagateToken,
+		HeaderProvider:        headerProvider,
+		ServerType:            "sse",
+		Timeout:               cfg.Params.Timeout,
+		SseReadTimeout:        cfg.Params.SseReadTimeout,
+		TLSInsecureSkipVerify: cfg.Params.TLSInsecureSkipVerify,
+		TLSCACertPath:         cfg.Params.TLSCACertPath,
+		TLSDisableSystemCAs:   cfg.Params.TLSDisableSystemCAs,
+		ToolPrefix:            cfg.ToolPrefix,
+	}
+}
+
 // addToolset logs, initializes, and returns a single MCP toolset.
 func addToolset(ctx context.Context, log logr.Logger, params mcpServerParams, tools []string, label string, index int) (tool.Toolset, error) {
 	if params.Headers == nil {
@@ -335,5 +363,9 @@ func initializeToolSet(ctx context.Context, params mcpServerParams, toolFilter m
 		return nil, fmt.Errorf("failed to create MCP toolset for %s: %w", params.URL, err)
 	}
 
-	return toolset, nil
+	if params.ToolPrefix != "" {
+		toolset = &prefixedToolset{Toolset: toolset, prefix: params.ToolPrefix}
+	}
+
+	return &serverToolset{Toolset: toolset, url: params.URL}, nil
 }
@@ -25,7 +25,7 @@ type KagentMemoryService struct {
 	apiURL          string
 	client          *http.Client
 	ttlDays         int
-	embeddingClient *embedding.Client
+	embeddingClient embedding.Embedder
 	model           adkmodel.LLM // Optional: for session summarization
 }
 
@@ -107,7 +107,7 @@ func (s *KagentMemoryService) AddSessionToMemory(ctx context.Context, session ad
 	}
 
 	// Generate embeddings
-	embeddings, err := s.embeddingClient.Generate(ctx, contents)
+	embeddings, err := s.embeddingClient.Embed(ctx, contents)
 	if err != nil {
 		return fmt.Errorf("failed to generate embeddings: %w", err)
 	}
@@ -174,7 +174,7 @@ func (s *KagentMemoryService) SearchMemory(ctx context.Context, req *memory.Sear
 
 	// Generate embedding for the query. Without a valid embedding we cannot
 	// perform similarity search, so return empty results on failure.
-	embeddings, err := s.embeddingClient.Generate(ctx, []string{req.Query})
+	embeddings, err := s.embeddingClient.Embed(ctx, []string{req.Query})
 	if err != nil {
 		log.Error(err, "Failed to generate query embedding, returning empty results")
 		return &memory.SearchResponse{Memories: []memory.Entry{}}, nil
@@ -235,6 +235,7 @@ func (s *KagentMemoryService) SearchMemory(ctx context.Context, req *memory.Sear
 			},
 		}
 		memories = append(memories, memory.Entry{
+			ID:      item.ID,
 			Content: content,
 		})
 	}
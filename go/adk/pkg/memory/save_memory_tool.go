@@ -23,7 +23,7 @@ func NewSaveMemoryTool(svc *KagentMemoryService) (tool.Tool, error) {
 		}
 
 		// Generate embedding for the content.
-		embeddings, err := svc.embeddingClient.Generate(toolCtx, []string{in.Content})
+		embeddings, err := svc.embeddingClient.Embed(toolCtx, []string{in.Content})
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate embedding: %w", err)
 		}
@@ -29,12 +29,16 @@ func anthropicPassthroughOpts(ctx context.Context, cfg *AnthropicConfig) []optio
 // AnthropicConfig holds Anthropic configuration
 type AnthropicConfig struct {
 	TransportConfig
-	Model       string
-	BaseUrl     string // Optional: override API base URL
-	MaxTokens   *int
-	Temperature *float64
-	TopP        *float64
-	TopK        *int
+	Model         string
+	BaseUrl       string // Optional: override API base URL
+	MaxTokens     *int
+	Temperature   *float64
+	TopP          *float64
+	TopK          *int
+	StopSequences []string
+	// ThinkingBudgetTokens enables extended thinking with the given token
+	// budget. Thinking blocks are returned as thought parts.
+	ThinkingBudgetTokens *int
 }
 
 // AnthropicModel implements model.LLM for Anthropic Claude models.
@@ -47,7 +51,9 @@ type AnthropicModel struct {
 // NewAnthropicModelWithLogger creates a new Anthropic model instance with a logger
 func NewAnthropicModelWithLogger(config *AnthropicConfig, logger logr.Logger) (*AnthropicModel, error) {
 	apiKey := "passthrough" // placeholder; real auth set per-request by transport
-	if !config.APIKeyPassthrough {
+	if config.KeyPool.Len() > 0 {
+		apiKey = "pool" // placeholder; the key pool sets a key per request
+	} else if !config.APIKeyPassthrough {
 		apiKey = os.Getenv("ANTHROPIC_API_KEY")
 		if apiKey == "" {
 			return nil, fmt.Errorf("ANTHROPIC_API_KEY environment variable is not set")
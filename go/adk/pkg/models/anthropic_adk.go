@@ -7,6 +7,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"iter"
+	"maps"
+	"slices"
 	"strings"
 
 	"github.com/anthropics/anthropic-sdk-go"
@@ -32,6 +34,17 @@ func anthropicStopReasonToGenai(reason anthropic.StopReason) genai.FinishReason
 	}
 }
 
+// anthropicThinking accumulates a streamed thinking or redacted_thinking block.
+// For redacted blocks, signature holds the opaque data payload and text is empty.
+type anthropicThinking struct {
+	text      strings.Builder
+	signature string
+}
+
+func (t *anthropicThinking) part() *genai.Part {
+	return &genai.Part{Text: t.text.String(), Thought: true, ThoughtSignature: []byte(t.signature)}
+}
+
 // Name implements model.LLM.
 func (m *AnthropicModel) Name() string {
 	return m.Config.Model
@@ -73,6 +86,7 @@ func (m *AnthropicModel) GenerateContent(ctx context.Context, req *model.LLMRequ
 
 		// Apply config options
 		applyAnthropicConfig(&params, m.Config)
+		applyAnthropicGenerateConfig(&params, req.Config)
 
 		// Add tools if provided
 		if req.Config != nil && len(req.Config.Tools) > 0 {
@@ -91,14 +105,57 @@ func applyAnthropicConfig(params *anthropic.MessageNewParams, cfg *AnthropicConf
 	if cfg == nil {
 		return
 	}
-	if cfg.Temperature != nil {
-		params.Temperature = anthropic.Float(*cfg.Temperature)
-	}
 	if cfg.TopP != nil {
 		params.TopP = anthropic.Float(*cfg.TopP)
 	}
-	if cfg.TopK != nil {
-		params.TopK = anthropic.Int(int64(*cfg.TopK))
+	if cfg.ThinkingBudgetTokens != nil && *cfg.ThinkingBudgetTokens > 0 {
+		// Extended thinking is incompatible with temperature and top_k, and
+		// max_tokens must leave room for the answer after the thinking budget.
+		budget := int64(*cfg.ThinkingBudgetTokens)
+		params.Thinking = anthropic.ThinkingConfigParamOfEnabled(budget)
+		if params.MaxTokens <= budget {
+			params.MaxTokens = budget + defaultAnthropicMaxTokens
+		}
+	} else {
+		if cfg.Temperature != nil {
+			params.Temperature = anthropic.Float(*cfg.Temperature)
+		}
+		if cfg.TopK != nil {
+			params.TopK = anthropic.Int(int64(*cfg.TopK))
+		}
+	}
+	if len(cfg.StopSequences) > 0 {
+		params.StopSequences = cfg.StopSequences
+	}
+}
+
+// applyAnthropicGenerateConfig applies the sampling parameters of the
+// request, which carry the agent's generation settings, over the model's own.
+// Temperature and top_k are left out while extended thinking is enabled.
+func applyAnthropicGenerateConfig(params *anthropic.MessageNewParams, cfg *genai.GenerateContentConfig) {
+	if cfg == nil {
+		return
+	}
+	thinking := params.Thinking.OfEnabled != nil
+	if cfg.MaxOutputTokens > 0 {
+		params.MaxTokens = int64(cfg.MaxOutputTokens)
+		if thinking && params.MaxTokens <= params.Thinking.OfEnabled.BudgetTokens {
+			params.MaxTokens = params.Thinking.OfEnabled.BudgetTokens + defaultAnthropicMaxTokens
+		}
+	}
+	if cfg.TopP != nil {
+		params.TopP = anthropic.Float(float64(*cfg.TopP))
+	}
+	if !thinking {
+		if cfg.Temperature != nil {
+			params.Temperature = anthropic.Float(float64(*cfg.Temperature))
+		}
+		if cfg.TopK != nil {
+			params.TopK = anthropic.Int(int64(*cfg.TopK))
+		}
+	}
+	if len(cfg.StopSequences) > 0 {
+		params.StopSequences = cfg.StopSequences
 	}
 }
 
@@ -139,6 +196,7 @@ func genaiContentsToAnthropicMessages(contents []*genai.Content, config *genai.G
 		}
 
 		var textParts []string
+		var thinkingBlocks []anthropic.ContentBlockParamUnion
 		var functionCalls []*genai.FunctionCall
 		var imageParts []struct {
 			mimeType string
@@ -149,6 +207,12 @@ func genaiContentsToAnthropicMessages(contents []*genai.Content, config *genai.G
 			if part == nil {
 				continue
 			}
+			if part.Thought {
+				if block, ok := thoughtPartToAnthropicBlock(part); ok {
+					thinkingBlocks = append(thinkingBlocks, block)
+				}
+				continue
+			}
 			if part.Text != "" {
 				textParts = append(textParts, part.Text)
 			} else if part.FunctionCall != nil {
@@ -163,8 +227,9 @@ func genaiContentsToAnthropicMessages(contents []*genai.Content, config *genai.G
 
 		// Handle assistant messages with tool use
 		if len(functionCalls) > 0 && (role == "model" || role == "assistant") {
-			// Build assistant message with tool use blocks
-			var contentBlocks []anthropic.ContentBlockParamUnion
+			// Build assistant message with tool use blocks. Thinking blocks must
+			// precede tool_use blocks so the API can verify their signatures.
+			contentBlocks := thinkingBlocks
 			if len(textParts) > 0 {
 				contentBlocks = append(contentBlocks, anthropic.NewTextBlock(strings.Join(textParts, "\n")))
 			}
@@ -221,6 +286,19 @@ func genaiContentsToAnthropicMessages(contents []*genai.Content, config *genai.G
 	return messages, systemPrompt
 }
 
+// thoughtPartToAnthropicBlock converts a thought part produced by this model
+// back into a thinking block. Thought parts without a signature (e.g. from
+// other providers) cannot be replayed and are dropped.
+func thoughtPartToAnthropicBlock(part *genai.Part) (anthropic.ContentBlockParamUnion, bool) {
+	if len(part.ThoughtSignature) == 0 {
+		return anthropic.ContentBlockParamUnion{}, false
+	}
+	if part.Text == "" {
+		return anthropic.NewRedactedThinkingBlock(string(part.ThoughtSignature)), true
+	}
+	return anthropic.NewThinkingBlock(string(part.ThoughtSignature), part.Text), true
+}
+
 func genaiToolsToAnthropicTools(tools []*genai.Tool) []anthropic.ToolUnionParam {
 	var out []anthropic.ToolUnionParam
 	for _, t := range tools {
@@ -273,6 +351,7 @@ func runAnthropicStreaming(ctx context.Context, m *AnthropicModel, params anthro
 		name      string
 		inputJSON string
 	})
+	thinkingBlocks := make(map[int]*anthropicThinking)
 	var stopReason anthropic.StopReason
 	var inputTokens, outputTokens int64
 
@@ -284,7 +363,8 @@ func runAnthropicStreaming(ctx context.Context, m *AnthropicModel, params anthro
 			inputTokens = e.Message.Usage.InputTokens
 		case anthropic.ContentBlockStartEvent:
 			idx := int(e.Index)
-			if e.ContentBlock.Type == "tool_use" {
+			switch e.ContentBlock.Type {
+			case "tool_use":
 				if toolUse, ok := e.ContentBlock.AsAny().(anthropic.ToolUseBlock); ok {
 					toolUseBlocks[idx] = struct {
 						id        string
@@ -292,6 +372,12 @@ func runAnthropicStreaming(ctx context.Context, m *AnthropicModel, params anthro
 						inputJSON string
 					}{id: toolUse.ID, name: toolUse.Name, inputJSON: ""}
 				}
+			case "thinking":
+				thinkingBlocks[idx] = &anthropicThinking{}
+			case "redacted_thinking":
+				if redacted, ok := e.ContentBlock.AsAny().(anthropic.RedactedThinkingBlock); ok {
+					thinkingBlocks[idx] = &anthropicThinking{signature: redacted.Data}
+				}
 			}
 		case anthropic.ContentBlockDeltaEvent:
 			idx := int(e.Index)
@@ -308,6 +394,25 @@ func runAnthropicStreaming(ctx context.Context, m *AnthropicModel, params anthro
 						return
 					}
 				}
+			case "thinking_delta":
+				if thinkingDelta, ok := delta.AsAny().(anthropic.ThinkingDelta); ok {
+					if block, exists := thinkingBlocks[idx]; exists {
+						block.text.WriteString(thinkingDelta.Thinking)
+					}
+					if !yield(&model.LLMResponse{
+						Partial:      true,
+						TurnComplete: false,
+						Content:      &genai.Content{Role: string(genai.RoleModel), Parts: []*genai.Part{{Text: thinkingDelta.Thinking, Thought: true}}},
+					}, nil) {
+						return
+					}
+				}
+			case "signature_delta":
+				if signatureDelta, ok := delta.AsAny().(anthropic.SignatureDelta); ok {
+					if block, exists := thinkingBlocks[idx]; exists {
+						block.signature += signatureDelta.Signature
+					}
+				}
 			case "input_json_delta":
 				if jsonDelta, ok := delta.AsAny().(anthropic.InputJSONDelta); ok {
 					if block, exists := toolUseBlocks[idx]; exists {
@@ -330,8 +435,11 @@ func runAnthropicStreaming(ctx context.Context, m *AnthropicModel, params anthro
 		return
 	}
 
-	// Build final response
-	finalParts := make([]*genai.Part, 0, 1+len(toolUseBlocks))
+	// Build final response. Thinking comes first, in block order.
+	finalParts := make([]*genai.Part, 0, 1+len(thinkingBlocks)+len(toolUseBlocks))
+	for _, idx := range slices.Sorted(maps.Keys(thinkingBlocks)) {
+		finalParts = append(finalParts, thinkingBlocks[idx].part())
+	}
 	aggregatedTextValue := aggregatedText.String()
 	if aggregatedTextValue != "" {
 		finalParts = append(finalParts, &genai.Part{Text: aggregatedTextValue})
@@ -381,6 +489,14 @@ func runAnthropicNonStreaming(ctx context.Context, m *AnthropicModel, params ant
 			if textBlock, ok := block.AsAny().(anthropic.TextBlock); ok {
 				parts = append(parts, &genai.Part{Text: textBlock.Text})
 			}
+		case "thinking":
+			if thinking, ok := block.AsAny().(anthropic.ThinkingBlock); ok {
+				parts = append(parts, &genai.Part{Text: thinking.Thinking, Thought: true, ThoughtSignature: []byte(thinking.Signature)})
+			}
+		case "redacted_thinking":
+			if redacted, ok := block.AsAny().(anthropic.RedactedThinkingBlock); ok {
+				parts = append(parts, &genai.Part{Thought: true, ThoughtSignature: []byte(redacted.Data)})
+			}
 		case "tool_use":
 			if toolUse, ok := block.AsAny().(anthropic.ToolUseBlock); ok {
 				// Convert input to map[string]interface{}
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"google.golang.org/genai"
+)
+
+func TestApplyAnthropicConfig_Thinking(t *testing.T) {
+	temp := 0.5
+	topK := 40
+	budget := 4096
+
+	t.Run("thinking disabled keeps sampling params", func(t *testing.T) {
+		params := anthropic.MessageNewParams{MaxTokens: defaultAnthropicMaxTokens}
+		applyAnthropicConfig(&params, &AnthropicConfig{Temperature: &temp, TopK: &topK})
+		if !params.Temperature.Valid() || params.Temperature.Value != temp {
+			t.Errorf("Temperature = %v, want %v", params.Temperature, temp)
+		}
+		if !params.TopK.Valid() || params.TopK.Value != int64(topK) {
+			t.Errorf("TopK = %v, want %v", params.TopK, topK)
+		}
+		if params.Thinking.OfEnabled != nil {
+			t.Error("Thinking should not be enabled")
+		}
+	})
+
+	t.Run("thinking enabled drops temperature and top_k", func(t *testing.T) {
+		params := anthropic.MessageNewParams{MaxTokens: defaultAnthropicMaxTokens}
+		applyAnthropicConfig(&params, &AnthropicConfig{Temperature: &temp, TopK: &topK, ThinkingBudgetTokens: &budget})
+		if params.Thinking.OfEnabled == nil || params.Thinking.OfEnabled.BudgetTokens != int64(budget) {
+			t.Fatalf("Thinking = %+v, want enabled with budget %d", params.Thinking, budget)
+		}
+		if params.Temperature.Valid() {
+			t.Error("Temperature must not be sent with thinking enabled")
+		}
+		if params.TopK.Valid() {
+			t.Error("TopK must not be sent with thinking enabled")
+		}
+		if params.MaxTokens != defaultAnthropicMaxTokens {
+			t.Errorf("MaxTokens = %d, want %d", params.MaxTokens, defaultAnthropicMaxTokens)
+		}
+	})
+
+	t.Run("max_tokens raised above budget", func(t *testing.T) {
+		large := 16000
+		params := anthropic.MessageNewParams{MaxTokens: defaultAnthropicMaxTokens}
+		applyAnthropicConfig(&params, &AnthropicConfig{ThinkingBudgetTokens: &large})
+		if params.MaxTokens <= int64(large) {
+			t.Errorf("MaxTokens = %d, want > %d", params.MaxTokens, large)
+		}
+	})
+}
+
+func TestGenaiContentsToAnthropicMessages_Thinking(t *testing.T) {
+	contents := []*genai.Content{
+		{Role: "user", Parts: []*genai.Part{{Text: "what time is it?"}}},
+		{
+			Role: "model",
+			Parts: []*genai.Part{
+				{Text: "I should call the clock tool.", Thought: true, ThoughtSignature: []byte("sig1")},
+				{Thought: true, ThoughtSignature: []byte("redacted-data")},
+				{Text: "unsigned thought from another provider", Thought: true},
+				{FunctionCall: &genai.FunctionCall{ID: "call_1", Name: "get_time", Args: map[string]any{}}},
+			},
+		},
+		{Role: "user", Parts: []*genai.Part{{FunctionResponse: &genai.FunctionResponse{ID: "call_1", Name: "get_time", Response: map[string]any{"result": "noon"}}}}},
+	}
+
+	msgs, _ := genaiContentsToAnthropicMessages(contents, nil)
+	if len(msgs) != 3 {
+		t.Fatalf("want 3 messages, got %d", len(msgs))
+	}
+
+	blocks := msgs[1].Content
+	if len(blocks) != 3 {
+		t.Fatalf("assistant message: want 3 blocks (thinking, redacted, tool_use), got %d", len(blocks))
+	}
+	if blocks[0].OfThinking == nil || blocks[0].OfThinking.Signature != "sig1" || blocks[0].OfThinking.Thinking != "I should call the clock tool." {
+		t.Errorf("block 0 = %+v, want signed thinking block", blocks[0])
+	}
+	if blocks[1].OfRedactedThinking == nil || blocks[1].OfRedactedThinking.Data != "redacted-data" {
+		t.Errorf("block 1 = %+v, want redacted thinking block", blocks[1])
+	}
+	if blocks[2].OfToolUse == nil || blocks[2].OfToolUse.ID != "call_1" {
+		t.Errorf("block 2 = %+v, want tool_use block", blocks[2])
+	}
+
+	for _, b := range msgs[0].Content {
+		if b.OfThinking != nil || b.OfRedactedThinking != nil {
+			t.Error("user message must not contain thinking blocks")
+		}
+	}
+}
@@ -20,10 +20,16 @@ type TransportConfig struct {
 	TLSDisableSystemCAs   *bool
 	APIKeyPassthrough     bool
 	Timeout               *int // seconds; nil = defaultTimeout
+	// KeyPool, when set, sends each request with one of its keys instead
+	// of the client's own.
+	KeyPool *KeyPool
 }
 
 // BuildHTTPClient creates an http.Client with the full transport stack:
-// TLS → custom headers → timeout.
+// TLS → wire logging (when KAGENT_LLM_WIRE_LOG is set) → custom headers →
+// key pool → timeout. Wire logging sits below the custom headers so it
+// records them, and the key pool above them so each retry on another key
+// goes through the whole stack.
 func BuildHTTPClient(tc TransportConfig) (*http.Client, error) {
 	transport, err := BuildTLSTransport(
 		http.DefaultTransport,
@@ -35,10 +41,22 @@ func BuildHTTPClient(tc TransportConfig) (*http.Client, error) {
 		return nil, err
 	}
 
+	wireLog, err := WireLogConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	if wireLog.Enabled {
+		transport = &wireLogTransport{base: transport, config: wireLog}
+	}
+
 	if len(tc.Headers) > 0 {
 		transport = &headerTransport{base: transport, headers: tc.Headers}
 	}
 
+	if tc.KeyPool.Len() > 0 {
+		transport = tc.KeyPool.wrap(transport)
+	}
+
 	timeout := defaultTimeout
 	if tc.Timeout != nil {
 		timeout = time.Duration(*tc.Timeout) * time.Second
@@ -200,7 +200,7 @@ func (m *BedrockModel) GenerateContent(ctx context.Context, req *model.LLMReques
 
 		// temperature/top_p must not be sent when thinking is active. https://docs.aws.amazon.com/bedrock/latest/userguide/claude-messages-extended-thinking.html
 		_, thinkingEnabled := m.Config.AdditionalModelRequestFields["thinking"]
-		inferenceConfig := buildInferenceConfig(m.Config, thinkingEnabled)
+		inferenceConfig := applyBedrockGenerateConfig(buildInferenceConfig(m.Config, thinkingEnabled), req.Config, thinkingEnabled)
 
 		// Build system prompt
 		var systemPrompt []types.SystemContentBlock
@@ -803,3 +803,33 @@ func buildInferenceConfig(cfg *BedrockConfig, thinkingEnabled bool) *types.Infer
 	}
 	return ic
 }
+
+// applyBedrockGenerateConfig applies the sampling parameters of the request,
+// which carry the agent's generation settings, over those in ic, allocating
+// it when needed. Temperature and top_p are left out while thinking is on.
+func applyBedrockGenerateConfig(ic *types.InferenceConfiguration, cfg *genai.GenerateContentConfig, thinkingEnabled bool) *types.InferenceConfiguration {
+	if cfg == nil {
+		return ic
+	}
+	set := func() *types.InferenceConfiguration {
+		if ic == nil {
+			ic = &types.InferenceConfiguration{}
+		}
+		return ic
+	}
+	if cfg.MaxOutputTokens > 0 {
+		set().MaxTokens = aws.Int32(cfg.MaxOutputTokens)
+	}
+	if !thinkingEnabled {
+		if cfg.Temperature != nil {
+			set().Temperature = aws.Float32(*cfg.Temperature)
+		}
+		if cfg.TopP != nil {
+			set().TopP = aws.Float32(*cfg.TopP)
+		}
+	}
+	if len(cfg.StopSequences) > 0 {
+		set().StopSequences = cfg.StopSequences
+	}
+	return ic
+}
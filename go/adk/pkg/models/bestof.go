@@ -0,0 +1,257 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"github.com/kagent-dev/kagent/go/adk/pkg/telemetry"
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// CandidateGenerator is implemented by models whose provider can return
+// several candidates for one request in a single call.
+type CandidateGenerator interface {
+	GenerateCandidates(ctx context.Context, req *model.LLMRequest, n int) ([]*model.LLMResponse, error)
+}
+
+// CandidateScorer scores the candidates generated for req. The candidate
+// with the highest score is kept; ties go to the earliest.
+type CandidateScorer interface {
+	Score(ctx context.Context, req *model.LLMRequest, candidates []*model.LLMResponse) ([]float64, error)
+}
+
+// CandidateScorerFunc adapts a function to CandidateScorer.
+type CandidateScorerFunc func(ctx context.Context, req *model.LLMRequest, candidates []*model.LLMResponse) ([]float64, error)
+
+// Score implements CandidateScorer.
+func (f CandidateScorerFunc) Score(ctx context.Context, req *model.LLMRequest, candidates []*model.LLMResponse) ([]float64, error) {
+	return f(ctx, req, candidates)
+}
+
+// HeuristicScorer prefers candidates that completed normally over truncated
+// ones, and those over empty or failed ones.
+var HeuristicScorer CandidateScorer = CandidateScorerFunc(func(_ context.Context, _ *model.LLMRequest, candidates []*model.LLMResponse) ([]float64, error) {
+	scores := make([]float64, len(candidates))
+	for i, c := range candidates {
+		scores[i] = heuristicScore(c)
+	}
+	return scores, nil
+})
+
+func heuristicScore(c *model.LLMResponse) float64 {
+	if c == nil || c.ErrorCode != "" || c.Content == nil {
+		return 0
+	}
+	hasOutput := false
+	for _, p := range c.Content.Parts {
+		if p != nil && !p.Thought && (strings.TrimSpace(p.Text) != "" || p.FunctionCall != nil) {
+			hasOutput = true
+			break
+		}
+	}
+	switch {
+	case !hasOutput:
+		return 0
+	case c.FinishReason == genai.FinishReasonMaxTokens:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// JudgeScorer asks a model which candidate best answers the conversation.
+// The chosen candidate scores 1 and the others 0.
+type JudgeScorer struct {
+	Model model.LLM
+}
+
+var judgeChoiceRe = regexp.MustCompile(`\d+`)
+
+// Score implements CandidateScorer.
+func (j *JudgeScorer) Score(ctx context.Context, req *model.LLMRequest, candidates []*model.LLMResponse) ([]float64, error) {
+	var prompt strings.Builder
+	prompt.WriteString("Several candidate replies were generated for the conversation below. ")
+	prompt.WriteString("Choose the one that best answers the user: correct, complete, and using tools appropriately.\n\n")
+	prompt.WriteString("Conversation:\n")
+	for _, c := range req.Contents {
+		if c != nil {
+			fmt.Fprintf(&prompt, "[%s] %s\n", c.Role, renderContent(c))
+		}
+	}
+	for i, c := range candidates {
+		fmt.Fprintf(&prompt, "\nCandidate %d:\n%s\n", i+1, renderContent(c.Content))
+	}
+	fmt.Fprintf(&prompt, "\nReply with only the number of the best candidate, between 1 and %d.", len(candidates))
+
+	judgeReq := &model.LLMRequest{
+		Model:    j.Model.Name(),
+		Contents: []*genai.Content{genai.NewContentFromText(prompt.String(), genai.RoleUser)},
+		Config:   &genai.GenerateContentConfig{Temperature: genai.Ptr[float32](0)},
+	}
+	resp, err := finalResponse(j.Model.GenerateContent(ctx, judgeReq, false))
+	if err != nil {
+		return nil, fmt.Errorf("judge call failed: %w", err)
+	}
+	choice, err := strconv.Atoi(judgeChoiceRe.FindString(renderContent(resp.Content)))
+	if err != nil || choice < 1 || choice > len(candidates) {
+		return nil, fmt.Errorf("judge gave no valid candidate number")
+	}
+	scores := make([]float64, len(candidates))
+	scores[choice-1] = 1
+	return scores, nil
+}
+
+// renderContent renders the text and function calls of c for a judge prompt.
+func renderContent(c *genai.Content) string {
+	if c == nil {
+		return ""
+	}
+	var parts []string
+	for _, p := range c.Parts {
+		switch {
+		case p == nil || p.Thought:
+		case p.Text != "":
+			parts = append(parts, p.Text)
+		case p.FunctionCall != nil:
+			args, _ := json.Marshal(p.FunctionCall.Args)
+			parts = append(parts, fmt.Sprintf("call %s(%s)", p.FunctionCall.Name, args))
+		case p.FunctionResponse != nil:
+			resp, _ := json.Marshal(p.FunctionResponse.Response)
+			parts = append(parts, fmt.Sprintf("result of %s: %s", p.FunctionResponse.Name, resp))
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// BestOfModel samples several candidates for each request and returns the
+// one its scorer rates highest. Models implementing CandidateGenerator are
+// asked for all candidates in one call; others are called in parallel.
+// Responses are never streamed, since the best candidate is only known once
+// all are complete. The selected candidate reports the usage of all of them,
+// and the rejected ones are recorded on the trace.
+type BestOfModel struct {
+	model.LLM
+	candidates int
+	scorer     CandidateScorer
+}
+
+// NewBestOfModel wraps llm to keep the best of n candidates. A nil scorer
+// means HeuristicScorer.
+func NewBestOfModel(llm model.LLM, n int, scorer CandidateScorer) *BestOfModel {
+	if scorer == nil {
+		scorer = HeuristicScorer
+	}
+	return &BestOfModel{LLM: llm, candidates: n, scorer: scorer}
+}
+
+// GenerateContent implements model.LLM.
+func (m *BestOfModel) GenerateContent(ctx context.Context, req *model.LLMRequest, _ bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		candidates, err := m.generate(ctx, req)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		scores, err := m.scorer.Score(ctx, req, candidates)
+		if err != nil || len(scores) != len(candidates) {
+			if err == nil {
+				err = fmt.Errorf("scorer returned %d scores for %d candidates", len(scores), len(candidates))
+			}
+			logr.FromContextOrDiscard(ctx).Info("Candidate scoring failed, using the heuristic", "error", err.Error())
+			scores, _ = HeuristicScorer.Score(ctx, req, candidates)
+		}
+		best := 0
+		for i, s := range scores {
+			if s > scores[best] {
+				best = i
+			}
+		}
+
+		selected := *candidates[best]
+		selected.UsageMetadata = sumUsage(candidates)
+		rejected := make([]*model.LLMResponse, 0, len(candidates)-1)
+		for i, c := range candidates {
+			if i != best {
+				rejected = append(rejected, c)
+			}
+		}
+		telemetry.SetBestOfAttributes(ctx, len(candidates), best, scores, rejected)
+		yield(&selected, nil)
+	}
+}
+
+// generate returns the successful candidates, failing only when none is.
+func (m *BestOfModel) generate(ctx context.Context, req *model.LLMRequest) ([]*model.LLMResponse, error) {
+	if g, ok := m.LLM.(CandidateGenerator); ok {
+		return g.GenerateCandidates(ctx, req, m.candidates)
+	}
+
+	results := make([]*model.LLMResponse, m.candidates)
+	errs := make([]error, m.candidates)
+	var wg sync.WaitGroup
+	for i := range m.candidates {
+		wg.Go(func() {
+			results[i], errs[i] = finalResponse(m.LLM.GenerateContent(ctx, req, false))
+		})
+	}
+	wg.Wait()
+
+	var candidates []*model.LLMResponse
+	for _, r := range results {
+		if r != nil {
+			candidates = append(candidates, r)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, errors.Join(errs...)
+	}
+	return candidates, nil
+}
+
+// finalResponse drains a non-streamed response sequence, returning its last
+// complete response.
+func finalResponse(seq iter.Seq2[*model.LLMResponse, error]) (*model.LLMResponse, error) {
+	var last *model.LLMResponse
+	for resp, err := range seq {
+		if err != nil {
+			return nil, err
+		}
+		if resp != nil && !resp.Partial {
+			last = resp
+		}
+	}
+	if last == nil {
+		return nil, fmt.Errorf("model returned no response")
+	}
+	return last, nil
+}
+
+// sumUsage adds up the token usage of all candidates.
+func sumUsage(candidates []*model.LLMResponse) *genai.GenerateContentResponseUsageMetadata {
+	var total *genai.GenerateContentResponseUsageMetadata
+	for _, c := range candidates {
+		u := c.UsageMetadata
+		if u == nil {
+			continue
+		}
+		if total == nil {
+			total = &genai.GenerateContentResponseUsageMetadata{}
+		}
+		total.PromptTokenCount += u.PromptTokenCount
+		total.CandidatesTokenCount += u.CandidatesTokenCount
+		total.ThoughtsTokenCount += u.ThoughtsTokenCount
+		total.CachedContentTokenCount += u.CachedContentTokenCount
+		total.TotalTokenCount += u.TotalTokenCount
+	}
+	return total
+}
@@ -0,0 +1,133 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"sync/atomic"
+	"testing"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// cannedModel returns its responses in turn, one per call.
+type cannedModel struct {
+	responses []*model.LLMResponse
+	errs      []error
+	calls     atomic.Int32
+}
+
+func (m *cannedModel) Name() string { return "canned" }
+
+func (m *cannedModel) GenerateContent(context.Context, *model.LLMRequest, bool) iter.Seq2[*model.LLMResponse, error] {
+	i := int(m.calls.Add(1)-1) % len(m.responses)
+	return func(yield func(*model.LLMResponse, error) bool) {
+		if i < len(m.errs) && m.errs[i] != nil {
+			yield(nil, m.errs[i])
+			return
+		}
+		yield(m.responses[i], nil)
+	}
+}
+
+func textResponse(text string, finish genai.FinishReason, promptTokens int32) *model.LLMResponse {
+	return &model.LLMResponse{
+		Content:       genai.NewContentFromText(text, genai.RoleModel),
+		FinishReason:  finish,
+		UsageMetadata: &genai.GenerateContentResponseUsageMetadata{PromptTokenCount: promptTokens},
+	}
+}
+
+func generateOne(t *testing.T, llm model.LLM) *model.LLMResponse {
+	t.Helper()
+	resp, err := finalResponse(llm.GenerateContent(context.Background(), &model.LLMRequest{
+		Contents: []*genai.Content{genai.NewContentFromText("hi", genai.RoleUser)},
+	}, true))
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	return resp
+}
+
+func TestBestOfModel_HeuristicPrefersCompleteAnswers(t *testing.T) {
+	inner := &cannedModel{responses: []*model.LLMResponse{
+		textResponse("", genai.FinishReasonStop, 10),
+		textResponse("cut off", genai.FinishReasonMaxTokens, 10),
+		textResponse("complete", genai.FinishReasonStop, 10),
+	}}
+
+	resp := generateOne(t, NewBestOfModel(inner, 3, nil))
+	if got := renderContent(resp.Content); got != "complete" {
+		t.Errorf("selected %q, want the complete answer", got)
+	}
+	if inner.calls.Load() != 3 {
+		t.Errorf("inner model called %d times, want 3", inner.calls.Load())
+	}
+	if resp.UsageMetadata == nil || resp.UsageMetadata.PromptTokenCount != 30 {
+		t.Errorf("usage = %+v, want the sum over all candidates", resp.UsageMetadata)
+	}
+}
+
+func TestBestOfModel_FailedCandidatesAreDropped(t *testing.T) {
+	inner := &cannedModel{
+		responses: []*model.LLMResponse{nil, textResponse("ok", genai.FinishReasonStop, 1)},
+		errs:      []error{errors.New("rate limited"), nil},
+	}
+	if got := renderContent(generateOne(t, NewBestOfModel(inner, 2, nil)).Content); got != "ok" {
+		t.Errorf("selected %q, want the successful candidate", got)
+	}
+
+	failing := &cannedModel{responses: []*model.LLMResponse{nil}, errs: []error{errors.New("down")}}
+	if _, err := finalResponse(NewBestOfModel(failing, 2, nil).GenerateContent(context.Background(), &model.LLMRequest{}, false)); err == nil {
+		t.Error("GenerateContent() error = nil, want an error when every candidate fails")
+	}
+}
+
+func TestBestOfModel_Judge(t *testing.T) {
+	// Native candidates keep their order, unlike parallel calls.
+	inner := &nativeModel{cannedModel: cannedModel{responses: []*model.LLMResponse{
+		textResponse("first", genai.FinishReasonStop, 1),
+		textResponse("second", genai.FinishReasonStop, 1),
+	}}}
+	judge := &cannedModel{responses: []*model.LLMResponse{textResponse("Candidate 2", genai.FinishReasonStop, 0)}}
+
+	resp := generateOne(t, NewBestOfModel(inner, 2, &JudgeScorer{Model: judge}))
+	if got := renderContent(resp.Content); got != "second" {
+		t.Errorf("selected %q, want the judge's choice", got)
+	}
+
+	// An unusable verdict falls back to the heuristic, which keeps the
+	// earliest of equal candidates.
+	judge = &cannedModel{responses: []*model.LLMResponse{textResponse("Candidate 7", genai.FinishReasonStop, 0)}}
+	resp = generateOne(t, NewBestOfModel(inner, 2, &JudgeScorer{Model: judge}))
+	if got := renderContent(resp.Content); got != "first" {
+		t.Errorf("selected %q, want the heuristic's choice", got)
+	}
+}
+
+// nativeModel implements CandidateGenerator.
+type nativeModel struct {
+	cannedModel
+	n int
+}
+
+func (m *nativeModel) GenerateCandidates(_ context.Context, _ *model.LLMRequest, n int) ([]*model.LLMResponse, error) {
+	m.n = n
+	return m.responses, nil
+}
+
+func TestBestOfModel_UsesNativeCandidates(t *testing.T) {
+	inner := &nativeModel{cannedModel: cannedModel{responses: []*model.LLMResponse{
+		textResponse("cut off", genai.FinishReasonMaxTokens, 5),
+		textResponse("complete", genai.FinishReasonStop, 0),
+	}}}
+
+	resp := generateOne(t, NewBestOfModel(inner, 2, nil))
+	if inner.n != 2 || inner.calls.Load() != 0 {
+		t.Errorf("GenerateCandidates n = %d, GenerateContent calls = %d; want one native call for 2", inner.n, inner.calls.Load())
+	}
+	if got := renderContent(resp.Content); got != "complete" {
+		t.Errorf("selected %q, want the complete answer", got)
+	}
+}
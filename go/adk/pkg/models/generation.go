@@ -0,0 +1,53 @@
+package models
+
+import (
+	"context"
+
+	"github.com/kagent-dev/kagent/go/api/adk"
+	"google.golang.org/genai"
+)
+
+type generationOverridesKey struct{}
+
+// WithGenerationOverrides attaches per-request sampling parameters to ctx.
+// They replace the agent's defaults for the model calls made under ctx.
+func WithGenerationOverrides(ctx context.Context, g *adk.GenerationConfig) context.Context {
+	if g == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, generationOverridesKey{}, g)
+}
+
+// GenerationOverridesFrom returns the overrides attached with
+// WithGenerationOverrides, or nil.
+func GenerationOverridesFrom(ctx context.Context) *adk.GenerationConfig {
+	g, _ := ctx.Value(generationOverridesKey{}).(*adk.GenerationConfig)
+	return g
+}
+
+// ApplyGenerationConfig sets the parameters held by g on cfg, allocating cfg
+// when it is nil, and returns it. Parameters g leaves unset are kept.
+func ApplyGenerationConfig(cfg *genai.GenerateContentConfig, g *adk.GenerationConfig) *genai.GenerateContentConfig {
+	if g == nil {
+		return cfg
+	}
+	if cfg == nil {
+		cfg = &genai.GenerateContentConfig{}
+	}
+	if g.Temperature != nil {
+		cfg.Temperature = genai.Ptr(float32(*g.Temperature))
+	}
+	if g.TopP != nil {
+		cfg.TopP = genai.Ptr(float32(*g.TopP))
+	}
+	if g.TopK != nil {
+		cfg.TopK = genai.Ptr(float32(*g.TopK))
+	}
+	if g.MaxOutputTokens != nil {
+		cfg.MaxOutputTokens = int32(*g.MaxOutputTokens)
+	}
+	if g.StopSequences != nil {
+		cfg.StopSequences = g.StopSequences
+	}
+	return cfg
+}
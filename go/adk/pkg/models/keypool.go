@@ -0,0 +1,249 @@
+package models
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// Headers the providers authenticate with. KeyHeaderAuthorization sends
+// the key as a bearer token.
+const (
+	KeyHeaderAuthorization = "Authorization"
+	KeyHeaderAzure         = "Api-Key"
+	KeyHeaderAnthropic     = "X-Api-Key"
+	KeyHeaderGemini        = "X-Goog-Api-Key"
+)
+
+var errNoPoolKey = errors.New("key pool has no keys")
+
+// defaultKeyCooldown is how long a key answering 429 is skipped when the
+// response does not say when to retry.
+const defaultKeyCooldown = 30 * time.Second
+
+// PoolKey is one API key of a KeyPool.
+type PoolKey struct {
+	// Name identifies the key in logs, such as the environment variable
+	// it was read from. The key itself is never logged.
+	Name   string
+	APIKey string
+	// BaseURL replaces the client's base URL for the key's requests.
+	// Empty keeps it.
+	BaseURL string
+	// Weight is the key's share of the requests. Defaults to 1.
+	Weight int
+}
+
+// KeyPool load-balances the requests of one model client over several API
+// keys by smooth weighted round robin. A key answering 429, or reporting
+// through the provider's rate limit headers that it has no requests left,
+// cools down until its limit resets; its request is retried on another
+// key when one is available. Build the client with the pool in
+// TransportConfig.KeyPool.
+type KeyPool struct {
+	header  string
+	baseURL string
+	logger  logr.Logger
+	now     func() time.Time
+
+	mu   sync.Mutex
+	keys []*poolKey
+}
+
+type poolKey struct {
+	PoolKey
+	current       int
+	cooldownUntil time.Time
+}
+
+// NewKeyPool returns a pool sending the keys in header. baseURL is the
+// base URL the client is built with, which the keys' BaseURL replace.
+func NewKeyPool(keys []PoolKey, header, baseURL string, logger logr.Logger) *KeyPool {
+	p := &KeyPool{header: header, baseURL: baseURL, logger: logger, now: time.Now}
+	for _, k := range keys {
+		if k.Weight <= 0 {
+			k.Weight = 1
+		}
+		p.keys = append(p.keys, &poolKey{PoolKey: k})
+	}
+	return p
+}
+
+// Len returns the number of keys.
+func (p *KeyPool) Len() int {
+	if p == nil {
+		return 0
+	}
+	return len(p.keys)
+}
+
+// next picks the key for a request, skipping those in exclude and those
+// cooling down. When every key is cooling down and fallback is set, the one
+// available soonest is used and the provider decides; otherwise nil is
+// returned.
+func (p *KeyPool) next(exclude map[*poolKey]bool, fallback bool) *poolKey {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := p.now()
+	var best, soonest *poolKey
+	total := 0
+	for _, k := range p.keys {
+		if exclude[k] {
+			continue
+		}
+		if now.Before(k.cooldownUntil) {
+			if soonest == nil || k.cooldownUntil.Before(soonest.cooldownUntil) {
+				soonest = k
+			}
+			continue
+		}
+		k.current += k.Weight
+		total += k.Weight
+		if best == nil || k.current > best.current {
+			best = k
+		}
+	}
+	if best == nil {
+		if fallback {
+			return soonest
+		}
+		return nil
+	}
+	best.current -= total
+	return best
+}
+
+// observe cools k down when resp shows it is rate limited, and reports
+// whether the request should be retried on another key.
+func (p *KeyPool) observe(k *poolKey, resp *http.Response) bool {
+	now := p.now()
+	var until time.Time
+	if resp.StatusCode == http.StatusTooManyRequests {
+		until = now.Add(defaultKeyCooldown)
+		if d, ok := retryAfter(resp.Header, now); ok {
+			until = now.Add(d)
+		}
+	} else if reset, ok := requestsExhausted(resp.Header, now); ok {
+		until = now.Add(reset)
+	} else {
+		return false
+	}
+
+	p.mu.Lock()
+	if until.After(k.cooldownUntil) {
+		k.cooldownUntil = until
+	}
+	p.mu.Unlock()
+	p.logger.Info("LLM API key cooling down", "key", k.Name, "status", resp.StatusCode, "until", until)
+	return resp.StatusCode == http.StatusTooManyRequests
+}
+
+// wrap returns base sending each request with a key of the pool.
+func (p *KeyPool) wrap(base http.RoundTripper) http.RoundTripper {
+	return &keyPoolTransport{base: base, pool: p}
+}
+
+type keyPoolTransport struct {
+	base http.RoundTripper
+	pool *KeyPool
+}
+
+func (t *keyPoolTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// A request answered 429 is retried on each other key that is not
+	// cooling down, then the last response is returned.
+	tried := map[*poolKey]bool{}
+	var resp *http.Response
+	for {
+		k := t.pool.next(tried, resp == nil)
+		if k == nil {
+			if resp != nil {
+				return resp, nil
+			}
+			return nil, errNoPoolKey
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+		tried[k] = true
+		attempt, err := t.pool.request(req, k)
+		if err != nil {
+			return nil, err
+		}
+		resp, err = t.base.RoundTrip(attempt)
+		if err != nil {
+			return nil, err
+		}
+		if !t.pool.observe(k, resp) || req.Body != nil && req.GetBody == nil {
+			return resp, nil
+		}
+	}
+}
+
+// request returns a copy of req sent with k: its key in the pool's header
+// and, when k has one, its base URL.
+func (p *KeyPool) request(req *http.Request, k *poolKey) (*http.Request, error) {
+	r := req.Clone(req.Context())
+	if req.Body != nil && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		r.Body = body
+	}
+	value := k.APIKey
+	if p.header == KeyHeaderAuthorization {
+		value = "Bearer " + value
+	}
+	r.Header.Set(p.header, value)
+	if k.BaseURL != "" && p.baseURL != "" {
+		if rest, ok := strings.CutPrefix(r.URL.String(), strings.TrimSuffix(p.baseURL, "/")); ok {
+			u, err := r.URL.Parse(strings.TrimSuffix(k.BaseURL, "/") + rest)
+			if err != nil {
+				return nil, err
+			}
+			r.URL = u
+			r.Host = u.Host
+		}
+	}
+	return r, nil
+}
+
+// retryAfter reads how long to wait from the retry-after-ms and
+// Retry-After headers.
+func retryAfter(h http.Header, now time.Time) (time.Duration, bool) {
+	if ms, err := strconv.ParseFloat(h.Get("Retry-After-Ms"), 64); err == nil && ms > 0 {
+		return time.Duration(ms * float64(time.Millisecond)), true
+	}
+	v := h.Get("Retry-After")
+	if secs, err := strconv.ParseFloat(v, 64); err == nil && secs > 0 {
+		return time.Duration(secs * float64(time.Second)), true
+	}
+	if at, err := http.ParseTime(v); err == nil && at.After(now) {
+		return at.Sub(now), true
+	}
+	return 0, false
+}
+
+// requestsExhausted reports when a key with no requests left in its rate
+// limit window gets them back, from the OpenAI (x-ratelimit-*) or
+// Anthropic (anthropic-ratelimit-*) headers.
+func requestsExhausted(h http.Header, now time.Time) (time.Duration, bool) {
+	if h.Get("X-Ratelimit-Remaining-Requests") == "0" {
+		if d, err := time.ParseDuration(h.Get("X-Ratelimit-Reset-Requests")); err == nil && d > 0 {
+			return d, true
+		}
+		return defaultKeyCooldown, true
+	}
+	if h.Get("Anthropic-Ratelimit-Requests-Remaining") == "0" {
+		if at, err := time.Parse(time.RFC3339, h.Get("Anthropic-Ratelimit-Requests-Reset")); err == nil && at.After(now) {
+			return at.Sub(now), true
+		}
+		return defaultKeyCooldown, true
+	}
+	return 0, false
+}
@@ -0,0 +1,105 @@
+package models
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+func TestKeyPool(t *testing.T) {
+	var limited map[string]http.Header
+	var seen []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		key := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		seen = append(seen, key+" "+r.URL.Path+" "+string(body))
+		if h, ok := limited[key]; ok {
+			for k, v := range h {
+				w.Header()[k] = v
+			}
+			if h.Get("Retry-After") != "" {
+				w.WriteHeader(http.StatusTooManyRequests)
+			}
+		}
+	}))
+	defer server.Close()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	pool := NewKeyPool([]PoolKey{
+		{Name: "KEY_A", APIKey: "a", Weight: 2},
+		{Name: "KEY_B", APIKey: "b"},
+		{Name: "KEY_C", APIKey: "c", BaseURL: server.URL + "/eu", Weight: 0},
+	}, KeyHeaderAuthorization, server.URL+"/v1/", logr.Discard())
+	pool.now = func() time.Time { return now }
+	client, err := BuildHTTPClient(TransportConfig{KeyPool: pool})
+	if err != nil {
+		t.Fatalf("BuildHTTPClient() error = %v", err)
+	}
+	send := func() int {
+		t.Helper()
+		resp, err := client.Post(server.URL+"/v1/chat", "application/json", strings.NewReader("{}"))
+		if err != nil {
+			t.Fatalf("Post() error = %v", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	for range 8 {
+		send()
+	}
+	want := "a /v1/chat {},b /v1/chat {},c /eu/chat {},a /v1/chat {}"
+	if got := strings.Join(seen[:4], ","); got != want {
+		t.Errorf("requests = %s, want %s", got, want)
+	}
+
+	// A 429 cools the key down and the request is retried on another key.
+	limited = map[string]http.Header{"a": {"Retry-After": {"60"}}}
+	seen = nil
+	for range 4 {
+		if code := send(); code != http.StatusOK {
+			t.Fatalf("status = %d, want the retry's 200", code)
+		}
+	}
+	if n := countKey(seen, "a"); n != 1 || len(seen) != 5 {
+		t.Errorf("requests = %v, want key a used once, then skipped", seen)
+	}
+
+	// A key reporting no requests left cools down without a retry.
+	limited = map[string]http.Header{"b": {"X-Ratelimit-Remaining-Requests": {"0"}, "X-Ratelimit-Reset-Requests": {"90s"}}}
+	now = now.Add(time.Minute)
+	seen = nil
+	for range 6 {
+		send()
+	}
+	if n := countKey(seen, "b"); n != 1 || len(seen) != 6 {
+		t.Errorf("requests = %v, want key b used once", seen)
+	}
+
+	// With every key cooling down, the request is still sent once.
+	limited = map[string]http.Header{"a": {"Retry-After": {"60"}}, "b": {"Retry-After": {"60"}}, "c": {"Retry-After": {"60"}}}
+	now = now.Add(2 * time.Minute)
+	seen = nil
+	if code := send(); code != http.StatusTooManyRequests || len(seen) != 3 {
+		t.Errorf("status = %d after %d requests, want 429 after trying each key", code, len(seen))
+	}
+	seen = nil
+	if code := send(); code != http.StatusTooManyRequests || len(seen) != 1 {
+		t.Errorf("status = %d after %d requests, want one 429 when all keys cool down", code, len(seen))
+	}
+}
+
+func countKey(requests []string, key string) int {
+	n := 0
+	for _, r := range requests {
+		if strings.HasPrefix(r, key+" ") {
+			n++
+		}
+	}
+	return n
+}
@@ -28,6 +28,7 @@ func (m *OllamaModel) GenerateContent(ctx context.Context, req *model.LLMRequest
 		if m.Config.Options != nil {
 			options = convertOllamaOptions(m.Config.Options)
 		}
+		options = applyOllamaGenerateConfig(options, req.Config)
 
 		// Convert content to Ollama messages
 		messages, systemInstruction := convertGenaiContentsToOllamaMessages(req.Contents)
@@ -420,3 +421,33 @@ func convertGenaiToolsToOllama(tools []*genai.Tool) []api.Tool {
 
 	return ollamaTools
 }
+
+// applyOllamaGenerateConfig applies the sampling parameters of the request,
+// which carry the agent's generation settings, over the model's options.
+func applyOllamaGenerateConfig(options map[string]any, cfg *genai.GenerateContentConfig) map[string]any {
+	if cfg == nil {
+		return options
+	}
+	set := func(key string, value any) {
+		if options == nil {
+			options = map[string]any{}
+		}
+		options[key] = value
+	}
+	if cfg.Temperature != nil {
+		set("temperature", float64(*cfg.Temperature))
+	}
+	if cfg.TopP != nil {
+		set("top_p", float64(*cfg.TopP))
+	}
+	if cfg.TopK != nil {
+		set("top_k", int(*cfg.TopK))
+	}
+	if cfg.MaxOutputTokens > 0 {
+		set("num_predict", int(cfg.MaxOutputTokens))
+	}
+	if len(cfg.StopSequences) > 0 {
+		set("stop", cfg.StopSequences)
+	}
+	return options
+}
@@ -48,7 +48,9 @@ type OpenAIModel struct {
 // NewOpenAIModelWithLogger creates a new OpenAI model instance with a logger
 func NewOpenAIModelWithLogger(config *OpenAIConfig, logger logr.Logger) (*OpenAIModel, error) {
 	apiKey := "passthrough" // placeholder; real auth set per-request by transport
-	if !config.APIKeyPassthrough {
+	if config.KeyPool.Len() > 0 {
+		apiKey = "pool" // placeholder; the key pool sets a key per request
+	} else if !config.APIKeyPassthrough {
 		apiKey = os.Getenv("OPENAI_API_KEY")
 		if apiKey == "" {
 			return nil, fmt.Errorf("OPENAI_API_KEY environment variable is not set")
@@ -124,7 +126,7 @@ func NewAzureOpenAIModelWithLogger(config *AzureOpenAIConfig, logger logr.Logger
 		option.WithMiddleware(azurePathRewriteMiddleware()),
 	}
 
-	if !config.APIKeyPassthrough {
+	if !config.APIKeyPassthrough && config.KeyPool.Len() == 0 {
 		apiKey := os.Getenv("AZURE_OPENAI_API_KEY")
 		if apiKey == "" {
 			return nil, fmt.Errorf("AZURE_OPENAI_API_KEY environment variable is not set")
@@ -128,60 +128,123 @@ func (m *OpenAIModel) Name() string {
 // GenerateContent implements model.LLM. Uses only ADK/genai types.
 func (m *OpenAIModel) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
 	return func(yield func(*model.LLMResponse, error) bool) {
-		messages, systemInstruction := genaiContentsToOpenAIMessages(req.Contents, req.Config)
-		modelName := req.Model
-		if modelName == "" {
-			modelName = m.Config.Model
-		}
-		if m.IsAzure && m.Config.Model != "" {
-			modelName = m.Config.Model
+		params := m.chatCompletionParams(ctx, req)
+		if stream {
+			runStreaming(ctx, m, params, yield)
+		} else {
+			runNonStreaming(ctx, m, params, yield)
 		}
-		telemetry.SetLLMRequestAttributes(ctx, modelName, req)
+	}
+}
 
-		params := openai.ChatCompletionNewParams{
-			Model:    shared.ChatModel(modelName),
-			Messages: messages,
-		}
-		if systemInstruction != "" {
-			params.Messages = append([]openai.ChatCompletionMessageParamUnion{
-				openai.SystemMessage(systemInstruction),
-			}, params.Messages...)
+// GenerateCandidates implements CandidateGenerator with the n parameter of
+// the chat completions API. The usage of the call is reported on the first
+// candidate.
+func (m *OpenAIModel) GenerateCandidates(ctx context.Context, req *model.LLMRequest, n int) ([]*model.LLMResponse, error) {
+	params := m.chatCompletionParams(ctx, req)
+	params.N = openai.Int(int64(n))
+	completion, err := m.Client.Chat.Completions.New(ctx, params, openAIPassthroughOpts(ctx, m)...)
+	if err != nil {
+		return nil, fmt.Errorf("OpenAI chat completion request failed: %w", err)
+	}
+	if len(completion.Choices) == 0 {
+		return nil, fmt.Errorf("OpenAI chat completion returned no choices")
+	}
+	candidates := make([]*model.LLMResponse, len(completion.Choices))
+	for i := range completion.Choices {
+		candidates[i] = chatChoiceToLLMResponse(completion, i)
+		if i > 0 {
+			candidates[i].UsageMetadata = nil
 		}
-		applyOpenAIConfig(&params, m.Config)
+	}
+	return candidates, nil
+}
 
-		if req.Config != nil && len(req.Config.Tools) > 0 {
-			params.Tools = genaiToolsToOpenAITools(req.Config.Tools)
-			params.ToolChoice = openai.ChatCompletionToolChoiceOptionUnionParam{
-				OfAuto: openai.String("auto"),
-			}
-		}
+// chatCompletionParams converts req to chat completion parameters.
+func (m *OpenAIModel) chatCompletionParams(ctx context.Context, req *model.LLMRequest) openai.ChatCompletionNewParams {
+	messages, systemInstruction := genaiContentsToOpenAIMessages(req.Contents, req.Config)
+	modelName := req.Model
+	if modelName == "" {
+		modelName = m.Config.Model
+	}
+	if m.IsAzure && m.Config.Model != "" {
+		modelName = m.Config.Model
+	}
+	telemetry.SetLLMRequestAttributes(ctx, modelName, req)
 
-		if stream {
-			runStreaming(ctx, m, params, yield)
-		} else {
-			runNonStreaming(ctx, m, params, yield)
+	params := openai.ChatCompletionNewParams{
+		Model:    shared.ChatModel(modelName),
+		Messages: messages,
+	}
+	if systemInstruction != "" {
+		params.Messages = append([]openai.ChatCompletionMessageParamUnion{
+			openai.SystemMessage(systemInstruction),
+		}, params.Messages...)
+	}
+	applyOpenAIConfig(&params, m.Config)
+	applyOpenAIGenerateConfig(&params, req.Config)
+
+	if req.Config != nil && len(req.Config.Tools) > 0 {
+		params.Tools = genaiToolsToOpenAITools(req.Config.Tools)
+		params.ToolChoice = openai.ChatCompletionToolChoiceOptionUnionParam{
+			OfAuto: openai.String("auto"),
 		}
 	}
+	return params
 }
 
-func applyOpenAIConfig(params *openai.ChatCompletionNewParams, cfg *OpenAIConfig) {
+// applyOpenAIGenerateConfig applies the sampling parameters of the request,
+// which carry the agent's generation settings, over the model's own.
+func applyOpenAIGenerateConfig(params *openai.ChatCompletionNewParams, cfg *genai.GenerateContentConfig) {
 	if cfg == nil {
 		return
 	}
-	if cfg.Temperature != nil {
-		params.Temperature = openai.Float(*cfg.Temperature)
-	}
-	if cfg.MaxTokens != nil {
-		params.MaxTokens = openai.Int(int64(*cfg.MaxTokens))
+	if isOpenAIReasoningModel(string(params.Model)) {
+		if cfg.MaxOutputTokens > 0 {
+			params.MaxCompletionTokens = openai.Int(int64(cfg.MaxOutputTokens))
+		}
+	} else {
+		if cfg.Temperature != nil {
+			params.Temperature = openai.Float(float64(*cfg.Temperature))
+		}
+		if cfg.TopP != nil {
+			params.TopP = openai.Float(float64(*cfg.TopP))
+		}
+		if cfg.MaxOutputTokens > 0 {
+			params.MaxTokens = openai.Int(int64(cfg.MaxOutputTokens))
+		}
 	}
-	if cfg.TopP != nil {
-		params.TopP = openai.Float(*cfg.TopP)
+	if len(cfg.StopSequences) > 0 {
+		params.Stop = openai.ChatCompletionNewParamsStopUnion{OfStringArray: cfg.StopSequences}
 	}
-	if cfg.FrequencyPenalty != nil {
-		params.FrequencyPenalty = openai.Float(*cfg.FrequencyPenalty)
+}
+
+func applyOpenAIConfig(params *openai.ChatCompletionNewParams, cfg *OpenAIConfig) {
+	if cfg == nil {
+		return
 	}
-	if cfg.PresencePenalty != nil {
-		params.PresencePenalty = openai.Float(*cfg.PresencePenalty)
+	// Reasoning models reject sampling parameters and the legacy max_tokens
+	// field, so map MaxTokens to max_completion_tokens and drop the rest.
+	if isOpenAIReasoningModel(string(params.Model)) {
+		if cfg.MaxTokens != nil {
+			params.MaxCompletionTokens = openai.Int(int64(*cfg.MaxTokens))
+		}
+	} else {
+		if cfg.Temperature != nil {
+			params.Temperature = openai.Float(*cfg.Temperature)
+		}
+		if cfg.MaxTokens != nil {
+			params.MaxTokens = openai.Int(int64(*cfg.MaxTokens))
+		}
+		if cfg.TopP != nil {
+			params.TopP = openai.Float(*cfg.TopP)
+		}
+		if cfg.FrequencyPenalty != nil {
+			params.FrequencyPenalty = openai.Float(*cfg.FrequencyPenalty)
+		}
+		if cfg.PresencePenalty != nil {
+			params.PresencePenalty = openai.Float(*cfg.PresencePenalty)
+		}
 	}
 	if cfg.Seed != nil {
 		params.Seed = openai.Int(int64(*cfg.Seed))
@@ -194,6 +257,23 @@ func applyOpenAIConfig(params *openai.ChatCompletionNewParams, cfg *OpenAIConfig
 	}
 }
 
+// isOpenAIReasoningModel reports whether the model belongs to the o-series or
+// gpt-5 reasoning families. Provider prefixes such as "openai/" or
+// "azure/" are ignored.
+func isOpenAIReasoningModel(name string) bool {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	if strings.HasPrefix(name, "gpt-5") {
+		return !strings.HasPrefix(name, "gpt-5-chat")
+	}
+	if len(name) >= 2 && name[0] == 'o' && name[1] >= '1' && name[1] <= '9' {
+		return true
+	}
+	return false
+}
+
 func genaiContentsToOpenAIMessages(contents []*genai.Content, config *genai.GenerateContentConfig) ([]openai.ChatCompletionMessageParamUnion, string) {
 	var systemBuilder strings.Builder
 	if config != nil && config.SystemInstruction != nil {
@@ -347,6 +427,40 @@ func genaiToolsToOpenAITools(tools []*genai.Tool) []openai.ChatCompletionToolUni
 	return out
 }
 
+// openAIStreamToolCall accumulates a single streamed tool call. OpenAI sends
+// the id and name once and splits the JSON arguments across many chunks, so
+// arguments are only parsed after the stream completes.
+type openAIStreamToolCall struct {
+	id               string
+	name             string
+	arguments        strings.Builder
+	thoughtSignature []byte
+}
+
+func (a *openAIStreamToolCall) add(id, name, argsFragment string, thoughtSignature []byte) {
+	if id != "" {
+		a.id = id
+	}
+	if name != "" {
+		a.name = name
+	}
+	a.arguments.WriteString(argsFragment)
+	if len(thoughtSignature) > 0 {
+		a.thoughtSignature = thoughtSignature
+	}
+}
+
+func (a *openAIStreamToolCall) part() *genai.Part {
+	if a.name == "" && a.id == "" {
+		return nil
+	}
+	var args map[string]any
+	if a.arguments.Len() > 0 {
+		_ = json.Unmarshal([]byte(a.arguments.String()), &args)
+	}
+	return newFunctionCallPart(a.name, args, a.id, a.thoughtSignature)
+}
+
 func runStreaming(ctx context.Context, m *OpenAIModel, params openai.ChatCompletionNewParams, yield func(*model.LLMResponse, error) bool) {
 	params.StreamOptions = openai.ChatCompletionStreamOptionsParam{
 		IncludeUsage: param.NewOpt(true),
@@ -355,7 +469,7 @@ func runStreaming(ctx context.Context, m *OpenAIModel, params openai.ChatComplet
 	defer stream.Close()
 
 	var aggregatedText strings.Builder
-	toolCallsAcc := make(map[int64]map[string]any)
+	toolCallsAcc := make(map[int64]*openAIStreamToolCall)
 	var finishReason string
 	var promptTokens, completionTokens int64
 
@@ -381,23 +495,12 @@ func runStreaming(ctx context.Context, m *OpenAIModel, params openai.ChatComplet
 			}
 		}
 		for _, tc := range delta.ToolCalls {
-			idx := tc.Index
-			if toolCallsAcc[idx] == nil {
-				toolCallsAcc[idx] = map[string]any{"id": "", "name": "", "arguments": "", "thought_signature": []byte(nil)}
-			}
-			if tc.ID != "" {
-				toolCallsAcc[idx]["id"] = tc.ID
-			}
-			if tc.Function.Name != "" {
-				toolCallsAcc[idx]["name"] = tc.Function.Name
-			}
-			if tc.Function.Arguments != "" {
-				prev, _ := toolCallsAcc[idx]["arguments"].(string)
-				toolCallsAcc[idx]["arguments"] = prev + tc.Function.Arguments
-			}
-			if thoughtSignature := extractThoughtSignatureFromExtraFields(tc.JSON.ExtraFields); len(thoughtSignature) > 0 {
-				toolCallsAcc[idx]["thought_signature"] = thoughtSignature
+			acc := toolCallsAcc[tc.Index]
+			if acc == nil {
+				acc = &openAIStreamToolCall{}
+				toolCallsAcc[tc.Index] = acc
 			}
+			acc.add(tc.ID, tc.Function.Name, tc.Function.Arguments, extractThoughtSignatureFromExtraFields(tc.JSON.ExtraFields))
 		}
 		if choice.FinishReason != "" {
 			finishReason = choice.FinishReason
@@ -425,17 +528,7 @@ func runStreaming(ctx context.Context, m *OpenAIModel, params openai.ChatComplet
 		finalParts = append(finalParts, &genai.Part{Text: text})
 	}
 	for _, idx := range indices {
-		tc := toolCallsAcc[idx]
-		argsStr, _ := tc["arguments"].(string)
-		var args map[string]any
-		if argsStr != "" {
-			_ = json.Unmarshal([]byte(argsStr), &args)
-		}
-		name, _ := tc["name"].(string)
-		id, _ := tc["id"].(string)
-		if name != "" || id != "" {
-			thoughtSignature, _ := tc["thought_signature"].([]byte)
-			p := newFunctionCallPart(name, args, id, thoughtSignature)
+		if p := toolCallsAcc[idx].part(); p != nil {
 			finalParts = append(finalParts, p)
 		}
 	}
@@ -474,7 +567,12 @@ func runNonStreaming(ctx context.Context, m *OpenAIModel, params openai.ChatComp
 }
 
 func chatCompletionToLLMResponse(completion *openai.ChatCompletion) *model.LLMResponse {
-	choice := completion.Choices[0]
+	return chatChoiceToLLMResponse(completion, 0)
+}
+
+// chatChoiceToLLMResponse converts the i-th choice of completion.
+func chatChoiceToLLMResponse(completion *openai.ChatCompletion, i int) *model.LLMResponse {
+	choice := completion.Choices[i]
 	msg := choice.Message
 	nParts := 0
 	if msg.Content != "" {
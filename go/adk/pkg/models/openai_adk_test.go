@@ -1,11 +1,17 @@
 package models
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/go-logr/logr"
 	"github.com/openai/openai-go/v3"
+	"google.golang.org/adk/model"
 	"google.golang.org/genai"
 )
 
@@ -259,6 +265,77 @@ func TestApplyOpenAIConfig(t *testing.T) {
 	})
 }
 
+func TestApplyOpenAIConfig_ReasoningModel(t *testing.T) {
+	temp := 0.7
+	topP := 0.9
+	n := 500
+	effort := "high"
+	cfg := &OpenAIConfig{Temperature: &temp, TopP: &topP, MaxTokens: &n, ReasoningEffort: &effort}
+
+	params := openai.ChatCompletionNewParams{Model: "o3-mini"}
+	applyOpenAIConfig(&params, cfg)
+	if params.Temperature.Valid() || params.TopP.Valid() {
+		t.Errorf("sampling params must be omitted for reasoning models: temperature=%v top_p=%v", params.Temperature.Valid(), params.TopP.Valid())
+	}
+	if params.MaxTokens.Valid() {
+		t.Error("max_tokens must not be sent for reasoning models")
+	}
+	if !params.MaxCompletionTokens.Valid() || params.MaxCompletionTokens.Value != 500 {
+		t.Errorf("MaxCompletionTokens: Valid=%v, Value=%v, want (true, 500)", params.MaxCompletionTokens.Valid(), params.MaxCompletionTokens.Value)
+	}
+	if params.ReasoningEffort != "high" {
+		t.Errorf("ReasoningEffort: got %q, want %q", params.ReasoningEffort, "high")
+	}
+}
+
+func TestIsOpenAIReasoningModel(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"o1", true},
+		{"o1-mini", true},
+		{"o3-mini-2025-01-31", true},
+		{"o4-mini", true},
+		{"openai/o3", true},
+		{"gpt-5", true},
+		{"gpt-5-mini", true},
+		{"gpt-5-chat-latest", false},
+		{"gpt-4o", false},
+		{"gpt-4.1-mini", false},
+		{"omni-moderation-latest", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := isOpenAIReasoningModel(tt.name); got != tt.want {
+			t.Errorf("isOpenAIReasoningModel(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestOpenAIStreamToolCall_AccumulatesFragments(t *testing.T) {
+	acc := &openAIStreamToolCall{}
+	acc.add("call_1", "get_weather", "", nil)
+	acc.add("", "", `{"city":`, nil)
+	acc.add("", "", ` "Paris"`, nil)
+	acc.add("", "", `}`, nil)
+
+	p := acc.part()
+	if p == nil || p.FunctionCall == nil {
+		t.Fatal("expected function call part")
+	}
+	if p.FunctionCall.ID != "call_1" || p.FunctionCall.Name != "get_weather" {
+		t.Errorf("got id=%q name=%q", p.FunctionCall.ID, p.FunctionCall.Name)
+	}
+	if p.FunctionCall.Args["city"] != "Paris" {
+		t.Errorf("Args = %v, want city=Paris", p.FunctionCall.Args)
+	}
+
+	if (&openAIStreamToolCall{}).part() != nil {
+		t.Error("empty accumulator should yield no part")
+	}
+}
+
 func TestGenaiContentsToOpenAIMessages_PreservesThoughtSignatureOnToolCallAndToolResult(t *testing.T) {
 	thoughtSignature := []byte("abc")
 
@@ -418,3 +495,58 @@ func TestExtractThoughtSignatureFromStreamingToolCallChunk(t *testing.T) {
 		t.Fatalf("thoughtSignature = %q, want %q", string(thoughtSignature), "abc")
 	}
 }
+
+func TestOpenAIModel_StreamingAssemblesFragmentedToolCalls(t *testing.T) {
+	// Two tool calls whose arguments are split across interleaved chunks.
+	chunks := []string{
+		`{"id":"c","object":"chat.completion.chunk","created":0,"model":"gpt-4o","choices":[{"index":0,"delta":{"role":"assistant","tool_calls":[{"index":0,"id":"call_a","type":"function","function":{"name":"get_weather","arguments":""}}]}}]}`,
+		`{"id":"c","object":"chat.completion.chunk","created":0,"model":"gpt-4o","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"{\"city\":"}}]}}]}`,
+		`{"id":"c","object":"chat.completion.chunk","created":0,"model":"gpt-4o","choices":[{"index":0,"delta":{"tool_calls":[{"index":1,"id":"call_b","type":"function","function":{"name":"get_time","arguments":"{\"tz\""}}]}}]}`,
+		`{"id":"c","object":"chat.completion.chunk","created":0,"model":"gpt-4o","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":" \"Paris\"}"}}]}}]}`,
+		`{"id":"c","object":"chat.completion.chunk","created":0,"model":"gpt-4o","choices":[{"index":0,"delta":{"tool_calls":[{"index":1,"function":{"arguments":": \"UTC\"}"}}]}}]}`,
+		`{"id":"c","object":"chat.completion.chunk","created":0,"model":"gpt-4o","choices":[{"index":0,"delta":{},"finish_reason":"tool_calls"}]}`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, c := range chunks {
+			fmt.Fprintf(w, "data: %s\n\n", c)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer srv.Close()
+
+	m, err := NewOpenAICompatibleModelWithLogger(srv.URL, "gpt-4o", nil, "test", logr.Discard())
+	if err != nil {
+		t.Fatalf("NewOpenAICompatibleModelWithLogger: %v", err)
+	}
+
+	var final *model.LLMResponse
+	for resp, err := range m.GenerateContent(context.Background(), &model.LLMRequest{}, true) {
+		if err != nil {
+			t.Fatalf("GenerateContent: %v", err)
+		}
+		if resp.ErrorMessage != "" {
+			t.Fatalf("stream error: %s", resp.ErrorMessage)
+		}
+		if !resp.Partial {
+			final = resp
+		}
+	}
+	if final == nil || final.Content == nil {
+		t.Fatal("expected a final response")
+	}
+	if final.FinishReason != genai.FinishReasonStop {
+		t.Errorf("FinishReason = %v, want %v", final.FinishReason, genai.FinishReasonStop)
+	}
+
+	parts := final.Content.Parts
+	if len(parts) != 2 {
+		t.Fatalf("want 2 function call parts, got %d", len(parts))
+	}
+	if fc := parts[0].FunctionCall; fc == nil || fc.ID != "call_a" || fc.Name != "get_weather" || fc.Args["city"] != "Paris" {
+		t.Errorf("parts[0] = %+v, want get_weather(city=Paris)", parts[0].FunctionCall)
+	}
+	if fc := parts[1].FunctionCall; fc == nil || fc.ID != "call_b" || fc.Name != "get_time" || fc.Args["tz"] != "UTC" {
+		t.Errorf("parts[1] = %+v, want get_time(tz=UTC)", parts[1].FunctionCall)
+	}
+}
@@ -0,0 +1,157 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"slices"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/kagent-dev/kagent/go/adk/pkg/telemetry"
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// DefaultCritiquePrompt is the review instruction given to the critique
+// model.
+const DefaultCritiquePrompt = "You are reviewing a draft reply written by an assistant. " +
+	"Check it against the conversation for factual errors, missed parts of the request, " +
+	"unsupported claims and unclear wording. If the draft needs no changes, reply with " +
+	"exactly APPROVED. Otherwise list the specific problems to fix, without rewriting the reply."
+
+const critiqueApproved = "APPROVED"
+
+// ReflectingModel has each final answer of its model reviewed by a critique
+// model, and revised with the critique until the critique model approves it
+// or maxPasses revisions were made. Responses calling tools are returned
+// unreviewed. Responses are never streamed, since a draft may be replaced.
+// The returned response reports the usage of every call, and all drafts and
+// critiques are recorded on the trace.
+type ReflectingModel struct {
+	model.LLM
+	critic    model.LLM
+	maxPasses int
+	prompt    string
+}
+
+// NewReflectingModel wraps llm with a critique step run by critic, which may
+// be llm itself. An empty prompt means DefaultCritiquePrompt.
+func NewReflectingModel(llm, critic model.LLM, maxPasses int, prompt string) *ReflectingModel {
+	if prompt == "" {
+		prompt = DefaultCritiquePrompt
+	}
+	return &ReflectingModel{LLM: llm, critic: critic, maxPasses: maxPasses, prompt: prompt}
+}
+
+// GenerateContent implements model.LLM.
+func (m *ReflectingModel) GenerateContent(ctx context.Context, req *model.LLMRequest, _ bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		draft, err := finalResponse(m.LLM.GenerateContent(ctx, req, false))
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		if !isFinalAnswer(draft) {
+			yield(draft, nil)
+			return
+		}
+
+		log := logr.FromContextOrDiscard(ctx)
+		drafts := []*model.LLMResponse{draft}
+		calls := []*model.LLMResponse{draft}
+		var critiques []string
+		for range m.maxPasses {
+			critique, err := m.critique(ctx, req, draft)
+			if err != nil {
+				log.Info("Critique failed, keeping the draft", "error", err.Error())
+				break
+			}
+			calls = append(calls, critique)
+			text := strings.TrimSpace(renderContent(critique.Content))
+			critiques = append(critiques, text)
+			if strings.HasPrefix(strings.ToUpper(text), critiqueApproved) {
+				break
+			}
+
+			revised, err := finalResponse(m.LLM.GenerateContent(ctx, revisionRequest(req, draft, text), false))
+			if err != nil {
+				log.Info("Revision failed, keeping the draft", "error", err.Error())
+				break
+			}
+			calls = append(calls, revised)
+			if !isFinalAnswer(revised) {
+				// The revision reached for a tool; keep the answer that was
+				// reviewed rather than starting a tool call mid-review.
+				break
+			}
+			drafts = append(drafts, revised)
+			draft = revised
+		}
+
+		telemetry.SetReflectionAttributes(ctx, drafts, critiques)
+		final := *draft
+		final.UsageMetadata = sumUsage(calls)
+		yield(&final, nil)
+	}
+}
+
+// critique asks the critique model to review draft as a reply to req.
+func (m *ReflectingModel) critique(ctx context.Context, req *model.LLMRequest, draft *model.LLMResponse) (*model.LLMResponse, error) {
+	var prompt strings.Builder
+	prompt.WriteString("Conversation:\n")
+	for _, c := range req.Contents {
+		if c != nil {
+			fmt.Fprintf(&prompt, "[%s] %s\n", c.Role, renderContent(c))
+		}
+	}
+	fmt.Fprintf(&prompt, "\nDraft reply:\n%s\n", renderContent(draft.Content))
+
+	critiqueReq := &model.LLMRequest{
+		Model:    m.critic.Name(),
+		Contents: []*genai.Content{genai.NewContentFromText(prompt.String(), genai.RoleUser)},
+		Config: &genai.GenerateContentConfig{
+			SystemInstruction: genai.NewContentFromText(m.prompt, genai.RoleUser),
+			Temperature:       genai.Ptr[float32](0),
+		},
+	}
+	resp, err := finalResponse(m.critic.GenerateContent(ctx, critiqueReq, false))
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(renderContent(resp.Content)) == "" {
+		return nil, fmt.Errorf("critique model returned no text")
+	}
+	return resp, nil
+}
+
+// revisionRequest extends req with the draft and the critique of it.
+func revisionRequest(req *model.LLMRequest, draft *model.LLMResponse, critique string) *model.LLMRequest {
+	revision := *req
+	revision.Contents = append(slices.Clone(req.Contents),
+		draft.Content,
+		genai.NewContentFromText("A reviewer raised these problems with your reply:\n\n"+critique+
+			"\n\nWrite an improved reply that fixes them. Reply with the full answer only.", genai.RoleUser),
+	)
+	return &revision
+}
+
+// isFinalAnswer reports whether resp is a text answer that calls no tools.
+func isFinalAnswer(resp *model.LLMResponse) bool {
+	if resp == nil || resp.ErrorCode != "" || resp.Content == nil {
+		return false
+	}
+	hasText := false
+	for _, p := range resp.Content.Parts {
+		if p == nil {
+			continue
+		}
+		if p.FunctionCall != nil {
+			return false
+		}
+		if !p.Thought && strings.TrimSpace(p.Text) != "" {
+			hasText = true
+		}
+	}
+	return hasText
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"errors"
+	"testing"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+func TestReflectingModel_RevisesUntilApproved(t *testing.T) {
+	inner := &cannedModel{responses: []*model.LLMResponse{
+		textResponse("draft one", genai.FinishReasonStop, 10),
+		textResponse("draft two", genai.FinishReasonStop, 10),
+	}}
+	critic := &cannedModel{responses: []*model.LLMResponse{
+		textResponse("The answer misses the second question.", genai.FinishReasonStop, 1),
+		textResponse("APPROVED", genai.FinishReasonStop, 1),
+	}}
+
+	resp := generateOne(t, NewReflectingModel(inner, critic, 3, ""))
+	if got := renderContent(resp.Content); got != "draft two" {
+		t.Errorf("returned %q, want the revised draft", got)
+	}
+	if inner.calls.Load() != 2 || critic.calls.Load() != 2 {
+		t.Errorf("model calls = %d, critique calls = %d; want 2 and 2", inner.calls.Load(), critic.calls.Load())
+	}
+	if resp.UsageMetadata == nil || resp.UsageMetadata.PromptTokenCount != 22 {
+		t.Errorf("usage = %+v, want the sum over drafts and critiques", resp.UsageMetadata)
+	}
+}
+
+func TestReflectingModel_StopsAfterMaxPasses(t *testing.T) {
+	inner := &cannedModel{responses: []*model.LLMResponse{
+		textResponse("draft one", genai.FinishReasonStop, 0),
+		textResponse("draft two", genai.FinishReasonStop, 0),
+	}}
+	critic := &cannedModel{responses: []*model.LLMResponse{textResponse("Still wrong.", genai.FinishReasonStop, 0)}}
+
+	resp := generateOne(t, NewReflectingModel(inner, critic, 1, ""))
+	if got := renderContent(resp.Content); got != "draft two" {
+		t.Errorf("returned %q, want the single revision", got)
+	}
+	if inner.calls.Load() != 2 || critic.calls.Load() != 1 {
+		t.Errorf("model calls = %d, critique calls = %d; want 2 and 1", inner.calls.Load(), critic.calls.Load())
+	}
+}
+
+func TestReflectingModel_SkipsToolCalls(t *testing.T) {
+	toolCall := &model.LLMResponse{Content: &genai.Content{
+		Role:  string(genai.RoleModel),
+		Parts: []*genai.Part{genai.NewPartFromFunctionCall("lookup", map[string]any{"q": "x"})},
+	}}
+	inner := &cannedModel{responses: []*model.LLMResponse{toolCall}}
+	critic := &cannedModel{responses: []*model.LLMResponse{textResponse("APPROVED", genai.FinishReasonStop, 0)}}
+
+	resp := generateOne(t, NewReflectingModel(inner, critic, 1, ""))
+	if resp != toolCall {
+		t.Errorf("returned %+v, want the tool call unchanged", resp)
+	}
+	if critic.calls.Load() != 0 {
+		t.Errorf("critique calls = %d, want none for a tool call", critic.calls.Load())
+	}
+}
+
+func TestReflectingModel_KeepsDraftWhenCritiqueFails(t *testing.T) {
+	inner := &cannedModel{responses: []*model.LLMResponse{textResponse("draft", genai.FinishReasonStop, 0)}}
+	critic := &cannedModel{responses: []*model.LLMResponse{nil}, errs: []error{errors.New("critic down")}}
+
+	if got := renderContent(generateOne(t, NewReflectingModel(inner, critic, 1, "")).Content); got != "draft" {
+		t.Errorf("returned %q, want the draft", got)
+	}
+}
@@ -136,6 +136,9 @@ func (m *SAPAICoreModel) buildOrchestrationBody(req *model.LLMRequest, stream bo
 		if req.Config.TopP != nil {
 			params["top_p"] = *req.Config.TopP
 		}
+		if len(req.Config.StopSequences) > 0 {
+			params["stop"] = req.Config.StopSequences
+		}
 	}
 
 	promptConfig := map[string]any{
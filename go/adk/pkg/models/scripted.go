@@ -0,0 +1,131 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"os"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+	"sigs.k8s.io/yaml"
+)
+
+// ScriptToolCall is a tool call the scripted model asks the agent to make.
+type ScriptToolCall struct {
+	ID   string         `json:"id,omitempty"`
+	Name string         `json:"name"`
+	Args map[string]any `json:"args,omitempty"`
+}
+
+// ScriptTurn is one canned model response.
+type ScriptTurn struct {
+	Text      string           `json:"text,omitempty"`
+	ToolCalls []ScriptToolCall `json:"tool_calls,omitempty"`
+}
+
+// Script is the list of turns replayed by ScriptedModel.
+type Script struct {
+	Turns []ScriptTurn `json:"turns"`
+}
+
+// LoadScript reads a YAML or JSON script file.
+func LoadScript(path string) (*Script, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read model script %s: %w", path, err)
+	}
+	var s Script
+	if err := yaml.UnmarshalStrict(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse model script %s: %w", path, err)
+	}
+	if len(s.Turns) == 0 {
+		return nil, fmt.Errorf("model script %s has no turns", path)
+	}
+	for i, t := range s.Turns {
+		for j, tc := range t.ToolCalls {
+			if tc.Name == "" {
+				return nil, fmt.Errorf("model script %s turn %d tool call %d: name is required", path, i, j)
+			}
+		}
+	}
+	return &s, nil
+}
+
+// ScriptedModel implements model.LLM by replaying a Script. The turn to
+// replay is chosen by counting the model turns already present in the
+// request, so every session walks the script from the start and output is
+// deterministic regardless of concurrency.
+type ScriptedModel struct {
+	Script *Script
+	Logger logr.Logger
+}
+
+// NewScriptedModelWithLogger loads the script at path and returns a model
+// replaying it.
+func NewScriptedModelWithLogger(path string, logger logr.Logger) (*ScriptedModel, error) {
+	s, err := LoadScript(path)
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("Initialized scripted model", "script", path, "turns", len(s.Turns))
+	return &ScriptedModel{Script: s, Logger: logger}, nil
+}
+
+// Name implements model.LLM.
+func (m *ScriptedModel) Name() string {
+	return "scripted"
+}
+
+// GenerateContent implements model.LLM.
+func (m *ScriptedModel) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		idx := 0
+		for _, c := range req.Contents {
+			if c != nil && c.Role == string(genai.RoleModel) {
+				idx++
+			}
+		}
+		if idx >= len(m.Script.Turns) {
+			yield(&model.LLMResponse{
+				ErrorCode:    "SCRIPT_EXHAUSTED",
+				ErrorMessage: fmt.Sprintf("model script has %d turns, request needs turn %d", len(m.Script.Turns), idx+1),
+			}, nil)
+			return
+		}
+		turn := m.Script.Turns[idx]
+
+		if stream && turn.Text != "" {
+			for _, chunk := range strings.SplitAfter(turn.Text, " ") {
+				if ctx.Err() != nil {
+					return
+				}
+				if !yield(&model.LLMResponse{
+					Partial: true,
+					Content: &genai.Content{Role: string(genai.RoleModel), Parts: []*genai.Part{{Text: chunk}}},
+				}, nil) {
+					return
+				}
+			}
+		}
+
+		var parts []*genai.Part
+		if turn.Text != "" {
+			parts = append(parts, &genai.Part{Text: turn.Text})
+		}
+		for i, tc := range turn.ToolCalls {
+			id := tc.ID
+			if id == "" {
+				id = fmt.Sprintf("call_%d_%d", idx, i)
+			}
+			parts = append(parts, newFunctionCallPart(tc.Name, tc.Args, id, nil))
+		}
+		yield(&model.LLMResponse{
+			TurnComplete: true,
+			FinishReason: genai.FinishReasonStop,
+			Content:      &genai.Content{Role: string(genai.RoleModel), Parts: parts},
+		}, nil)
+	}
+}
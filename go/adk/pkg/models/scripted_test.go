@@ -0,0 +1,99 @@
+package models
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+const testScript = `turns:
+  - tool_calls:
+      - name: get_pods
+        args:
+          namespace: default
+  - text: There are 3 pods in default.
+`
+
+func newTestScriptedModel(t *testing.T) *ScriptedModel {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "script.yaml")
+	if err := os.WriteFile(path, []byte(testScript), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	m, err := NewScriptedModelWithLogger(path, logr.Discard())
+	if err != nil {
+		t.Fatalf("NewScriptedModelWithLogger: %v", err)
+	}
+	return m
+}
+
+func collectResponses(t *testing.T, m model.LLM, req *model.LLMRequest, stream bool) []*model.LLMResponse {
+	t.Helper()
+	var out []*model.LLMResponse
+	for resp, err := range m.GenerateContent(context.Background(), req, stream) {
+		if err != nil {
+			t.Fatalf("GenerateContent: %v", err)
+		}
+		out = append(out, resp)
+	}
+	return out
+}
+
+func TestScriptedModel_ReplaysTurnsByConversationPosition(t *testing.T) {
+	m := newTestScriptedModel(t)
+	user := genai.NewContentFromText("list pods", genai.RoleUser)
+
+	// First model turn: a tool call with a deterministic ID.
+	resps := collectResponses(t, m, &model.LLMRequest{Contents: []*genai.Content{user}}, false)
+	if len(resps) != 1 {
+		t.Fatalf("want 1 response, got %d", len(resps))
+	}
+	fc := resps[0].Content.Parts[0].FunctionCall
+	if fc == nil || fc.Name != "get_pods" || fc.ID != "call_0_0" || fc.Args["namespace"] != "default" {
+		t.Fatalf("unexpected function call: %+v", fc)
+	}
+
+	// Second model turn, streamed: partial chunks then the full text.
+	history := []*genai.Content{
+		user,
+		{Role: string(genai.RoleModel), Parts: []*genai.Part{{FunctionCall: fc}}},
+		{Role: string(genai.RoleUser), Parts: []*genai.Part{{FunctionResponse: &genai.FunctionResponse{ID: fc.ID, Name: fc.Name}}}},
+	}
+	resps = collectResponses(t, m, &model.LLMRequest{Contents: history}, true)
+	final := resps[len(resps)-1]
+	if final.Partial || final.Content.Parts[0].Text != "There are 3 pods in default." {
+		t.Fatalf("unexpected final response: %+v", final)
+	}
+	if len(resps) < 2 || !resps[0].Partial {
+		t.Errorf("expected partial chunks before the final response, got %d responses", len(resps))
+	}
+
+	// A third model turn is not scripted.
+	history = append(history, final.Content, genai.NewContentFromText("thanks", genai.RoleUser))
+	resps = collectResponses(t, m, &model.LLMRequest{Contents: history}, false)
+	if resps[0].ErrorCode != "SCRIPT_EXHAUSTED" {
+		t.Errorf("ErrorCode = %q, want SCRIPT_EXHAUSTED", resps[0].ErrorCode)
+	}
+}
+
+func TestLoadScript_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	for name, body := range map[string]string{
+		"empty.yaml":   "turns: []\n",
+		"noname.yaml":  "turns:\n  - tool_calls:\n      - args: {}\n",
+		"unknown.yaml": "turns:\n  - txt: typo\n",
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadScript(path); err == nil {
+			t.Errorf("LoadScript(%s) should fail", name)
+		}
+	}
+}
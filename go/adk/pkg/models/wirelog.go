@@ -0,0 +1,307 @@
+package models
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kagent-dev/kagent/go/adk/pkg/skills"
+)
+
+// Environment variables configuring LLM wire logging.
+const (
+	// EnvWireLog enables logging of full provider requests and responses
+	// when set to "true". Secrets are redacted before anything is written.
+	EnvWireLog = "KAGENT_LLM_WIRE_LOG"
+	// EnvWireLogSampleRate is the fraction of requests logged, between 0 and
+	// 1. Defaults to 1.
+	EnvWireLogSampleRate = "KAGENT_LLM_WIRE_LOG_SAMPLE_RATE"
+	// EnvWireLogDir is the directory the logs are written under. Defaults to
+	// the session workspace directory, so each session's logs live in its
+	// workspace and are removed with it.
+	EnvWireLogDir = "KAGENT_LLM_WIRE_LOG_DIR"
+)
+
+// wireLogSubdir is the directory, inside a session's directory, holding its
+// wire logs. It is hidden so workspace change reports skip it.
+const wireLogSubdir = ".llm-wire"
+
+// maxWireLogBodyBytes caps each logged request or response body.
+const maxWireLogBodyBytes = 1 << 20
+
+const redactedValue = "[REDACTED]"
+
+// WireLogConfig configures LLM wire logging.
+type WireLogConfig struct {
+	Enabled    bool
+	SampleRate float64
+	Dir        string
+}
+
+// WireLogConfigFromEnv reads the wire logging configuration from the
+// environment.
+func WireLogConfigFromEnv() (WireLogConfig, error) {
+	cfg := WireLogConfig{
+		Enabled:    strings.EqualFold(strings.TrimSpace(os.Getenv(EnvWireLog)), "true"),
+		SampleRate: 1,
+		Dir:        skills.WorkspaceBaseDir(),
+	}
+	if v := strings.TrimSpace(os.Getenv(EnvWireLogSampleRate)); v != "" {
+		rate, err := strconv.ParseFloat(v, 64)
+		if err != nil || rate < 0 || rate > 1 {
+			return WireLogConfig{}, fmt.Errorf("invalid %s %q: must be between 0 and 1", EnvWireLogSampleRate, v)
+		}
+		cfg.SampleRate = rate
+	}
+	if v := strings.TrimSpace(os.Getenv(EnvWireLogDir)); v != "" {
+		cfg.Dir = filepath.Clean(v)
+	}
+	return cfg, nil
+}
+
+type wireLogScopeKey struct{}
+
+type wireLogScope struct {
+	sessionID string
+	taskID    string
+}
+
+// WithWireLogScope records the session and task a model call is made for,
+// so its wire log is stored with them.
+func WithWireLogScope(ctx context.Context, sessionID, taskID string) context.Context {
+	return context.WithValue(ctx, wireLogScopeKey{}, wireLogScope{sessionID: sessionID, taskID: taskID})
+}
+
+// wireLogTransport writes a sample of the requests that pass through it,
+// with their responses, to one JSON file each.
+type wireLogTransport struct {
+	base   http.RoundTripper
+	config WireLogConfig
+	seq    atomic.Uint64
+}
+
+func (t *wireLogTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.config.SampleRate < 1 && rand.Float64() >= t.config.SampleRate {
+		return t.base.RoundTrip(req)
+	}
+
+	entry := &wireLogEntry{
+		Time:           time.Now().UTC(),
+		Method:         req.Method,
+		URL:            redactURL(req.URL.String()),
+		RequestHeaders: redactHeaders(req.Header),
+	}
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(body)), nil }
+		entry.RequestBody = redactBody(body)
+	}
+	path := t.logPath(req.Context(), entry.Time)
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		entry.Error = err.Error()
+		entry.DurationMS = time.Since(start).Milliseconds()
+		writeWireLog(path, entry)
+		return nil, err
+	}
+	entry.Status = resp.StatusCode
+	entry.ResponseHeaders = redactHeaders(resp.Header)
+	// Streamed responses are logged once the caller has read them.
+	resp.Body = &wireLogBody{ReadCloser: resp.Body, entry: entry, path: path, start: start}
+	return resp, nil
+}
+
+// logPath returns <dir>/<session>/.llm-wire/<task>/<time>-<seq>.json, or
+// <dir>/.llm-wire/<time>-<seq>.json for calls made outside a task.
+func (t *wireLogTransport) logPath(ctx context.Context, now time.Time) string {
+	name := fmt.Sprintf("%s-%04d.json", now.Format("20060102T150405.000Z"), t.seq.Add(1))
+	scope, _ := ctx.Value(wireLogScopeKey{}).(wireLogScope)
+	if scope.sessionID == "" {
+		return filepath.Join(t.config.Dir, wireLogSubdir, name)
+	}
+	taskID := scope.taskID
+	if taskID == "" {
+		taskID = "no-task"
+	}
+	return filepath.Join(t.config.Dir, safePathElem(scope.sessionID), wireLogSubdir, safePathElem(taskID), name)
+}
+
+// safePathElem keeps IDs from escaping the log directory.
+func safePathElem(s string) string {
+	s = strings.NewReplacer("/", "_", `\`, "_").Replace(s)
+	if s == "." || s == ".." {
+		return "_"
+	}
+	return s
+}
+
+type wireLogEntry struct {
+	Time            time.Time         `json:"time"`
+	Method          string            `json:"method"`
+	URL             string            `json:"url"`
+	RequestHeaders  map[string]string `json:"request_headers,omitempty"`
+	RequestBody     json.RawMessage   `json:"request_body,omitempty"`
+	Status          int               `json:"status,omitempty"`
+	ResponseHeaders map[string]string `json:"response_headers,omitempty"`
+	ResponseBody    json.RawMessage   `json:"response_body,omitempty"`
+	DurationMS      int64             `json:"duration_ms"`
+	Error           string            `json:"error,omitempty"`
+}
+
+// wireLogBody records a response body as it is read and writes the log
+// entry at EOF or Close, whichever comes first.
+type wireLogBody struct {
+	io.ReadCloser
+	entry *wireLogEntry
+	path  string
+	start time.Time
+
+	buf  bytes.Buffer
+	once sync.Once
+}
+
+func (b *wireLogBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if room := maxWireLogBodyBytes - b.buf.Len(); room > 0 {
+		b.buf.Write(p[:min(n, room)])
+	}
+	if err == io.EOF {
+		b.flush()
+	}
+	return n, err
+}
+
+func (b *wireLogBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.flush()
+	return err
+}
+
+func (b *wireLogBody) flush() {
+	b.once.Do(func() {
+		b.entry.DurationMS = time.Since(b.start).Milliseconds()
+		b.entry.ResponseBody = redactBody(b.buf.Bytes())
+		writeWireLog(b.path, b.entry)
+	})
+}
+
+// writeWireLog writes entry to path. Logging is best effort and never fails
+// the model call.
+func writeWireLog(path string, entry *wireLogEntry) {
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return
+	}
+	_ = os.WriteFile(path, data, 0o600)
+}
+
+var (
+	// secretNameRe matches header, query parameter and JSON field names
+	// whose values are redacted.
+	secretNameRe = regexp.MustCompile(`(?i)(authorization|^auth$|^key$|api[-_]?key|access[-_]?key|token|secret|password|credential|cookie|amz-signature)`)
+	// secretValueRe matches well-known API key formats anywhere in a body.
+	secretValueRe = regexp.MustCompile(`\b(sk-[A-Za-z0-9_-]{16,}|AIza[0-9A-Za-z_-]{30,}|AKIA[0-9A-Z]{16}|gh[pousr]_[A-Za-z0-9]{30,})\b`)
+	// bearerRe matches bearer credentials in free text.
+	bearerRe = regexp.MustCompile(`(?i)bearer\s+[A-Za-z0-9._~+/-]+=*`)
+)
+
+func redactHeaders(h http.Header) map[string]string {
+	if len(h) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(h))
+	for name, values := range h {
+		v := strings.Join(values, ", ")
+		if secretNameRe.MatchString(name) {
+			v = redactedValue
+		}
+		out[name] = v
+	}
+	return out
+}
+
+func redactURL(raw string) string {
+	base, query, ok := strings.Cut(raw, "?")
+	if !ok {
+		return raw
+	}
+	params := strings.Split(query, "&")
+	for i, p := range params {
+		if name, _, ok := strings.Cut(p, "="); ok && secretNameRe.MatchString(name) {
+			params[i] = name + "=" + redactedValue
+		}
+	}
+	return base + "?" + strings.Join(params, "&")
+}
+
+// redactBody returns body as JSON with secrets redacted. JSON bodies are
+// redacted field by field; anything else, such as a stream of server-sent
+// events or a body cut at maxWireLogBodyBytes, is logged as a string with
+// known key formats masked.
+func redactBody(body []byte) json.RawMessage {
+	if len(body) == 0 {
+		return nil
+	}
+	if len(body) > maxWireLogBodyBytes {
+		body = body[:maxWireLogBodyBytes]
+	}
+	var v any
+	if err := json.Unmarshal(body, &v); err == nil {
+		if out, err := json.Marshal(redactValue(v)); err == nil {
+			return out
+		}
+	}
+	out, _ := json.Marshal(redactText(string(body)))
+	return out
+}
+
+func redactValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, val := range v {
+			if _, isString := val.(string); isString && secretNameRe.MatchString(k) {
+				v[k] = redactedValue
+				continue
+			}
+			v[k] = redactValue(val)
+		}
+		return v
+	case []any:
+		for i, val := range v {
+			v[i] = redactValue(val)
+		}
+		return v
+	case string:
+		return redactText(v)
+	default:
+		return v
+	}
+}
+
+func redactText(s string) string {
+	s = bearerRe.ReplaceAllString(s, "Bearer "+redactedValue)
+	return secretValueRe.ReplaceAllString(s, redactedValue)
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWireLogTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), "sk-live-0123456789abcdefghij") {
+			t.Errorf("the provider received a redacted body: %s", body)
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		io.WriteString(w, "data: {\"text\":\"hello\"}\n\n")
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	client := &http.Client{Transport: &wireLogTransport{base: http.DefaultTransport, config: WireLogConfig{Enabled: true, SampleRate: 1, Dir: dir}}}
+
+	ctx := WithWireLogScope(context.Background(), "sess-1", "task-1")
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, srv.URL+"/v1/models?key=AIzaSecret", strings.NewReader(`{"model":"m","api_key":"plain","messages":[{"content":"my key is sk-live-0123456789abcdefghij"}]}`))
+	req.Header.Set("Authorization", "Bearer secret-token")
+	req.Header.Set("X-Trace", "visible")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body, _ := io.ReadAll(resp.Body); !strings.Contains(string(body), "hello") {
+		t.Errorf("response body = %q", body)
+	}
+	resp.Body.Close()
+
+	logs, _ := filepath.Glob(filepath.Join(dir, "sess-1", wireLogSubdir, "task-1", "*.json"))
+	if len(logs) != 1 {
+		t.Fatalf("wire logs = %v, want one under the session and task", logs)
+	}
+	data, err := os.ReadFile(logs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	log := string(data)
+	for _, secret := range []string{"secret-token", "AIzaSecret", "plain", "sk-live-0123456789abcdefghij"} {
+		if strings.Contains(log, secret) {
+			t.Errorf("wire log contains %q:\n%s", secret, log)
+		}
+	}
+	for _, want := range []string{`"X-Trace": "visible"`, `"model": "m"`, `hello`, `"status": 200`} {
+		if !strings.Contains(log, want) {
+			t.Errorf("wire log is missing %s:\n%s", want, log)
+		}
+	}
+}
+
+func TestWireLogTransport_Sampling(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	client := &http.Client{Transport: &wireLogTransport{base: http.DefaultTransport, config: WireLogConfig{Enabled: true, SampleRate: 0, Dir: dir}}}
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("a sample rate of 0 wrote %d entries", len(entries))
+	}
+}
+
+func TestWireLogConfigFromEnv(t *testing.T) {
+	t.Setenv(EnvWireLog, "true")
+	t.Setenv(EnvWireLogSampleRate, "0.25")
+	t.Setenv(EnvWireLogDir, "/var/log/kagent/")
+	cfg, err := WireLogConfigFromEnv()
+	if err != nil {
+		t.Fatalf("WireLogConfigFromEnv() error = %v", err)
+	}
+	if !cfg.Enabled || cfg.SampleRate != 0.25 || cfg.Dir != "/var/log/kagent" {
+		t.Errorf("WireLogConfigFromEnv() = %+v", cfg)
+	}
+
+	t.Setenv(EnvWireLogSampleRate, "2")
+	if _, err := WireLogConfigFromEnv(); err == nil {
+		t.Error("WireLogConfigFromEnv() accepted a sample rate above 1")
+	}
+}
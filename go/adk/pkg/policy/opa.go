@@ -0,0 +1,81 @@
+package policy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const defaultOPATimeout = 5 * time.Second
+
+// opaEngine queries the OPA REST data API:
+// POST <url>/v1/data/<path> with {"input": Request}.
+type opaEngine struct {
+	endpoint string
+	client   *http.Client
+}
+
+func newOPAEngine(cfg *OPAConfig) *opaEngine {
+	timeout := defaultOPATimeout
+	if cfg.TimeoutSeconds > 0 {
+		timeout = time.Duration(cfg.TimeoutSeconds) * time.Second
+	}
+	return &opaEngine{
+		endpoint: strings.TrimRight(cfg.URL, "/") + "/v1/data/" + strings.Trim(cfg.Path, "/"),
+		client:   &http.Client{Timeout: timeout},
+	}
+}
+
+func (e *opaEngine) Decide(ctx context.Context, req Request) (Decision, error) {
+	body, err := json.Marshal(map[string]any{"input": req})
+	if err != nil {
+		return Decision{}, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return Decision{}, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	resp, err := e.client.Do(httpReq)
+	if err != nil {
+		return Decision{}, fmt.Errorf("opa query failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return Decision{}, fmt.Errorf("opa query failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	var out struct {
+		Result json.RawMessage `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return Decision{}, fmt.Errorf("failed to decode opa response: %w", err)
+	}
+	if len(out.Result) == 0 {
+		return Decision{}, fmt.Errorf("opa document %s is undefined", e.endpoint)
+	}
+
+	d := Decision{Rule: "opa"}
+	var effect string
+	if err := json.Unmarshal(out.Result, &effect); err == nil {
+		d.Effect = Effect(effect)
+	} else {
+		var obj struct {
+			Effect Effect `json:"effect"`
+			Reason string `json:"reason"`
+		}
+		if err := json.Unmarshal(out.Result, &obj); err != nil {
+			return Decision{}, fmt.Errorf("unexpected opa result %s", string(out.Result))
+		}
+		d.Effect, d.Reason = obj.Effect, obj.Reason
+	}
+	if !d.Effect.valid() {
+		return Decision{}, fmt.Errorf("opa returned unknown effect %q", d.Effect)
+	}
+	return d, nil
+}
@@ -0,0 +1,240 @@
+// Package policy decides whether a principal may invoke a tool. Decisions
+// come from built-in rules loaded from a YAML file and, optionally, from an
+// Open Policy Agent server queried over its REST data API.
+package policy
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// EnvPolicyFile is the environment variable that enables tool authorization.
+// Its value is the path of the policy file.
+const EnvPolicyFile = "KAGENT_TOOL_POLICY"
+
+// Effect is the outcome of a policy evaluation.
+type Effect string
+
+const (
+	EffectAllow           Effect = "allow"
+	EffectDeny            Effect = "deny"
+	EffectRequireApproval Effect = "require_approval"
+)
+
+func (e Effect) valid() bool {
+	switch e {
+	case EffectAllow, EffectDeny, EffectRequireApproval:
+		return true
+	}
+	return false
+}
+
+// Request describes a tool invocation to authorize.
+type Request struct {
+	User      string         `json:"user"`
+	Roles     []string       `json:"roles,omitempty"`
+	AppName   string         `json:"app_name,omitempty"`
+	AgentName string         `json:"agent_name,omitempty"`
+	SessionID string         `json:"session_id,omitempty"`
+	Tool      string         `json:"tool"`
+	Args      map[string]any `json:"args,omitempty"`
+}
+
+// Decision is the result of evaluating a Request.
+type Decision struct {
+	Effect Effect `json:"effect"`
+	// Rule names the rule (or "opa") that produced the decision; empty when
+	// the default effect applied.
+	Rule   string `json:"rule,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// Engine evaluates tool invocation requests.
+type Engine interface {
+	Decide(ctx context.Context, req Request) (Decision, error)
+}
+
+// Rule matches tool invocations and assigns them an effect. Tools, Users and
+// Roles are glob patterns (path.Match syntax); an empty list matches
+// everything.
+type Rule struct {
+	Name   string   `json:"name,omitempty"`
+	Tools  []string `json:"tools,omitempty"`
+	Users  []string `json:"users,omitempty"`
+	Roles  []string `json:"roles,omitempty"`
+	Effect Effect   `json:"effect"`
+	Reason string   `json:"reason,omitempty"`
+}
+
+// OPAConfig points the policy at an Open Policy Agent server. The document at
+// Path must evaluate to an effect string or an object with "effect" and
+// optional "reason" keys.
+type OPAConfig struct {
+	URL  string `json:"url"`
+	Path string `json:"path"`
+	// TimeoutSeconds bounds each query. Defaults to 5.
+	TimeoutSeconds int `json:"timeoutSeconds,omitempty"`
+}
+
+// Policy is the on-disk policy format.
+type Policy struct {
+	// DefaultEffect applies when no rule matches. Defaults to allow.
+	DefaultEffect Effect `json:"defaultEffect,omitempty"`
+	// Roles maps role names to the users (glob patterns) holding them.
+	Roles map[string][]string `json:"roles,omitempty"`
+	// Rules are evaluated in order; the first match wins.
+	Rules []Rule `json:"rules,omitempty"`
+	// OPA, when set, is consulted after the rules for invocations the rules
+	// allowed. It can tighten a decision but never loosen one.
+	OPA *OPAConfig `json:"opa,omitempty"`
+}
+
+// Load reads and validates the policy file at path.
+func Load(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tool policy %s: %w", path, err)
+	}
+	var p Policy
+	if err := yaml.UnmarshalStrict(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse tool policy %s: %w", path, err)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid tool policy %s: %w", path, err)
+	}
+	return &p, nil
+}
+
+// Validate checks effects and glob patterns.
+func (p *Policy) Validate() error {
+	if p.DefaultEffect != "" && !p.DefaultEffect.valid() {
+		return fmt.Errorf("unknown default effect %q", p.DefaultEffect)
+	}
+	for role, users := range p.Roles {
+		if err := validatePatterns(users); err != nil {
+			return fmt.Errorf("role %q: %w", role, err)
+		}
+	}
+	for i, r := range p.Rules {
+		if !r.Effect.valid() {
+			return fmt.Errorf("rule %d: unknown effect %q", i, r.Effect)
+		}
+		for _, patterns := range [][]string{r.Tools, r.Users, r.Roles} {
+			if err := validatePatterns(patterns); err != nil {
+				return fmt.Errorf("rule %d: %w", i, err)
+			}
+		}
+	}
+	if p.OPA != nil && (p.OPA.URL == "" || p.OPA.Path == "") {
+		return fmt.Errorf("opa requires url and path")
+	}
+	return nil
+}
+
+func validatePatterns(patterns []string) error {
+	for _, pat := range patterns {
+		if _, err := path.Match(pat, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", pat, err)
+		}
+	}
+	return nil
+}
+
+// NewEngine builds the Engine for p.
+func NewEngine(p *Policy) Engine {
+	rules := &ruleEngine{policy: p}
+	if p.OPA == nil {
+		return rules
+	}
+	return &chainEngine{rules: rules, opa: newOPAEngine(p.OPA)}
+}
+
+// RolesFor returns the roles held by user.
+func (p *Policy) RolesFor(user string) []string {
+	var roles []string
+	for role, users := range p.Roles {
+		if matchAny(users, user) {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+type ruleEngine struct {
+	policy *Policy
+}
+
+func (e *ruleEngine) Decide(_ context.Context, req Request) (Decision, error) {
+	roles := append(e.policy.RolesFor(req.User), req.Roles...)
+	for i, r := range e.policy.Rules {
+		if len(r.Tools) > 0 && !matchAny(r.Tools, req.Tool) {
+			continue
+		}
+		if len(r.Users) > 0 && !matchAny(r.Users, req.User) {
+			continue
+		}
+		if len(r.Roles) > 0 && !matchAnyOf(r.Roles, roles) {
+			continue
+		}
+		name := r.Name
+		if name == "" {
+			name = fmt.Sprintf("rule-%d", i)
+		}
+		return Decision{Effect: r.Effect, Rule: name, Reason: r.Reason}, nil
+	}
+	effect := e.policy.DefaultEffect
+	if effect == "" {
+		effect = EffectAllow
+	}
+	return Decision{Effect: effect, Reason: "no rule matched"}, nil
+}
+
+// chainEngine applies the rules first and lets OPA tighten allow decisions.
+type chainEngine struct {
+	rules Engine
+	opa   Engine
+}
+
+func (e *chainEngine) Decide(ctx context.Context, req Request) (Decision, error) {
+	d, err := e.rules.Decide(ctx, req)
+	if err != nil || d.Effect != EffectAllow {
+		return d, err
+	}
+	return e.opa.Decide(ctx, req)
+}
+
+func matchAny(patterns []string, s string) bool {
+	for _, pat := range patterns {
+		if ok, _ := path.Match(pat, s); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func matchAnyOf(patterns, values []string) bool {
+	for _, v := range values {
+		if matchAny(patterns, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// String renders a decision for logs.
+func (d Decision) String() string {
+	var b strings.Builder
+	b.WriteString(string(d.Effect))
+	if d.Rule != "" {
+		b.WriteString(" (" + d.Rule + ")")
+	}
+	if d.Reason != "" {
+		b.WriteString(": " + d.Reason)
+	}
+	return b.String()
+}
@@ -0,0 +1,152 @@
+package policy
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testPolicy = `
+defaultEffect: deny
+roles:
+  sre: ["alice@example.com", "*@sre.example.com"]
+rules:
+  - name: sre-mutations
+    tools: ["delete_*", "scale_*"]
+    roles: ["sre"]
+    effect: require_approval
+  - name: no-mutations
+    tools: ["delete_*", "scale_*"]
+    effect: deny
+    reason: only SREs may mutate resources
+  - name: read-only
+    tools: ["get_*", "list_*"]
+    effect: allow
+`
+
+func writePolicy(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRuleEngine(t *testing.T) {
+	p, err := Load(writePolicy(t, testPolicy))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	engine := NewEngine(p)
+
+	tests := []struct {
+		user, tool string
+		want       Effect
+		wantRule   string
+	}{
+		{"alice@example.com", "delete_pod", EffectRequireApproval, "sre-mutations"},
+		{"bob@sre.example.com", "scale_deployment", EffectRequireApproval, "sre-mutations"},
+		{"mallory@example.com", "delete_pod", EffectDeny, "no-mutations"},
+		{"mallory@example.com", "get_pods", EffectAllow, "read-only"},
+		{"mallory@example.com", "exec_shell", EffectDeny, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.user+"/"+tt.tool, func(t *testing.T) {
+			d, err := engine.Decide(t.Context(), Request{User: tt.user, Tool: tt.tool})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d.Effect != tt.want || d.Rule != tt.wantRule {
+				t.Errorf("Decide() = %+v, want effect %s rule %q", d, tt.want, tt.wantRule)
+			}
+		})
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	for name, content := range map[string]string{
+		"unknown effect":  "rules:\n  - effect: maybe\n",
+		"bad pattern":     "rules:\n  - tools: [\"[\"]\n    effect: allow\n",
+		"unknown field":   "rulez: []\n",
+		"opa without url": "opa:\n  path: kagent/tools\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Load(writePolicy(t, content)); err == nil {
+				t.Error("Load() should fail")
+			}
+		})
+	}
+}
+
+func TestOPAEngine(t *testing.T) {
+	var gotInput Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/data/kagent/tools/decision" {
+			http.NotFound(w, r)
+			return
+		}
+		var body struct {
+			Input Request `json:"input"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		gotInput = body.Input
+		switch {
+		case strings.HasPrefix(body.Input.Tool, "delete_"):
+			_, _ = w.Write([]byte(`{"result":{"effect":"deny","reason":"deletes are frozen"}}`))
+		case body.Input.Tool == "undefined":
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			_, _ = w.Write([]byte(`{"result":"allow"}`))
+		}
+	}))
+	defer srv.Close()
+
+	p, err := Load(writePolicy(t, `
+rules:
+  - tools: ["exec_*"]
+    effect: require_approval
+opa:
+  url: `+srv.URL+`/
+  path: /kagent/tools/decision
+`))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	engine := NewEngine(p)
+
+	d, err := engine.Decide(t.Context(), Request{User: "alice", Tool: "delete_pod", Args: map[string]any{"name": "a"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Effect != EffectDeny || d.Rule != "opa" || d.Reason != "deletes are frozen" {
+		t.Errorf("Decide(delete_pod) = %+v", d)
+	}
+	if gotInput.User != "alice" || gotInput.Args["name"] != "a" {
+		t.Errorf("OPA input = %+v", gotInput)
+	}
+
+	if d, err = engine.Decide(t.Context(), Request{User: "alice", Tool: "get_pods"}); err != nil || d.Effect != EffectAllow {
+		t.Errorf("Decide(get_pods) = %+v, %v", d, err)
+	}
+
+	// Rules that do not allow are final; OPA is not consulted.
+	gotInput = Request{}
+	if d, err = engine.Decide(t.Context(), Request{User: "alice", Tool: "exec_shell"}); err != nil || d.Effect != EffectRequireApproval {
+		t.Errorf("Decide(exec_shell) = %+v, %v", d, err)
+	}
+	if gotInput.Tool != "" {
+		t.Error("OPA should not be queried when a rule requires approval")
+	}
+
+	if _, err = engine.Decide(t.Context(), Request{User: "alice", Tool: "undefined"}); err == nil {
+		t.Error("Decide() should fail when the OPA document is undefined")
+	}
+}
@@ -0,0 +1,97 @@
+// Package prompts chooses the prompt version of each run from the agent's
+// versioned prompts: the version pinned by the request, else the one the
+// prompt experiment assigns to the conversation, else the active version.
+package prompts
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+
+	"github.com/kagent-dev/kagent/go/api/adk"
+)
+
+// Experiment arms.
+const (
+	ArmControl   = "control"
+	ArmCandidate = "candidate"
+)
+
+// Assignment is the prompt version chosen for a run.
+type Assignment struct {
+	Version *adk.PromptVersion
+	// Experiment and Arm are set when the prompt experiment chose the
+	// version.
+	Experiment string
+	Arm        string
+}
+
+// Router assigns prompt versions to runs. A nil Router assigns nothing.
+type Router struct {
+	cfg *adk.PromptConfig
+}
+
+// NewRouter validates cfg and returns its router, or nil when cfg is nil.
+func NewRouter(cfg *adk.PromptConfig) (*Router, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &Router{cfg: cfg}, nil
+}
+
+// Assign returns the version of a run in the conversation conversationID.
+// pinned names a version requested explicitly, e.g. by an evaluation; an
+// unknown pinned version is an error. Conversations are assigned to an
+// experiment arm by a hash of their ID, so all their runs get the same
+// version.
+func (r *Router) Assign(conversationID, pinned string) (Assignment, error) {
+	if r == nil {
+		if pinned != "" {
+			return Assignment{}, fmt.Errorf("prompt version %q requested but the agent has no prompt versions", pinned)
+		}
+		return Assignment{}, nil
+	}
+	if pinned != "" {
+		v := r.cfg.Version(pinned)
+		if v == nil {
+			return Assignment{}, fmt.Errorf("unknown prompt version %q", pinned)
+		}
+		return Assignment{Version: v}, nil
+	}
+	e := r.cfg.Experiment
+	if e == nil {
+		return Assignment{Version: r.cfg.ActiveVersion()}, nil
+	}
+	arm, version := ArmControl, e.Control
+	if bucket(e.Name, conversationID) < e.CandidatePercent {
+		arm, version = ArmCandidate, e.Candidate
+	}
+	return Assignment{Version: r.cfg.Version(version), Experiment: e.Name, Arm: arm}, nil
+}
+
+// bucket maps a conversation to [0, 100). The experiment name is hashed in
+// so that successive experiments split conversations differently.
+func bucket(experiment, conversationID string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(experiment + "/" + conversationID))
+	return int(h.Sum32() % 100)
+}
+
+type assignmentKey struct{}
+
+// WithAssignment attaches the run's prompt assignment to ctx.
+func WithAssignment(ctx context.Context, a Assignment) context.Context {
+	if a.Version == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, assignmentKey{}, a)
+}
+
+// AssignmentFrom returns the assignment attached with WithAssignment.
+func AssignmentFrom(ctx context.Context) (Assignment, bool) {
+	a, ok := ctx.Value(assignmentKey{}).(Assignment)
+	return a, ok
+}
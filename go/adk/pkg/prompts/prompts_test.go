@@ -0,0 +1,80 @@
+package prompts
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/kagent-dev/kagent/go/api/adk"
+)
+
+var versions = []adk.PromptVersion{
+	{Version: "v1", Instruction: "Be helpful."},
+	{Version: "v2", Instruction: "Be brief."},
+}
+
+func TestRouter_Experiment(t *testing.T) {
+	r, err := NewRouter(&adk.PromptConfig{
+		Versions:   versions,
+		Experiment: &adk.PromptExperiment{Name: "brevity", Control: "v1", Candidate: "v2", CandidatePercent: 30},
+	})
+	if err != nil {
+		t.Fatalf("NewRouter() error = %v", err)
+	}
+
+	candidates := 0
+	for i := range 1000 {
+		conversation := fmt.Sprintf("ctx-%d", i)
+		a, err := r.Assign(conversation, "")
+		if err != nil {
+			t.Fatalf("Assign() error = %v", err)
+		}
+		if a.Experiment != "brevity" {
+			t.Fatalf("Assign() = %+v, want an experiment assignment", a)
+		}
+		if a.Arm == ArmCandidate {
+			candidates++
+			if a.Version.Version != "v2" {
+				t.Fatalf("candidate arm got version %q", a.Version.Version)
+			}
+		}
+		if again, _ := r.Assign(conversation, ""); again.Version != a.Version {
+			t.Fatalf("conversation %s moved from %q to %q", conversation, a.Version.Version, again.Version.Version)
+		}
+	}
+	if candidates < 250 || candidates > 350 {
+		t.Errorf("%d of 1000 conversations routed to the candidate, want about 300", candidates)
+	}
+}
+
+func TestRouter_Assign(t *testing.T) {
+	r, err := NewRouter(&adk.PromptConfig{Versions: versions, Active: "v1"})
+	if err != nil {
+		t.Fatalf("NewRouter() error = %v", err)
+	}
+	if a, _ := r.Assign("ctx-1", ""); a.Version.Version != "v1" || a.Experiment != "" {
+		t.Errorf("Assign() = %+v, want the active version", a)
+	}
+	if a, _ := r.Assign("ctx-1", "v2"); a.Version.Version != "v2" {
+		t.Errorf("Assign() = %+v, want the pinned version", a)
+	}
+	if _, err := r.Assign("ctx-1", "v3"); err == nil {
+		t.Error("Assign() of an unknown version error = nil")
+	}
+
+	var none *Router
+	if a, err := none.Assign("ctx-1", ""); err != nil || a.Version != nil {
+		t.Errorf("nil Router Assign() = %+v, %v", a, err)
+	}
+	if _, err := none.Assign("ctx-1", "v1"); err == nil {
+		t.Error("nil Router Assign() of a pinned version error = nil")
+	}
+	if _, err := NewRouter(&adk.PromptConfig{}); err == nil {
+		t.Error("NewRouter() of an invalid config error = nil")
+	}
+
+	ctx := WithAssignment(context.Background(), Assignment{Version: &versions[0]})
+	if a, ok := AssignmentFrom(ctx); !ok || a.Version.Version != "v1" {
+		t.Errorf("AssignmentFrom() = %+v, %v", a, ok)
+	}
+}
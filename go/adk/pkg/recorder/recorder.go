@@ -0,0 +1,390 @@
+// Package recorder captures LLM requests, LLM responses and tool calls made
+// during an agent invocation into JSONL trace files, and loads them back so
+// recorded conversations can be replayed against new prompts or models.
+// Traces can also be written as LangSmith run trees, for observability tools
+// that ingest LangSmith runs.
+package recorder
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/model"
+	adkplugin "google.golang.org/adk/plugin"
+	"google.golang.org/adk/tool"
+	"google.golang.org/genai"
+)
+
+// EnvTraceDir is the environment variable that enables trace recording. When
+// set, one trace file per invocation is written to that directory.
+const EnvTraceDir = "KAGENT_TRACE_DIR"
+
+// EnvTraceFormat selects the Format of the trace files, records by default.
+const EnvTraceFormat = "KAGENT_TRACE_FORMAT"
+
+// Format is the serialization of trace files.
+type Format string
+
+const (
+	// FormatRecords writes the records of an invocation as they happen to
+	// <invocation>.jsonl. Only these traces can be loaded back with Load.
+	FormatRecords Format = "records"
+	// FormatLangSmith writes the run tree of an invocation (see
+	// Trace.RunTree), one run per line, to <invocation>.langsmith.jsonl once
+	// the invocation ends.
+	FormatLangSmith Format = "langsmith"
+)
+
+// RecordType identifies the kind of a trace record.
+type RecordType string
+
+const (
+	RecordTypeLLMRequest  RecordType = "llm_request"
+	RecordTypeLLMResponse RecordType = "llm_response"
+	RecordTypeToolCall    RecordType = "tool_call"
+	RecordTypeToolResult  RecordType = "tool_result"
+)
+
+// Record is a single line of a trace file.
+type Record struct {
+	Type         RecordType `json:"type"`
+	Timestamp    time.Time  `json:"timestamp"`
+	InvocationID string     `json:"invocation_id"`
+	SessionID    string     `json:"session_id,omitempty"`
+	AgentName    string     `json:"agent_name,omitempty"`
+
+	// LLM request/response fields.
+	Model    string                                      `json:"model,omitempty"`
+	Contents []*genai.Content                            `json:"contents,omitempty"`
+	Config   *genai.GenerateContentConfig                `json:"config,omitempty"`
+	Response *genai.Content                              `json:"response,omitempty"`
+	Usage    *genai.GenerateContentResponseUsageMetadata `json:"usage,omitempty"`
+
+	// Tool call fields.
+	ToolName   string         `json:"tool_name,omitempty"`
+	ToolCallID string         `json:"tool_call_id,omitempty"`
+	Args       map[string]any `json:"args,omitempty"`
+	Result     map[string]any `json:"result,omitempty"`
+
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms,omitempty"`
+}
+
+// Recorder is an ADK plugin that appends trace records to a JSONL file per
+// invocation in its trace directory.
+type Recorder struct {
+	dir    string
+	format Format
+	logger logr.Logger
+
+	mu          sync.Mutex
+	files       map[string]*os.File // keyed by invocation ID
+	pending     map[string][]Record // keyed by invocation ID, for FormatLangSmith
+	modelStarts map[string]time.Time
+	toolStarts  map[string]time.Time // keyed by function call ID
+}
+
+// New creates a Recorder writing records into dir, creating it if necessary.
+func New(dir string, logger logr.Logger) (*Recorder, error) {
+	return NewWithFormat(dir, FormatRecords, logger)
+}
+
+// NewWithFormat creates a Recorder writing traces in format into dir,
+// creating it if necessary.
+func NewWithFormat(dir string, format Format, logger logr.Logger) (*Recorder, error) {
+	switch format {
+	case FormatRecords, FormatLangSmith:
+	default:
+		return nil, fmt.Errorf("unknown trace format %q", format)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create trace directory %s: %w", dir, err)
+	}
+	return &Recorder{
+		dir:         dir,
+		format:      format,
+		logger:      logger.WithName("trace-recorder"),
+		files:       make(map[string]*os.File),
+		pending:     make(map[string][]Record),
+		modelStarts: make(map[string]time.Time),
+		toolStarts:  make(map[string]time.Time),
+	}, nil
+}
+
+// NewFromEnv returns a Recorder when KAGENT_TRACE_DIR is set, or nil otherwise.
+// KAGENT_TRACE_FORMAT selects its format.
+func NewFromEnv(logger logr.Logger) (*Recorder, error) {
+	dir := strings.TrimSpace(os.Getenv(EnvTraceDir))
+	if dir == "" {
+		return nil, nil
+	}
+	format := FormatRecords
+	if v := strings.TrimSpace(os.Getenv(EnvTraceFormat)); v != "" {
+		format = Format(strings.ToLower(v))
+		if format != FormatRecords && format != FormatLangSmith {
+			return nil, fmt.Errorf("invalid %s %q", EnvTraceFormat, v)
+		}
+	}
+	return NewWithFormat(dir, format, logger)
+}
+
+// Path returns the trace file path for an invocation.
+func (r *Recorder) Path(invocationID string) string {
+	if r.format == FormatLangSmith {
+		return filepath.Join(r.dir, invocationID+".langsmith.jsonl")
+	}
+	return filepath.Join(r.dir, invocationID+".jsonl")
+}
+
+// ADKPlugin returns the Go ADK plugin registered with runner.PluginConfig.
+func (r *Recorder) ADKPlugin() (*adkplugin.Plugin, error) {
+	return adkplugin.New(adkplugin.Config{
+		Name:                "kagent-trace-recorder",
+		BeforeModelCallback: r.BeforeModelCallback,
+		AfterModelCallback:  r.AfterModelCallback,
+		BeforeToolCallback:  r.BeforeToolCallback,
+		AfterToolCallback:   r.AfterToolCallback,
+		AfterRunCallback:    r.AfterRunCallback,
+		CloseFunc:           r.Close,
+	})
+}
+
+// BeforeModelCallback records the outgoing LLM request.
+func (r *Recorder) BeforeModelCallback(ctx agent.CallbackContext, req *model.LLMRequest) (*model.LLMResponse, error) {
+	rec := newRecord(RecordTypeLLMRequest, ctx)
+	if req != nil {
+		rec.Model = req.Model
+		rec.Contents = req.Contents
+		rec.Config = req.Config
+	}
+	r.mu.Lock()
+	r.modelStarts[ctx.InvocationID()] = rec.Timestamp
+	r.mu.Unlock()
+	r.write(rec)
+	return nil, nil
+}
+
+// AfterModelCallback records the final (non-partial) LLM response.
+func (r *Recorder) AfterModelCallback(ctx agent.CallbackContext, resp *model.LLMResponse, respErr error) (*model.LLMResponse, error) {
+	if resp != nil && resp.Partial {
+		return nil, nil
+	}
+	rec := newRecord(RecordTypeLLMResponse, ctx)
+	if resp != nil {
+		rec.Response = resp.Content
+		rec.Usage = resp.UsageMetadata
+		if resp.ErrorMessage != "" {
+			rec.Error = resp.ErrorMessage
+		}
+	}
+	if respErr != nil {
+		rec.Error = respErr.Error()
+	}
+	r.mu.Lock()
+	if start, ok := r.modelStarts[ctx.InvocationID()]; ok {
+		rec.DurationMs = rec.Timestamp.Sub(start).Milliseconds()
+		delete(r.modelStarts, ctx.InvocationID())
+	}
+	r.mu.Unlock()
+	r.write(rec)
+	return nil, nil
+}
+
+// BeforeToolCallback records a tool invocation.
+func (r *Recorder) BeforeToolCallback(ctx tool.Context, t tool.Tool, args map[string]any) (map[string]any, error) {
+	rec := newRecord(RecordTypeToolCall, ctx)
+	rec.ToolName = t.Name()
+	rec.ToolCallID = ctx.FunctionCallID()
+	rec.Args = args
+	r.mu.Lock()
+	r.toolStarts[rec.ToolCallID] = rec.Timestamp
+	r.mu.Unlock()
+	r.write(rec)
+	return nil, nil
+}
+
+// AfterToolCallback records a tool result or error.
+func (r *Recorder) AfterToolCallback(ctx tool.Context, t tool.Tool, args, result map[string]any, err error) (map[string]any, error) {
+	rec := newRecord(RecordTypeToolResult, ctx)
+	rec.ToolName = t.Name()
+	rec.ToolCallID = ctx.FunctionCallID()
+	rec.Result = result
+	if err != nil {
+		rec.Error = err.Error()
+	}
+	r.mu.Lock()
+	if start, ok := r.toolStarts[rec.ToolCallID]; ok {
+		rec.DurationMs = rec.Timestamp.Sub(start).Milliseconds()
+		delete(r.toolStarts, rec.ToolCallID)
+	}
+	r.mu.Unlock()
+	r.write(rec)
+	return nil, nil
+}
+
+// AfterRunCallback closes the trace file of the finished invocation, or
+// writes its run tree.
+func (r *Recorder) AfterRunCallback(ctx agent.InvocationContext) {
+	r.closeInvocation(ctx.InvocationID())
+}
+
+// Close closes all open trace files, and writes the run trees of the
+// invocations still running.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var firstErr error
+	for id, records := range r.pending {
+		if err := r.writeRunTree(id, records); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		delete(r.pending, id)
+	}
+	for id, f := range r.files {
+		if err := f.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		delete(r.files, id)
+	}
+	return firstErr
+}
+
+func (r *Recorder) closeInvocation(invocationID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.modelStarts, invocationID)
+	if records, ok := r.pending[invocationID]; ok {
+		delete(r.pending, invocationID)
+		if err := r.writeRunTree(invocationID, records); err != nil {
+			r.logger.Error(err, "Failed to write run tree", "invocationID", invocationID)
+		}
+	}
+	if f, ok := r.files[invocationID]; ok {
+		if err := f.Close(); err != nil {
+			r.logger.Error(err, "Failed to close trace file", "invocationID", invocationID)
+		}
+		delete(r.files, invocationID)
+	}
+}
+
+// writeRunTree writes the run tree of an invocation's records to its trace
+// file.
+func (r *Recorder) writeRunTree(invocationID string, records []Record) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, run := range runTree(records) {
+		if err := enc.Encode(run); err != nil {
+			return fmt.Errorf("failed to marshal run %s: %w", run.ID, err)
+		}
+	}
+	return os.WriteFile(r.Path(invocationID), buf.Bytes(), 0o644)
+}
+
+// write appends rec to its invocation's trace file, or keeps it for the
+// invocation's run tree. Recording failures are logged and never interrupt
+// the agent run.
+func (r *Recorder) write(rec Record) {
+	if r.format == FormatLangSmith {
+		r.mu.Lock()
+		r.pending[rec.InvocationID] = append(r.pending[rec.InvocationID], rec)
+		r.mu.Unlock()
+		return
+	}
+	line, err := json.Marshal(rec)
+	if err != nil {
+		r.logger.Error(err, "Failed to marshal trace record", "type", rec.Type)
+		return
+	}
+	line = append(line, '\n')
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	f, ok := r.files[rec.InvocationID]
+	if !ok {
+		f, err = os.OpenFile(r.Path(rec.InvocationID), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			r.logger.Error(err, "Failed to open trace file", "invocationID", rec.InvocationID)
+			return
+		}
+		r.files[rec.InvocationID] = f
+	}
+	if _, err := f.Write(line); err != nil {
+		r.logger.Error(err, "Failed to write trace record", "invocationID", rec.InvocationID)
+	}
+}
+
+func newRecord(t RecordType, ctx agent.ReadonlyContext) Record {
+	return Record{
+		Type:         t,
+		Timestamp:    time.Now(),
+		InvocationID: ctx.InvocationID(),
+		SessionID:    ctx.SessionID(),
+		AgentName:    ctx.AgentName(),
+	}
+}
+
+// Trace is a loaded trace file.
+type Trace struct {
+	Records []Record
+}
+
+// Load reads a JSONL trace file written by Recorder.
+func Load(path string) (*Trace, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open trace %s: %w", path, err)
+	}
+	defer f.Close()
+
+	var t Trace
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("failed to parse trace %s line %d: %w", path, lineNo, err)
+		}
+		t.Records = append(t.Records, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read trace %s: %w", path, err)
+	}
+	return &t, nil
+}
+
+// LLMRequests rebuilds the recorded LLM requests in order, ready to be sent
+// to a different model.LLM for regression evaluation.
+func (t *Trace) LLMRequests() []*model.LLMRequest {
+	var out []*model.LLMRequest
+	for _, rec := range t.Records {
+		if rec.Type == RecordTypeLLMRequest {
+			out = append(out, &model.LLMRequest{Model: rec.Model, Contents: rec.Contents, Config: rec.Config})
+		}
+	}
+	return out
+}
+
+// LLMResponses returns the recorded final LLM responses in order.
+func (t *Trace) LLMResponses() []*genai.Content {
+	var out []*genai.Content
+	for _, rec := range t.Records {
+		if rec.Type == RecordTypeLLMResponse {
+			out = append(out, rec.Response)
+		}
+	}
+	return out
+}
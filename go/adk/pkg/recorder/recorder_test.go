@@ -0,0 +1,219 @@
+package recorder
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+	"google.golang.org/genai"
+)
+
+type fakeCallbackContext struct {
+	agent.CallbackContext
+	invocationID string
+}
+
+func (f fakeCallbackContext) InvocationID() string { return f.invocationID }
+func (f fakeCallbackContext) SessionID() string    { return "session-1" }
+func (f fakeCallbackContext) AgentName() string    { return "test_agent" }
+
+type fakeToolContext struct {
+	agent.ToolContext
+	invocationID   string
+	functionCallID string
+}
+
+func (f fakeToolContext) InvocationID() string   { return f.invocationID }
+func (f fakeToolContext) SessionID() string      { return "session-1" }
+func (f fakeToolContext) AgentName() string      { return "test_agent" }
+func (f fakeToolContext) FunctionCallID() string { return f.functionCallID }
+
+type fakeInvocationContext struct {
+	agent.InvocationContext
+	invocationID string
+}
+
+func (f fakeInvocationContext) InvocationID() string { return f.invocationID }
+
+type fakeTool struct {
+	tool.Tool
+	name string
+}
+
+func (f fakeTool) Name() string { return f.name }
+
+func TestRecorder_RoundTrip(t *testing.T) {
+	r, err := New(t.TempDir(), logr.Discard())
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	const inv = "inv-1"
+	cbCtx := fakeCallbackContext{invocationID: inv}
+	toolCtx := fakeToolContext{invocationID: inv, functionCallID: "call_1"}
+	clock := fakeTool{name: "get_time"}
+
+	req := &model.LLMRequest{
+		Model:    "gpt-4o",
+		Contents: []*genai.Content{genai.NewContentFromText("what time is it?", genai.RoleUser)},
+	}
+	if _, err := r.BeforeModelCallback(cbCtx, req); err != nil {
+		t.Fatal(err)
+	}
+	// Partial responses are not recorded.
+	if _, err := r.AfterModelCallback(cbCtx, &model.LLMResponse{Partial: true, Content: genai.NewContentFromText("par", genai.RoleModel)}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.AfterModelCallback(cbCtx, &model.LLMResponse{
+		Content:       &genai.Content{Role: genai.RoleModel, Parts: []*genai.Part{genai.NewPartFromFunctionCall("get_time", nil)}},
+		UsageMetadata: &genai.GenerateContentResponseUsageMetadata{PromptTokenCount: 10, CandidatesTokenCount: 5},
+	}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.BeforeToolCallback(toolCtx, clock, map[string]any{"tz": "UTC"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.AfterToolCallback(toolCtx, clock, map[string]any{"tz": "UTC"}, nil, errors.New("clock unavailable")); err != nil {
+		t.Fatal(err)
+	}
+	r.AfterRunCallback(fakeInvocationContext{invocationID: inv})
+
+	trace, err := Load(r.Path(inv))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	wantTypes := []RecordType{RecordTypeLLMRequest, RecordTypeLLMResponse, RecordTypeToolCall, RecordTypeToolResult}
+	if len(trace.Records) != len(wantTypes) {
+		t.Fatalf("got %d records, want %d", len(trace.Records), len(wantTypes))
+	}
+	for i, want := range wantTypes {
+		rec := trace.Records[i]
+		if rec.Type != want {
+			t.Errorf("record %d: type = %q, want %q", i, rec.Type, want)
+		}
+		if rec.InvocationID != inv || rec.SessionID != "session-1" || rec.AgentName != "test_agent" {
+			t.Errorf("record %d: unexpected identity %+v", i, rec)
+		}
+	}
+
+	if got := trace.Records[1].Usage; got == nil || got.PromptTokenCount != 10 {
+		t.Errorf("usage not recorded: %+v", got)
+	}
+	toolResult := trace.Records[3]
+	if toolResult.ToolName != "get_time" || toolResult.ToolCallID != "call_1" || toolResult.Error != "clock unavailable" {
+		t.Errorf("tool result = %+v", toolResult)
+	}
+
+	reqs := trace.LLMRequests()
+	if len(reqs) != 1 || reqs[0].Model != "gpt-4o" || reqs[0].Contents[0].Parts[0].Text != "what time is it?" {
+		t.Errorf("LLMRequests() = %+v", reqs)
+	}
+	resps := trace.LLMResponses()
+	if len(resps) != 1 || resps[0].Parts[0].FunctionCall == nil || resps[0].Parts[0].FunctionCall.Name != "get_time" {
+		t.Errorf("LLMResponses() = %+v", resps)
+	}
+}
+
+func TestNewFromEnv(t *testing.T) {
+	t.Setenv(EnvTraceDir, "")
+	r, err := NewFromEnv(logr.Discard())
+	if err != nil || r != nil {
+		t.Fatalf("NewFromEnv with unset dir = (%v, %v), want (nil, nil)", r, err)
+	}
+
+	t.Setenv(EnvTraceDir, t.TempDir())
+	r, err = NewFromEnv(logr.Discard())
+	if err != nil || r == nil {
+		t.Fatalf("NewFromEnv with dir set = (%v, %v), want recorder", r, err)
+	}
+}
+
+func TestRecorder_LangSmithFormat(t *testing.T) {
+	r, err := NewWithFormat(t.TempDir(), FormatLangSmith, logr.Discard())
+	if err != nil {
+		t.Fatalf("NewWithFormat: %v", err)
+	}
+	const inv = "inv-1"
+	cbCtx := fakeCallbackContext{invocationID: inv}
+	toolCtx := fakeToolContext{invocationID: inv, functionCallID: "call_1"}
+	clock := fakeTool{name: "get_time"}
+
+	req := &model.LLMRequest{
+		Model:    "gpt-4o",
+		Contents: []*genai.Content{genai.NewContentFromText("what time is it?", genai.RoleUser)},
+	}
+	if _, err := r.BeforeModelCallback(cbCtx, req); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.AfterModelCallback(cbCtx, &model.LLMResponse{
+		Content:       genai.NewContentFromText("It is noon.", genai.RoleModel),
+		UsageMetadata: &genai.GenerateContentResponseUsageMetadata{PromptTokenCount: 10, CandidatesTokenCount: 5, TotalTokenCount: 15},
+	}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.BeforeToolCallback(toolCtx, clock, map[string]any{"tz": "UTC"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(r.Path(inv)); !os.IsNotExist(err) {
+		t.Fatalf("run tree written before the invocation ended: %v", err)
+	}
+	r.AfterRunCallback(fakeInvocationContext{invocationID: inv})
+
+	data, err := os.ReadFile(r.Path(inv))
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	var runs []Run
+	for line := range strings.Lines(string(data)) {
+		var run Run
+		if err := json.Unmarshal([]byte(line), &run); err != nil {
+			t.Fatalf("invalid run %s: %v", line, err)
+		}
+		runs = append(runs, run)
+	}
+	if len(runs) != 3 {
+		t.Fatalf("got %d runs, want invocation, llm and tool runs", len(runs))
+	}
+	root, llmRun, toolRun := runs[0], runs[1], runs[2]
+	if root.RunType != RunTypeChain || root.Name != "test_agent" || root.TraceID != root.ID || root.ParentRunID != "" {
+		t.Errorf("root run = %+v", root)
+	}
+	if root.Inputs["input"] != "what time is it?" || root.Outputs["output"] != "It is noon." {
+		t.Errorf("root inputs = %v, outputs = %v", root.Inputs, root.Outputs)
+	}
+	for _, run := range []Run{llmRun, toolRun} {
+		if run.ParentRunID != root.ID || run.TraceID != root.ID || !strings.HasPrefix(run.DottedOrder, root.DottedOrder+".") {
+			t.Errorf("run %s is not a child of the invocation: %+v", run.Name, run)
+		}
+	}
+	usage, _ := llmRun.Outputs["usage_metadata"].(map[string]any)
+	if llmRun.RunType != RunTypeLLM || llmRun.Name != "gpt-4o" || usage["total_tokens"] != 15.0 {
+		t.Errorf("llm run = %+v", llmRun)
+	}
+	if toolRun.RunType != RunTypeTool || toolRun.Inputs["tz"] != "UTC" || toolRun.Error != "no result recorded" {
+		t.Errorf("tool run = %+v, want the unfinished tool call", toolRun)
+	}
+
+	trace := &Trace{Records: []Record{{Type: RecordTypeToolCall, InvocationID: inv, AgentName: "test_agent", ToolName: "get_time", ToolCallID: "call_1"}}}
+	if again := trace.RunTree(); again[0].ID != root.ID {
+		t.Errorf("RunTree() root ID = %s, want the same ID for the same invocation", again[0].ID)
+	}
+}
+
+func TestNewFromEnv_Format(t *testing.T) {
+	t.Setenv(EnvTraceDir, t.TempDir())
+	t.Setenv(EnvTraceFormat, "LangSmith")
+	r, err := NewFromEnv(logr.Discard())
+	if err != nil || r.format != FormatLangSmith {
+		t.Fatalf("NewFromEnv = (%v, %v), want a LangSmith recorder", r, err)
+	}
+	t.Setenv(EnvTraceFormat, "otlp")
+	if _, err := NewFromEnv(logr.Discard()); err == nil {
+		t.Error("NewFromEnv with an unknown format error = nil")
+	}
+}
@@ -0,0 +1,210 @@
+package recorder
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/genai"
+)
+
+// RunType is the kind of a LangSmith run.
+type RunType string
+
+const (
+	RunTypeChain RunType = "chain"
+	RunTypeLLM   RunType = "llm"
+	RunTypeTool  RunType = "tool"
+)
+
+// Run is one node of a LangSmith run tree. Its fields follow the LangSmith
+// run schema, so the runs of a trace can be posted as is to the LangSmith
+// /runs/batch endpoint, or to other tools that ingest LangSmith runs.
+type Run struct {
+	ID          string `json:"id"`
+	TraceID     string `json:"trace_id"`
+	ParentRunID string `json:"parent_run_id,omitempty"`
+	// DottedOrder orders the run within its trace: the start time and ID of
+	// each ancestor and of the run, joined by dots.
+	DottedOrder string         `json:"dotted_order"`
+	Name        string         `json:"name"`
+	RunType     RunType        `json:"run_type"`
+	StartTime   time.Time      `json:"start_time"`
+	EndTime     time.Time      `json:"end_time"`
+	Inputs      map[string]any `json:"inputs"`
+	Outputs     map[string]any `json:"outputs,omitempty"`
+	Error       string         `json:"error,omitempty"`
+	Extra       map[string]any `json:"extra,omitempty"`
+}
+
+// RunTree converts the trace of one invocation to LangSmith runs: a chain
+// run for the invocation, a chain run below it for each other agent that
+// took part, and llm and tool runs for the model and tool calls of each
+// agent. Parents come before their children.
+func (t *Trace) RunTree() []Run {
+	return runTree(t.Records)
+}
+
+func runTree(records []Record) []Run {
+	if len(records) == 0 {
+		return nil
+	}
+	invocationID := records[0].InvocationID
+	// Run IDs are derived from the invocation, so converting a trace again
+	// yields the same runs.
+	runID := func(key string) string {
+		return uuid.NewSHA1(uuid.NameSpaceURL, []byte("kagent:"+invocationID+"/"+key)).String()
+	}
+
+	root := &Run{
+		ID:        runID("invocation"),
+		Name:      records[0].AgentName,
+		RunType:   RunTypeChain,
+		StartTime: records[0].Timestamp,
+		Inputs:    map[string]any{},
+		Extra: map[string]any{"metadata": map[string]any{
+			"invocation_id": invocationID,
+			"session_id":    records[0].SessionID,
+		}},
+	}
+	root.TraceID = root.ID
+	root.DottedOrder = dottedOrder(root.StartTime, root.ID)
+	runs := []*Run{root}
+
+	newRun := func(parent *Run, key, name string, runType RunType, start time.Time) *Run {
+		run := &Run{
+			ID:          runID(key),
+			TraceID:     root.ID,
+			ParentRunID: parent.ID,
+			Name:        name,
+			RunType:     runType,
+			StartTime:   start,
+			Inputs:      map[string]any{},
+		}
+		run.DottedOrder = parent.DottedOrder + "." + dottedOrder(start, run.ID)
+		runs = append(runs, run)
+		return run
+	}
+	agents := map[string]*Run{root.Name: root}
+	agentRun := func(rec Record) *Run {
+		run, ok := agents[rec.AgentName]
+		if !ok {
+			run = newRun(root, "agent/"+rec.AgentName, rec.AgentName, RunTypeChain, rec.Timestamp)
+			agents[rec.AgentName] = run
+		}
+		run.EndTime = rec.Timestamp
+		return run
+	}
+
+	var (
+		modelCalls int
+		// The model calls of an agent are sequential; tool calls may overlap.
+		openModelCalls = map[string]*Run{}
+		openToolCalls  = map[string]*Run{}
+	)
+	for _, rec := range records {
+		parent := agentRun(rec)
+		switch rec.Type {
+		case RecordTypeLLMRequest:
+			modelCalls++
+			name := rec.Model
+			if name == "" {
+				name = "llm"
+			}
+			run := newRun(parent, fmt.Sprintf("llm/%d", modelCalls), name, RunTypeLLM, rec.Timestamp)
+			run.Inputs["messages"] = rec.Contents
+			if rec.Config != nil {
+				run.Inputs["config"] = rec.Config
+			}
+			run.Extra = map[string]any{"metadata": map[string]any{"ls_model_name": rec.Model}}
+			if _, ok := root.Inputs["input"]; !ok {
+				if input := lastUserText(rec.Contents); input != "" {
+					root.Inputs["input"] = input
+				}
+			}
+			openModelCalls[rec.AgentName] = run
+		case RecordTypeLLMResponse:
+			run, ok := openModelCalls[rec.AgentName]
+			if !ok {
+				continue
+			}
+			delete(openModelCalls, rec.AgentName)
+			run.EndTime = rec.Timestamp
+			run.Error = rec.Error
+			run.Outputs = map[string]any{"message": rec.Response}
+			if rec.Usage != nil {
+				run.Outputs["usage_metadata"] = map[string]any{
+					"input_tokens":  rec.Usage.PromptTokenCount,
+					"output_tokens": rec.Usage.CandidatesTokenCount,
+					"total_tokens":  rec.Usage.TotalTokenCount,
+				}
+			}
+			if output := contentText(rec.Response); output != "" {
+				root.Outputs = map[string]any{"output": output}
+			}
+		case RecordTypeToolCall:
+			run := newRun(parent, "tool/"+rec.ToolCallID, rec.ToolName, RunTypeTool, rec.Timestamp)
+			if rec.Args != nil {
+				run.Inputs = rec.Args
+			}
+			run.Extra = map[string]any{"metadata": map[string]any{"tool_call_id": rec.ToolCallID}}
+			openToolCalls[rec.ToolCallID] = run
+		case RecordTypeToolResult:
+			run, ok := openToolCalls[rec.ToolCallID]
+			if !ok {
+				continue
+			}
+			delete(openToolCalls, rec.ToolCallID)
+			run.EndTime = rec.Timestamp
+			run.Error = rec.Error
+			run.Outputs = rec.Result
+		}
+	}
+	root.EndTime = records[len(records)-1].Timestamp
+
+	out := make([]Run, 0, len(runs))
+	for _, run := range runs {
+		if run.EndTime.IsZero() {
+			// A call without a result, e.g. when the invocation stopped
+			// while it ran.
+			run.EndTime = root.EndTime
+			run.Error = "no result recorded"
+		}
+		out = append(out, *run)
+	}
+	return out
+}
+
+// dottedOrder formats one segment of a dotted order: the start time in UTC
+// with microseconds, then the run ID.
+func dottedOrder(start time.Time, id string) string {
+	start = start.UTC()
+	return fmt.Sprintf("%s%06dZ%s", start.Format("20060102T150405"), start.Nanosecond()/1000, id)
+}
+
+// lastUserText returns the text of the last user content.
+func lastUserText(contents []*genai.Content) string {
+	for i := len(contents) - 1; i >= 0; i-- {
+		if contents[i] != nil && contents[i].Role == string(genai.RoleUser) {
+			if text := contentText(contents[i]); text != "" {
+				return text
+			}
+		}
+	}
+	return ""
+}
+
+// contentText concatenates the text parts of c.
+func contentText(c *genai.Content) string {
+	if c == nil {
+		return ""
+	}
+	var b strings.Builder
+	for _, part := range c.Parts {
+		if part != nil && !part.Thought {
+			b.WriteString(part.Text)
+		}
+	}
+	return b.String()
+}
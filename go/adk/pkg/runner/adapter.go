@@ -9,7 +9,12 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/kagent-dev/kagent/go/adk/pkg/agent"
+	"github.com/kagent-dev/kagent/go/adk/pkg/artifacts"
+	"github.com/kagent-dev/kagent/go/adk/pkg/audit"
+	"github.com/kagent-dev/kagent/go/adk/pkg/knowledge"
+	"github.com/kagent-dev/kagent/go/adk/pkg/language"
 	kagentmemory "github.com/kagent-dev/kagent/go/adk/pkg/memory"
+	"github.com/kagent-dev/kagent/go/adk/pkg/recorder"
 	"github.com/kagent-dev/kagent/go/adk/pkg/session"
 	"github.com/kagent-dev/kagent/go/adk/pkg/sts"
 	"github.com/kagent-dev/kagent/go/adk/pkg/tools"
@@ -29,7 +34,10 @@ func agentNameFromAppName(appName string) string {
 }
 
 // CreateRunnerConfig builds a runner.Config and subagent session IDs for A2A
-// stamping (from remote agent wiring in the agent builder).
+// stamping (from remote agent wiring in the agent builder). toolsets are
+// added to the agent's MCP servers, e.g. the servers registered at runtime.
+// plugins are registered after kagent's own, e.g. the model and tool hooks
+// returned by app.ADKPlugins.
 func CreateRunnerConfig(
 	ctx context.Context,
 	agentConfig *adk.AgentConfig,
@@ -38,6 +46,8 @@ func CreateRunnerConfig(
 	memoryService *kagentmemory.KagentMemoryService,
 	kagentURL string,
 	httpClient *http.Client,
+	toolsets []adktool.Toolset,
+	plugins ...*adkplugin.Plugin,
 ) (runner.Config, map[string]string, error) {
 	log := logr.FromContextOrDiscard(ctx)
 
@@ -67,12 +77,87 @@ func CreateRunnerConfig(
 		log.Info("Share link tools enabled")
 	}
 
+	if agentConfig.Knowledge != nil && kagentURL != "" && httpClient != nil {
+		knowledgeClient, err := knowledge.New(knowledge.Config{
+			APIURL:          kagentURL,
+			HTTPClient:      httpClient,
+			EmbeddingConfig: agentConfig.Knowledge.Embedding,
+		})
+		if err != nil {
+			return runner.Config{}, nil, fmt.Errorf("failed to create knowledge client: %w", err)
+		}
+		retrieveTool, err := knowledge.NewRetrieveDocsTool(knowledgeClient, agentConfig.Knowledge.Collections, agentConfig.Knowledge.TopK)
+		if err != nil {
+			return runner.Config{}, nil, fmt.Errorf("failed to create retrieve_docs tool: %w", err)
+		}
+		extraTools = append(extraTools, retrieveTool)
+		log.Info("Knowledge retrieval enabled", "collections", agentConfig.Knowledge.Collections)
+	}
+
 	stsPlugin, err := buildTokenPropagationPlugin(ctx, log)
 	if err != nil {
 		return runner.Config{}, nil, err
 	}
 
-	adkAgent, subagentSessionIDs, err := agent.CreateGoogleADKAgentWithSubagentSessionIDs(ctx, agentConfig, agentNameFromAppName(appName), stsPlugin, extraTools...)
+	var adkPlugins []*adkplugin.Plugin
+	if stsPlugin != nil {
+		p, err := stsPlugin.ADKPlugin()
+		if err != nil {
+			return runner.Config{}, nil, fmt.Errorf("failed to create STS ADK plugin: %w", err)
+		}
+		if p != nil {
+			adkPlugins = append(adkPlugins, p)
+		}
+	}
+
+	auditLog, err := audit.NewFromEnv(log)
+	if err != nil {
+		return runner.Config{}, nil, fmt.Errorf("failed to create audit log: %w", err)
+	}
+	if auditLog != nil {
+		p, err := auditLog.ADKPlugin()
+		if err != nil {
+			return runner.Config{}, nil, fmt.Errorf("failed to create audit log ADK plugin: %w", err)
+		}
+		adkPlugins = append(adkPlugins, p)
+		log.Info("Writing tool audit log", "path", os.Getenv(audit.EnvAuditLog))
+	}
+
+	traceRecorder, err := recorder.NewFromEnv(log)
+	if err != nil {
+		return runner.Config{}, nil, fmt.Errorf("failed to create trace recorder: %w", err)
+	}
+	if traceRecorder != nil {
+		p, err := traceRecorder.ADKPlugin()
+		if err != nil {
+			return runner.Config{}, nil, fmt.Errorf("failed to create trace recorder ADK plugin: %w", err)
+		}
+		adkPlugins = append(adkPlugins, p)
+		log.Info("Recording conversation traces", "dir", os.Getenv(recorder.EnvTraceDir))
+	}
+
+	languageMiddleware, err := language.NewFromEnv(log, func() (language.Translator, error) {
+		llm, err := agent.CreateLLM(ctx, agentConfig.Model, log)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create translation model: %w", err)
+		}
+		return &language.ModelTranslator{Model: llm}, nil
+	})
+	if err != nil {
+		return runner.Config{}, nil, fmt.Errorf("failed to create language middleware: %w", err)
+	}
+	if languageMiddleware != nil {
+		p, err := languageMiddleware.ADKPlugin()
+		if err != nil {
+			return runner.Config{}, nil, fmt.Errorf("failed to create language ADK plugin: %w", err)
+		}
+		adkPlugins = append(adkPlugins, p)
+		log.Info("Language detection enabled", "translateToolOutputs", os.Getenv(language.EnvTranslateToolOutputs), "translateTools", os.Getenv(language.EnvTranslateTools))
+	}
+
+	adkPlugins = append(adkPlugins, plugins...)
+
+	adkAgent, subagentSessionIDs, err := agent.CreateGoogleADKAgentWithSubagentSessionIDs(ctx, agentConfig, agentNameFromAppName(appName), stsPlugin, adkPlugins, toolsets, extraTools...)
 	if err != nil {
 		return runner.Config{}, nil, fmt.Errorf("failed to create agent: %w", err)
 	}
@@ -93,15 +178,9 @@ func CreateRunnerConfig(
 		runnerMemory = memoryService
 	}
 
-	var adkPlugins []*adkplugin.Plugin
-	if stsPlugin != nil {
-		p, err := stsPlugin.ADKPlugin()
-		if err != nil {
-			return runner.Config{}, nil, fmt.Errorf("failed to create STS ADK plugin: %w", err)
-		}
-		if p != nil {
-			adkPlugins = append(adkPlugins, p)
-		}
+	artifactService, err := artifacts.NewServiceFromEnv()
+	if err != nil {
+		return runner.Config{}, nil, fmt.Errorf("failed to create artifact service: %w", err)
 	}
 
 	cfg := runner.Config{
@@ -109,6 +188,10 @@ func CreateRunnerConfig(
 		Agent:          adkAgent,
 		SessionService: adkSessionService,
 		MemoryService:  runnerMemory,
+		// Artifacts saved by tools are emitted to A2A clients as artifact
+		// updates by the executor. The store is capped by
+		// KAGENT_ARTIFACT_MAX_MB, evicting the least recently used sessions.
+		ArtifactService: artifactService,
 		PluginConfig: runner.PluginConfig{
 			Plugins: adkPlugins,
 		},
@@ -0,0 +1,349 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Environment variables tuning the session client.
+const (
+	// EnvSessionMaxRetries is how many times a request failing with a
+	// transient error is retried. Defaults to 3; 0 disables retries.
+	EnvSessionMaxRetries = "KAGENT_SESSION_MAX_RETRIES"
+	// EnvSessionCacheTTL is how long a fetched session is served from
+	// memory, as a Go duration. Defaults to 2s; 0 disables the cache.
+	EnvSessionCacheTTL = "KAGENT_SESSION_CACHE_TTL"
+	// EnvSessionBreakerThreshold is the number of consecutive failures
+	// after which requests fail fast. Defaults to 5; 0 disables the
+	// circuit breaker.
+	EnvSessionBreakerThreshold = "KAGENT_SESSION_BREAKER_THRESHOLD"
+	// EnvSessionBreakerCooldown is how long requests fail fast before one
+	// is let through to probe the control plane, as a Go duration.
+	// Defaults to 30s.
+	EnvSessionBreakerCooldown = "KAGENT_SESSION_BREAKER_COOLDOWN"
+)
+
+const maxRetryBackoff = 5 * time.Second
+
+// ErrCircuitOpen is returned without contacting the control plane after
+// repeated failures, until the breaker cooldown has passed.
+var ErrCircuitOpen = errors.New("session service unavailable: circuit breaker is open")
+
+// ClientOptions configures how KAgentSessionService talks to the control
+// plane.
+type ClientOptions struct {
+	// MaxRetries is how many times a transient failure is retried.
+	MaxRetries int
+	// RetryBackoff is the delay before the first retry. It doubles with
+	// each retry, up to 5s, with jitter.
+	RetryBackoff time.Duration
+	// CacheTTL is how long a fetched session is reused. Zero disables the
+	// cache.
+	CacheTTL time.Duration
+	// BreakerThreshold is the number of consecutive failures that opens the
+	// circuit breaker. Zero disables it.
+	BreakerThreshold int
+	// BreakerCooldown is how long the breaker stays open.
+	BreakerCooldown time.Duration
+}
+
+// DefaultClientOptions returns the options used by NewKAgentSessionService.
+func DefaultClientOptions() ClientOptions {
+	return ClientOptions{
+		MaxRetries:       3,
+		RetryBackoff:     200 * time.Millisecond,
+		CacheTTL:         2 * time.Second,
+		BreakerThreshold: 5,
+		BreakerCooldown:  30 * time.Second,
+	}
+}
+
+// ClientOptionsFromEnv returns DefaultClientOptions overridden by the
+// KAGENT_SESSION_* environment variables.
+func ClientOptionsFromEnv() (ClientOptions, error) {
+	opts := DefaultClientOptions()
+	var err error
+	if opts.MaxRetries, err = intFromEnv(EnvSessionMaxRetries, opts.MaxRetries); err != nil {
+		return ClientOptions{}, err
+	}
+	if opts.CacheTTL, err = durationFromEnv(EnvSessionCacheTTL, opts.CacheTTL, true); err != nil {
+		return ClientOptions{}, err
+	}
+	if opts.BreakerThreshold, err = intFromEnv(EnvSessionBreakerThreshold, opts.BreakerThreshold); err != nil {
+		return ClientOptions{}, err
+	}
+	if opts.BreakerCooldown, err = durationFromEnv(EnvSessionBreakerCooldown, opts.BreakerCooldown, false); err != nil {
+		return ClientOptions{}, err
+	}
+	return opts, nil
+}
+
+func intFromEnv(name string, def int) (int, error) {
+	v := strings.TrimSpace(os.Getenv(name))
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid %s %q: must be a non-negative integer", name, v)
+	}
+	return n, nil
+}
+
+func durationFromEnv(name string, def time.Duration, allowZero bool) (time.Duration, error) {
+	v := strings.TrimSpace(os.Getenv(name))
+	if v == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 || (d == 0 && !allowZero) {
+		return 0, fmt.Errorf("invalid %s %q: must be a positive duration", name, v)
+	}
+	return d, nil
+}
+
+// do sends req, retrying transient failures with exponential backoff, and
+// fails fast with ErrCircuitOpen while the control plane is down. The body
+// of a retried request is rebuilt with req.GetBody.
+func (s *KAgentSessionService) do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	for attempt := 0; ; attempt++ {
+		if err := s.breaker.allow(); err != nil {
+			return nil, err
+		}
+		r := req
+		if attempt > 0 {
+			r = req.Clone(ctx)
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				r.Body = body
+			}
+		}
+
+		resp, err := s.Client.Do(r)
+		switch {
+		case err != nil && ctx.Err() != nil:
+			s.breaker.release()
+			return nil, err
+		case err != nil || resp.StatusCode >= http.StatusInternalServerError:
+			s.breaker.failure()
+		default:
+			s.breaker.success()
+		}
+
+		if attempt >= s.options.MaxRetries || !retryable(req.Method, resp, err) {
+			return resp, err
+		}
+		delay := retryDelay(s.options.RetryBackoff, attempt, resp)
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+			resp.Body.Close()
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// retryable reports whether a failed request may be sent again. Rate
+// limiting and gateway errors are retried; transport errors are retried for
+// idempotent requests, and for POSTs only when the connection was never
+// established, so an event is not appended twice.
+func retryable(method string, resp *http.Response, err error) bool {
+	if err != nil {
+		var opErr *net.OpError
+		if errors.As(err, &opErr) && opErr.Op == "dial" {
+			return true
+		}
+		return method != http.MethodPost
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryDelay returns the backoff before retry attempt+1: base doubled per
+// attempt with full jitter in its upper half, or the server's Retry-After
+// when that is longer, capped at maxRetryBackoff.
+func retryDelay(base time.Duration, attempt int, resp *http.Response) time.Duration {
+	d := min(base<<attempt, maxRetryBackoff)
+	if d > 0 {
+		d = d/2 + rand.N(d/2+1)
+	}
+	if resp != nil {
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+			d = max(d, time.Duration(secs)*time.Second)
+		}
+	}
+	return min(d, maxRetryBackoff)
+}
+
+// circuitBreaker opens after threshold consecutive failures. While open,
+// requests fail with ErrCircuitOpen; after cooldown a single probe is let
+// through, and its outcome closes or reopens the breaker. A nil breaker
+// allows everything.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	if threshold <= 0 {
+		return nil
+	}
+	return &circuitBreaker{threshold: threshold, cooldown: cooldown, now: time.Now}
+}
+
+func (b *circuitBreaker) allow() error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < b.threshold {
+		return nil
+	}
+	if b.probing || b.now().Sub(b.openedAt) < b.cooldown {
+		return ErrCircuitOpen
+	}
+	b.probing = true
+	return nil
+}
+
+func (b *circuitBreaker) success() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.probing = false
+}
+
+func (b *circuitBreaker) failure() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	b.probing = false
+	if b.failures >= b.threshold {
+		b.openedAt = b.now()
+	}
+}
+
+// release ends a probe whose outcome says nothing about the control plane,
+// such as a request canceled by its caller.
+func (b *circuitBreaker) release() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+// sessionCache keeps recently fetched sessions for a short time so that
+// the several lookups of one request do not each hit the control plane.
+// Entries are copies; callers get their own copy to mutate. A nil cache
+// stores nothing.
+type sessionCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cachedSession
+	// version changes on every invalidation, so a fetch that raced with a
+	// write is not cached.
+	version uint64
+}
+
+type cachedSession struct {
+	session *localSession
+	expires time.Time
+}
+
+func newSessionCache(ttl time.Duration) *sessionCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &sessionCache{ttl: ttl, now: time.Now, entries: map[string]cachedSession{}}
+}
+
+// snapshot returns the current version, to be passed to put.
+func (c *sessionCache) snapshot() uint64 {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.version
+}
+
+func (c *sessionCache) get(appName, userID, sessionID string) *localSession {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[sessionID]
+	if !ok || c.now().After(entry.expires) || entry.session.appName != appName || entry.session.userID != userID {
+		return nil
+	}
+	return entry.session.clone()
+}
+
+// put caches a copy of sess unless the cache was invalidated since version
+// was taken.
+func (c *sessionCache) put(version uint64, sess *localSession) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.version != version {
+		return
+	}
+	now := c.now()
+	for id, entry := range c.entries {
+		if now.After(entry.expires) {
+			delete(c.entries, id)
+		}
+	}
+	c.entries[sess.sessionID] = cachedSession{session: sess.clone(), expires: now.Add(c.ttl)}
+}
+
+func (c *sessionCache) invalidate(sessionID string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.version++
+	delete(c.entries, sessionID)
+}
@@ -0,0 +1,157 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	adksession "google.golang.org/adk/session"
+)
+
+const getSessionBody = `{"data":{"session":{"id":"sess-1","user_id":"u"},"events":[{"data":"{\"id\":\"e1\",\"author\":\"user\"}"}]}}`
+
+func TestDo_RetriesTransientFailures(t *testing.T) {
+	var calls atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/sessions/sess-1", func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(getSessionBody))
+	})
+
+	svc := newService(t, mux)
+	sess, err := svc.GetSession(context.Background(), "app", "u", "sess-1")
+	if err != nil {
+		t.Fatalf("GetSession() error = %v", err)
+	}
+	if sess == nil || sess.Events().Len() != 1 {
+		t.Fatalf("GetSession() = %v, want the session with one event", sess)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("requests = %d, want 3", got)
+	}
+}
+
+func TestDo_DoesNotRetryClientErrors(t *testing.T) {
+	var calls atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/sessions/sess-1/events", func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		http.Error(w, "bad event", http.StatusBadRequest)
+	})
+
+	svc := newService(t, mux)
+	ls := &localSession{appName: "app", userID: "u", sessionID: "sess-1", state: map[string]any{}}
+	if err := svc.AppendEvent(context.Background(), ls, &adksession.Event{ID: "e1", Author: "agent"}); err == nil {
+		t.Fatal("AppendEvent() error = nil, want the 400")
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+}
+
+func TestDo_CircuitBreaker(t *testing.T) {
+	var calls atomic.Int32
+	var healthy atomic.Bool
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/sessions/sess-1", func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if !healthy.Load() {
+			http.Error(w, "down", http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(getSessionBody))
+	})
+
+	svc := newService(t, mux)
+	now := time.Now()
+	svc.breaker.now = func() time.Time { return now }
+	svc.cache = nil
+
+	for range svc.options.BreakerThreshold {
+		if _, err := svc.GetSession(context.Background(), "app", "u", "sess-1"); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("GetSession() error = %v, want the backend error", err)
+		}
+	}
+	before := calls.Load()
+	if _, err := svc.GetSession(context.Background(), "app", "u", "sess-1"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("GetSession() error = %v, want ErrCircuitOpen", err)
+	}
+	if calls.Load() != before {
+		t.Error("an open breaker let a request through")
+	}
+
+	// After the cooldown a probe is let through and closes the breaker.
+	healthy.Store(true)
+	now = now.Add(svc.options.BreakerCooldown)
+	if _, err := svc.GetSession(context.Background(), "app", "u", "sess-1"); err != nil {
+		t.Fatalf("GetSession() after cooldown error = %v", err)
+	}
+	if _, err := svc.GetSession(context.Background(), "app", "u", "sess-1"); err != nil {
+		t.Fatalf("GetSession() after probe error = %v", err)
+	}
+}
+
+func TestGet_CachesUntilWrite(t *testing.T) {
+	var gets atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/sessions/sess-1", func(w http.ResponseWriter, r *http.Request) {
+		gets.Add(1)
+		w.Write([]byte(getSessionBody))
+	})
+	mux.HandleFunc("/api/sessions/sess-1/events", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	svc := newService(t, mux)
+	first, err := svc.GetSession(context.Background(), "app", "u", "sess-1")
+	if err != nil {
+		t.Fatalf("GetSession() error = %v", err)
+	}
+	second, err := svc.GetSession(context.Background(), "app", "u", "sess-1")
+	if err != nil {
+		t.Fatalf("GetSession() error = %v", err)
+	}
+	if got := gets.Load(); got != 1 {
+		t.Errorf("requests = %d, want the second lookup served from cache", got)
+	}
+	if first == second {
+		t.Error("cached lookups returned the same session object")
+	}
+
+	if err := svc.AppendEvent(context.Background(), first, &adksession.Event{ID: "e2", Author: "agent"}); err != nil {
+		t.Fatalf("AppendEvent() error = %v", err)
+	}
+	if second.Events().Len() != 1 {
+		t.Error("appending to one cached copy changed another")
+	}
+	if _, err := svc.GetSession(context.Background(), "app", "u", "sess-1"); err != nil {
+		t.Fatalf("GetSession() error = %v", err)
+	}
+	if got := gets.Load(); got != 2 {
+		t.Errorf("requests = %d, want AppendEvent to invalidate the cache", got)
+	}
+}
+
+func TestClientOptionsFromEnv(t *testing.T) {
+	t.Setenv(EnvSessionMaxRetries, "0")
+	t.Setenv(EnvSessionCacheTTL, "0")
+	t.Setenv(EnvSessionBreakerCooldown, "1m")
+	opts, err := ClientOptionsFromEnv()
+	if err != nil {
+		t.Fatalf("ClientOptionsFromEnv() error = %v", err)
+	}
+	if opts.MaxRetries != 0 || opts.CacheTTL != 0 || opts.BreakerCooldown != time.Minute || opts.BreakerThreshold != 5 {
+		t.Errorf("ClientOptionsFromEnv() = %+v", opts)
+	}
+
+	t.Setenv(EnvSessionBreakerCooldown, "0")
+	if _, err := ClientOptionsFromEnv(); err == nil {
+		t.Error("ClientOptionsFromEnv() accepted a zero cooldown")
+	}
+}
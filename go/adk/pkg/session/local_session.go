@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"iter"
 	"maps"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -45,6 +46,21 @@ func (s *localSession) LastUpdateTime() time.Time {
 	return s.updatedAt
 }
 
+// clone returns a copy of the session that can be mutated independently.
+// Events are shared; they are not modified once appended.
+func (s *localSession) clone() *localSession {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return &localSession{
+		appName:   s.appName,
+		userID:    s.userID,
+		sessionID: s.sessionID,
+		events:    slices.Clone(s.events),
+		state:     maps.Clone(s.state),
+		updatedAt: s.updatedAt,
+	}
+}
+
 func (s *localSession) appendEvent(event *adksession.Event) error {
 	if event == nil || event.Partial {
 		return nil
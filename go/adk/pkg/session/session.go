@@ -9,6 +9,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -23,18 +24,71 @@ const (
 // ErrSessionNotFound indicates the requested persisted session does not exist.
 var ErrSessionNotFound = errors.New("session not found")
 
+// AccessError reports a session that exists but belongs to another agent
+// than the one requesting it. Sessions of other users are not found at
+// all, since the KAgent backend looks them up by user.
+type AccessError struct {
+	SessionID string
+	// AppName identifies the requester.
+	AppName string
+	// OwnerAgentID identifies the session's agent, as stored by the KAgent
+	// backend.
+	OwnerAgentID string
+}
+
+func (e *AccessError) Error() string {
+	return fmt.Sprintf("session %s belongs to agent %s, not %s", e.SessionID, e.OwnerAgentID, e.AppName)
+}
+
+// agentID converts an app name to the agent ID the backend stores for the
+// sessions it creates with that agent_ref.
+func agentID(appName string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(appName, "-", "_"), "/", "__NS__")
+}
+
+// checkOwner returns an *AccessError unless the session belongs to the
+// requesting app. An empty owner agent is not checked.
+func checkOwner(req *adksession.GetRequest, ownerAgentID string) error {
+	if ownerAgentID == "" || req.AppName == "" || ownerAgentID == agentID(req.AppName) {
+		return nil
+	}
+	return &AccessError{
+		SessionID:    req.SessionID,
+		AppName:      req.AppName,
+		OwnerAgentID: ownerAgentID,
+	}
+}
+
 type KAgentSessionService struct {
 	BaseURL string
 	Client  *http.Client
+
+	options ClientOptions
+	breaker *circuitBreaker
+	cache   *sessionCache
 }
 
-// NewKAgentSessionService creates a new KAgentSessionService.
+// NewKAgentSessionService creates a new KAgentSessionService with
+// DefaultClientOptions.
 // If client is nil, http.DefaultClient is used.
 func NewKAgentSessionService(baseURL string, client *http.Client) *KAgentSessionService {
+	return NewKAgentSessionServiceWithOptions(baseURL, client, DefaultClientOptions())
+}
+
+// NewKAgentSessionServiceWithOptions creates a new KAgentSessionService that
+// retries, caches and breaks the circuit as configured by opts.
+// If client is nil, http.DefaultClient is used.
+func NewKAgentSessionServiceWithOptions(baseURL string, client *http.Client, opts ClientOptions) *KAgentSessionService {
 	if client == nil {
 		client = http.DefaultClient
 	}
-	return &KAgentSessionService{BaseURL: baseURL, Client: client}
+	return &KAgentSessionService{
+		BaseURL: baseURL,
+		Client:  client,
+		options: opts,
+		breaker: newCircuitBreaker(opts.BreakerThreshold, opts.BreakerCooldown),
+		cache:   newSessionCache(opts.CacheTTL),
+	}
 }
 
 // Create implements adksession.Service.
@@ -73,7 +127,7 @@ func (s *KAgentSessionService) Create(ctx context.Context, req *adksession.Creat
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.Header.Set("X-User-ID", req.UserID)
 
-	resp, err := s.Client.Do(httpReq)
+	resp, err := s.do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute create session request: %w", err)
 	}
@@ -94,6 +148,7 @@ func (s *KAgentSessionService) Create(ctx context.Context, req *adksession.Creat
 		return nil, fmt.Errorf("failed to decode create session response: %w", err)
 	}
 
+	s.cache.invalidate(result.Data.ID)
 	log.V(1).Info("Session created", "sessionID", result.Data.ID)
 	return &adksession.CreateResponse{
 		Session: &localSession{
@@ -109,9 +164,16 @@ func (s *KAgentSessionService) Create(ctx context.Context, req *adksession.Creat
 // Fetches the session and its events from the KAgent API, deserialising each
 // raw event payload into a typed *adksession.Event — mirroring Python's
 // KAgentSessionService.get_session() which calls Event.model_validate_json().
+// Sessions fetched within the cache TTL are served from memory; writes made
+// through this service invalidate them.
 func (s *KAgentSessionService) Get(ctx context.Context, req *adksession.GetRequest) (*adksession.GetResponse, error) {
 	log := logr.FromContextOrDiscard(ctx)
+	if cached := s.cache.get(req.AppName, req.UserID, req.SessionID); cached != nil {
+		log.V(1).Info("Session served from cache", "sessionID", req.SessionID)
+		return &adksession.GetResponse{Session: cached}, nil
+	}
 	log.V(1).Info("Getting session", "appName", req.AppName, "userID", req.UserID, "sessionID", req.SessionID)
+	version := s.cache.snapshot()
 
 	url := fmt.Sprintf("%s/api/sessions/%s?user_id=%s&limit=-1&order=asc", s.BaseURL, url.PathEscape(req.SessionID), url.QueryEscape(req.UserID))
 	httpReq, err := http.NewRequestWithContext(ctx, "GET", url, nil)
@@ -120,7 +182,7 @@ func (s *KAgentSessionService) Get(ctx context.Context, req *adksession.GetReque
 	}
 	httpReq.Header.Set("X-User-ID", req.UserID)
 
-	resp, err := s.Client.Do(httpReq)
+	resp, err := s.do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute get session request: %w", err)
 	}
@@ -137,8 +199,9 @@ func (s *KAgentSessionService) Get(ctx context.Context, req *adksession.GetReque
 	var result struct {
 		Data struct {
 			Session struct {
-				ID     string `json:"id"`
-				UserID string `json:"user_id"`
+				ID      string `json:"id"`
+				UserID  string `json:"user_id"`
+				AgentID string `json:"agent_id"`
 			} `json:"session"`
 			Events []struct {
 				Data json.RawMessage `json:"data"`
@@ -148,6 +211,9 @@ func (s *KAgentSessionService) Get(ctx context.Context, req *adksession.GetReque
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return nil, fmt.Errorf("failed to decode get session response: %w", err)
 	}
+	if err := checkOwner(req, result.Data.Session.AgentID); err != nil {
+		return nil, err
+	}
 
 	log.V(1).Info("Session retrieved", "sessionID", result.Data.Session.ID, "eventsCount", len(result.Data.Events))
 
@@ -170,15 +236,15 @@ func (s *KAgentSessionService) Get(ctx context.Context, req *adksession.GetReque
 		adkEvents = append(adkEvents, e)
 	}
 
-	return &adksession.GetResponse{
-		Session: &localSession{
-			appName:   req.AppName,
-			userID:    result.Data.Session.UserID,
-			sessionID: result.Data.Session.ID,
-			events:    adkEvents,
-			state:     make(map[string]any),
-		},
-	}, nil
+	sess := &localSession{
+		appName:   req.AppName,
+		userID:    result.Data.Session.UserID,
+		sessionID: result.Data.Session.ID,
+		events:    adkEvents,
+		state:     make(map[string]any),
+	}
+	s.cache.put(version, sess)
+	return &adksession.GetResponse{Session: sess}, nil
 }
 
 // List implements adksession.Service.
@@ -189,6 +255,7 @@ func (s *KAgentSessionService) List(_ context.Context, _ *adksession.ListRequest
 // Delete implements adksession.Service.
 func (s *KAgentSessionService) Delete(ctx context.Context, req *adksession.DeleteRequest) error {
 	log := logr.FromContextOrDiscard(ctx)
+	defer s.cache.invalidate(req.SessionID)
 	url := fmt.Sprintf("%s/api/sessions/%s?user_id=%s", s.BaseURL, url.PathEscape(req.SessionID), url.QueryEscape(req.UserID))
 	httpReq, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
 	if err != nil {
@@ -196,7 +263,7 @@ func (s *KAgentSessionService) Delete(ctx context.Context, req *adksession.Delet
 	}
 	httpReq.Header.Set("X-User-ID", req.UserID)
 
-	resp, err := s.Client.Do(httpReq)
+	resp, err := s.do(httpReq)
 	if err != nil {
 		return fmt.Errorf("failed to execute delete session request: %w", err)
 	}
@@ -210,6 +277,43 @@ func (s *KAgentSessionService) Delete(ctx context.Context, req *adksession.Delet
 	return nil
 }
 
+// SupersedeEvents hides the event eventID and every later event of the
+// session so the conversation can be regenerated from an edited message. The
+// backend soft-deletes the events; the IDs of the superseded events are
+// returned.
+func (s *KAgentSessionService) SupersedeEvents(ctx context.Context, userID, sessionID, eventID string) ([]string, error) {
+	log := logr.FromContextOrDiscard(ctx)
+	defer s.cache.invalidate(sessionID)
+	url := fmt.Sprintf("%s/api/sessions/%s/events/%s/supersede?user_id=%s", s.BaseURL, url.PathEscape(sessionID), url.PathEscape(eventID), url.QueryEscape(userID))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build supersede events request: %w", err)
+	}
+	httpReq.Header.Set("X-User-ID", userID)
+
+	resp, err := s.do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute supersede events request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("supersede events: status %d, body: %s", resp.StatusCode, string(b))
+	}
+
+	var result struct {
+		Data struct {
+			SupersededEventIDs []string `json:"superseded_event_ids"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode supersede events response: %w", err)
+	}
+	log.V(1).Info("Events superseded", "sessionID", sessionID, "eventID", eventID, "count", len(result.Data.SupersededEventIDs))
+	return result.Data.SupersededEventIDs, nil
+}
+
 // AppendEvent implements adksession.Service.
 // Persists the event to the KAgent backend (mirroring Python's append_event
 // which POSTs event.model_dump_json()), then updates the in-memory localSession
@@ -221,6 +325,7 @@ func (s *KAgentSessionService) AppendEvent(ctx context.Context, adkSess adksessi
 	}
 
 	log := logr.FromContextOrDiscard(ctx)
+	defer s.cache.invalidate(adkSess.ID())
 
 	// Use a detached context so a client disconnect does not cancel the write.
 	persistCtx, cancel := context.WithTimeout(context.Background(), eventPersistTimeout)
@@ -253,7 +358,7 @@ func (s *KAgentSessionService) AppendEvent(ctx context.Context, adkSess adksessi
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.Header.Set("X-User-ID", adkSess.UserID())
 
-	resp, err := s.Client.Do(httpReq)
+	resp, err := s.do(httpReq)
 	if err != nil {
 		return fmt.Errorf("failed to execute append event request: %w", err)
 	}
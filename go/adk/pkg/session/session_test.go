@@ -3,9 +3,11 @@ package session
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	adksession "google.golang.org/adk/session"
 )
@@ -23,7 +25,9 @@ func newService(t *testing.T, mux *http.ServeMux) *KAgentSessionService {
 	t.Helper()
 	srv := httptest.NewServer(mux)
 	t.Cleanup(srv.Close)
-	return NewKAgentSessionService(srv.URL, srv.Client())
+	opts := DefaultClientOptions()
+	opts.RetryBackoff = time.Millisecond
+	return NewKAgentSessionServiceWithOptions(srv.URL, srv.Client(), opts)
 }
 
 func TestCreate_Success(t *testing.T) {
@@ -145,6 +149,34 @@ func TestGet_EmptyEventsSkipped(t *testing.T) {
 	}
 }
 
+func TestGet_ChecksOwner(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/sessions/sess-4", func(w http.ResponseWriter, r *http.Request) {
+		body := map[string]any{
+			"data": map[string]any{
+				"session": map[string]any{"id": "sess-4", "user_id": "u", "agent_id": "kagent__NS__k8s_agent"},
+				"events":  []any{},
+			},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(mustJSON(t, body))
+	})
+	svc := newService(t, mux)
+
+	if _, err := svc.Get(context.Background(), &adksession.GetRequest{
+		AppName: "kagent__NS__k8s-agent", UserID: "u", SessionID: "sess-4",
+	}); err != nil {
+		t.Fatalf("Get() by the owner error = %v", err)
+	}
+	_, err := svc.Get(context.Background(), &adksession.GetRequest{
+		AppName: "kagent__NS__helm_agent", UserID: "u", SessionID: "sess-4",
+	})
+	var accessErr *AccessError
+	if !errors.As(err, &accessErr) {
+		t.Errorf("Get() by another agent error = %v, want an *AccessError", err)
+	}
+}
+
 func TestGet_NotFound(t *testing.T) {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/api/sessions/missing", func(w http.ResponseWriter, r *http.Request) {
@@ -213,6 +245,31 @@ func TestDelete_Success(t *testing.T) {
 	}
 }
 
+func TestSupersedeEvents(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/sessions/sess-1/events/evt-2/supersede", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Query().Get("user_id") != "u" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"superseded_event_ids":["evt-2","evt-3"]}}`))
+	})
+
+	svc := newService(t, mux)
+	ids, err := svc.SupersedeEvents(context.Background(), "u", "sess-1", "evt-2")
+	if err != nil {
+		t.Fatalf("SupersedeEvents() error = %v", err)
+	}
+	if len(ids) != 2 || ids[0] != "evt-2" || ids[1] != "evt-3" {
+		t.Errorf("SupersedeEvents() = %v, want [evt-2 evt-3]", ids)
+	}
+
+	if _, err := svc.SupersedeEvents(context.Background(), "u", "sess-1", "missing"); err == nil {
+		t.Error("SupersedeEvents() expected error for unknown event")
+	}
+}
+
 func TestAppendEvent_PersistsAndUpdatesLocalSession(t *testing.T) {
 	var gotBody map[string]any
 	mux := http.NewServeMux()
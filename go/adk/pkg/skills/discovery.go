@@ -133,11 +133,16 @@ func GenerateSkillsToolDescription(skills []Skill) string {
 
 // GetSessionPath returns the working directory path for a session
 func GetSessionPath(sessionID, skillsDirectory string) (string, error) {
+	return prepareSessionDir(WorkspaceBaseDir(), sessionID, skillsDirectory)
+}
+
+// prepareSessionDir creates the working directory of a session under
+// basePath with uploads/, outputs/ and a symlink to the skills directory.
+func prepareSessionDir(basePath, sessionID, skillsDirectory string) (string, error) {
 	if sessionID == "" {
 		return "", fmt.Errorf("sessionID cannot be empty")
 	}
 
-	basePath := filepath.Join(os.TempDir(), "kagent")
 	sessionPath := filepath.Clean(filepath.Join(basePath, sessionID))
 
 	// Validate the resolved path stays under basePath to prevent path traversal
@@ -386,6 +386,22 @@ func TestGetSessionPath(t *testing.T) {
 	}
 }
 
+func TestGetSessionPath_WorkspaceDir(t *testing.T) {
+	workspaceDir := filepath.Join(t.TempDir(), "shared")
+	t.Setenv(EnvWorkspaceDir, workspaceDir)
+
+	sessionPath, err := GetSessionPath("session-1", t.TempDir())
+	if err != nil {
+		t.Fatalf("GetSessionPath() error = %v", err)
+	}
+	if want := filepath.Join(workspaceDir, "session-1"); sessionPath != want {
+		t.Errorf("GetSessionPath() = %q, want %q", sessionPath, want)
+	}
+	if _, err := GetSessionPath("../escape", t.TempDir()); err == nil {
+		t.Error("GetSessionPath() should reject path traversal outside the workspace dir")
+	}
+}
+
 func TestGenerateSkillsToolDescription(t *testing.T) {
 	skills := []Skill{
 		{Name: "skill1", Description: "First skill"},
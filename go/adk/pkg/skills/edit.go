@@ -0,0 +1,340 @@
+package skills
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// FuzzyMatchThreshold is the minimum confidence, between 0 and 1, of a fuzzy
+// match. Confidence is the average similarity of the matched lines after
+// collapsing whitespace.
+const FuzzyMatchThreshold = 0.9
+
+// Edit is one string replacement in a file.
+type Edit struct {
+	OldString  string `json:"old_string"`
+	NewString  string `json:"new_string"`
+	ReplaceAll bool   `json:"replace_all,omitempty"`
+}
+
+// EditMatch tells where an edit or diff hunk was applied.
+type EditMatch struct {
+	// Line is the 1-based line the replaced text started at.
+	Line int
+	// Fuzzy is set when the text was found by fuzzy matching.
+	Fuzzy bool
+	// Confidence is the similarity of a fuzzy match, or 1.
+	Confidence float64
+}
+
+var hunkHeaderRe = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// MultiEditFileContent applies edits to a file in order, each to the result
+// of the previous one. The file is written only if every edit applies, so a
+// failing edit leaves it unchanged.
+func MultiEditFileContent(path string, edits []Edit) ([]EditMatch, error) {
+	if len(edits) == 0 {
+		return nil, errors.New("no edits provided")
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := string(content)
+	matches := make([]EditMatch, 0, len(edits))
+	for i, edit := range edits {
+		var match EditMatch
+		updated, match, err = applyEdit(updated, edit)
+		if err != nil {
+			if len(edits) == 1 {
+				return nil, err
+			}
+			return nil, fmt.Errorf("edit %d: %w; no edits were applied", i+1, err)
+		}
+		matches = append(matches, match)
+	}
+	return matches, os.WriteFile(path, []byte(updated), 0644)
+}
+
+// ApplyDiffToFile applies a unified diff of a single file. Hunks are located
+// by their removed and context lines, preferring the match closest to the
+// line in the hunk header, and fall back to fuzzy matching. The file is
+// written only if every hunk applies.
+func ApplyDiffToFile(path, diff string) ([]EditMatch, error) {
+	hunks, err := parseUnifiedDiff(diff)
+	if err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(string(content), "\n")
+	matches := make([]EditMatch, 0, len(hunks))
+	offset := 0
+	for i, h := range hunks {
+		hint := max(h.oldStart-1+offset, 0)
+		start, match, err := locateLines(lines, h.oldLines, hint)
+		if err != nil {
+			return nil, fmt.Errorf("hunk %d (%s): %w; no hunks were applied", i+1, h.header, err)
+		}
+		lines = replaceLines(lines, start, len(h.oldLines), reindent(h.oldLines, lines[start:start+len(h.oldLines)], h.newLines))
+		offset += len(h.newLines) - len(h.oldLines)
+		matches = append(matches, match)
+	}
+	return matches, os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0644)
+}
+
+// applyEdit applies one edit to content. Exact matches are preferred; when
+// old_string does not occur verbatim, the best fuzzy match is replaced if
+// it is confident and unambiguous.
+func applyEdit(content string, edit Edit) (string, EditMatch, error) {
+	if edit.OldString == edit.NewString {
+		return "", EditMatch{}, fmt.Errorf("old_string and new_string must be different")
+	}
+	if edit.OldString == "" {
+		return "", EditMatch{}, fmt.Errorf("old_string is empty")
+	}
+
+	if count := strings.Count(content, edit.OldString); count > 0 {
+		// Multiple occurrences of a longer old_string replace the first one;
+		// very short strings are too ambiguous for that.
+		if !edit.ReplaceAll && count > 1 && len(strings.TrimSpace(edit.OldString)) < 5 {
+			return "", EditMatch{}, fmt.Errorf("old_string appears %d times. Provide more context or set replace_all=true", count)
+		}
+		match := EditMatch{Line: strings.Count(content[:strings.Index(content, edit.OldString)], "\n") + 1, Confidence: 1}
+		if edit.ReplaceAll {
+			return strings.ReplaceAll(content, edit.OldString, edit.NewString), match, nil
+		}
+		return strings.Replace(content, edit.OldString, edit.NewString, 1), match, nil
+	}
+
+	oldLines := strings.Split(strings.TrimSuffix(edit.OldString, "\n"), "\n")
+	newLines := strings.Split(strings.TrimSuffix(edit.NewString, "\n"), "\n")
+	if edit.NewString == "" {
+		newLines = nil
+	}
+	lines := strings.Split(content, "\n")
+	start, score, err := fuzzyFind(lines, oldLines, -1)
+	if err != nil {
+		return "", EditMatch{}, fmt.Errorf("old_string not found: %w", err)
+	}
+	lines = replaceLines(lines, start, len(oldLines), reindent(oldLines, lines[start:start+len(oldLines)], newLines))
+	return strings.Join(lines, "\n"), EditMatch{Line: start + 1, Fuzzy: true, Confidence: score}, nil
+}
+
+// locateLines finds want in lines: the exact occurrence nearest to hint, or
+// else the best fuzzy match. Empty want matches at hint.
+func locateLines(lines, want []string, hint int) (int, EditMatch, error) {
+	if len(want) == 0 {
+		hint = min(hint, len(lines))
+		return hint, EditMatch{Line: hint + 1, Confidence: 1}, nil
+	}
+	best := -1
+	for i := 0; i+len(want) <= len(lines); i++ {
+		if linesEqual(lines[i:i+len(want)], want) && (best < 0 || abs(i-hint) < abs(best-hint)) {
+			best = i
+		}
+	}
+	if best >= 0 {
+		return best, EditMatch{Line: best + 1, Confidence: 1}, nil
+	}
+	start, score, err := fuzzyFind(lines, want, hint)
+	if err != nil {
+		return 0, EditMatch{}, fmt.Errorf("context not found: %w", err)
+	}
+	return start, EditMatch{Line: start + 1, Fuzzy: true, Confidence: score}, nil
+}
+
+// fuzzyFind returns the start of the window of lines most similar to want.
+// It fails when no window reaches FuzzyMatchThreshold, or when several
+// windows score best and hint (ignored when negative) does not single out
+// the nearest one.
+func fuzzyFind(lines, want []string, hint int) (int, float64, error) {
+	var candidates []int
+	bestScore := 0.0
+	for i := 0; i+len(want) <= len(lines); i++ {
+		score := windowSimilarity(lines[i:i+len(want)], want)
+		switch {
+		case score < FuzzyMatchThreshold || score < bestScore:
+		case score > bestScore:
+			candidates, bestScore = []int{i}, score
+		default:
+			candidates = append(candidates, i)
+		}
+	}
+	if len(candidates) == 0 {
+		return 0, 0, fmt.Errorf("no match with at least %.0f%% confidence", FuzzyMatchThreshold*100)
+	}
+	if hint >= 0 {
+		slices.SortStableFunc(candidates, func(a, b int) int { return abs(a-hint) - abs(b-hint) })
+		if len(candidates) == 1 || abs(candidates[0]-hint) < abs(candidates[1]-hint) {
+			return candidates[0], bestScore, nil
+		}
+	} else if len(candidates) == 1 {
+		return candidates[0], bestScore, nil
+	}
+	return 0, 0, fmt.Errorf("%d equally good fuzzy matches; provide more context", len(candidates))
+}
+
+// windowSimilarity averages the similarity of corresponding lines. It stops
+// early once the window can no longer reach FuzzyMatchThreshold.
+func windowSimilarity(window, want []string) float64 {
+	budget := (1 - FuzzyMatchThreshold) * float64(len(want))
+	total := 0.0
+	for i := range want {
+		s := lineSimilarity(window[i], want[i])
+		total += s
+		if budget -= 1 - s; budget < -1e-9 {
+			return 0
+		}
+	}
+	return total / float64(len(want))
+}
+
+// lineSimilarity compares two lines after collapsing whitespace, as one
+// minus their edit distance relative to the longer line.
+func lineSimilarity(a, b string) float64 {
+	a, b = normalizeSpace(a), normalizeSpace(b)
+	if a == b {
+		return 1
+	}
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+func normalizeSpace(s string) string {
+	return strings.Join(strings.FieldsFunc(s, unicode.IsSpace), " ")
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// reindent shifts the indentation of newLines by the difference between
+// the indentation of the expected lines and that of the lines actually
+// matched, so fuzzy matches keep the file's indentation.
+func reindent(expected, matched, newLines []string) []string {
+	for i := range expected {
+		if strings.TrimSpace(expected[i]) == "" {
+			continue
+		}
+		from, to := leadingSpace(expected[i]), leadingSpace(matched[i])
+		if from == to {
+			return newLines
+		}
+		out := make([]string, len(newLines))
+		for j, line := range newLines {
+			if rest, ok := strings.CutPrefix(line, from); ok && strings.TrimSpace(line) != "" {
+				line = to + rest
+			}
+			out[j] = line
+		}
+		return out
+	}
+	return newLines
+}
+
+func leadingSpace(s string) string {
+	return s[:len(s)-len(strings.TrimLeftFunc(s, unicode.IsSpace))]
+}
+
+func replaceLines(lines []string, start, n int, replacement []string) []string {
+	out := make([]string, 0, len(lines)-n+len(replacement))
+	out = append(out, lines[:start]...)
+	out = append(out, replacement...)
+	return append(out, lines[start+n:]...)
+}
+
+func linesEqual(a, b []string) bool {
+	for i := range a {
+		if strings.TrimRight(a[i], "\r") != strings.TrimRight(b[i], "\r") {
+			return false
+		}
+	}
+	return true
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+type diffHunk struct {
+	header   string
+	oldStart int
+	oldLines []string
+	newLines []string
+}
+
+// parseUnifiedDiff parses the hunks of a unified diff of one file. File
+// headers are optional.
+func parseUnifiedDiff(diff string) ([]diffHunk, error) {
+	var hunks []diffHunk
+	var cur *diffHunk
+	files := 0
+	for _, line := range strings.Split(strings.TrimSuffix(diff, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			m := hunkHeaderRe.FindStringSubmatch(line)
+			if m == nil {
+				return nil, fmt.Errorf("invalid hunk header %q", line)
+			}
+			oldStart, _ := strconv.Atoi(m[1])
+			hunks = append(hunks, diffHunk{header: strings.TrimSpace(line[:len(m[0])]), oldStart: oldStart})
+			cur = &hunks[len(hunks)-1]
+		case strings.HasPrefix(line, "+++ "):
+			if files++; files > 1 {
+				return nil, errors.New("diff changes more than one file; edit one file at a time")
+			}
+			cur = nil
+		case strings.HasPrefix(line, "--- "), strings.HasPrefix(line, "diff "), strings.HasPrefix(line, "index "):
+			cur = nil
+		case cur == nil:
+			continue
+		case strings.HasPrefix(line, "+"):
+			cur.newLines = append(cur.newLines, line[1:])
+		case strings.HasPrefix(line, "-"):
+			cur.oldLines = append(cur.oldLines, line[1:])
+		case strings.HasPrefix(line, " "), line == "":
+			// Some tools strip the space of empty context lines.
+			cur.oldLines = append(cur.oldLines, strings.TrimPrefix(line, " "))
+			cur.newLines = append(cur.newLines, strings.TrimPrefix(line, " "))
+		case strings.HasPrefix(line, `\`):
+			// "\ No newline at end of file"
+		default:
+			return nil, fmt.Errorf("invalid diff line %q", line)
+		}
+	}
+	if len(hunks) == 0 {
+		return nil, errors.New("diff has no hunks")
+	}
+	return hunks, nil
+}
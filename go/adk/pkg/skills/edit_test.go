@@ -0,0 +1,136 @@
+package skills
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeEditTestFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func readEditTestFile(t *testing.T, path string) string {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
+
+const editTestSource = `func main() {
+	if ready {
+		start(ctx,   "server")
+		wait()
+	}
+}
+`
+
+func TestMultiEditFileContent_FuzzyMatch(t *testing.T) {
+	path := writeEditTestFile(t, editTestSource)
+
+	// Spaces instead of tabs and collapsed whitespace still match; the
+	// replacement takes the file's indentation.
+	matches, err := MultiEditFileContent(path, []Edit{{
+		OldString: "    start(ctx, \"server\")\n    wait()",
+		NewString: "    start(ctx, \"server\")\n    waitAll()",
+	}})
+	if err != nil {
+		t.Fatalf("MultiEditFileContent() error = %v", err)
+	}
+	if len(matches) != 1 || !matches[0].Fuzzy || matches[0].Line != 3 {
+		t.Errorf("matches = %+v, want one fuzzy match at line 3", matches)
+	}
+	want := strings.Replace(editTestSource, "\t\tstart(ctx,   \"server\")\n\t\twait()", "\t\tstart(ctx, \"server\")\n\t\twaitAll()", 1)
+	if got := readEditTestFile(t, path); got != want {
+		t.Errorf("content = %q, want %q", got, want)
+	}
+
+	if _, err := MultiEditFileContent(path, []Edit{{OldString: "stop(everything)\nnow()", NewString: "x"}}); err == nil || !strings.Contains(err.Error(), "confidence") {
+		t.Errorf("dissimilar old_string error = %v, want no confident match", err)
+	}
+}
+
+func TestMultiEditFileContent_Atomic(t *testing.T) {
+	path := writeEditTestFile(t, editTestSource)
+
+	_, err := MultiEditFileContent(path, []Edit{
+		{OldString: "wait()", NewString: "waitAll()"},
+		{OldString: "missing()", NewString: "x"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "edit 2") {
+		t.Fatalf("MultiEditFileContent() error = %v, want edit 2 to fail", err)
+	}
+	if got := readEditTestFile(t, path); got != editTestSource {
+		t.Errorf("a failed multi-edit changed the file: %q", got)
+	}
+
+	// Later edits see the result of earlier ones.
+	if _, err := MultiEditFileContent(path, []Edit{
+		{OldString: "wait()", NewString: "waitAll()"},
+		{OldString: "waitAll()", NewString: "waitAll(ctx)"},
+	}); err != nil {
+		t.Fatalf("MultiEditFileContent() error = %v", err)
+	}
+	if got := readEditTestFile(t, path); !strings.Contains(got, "waitAll(ctx)") {
+		t.Errorf("content = %q, want both edits applied", got)
+	}
+}
+
+func TestApplyDiffToFile(t *testing.T) {
+	path := writeEditTestFile(t, editTestSource)
+
+	// The header line numbers are off and the context has drifted
+	// whitespace; the hunk is still located.
+	diff := `--- a/main.go
++++ b/main.go
+@@ -10,4 +10,5 @@ func main() {
+ 	if ready {
+ 		start(ctx, "server")
+-		wait()
++		wait()
++		log.Print("done")
+ 	}
+`
+	matches, err := ApplyDiffToFile(path, diff)
+	if err != nil {
+		t.Fatalf("ApplyDiffToFile() error = %v", err)
+	}
+	if len(matches) != 1 || !matches[0].Fuzzy || matches[0].Line != 2 {
+		t.Errorf("matches = %+v, want one fuzzy match at line 2", matches)
+	}
+	if got := readEditTestFile(t, path); !strings.Contains(got, "\t\twait()\n\t\tlog.Print(\"done\")\n\t}") {
+		t.Errorf("content = %q, want the added line", got)
+	}
+
+	before := readEditTestFile(t, path)
+	bad := "@@ -1,2 +1,2 @@\n func main() {\n-\tif ready {\n+\tif set {\n@@ -40,1 +40,1 @@\n-nothing like this\n+x\n"
+	if _, err := ApplyDiffToFile(path, bad); err == nil || !strings.Contains(err.Error(), "hunk 2") {
+		t.Errorf("ApplyDiffToFile() error = %v, want hunk 2 to fail", err)
+	}
+	if got := readEditTestFile(t, path); got != before {
+		t.Errorf("a failed diff changed the file: %q", got)
+	}
+
+	twoFiles := "--- a/a\n+++ b/a\n@@ -1 +1 @@\n-x\n+y\n--- a/b\n+++ b/b\n@@ -1 +1 @@\n-x\n+y\n"
+	if _, err := ApplyDiffToFile(path, twoFiles); err == nil {
+		t.Error("a diff of two files should be rejected")
+	}
+}
+
+func TestFuzzyFind_Ambiguous(t *testing.T) {
+	lines := strings.Split("a()\nb()\nx\na()\nb()", "\n")
+	if _, _, err := fuzzyFind(lines, []string{" a()", " b()"}, -1); err == nil || !strings.Contains(err.Error(), "equally good") {
+		t.Errorf("fuzzyFind() error = %v, want an ambiguous match", err)
+	}
+	if start, _, err := fuzzyFind(lines, []string{" a()", " b()"}, 4); err != nil || start != 3 {
+		t.Errorf("fuzzyFind() with hint = %d, %v; want the match nearest the hint", start, err)
+	}
+}
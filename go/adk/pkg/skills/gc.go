@@ -0,0 +1,238 @@
+package skills
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Environment variables configuring workspace garbage collection.
+const (
+	// EnvWorkspaceMaxAge is the idle time (a Go duration such as "24h") after
+	// which a session workspace is deleted.
+	EnvWorkspaceMaxAge = "KAGENT_WORKSPACE_MAX_AGE"
+	// EnvWorkspaceMaxSizeMB is the size in megabytes above which a session
+	// workspace is deleted.
+	EnvWorkspaceMaxSizeMB = "KAGENT_WORKSPACE_MAX_SIZE_MB"
+	// EnvWorkspaceGCInterval is how often the sweeper runs. Defaults to 10m.
+	EnvWorkspaceGCInterval = "KAGENT_WORKSPACE_GC_INTERVAL"
+)
+
+const (
+	defaultGCInterval = 10 * time.Minute
+	// sessionUserFile records the user owning a session workspace so the
+	// collector can delete the session on their behalf.
+	sessionUserFile = ".kagent_user"
+)
+
+// GCConfig holds the limits enforced by the workspace garbage collector.
+type GCConfig struct {
+	// MaxAge deletes workspaces not used for longer than this. Zero disables
+	// the age limit.
+	MaxAge time.Duration
+	// MaxSessionBytes deletes workspaces larger than this. Zero disables the
+	// size limit.
+	MaxSessionBytes int64
+	// Interval between background sweeps.
+	Interval time.Duration
+}
+
+// Enabled reports whether any limit is configured.
+func (c GCConfig) Enabled() bool {
+	return c.MaxAge > 0 || c.MaxSessionBytes > 0
+}
+
+// GCConfigFromEnv reads the garbage collection limits from the environment.
+func GCConfigFromEnv() (GCConfig, error) {
+	cfg := GCConfig{Interval: defaultGCInterval}
+	if v := strings.TrimSpace(os.Getenv(EnvWorkspaceMaxAge)); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return GCConfig{}, fmt.Errorf("invalid %s %q", EnvWorkspaceMaxAge, v)
+		}
+		cfg.MaxAge = d
+	}
+	if v := strings.TrimSpace(os.Getenv(EnvWorkspaceMaxSizeMB)); v != "" {
+		mb, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || mb < 0 {
+			return GCConfig{}, fmt.Errorf("invalid %s %q", EnvWorkspaceMaxSizeMB, v)
+		}
+		cfg.MaxSessionBytes = mb << 20
+	}
+	if v := strings.TrimSpace(os.Getenv(EnvWorkspaceGCInterval)); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return GCConfig{}, fmt.Errorf("invalid %s %q", EnvWorkspaceGCInterval, v)
+		}
+		cfg.Interval = d
+	}
+	return cfg, nil
+}
+
+// RecordSessionUser stores the user owning the session workspace at dir.
+func RecordSessionUser(dir, userID string) error {
+	return os.WriteFile(filepath.Join(dir, sessionUserFile), []byte(userID), 0o644)
+}
+
+// GCResult summarizes a garbage collection sweep.
+type GCResult struct {
+	Scanned        int      `json:"scanned"`
+	Deleted        []string `json:"deleted"`
+	ReclaimedBytes int64    `json:"reclaimed_bytes"`
+	Errors         []string `json:"errors,omitempty"`
+}
+
+// SessionDeleteFunc is called for every session whose workspace was collected.
+type SessionDeleteFunc func(ctx context.Context, sessionID, userID string) error
+
+// WorkspaceGC deletes expired or oversized session workspaces under a base
+// directory.
+type WorkspaceGC struct {
+	baseDir  string
+	config   GCConfig
+	onDelete SessionDeleteFunc
+	now      func() time.Time
+
+	// mu serializes sweeps so the admin endpoint and the background loop do
+	// not race on the same directories.
+	mu sync.Mutex
+
+	reclaimedBytes metric.Int64Counter
+	deletedCount   metric.Int64Counter
+}
+
+// NewWorkspaceGC returns a collector for the workspaces under baseDir.
+// onDelete may be nil; when set it is used to delete the session itself,
+// e.g. from the kagent controller.
+func NewWorkspaceGC(baseDir string, config GCConfig, onDelete SessionDeleteFunc) *WorkspaceGC {
+	if config.Interval <= 0 {
+		config.Interval = defaultGCInterval
+	}
+	meter := otel.Meter("github.com/kagent-dev/kagent/go/adk/pkg/skills")
+	reclaimed, _ := meter.Int64Counter("kagent.workspace.gc.reclaimed_bytes",
+		metric.WithUnit("By"),
+		metric.WithDescription("Bytes reclaimed by deleting session workspaces"))
+	deleted, _ := meter.Int64Counter("kagent.workspace.gc.deleted_sessions",
+		metric.WithDescription("Session workspaces deleted by garbage collection"))
+	return &WorkspaceGC{
+		baseDir:        baseDir,
+		config:         config,
+		onDelete:       onDelete,
+		now:            time.Now,
+		reclaimedBytes: reclaimed,
+		deletedCount:   deleted,
+	}
+}
+
+// Start runs a sweep every Interval until ctx is done.
+func (g *WorkspaceGC) Start(ctx context.Context) {
+	log := logr.FromContextOrDiscard(ctx)
+	go func() {
+		ticker := time.NewTicker(g.config.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				result, err := g.Sweep(ctx)
+				if err != nil {
+					log.Error(err, "Workspace garbage collection failed")
+					continue
+				}
+				if len(result.Deleted) > 0 || len(result.Errors) > 0 {
+					log.Info("Workspace garbage collection finished",
+						"deleted", len(result.Deleted),
+						"reclaimedBytes", result.ReclaimedBytes,
+						"errors", result.Errors)
+				}
+			}
+		}
+	}()
+}
+
+// Sweep deletes every session workspace over the configured age or size.
+func (g *WorkspaceGC) Sweep(ctx context.Context) (*GCResult, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	result := &GCResult{Deleted: []string{}}
+	entries, err := os.ReadDir(g.baseDir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return result, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workspace directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		result.Scanned++
+		sessionID := entry.Name()
+		dir := filepath.Join(g.baseDir, sessionID)
+
+		info, err := entry.Info()
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", sessionID, err))
+			continue
+		}
+		size, err := DirSize(dir)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", sessionID, err))
+			continue
+		}
+		expired := g.config.MaxAge > 0 && g.now().Sub(info.ModTime()) > g.config.MaxAge
+		oversized := g.config.MaxSessionBytes > 0 && size > g.config.MaxSessionBytes
+		if !expired && !oversized {
+			continue
+		}
+
+		userID, _ := os.ReadFile(filepath.Join(dir, sessionUserFile))
+		if err := os.RemoveAll(dir); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", sessionID, err))
+			continue
+		}
+		result.Deleted = append(result.Deleted, sessionID)
+		result.ReclaimedBytes += size
+		g.reclaimedBytes.Add(ctx, size)
+		g.deletedCount.Add(ctx, 1)
+
+		if g.onDelete != nil && len(userID) > 0 {
+			if err := g.onDelete(ctx, sessionID, string(userID)); err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("%s: failed to delete session: %v", sessionID, err))
+			}
+		}
+	}
+	return result, nil
+}
+
+// ServeHTTP runs a sweep on POST and returns the GCResult as JSON.
+func (g *WorkspaceGC) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	result, err := g.Sweep(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(result)
+}
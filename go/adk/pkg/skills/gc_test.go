@@ -0,0 +1,95 @@
+package skills
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWorkspaceGC_Sweep(t *testing.T) {
+	ctx := context.Background()
+	base := t.TempDir()
+	ws := NewDirWorkspace(base, "", 0)
+
+	for _, id := range []string{"fresh", "stale", "large"} {
+		dir, err := ws.Prepare(ctx, id)
+		if err != nil {
+			t.Fatalf("Prepare(%s) error = %v", id, err)
+		}
+		if err := RecordSessionUser(dir, "user-"+id); err != nil {
+			t.Fatalf("RecordSessionUser() error = %v", err)
+		}
+	}
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(filepath.Join(base, "stale"), old, old); err != nil {
+		t.Fatalf("Chtimes() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(base, "large", "outputs", "big.bin"), make([]byte, 4096), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	deleted := map[string]string{}
+	gc := NewWorkspaceGC(base, GCConfig{MaxAge: time.Hour, MaxSessionBytes: 1024}, func(_ context.Context, sessionID, userID string) error {
+		deleted[sessionID] = userID
+		return nil
+	})
+	result, err := gc.Sweep(ctx)
+	if err != nil {
+		t.Fatalf("Sweep() error = %v", err)
+	}
+
+	if result.Scanned != 3 || len(result.Deleted) != 2 {
+		t.Errorf("Sweep() = %+v, want 3 scanned and 2 deleted", result)
+	}
+	if result.ReclaimedBytes < 4096 {
+		t.Errorf("ReclaimedBytes = %d, want at least 4096", result.ReclaimedBytes)
+	}
+	if deleted["stale"] != "user-stale" || deleted["large"] != "user-large" {
+		t.Errorf("onDelete calls = %v", deleted)
+	}
+	if _, err := os.Stat(filepath.Join(base, "fresh")); err != nil {
+		t.Errorf("Expected fresh workspace to be kept: %v", err)
+	}
+}
+
+func TestWorkspaceGC_ServeHTTP(t *testing.T) {
+	gc := NewWorkspaceGC(filepath.Join(t.TempDir(), "missing"), GCConfig{MaxAge: time.Hour}, nil)
+
+	rec := httptest.NewRecorder()
+	gc.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/workspaces/gc", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("GET status = %d, want %d", rec.Code, http.StatusMethodNotAllowed)
+	}
+
+	rec = httptest.NewRecorder()
+	gc.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/workspaces/gc", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("POST status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), `"reclaimed_bytes":0`) {
+		t.Errorf("POST body = %s", rec.Body.String())
+	}
+}
+
+func TestGCConfigFromEnv(t *testing.T) {
+	t.Setenv(EnvWorkspaceMaxAge, "24h")
+	t.Setenv(EnvWorkspaceMaxSizeMB, "100")
+	t.Setenv(EnvWorkspaceGCInterval, "")
+	cfg, err := GCConfigFromEnv()
+	if err != nil {
+		t.Fatalf("GCConfigFromEnv() error = %v", err)
+	}
+	if cfg.MaxAge != 24*time.Hour || cfg.MaxSessionBytes != 100<<20 || cfg.Interval != defaultGCInterval || !cfg.Enabled() {
+		t.Errorf("GCConfigFromEnv() = %+v", cfg)
+	}
+
+	t.Setenv(EnvWorkspaceMaxAge, "forever")
+	if _, err := GCConfigFromEnv(); err == nil {
+		t.Error("Expected error for invalid max age")
+	}
+}
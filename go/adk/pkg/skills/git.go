@@ -0,0 +1,228 @@
+package skills
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Environment variables configuring the git tools.
+const (
+	// EnvGitAuthorName is the author and committer name of commits made by
+	// git_commit. Defaults to "kagent".
+	EnvGitAuthorName = "KAGENT_GIT_AUTHOR_NAME"
+	// EnvGitAuthorEmail is the author and committer email of commits made
+	// by git_commit. Defaults to "kagent@localhost".
+	EnvGitAuthorEmail = "KAGENT_GIT_AUTHOR_EMAIL"
+	// EnvGitRequireApproval makes git_commit ask the user for approval
+	// before committing when set to "true".
+	EnvGitRequireApproval = "KAGENT_GIT_REQUIRE_APPROVAL"
+)
+
+const (
+	defaultGitAuthorName  = "kagent"
+	defaultGitAuthorEmail = "kagent@localhost"
+	gitCommandTimeout     = 30 * time.Second
+)
+
+// ErrNothingToCommit is returned by Commit when the repository has no
+// changes to commit.
+var ErrNothingToCommit = errors.New("nothing to commit")
+
+// GitRunner runs git in the sandbox for the git tools. Commits use the
+// configured author identity and skip repository hooks.
+type GitRunner struct {
+	srtArgs         []string
+	authorName      string
+	authorEmail     string
+	requireApproval bool
+}
+
+// NewGitRunnerFromEnv builds a GitRunner using the srt settings and the
+// KAGENT_GIT_* variables.
+func NewGitRunnerFromEnv() (*GitRunner, error) {
+	srtArgs, err := resolveSRTSettingsArgs()
+	if err != nil {
+		return nil, err
+	}
+	g := &GitRunner{
+		srtArgs:         srtArgs,
+		authorName:      defaultGitAuthorName,
+		authorEmail:     defaultGitAuthorEmail,
+		requireApproval: strings.EqualFold(strings.TrimSpace(os.Getenv(EnvGitRequireApproval)), "true"),
+	}
+	if v := strings.TrimSpace(os.Getenv(EnvGitAuthorName)); v != "" {
+		g.authorName = v
+	}
+	if v := strings.TrimSpace(os.Getenv(EnvGitAuthorEmail)); v != "" {
+		g.authorEmail = v
+	}
+	return g, nil
+}
+
+// RequireApproval reports whether commits need the user's approval.
+func (g *GitRunner) RequireApproval() bool {
+	return g.requireApproval
+}
+
+// Status returns the short status of the repository at repoDir.
+func (g *GitRunner) Status(ctx context.Context, repoDir string) (string, error) {
+	out, err := g.git(ctx, repoDir, nil, "status", "--short", "--branch")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(out, "\n"), nil
+}
+
+// Diff returns the diff of the working tree against the index, or of the
+// index against HEAD when staged is true, limited to paths when given.
+func (g *GitRunner) Diff(ctx context.Context, repoDir string, staged bool, paths []string) (string, error) {
+	args := []string{"diff", "--no-color", "--no-ext-diff"}
+	if staged {
+		args = append(args, "--cached")
+	}
+	args = append(append(args, "--"), paths...)
+	out, err := g.git(ctx, repoDir, nil, args...)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(out, "\n"), nil
+}
+
+// Commit stages paths, or every change when paths is empty, and commits
+// them with message. It returns a summary of the new commit, or
+// ErrNothingToCommit when nothing is staged.
+func (g *GitRunner) Commit(ctx context.Context, repoDir, message string, paths []string) (string, error) {
+	if strings.TrimSpace(message) == "" {
+		return "", errors.New("commit message is empty")
+	}
+	if _, err := g.git(ctx, repoDir, nil, append([]string{"add", "--all", "--"}, paths...)...); err != nil {
+		return "", err
+	}
+	if _, err := g.git(ctx, repoDir, nil, "diff", "--cached", "--quiet"); err == nil {
+		return "", ErrNothingToCommit
+	}
+	if _, err := g.git(ctx, repoDir, strings.NewReader(message),
+		"-c", "user.name="+g.authorName, "-c", "user.email="+g.authorEmail,
+		"commit", "--no-verify", "--file=-"); err != nil {
+		return "", err
+	}
+	out, err := g.git(ctx, repoDir, nil, "log", "-1", "--stat", "--format=%H%n%an <%ae>%n%n%B")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(out, "\n"), nil
+}
+
+// ApplyPatch applies a unified diff to the working tree of the repository
+// at repoDir. The patch is checked first and applied only if every hunk
+// applies; patches touching .git or paths outside the repository are
+// rejected. It returns the files the patch changed.
+func (g *GitRunner) ApplyPatch(ctx context.Context, repoDir, patch string) (string, error) {
+	if !strings.Contains(patch, "\n@@") && !strings.Contains(patch, "GIT binary patch") {
+		return "", errors.New("patch is not a unified diff")
+	}
+	if !strings.HasSuffix(patch, "\n") {
+		patch += "\n"
+	}
+	numstat, err := g.git(ctx, repoDir, strings.NewReader(patch), "apply", "--numstat", "-z", "-")
+	if err != nil {
+		return "", fmt.Errorf("invalid patch: %w", err)
+	}
+	for _, path := range patchPaths(numstat) {
+		if path == ".git" || strings.HasPrefix(path, ".git/") || strings.Contains("/"+path, "/.git/") {
+			return "", fmt.Errorf("patch may not modify %s", path)
+		}
+	}
+	if _, err := g.git(ctx, repoDir, strings.NewReader(patch), "apply", "--check", "--whitespace=nowarn", "-"); err != nil {
+		return "", fmt.Errorf("patch does not apply: %w", err)
+	}
+	if _, err := g.git(ctx, repoDir, strings.NewReader(patch), "apply", "--whitespace=nowarn", "-"); err != nil {
+		return "", err
+	}
+	out, err := g.git(ctx, repoDir, strings.NewReader(patch), "apply", "--stat", "-")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(out, "\n"), nil
+}
+
+// patchPaths returns the paths in `git apply --numstat -z` output. Renames
+// list both the old and the new path.
+func patchPaths(numstat string) []string {
+	var paths []string
+	fields := strings.Split(numstat, "\x00")
+	for i := 0; i < len(fields); i++ {
+		line := fields[i]
+		if line == "" {
+			continue
+		}
+		parts := strings.SplitN(line, "\t", 3)
+		if len(parts) < 3 {
+			continue
+		}
+		if parts[2] != "" {
+			paths = append(paths, parts[2])
+			continue
+		}
+		// A rename: the old and new paths follow as separate fields.
+		for j := 0; j < 2 && i+1 < len(fields); j++ {
+			i++
+			paths = append(paths, fields[i])
+		}
+	}
+	return paths
+}
+
+// git runs git in repoDir, which must be the top level of a repository so
+// a session cannot reach a repository enclosing its workspace.
+func (g *GitRunner) git(ctx context.Context, repoDir string, stdin io.Reader, args ...string) (string, error) {
+	if _, err := os.Stat(filepath.Join(repoDir, ".git")); err != nil {
+		return "", fmt.Errorf("%s is not the top level of a git repository", repoDir)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, gitCommandTimeout)
+	defer cancel()
+
+	gitArgs := append(append([]string{}, g.srtArgs...), "git")
+	gitArgs = append(append(gitArgs, "-c", "core.hooksPath=/dev/null", "-c", "core.pager=cat"), args...)
+	cmd := exec.CommandContext(ctx, "srt", gitArgs...)
+	cmd.Dir = repoDir
+	cmd.Stdin = stdin
+	// Keep the repository from being looked up above repoDir.
+	cmd.Env = append(os.Environ(), "GIT_CEILING_DIRECTORIES="+filepath.Dir(repoDir), "GIT_TERMINAL_PROMPT=0")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return "", fmt.Errorf("git %s timed out after %v", gitSubcommand(args), gitCommandTimeout)
+		}
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = strings.TrimSpace(stdout.String())
+		}
+		return "", fmt.Errorf("git %s failed: %v: %s", gitSubcommand(args), err, msg)
+	}
+	return stdout.String(), nil
+}
+
+// gitSubcommand returns the subcommand of git arguments, skipping -c options.
+func gitSubcommand(args []string) string {
+	for i := 0; i < len(args); i++ {
+		if args[i] == "-c" {
+			i++
+			continue
+		}
+		return args[i]
+	}
+	return ""
+}
@@ -0,0 +1,113 @@
+package skills
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestGitRepo(t *testing.T) (*GitRunner, string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	installFakeSRT(t)
+	t.Setenv(EnvGitAuthorName, "Agent Smith")
+	t.Setenv(EnvGitAuthorEmail, "agent@example.com")
+	git, err := NewGitRunnerFromEnv()
+	if err != nil {
+		t.Fatalf("NewGitRunnerFromEnv() error = %v", err)
+	}
+
+	dir := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", dir).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v: %s", err, out)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.txt"), []byte("one\ntwo\nthree\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return git, dir
+}
+
+func TestGitRunner_CommitAndDiff(t *testing.T) {
+	git, dir := newTestGitRepo(t)
+	ctx := context.Background()
+
+	status, err := git.Status(ctx, dir)
+	if err != nil || !strings.Contains(status, "?? main.txt") {
+		t.Fatalf("Status() = %q, %v; want main.txt untracked", status, err)
+	}
+
+	summary, err := git.Commit(ctx, dir, "Add main.txt", nil)
+	if err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	if !strings.Contains(summary, "Agent Smith <agent@example.com>") || !strings.Contains(summary, "Add main.txt") {
+		t.Errorf("Commit() = %q, want the configured author and the message", summary)
+	}
+	if _, err := git.Commit(ctx, dir, "Again", nil); !errors.Is(err, ErrNothingToCommit) {
+		t.Errorf("Commit() with no changes error = %v, want ErrNothingToCommit", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "main.txt"), []byte("one\n2\nthree\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	diff, err := git.Diff(ctx, dir, false, nil)
+	if err != nil || !strings.Contains(diff, "-two\n+2") {
+		t.Errorf("Diff() = %q, %v; want the change", diff, err)
+	}
+}
+
+func TestGitRunner_ApplyPatch(t *testing.T) {
+	git, dir := newTestGitRepo(t)
+	ctx := context.Background()
+
+	patch := `--- a/main.txt
++++ b/main.txt
+@@ -1,3 +1,3 @@
+ one
+-two
++TWO
+ three
+`
+	stat, err := git.ApplyPatch(ctx, dir, patch)
+	if err != nil || !strings.Contains(stat, "main.txt") {
+		t.Fatalf("ApplyPatch() = %q, %v", stat, err)
+	}
+	if got, _ := os.ReadFile(filepath.Join(dir, "main.txt")); string(got) != "one\nTWO\nthree\n" {
+		t.Errorf("main.txt = %q, want the patch applied", got)
+	}
+
+	// The same patch no longer applies and must leave the file untouched.
+	if _, err := git.ApplyPatch(ctx, dir, patch); err == nil || !strings.Contains(err.Error(), "does not apply") {
+		t.Errorf("reapplying error = %v, want does not apply", err)
+	}
+
+	for name, bad := range map[string]string{
+		"not a diff":   "just some text",
+		"git metadata": "--- a/.git/config\n+++ b/.git/config\n@@ -0,0 +1 @@\n+x\n",
+		"outside repo": "--- a/../escape.txt\n+++ b/../escape.txt\n@@ -0,0 +1 @@\n+x\n",
+	} {
+		if _, err := git.ApplyPatch(ctx, dir, bad); err == nil {
+			t.Errorf("%s: ApplyPatch() should fail", name)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(dir), "escape.txt")); !os.IsNotExist(err) {
+		t.Error("patch escaped the repository")
+	}
+}
+
+func TestGitRunner_RequiresRepositoryRoot(t *testing.T) {
+	git, dir := newTestGitRepo(t)
+	sub := filepath.Join(dir, "sub")
+	if err := os.Mkdir(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := git.Status(context.Background(), sub); err == nil {
+		t.Error("Status() of a directory inside a repository should fail")
+	}
+}
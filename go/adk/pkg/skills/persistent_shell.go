@@ -0,0 +1,323 @@
+package skills
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Environment variables configuring persistent bash shells.
+const (
+	// EnvPersistentShell keeps one bash process per session when set to
+	// "true", so the working directory, exported variables and shell
+	// functions carry over between bash tool calls.
+	EnvPersistentShell = "KAGENT_BASH_PERSISTENT_SHELL"
+	// EnvShellIdleTimeout is how long an unused session shell is kept, as a
+	// Go duration. Defaults to 10m.
+	EnvShellIdleTimeout = "KAGENT_BASH_SHELL_IDLE_TIMEOUT"
+)
+
+const defaultShellIdleTimeout = 10 * time.Minute
+
+// PersistentShells runs bash tool commands in one sandboxed bash process per
+// session. A shell is killed when it has been idle for the idle timeout,
+// when a command times out or exits the shell, and when the session's
+// workspace is deleted; the next command then starts a fresh shell.
+type PersistentShells struct {
+	srtArgs     []string
+	idleTimeout time.Duration
+
+	mu     sync.Mutex
+	shells map[string]*sessionShell
+}
+
+// NewPersistentShellsFromEnv returns PersistentShells when
+// KAGENT_BASH_PERSISTENT_SHELL is "true", or nil otherwise.
+func NewPersistentShellsFromEnv() (*PersistentShells, error) {
+	if !strings.EqualFold(strings.TrimSpace(os.Getenv(EnvPersistentShell)), "true") {
+		return nil, nil
+	}
+	srtArgs, err := resolveSRTSettingsArgs()
+	if err != nil {
+		return nil, err
+	}
+	idleTimeout := defaultShellIdleTimeout
+	if v := strings.TrimSpace(os.Getenv(EnvShellIdleTimeout)); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid %s %q: must be a positive duration", EnvShellIdleTimeout, v)
+		}
+		idleTimeout = d
+	}
+	return &PersistentShells{
+		srtArgs:     srtArgs,
+		idleTimeout: idleTimeout,
+		shells:      make(map[string]*sessionShell),
+	}, nil
+}
+
+// ExecuteCommand runs command in the session's shell, starting the shell in
+// workingDir if the session has none. Output is copied to stream as it is
+// written when stream is not nil. Results and errors are reported like
+// CommandExecutor.ExecuteCommand, except that stdout and stderr are
+// interleaved in the order they were written.
+func (p *PersistentShells) ExecuteCommand(ctx context.Context, sessionID, command, workingDir string, stream io.Writer) (string, error) {
+	var shell *sessionShell
+	for {
+		var err error
+		if shell, err = p.shell(sessionID, workingDir); err != nil {
+			return "", err
+		}
+		shell.mu.Lock()
+		if !shell.closed {
+			break
+		}
+		// Killed between lookup and lock; start a fresh shell.
+		shell.mu.Unlock()
+	}
+	defer shell.mu.Unlock()
+
+	timeout := commandTimeout(command)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	output, exitCode, err := shell.run(ctx, command, stream)
+	if err != nil {
+		p.remove(sessionID, shell)
+		shell.kill()
+		if errors.Is(err, context.DeadlineExceeded) {
+			return "", fmt.Errorf("command timed out after %v; the shell was restarted", timeout)
+		}
+		return "", err
+	}
+	shell.lastUsed = time.Now()
+	shell.idle.Reset(p.idleTimeout)
+
+	res := strings.TrimSpace(output)
+	if exitCode != 0 {
+		errorMsg := fmt.Sprintf("Command failed with exit code %d", exitCode)
+		if res != "" {
+			errorMsg += ":\n" + res
+		}
+		return "", errors.New(errorMsg)
+	}
+	if res == "" {
+		return "Command completed successfully.", nil
+	}
+	return res, nil
+}
+
+// Close kills the shell of a session, if it has one.
+func (p *PersistentShells) Close(sessionID string) {
+	p.mu.Lock()
+	shell := p.shells[sessionID]
+	delete(p.shells, sessionID)
+	p.mu.Unlock()
+	if shell != nil {
+		shell.mu.Lock()
+		shell.kill()
+		shell.mu.Unlock()
+	}
+}
+
+// CloseAll kills every shell.
+func (p *PersistentShells) CloseAll() {
+	p.mu.Lock()
+	sessionIDs := make([]string, 0, len(p.shells))
+	for id := range p.shells {
+		sessionIDs = append(sessionIDs, id)
+	}
+	p.mu.Unlock()
+	for _, id := range sessionIDs {
+		p.Close(id)
+	}
+}
+
+// shell returns the session's running shell, starting one when the session
+// has none or its workspace was deleted or moved.
+func (p *PersistentShells) shell(sessionID, workingDir string) (*sessionShell, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if shell := p.shells[sessionID]; shell != nil {
+		if _, err := os.Stat(shell.dir); err == nil && shell.dir == workingDir {
+			return shell, nil
+		}
+		delete(p.shells, sessionID)
+		go func() {
+			shell.mu.Lock()
+			shell.kill()
+			shell.mu.Unlock()
+		}()
+	}
+
+	shell, err := startShell(p.srtArgs, workingDir)
+	if err != nil {
+		return nil, err
+	}
+	shell.idle = time.AfterFunc(p.idleTimeout, func() { p.closeIdle(sessionID, shell) })
+	p.shells[sessionID] = shell
+	return shell, nil
+}
+
+// closeIdle kills shell once it has been unused for the idle timeout.
+func (p *PersistentShells) closeIdle(sessionID string, shell *sessionShell) {
+	shell.mu.Lock()
+	defer shell.mu.Unlock()
+	if shell.closed {
+		return
+	}
+	if idle := time.Since(shell.lastUsed); idle < p.idleTimeout {
+		shell.idle.Reset(p.idleTimeout - idle)
+		return
+	}
+	p.remove(sessionID, shell)
+	shell.kill()
+}
+
+func (p *PersistentShells) remove(sessionID string, shell *sessionShell) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.shells[sessionID] == shell {
+		delete(p.shells, sessionID)
+	}
+}
+
+// sessionShell is a running bash process. Commands are written to its
+// stdin; stdout and stderr share one pipe, and each command ends with a
+// marker line carrying its exit status.
+type sessionShell struct {
+	mu       sync.Mutex // serializes commands
+	cmd      *exec.Cmd
+	stdin    io.WriteCloser
+	outFile  *os.File
+	output   *bufio.Reader
+	marker   string
+	dir      string
+	lastUsed time.Time
+	idle     *time.Timer
+	closed   bool
+}
+
+func startShell(srtArgs []string, workingDir string) (*sessionShell, error) {
+	nonce := make([]byte, 8)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate shell marker: %w", err)
+	}
+
+	args := append(append([]string{}, srtArgs...), "bash", "--noprofile", "--norc")
+	cmd := exec.Command("srt", args...)
+	cmd.Dir = workingDir
+	// A process group lets kill reach bash and the commands it started,
+	// not only the srt wrapper.
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create shell stdin: %w", err)
+	}
+	outR, outW, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create shell output pipe: %w", err)
+	}
+	cmd.Stdout = outW
+	cmd.Stderr = outW
+	if err := cmd.Start(); err != nil {
+		outR.Close()
+		outW.Close()
+		return nil, fmt.Errorf("failed to start shell: %w", err)
+	}
+	outW.Close()
+	// Reap the process; an exited shell is noticed through the output pipe.
+	go func() { _ = cmd.Wait() }()
+
+	return &sessionShell{
+		cmd:      cmd,
+		stdin:    stdin,
+		outFile:  outR,
+		output:   bufio.NewReader(outR),
+		marker:   "__KAGENT_SHELL_DONE_" + hex.EncodeToString(nonce) + "__",
+		dir:      workingDir,
+		lastUsed: time.Now(),
+	}, nil
+}
+
+// run writes command to the shell and reads its output up to the marker
+// line, until ctx is done. Commands read stdin from /dev/null so they cannot
+// consume the commands that follow.
+func (s *sessionShell) run(ctx context.Context, command string, stream io.Writer) (string, int, error) {
+	s.idle.Stop()
+	script := fmt.Sprintf("{\n%s\n} </dev/null\nprintf '\\n%s %%d\\n' \"$?\"\n", command, s.marker)
+	if _, err := io.WriteString(s.stdin, script); err != nil {
+		return "", 0, fmt.Errorf("shell is not running: %w", err)
+	}
+
+	type result struct {
+		output   string
+		exitCode int
+		err      error
+	}
+	done := make(chan result, 1)
+	go func() {
+		var out strings.Builder
+		emit := func(text string) {
+			out.WriteString(text)
+			if stream != nil && text != "" {
+				_, _ = io.WriteString(stream, text)
+			}
+		}
+		// Each line is held back until the next one arrives, because the
+		// newline printed before the marker ends the line preceding it.
+		var pending string
+		for {
+			line, err := s.output.ReadString('\n')
+			if rest, ok := strings.CutPrefix(line, s.marker+" "); ok {
+				emit(strings.TrimSuffix(pending, "\n"))
+				exitCode, _ := strconv.Atoi(strings.TrimSpace(rest))
+				done <- result{output: out.String(), exitCode: exitCode}
+				return
+			}
+			emit(pending)
+			pending = line
+			if err != nil {
+				emit(pending)
+				done <- result{output: out.String(), err: fmt.Errorf("shell exited while running the command; the next command starts a new shell:\n%s", strings.TrimSpace(out.String()))}
+				return
+			}
+		}
+	}()
+
+	select {
+	case r := <-done:
+		return r.output, r.exitCode, r.err
+	case <-ctx.Done():
+		s.kill()
+		<-done
+		return "", 0, ctx.Err()
+	}
+}
+
+// kill stops the shell and every process it started. The caller holds mu.
+func (s *sessionShell) kill() {
+	if s.closed {
+		return
+	}
+	s.closed = true
+	if s.idle != nil {
+		s.idle.Stop()
+	}
+	_ = s.stdin.Close()
+	_ = syscall.Kill(-s.cmd.Process.Pid, syscall.SIGKILL)
+	// Closing the read end unblocks the reader even when a command left a
+	// process outside the group holding the pipe open.
+	_ = s.outFile.Close()
+}
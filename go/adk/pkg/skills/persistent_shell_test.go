@@ -0,0 +1,85 @@
+package skills
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestPersistentShells(t *testing.T) *PersistentShells {
+	t.Helper()
+	installFakeSRT(t)
+	t.Setenv(EnvPersistentShell, "true")
+	shells, err := NewPersistentShellsFromEnv()
+	if err != nil {
+		t.Fatalf("NewPersistentShellsFromEnv() error = %v", err)
+	}
+	t.Cleanup(shells.CloseAll)
+	return shells
+}
+
+func TestPersistentShells_KeepsStateBetweenCommands(t *testing.T) {
+	shells := newTestPersistentShells(t)
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	for _, command := range []string{"cd sub", "export GREETING=hello", "greet() { echo \"$GREETING from $(basename \"$PWD\")\"; }"} {
+		if _, err := shells.ExecuteCommand(ctx, "session-1", command, dir, nil); err != nil {
+			t.Fatalf("ExecuteCommand(%q) error = %v", command, err)
+		}
+	}
+	got, err := shells.ExecuteCommand(ctx, "session-1", "greet", dir, nil)
+	if err != nil || got != "hello from sub" {
+		t.Fatalf("greet = %q, %v; want %q", got, err, "hello from sub")
+	}
+
+	// Other sessions get their own shell.
+	got, err = shells.ExecuteCommand(ctx, "session-2", "echo \"${GREETING:-unset}\"", dir, nil)
+	if err != nil || got != "unset" {
+		t.Fatalf("other session = %q, %v; want unset", got, err)
+	}
+}
+
+func TestPersistentShells_Failures(t *testing.T) {
+	shells := newTestPersistentShells(t)
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	_, err := shells.ExecuteCommand(ctx, "s", "echo oops >&2; false", dir, nil)
+	if err == nil || !strings.Contains(err.Error(), "exit code 1") || !strings.Contains(err.Error(), "oops") {
+		t.Fatalf("failing command error = %v, want exit code 1 with its output", err)
+	}
+
+	if _, err := shells.ExecuteCommand(ctx, "s", "export KEEP=1; exit 3", dir, nil); err == nil {
+		t.Fatal("exit should be reported as an error")
+	}
+	got, err := shells.ExecuteCommand(ctx, "s", "echo \"${KEEP:-fresh}\"", dir, nil)
+	if err != nil || got != "fresh" {
+		t.Fatalf("after exit = %q, %v; want a fresh shell", got, err)
+	}
+}
+
+func TestPersistentShells_StreamsOutput(t *testing.T) {
+	shells := newTestPersistentShells(t)
+	var streamed strings.Builder
+	got, err := shells.ExecuteCommand(context.Background(), "s", "echo one; echo two", t.TempDir(), &streamed)
+	if err != nil || got != "one\ntwo" {
+		t.Fatalf("ExecuteCommand() = %q, %v", got, err)
+	}
+	if streamed.String() != "one\ntwo\n" {
+		t.Errorf("streamed = %q, want both lines", streamed.String())
+	}
+}
+
+func TestPersistentShells_DisabledByDefault(t *testing.T) {
+	t.Setenv(EnvPersistentShell, "")
+	shells, err := NewPersistentShellsFromEnv()
+	if err != nil || shells != nil {
+		t.Fatalf("NewPersistentShellsFromEnv() = %v, %v; want nil, nil", shells, err)
+	}
+}
@@ -0,0 +1,239 @@
+package skills
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// EnvPythonPipAllowlist lists the packages execute_python may pip install,
+// separated by commas. Names are compared after PEP 503 normalization.
+// Unset or empty disables pip installs.
+const EnvPythonPipAllowlist = "KAGENT_PYTHON_PIP_ALLOWLIST"
+
+const (
+	// DefaultPythonTimeout is how long a snippet may run when the caller
+	// does not ask for a timeout.
+	DefaultPythonTimeout = 60 * time.Second
+	// MaxPythonTimeout caps the timeout a caller may ask for.
+	MaxPythonTimeout = 5 * time.Minute
+
+	pipInstallTimeout = 5 * time.Minute
+	// pythonVenvDir is the session's virtual environment, relative to the
+	// session directory. It is created on the first pip install and inherits
+	// the system site packages.
+	pythonVenvDir = ".venv"
+)
+
+var (
+	pipNameRe      = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9._-]*[A-Za-z0-9])?`)
+	pipSpecifierRe = regexp.MustCompile(`^(\[[A-Za-z0-9._,-]+\])?((==|!=|<=|>=|~=|<|>)[A-Za-z0-9.*+!_-]+(,(==|!=|<=|>=|~=|<|>)[A-Za-z0-9.*+!_-]+)*)?$`)
+	pipSeparatorRe = regexp.MustCompile(`[-_.]+`)
+)
+
+// PythonExecutor runs Python snippets in the sandbox, using the session's
+// virtual environment once packages have been installed into it.
+type PythonExecutor struct {
+	srtArgs      []string
+	pipAllowlist map[string]bool
+}
+
+// PythonResult is the outcome of a snippet that ran to completion.
+type PythonResult struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int
+	// Files lists the files under outputs/ the snippet created or modified,
+	// relative to the session directory.
+	Files []string
+}
+
+// NewPythonExecutorFromEnv builds a PythonExecutor using the srt settings
+// and KAGENT_PYTHON_PIP_ALLOWLIST.
+func NewPythonExecutorFromEnv() (*PythonExecutor, error) {
+	srtArgs, err := resolveSRTSettingsArgs()
+	if err != nil {
+		return nil, err
+	}
+	allowlist := map[string]bool{}
+	for name := range strings.SplitSeq(os.Getenv(EnvPythonPipAllowlist), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			allowlist[normalizePipName(name)] = true
+		}
+	}
+	return &PythonExecutor{srtArgs: srtArgs, pipAllowlist: allowlist}, nil
+}
+
+// PipAllowlist returns the packages that may be installed, sorted.
+func (p *PythonExecutor) PipAllowlist() []string {
+	names := make([]string, 0, len(p.pipAllowlist))
+	for name := range p.pipAllowlist {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Install pip installs packages into the session's virtual environment,
+// creating it first if needed. Every package must be on the allowlist;
+// version specifiers and extras are allowed, URLs, paths and pip options are
+// not. Installer output is copied to stream when it is not nil.
+func (p *PythonExecutor) Install(ctx context.Context, packages []string, workingDir string, stream io.Writer) (string, error) {
+	if len(packages) == 0 {
+		return "", nil
+	}
+	for _, pkg := range packages {
+		if err := p.checkPipPackage(pkg); err != nil {
+			return "", err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, pipInstallTimeout)
+	defer cancel()
+
+	var out bytes.Buffer
+	var w io.Writer = &out
+	if stream != nil {
+		w = io.MultiWriter(&out, stream)
+	}
+	if _, err := os.Stat(filepath.Join(workingDir, pythonVenvDir, "bin", "python")); err != nil {
+		if err := p.run(ctx, workingDir, nil, w, w, "python3", "-m", "venv", "--system-site-packages", pythonVenvDir); err != nil {
+			return "", pythonCommandError(ctx, "creating the virtual environment", err, out.String())
+		}
+	}
+	args := append([]string{"-m", "pip", "install", "--disable-pip-version-check", "--no-input"}, packages...)
+	if err := p.run(ctx, workingDir, nil, w, w, p.interpreter(workingDir), args...); err != nil {
+		return "", pythonCommandError(ctx, "pip install", err, out.String())
+	}
+	return strings.TrimSpace(out.String()), nil
+}
+
+// Run executes code with the session's interpreter in workingDir. The code
+// is passed on stdin, so it may be of any size. A snippet that exits with a
+// non-zero status is reported through PythonResult.ExitCode; an error means
+// the snippet timed out or could not be started. Output is copied to stream
+// as it is written when stream is not nil; stream must then be safe for
+// concurrent use.
+func (p *PythonExecutor) Run(ctx context.Context, code, workingDir string, timeout time.Duration, stream io.Writer) (*PythonResult, error) {
+	if timeout <= 0 {
+		timeout = DefaultPythonTimeout
+	}
+	timeout = min(timeout, MaxPythonTimeout)
+
+	outputsDir := filepath.Join(workingDir, "outputs")
+	before := snapshotFiles(outputsDir)
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	var stdoutW, stderrW io.Writer = &stdout, &stderr
+	if stream != nil {
+		stdoutW = io.MultiWriter(&stdout, stream)
+		stderrW = io.MultiWriter(&stderr, stream)
+	}
+	err := p.run(ctx, workingDir, strings.NewReader(code), stdoutW, stderrW, p.interpreter(workingDir), "-")
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("python timed out after %v", timeout)
+	}
+	result := &PythonResult{Stdout: stdout.String(), Stderr: stderr.String()}
+	if err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("failed to run python: %w", err)
+		}
+		result.ExitCode = exitErr.ExitCode()
+	}
+
+	for path, stamp := range snapshotFiles(outputsDir) {
+		if prev, ok := before[path]; !ok || prev != stamp {
+			rel, err := filepath.Rel(workingDir, path)
+			if err != nil {
+				continue
+			}
+			result.Files = append(result.Files, filepath.ToSlash(rel))
+		}
+	}
+	slices.Sort(result.Files)
+	return result, nil
+}
+
+func (p *PythonExecutor) run(ctx context.Context, workingDir string, stdin io.Reader, stdout, stderr io.Writer, name string, args ...string) error {
+	srtArgs := append(append(append([]string{}, p.srtArgs...), name), args...)
+	cmd := exec.CommandContext(ctx, "srt", srtArgs...)
+	cmd.Dir = workingDir
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	return cmd.Run()
+}
+
+// interpreter returns the session's venv python once the venv exists, or
+// the system python3.
+func (p *PythonExecutor) interpreter(workingDir string) string {
+	venvPython := filepath.Join(pythonVenvDir, "bin", "python")
+	if _, err := os.Stat(filepath.Join(workingDir, venvPython)); err == nil {
+		return venvPython
+	}
+	return "python3"
+}
+
+func (p *PythonExecutor) checkPipPackage(pkg string) error {
+	name := pipNameRe.FindString(pkg)
+	if name == "" || !pipSpecifierRe.MatchString(pkg[len(name):]) {
+		return fmt.Errorf("invalid package %q: give a package name with an optional version specifier", pkg)
+	}
+	if !p.pipAllowlist[normalizePipName(name)] {
+		if len(p.pipAllowlist) == 0 {
+			return fmt.Errorf("package %q is not allowed: pip installs are disabled", pkg)
+		}
+		return fmt.Errorf("package %q is not allowed; allowed packages: %s", pkg, strings.Join(p.PipAllowlist(), ", "))
+	}
+	return nil
+}
+
+// normalizePipName normalizes a package name as described in PEP 503.
+func normalizePipName(name string) string {
+	return pipSeparatorRe.ReplaceAllString(strings.ToLower(name), "-")
+}
+
+func pythonCommandError(ctx context.Context, step string, err error, output string) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%s timed out after %v", step, pipInstallTimeout)
+	}
+	if output = strings.TrimSpace(output); output != "" {
+		return fmt.Errorf("%s failed: %w:\n%s", step, err, output)
+	}
+	return fmt.Errorf("%s failed: %w", step, err)
+}
+
+type fileStamp struct {
+	size    int64
+	modTime time.Time
+}
+
+// snapshotFiles returns the size and modification time of every regular
+// file under dir.
+func snapshotFiles(dir string) map[string]fileStamp {
+	files := map[string]fileStamp{}
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			files[path] = fileStamp{size: info.Size(), modTime: info.ModTime()}
+		}
+		return nil
+	})
+	return files
+}
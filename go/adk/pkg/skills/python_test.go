@@ -0,0 +1,91 @@
+package skills
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestPythonExecutor(t *testing.T, allowlist string) *PythonExecutor {
+	t.Helper()
+	if _, err := exec.LookPath("python3"); err != nil {
+		t.Skip("python3 is not installed")
+	}
+	installFakeSRT(t)
+	t.Setenv(EnvPythonPipAllowlist, allowlist)
+	python, err := NewPythonExecutorFromEnv()
+	if err != nil {
+		t.Fatalf("NewPythonExecutorFromEnv() error = %v", err)
+	}
+	return python
+}
+
+func TestPythonExecutor_Run(t *testing.T) {
+	python := newTestPythonExecutor(t, "")
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "outputs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "outputs", "old.txt"), []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	code := `import sys
+print("hello")
+print("warn", file=sys.stderr)
+open("outputs/report.csv", "w").write("a,b\n")
+open("scratch.txt", "w").write("not an output")
+sys.exit(3)
+`
+	var streamed strings.Builder
+	result, err := python.Run(context.Background(), code, dir, 0, &streamed)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.Stdout != "hello\n" || result.Stderr != "warn\n" || result.ExitCode != 3 {
+		t.Errorf("Run() = %+v, want stdout, stderr and exit code 3", result)
+	}
+	if !slices.Equal(result.Files, []string{"outputs/report.csv"}) {
+		t.Errorf("Files = %v, want only the new output", result.Files)
+	}
+	if !strings.Contains(streamed.String(), "hello") || !strings.Contains(streamed.String(), "warn") {
+		t.Errorf("streamed = %q, want stdout and stderr", streamed.String())
+	}
+}
+
+func TestPythonExecutor_Timeout(t *testing.T) {
+	python := newTestPythonExecutor(t, "")
+	_, err := python.Run(context.Background(), "import time\ntime.sleep(10)\n", t.TempDir(), 200*time.Millisecond, nil)
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("Run() error = %v, want a timeout", err)
+	}
+}
+
+func TestPythonExecutor_InstallAllowlist(t *testing.T) {
+	python := newTestPythonExecutor(t, "Requests, python_dateutil")
+	dir := t.TempDir()
+
+	for _, pkg := range []string{"numpy", "requests; rm -rf /", "--index-url=http://evil", "git+https://example.com/x.git", "./local"} {
+		if _, err := python.Install(context.Background(), []string{pkg}, dir, nil); err == nil {
+			t.Errorf("Install(%q) should be rejected", pkg)
+		}
+	}
+	for _, pkg := range []string{"requests", "requests==2.32.0", "Python.Dateutil>=2.8,<3", "requests[socks]"} {
+		if err := python.checkPipPackage(pkg); err != nil {
+			t.Errorf("checkPipPackage(%q) error = %v", pkg, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, pythonVenvDir)); !os.IsNotExist(err) {
+		t.Errorf("rejected installs should not create the virtual environment")
+	}
+
+	python = newTestPythonExecutor(t, "")
+	if _, err := python.Install(context.Background(), []string{"requests"}, dir, nil); err == nil || !strings.Contains(err.Error(), "disabled") {
+		t.Errorf("Install() error = %v, want pip installs disabled", err)
+	}
+}
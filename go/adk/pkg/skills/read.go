@@ -0,0 +1,136 @@
+package skills
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultReadLimit is the number of lines ReadFileContent returns when
+	// no limit is given.
+	DefaultReadLimit = 2000
+	// MaxReadBytes caps the output of one ReadFileContent call; longer
+	// reads stop early with a marker telling where to continue.
+	MaxReadBytes = 256 << 10
+	// maxLineLength is the length long lines are cut to.
+	maxLineLength = 2000
+	// sniffBytes is how much of a file is inspected to detect binary
+	// content, and binaryPreviewBytes how much of a binary file is shown.
+	sniffBytes         = 8 << 10
+	binaryPreviewBytes = 256
+)
+
+// ReadFileContent reads a file with line numbers, starting at the 1-based
+// line offset and returning at most limit lines (DefaultReadLimit when limit
+// is not positive) and about MaxReadBytes. When lines remain, the result
+// ends with a marker giving the offset to continue from. Binary files are
+// summarized with their MIME type, size and a hex dump of their start.
+func ReadFileContent(path string, offset, limit int) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReaderSize(file, 2*sniffBytes)
+	head, err := reader.Peek(sniffBytes)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return "", err
+	}
+	if len(head) == 0 {
+		return "File is empty.", nil
+	}
+	if isBinary(head) {
+		info, err := file.Stat()
+		if err != nil {
+			return "", err
+		}
+		return binarySummary(path, info.Size(), head), nil
+	}
+
+	if limit <= 0 {
+		limit = DefaultReadLimit
+	}
+	start := max(offset, 1)
+
+	var result strings.Builder
+	lineNum, last := 0, 0
+	for {
+		line, err := reader.ReadString('\n')
+		if line == "" && err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return "", err
+		}
+		lineNum++
+		if lineNum < start || last > 0 {
+			continue
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if len(line) > maxLineLength {
+			line = truncateRunes(line, maxLineLength) + "... [line truncated]"
+		}
+		fmt.Fprintf(&result, "%6d|%s\n", lineNum, line)
+		if lineNum-start+1 >= limit || result.Len() >= MaxReadBytes {
+			last = lineNum
+		}
+	}
+
+	if start > lineNum {
+		return fmt.Sprintf("File has %d lines; offset %d is past the end.", lineNum, start), nil
+	}
+	if last > 0 && last < lineNum {
+		fmt.Fprintf(&result, "... [showing lines %d-%d of %d; call read_file again with offset=%d to continue]", start, last, lineNum, last+1)
+	}
+	return strings.TrimSuffix(result.String(), "\n"), nil
+}
+
+// isBinary reports whether head, the start of a file, looks like binary
+// content: it contains a NUL byte or is not valid UTF-8.
+func isBinary(head []byte) bool {
+	if bytes.IndexByte(head, 0) >= 0 {
+		return true
+	}
+	// The sniffed prefix may end inside a multi-byte rune.
+	for i := 0; i < utf8.UTFMax && len(head) > 0; i++ {
+		if utf8.Valid(head) {
+			return false
+		}
+		head = head[:len(head)-1]
+	}
+	return !utf8.Valid(head)
+}
+
+// DetectMIMEType returns the MIME type of a file from its extension, or
+// from its content when the extension is unknown.
+func DetectMIMEType(path string, head []byte) string {
+	if mimeType := mime.TypeByExtension(filepath.Ext(path)); mimeType != "" {
+		return mimeType
+	}
+	return http.DetectContentType(head)
+}
+
+func binarySummary(path string, size int64, head []byte) string {
+	preview := head[:min(len(head), binaryPreviewBytes)]
+	return fmt.Sprintf("Binary file, not shown as text.\nMIME type: %s\nSize: %d bytes\n\nFirst %d bytes:\n%s",
+		DetectMIMEType(path, head), size, len(preview), strings.TrimSuffix(hex.Dump(preview), "\n"))
+}
+
+// truncateRunes cuts s to at most n bytes without splitting a rune.
+func truncateRunes(s string, n int) string {
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
@@ -1,10 +1,10 @@
 package skills
 
 import (
-	"bufio"
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -18,46 +18,6 @@ type CommandExecutor struct {
 	srtArgs []string
 }
 
-// ReadFileContent reads a file with line numbers.
-func ReadFileContent(path string, offset, limit int) (string, error) {
-	file, err := os.Open(path)
-	if err != nil {
-		return "", err
-	}
-	defer file.Close()
-
-	var result strings.Builder
-	scanner := bufio.NewScanner(file)
-	lineNum := 1
-	start := max(offset, 1)
-	count := 0
-
-	for scanner.Scan() {
-		if lineNum >= start {
-			line := scanner.Text()
-			if len(line) > 2000 {
-				line = line[:2000] + "..."
-			}
-			fmt.Fprintf(&result, "%6d|%s\n", lineNum, line)
-			count++
-			if limit > 0 && count >= limit {
-				break
-			}
-		}
-		lineNum++
-	}
-
-	if err := scanner.Err(); err != nil {
-		return "", err
-	}
-
-	if result.Len() == 0 {
-		return "File is empty.", nil
-	}
-
-	return strings.TrimSuffix(result.String(), "\n"), nil
-}
-
 // WriteFileContent writes content to a file.
 func WriteFileContent(path string, content string) error {
 	dir := filepath.Dir(path)
@@ -67,45 +27,11 @@ func WriteFileContent(path string, content string) error {
 	return os.WriteFile(path, []byte(content), 0644)
 }
 
-// EditFileContent performs an exact string replacement in a file.
+// EditFileContent replaces oldString with newString in a file, falling back
+// to fuzzy matching when oldString does not occur verbatim.
 func EditFileContent(path string, oldString, newString string, replaceAll bool) error {
-	if oldString == newString {
-		return fmt.Errorf("old_string and new_string must be different")
-	}
-
-	content, err := os.ReadFile(path)
-	if err != nil {
-		return err
-	}
-
-	contentStr := string(content)
-	if !strings.Contains(contentStr, oldString) {
-		return fmt.Errorf("old_string not found in %s", path)
-	}
-
-	count := strings.Count(contentStr, oldString)
-	// If there are multiple occurrences and replaceAll is false, we need to check
-	// if the old_string is ambiguous (very short or appears in many contexts)
-	// For now, we'll allow single replacement even with multiple occurrences
-	// as the test "single_replacement" expects this behavior
-	// But we'll error if it's clearly ambiguous (like single character or very short word)
-	if !replaceAll && count > 1 {
-		// Only error for very short/ambiguous strings (less than 4 chars)
-		// This allows "old text" (9 chars) to work but "line" (4 chars) to error
-		if len(strings.TrimSpace(oldString)) < 5 {
-			return fmt.Errorf("old_string appears %d times in %s. Provide more context or set replace_all=true", count, path)
-		}
-	}
-
-	var newContent string
-	if replaceAll {
-		newContent = strings.ReplaceAll(contentStr, oldString, newString)
-	} else {
-		// Replace only the first occurrence
-		newContent = strings.Replace(contentStr, oldString, newString, 1)
-	}
-
-	return os.WriteFile(path, []byte(newContent), 0644)
+	_, err := MultiEditFileContent(path, []Edit{{OldString: oldString, NewString: newString, ReplaceAll: replaceAll}})
+	return err
 }
 
 func resolveSRTSettingsArgs() ([]string, error) {
@@ -116,6 +42,14 @@ func resolveSRTSettingsArgs() ([]string, error) {
 	return []string{"--settings", settingsPath}, nil
 }
 
+// commandTimeout returns how long a bash tool command may run.
+func commandTimeout(command string) time.Duration {
+	if strings.Contains(command, "python") {
+		return 60 * time.Second
+	}
+	return 30 * time.Second
+}
+
 func NewCommandExecutorFromEnv() (*CommandExecutor, error) {
 	srtArgs, err := resolveSRTSettingsArgs()
 	if err != nil {
@@ -126,10 +60,14 @@ func NewCommandExecutorFromEnv() (*CommandExecutor, error) {
 
 // ExecuteCommand executes a shell command.
 func (e *CommandExecutor) ExecuteCommand(ctx context.Context, command string, workingDir string) (string, error) {
-	timeout := 30 * time.Second
-	if strings.Contains(command, "python") {
-		timeout = 60 * time.Second
-	}
+	return e.ExecuteCommandStream(ctx, command, workingDir, nil)
+}
+
+// ExecuteCommandStream executes a shell command like ExecuteCommand and also
+// copies its stdout and stderr to stream as they are written. stream may be
+// nil; otherwise it must be safe for concurrent use.
+func (e *CommandExecutor) ExecuteCommandStream(ctx context.Context, command string, workingDir string, stream io.Writer) (string, error) {
+	timeout := commandTimeout(command)
 
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
@@ -141,6 +79,10 @@ func (e *CommandExecutor) ExecuteCommand(ctx context.Context, command string, wo
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
+	if stream != nil {
+		cmd.Stdout = io.MultiWriter(&stdout, stream)
+		cmd.Stderr = io.MultiWriter(&stderr, stream)
+	}
 
 	err := cmd.Run()
 	if ctx.Err() == context.DeadlineExceeded {
@@ -2,11 +2,13 @@ package skills
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 	"time"
+	"unicode/utf8"
 )
 
 func createTempDir(t *testing.T) string {
@@ -95,8 +97,11 @@ func TestReadFileContent(t *testing.T) {
 			limit:  2,
 			checkFn: func(t *testing.T, result string) {
 				lines := strings.Split(result, "\n")
-				if len(lines) != 2 {
-					t.Errorf("Expected 2 lines, got %d", len(lines))
+				if len(lines) != 3 {
+					t.Errorf("Expected 2 lines and a truncation marker, got %d lines", len(lines))
+				}
+				if !strings.Contains(result, "showing lines 1-2 of 5; call read_file again with offset=3") {
+					t.Errorf("Expected a marker to continue at offset 3, got %q", result)
 				}
 			},
 		},
@@ -107,8 +112,8 @@ func TestReadFileContent(t *testing.T) {
 			limit:  2,
 			checkFn: func(t *testing.T, result string) {
 				lines := strings.Split(result, "\n")
-				if len(lines) != 2 {
-					t.Errorf("Expected 2 lines, got %d", len(lines))
+				if len(lines) != 3 {
+					t.Errorf("Expected 2 lines and a truncation marker, got %d lines", len(lines))
 				}
 				if !strings.Contains(result, "line 2") {
 					t.Error("Expected 'line 2' in result")
@@ -163,7 +168,7 @@ func TestReadFileContent(t *testing.T) {
 			if result != "File is empty." {
 				lines := strings.SplitSeq(result, "\n")
 				for line := range lines {
-					if line != "" && !strings.Contains(line, "|") {
+					if line != "" && !strings.Contains(line, "|") && !strings.HasPrefix(line, "... [") {
 						t.Errorf("Expected line number format (number|content), got %q", line)
 					}
 				}
@@ -479,3 +484,55 @@ func TestExecuteCommand_Timeout(t *testing.T) {
 		t.Logf("Note: Got non-empty result on timeout: %q", result)
 	}
 }
+
+func TestReadFileContent_LongAndBinaryFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	var long strings.Builder
+	for i := 1; i <= DefaultReadLimit+10; i++ {
+		fmt.Fprintf(&long, "line %d\n", i)
+	}
+	longPath := filepath.Join(dir, "long.txt")
+	if err := os.WriteFile(longPath, []byte(long.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+	result, err := ReadFileContent(longPath, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantMarker := fmt.Sprintf("... [showing lines 1-%d of %d; call read_file again with offset=%d to continue]", DefaultReadLimit, DefaultReadLimit+10, DefaultReadLimit+1)
+	if !strings.HasSuffix(result, wantMarker) {
+		t.Errorf("ReadFileContent() should end with %q, got %q", wantMarker, result[len(result)-100:])
+	}
+	if result, _ := ReadFileContent(longPath, DefaultReadLimit+1, 0); strings.Contains(result, "showing lines") || !strings.Contains(result, "line 2010") {
+		t.Errorf("reading the rest = %q, want the last lines without a marker", result)
+	}
+	if result, _ := ReadFileContent(longPath, 5000, 0); !strings.Contains(result, "past the end") {
+		t.Errorf("offset past the end = %q", result)
+	}
+
+	wide := strings.Repeat("é", MaxReadBytes)
+	widePath := filepath.Join(dir, "wide.txt")
+	if err := os.WriteFile(widePath, []byte(wide+"\n"+wide+"\nlast\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	result, err = ReadFileContent(widePath, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(result, "line truncated") || !utf8.ValidString(result) {
+		t.Errorf("long lines should be cut on a rune boundary with a marker")
+	}
+
+	pngPath := filepath.Join(dir, "image.png")
+	if err := os.WriteFile(pngPath, []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	result, err = ReadFileContent(pngPath, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(result, "Binary file") || !strings.Contains(result, "MIME type: image/png") || !strings.Contains(result, "89 50 4e 47") {
+		t.Errorf("binary summary = %q, want the MIME type and a hex dump", result)
+	}
+}
@@ -0,0 +1,356 @@
+package skills
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/go-logr/logr"
+)
+
+// Environment variables configuring the workspace file watcher.
+const (
+	// EnvWorkspaceWatch enables the watcher when set to "true".
+	EnvWorkspaceWatch = "KAGENT_WORKSPACE_WATCH"
+	// EnvWorkspaceWatchDebounce is how long a session's workspace must be
+	// quiet before its changes are reported, as a Go duration. Defaults to
+	// 2s.
+	EnvWorkspaceWatchDebounce = "KAGENT_WORKSPACE_WATCH_DEBOUNCE"
+	// EnvWorkspaceWatchInclude lists glob patterns, separated by commas, of
+	// the paths to report. Unset reports every path.
+	EnvWorkspaceWatchInclude = "KAGENT_WORKSPACE_WATCH_INCLUDE"
+	// EnvWorkspaceWatchExclude lists glob patterns, separated by commas, of
+	// paths never to report. They are added to the default exclusions of
+	// hidden files and directories such as .git and .venv.
+	EnvWorkspaceWatchExclude = "KAGENT_WORKSPACE_WATCH_EXCLUDE"
+)
+
+const defaultWatchDebounce = 2 * time.Second
+
+// WatchConfig configures the workspace file watcher. Patterns use
+// path.Match syntax and are matched against the path relative to the
+// session workspace and against each of its directory prefixes, so
+// "outputs" matches everything under outputs/ and "*.py" matches Python
+// files at the top level.
+type WatchConfig struct {
+	Enabled  bool
+	Debounce time.Duration
+	Include  []string
+	Exclude  []string
+}
+
+// WatchConfigFromEnv reads the watcher configuration from the environment.
+func WatchConfigFromEnv() (WatchConfig, error) {
+	cfg := WatchConfig{
+		Enabled:  strings.EqualFold(strings.TrimSpace(os.Getenv(EnvWorkspaceWatch)), "true"),
+		Debounce: defaultWatchDebounce,
+		Include:  splitPatterns(os.Getenv(EnvWorkspaceWatchInclude)),
+		Exclude:  splitPatterns(os.Getenv(EnvWorkspaceWatchExclude)),
+	}
+	if v := strings.TrimSpace(os.Getenv(EnvWorkspaceWatchDebounce)); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return WatchConfig{}, fmt.Errorf("invalid %s %q: must be a positive duration", EnvWorkspaceWatchDebounce, v)
+		}
+		cfg.Debounce = d
+	}
+	for _, pattern := range slices.Concat(cfg.Include, cfg.Exclude) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return WatchConfig{}, fmt.Errorf("invalid workspace watch pattern %q: %w", pattern, err)
+		}
+	}
+	return cfg, nil
+}
+
+func splitPatterns(v string) []string {
+	var patterns []string
+	for p := range strings.SplitSeq(v, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			patterns = append(patterns, p)
+		}
+	}
+	return patterns
+}
+
+// File change kinds reported by the watcher.
+const (
+	FileCreated  = "created"
+	FileModified = "modified"
+	FileRemoved  = "removed"
+)
+
+// FileChange is a change to a file in a session workspace.
+type FileChange struct {
+	// Path is relative to the session workspace, with forward slashes.
+	Path string
+	// Kind is FileCreated, FileModified or FileRemoved.
+	Kind string
+}
+
+// WorkspaceEventAuthor is the author of the session events reporting
+// workspace changes.
+const WorkspaceEventAuthor = "workspace"
+
+// DescribeFileChanges renders changes as the text of a session event.
+func DescribeFileChanges(changes []FileChange) string {
+	var b strings.Builder
+	b.WriteString("Files in the session workspace changed:")
+	for _, c := range changes {
+		fmt.Fprintf(&b, "\n- %s %s", c.Kind, c.Path)
+	}
+	return b.String()
+}
+
+// FileChangeFunc receives the changes to a session's workspace once it has
+// been quiet for the debounce period. Changes are sorted by path.
+type FileChangeFunc func(ctx context.Context, sessionID, userID string, changes []FileChange) error
+
+// WorkspaceWatcher watches every session workspace under a base directory
+// and reports file changes per session, whether made by tools or by other
+// processes. Only workspaces with a recorded user are reported.
+type WorkspaceWatcher struct {
+	baseDir  string
+	config   WatchConfig
+	onChange FileChangeFunc
+
+	watcher *fsnotify.Watcher
+
+	mu      sync.Mutex
+	pending map[string]*pendingChanges
+}
+
+type pendingChanges struct {
+	changes map[string]string
+	timer   *time.Timer
+}
+
+// NewWorkspaceWatcher returns a watcher for the workspaces under baseDir.
+func NewWorkspaceWatcher(baseDir string, config WatchConfig, onChange FileChangeFunc) (*WorkspaceWatcher, error) {
+	if config.Debounce <= 0 {
+		config.Debounce = defaultWatchDebounce
+	}
+	if err := os.MkdirAll(baseDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create workspace directory: %w", err)
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file watcher: %w", err)
+	}
+	w := &WorkspaceWatcher{
+		baseDir:  filepath.Clean(baseDir),
+		config:   config,
+		onChange: onChange,
+		watcher:  watcher,
+		pending:  make(map[string]*pendingChanges),
+	}
+	if err := w.addTree(w.baseDir); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+// Start processes file events until ctx is done, then closes the watcher.
+func (w *WorkspaceWatcher) Start(ctx context.Context) {
+	log := logr.FromContextOrDiscard(ctx).WithName("workspace-watcher")
+	go func() {
+		defer w.watcher.Close()
+		for {
+			select {
+			case <-ctx.Done():
+				w.mu.Lock()
+				for _, p := range w.pending {
+					p.timer.Stop()
+				}
+				w.mu.Unlock()
+				return
+			case event, ok := <-w.watcher.Events:
+				if !ok {
+					return
+				}
+				w.handle(ctx, log, event)
+			case err, ok := <-w.watcher.Errors:
+				if !ok {
+					return
+				}
+				log.Error(err, "Workspace watcher error")
+			}
+		}
+	}()
+}
+
+func (w *WorkspaceWatcher) handle(ctx context.Context, log logr.Logger, event fsnotify.Event) {
+	rel, err := filepath.Rel(w.baseDir, event.Name)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return
+	}
+	sessionID, filePath, _ := strings.Cut(filepath.ToSlash(rel), "/")
+
+	if isHiddenPath(filePath) {
+		return
+	}
+	if event.Has(fsnotify.Create) {
+		if info, err := os.Lstat(event.Name); err == nil && info.IsDir() {
+			// Watch new directories, and report files created in them
+			// before the watch was in place.
+			if err := w.addTree(event.Name); err != nil {
+				log.Error(err, "Failed to watch workspace directory", "path", event.Name)
+			}
+			if filePath != "" {
+				_ = filepath.WalkDir(event.Name, func(p string, d fs.DirEntry, err error) error {
+					if err == nil && d.Type().IsRegular() {
+						if r, err := filepath.Rel(w.baseDir, p); err == nil {
+							_, f, _ := strings.Cut(filepath.ToSlash(r), "/")
+							w.record(ctx, log, sessionID, f, FileCreated)
+						}
+					}
+					return nil
+				})
+			}
+			return
+		}
+	}
+	if filePath == "" {
+		return
+	}
+
+	switch {
+	case event.Has(fsnotify.Create):
+		w.record(ctx, log, sessionID, filePath, FileCreated)
+	case event.Has(fsnotify.Write):
+		w.record(ctx, log, sessionID, filePath, FileModified)
+	case event.Has(fsnotify.Remove), event.Has(fsnotify.Rename):
+		w.record(ctx, log, sessionID, filePath, FileRemoved)
+	}
+}
+
+// record adds a change to the session's pending changes and restarts its
+// debounce timer.
+func (w *WorkspaceWatcher) record(ctx context.Context, log logr.Logger, sessionID, filePath, kind string) {
+	if !w.matches(filePath) {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	p := w.pending[sessionID]
+	if p == nil {
+		p = &pendingChanges{changes: map[string]string{}}
+		p.timer = time.AfterFunc(w.config.Debounce, func() { w.flush(ctx, log, sessionID) })
+		w.pending[sessionID] = p
+	} else {
+		p.timer.Reset(w.config.Debounce)
+	}
+	p.changes[filePath] = mergeChange(p.changes[filePath], kind)
+}
+
+// mergeChange combines the change already pending for a file with a new
+// one. An empty result means the file is back to how it was.
+func mergeChange(prev, next string) string {
+	switch {
+	case prev == FileCreated && next == FileRemoved:
+		return ""
+	case prev == FileCreated:
+		return FileCreated
+	case prev == FileRemoved && next == FileCreated:
+		return FileModified
+	default:
+		return next
+	}
+}
+
+func (w *WorkspaceWatcher) flush(ctx context.Context, log logr.Logger, sessionID string) {
+	w.mu.Lock()
+	p := w.pending[sessionID]
+	delete(w.pending, sessionID)
+	w.mu.Unlock()
+	if p == nil || ctx.Err() != nil {
+		return
+	}
+
+	var changes []FileChange
+	for filePath, kind := range p.changes {
+		if kind != "" {
+			changes = append(changes, FileChange{Path: filePath, Kind: kind})
+		}
+	}
+	if len(changes) == 0 || w.onChange == nil {
+		return
+	}
+	slices.SortFunc(changes, func(a, b FileChange) int { return strings.Compare(a.Path, b.Path) })
+
+	userID, err := os.ReadFile(filepath.Join(w.baseDir, sessionID, sessionUserFile))
+	if err != nil || len(userID) == 0 {
+		return
+	}
+	if err := w.onChange(ctx, sessionID, string(userID), changes); err != nil {
+		log.Error(err, "Failed to report workspace changes", "sessionID", sessionID)
+	}
+}
+
+// matches applies the include and exclude patterns, and the default
+// exclusion of hidden paths, to a path relative to the session workspace.
+func (w *WorkspaceWatcher) matches(filePath string) bool {
+	if isHiddenPath(filePath) {
+		return false
+	}
+	if matchAnyPrefix(w.config.Exclude, filePath) {
+		return false
+	}
+	return len(w.config.Include) == 0 || matchAnyPrefix(w.config.Include, filePath)
+}
+
+// isHiddenPath reports whether a component of filePath starts with a dot.
+func isHiddenPath(filePath string) bool {
+	for part := range strings.SplitSeq(filePath, "/") {
+		if strings.HasPrefix(part, ".") {
+			return true
+		}
+	}
+	return false
+}
+
+// matchAnyPrefix reports whether a pattern matches filePath or one of the
+// directories containing it.
+func matchAnyPrefix(patterns []string, filePath string) bool {
+	for candidate := filePath; candidate != "."; candidate = path.Dir(candidate) {
+		for _, pattern := range patterns {
+			if ok, _ := path.Match(pattern, candidate); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// addTree watches dir and the directories below it. Symlinks, such as the
+// skills directory link, are not followed, and hidden directories are
+// skipped.
+func (w *WorkspaceWatcher) addTree(dir string) error {
+	return filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if p != dir && strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+		if err := w.watcher.Add(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to watch %s: %w", p, err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,142 @@
+package skills
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWorkspaceWatcher(t *testing.T) {
+	baseDir := t.TempDir()
+	sessionDir := filepath.Join(baseDir, "session-1")
+	if err := os.MkdirAll(filepath.Join(sessionDir, "outputs"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := RecordSessionUser(sessionDir, "user-1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(sessionDir, "outputs", "old.txt"), []byte("old"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	type report struct {
+		sessionID, userID string
+		changes           []FileChange
+	}
+	var mu sync.Mutex
+	var reports []report
+	watcher, err := NewWorkspaceWatcher(baseDir, WatchConfig{
+		Debounce: 100 * time.Millisecond,
+		Exclude:  []string{"*.tmp"},
+	}, func(_ context.Context, sessionID, userID string, changes []FileChange) error {
+		mu.Lock()
+		defer mu.Unlock()
+		reports = append(reports, report{sessionID, userID, changes})
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	watcher.Start(ctx)
+
+	write := func(rel, content string) {
+		t.Helper()
+		p := filepath.Join(sessionDir, rel)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("outputs/old.txt", "changed")
+	write("report.md", "draft")
+	write("report.md", "final")
+	write("scratch.tmp", "ignored")
+	write(".venv/lib/site.py", "hidden")
+	write("src/pkg/new.go", "package pkg")
+	write("gone.txt", "short-lived")
+	if err := os.Remove(filepath.Join(sessionDir, "gone.txt")); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		mu.Lock()
+		n := len(reports)
+		mu.Unlock()
+		if n > 0 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	time.Sleep(200 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(reports) != 1 {
+		t.Fatalf("got %d reports, want one debounced report: %+v", len(reports), reports)
+	}
+	got := reports[0]
+	if got.sessionID != "session-1" || got.userID != "user-1" {
+		t.Errorf("report for %s/%s, want session-1/user-1", got.sessionID, got.userID)
+	}
+	want := []FileChange{
+		{Path: "outputs/old.txt", Kind: FileModified},
+		{Path: "report.md", Kind: FileCreated},
+		{Path: "src/pkg/new.go", Kind: FileCreated},
+	}
+	if !slices.Equal(got.changes, want) {
+		t.Errorf("changes = %+v, want %+v", got.changes, want)
+	}
+}
+
+func TestWorkspaceWatcher_Matches(t *testing.T) {
+	w := &WorkspaceWatcher{config: WatchConfig{Include: []string{"outputs", "*.py"}, Exclude: []string{"outputs/cache"}}}
+	for path, want := range map[string]bool{
+		"outputs/a.csv":       true,
+		"outputs/sub/b.png":   true,
+		"main.py":             true,
+		"lib/main.py":         false,
+		"notes.md":            false,
+		"outputs/cache/x.bin": false,
+		".git/HEAD":           false,
+	} {
+		if got := w.matches(path); got != want {
+			t.Errorf("matches(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestWatchConfigFromEnv(t *testing.T) {
+	t.Setenv(EnvWorkspaceWatch, "true")
+	t.Setenv(EnvWorkspaceWatchDebounce, "500ms")
+	t.Setenv(EnvWorkspaceWatchInclude, "outputs, *.py")
+	t.Setenv(EnvWorkspaceWatchExclude, "")
+	cfg, err := WatchConfigFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.Enabled || cfg.Debounce != 500*time.Millisecond || !slices.Equal(cfg.Include, []string{"outputs", "*.py"}) {
+		t.Errorf("WatchConfigFromEnv() = %+v", cfg)
+	}
+
+	t.Setenv(EnvWorkspaceWatchInclude, "[")
+	if _, err := WatchConfigFromEnv(); err == nil {
+		t.Error("an invalid pattern should be rejected")
+	}
+}
+
+func TestDescribeFileChanges(t *testing.T) {
+	got := DescribeFileChanges([]FileChange{{Path: "outputs/a.csv", Kind: FileCreated}, {Path: "main.py", Kind: FileRemoved}})
+	want := "Files in the session workspace changed:\n- created outputs/a.csv\n- removed main.py"
+	if got != want {
+		t.Errorf("DescribeFileChanges() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,202 @@
+package skills
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Environment variables selecting the session workspace backend.
+const (
+	// EnvWorkspaceDir overrides the directory session workspaces are created
+	// in. Point it at a volume shared by all replicas (e.g. a ReadWriteMany
+	// PVC) so a follow-up message finds the session's files whichever replica
+	// serves it. The controller sets it, with the pvc driver, for agents
+	// whose deployment has a workspace claim.
+	EnvWorkspaceDir = "KAGENT_WORKSPACE_DIR"
+	// EnvWorkspaceDriver selects the workspace driver: "local" (default),
+	// "pvc" or "s3". The first two keep workspaces as plain directories;
+	// "pvc" additionally requires the volume to be mounted at
+	// KAGENT_WORKSPACE_DIR. "s3" keeps them in a bucket, with a local copy
+	// under KAGENT_WORKSPACE_DIR. Any other value is rejected.
+	EnvWorkspaceDriver = "KAGENT_WORKSPACE_DRIVER"
+	// EnvWorkspaceQuotaMB caps the size of a single session workspace in
+	// megabytes. Zero or unset means unlimited.
+	EnvWorkspaceQuotaMB = "KAGENT_WORKSPACE_QUOTA_MB"
+)
+
+// Workspace drivers.
+const (
+	WorkspaceDriverLocal = "local"
+	WorkspaceDriverPVC   = "pvc"
+	WorkspaceDriverS3    = "s3"
+)
+
+// ErrWorkspaceQuotaExceeded is returned when a session workspace has grown
+// past its quota.
+var ErrWorkspaceQuotaExceeded = errors.New("session workspace quota exceeded")
+
+// Workspace manages the per-session working directories used by the skills
+// tools.
+type Workspace interface {
+	// Prepare returns the working directory of a session, creating it on
+	// first use. It fails with ErrWorkspaceQuotaExceeded once the workspace
+	// is over quota.
+	Prepare(ctx context.Context, sessionID string) (string, error)
+	// Finalize is called after each invocation of the session completes.
+	Finalize(ctx context.Context, sessionID string) error
+	// Delete removes the session's workspace.
+	Delete(ctx context.Context, sessionID string) error
+}
+
+// WorkspaceBaseDir returns the directory holding session workspaces:
+// $KAGENT_WORKSPACE_DIR when set, or <tmp>/kagent otherwise.
+func WorkspaceBaseDir() string {
+	if dir := strings.TrimSpace(os.Getenv(EnvWorkspaceDir)); dir != "" {
+		return filepath.Clean(dir)
+	}
+	return filepath.Join(os.TempDir(), "kagent")
+}
+
+// NewWorkspaceFromEnv builds the Workspace selected by KAGENT_WORKSPACE_DRIVER.
+func NewWorkspaceFromEnv(skillsDirectory string) (Workspace, error) {
+	var quota int64
+	if v := strings.TrimSpace(os.Getenv(EnvWorkspaceQuotaMB)); v != "" {
+		mb, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || mb < 0 {
+			return nil, fmt.Errorf("invalid %s %q", EnvWorkspaceQuotaMB, v)
+		}
+		quota = mb << 20
+	}
+
+	switch driver := strings.TrimSpace(os.Getenv(EnvWorkspaceDriver)); driver {
+	case "", WorkspaceDriverLocal:
+		return NewDirWorkspace(WorkspaceBaseDir(), skillsDirectory, quota), nil
+	case WorkspaceDriverPVC:
+		// A PVC must already be mounted; never fall back to local disk.
+		dir := strings.TrimSpace(os.Getenv(EnvWorkspaceDir))
+		if dir == "" {
+			return nil, fmt.Errorf("%s=%s requires %s to point at the mounted volume", EnvWorkspaceDriver, WorkspaceDriverPVC, EnvWorkspaceDir)
+		}
+		info, err := os.Stat(dir)
+		if err != nil {
+			return nil, fmt.Errorf("workspace volume %s is not available: %w", dir, err)
+		}
+		if !info.IsDir() {
+			return nil, fmt.Errorf("workspace volume %s is not a directory", dir)
+		}
+		return NewDirWorkspace(dir, skillsDirectory, quota), nil
+	case WorkspaceDriverS3:
+		bucket := strings.TrimSpace(os.Getenv(EnvWorkspaceS3Bucket))
+		if bucket == "" {
+			return nil, fmt.Errorf("%s=%s requires %s", EnvWorkspaceDriver, WorkspaceDriverS3, EnvWorkspaceS3Bucket)
+		}
+		return NewS3Workspace(S3WorkspaceConfig{
+			Bucket:   bucket,
+			Prefix:   strings.TrimSpace(os.Getenv(EnvWorkspaceS3Prefix)),
+			Endpoint: strings.TrimSpace(os.Getenv(EnvWorkspaceS3Endpoint)),
+		}, NewDirWorkspace(WorkspaceBaseDir(), skillsDirectory, quota)), nil
+	default:
+		return nil, fmt.Errorf("unknown %s %q: must be %s, %s or %s", EnvWorkspaceDriver, driver, WorkspaceDriverLocal, WorkspaceDriverPVC, WorkspaceDriverS3)
+	}
+}
+
+// DirWorkspace keeps session workspaces as directories under a base
+// directory, on local disk or on a mounted volume.
+type DirWorkspace struct {
+	baseDir         string
+	skillsDirectory string
+	quotaBytes      int64
+}
+
+// NewDirWorkspace returns a DirWorkspace rooted at baseDir. quotaBytes <= 0
+// disables the per-session quota.
+func NewDirWorkspace(baseDir, skillsDirectory string, quotaBytes int64) *DirWorkspace {
+	return &DirWorkspace{baseDir: baseDir, skillsDirectory: skillsDirectory, quotaBytes: quotaBytes}
+}
+
+func (w *DirWorkspace) Prepare(_ context.Context, sessionID string) (string, error) {
+	dir, err := prepareSessionDir(w.baseDir, sessionID, w.skillsDirectory)
+	if err != nil {
+		return "", err
+	}
+	if err := w.checkQuota(dir); err != nil {
+		return "", err
+	}
+	// The directory's mtime tracks the last use of the session for WorkspaceGC.
+	now := time.Now()
+	_ = os.Chtimes(dir, now, now)
+	return dir, nil
+}
+
+// Finalize reports a workspace that went over quota during the invocation.
+func (w *DirWorkspace) Finalize(_ context.Context, sessionID string) error {
+	dir, err := w.sessionDir(sessionID)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(dir); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return w.checkQuota(dir)
+}
+
+func (w *DirWorkspace) Delete(_ context.Context, sessionID string) error {
+	dir, err := w.sessionDir(sessionID)
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(dir)
+}
+
+func (w *DirWorkspace) sessionDir(sessionID string) (string, error) {
+	if sessionID == "" {
+		return "", fmt.Errorf("sessionID cannot be empty")
+	}
+	dir := filepath.Clean(filepath.Join(w.baseDir, sessionID))
+	if !strings.HasPrefix(dir, filepath.Clean(w.baseDir)+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid sessionID: path traversal detected")
+	}
+	return dir, nil
+}
+
+func (w *DirWorkspace) checkQuota(dir string) error {
+	if w.quotaBytes <= 0 {
+		return nil
+	}
+	size, err := DirSize(dir)
+	if err != nil {
+		return err
+	}
+	if size > w.quotaBytes {
+		return fmt.Errorf("%w: %d bytes used, quota is %d bytes", ErrWorkspaceQuotaExceeded, size, w.quotaBytes)
+	}
+	return nil
+}
+
+// DirSize returns the total size of the regular files under dir. Symlinks,
+// such as the skills link, are not followed.
+func DirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, err
+}
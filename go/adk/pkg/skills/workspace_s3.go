@@ -0,0 +1,462 @@
+package skills
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+)
+
+// Environment variables configuring the s3 workspace driver. Credentials
+// and the region come from the standard AWS configuration (AWS_REGION,
+// AWS_ACCESS_KEY_ID, a web identity token, ...).
+const (
+	// EnvWorkspaceS3Bucket is the bucket holding the session workspaces.
+	EnvWorkspaceS3Bucket = "KAGENT_WORKSPACE_S3_BUCKET"
+	// EnvWorkspaceS3Prefix prefixes the object keys, e.g. with the agent
+	// name when several agents share a bucket.
+	EnvWorkspaceS3Prefix = "KAGENT_WORKSPACE_S3_PREFIX"
+	// EnvWorkspaceS3Endpoint points the driver at an S3-compatible store,
+	// such as MinIO, addressed path-style. Unset uses AWS S3.
+	EnvWorkspaceS3Endpoint = "KAGENT_WORKSPACE_S3_ENDPOINT"
+)
+
+// syncStateFile records, in the local copy of a session workspace, the
+// objects it was last synced with.
+const syncStateFile = ".kagent_sync"
+
+// S3WorkspaceConfig configures an S3Workspace.
+type S3WorkspaceConfig struct {
+	Bucket string
+	Prefix string
+	// Endpoint is the URL of an S3-compatible store. Empty uses AWS S3.
+	Endpoint string
+	// Region defaults to the region of the AWS configuration.
+	Region string
+	// Credentials defaults to those of the AWS configuration.
+	Credentials aws.CredentialsProvider
+	HTTPClient  *http.Client
+}
+
+// S3Workspace keeps session workspaces in an S3 bucket, so any replica can
+// serve a session. Each replica works in a local copy: Prepare downloads the
+// objects changed since the copy was last synced and removes the files
+// deleted from the bucket, and Finalize uploads the files changed by the
+// invocation and deletes the objects of removed files. Replicas serving the
+// same session at the same time are not supported; the last upload wins.
+type S3Workspace struct {
+	local  *DirWorkspace
+	prefix string
+	client *s3Client
+}
+
+// NewS3Workspace returns an S3Workspace keeping its local copies in local.
+func NewS3Workspace(cfg S3WorkspaceConfig, local *DirWorkspace) *S3Workspace {
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &S3Workspace{
+		local:  local,
+		prefix: strings.Trim(cfg.Prefix, "/"),
+		client: &s3Client{
+			endpoint:    strings.TrimSuffix(cfg.Endpoint, "/"),
+			bucket:      cfg.Bucket,
+			region:      cfg.Region,
+			credentials: cfg.Credentials,
+			httpClient:  httpClient,
+			signer: v4.NewSigner(func(o *v4.SignerOptions) {
+				// Object keys are escaped once, as S3 expects.
+				o.DisableURIPathEscaping = true
+			}),
+		},
+	}
+}
+
+func (w *S3Workspace) Prepare(ctx context.Context, sessionID string) (string, error) {
+	dir, err := w.local.sessionDir(sessionID)
+	if err != nil {
+		return "", err
+	}
+	if err := w.download(ctx, sessionID, dir); err != nil {
+		return "", fmt.Errorf("failed to sync session workspace from s3: %w", err)
+	}
+	return w.local.Prepare(ctx, sessionID)
+}
+
+// Finalize uploads the session's changes, then reports a workspace over
+// quota.
+func (w *S3Workspace) Finalize(ctx context.Context, sessionID string) error {
+	dir, err := w.local.sessionDir(sessionID)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(dir); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err := w.upload(ctx, sessionID, dir); err != nil {
+		return fmt.Errorf("failed to sync session workspace to s3: %w", err)
+	}
+	return w.local.Finalize(ctx, sessionID)
+}
+
+func (w *S3Workspace) Delete(ctx context.Context, sessionID string) error {
+	if _, err := w.local.sessionDir(sessionID); err != nil {
+		return err
+	}
+	objects, err := w.client.list(ctx, w.sessionPrefix(sessionID))
+	if err != nil {
+		return err
+	}
+	for _, obj := range objects {
+		if err := w.client.delete(ctx, obj.Key); err != nil {
+			return err
+		}
+	}
+	return w.local.Delete(ctx, sessionID)
+}
+
+func (w *S3Workspace) sessionPrefix(sessionID string) string {
+	return path.Join(w.prefix, sessionID) + "/"
+}
+
+// syncedFile is a file of the local copy as of its last sync.
+type syncedFile struct {
+	ETag    string    `json:"etag"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+}
+
+func readSyncState(dir string) (map[string]syncedFile, error) {
+	state := map[string]syncedFile{}
+	data, err := os.ReadFile(filepath.Join(dir, syncStateFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", syncStateFile, err)
+	}
+	return state, nil
+}
+
+func writeSyncState(dir string, state map[string]syncedFile) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, syncStateFile), data, 0o644)
+}
+
+// download brings the local copy in dir up to date with the bucket.
+func (w *S3Workspace) download(ctx context.Context, sessionID, dir string) error {
+	prefix := w.sessionPrefix(sessionID)
+	objects, err := w.client.list(ctx, prefix)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	state, err := readSyncState(dir)
+	if err != nil {
+		return err
+	}
+
+	remote := map[string]bool{}
+	for _, obj := range objects {
+		rel := strings.TrimPrefix(obj.Key, prefix)
+		// Skip directory markers, and never write into the skills link.
+		if rel == "" || strings.HasSuffix(rel, "/") || rel == syncStateFile || rel == "skills" || strings.HasPrefix(rel, "skills/") {
+			continue
+		}
+		if !filepath.IsLocal(filepath.FromSlash(rel)) {
+			return fmt.Errorf("invalid object key %q", obj.Key)
+		}
+		remote[rel] = true
+		if state[rel].ETag == obj.ETag {
+			continue
+		}
+		file := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := w.client.get(ctx, obj.Key, file); err != nil {
+			return err
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			return err
+		}
+		state[rel] = syncedFile{ETag: obj.ETag, Size: info.Size(), ModTime: info.ModTime()}
+	}
+	// Files synced before but gone from the bucket were deleted by the
+	// replica that served the session last.
+	for rel := range state {
+		if remote[rel] {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, filepath.FromSlash(rel))); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		delete(state, rel)
+	}
+	return writeSyncState(dir, state)
+}
+
+// upload copies the files of dir changed since the last sync to the bucket
+// and deletes the objects of the files removed.
+func (w *S3Workspace) upload(ctx context.Context, sessionID, dir string) error {
+	prefix := w.sessionPrefix(sessionID)
+	state, err := readSyncState(dir)
+	if err != nil {
+		return err
+	}
+
+	seen := map[string]bool{}
+	err = filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		// Symlinks, such as the skills link, are not followed.
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == syncStateFile {
+			return nil
+		}
+		seen[rel] = true
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if s, ok := state[rel]; ok && s.Size == info.Size() && s.ModTime.Equal(info.ModTime()) {
+			return nil
+		}
+		etag, err := w.client.put(ctx, prefix+rel, p, info.Size())
+		if err != nil {
+			return err
+		}
+		state[rel] = syncedFile{ETag: etag, Size: info.Size(), ModTime: info.ModTime()}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for rel := range state {
+		if seen[rel] {
+			continue
+		}
+		if err := w.client.delete(ctx, prefix+rel); err != nil {
+			return err
+		}
+		delete(state, rel)
+	}
+	return writeSyncState(dir, state)
+}
+
+// s3Client is the small part of the S3 API the workspace needs, signed with
+// AWS Signature Version 4.
+type s3Client struct {
+	endpoint    string
+	bucket      string
+	region      string
+	credentials aws.CredentialsProvider
+	httpClient  *http.Client
+	signer      *v4.Signer
+
+	once    sync.Once
+	initErr error
+}
+
+type s3Object struct {
+	Key  string
+	ETag string
+	Size int64
+}
+
+// init loads the region and credentials missing from the configuration
+// from the AWS configuration, on first use.
+func (c *s3Client) init(ctx context.Context) error {
+	c.once.Do(func() {
+		if c.credentials == nil || c.region == "" {
+			cfg, err := awsconfig.LoadDefaultConfig(ctx)
+			if err != nil {
+				c.initErr = fmt.Errorf("failed to load AWS config: %w", err)
+				return
+			}
+			if c.credentials == nil {
+				c.credentials = cfg.Credentials
+			}
+			if c.region == "" {
+				c.region = cfg.Region
+			}
+		}
+		if c.region == "" {
+			c.region = "us-east-1"
+		}
+	})
+	return c.initErr
+}
+
+func (c *s3Client) list(ctx context.Context, prefix string) ([]s3Object, error) {
+	var objects []s3Object
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		resp, err := c.do(ctx, http.MethodGet, "", query, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		var result struct {
+			Contents              []s3Object
+			IsTruncated           bool
+			NextContinuationToken string
+		}
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode s3 object list: %w", err)
+		}
+		objects = append(objects, result.Contents...)
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return objects, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+// get downloads key to file, replacing it only once the download completes.
+func (c *s3Client) get(ctx context.Context, key, file string) error {
+	resp, err := c.do(ctx, http.MethodGet, key, nil, nil, 0)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(file), ".download-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, resp.Body); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to download %s: %w", key, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
+
+// put uploads file to key and returns the object's ETag.
+func (c *s3Client) put(ctx context.Context, key, file string, size int64) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	resp, err := c.do(ctx, http.MethodPut, key, nil, f, size)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+	return resp.Header.Get("ETag"), nil
+}
+
+func (c *s3Client) delete(ctx context.Context, key string) error {
+	resp, err := c.do(ctx, http.MethodDelete, key, nil, nil, 0)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// do sends a signed request for key, or for the bucket when key is empty,
+// and fails on a non-2xx response.
+func (c *s3Client) do(ctx context.Context, method, key string, query url.Values, body io.Reader, size int64) (*http.Response, error) {
+	if err := c.init(ctx); err != nil {
+		return nil, err
+	}
+	base := "https://" + c.bucket + ".s3." + c.region + ".amazonaws.com"
+	if c.endpoint != "" {
+		base = c.endpoint + "/" + c.bucket
+	}
+	if key != "" {
+		base += "/" + escapeObjectKey(key)
+	}
+	u, err := url.Parse(base)
+	if err != nil {
+		return nil, err
+	}
+	// The signer encodes spaces in the query as %20.
+	u.RawQuery = strings.ReplaceAll(query.Encode(), "+", "%20")
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.ContentLength = size
+	}
+	const payloadHash = "UNSIGNED-PAYLOAD"
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	creds, err := c.credentials.Retrieve(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve AWS credentials: %w", err)
+	}
+	if err := c.signer.SignHTTP(ctx, creds, req, payloadHash, "s3", c.region, time.Now()); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		return nil, fmt.Errorf("s3 %s %s: %s: %s", method, key, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return resp, nil
+}
+
+// escapeObjectKey escapes every byte of key but the unreserved characters
+// and the slashes, the encoding S3 signs.
+func escapeObjectKey(key string) string {
+	var b strings.Builder
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || strings.IndexByte("-._~/", c) >= 0 {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
@@ -0,0 +1,169 @@
+package skills
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// fakeS3 serves the path-style S3 calls of the workspace from memory.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 ") {
+		http.Error(w, "unsigned request", http.StatusForbidden)
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key, _ := strings.CutPrefix(r.URL.Path, "/bucket/")
+	switch {
+	case r.Method == http.MethodGet && r.URL.Query().Get("list-type") == "2":
+		type object struct {
+			Key  string
+			ETag string
+			Size int
+		}
+		var result struct {
+			XMLName  xml.Name `xml:"ListBucketResult"`
+			Contents []object
+		}
+		for k, v := range f.objects {
+			if strings.HasPrefix(k, r.URL.Query().Get("prefix")) {
+				result.Contents = append(result.Contents, object{Key: k, ETag: etag(v), Size: len(v)})
+			}
+		}
+		_ = xml.NewEncoder(w).Encode(result)
+	case r.Method == http.MethodGet:
+		v, ok := f.objects[key]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(v)
+	case r.Method == http.MethodPut:
+		v, _ := io.ReadAll(r.Body)
+		f.objects[key] = v
+		w.Header().Set("ETag", etag(v))
+	case r.Method == http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (f *fakeS3) keys() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var keys []string
+	for k := range f.objects {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func etag(v []byte) string {
+	sum := md5.Sum(v)
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+func TestS3Workspace(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeS3{objects: map[string][]byte{}}
+	server := httptest.NewServer(store)
+	defer server.Close()
+
+	// Two replicas, each with its own local copy.
+	newReplica := func() *S3Workspace {
+		return NewS3Workspace(S3WorkspaceConfig{
+			Bucket:   "bucket",
+			Prefix:   "agent",
+			Endpoint: server.URL,
+			Region:   "us-east-1",
+			Credentials: aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+				return aws.Credentials{AccessKeyID: "id", SecretAccessKey: "secret"}, nil
+			}),
+		}, NewDirWorkspace(t.TempDir(), t.TempDir(), 0))
+	}
+	a, b := newReplica(), newReplica()
+
+	dirA, err := a.Prepare(ctx, "s1")
+	if err != nil {
+		t.Fatalf("Prepare() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dirA, "outputs", "report 1.txt"), []byte("v1"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Finalize(ctx, "s1"); err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+	if got, want := store.keys(), []string{"agent/s1/outputs/report 1.txt"}; !slices.Equal(got, want) {
+		t.Fatalf("objects = %v, want %v", got, want)
+	}
+
+	// The next message is served by the other replica.
+	dirB, err := b.Prepare(ctx, "s1")
+	if err != nil {
+		t.Fatalf("Prepare() error = %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(dirB, "outputs", "report 1.txt")); err != nil || string(data) != "v1" {
+		t.Fatalf("report = %q, %v, want the file written on the other replica", data, err)
+	}
+	if err := os.WriteFile(filepath.Join(dirB, "outputs", "report 1.txt"), []byte("version 2"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dirB, "notes.md"), []byte("notes"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Finalize(ctx, "s1"); err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	if _, err := a.Prepare(ctx, "s1"); err != nil {
+		t.Fatalf("Prepare() error = %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dirA, "outputs", "report 1.txt")); string(data) != "version 2" {
+		t.Errorf("report = %q, want the other replica's update", data)
+	}
+	if err := os.Remove(filepath.Join(dirA, "notes.md")); err != nil {
+		t.Fatalf("notes.md was not downloaded: %v", err)
+	}
+	if err := a.Finalize(ctx, "s1"); err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+	if got, want := store.keys(), []string{"agent/s1/outputs/report 1.txt"}; !slices.Equal(got, want) {
+		t.Errorf("objects = %v, want the removed file deleted", got)
+	}
+
+	if _, err := b.Prepare(ctx, "s1"); err != nil {
+		t.Fatalf("Prepare() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dirB, "notes.md")); !os.IsNotExist(err) {
+		t.Errorf("notes.md stat error = %v, want it removed like on the other replica", err)
+	}
+
+	if err := b.Delete(ctx, "s1"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if got := store.keys(); len(got) != 0 {
+		t.Errorf("objects = %v, want none after Delete", got)
+	}
+	if _, err := os.Stat(dirB); !os.IsNotExist(err) {
+		t.Errorf("Expected %s to be removed, stat error = %v", dirB, err)
+	}
+}
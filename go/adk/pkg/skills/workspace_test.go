@@ -0,0 +1,110 @@
+package skills
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDirWorkspace_Quota(t *testing.T) {
+	ctx := context.Background()
+	ws := NewDirWorkspace(t.TempDir(), "", 1024)
+
+	dir, err := ws.Prepare(ctx, "session-1")
+	if err != nil {
+		t.Fatalf("Prepare() error = %v", err)
+	}
+	if err := ws.Finalize(ctx, "session-1"); err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "outputs", "big.bin"), make([]byte, 2048), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if err := ws.Finalize(ctx, "session-1"); !errors.Is(err, ErrWorkspaceQuotaExceeded) {
+		t.Errorf("Finalize() error = %v, want ErrWorkspaceQuotaExceeded", err)
+	}
+	if _, err := ws.Prepare(ctx, "session-1"); !errors.Is(err, ErrWorkspaceQuotaExceeded) {
+		t.Errorf("Prepare() error = %v, want ErrWorkspaceQuotaExceeded", err)
+	}
+}
+
+func TestDirWorkspace_Delete(t *testing.T) {
+	ctx := context.Background()
+	ws := NewDirWorkspace(t.TempDir(), "", 0)
+
+	dir, err := ws.Prepare(ctx, "session-1")
+	if err != nil {
+		t.Fatalf("Prepare() error = %v", err)
+	}
+	if err := ws.Delete(ctx, "session-1"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("Expected %s to be removed, stat error = %v", dir, err)
+	}
+	if err := ws.Delete(ctx, "../escape"); err == nil {
+		t.Error("Expected error for path traversal")
+	}
+}
+
+func TestNewWorkspaceFromEnv(t *testing.T) {
+	t.Run("pvc requires workspace dir", func(t *testing.T) {
+		t.Setenv(EnvWorkspaceDriver, WorkspaceDriverPVC)
+		t.Setenv(EnvWorkspaceDir, "")
+		if _, err := NewWorkspaceFromEnv(""); err == nil || !strings.Contains(err.Error(), EnvWorkspaceDir) {
+			t.Errorf("NewWorkspaceFromEnv() error = %v, want error mentioning %s", err, EnvWorkspaceDir)
+		}
+	})
+
+	t.Run("pvc requires mounted volume", func(t *testing.T) {
+		t.Setenv(EnvWorkspaceDriver, WorkspaceDriverPVC)
+		t.Setenv(EnvWorkspaceDir, filepath.Join(t.TempDir(), "missing"))
+		if _, err := NewWorkspaceFromEnv(""); err == nil {
+			t.Error("Expected error for missing volume")
+		}
+	})
+
+	t.Run("pvc", func(t *testing.T) {
+		base := t.TempDir()
+		t.Setenv(EnvWorkspaceDriver, WorkspaceDriverPVC)
+		t.Setenv(EnvWorkspaceDir, base)
+		ws, err := NewWorkspaceFromEnv("")
+		if err != nil {
+			t.Fatalf("NewWorkspaceFromEnv() error = %v", err)
+		}
+		dir, err := ws.Prepare(context.Background(), "session-1")
+		if err != nil {
+			t.Fatalf("Prepare() error = %v", err)
+		}
+		if want := filepath.Join(base, "session-1"); dir != want {
+			t.Errorf("Prepare() = %s, want %s", dir, want)
+		}
+	})
+
+	t.Run("unknown driver", func(t *testing.T) {
+		t.Setenv(EnvWorkspaceDriver, "nfs")
+		if _, err := NewWorkspaceFromEnv(""); err == nil {
+			t.Error("Expected error for unknown driver")
+		}
+	})
+
+	t.Run("s3 requires bucket", func(t *testing.T) {
+		t.Setenv(EnvWorkspaceDriver, WorkspaceDriverS3)
+		t.Setenv(EnvWorkspaceS3Bucket, "")
+		if _, err := NewWorkspaceFromEnv(""); err == nil || !strings.Contains(err.Error(), EnvWorkspaceS3Bucket) {
+			t.Errorf("NewWorkspaceFromEnv() error = %v, want error mentioning %s", err, EnvWorkspaceS3Bucket)
+		}
+	})
+
+	t.Run("invalid quota", func(t *testing.T) {
+		t.Setenv(EnvWorkspaceDriver, "")
+		t.Setenv(EnvWorkspaceQuotaMB, "lots")
+		if _, err := NewWorkspaceFromEnv(""); err == nil {
+			t.Error("Expected error for invalid quota")
+		}
+	})
+}
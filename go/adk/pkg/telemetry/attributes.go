@@ -46,6 +46,29 @@ func SetLLMResponseAttributes(ctx context.Context, resp *model.LLMResponse) {
 	setSpanAttributes(ctx, attribute.String("gcp.vertex.agent.llm_response", marshalSpanPayload(resp)))
 }
 
+// SetBestOfAttributes records a best-of selection on the current
+// generate_content span: the candidate count, the index kept, every score and
+// the rejected candidates.
+func SetBestOfAttributes(ctx context.Context, candidates, selected int, scores []float64, rejected []*model.LLMResponse) {
+	setSpanAttributes(ctx,
+		attribute.Int("kagent.best_of.candidates", candidates),
+		attribute.Int("kagent.best_of.selected", selected),
+		attribute.Float64Slice("kagent.best_of.scores", scores),
+		attribute.String("kagent.best_of.rejected", marshalSpanPayload(rejected)),
+	)
+}
+
+// SetReflectionAttributes records a reflection step on the current
+// generate_content span: every draft, the last being the one returned, and
+// the critique of each.
+func SetReflectionAttributes(ctx context.Context, drafts []*model.LLMResponse, critiques []string) {
+	setSpanAttributes(ctx,
+		attribute.Int("kagent.reflection.passes", len(drafts)-1),
+		attribute.String("kagent.reflection.drafts", marshalSpanPayload(drafts)),
+		attribute.String("kagent.reflection.critiques", marshalSpanPayload(critiques)),
+	)
+}
+
 func contextAttributes(ctx context.Context) map[string]string {
 	attrs, _ := ctx.Value(kagentSpanAttributesKey{}).(map[string]string)
 	if len(attrs) == 0 {
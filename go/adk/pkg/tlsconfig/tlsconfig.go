@@ -0,0 +1,214 @@
+// Package tlsconfig builds the TLS configuration of the agent's A2A server and
+// of the client it uses to call other agents. Certificates are re-read when
+// their files change so that rotated secrets are picked up without a restart.
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Environment variables read by FromEnv.
+const (
+	EnvCertFile          = "KAGENT_TLS_CERT_FILE"
+	EnvKeyFile           = "KAGENT_TLS_KEY_FILE"
+	EnvClientCAFile      = "KAGENT_TLS_CLIENT_CA_FILE"
+	EnvRequireClientCert = "KAGENT_TLS_REQUIRE_CLIENT_CERT"
+)
+
+// Config configures TLS. The zero value serves plaintext HTTP.
+type Config struct {
+	// CertFile and KeyFile are the PEM certificate and key served by the
+	// agent. They are also presented as the client certificate on calls to
+	// other agents when ClientCAFile is set.
+	CertFile string
+	KeyFile  string
+	// ClientCAFile is a PEM bundle of CAs used to verify client certificates
+	// on the server and the peer's certificate on calls to other agents.
+	ClientCAFile string
+	// RequireClientCert rejects clients without a valid certificate. When
+	// false, certificates are verified only when presented, so plaintext
+	// probes such as kubelet health checks keep working.
+	RequireClientCert bool
+}
+
+// FromEnv reads the configuration from the KAGENT_TLS_* env vars.
+func FromEnv() (Config, error) {
+	cfg := Config{
+		CertFile:     strings.TrimSpace(os.Getenv(EnvCertFile)),
+		KeyFile:      strings.TrimSpace(os.Getenv(EnvKeyFile)),
+		ClientCAFile: strings.TrimSpace(os.Getenv(EnvClientCAFile)),
+	}
+	if v := strings.TrimSpace(os.Getenv(EnvRequireClientCert)); v != "" {
+		var err error
+		if cfg.RequireClientCert, err = strconv.ParseBool(v); err != nil {
+			return Config{}, fmt.Errorf("invalid %s %q", EnvRequireClientCert, v)
+		}
+	}
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// Enabled reports whether the server should serve TLS.
+func (c Config) Enabled() bool {
+	return c.CertFile != ""
+}
+
+// MutualTLS reports whether client certificates are verified.
+func (c Config) MutualTLS() bool {
+	return c.Enabled() && c.ClientCAFile != ""
+}
+
+// Validate checks that the configured files form a usable combination.
+func (c Config) Validate() error {
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return fmt.Errorf("%s and %s must be set together", EnvCertFile, EnvKeyFile)
+	}
+	if c.ClientCAFile != "" && !c.Enabled() {
+		return fmt.Errorf("%s requires %s and %s", EnvClientCAFile, EnvCertFile, EnvKeyFile)
+	}
+	if c.RequireClientCert && c.ClientCAFile == "" {
+		return fmt.Errorf("%s requires %s", EnvRequireClientCert, EnvClientCAFile)
+	}
+	return nil
+}
+
+// Server returns the server TLS configuration. The certificate and client CAs
+// are reloaded on the first handshake after their files change.
+func (c Config) Server() (*tls.Config, error) {
+	r, err := newReloader(c)
+	if err != nil {
+		return nil, err
+	}
+	clientAuth := tls.NoClientCert
+	if c.ClientCAFile != "" {
+		clientAuth = tls.VerifyClientCertIfGiven
+		if c.RequireClientCert {
+			clientAuth = tls.RequireAndVerifyClientCert
+		}
+	}
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cert, pool, err := r.load()
+			if err != nil {
+				return nil, err
+			}
+			// The returned config replaces the server's, so it must repeat
+			// the ALPN protocols for HTTP/2 (and gRPC) to be negotiated.
+			return &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				NextProtos:   []string{"h2", "http/1.1"},
+				Certificates: []tls.Certificate{*cert},
+				ClientAuth:   clientAuth,
+				ClientCAs:    pool,
+			}, nil
+		},
+	}, nil
+}
+
+// Client returns the TLS configuration for calls to other agents, presenting
+// the agent's certificate and trusting ClientCAFile. It returns nil when
+// mutual TLS is not configured.
+func (c Config) Client() (*tls.Config, error) {
+	if !c.MutualTLS() {
+		return nil, nil
+	}
+	r, err := newReloader(c)
+	if err != nil {
+		return nil, err
+	}
+	_, pool, _ := r.load()
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		RootCAs:    pool,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			cert, _, err := r.load()
+			return cert, err
+		},
+	}, nil
+}
+
+// reloader caches the parsed certificate and CA pool and re-reads them when
+// the modification time of any of the files changes.
+type reloader struct {
+	cfg Config
+
+	mu      sync.Mutex
+	modTime map[string]time.Time
+	cert    *tls.Certificate
+	pool    *x509.CertPool
+}
+
+func newReloader(cfg Config) (*reloader, error) {
+	r := &reloader{cfg: cfg}
+	if _, _, err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// load returns the current certificate and CA pool, reloading them when the
+// files changed. A failed reload keeps serving the previous certificate.
+func (r *reloader) load() (*tls.Certificate, *x509.CertPool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	files := []string{r.cfg.CertFile, r.cfg.KeyFile}
+	if r.cfg.ClientCAFile != "" {
+		files = append(files, r.cfg.ClientCAFile)
+	}
+	modTime := make(map[string]time.Time, len(files))
+	changed := r.cert == nil
+	for _, f := range files {
+		info, err := os.Stat(f)
+		if err != nil {
+			if r.cert != nil {
+				return r.cert, r.pool, nil
+			}
+			return nil, nil, fmt.Errorf("failed to stat %s: %w", f, err)
+		}
+		modTime[f] = info.ModTime()
+		if !info.ModTime().Equal(r.modTime[f]) {
+			changed = true
+		}
+	}
+	if !changed {
+		return r.cert, r.pool, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.cfg.CertFile, r.cfg.KeyFile)
+	if err != nil {
+		if r.cert != nil {
+			return r.cert, r.pool, nil
+		}
+		return nil, nil, fmt.Errorf("failed to load TLS key pair: %w", err)
+	}
+	var pool *x509.CertPool
+	if r.cfg.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.cfg.ClientCAFile)
+		if err != nil {
+			if r.cert != nil {
+				return r.cert, r.pool, nil
+			}
+			return nil, nil, fmt.Errorf("failed to read client CA file: %w", err)
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			if r.cert != nil {
+				return r.cert, r.pool, nil
+			}
+			return nil, nil, fmt.Errorf("no certificates found in %s", r.cfg.ClientCAFile)
+		}
+	}
+	r.cert, r.pool, r.modTime = &cert, pool, modTime
+	return r.cert, r.pool, nil
+}
@@ -0,0 +1,193 @@
+// Package toolargs applies the agent's declarative tool argument transforms
+// (adk.ToolArgTransform) to tool calls before they are authorized and run,
+// so platform teams can enforce safe defaults without changing the prompt.
+package toolargs
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/kagent-dev/kagent/go/api/adk"
+)
+
+// Transformer applies transforms to the arguments of tool calls.
+type Transformer struct {
+	transforms []adk.ToolArgTransform
+}
+
+// New validates transforms and returns their Transformer, or nil when there
+// are none.
+func New(transforms []adk.ToolArgTransform) (*Transformer, error) {
+	if len(transforms) == 0 {
+		return nil, nil
+	}
+	for i := range transforms {
+		if err := transforms[i].Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return &Transformer{transforms: slices.Clone(transforms)}, nil
+}
+
+// Len returns the number of transforms.
+func (t *Transformer) Len() int {
+	if t == nil {
+		return 0
+	}
+	return len(t.transforms)
+}
+
+// Apply rewrites args, the arguments of a call to tool, in place with the
+// transforms matching tool, in order. It returns the arguments it changed.
+// An error means the call must not run: an argument does not have the type
+// a transform needs, or a prefixed path escapes its prefix.
+func (t *Transformer) Apply(tool string, args map[string]any) ([]string, error) {
+	if t == nil {
+		return nil, nil
+	}
+	var changed []string
+	for _, tr := range t.transforms {
+		if ok, _ := path.Match(tr.Tool, tool); !ok {
+			continue
+		}
+		did, err := apply(tr, args)
+		if err != nil {
+			return changed, fmt.Errorf("argument %q: %w", tr.Arg, err)
+		}
+		if did && !slices.Contains(changed, tr.Arg) {
+			changed = append(changed, tr.Arg)
+		}
+	}
+	return changed, nil
+}
+
+// apply runs one transform and reports whether it changed args.
+func apply(tr adk.ToolArgTransform, args map[string]any) (bool, error) {
+	keys := strings.Split(tr.Arg, ".")
+	create := tr.Op == adk.ToolArgTransformSet || tr.Op == adk.ToolArgTransformDefault
+	parent, err := parentObject(args, keys[:len(keys)-1], create)
+	if err != nil || parent == nil {
+		return false, err
+	}
+	key := keys[len(keys)-1]
+	current, present := parent[key]
+
+	switch tr.Op {
+	case adk.ToolArgTransformSet:
+		if present && equal(current, tr.Value) {
+			return false, nil
+		}
+		parent[key] = tr.Value
+		return true, nil
+	case adk.ToolArgTransformDefault:
+		if present && current != nil {
+			return false, nil
+		}
+		parent[key] = tr.Value
+		return true, nil
+	case adk.ToolArgTransformRemove:
+		delete(parent, key)
+		return present, nil
+	}
+	if !present || current == nil {
+		return false, nil
+	}
+
+	switch tr.Op {
+	case adk.ToolArgTransformPrefix:
+		s, ok := current.(string)
+		if !ok {
+			return false, fmt.Errorf("want a string, got %T", current)
+		}
+		if slices.Contains(strings.FieldsFunc(s, isPathSeparator), "..") {
+			return false, fmt.Errorf("path %q must not contain ..", s)
+		}
+		prefix := tr.Value.(string)
+		if underPrefix(s, prefix) {
+			return false, nil
+		}
+		parent[key] = strings.TrimSuffix(prefix, "/") + "/" + strings.TrimPrefix(s, "/")
+		return true, nil
+	case adk.ToolArgTransformMax, adk.ToolArgTransformMin:
+		n, ok := number(current)
+		if !ok {
+			return false, fmt.Errorf("want a number, got %T", current)
+		}
+		bound, _ := number(tr.Value)
+		if (tr.Op == adk.ToolArgTransformMax && n > bound) || (tr.Op == adk.ToolArgTransformMin && n < bound) {
+			parent[key] = bound
+			return true, nil
+		}
+		return false, nil
+	}
+	return false, fmt.Errorf("unknown op %q", tr.Op)
+}
+
+// parentObject walks keys down from args and returns the object holding the
+// last argument. Missing objects are created when create is set; otherwise
+// nil is returned.
+func parentObject(args map[string]any, keys []string, create bool) (map[string]any, error) {
+	obj := args
+	for i, key := range keys {
+		v, ok := obj[key]
+		if !ok || v == nil {
+			if !create {
+				return nil, nil
+			}
+			next := map[string]any{}
+			obj[key] = next
+			obj = next
+			continue
+		}
+		next, ok := v.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%s is not an object", strings.Join(keys[:i+1], "."))
+		}
+		obj = next
+	}
+	return obj, nil
+}
+
+// underPrefix reports whether the path s is prefix or lies below it. A
+// sibling sharing the prefix's leading characters, such as /workspace-evil
+// for /workspace, is not below it.
+func underPrefix(s, prefix string) bool {
+	s, prefix = path.Clean(s), path.Clean(prefix)
+	return s == prefix || strings.HasPrefix(s, strings.TrimSuffix(prefix, "/")+"/")
+}
+
+func isPathSeparator(r rune) bool {
+	return r == '/' || r == '\\'
+}
+
+// number converts the numeric types found in decoded JSON and configs.
+func number(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// equal compares JSON-like values.
+func equal(a, b any) bool {
+	if x, ok := number(a); ok {
+		y, ok := number(b)
+		return ok && x == y
+	}
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(ja) == string(jb)
+}
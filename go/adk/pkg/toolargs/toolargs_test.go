@@ -0,0 +1,103 @@
+package toolargs
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/kagent-dev/kagent/go/api/adk"
+)
+
+func TestTransformer_Apply(t *testing.T) {
+	var transforms []adk.ToolArgTransform
+	if err := json.Unmarshal([]byte(`[
+		{"tool": "k8s_apply*", "arg": "dry_run", "op": "set", "value": true},
+		{"tool": "read_file", "arg": "path", "op": "prefix", "value": "/workspace/"},
+		{"tool": "write_file", "arg": "path", "op": "prefix", "value": "/workspace"},
+		{"tool": "k8s_*", "arg": "options.limit", "op": "max", "value": 100},
+		{"tool": "k8s_*", "arg": "options.limit", "op": "min", "value": 1},
+		{"tool": "k8s_get", "arg": "namespace", "op": "default", "value": "default"},
+		{"tool": "k8s_get", "arg": "all_namespaces", "op": "remove"}
+	]`), &transforms); err != nil {
+		t.Fatalf("invalid transforms: %v", err)
+	}
+	tr, err := New(transforms)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	tests := []struct {
+		tool        string
+		args        string
+		want        string
+		wantChanged []string
+	}{
+		{tool: "k8s_apply_manifest", args: `{"manifest": "x", "dry_run": false}`, want: `{"manifest": "x", "dry_run": true}`, wantChanged: []string{"dry_run"}},
+		{tool: "k8s_apply", args: `{"dry_run": true}`, want: `{"dry_run": true}`},
+		{tool: "read_file", args: `{"path": "notes.txt"}`, want: `{"path": "/workspace/notes.txt"}`, wantChanged: []string{"path"}},
+		{tool: "read_file", args: `{"path": "/workspace/notes.txt"}`, want: `{"path": "/workspace/notes.txt"}`},
+		{tool: "read_file", args: `{"path": "/workspace-evil/notes.txt"}`, want: `{"path": "/workspace/workspace-evil/notes.txt"}`, wantChanged: []string{"path"}},
+		{tool: "write_file", args: `{"path": "notes.txt"}`, want: `{"path": "/workspace/notes.txt"}`, wantChanged: []string{"path"}},
+		{tool: "write_file", args: `{"path": "/workspace"}`, want: `{"path": "/workspace"}`},
+		{tool: "write_file", args: `{"path": "/workspace/notes.txt"}`, want: `{"path": "/workspace/notes.txt"}`},
+		{tool: "write_file", args: `{"path": "/workspace-evil/notes.txt"}`, want: `{"path": "/workspace/workspace-evil/notes.txt"}`, wantChanged: []string{"path"}},
+		{tool: "write_file", args: `{"path": "/workspacenotes.txt"}`, want: `{"path": "/workspace/workspacenotes.txt"}`, wantChanged: []string{"path"}},
+		{tool: "k8s_get", args: `{"options": {"limit": 5000}, "all_namespaces": true}`, want: `{"options": {"limit": 100}, "namespace": "default"}`, wantChanged: []string{"options.limit", "namespace", "all_namespaces"}},
+		{tool: "k8s_get", args: `{"options": {"limit": 0}, "namespace": "prod"}`, want: `{"options": {"limit": 1}, "namespace": "prod"}`, wantChanged: []string{"options.limit"}},
+		{tool: "k8s_get", args: `{"namespace": "prod"}`, want: `{"namespace": "prod"}`},
+		{tool: "list_pods", args: `{"dry_run": false}`, want: `{"dry_run": false}`},
+	}
+	for _, tt := range tests {
+		var args, want map[string]any
+		_ = json.Unmarshal([]byte(tt.args), &args)
+		_ = json.Unmarshal([]byte(tt.want), &want)
+		changed, err := tr.Apply(tt.tool, args)
+		if err != nil {
+			t.Errorf("Apply(%s, %s) error = %v", tt.tool, tt.args, err)
+			continue
+		}
+		if !reflect.DeepEqual(args, want) || !reflect.DeepEqual(changed, tt.wantChanged) {
+			t.Errorf("Apply(%s, %s) = %v, changed %v; want %v, changed %v", tt.tool, tt.args, args, changed, want, tt.wantChanged)
+		}
+	}
+}
+
+func TestTransformer_Rejects(t *testing.T) {
+	tr, err := New([]adk.ToolArgTransform{
+		{Tool: "read_file", Arg: "path", Op: adk.ToolArgTransformPrefix, Value: "/workspace/"},
+		{Tool: "list", Arg: "opts.limit", Op: adk.ToolArgTransformMax, Value: 10},
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	for name, tc := range map[string]struct {
+		tool string
+		args map[string]any
+	}{
+		"path traversal":  {tool: "read_file", args: map[string]any{"path": "../../etc/passwd"}},
+		"prefixed escape": {tool: "read_file", args: map[string]any{"path": "/workspace/../etc/passwd"}},
+		"non-string path": {tool: "read_file", args: map[string]any{"path": 42.0}},
+		"non-number":      {tool: "list", args: map[string]any{"opts": map[string]any{"limit": "all"}}},
+		"non-object":      {tool: "list", args: map[string]any{"opts": "fast"}},
+	} {
+		if _, err := tr.Apply(tc.tool, tc.args); err == nil {
+			t.Errorf("%s: Apply() error = nil", name)
+		}
+	}
+
+	for name, transform := range map[string]adk.ToolArgTransform{
+		"bad glob":       {Tool: "[", Arg: "a", Op: adk.ToolArgTransformRemove},
+		"empty arg part": {Tool: "t", Arg: "a..b", Op: adk.ToolArgTransformRemove},
+		"unknown op":     {Tool: "t", Arg: "a", Op: "append"},
+		"set no value":   {Tool: "t", Arg: "a", Op: adk.ToolArgTransformSet},
+		"prefix number":  {Tool: "t", Arg: "a", Op: adk.ToolArgTransformPrefix, Value: 1.0},
+		"max string":     {Tool: "t", Arg: "a", Op: adk.ToolArgTransformMax, Value: "10"},
+	} {
+		if _, err := New([]adk.ToolArgTransform{transform}); err == nil {
+			t.Errorf("%s: New() error = nil", name)
+		}
+	}
+	if tr, err := New(nil); tr != nil || err != nil || tr.Len() != 0 {
+		t.Errorf("New(nil) = %v, %v; want nil", tr, err)
+	}
+}
@@ -0,0 +1,161 @@
+package tools
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	skillruntime "github.com/kagent-dev/kagent/go/adk/pkg/skills"
+	adkagent "google.golang.org/adk/agent"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+const (
+	gitRepoUsage = `
+- repo is the repository directory relative to your working directory (default "."); it must be the top level of a git repository inside your session.`
+
+	gitStatusDescription = `Shows the branch and the changed, staged and untracked files of a git repository.
+
+Usage:` + gitRepoUsage
+
+	gitDiffDescription = `Shows the unstaged changes of a git repository as a unified diff.
+
+Usage:` + gitRepoUsage + `
+- Set staged=true to show the changes staged for the next commit instead.
+- Optional paths limit the diff to those files or directories.
+- Large diffs are truncated in the middle.`
+
+	gitCommitDescription = `Stages changes and commits them to a git repository.
+
+Usage:` + gitRepoUsage + `
+- Stages the given paths, or every change (including new and deleted files) when paths is omitted.
+- Commits with the message and the configured author identity; repository hooks do not run.
+- Review the changes with git_diff before committing.`
+
+	gitApplyPatchDescription = `Applies a unified diff to the working tree of a git repository.
+
+Usage:` + gitRepoUsage + `
+- patch is a unified diff with paths relative to the repository root, as produced by git diff.
+- The patch is checked first; if any hunk does not apply, nothing is changed.
+- Patches touching .git or paths outside the repository are rejected.
+- Changes are not staged or committed; use git_commit for that.`
+)
+
+type gitStatusInput struct {
+	Repo string `json:"repo,omitempty"`
+}
+
+type gitDiffInput struct {
+	Repo   string   `json:"repo,omitempty"`
+	Staged bool     `json:"staged,omitempty"`
+	Paths  []string `json:"paths,omitempty"`
+}
+
+type gitCommitInput struct {
+	Repo    string   `json:"repo,omitempty"`
+	Message string   `json:"message"`
+	Paths   []string `json:"paths,omitempty"`
+}
+
+type gitApplyPatchInput struct {
+	Repo  string `json:"repo,omitempty"`
+	Patch string `json:"patch"`
+}
+
+// newGitTools creates the git tools, scoped to repositories inside the
+// session's working directory. git_commit asks for approval when the
+// runner requires it.
+func newGitTools(git *skillruntime.GitRunner, workspace skillruntime.Workspace, skillsDirectory string) ([]tool.Tool, error) {
+	repoDir := func(ctx adkagent.ToolContext, repo string) (string, error) {
+		sessionPath, err := workspace.Prepare(ctx, ctx.SessionID())
+		if err != nil {
+			return "", err
+		}
+		if strings.TrimSpace(repo) == "" {
+			repo = "."
+		}
+		return resolveEditPath(sessionPath, skillsDirectory, repo)
+	}
+
+	statusTool, err := functiontool.New(functiontool.Config{
+		Name:        "git_status",
+		Description: gitStatusDescription,
+	}, func(ctx adkagent.ToolContext, in gitStatusInput) (string, error) {
+		dir, err := repoDir(ctx, in.Repo)
+		if err != nil {
+			return fmt.Sprintf("Error getting git status: %v", err), nil
+		}
+		status, err := git.Status(ctx, dir)
+		if err != nil {
+			return fmt.Sprintf("Error getting git status: %v", err), nil
+		}
+		return status, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create git_status tool: %w", err)
+	}
+
+	diffTool, err := functiontool.New(functiontool.Config{
+		Name:        "git_diff",
+		Description: gitDiffDescription,
+	}, func(ctx adkagent.ToolContext, in gitDiffInput) (string, error) {
+		dir, err := repoDir(ctx, in.Repo)
+		if err != nil {
+			return fmt.Sprintf("Error getting git diff: %v", err), nil
+		}
+		diff, err := git.Diff(ctx, dir, in.Staged, in.Paths)
+		if err != nil {
+			return fmt.Sprintf("Error getting git diff: %v", err), nil
+		}
+		if diff == "" {
+			return "No changes.", nil
+		}
+		return TruncateOutput(diff, MaxStreamingToolResultBytes), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create git_diff tool: %w", err)
+	}
+
+	commitTool, err := functiontool.New(functiontool.Config{
+		Name:                "git_commit",
+		Description:         gitCommitDescription,
+		RequireConfirmation: git.RequireApproval(),
+	}, func(ctx adkagent.ToolContext, in gitCommitInput) (string, error) {
+		dir, err := repoDir(ctx, in.Repo)
+		if err != nil {
+			return fmt.Sprintf("Error committing: %v", err), nil
+		}
+		summary, err := git.Commit(ctx, dir, in.Message, in.Paths)
+		if errors.Is(err, skillruntime.ErrNothingToCommit) {
+			return "Nothing to commit: the working tree has no changes.", nil
+		}
+		if err != nil {
+			return fmt.Sprintf("Error committing: %v", err), nil
+		}
+		return "Committed:\n" + summary, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create git_commit tool: %w", err)
+	}
+
+	applyPatchTool, err := functiontool.New(functiontool.Config{
+		Name:        "git_apply_patch",
+		Description: gitApplyPatchDescription,
+	}, func(ctx adkagent.ToolContext, in gitApplyPatchInput) (string, error) {
+		dir, err := repoDir(ctx, in.Repo)
+		if err != nil {
+			return fmt.Sprintf("Error applying patch: %v", err), nil
+		}
+		stat, err := git.ApplyPatch(ctx, dir, in.Patch)
+		if err != nil {
+			return fmt.Sprintf("Error applying patch: %v", err), nil
+		}
+		return "Patch applied:\n" + stat, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create git_apply_patch tool: %w", err)
+	}
+
+	return []tool.Tool{statusTool, diffTool, commitTool, applyPatchTool}, nil
+}
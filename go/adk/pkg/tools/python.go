@@ -0,0 +1,110 @@
+package tools
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	skillruntime "github.com/kagent-dev/kagent/go/adk/pkg/skills"
+	adkagent "google.golang.org/adk/agent"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+	"google.golang.org/genai"
+)
+
+const executePythonDescription = `Runs a Python snippet in the sandbox and returns its exit code, stdout and stderr.
+
+Usage:
+- The snippet runs in the session's working directory, which is on sys.path; skills are available under skills/.
+- State does not carry over between calls: write helpers to files and import them.
+- Files the snippet writes under outputs/ are returned to the user as artifacts.
+- timeout_seconds defaults to 60 and is capped at 300.
+- packages are pip installed into the session's virtual environment before the snippet runs, e.g. ["pandas==2.2.3"]. Only allowlisted packages can be installed.`
+
+// maxPythonArtifactBytes skips produced files too large to hand back as
+// artifacts; they stay in the session's outputs/ directory.
+const maxPythonArtifactBytes = 10 << 20
+
+type executePythonInput struct {
+	Code           string   `json:"code"`
+	TimeoutSeconds int      `json:"timeout_seconds,omitempty"`
+	Packages       []string `json:"packages,omitempty"`
+}
+
+func newExecutePythonTool(python *skillruntime.PythonExecutor, workspace skillruntime.Workspace) (tool.Tool, error) {
+	description := executePythonDescription
+	if allowed := python.PipAllowlist(); len(allowed) > 0 {
+		description += "\n- Allowed packages: " + strings.Join(allowed, ", ")
+	} else {
+		description += "\n- pip installs are disabled."
+	}
+
+	return NewStreamingTool(functiontool.Config{
+		Name:        "execute_python",
+		Description: description,
+	}, func(ctx adkagent.ToolContext, in executePythonInput, out io.Writer) (string, error) {
+		if strings.TrimSpace(in.Code) == "" {
+			return "Error: No code provided", nil
+		}
+		sessionPath, err := workspace.Prepare(ctx, ctx.SessionID())
+		if err != nil {
+			return fmt.Sprintf("Error executing python: %v", err), nil
+		}
+
+		if len(in.Packages) > 0 {
+			if _, err := python.Install(ctx, in.Packages, sessionPath, out); err != nil {
+				return fmt.Sprintf("Error installing packages: %v", err), nil
+			}
+		}
+
+		result, err := python.Run(ctx, in.Code, sessionPath, time.Duration(in.TimeoutSeconds)*time.Second, out)
+		if err != nil {
+			return fmt.Sprintf("Error executing python: %v", err), nil
+		}
+		return formatPythonResult(result, savePythonArtifacts(ctx, sessionPath, result.Files)), nil
+	})
+}
+
+// savePythonArtifacts saves the files a snippet produced as artifacts named
+// after their path under outputs/, and returns a line per file describing
+// the outcome.
+func savePythonArtifacts(ctx adkagent.ToolContext, sessionPath string, files []string) []string {
+	var lines []string
+	for _, file := range files {
+		name := strings.TrimPrefix(file, "outputs/")
+		data, err := os.ReadFile(filepath.Join(sessionPath, filepath.FromSlash(file)))
+		switch {
+		case err != nil:
+			lines = append(lines, fmt.Sprintf("%s: not saved: %v", file, err))
+		case len(data) > maxPythonArtifactBytes:
+			lines = append(lines, fmt.Sprintf("%s: not saved as an artifact: larger than %d bytes", file, maxPythonArtifactBytes))
+		case ctx.Artifacts() == nil:
+			lines = append(lines, file)
+		default:
+			if _, err := ctx.Artifacts().Save(ctx, name, genai.NewPartFromBytes(data, skillruntime.DetectMIMEType(name, data))); err != nil {
+				lines = append(lines, fmt.Sprintf("%s: not saved as an artifact: %v", file, err))
+				continue
+			}
+			lines = append(lines, fmt.Sprintf("%s (artifact %q)", file, name))
+		}
+	}
+	return lines
+}
+
+func formatPythonResult(result *skillruntime.PythonResult, files []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Exit code: %d\n", result.ExitCode)
+	if stdout := strings.TrimSpace(result.Stdout); stdout != "" {
+		fmt.Fprintf(&b, "\nstdout:\n%s\n", stdout)
+	}
+	if stderr := strings.TrimSpace(result.Stderr); stderr != "" {
+		fmt.Fprintf(&b, "\nstderr:\n%s\n", stderr)
+	}
+	if len(files) > 0 {
+		fmt.Fprintf(&b, "\nFiles written to outputs/:\n- %s\n", strings.Join(files, "\n- "))
+	}
+	return strings.TrimSpace(b.String())
+}
@@ -343,7 +343,9 @@ func (s *remoteA2AState) processResult(ctx adkagent.ToolContext, result a2atype.
 		return map[string]any{"result": extractTextFromMessage(r)}, nil
 	case *a2atype.Task:
 		switch r.Status.State {
-		case a2atype.TaskStateInputRequired:
+		case a2atype.TaskStateInputRequired, a2atype.TaskStateAuthRequired:
+			// auth_required is only reported when the subagent opts into it
+			// and is resumed the same way as input_required.
 			return s.handleInputRequired(ctx, r), nil
 		case a2atype.TaskStateFailed:
 			text := extractTextFromTask(r)
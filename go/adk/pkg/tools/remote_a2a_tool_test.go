@@ -4,8 +4,10 @@ import (
 	"context"
 	"testing"
 
+	a2atype "github.com/a2aproject/a2a-go/a2a"
 	"github.com/a2aproject/a2a-go/a2aclient"
 	"github.com/a2aproject/a2a-go/a2asrv"
+	adkagent "google.golang.org/adk/agent"
 )
 
 // newReq returns an empty outbound client Request with an initialized CallMeta.
@@ -124,3 +126,36 @@ func assertSingleHeader(t *testing.T, req *a2aclient.Request, key, want string)
 		t.Errorf("%s: got %q, want %q", key, got[0], want)
 	}
 }
+
+// confirmingToolContext records RequestConfirmation calls; other
+// ToolContext methods are not used by processResult.
+type confirmingToolContext struct {
+	adkagent.ToolContext
+	hints []string
+}
+
+func (c *confirmingToolContext) RequestConfirmation(hint string, _ any) error {
+	c.hints = append(c.hints, hint)
+	return nil
+}
+
+func TestProcessResult_PausedStates(t *testing.T) {
+	for _, state := range []a2atype.TaskState{a2atype.TaskStateInputRequired, a2atype.TaskStateAuthRequired} {
+		t.Run(string(state), func(t *testing.T) {
+			s := &remoteA2AState{name: "sub"}
+			ctx := &confirmingToolContext{}
+			task := &a2atype.Task{ID: "task-1", ContextID: "ctx-1", Status: a2atype.TaskStatus{State: state}}
+
+			ret, err := s.processResult(ctx, task)
+			if err != nil {
+				t.Fatalf("processResult() error = %v", err)
+			}
+			if ret["status"] != "pending" {
+				t.Errorf("processResult() = %v, want a pending result", ret)
+			}
+			if len(ctx.hints) != 1 {
+				t.Errorf("RequestConfirmation called %d times, want 1", len(ctx.hints))
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package tools
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -18,8 +19,10 @@ const (
 Usage:
 - Provide a path to the file (absolute or relative to your working directory)
 - Returns content with line numbers (format: LINE_NUMBER|CONTENT)
-- Optional offset and limit parameters for reading specific line ranges
+- Optional offset (1-based line) and limit (number of lines) parameters for reading specific line ranges
+- Returns at most 2000 lines by default; when more lines remain, the output ends with a marker giving the offset to continue from
 - Lines longer than 2000 characters are truncated
+- Binary files are summarized with their MIME type, size and a hex dump of their first bytes
 - Always read a file before editing it
 - You can read from skills/ directory, uploads/, outputs/, or any file in your session`
 
@@ -34,7 +37,7 @@ Usage:
 - You can write to your working directory, outputs/, or any writable location
 - Note: skills/ directory is read-only`
 
-	editFileDescription = `Performs exact string replacements in files.
+	editFileDescription = `Performs string replacements in files.
 
 Usage:
 - You must read the file first using read_file
@@ -44,12 +47,23 @@ Usage:
 - old_string must be unique unless replace_all=true
 - Use replace_all to rename variables/strings throughout the file
 - old_string and new_string must be different
+- If old_string does not match exactly, the most similar block of lines is replaced when the match is confident and unique; the result reports such fuzzy matches
+- Alternatively, pass diff (a unified diff of this file) instead of old_string and new_string; hunks are located by their context, so line numbers may be off
+- Note: skills/ directory is read-only`
+
+	multiEditFileDescription = `Applies several string replacements to one file at once.
+
+Usage:
+- Takes a path and a list of edits, each with old_string, new_string and optional replace_all, matched like edit_file
+- Edits are applied in order, each to the result of the previous one
+- The edits are atomic: if any edit fails, the file is left unchanged
+- Prefer this over several edit_file calls when changing one file in several places
 - Note: skills/ directory is read-only`
 
 	bashDescription = `Execute bash commands in the skills environment with sandbox protection.
 
 Working Directory & Structure:
-- Commands run in a temporary session directory: /tmp/kagent/{session_id}/
+- Commands run in the session's working directory, which persists across turns of the session.
 - /skills -> All skills are available here (read-only).
 - Your current working directory and /skills are added to PYTHONPATH.
 
@@ -67,6 +81,12 @@ For file operations:
 Timeouts:
 - python scripts: 60s
 - other commands: 30s`
+
+	bashPersistentShellDescription = `
+
+Shell Session:
+- Commands of this session run in the same shell: the current directory, exported variables and functions carry over between calls.
+- Commands cannot read stdin; a command that times out or exits restarts the shell.`
 )
 
 type skillsInput struct {
@@ -91,9 +111,15 @@ type writeFileInput struct {
 
 type editFileInput struct {
 	FilePath   string `json:"file_path"`
-	OldString  string `json:"old_string"`
-	NewString  string `json:"new_string"`
+	OldString  string `json:"old_string,omitempty"`
+	NewString  string `json:"new_string,omitempty"`
 	ReplaceAll bool   `json:"replace_all,omitempty"`
+	Diff       string `json:"diff,omitempty"`
+}
+
+type multiEditFileInput struct {
+	FilePath string              `json:"file_path"`
+	Edits    []skillruntime.Edit `json:"edits"`
 }
 
 func NewSkillsTools(skillsDirectory string) ([]tool.Tool, error) {
@@ -118,6 +144,22 @@ func NewSkillsTools(skillsDirectory string) ([]tool.Tool, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to configure bash sandbox: %w", err)
 	}
+	shells, err := skillruntime.NewPersistentShellsFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure persistent bash shells: %w", err)
+	}
+	python, err := skillruntime.NewPythonExecutorFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure python execution: %w", err)
+	}
+	git, err := skillruntime.NewGitRunnerFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure git tools: %w", err)
+	}
+	workspace, err := skillruntime.NewWorkspaceFromEnv(absSkillsDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure session workspace: %w", err)
+	}
 
 	skillsTool, err := functiontool.New(functiontool.Config{
 		Name:        "skills",
@@ -148,7 +190,11 @@ func NewSkillsTools(skillsDirectory string) ([]tool.Tool, error) {
 		Name:        "read_file",
 		Description: readFileDescription,
 	}, func(ctx adkagent.ToolContext, in readFileInput) (string, error) {
-		path, err := resolveReadPath(ctx.SessionID(), absSkillsDir, in.FilePath)
+		sessionPath, err := workspace.Prepare(ctx, ctx.SessionID())
+		if err != nil {
+			return fmt.Sprintf("Error reading file %s: %v", strings.TrimSpace(in.FilePath), err), nil
+		}
+		path, err := resolveReadPath(sessionPath, absSkillsDir, in.FilePath)
 		if err != nil {
 			return fmt.Sprintf("Error reading file %s: %v", strings.TrimSpace(in.FilePath), err), nil
 		}
@@ -167,7 +213,11 @@ func NewSkillsTools(skillsDirectory string) ([]tool.Tool, error) {
 		Name:        "write_file",
 		Description: writeFileDescription,
 	}, func(ctx adkagent.ToolContext, in writeFileInput) (string, error) {
-		path, err := resolveWritePath(ctx.SessionID(), absSkillsDir, in.FilePath)
+		sessionPath, err := workspace.Prepare(ctx, ctx.SessionID())
+		if err != nil {
+			return fmt.Sprintf("Error writing file %s: %v", strings.TrimSpace(in.FilePath), err), nil
+		}
+		path, err := resolveWritePath(sessionPath, absSkillsDir, in.FilePath)
 		if err != nil {
 			return fmt.Sprintf("Error writing file %s: %v", strings.TrimSpace(in.FilePath), err), nil
 		}
@@ -185,35 +235,77 @@ func NewSkillsTools(skillsDirectory string) ([]tool.Tool, error) {
 		Name:        "edit_file",
 		Description: editFileDescription,
 	}, func(ctx adkagent.ToolContext, in editFileInput) (string, error) {
-		path, err := resolveEditPath(ctx.SessionID(), absSkillsDir, in.FilePath)
+		sessionPath, err := workspace.Prepare(ctx, ctx.SessionID())
+		if err != nil {
+			return fmt.Sprintf("Error editing file %s: %v", strings.TrimSpace(in.FilePath), err), nil
+		}
+		path, err := resolveEditPath(sessionPath, absSkillsDir, in.FilePath)
 		if err != nil {
 			return fmt.Sprintf("Error editing file %s: %v", strings.TrimSpace(in.FilePath), err), nil
 		}
 
-		if err := skillruntime.EditFileContent(path, in.OldString, in.NewString, in.ReplaceAll); err != nil {
+		var matches []skillruntime.EditMatch
+		if strings.TrimSpace(in.Diff) != "" {
+			matches, err = skillruntime.ApplyDiffToFile(path, in.Diff)
+		} else {
+			matches, err = skillruntime.MultiEditFileContent(path, []skillruntime.Edit{{OldString: in.OldString, NewString: in.NewString, ReplaceAll: in.ReplaceAll}})
+		}
+		if err != nil {
 			return fmt.Sprintf("Error editing file %s: %v", strings.TrimSpace(in.FilePath), err), nil
 		}
-		return fmt.Sprintf("Successfully edited file: %s", path), nil
+		return fmt.Sprintf("Successfully edited file: %s", path) + describeFuzzyMatches(matches), nil
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create edit_file tool: %w", err)
 	}
 
-	bashTool, err := functiontool.New(functiontool.Config{
+	multiEditFileTool, err := functiontool.New(functiontool.Config{
+		Name:        "multi_edit_file",
+		Description: multiEditFileDescription,
+	}, func(ctx adkagent.ToolContext, in multiEditFileInput) (string, error) {
+		sessionPath, err := workspace.Prepare(ctx, ctx.SessionID())
+		if err != nil {
+			return fmt.Sprintf("Error editing file %s: %v", strings.TrimSpace(in.FilePath), err), nil
+		}
+		path, err := resolveEditPath(sessionPath, absSkillsDir, in.FilePath)
+		if err != nil {
+			return fmt.Sprintf("Error editing file %s: %v", strings.TrimSpace(in.FilePath), err), nil
+		}
+
+		matches, err := skillruntime.MultiEditFileContent(path, in.Edits)
+		if err != nil {
+			return fmt.Sprintf("Error editing file %s: %v", strings.TrimSpace(in.FilePath), err), nil
+		}
+		return fmt.Sprintf("Successfully applied %d edits to file: %s", len(matches), path) + describeFuzzyMatches(matches), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create multi_edit_file tool: %w", err)
+	}
+
+	description := bashDescription
+	if shells != nil {
+		description += bashPersistentShellDescription
+	}
+	bashTool, err := NewStreamingTool(functiontool.Config{
 		Name:        "bash",
-		Description: bashDescription,
-	}, func(ctx adkagent.ToolContext, in bashInput) (string, error) {
+		Description: description,
+	}, func(ctx adkagent.ToolContext, in bashInput, out io.Writer) (string, error) {
 		command := strings.TrimSpace(in.Command)
 		if command == "" {
 			return "Error: No command provided", nil
 		}
 
-		sessionPath, err := skillruntime.GetSessionPath(ctx.SessionID(), absSkillsDir)
+		sessionPath, err := workspace.Prepare(ctx, ctx.SessionID())
 		if err != nil {
 			return fmt.Sprintf("Error executing command %q: %v", command, err), nil
 		}
 
-		result, err := commandExecutor.ExecuteCommand(ctx, command, sessionPath)
+		var result string
+		if shells != nil {
+			result, err = shells.ExecuteCommand(ctx, ctx.SessionID(), command, sessionPath, out)
+		} else {
+			result, err = commandExecutor.ExecuteCommandStream(ctx, command, sessionPath, out)
+		}
 		if err != nil {
 			return fmt.Sprintf("Error executing command %q: %v", command, err), nil
 		}
@@ -223,15 +315,37 @@ func NewSkillsTools(skillsDirectory string) ([]tool.Tool, error) {
 		return nil, fmt.Errorf("failed to create bash tool: %w", err)
 	}
 
-	return []tool.Tool{skillsTool, readFileTool, writeFileTool, editFileTool, bashTool}, nil
-}
+	executePythonTool, err := newExecutePythonTool(python, workspace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create execute_python tool: %w", err)
+	}
 
-func resolveReadPath(sessionID, skillsDirectory, requestedPath string) (string, error) {
-	sessionPath, err := skillruntime.GetSessionPath(sessionID, skillsDirectory)
+	gitTools, err := newGitTools(git, workspace, absSkillsDir)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
+	return append([]tool.Tool{skillsTool, readFileTool, writeFileTool, editFileTool, multiEditFileTool, bashTool, executePythonTool}, gitTools...), nil
+}
+
+// describeFuzzyMatches lists the edits that were applied by fuzzy matching,
+// so the model can check them.
+func describeFuzzyMatches(matches []skillruntime.EditMatch) string {
+	var b strings.Builder
+	for i, m := range matches {
+		if !m.Fuzzy {
+			continue
+		}
+		if len(matches) == 1 {
+			fmt.Fprintf(&b, "\nNo exact match; applied at line %d with %.0f%% confidence. Read the file to check the result.", m.Line, m.Confidence*100)
+		} else {
+			fmt.Fprintf(&b, "\nEdit %d had no exact match; applied at line %d with %.0f%% confidence.", i+1, m.Line, m.Confidence*100)
+		}
+	}
+	return b.String()
+}
+
+func resolveReadPath(sessionPath, skillsDirectory, requestedPath string) (string, error) {
 	candidate, err := resolveRequestedPath(sessionPath, requestedPath)
 	if err != nil {
 		return "", err
@@ -258,12 +372,7 @@ func resolveReadPath(sessionID, skillsDirectory, requestedPath string) (string,
 	return resolvedCandidate, nil
 }
 
-func resolveEditPath(sessionID, skillsDirectory, requestedPath string) (string, error) {
-	sessionPath, err := skillruntime.GetSessionPath(sessionID, skillsDirectory)
-	if err != nil {
-		return "", err
-	}
-
+func resolveEditPath(sessionPath, skillsDirectory, requestedPath string) (string, error) {
 	candidate, err := resolveRequestedPath(sessionPath, requestedPath)
 	if err != nil {
 		return "", err
@@ -285,12 +394,7 @@ func resolveEditPath(sessionID, skillsDirectory, requestedPath string) (string,
 	return resolvedCandidate, nil
 }
 
-func resolveWritePath(sessionID, skillsDirectory, requestedPath string) (string, error) {
-	sessionPath, err := skillruntime.GetSessionPath(sessionID, skillsDirectory)
-	if err != nil {
-		return "", err
-	}
-
+func resolveWritePath(sessionPath, skillsDirectory, requestedPath string) (string, error) {
 	candidate, err := resolveRequestedPath(sessionPath, requestedPath)
 	if err != nil {
 		return "", err
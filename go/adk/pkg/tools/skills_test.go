@@ -6,6 +6,8 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	skillruntime "github.com/kagent-dev/kagent/go/adk/pkg/skills"
 )
 
 func TestResolveReadPath_AllowsSymlinkedSkillsDirectory(t *testing.T) {
@@ -16,8 +18,11 @@ func TestResolveReadPath_AllowsSymlinkedSkillsDirectory(t *testing.T) {
 		t.Fatalf("failed to write skill file: %v", err)
 	}
 
-	sessionID := fmt.Sprintf("%s-read", t.Name())
-	resolved, err := resolveReadPath(sessionID, skillsDir, "skills/script.py")
+	sessionPath, err := skillruntime.GetSessionPath(fmt.Sprintf("%s-read", t.Name()), skillsDir)
+	if err != nil {
+		t.Fatalf("GetSessionPath() error = %v", err)
+	}
+	resolved, err := resolveReadPath(sessionPath, skillsDir, "skills/script.py")
 	if err != nil {
 		t.Fatalf("resolveReadPath() error = %v", err)
 	}
@@ -33,8 +38,11 @@ func TestResolveReadPath_AllowsSymlinkedSkillsDirectory(t *testing.T) {
 func TestResolveWritePath_BlocksSkillsSymlink(t *testing.T) {
 	t.Setenv("TMPDIR", t.TempDir())
 	skillsDir := t.TempDir()
-	sessionID := fmt.Sprintf("%s-write", t.Name())
-	_, err := resolveWritePath(sessionID, skillsDir, "skills/new-file.txt")
+	sessionPath, err := skillruntime.GetSessionPath(fmt.Sprintf("%s-write", t.Name()), skillsDir)
+	if err != nil {
+		t.Fatalf("GetSessionPath() error = %v", err)
+	}
+	_, err = resolveWritePath(sessionPath, skillsDir, "skills/new-file.txt")
 	if err == nil {
 		t.Fatal("expected write through skills symlink to be rejected")
 	}
@@ -68,7 +76,7 @@ description: Demo skill.
 		got[tool.Name()] = true
 	}
 
-	for _, name := range []string{"skills", "read_file", "write_file", "edit_file", "bash"} {
+	for _, name := range []string{"skills", "read_file", "write_file", "edit_file", "multi_edit_file", "bash", "execute_python", "git_status", "git_diff", "git_commit", "git_apply_patch"} {
 		if !got[name] {
 			t.Errorf("expected tool %q to be present", name)
 		}
@@ -0,0 +1,57 @@
+package tools
+
+import (
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	"github.com/kagent-dev/kagent/go/adk/pkg/a2a"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+// MaxStreamingToolResultBytes caps the result a streaming tool returns to
+// the model. A2A clients have already received the full output as deltas.
+const MaxStreamingToolResultBytes = 64 << 10
+
+// StreamingFunc runs a long-running tool call. It writes output to out as
+// the output is produced and returns the result the model sees.
+type StreamingFunc[TArgs any] func(ctx tool.Context, args TArgs, out io.Writer) (string, error)
+
+// NewStreamingTool creates a function tool whose output is streamed while
+// it runs: every write to out reaches the A2A client as a tool output delta.
+// Outside an A2A run, writes are discarded. The returned result is
+// truncated to MaxStreamingToolResultBytes before the model sees it.
+//
+// Unlike ADK streaming function tools, the call still goes through the
+// agent's tool callbacks, so policy, approval and audit apply as usual.
+func NewStreamingTool[TArgs any](cfg functiontool.Config, fn StreamingFunc[TArgs]) (tool.Tool, error) {
+	return functiontool.New(cfg, func(ctx tool.Context, args TArgs) (string, error) {
+		out := a2a.ToolOutputWriter(ctx, cfg.Name)
+		if out == nil {
+			out = io.Discard
+		}
+		result, err := fn(ctx, args, out)
+		return TruncateOutput(result, MaxStreamingToolResultBytes), err
+	})
+}
+
+// TruncateOutput shortens s to about limit bytes by dropping the middle. The
+// start usually names what ran and the end holds errors and summaries, so
+// both are kept.
+func TruncateOutput(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	head := runeBoundary(s, limit/4)
+	tail := runeBoundary(s, len(s)-(limit-head))
+	return fmt.Sprintf("%s\n... [%d bytes truncated] ...\n%s", s[:head], tail-head, s[tail:])
+}
+
+// runeBoundary moves i back to the start of the rune it falls in.
+func runeBoundary(s string, i int) int {
+	for i > 0 && i < len(s) && !utf8.RuneStart(s[i]) {
+		i--
+	}
+	return i
+}
@@ -0,0 +1,79 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/kagent-dev/kagent/go/adk/pkg/a2a"
+	adkagent "google.golang.org/adk/agent"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+	"google.golang.org/adk/tool/toolconfirmation"
+)
+
+type streamingTestToolContext struct {
+	adkagent.ToolContext
+	ctx context.Context
+}
+
+func (c streamingTestToolContext) Value(key any) any                                    { return c.ctx.Value(key) }
+func (c streamingTestToolContext) FunctionCallID() string                               { return "call_1" }
+func (c streamingTestToolContext) ToolConfirmation() *toolconfirmation.ToolConfirmation { return nil }
+
+func TestTruncateOutput(t *testing.T) {
+	if got := TruncateOutput("short", 10); got != "short" {
+		t.Errorf("TruncateOutput(short) = %q, want it unchanged", got)
+	}
+
+	s := "BEGIN" + strings.Repeat("é", 1000) + "END"
+	got := TruncateOutput(s, 200)
+	if !strings.HasPrefix(got, "BEGIN") || !strings.HasSuffix(got, "END") {
+		t.Errorf("TruncateOutput() = %q, want the start and end kept", got)
+	}
+	if !strings.Contains(got, "bytes truncated") || len(got) > 250 {
+		t.Errorf("TruncateOutput() returned %d bytes, want about 200 with a truncation marker", len(got))
+	}
+	if !strings.HasSuffix(strings.TrimSuffix(got, "END"), "é") {
+		t.Errorf("TruncateOutput() split a rune: %q", got)
+	}
+}
+
+func TestNewStreamingTool(t *testing.T) {
+	var deltas []string
+	ctx := a2a.WithToolOutputSink(context.Background(), func(callID, toolName, chunk string) {
+		deltas = append(deltas, fmt.Sprintf("%s/%s: %s", toolName, callID, chunk))
+	})
+
+	type input struct {
+		Lines int `json:"lines"`
+	}
+	streamTool, err := NewStreamingTool(functiontool.Config{Name: "count", Description: "counts"}, func(_ tool.Context, in input, out io.Writer) (string, error) {
+		var all strings.Builder
+		for i := range in.Lines {
+			line := fmt.Sprintf("line %d\n", i)
+			fmt.Fprint(out, line)
+			all.WriteString(line)
+		}
+		return all.String(), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := streamTool.(interface {
+		Run(tool.Context, any) (map[string]any, error)
+	}).Run(streamingTestToolContext{ctx: ctx}, map[string]any{"lines": 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result["result"] != "line 0\nline 1\n" {
+		t.Errorf("result = %v, want both lines", result)
+	}
+	want := []string{"count/call_1: line 0\n", "count/call_1: line 1\n"}
+	if strings.Join(deltas, "|") != strings.Join(want, "|") {
+		t.Errorf("deltas = %q, want %q", deltas, want)
+	}
+}
@@ -5,6 +5,9 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"fmt"
+	"path"
+	"slices"
+	"strings"
 )
 
 type StreamableHTTPConnectionParams struct {
@@ -24,6 +27,11 @@ type HttpMcpServerConfig struct {
 	Tools           []string                       `json:"tools,omitempty"`
 	AllowedHeaders  []string                       `json:"allowed_headers,omitempty"`
 	RequireApproval []string                       `json:"require_approval,omitempty"`
+	RetryPolicy     *ToolRetryPolicy               `json:"retry_policy,omitempty"`
+	// ToolPrefix, when set, exposes the server's tools to the model as
+	// <tool_prefix>__<tool>. Tools and RequireApproval keep the server's
+	// own tool names.
+	ToolPrefix string `json:"tool_prefix,omitempty"`
 }
 
 type SseConnectionParams struct {
@@ -42,6 +50,21 @@ type SseMcpServerConfig struct {
 	Tools           []string            `json:"tools,omitempty"`
 	AllowedHeaders  []string            `json:"allowed_headers,omitempty"`
 	RequireApproval []string            `json:"require_approval,omitempty"`
+	RetryPolicy     *ToolRetryPolicy    `json:"retry_policy,omitempty"`
+	// ToolPrefix, when set, exposes the server's tools to the model as
+	// <tool_prefix>__<tool>. Tools and RequireApproval keep the server's
+	// own tool names.
+	ToolPrefix string `json:"tool_prefix,omitempty"`
+}
+
+// ToolRetryPolicy configures automatic retries for failed tool calls.
+// Backoff values are in seconds.
+type ToolRetryPolicy struct {
+	MaxAttempts     int      `json:"max_attempts"`
+	InitialBackoff  *float64 `json:"initial_backoff,omitempty"`
+	MaxBackoff      *float64 `json:"max_backoff,omitempty"`
+	RetryableErrors []string `json:"retryable_errors,omitempty"`
+	Tools           []string `json:"tools,omitempty"`
 }
 
 type Model interface {
@@ -61,6 +84,42 @@ type BaseModel struct {
 	// APIKeyPassthrough enables forwarding the Bearer token from incoming requests
 	// as the LLM API key instead of using a static secret.
 	APIKeyPassthrough bool `json:"api_key_passthrough,omitempty"`
+
+	// KeyPool load-balances requests over several API keys, and optionally
+	// endpoints, of the provider. When set, it replaces the provider's
+	// API key environment variable.
+	KeyPool []ModelKey `json:"key_pool,omitempty"`
+}
+
+// ModelKey is one API key of a model's key pool. Keys are picked by
+// weighted round robin; a key answering 429, or reporting no requests
+// left, is skipped until its rate limit resets.
+type ModelKey struct {
+	// APIKeyEnv names the environment variable holding the key.
+	APIKeyEnv string `json:"api_key_env"`
+	// BaseUrl sends the key's requests to another endpoint of the same
+	// API, such as another region or Azure resource.
+	BaseUrl string `json:"base_url,omitempty"`
+	// Weight is the key's share of the requests. Defaults to 1.
+	Weight int `json:"weight,omitempty"`
+}
+
+// ValidateKeyPool reports key pool entries without an environment
+// variable or with a negative weight, and a key pool combined with API key
+// passthrough.
+func (b *BaseModel) ValidateKeyPool() error {
+	if len(b.KeyPool) > 0 && b.APIKeyPassthrough {
+		return fmt.Errorf("key_pool cannot be combined with api_key_passthrough")
+	}
+	for i, k := range b.KeyPool {
+		if k.APIKeyEnv == "" {
+			return fmt.Errorf("key_pool entry %d needs an api_key_env", i)
+		}
+		if k.Weight < 0 {
+			return fmt.Errorf("key_pool entry %s has a negative weight", k.APIKeyEnv)
+		}
+	}
+	return nil
 }
 
 // GDCHTokenExchangeConfig holds the GDCH-specific token exchange fields
@@ -104,6 +163,7 @@ const (
 	ModelTypeGemini          = "gemini"
 	ModelTypeBedrock         = "bedrock"
 	ModelTypeSAPAICore       = "sap_ai_core"
+	ModelTypeFake            = "fake"
 )
 
 func (o *OpenAI) MarshalJSON() ([]byte, error) {
@@ -152,6 +212,9 @@ type Anthropic struct {
 	TopP        *float64 `json:"top_p,omitempty"`
 	TopK        *int     `json:"top_k,omitempty"`
 	Timeout     *int     `json:"timeout,omitempty"`
+
+	// ThinkingBudgetTokens enables extended thinking with the given budget.
+	ThinkingBudgetTokens *int `json:"thinking_budget_tokens,omitempty"`
 }
 
 func (a *Anthropic) MarshalJSON() ([]byte, error) {
@@ -171,6 +234,13 @@ func (a *Anthropic) GetType() string {
 
 type GeminiVertexAI struct {
 	BaseModel
+	Temperature      *float64 `json:"temperature,omitempty"`
+	TopP             *float64 `json:"top_p,omitempty"`
+	TopK             *float64 `json:"top_k,omitempty"`
+	StopSequences    []string `json:"stop_sequences,omitempty"`
+	MaxOutputTokens  *int     `json:"max_output_tokens,omitempty"`
+	CandidateCount   *int     `json:"candidate_count,omitempty"`
+	ResponseMimeType string   `json:"response_mime_type,omitempty"`
 }
 
 func (g *GeminiVertexAI) MarshalJSON() ([]byte, error) {
@@ -190,6 +260,11 @@ func (g *GeminiVertexAI) GetType() string {
 
 type GeminiAnthropic struct {
 	BaseModel
+	MaxTokens     *int     `json:"max_tokens,omitempty"`
+	Temperature   *float64 `json:"temperature,omitempty"`
+	TopP          *float64 `json:"top_p,omitempty"`
+	TopK          *int     `json:"top_k,omitempty"`
+	StopSequences []string `json:"stop_sequences,omitempty"`
 }
 
 func (g *GeminiAnthropic) MarshalJSON() ([]byte, error) {
@@ -302,6 +377,29 @@ func (s *SAPAICore) GetType() string {
 	return ModelTypeSAPAICore
 }
 
+// Fake is a scripted model that replays canned responses from a YAML
+// script. It needs no API key and is meant for tests, demos and local
+// development; it is not exposed through the ModelConfig CRD.
+type Fake struct {
+	BaseModel
+	ScriptPath string `json:"script_path"`
+}
+
+func (f *Fake) MarshalJSON() ([]byte, error) {
+	type Alias Fake
+	return json.Marshal(&struct {
+		Type string `json:"type"`
+		*Alias
+	}{
+		Type:  ModelTypeFake,
+		Alias: (*Alias)(f),
+	})
+}
+
+func (f *Fake) GetType() string {
+	return ModelTypeFake
+}
+
 // GenericModel is a catch-all model type used by the Go ADK when the model
 // type doesn't match any known constant.
 type GenericModel struct {
@@ -370,6 +468,12 @@ func ParseModel(bytes []byte) (Model, error) {
 			return nil, err
 		}
 		return &sapAICore, nil
+	case ModelTypeFake:
+		var fake Fake
+		if err := json.Unmarshal(bytes, &fake); err != nil {
+			return nil, err
+		}
+		return &fake, nil
 	}
 	return nil, fmt.Errorf("unknown model type: %s", model.Type)
 }
@@ -450,6 +554,13 @@ type MemoryConfig struct {
 	Embedding *EmbeddingConfig `json:"embedding,omitempty"`
 }
 
+// KnowledgeConfig configures the retrieve_docs tool.
+type KnowledgeConfig struct {
+	Collections []string         `json:"collections"`
+	TopK        int              `json:"top_k,omitempty"`
+	Embedding   *EmbeddingConfig `json:"embedding,omitempty"`
+}
+
 type NetworkConfig struct {
 	AllowedDomains []string `json:"allowed_domains,omitempty"`
 }
@@ -507,9 +618,467 @@ type AgentConfig struct {
 	ExecuteCode   *bool                 `json:"execute_code,omitempty"`
 	Stream        *bool                 `json:"stream,omitempty"`
 	Memory        *MemoryConfig         `json:"memory,omitempty"`
+	Knowledge     *KnowledgeConfig      `json:"knowledge,omitempty"`
 	Network       *NetworkConfig        `json:"network,omitempty"`
 	ContextConfig *AgentContextConfig   `json:"context_config,omitempty"`
 	ShareTools    *bool                 `json:"share_tools,omitempty"`
+	// TaskStateRules choose the A2A task state reported when the agent stops
+	// on a long-running tool call. They are checked before the built-in
+	// defaults; unmatched calls report input-required.
+	TaskStateRules []TaskStateRule `json:"task_state_rules,omitempty"`
+	// ToolConflictPolicy decides which tool the model sees when a local
+	// tool and MCP tools share a name. Defaults to ToolConflictPolicyError.
+	ToolConflictPolicy ToolConflictPolicy `json:"tool_conflict_policy,omitempty"`
+	// Generation holds the agent's default sampling parameters. They take
+	// precedence over the model's own settings and can be overridden per
+	// request.
+	Generation *GenerationConfig `json:"generation,omitempty"`
+	// BestOf samples several candidates for each model call and keeps the
+	// best one.
+	BestOf *BestOfConfig `json:"best_of,omitempty"`
+	// Reflection has each final answer critiqued and revised before it is
+	// returned.
+	Reflection *ReflectionConfig `json:"reflection,omitempty"`
+	// Strategy selects the agent loop. Defaults to AgentStrategyReact.
+	Strategy AgentStrategy `json:"strategy,omitempty"`
+	// Langfuse exports the agent's model generations, tool spans and
+	// feedback scores to Langfuse.
+	Langfuse *LangfuseConfig `json:"langfuse,omitempty"`
+	// Prompts versions the agent's instruction and generation settings and
+	// can split conversations between two versions.
+	Prompts *PromptConfig `json:"prompts,omitempty"`
+	// ToolArgTransforms rewrite the arguments of tool calls, in order, before
+	// the calls are authorized and run.
+	ToolArgTransforms []ToolArgTransform `json:"tool_arg_transforms,omitempty"`
+}
+
+// LangfuseConfig configures the Langfuse exporter. Host, PublicKey and
+// SecretKey fall back to the LANGFUSE_HOST, LANGFUSE_PUBLIC_KEY and
+// LANGFUSE_SECRET_KEY env vars, so the keys can come from a secret.
+type LangfuseConfig struct {
+	// Host is the Langfuse URL. Defaults to https://cloud.langfuse.com.
+	Host      string `json:"host,omitempty"`
+	PublicKey string `json:"public_key,omitempty"`
+	SecretKey string `json:"secret_key,omitempty"`
+	// BatchSize is the most events sent per ingestion request. Defaults to
+	// 50.
+	BatchSize int `json:"batch_size,omitempty"`
+	// FlushInterval is how often, in seconds, buffered events are sent.
+	// Defaults to 5.
+	FlushInterval *float64 `json:"flush_interval,omitempty"`
+	// MaxRetries is how many times a failed ingestion request is retried.
+	// Defaults to 3.
+	MaxRetries *int `json:"max_retries,omitempty"`
+	// InputCostPerMillion and OutputCostPerMillion price the tokens of the
+	// agent's model, in USD per million tokens, for the generation costs.
+	// When unset, Langfuse prices generations from its own model table.
+	InputCostPerMillion  *float64 `json:"input_cost_per_million,omitempty"`
+	OutputCostPerMillion *float64 `json:"output_cost_per_million,omitempty"`
+}
+
+// Validate reports out-of-range batching, retry or cost settings.
+func (l *LangfuseConfig) Validate() error {
+	if l == nil {
+		return nil
+	}
+	if l.BatchSize < 0 {
+		return fmt.Errorf("langfuse batch_size must not be negative, got %d", l.BatchSize)
+	}
+	if l.FlushInterval != nil && *l.FlushInterval <= 0 {
+		return fmt.Errorf("langfuse flush_interval must be positive, got %v", *l.FlushInterval)
+	}
+	if l.MaxRetries != nil && *l.MaxRetries < 0 {
+		return fmt.Errorf("langfuse max_retries must not be negative, got %d", *l.MaxRetries)
+	}
+	if (l.InputCostPerMillion != nil && *l.InputCostPerMillion < 0) || (l.OutputCostPerMillion != nil && *l.OutputCostPerMillion < 0) {
+		return fmt.Errorf("langfuse token costs must not be negative")
+	}
+	return nil
+}
+
+// PromptConfig holds versions of the agent's prompt. Runs use the Active
+// version unless the experiment assigns their conversation another one or
+// the request pins a version.
+type PromptConfig struct {
+	Versions []PromptVersion `json:"versions"`
+	// Active names the version used outside the experiment. Defaults to the
+	// last version.
+	Active string `json:"active,omitempty"`
+	// Experiment splits conversations between two versions.
+	Experiment *PromptExperiment `json:"experiment,omitempty"`
+}
+
+// PromptVersion is one version of the agent's prompt.
+type PromptVersion struct {
+	// Version names the version, e.g. "v2". Names are unique.
+	Version string `json:"version"`
+	// Instruction replaces the agent's instruction. Empty keeps it.
+	Instruction string `json:"instruction,omitempty"`
+	// Generation is merged over the agent's generation config; request
+	// overrides still take precedence.
+	Generation *GenerationConfig `json:"generation,omitempty"`
+}
+
+// PromptExperiment routes CandidatePercent of the conversations to the
+// Candidate version and the others to Control. A conversation keeps its
+// version for all its runs.
+type PromptExperiment struct {
+	Name      string `json:"name"`
+	Control   string `json:"control"`
+	Candidate string `json:"candidate"`
+	// CandidatePercent is between 0 and 100.
+	CandidatePercent int `json:"candidate_percent"`
+}
+
+// Version returns the version named name, or nil.
+func (p *PromptConfig) Version(name string) *PromptVersion {
+	if p == nil {
+		return nil
+	}
+	for i := range p.Versions {
+		if p.Versions[i].Version == name {
+			return &p.Versions[i]
+		}
+	}
+	return nil
+}
+
+// ActiveVersion returns the version used outside the experiment.
+func (p *PromptConfig) ActiveVersion() *PromptVersion {
+	if p == nil || len(p.Versions) == 0 {
+		return nil
+	}
+	if p.Active == "" {
+		return &p.Versions[len(p.Versions)-1]
+	}
+	return p.Version(p.Active)
+}
+
+// Validate reports duplicate or unknown versions and an invalid experiment.
+func (p *PromptConfig) Validate() error {
+	if p == nil {
+		return nil
+	}
+	if len(p.Versions) == 0 {
+		return fmt.Errorf("prompts must have at least one version")
+	}
+	seen := make(map[string]bool, len(p.Versions))
+	for i, v := range p.Versions {
+		if v.Version == "" {
+			return fmt.Errorf("prompt version %d has no name", i)
+		}
+		if seen[v.Version] {
+			return fmt.Errorf("duplicate prompt version %q", v.Version)
+		}
+		seen[v.Version] = true
+		if err := v.Generation.Validate(); err != nil {
+			return fmt.Errorf("prompt version %q: %w", v.Version, err)
+		}
+	}
+	if p.Active != "" && !seen[p.Active] {
+		return fmt.Errorf("unknown active prompt version %q", p.Active)
+	}
+	if e := p.Experiment; e != nil {
+		if e.Name == "" {
+			return fmt.Errorf("prompt experiment has no name")
+		}
+		for _, name := range []string{e.Control, e.Candidate} {
+			if !seen[name] {
+				return fmt.Errorf("prompt experiment %q: unknown version %q", e.Name, name)
+			}
+		}
+		if e.Control == e.Candidate {
+			return fmt.Errorf("prompt experiment %q compares version %q with itself", e.Name, e.Control)
+		}
+		if e.CandidatePercent < 0 || e.CandidatePercent > 100 {
+			return fmt.Errorf("prompt experiment %q: candidate_percent must be between 0 and 100, got %d", e.Name, e.CandidatePercent)
+		}
+	}
+	return nil
+}
+
+// AgentStrategy names the loop an agent runs to answer a request.
+type AgentStrategy string
+
+const (
+	// AgentStrategyReact lets the model alternate between reasoning and tool
+	// calls until it answers.
+	AgentStrategyReact AgentStrategy = "react"
+	// AgentStrategyPlanExecute has the model write a checklist plan first,
+	// then works through it one step at a time, re-planning the remaining
+	// steps when one fails.
+	AgentStrategyPlanExecute AgentStrategy = "plan-execute"
+)
+
+// Validate reports an unknown strategy.
+func (s AgentStrategy) Validate() error {
+	switch s {
+	case "", AgentStrategyReact, AgentStrategyPlanExecute:
+		return nil
+	default:
+		return fmt.Errorf("unknown agent strategy %q", s)
+	}
+}
+
+// BestOfScorer names how the best of several candidates is chosen.
+type BestOfScorer string
+
+const (
+	// BestOfScorerHeuristic prefers complete, non-empty answers, and the
+	// earliest candidate among equals.
+	BestOfScorerHeuristic BestOfScorer = "heuristic"
+	// BestOfScorerJudge asks the agent's model which candidate is best,
+	// falling back to the heuristic when it gives no usable answer.
+	BestOfScorerJudge BestOfScorer = "judge"
+)
+
+// MaxBestOfCandidates bounds BestOfConfig.Candidates.
+const MaxBestOfCandidates = 8
+
+// BestOfConfig configures multi-candidate sampling.
+type BestOfConfig struct {
+	// Candidates is the number of candidates sampled per model call,
+	// between 2 and MaxBestOfCandidates.
+	Candidates int `json:"candidates"`
+	// Scorer chooses the candidate kept. Defaults to BestOfScorerHeuristic.
+	Scorer BestOfScorer `json:"scorer,omitempty"`
+}
+
+// Validate reports an invalid candidate count or scorer.
+func (b *BestOfConfig) Validate() error {
+	if b == nil {
+		return nil
+	}
+	if b.Candidates < 2 || b.Candidates > MaxBestOfCandidates {
+		return fmt.Errorf("best_of candidates must be between 2 and %d, got %d", MaxBestOfCandidates, b.Candidates)
+	}
+	switch b.Scorer {
+	case "", BestOfScorerHeuristic, BestOfScorerJudge:
+		return nil
+	default:
+		return fmt.Errorf("unknown best_of scorer %q", b.Scorer)
+	}
+}
+
+// MaxStopSequences is the most stop sequences a GenerationConfig may hold.
+// It is the lowest limit among the supported providers.
+const MaxStopSequences = 4
+
+// GenerationConfig holds sampling parameters applied to every model call.
+// Unset fields leave the model's settings unchanged.
+type GenerationConfig struct {
+	Temperature     *float64 `json:"temperature,omitempty"`
+	TopP            *float64 `json:"top_p,omitempty"`
+	TopK            *int     `json:"top_k,omitempty"`
+	MaxOutputTokens *int     `json:"max_output_tokens,omitempty"`
+	StopSequences   []string `json:"stop_sequences,omitempty"`
+}
+
+// Validate reports the first parameter that is out of range.
+func (g *GenerationConfig) Validate() error {
+	if g == nil {
+		return nil
+	}
+	if g.Temperature != nil && (*g.Temperature < 0 || *g.Temperature > 2) {
+		return fmt.Errorf("temperature must be between 0 and 2, got %v", *g.Temperature)
+	}
+	if g.TopP != nil && (*g.TopP < 0 || *g.TopP > 1) {
+		return fmt.Errorf("top_p must be between 0 and 1, got %v", *g.TopP)
+	}
+	if g.TopK != nil && *g.TopK < 1 {
+		return fmt.Errorf("top_k must be at least 1, got %d", *g.TopK)
+	}
+	if g.MaxOutputTokens != nil && *g.MaxOutputTokens < 1 {
+		return fmt.Errorf("max_output_tokens must be at least 1, got %d", *g.MaxOutputTokens)
+	}
+	if len(g.StopSequences) > MaxStopSequences {
+		return fmt.Errorf("at most %d stop sequences are allowed, got %d", MaxStopSequences, len(g.StopSequences))
+	}
+	for i, s := range g.StopSequences {
+		if s == "" {
+			return fmt.Errorf("stop sequence %d is empty", i)
+		}
+	}
+	return nil
+}
+
+// Merge returns g with the fields set in override replacing its own. Either
+// may be nil.
+func (g *GenerationConfig) Merge(override *GenerationConfig) *GenerationConfig {
+	if override == nil {
+		return g
+	}
+	if g == nil {
+		return override
+	}
+	out := *g
+	if override.Temperature != nil {
+		out.Temperature = override.Temperature
+	}
+	if override.TopP != nil {
+		out.TopP = override.TopP
+	}
+	if override.TopK != nil {
+		out.TopK = override.TopK
+	}
+	if override.MaxOutputTokens != nil {
+		out.MaxOutputTokens = override.MaxOutputTokens
+	}
+	if override.StopSequences != nil {
+		out.StopSequences = override.StopSequences
+	}
+	return &out
+}
+
+// MaxReflectionPasses bounds ReflectionConfig.MaxPasses.
+const MaxReflectionPasses = 3
+
+// ReflectionConfig configures the critique step run on draft final answers.
+// A draft the critique model approves is returned as is; otherwise the
+// agent's model revises it with the critique, up to MaxPasses times.
+type ReflectionConfig struct {
+	// CritiqueModel reviews the drafts. Defaults to the agent's model.
+	CritiqueModel Model `json:"critique_model,omitempty"`
+	// MaxPasses is how many revisions a draft may go through. Defaults to 1.
+	MaxPasses int `json:"max_passes,omitempty"`
+	// CritiquePrompt replaces the built-in review instructions. The critique
+	// model must still reply APPROVED to accept a draft.
+	CritiquePrompt string `json:"critique_prompt,omitempty"`
+}
+
+// GetMaxPasses returns MaxPasses, or its default of 1 when unset.
+func (r *ReflectionConfig) GetMaxPasses() int {
+	if r.MaxPasses > 0 {
+		return r.MaxPasses
+	}
+	return 1
+}
+
+// Validate reports an out-of-range pass count.
+func (r *ReflectionConfig) Validate() error {
+	if r == nil {
+		return nil
+	}
+	if r.MaxPasses < 0 || r.MaxPasses > MaxReflectionPasses {
+		return fmt.Errorf("reflection max_passes must be between 1 and %d, got %d", MaxReflectionPasses, r.MaxPasses)
+	}
+	return nil
+}
+
+func (r *ReflectionConfig) UnmarshalJSON(data []byte) error {
+	var tmp struct {
+		CritiqueModel  json.RawMessage `json:"critique_model,omitempty"`
+		MaxPasses      int             `json:"max_passes,omitempty"`
+		CritiquePrompt string          `json:"critique_prompt,omitempty"`
+	}
+	if err := json.Unmarshal(data, &tmp); err != nil {
+		return err
+	}
+	r.MaxPasses = tmp.MaxPasses
+	r.CritiquePrompt = tmp.CritiquePrompt
+	if len(tmp.CritiqueModel) > 0 && string(tmp.CritiqueModel) != "null" {
+		model, err := ParseModel(tmp.CritiqueModel)
+		if err != nil {
+			return fmt.Errorf("failed to parse critique model: %w", err)
+		}
+		r.CritiqueModel = model
+	}
+	return nil
+}
+
+// ToolConflictPolicy resolves tool name collisions between local tools
+// (built-in, memory, remote agent and skills tools) and MCP server tools.
+type ToolConflictPolicy string
+
+const (
+	// ToolConflictPolicyError fails the model request on any collision.
+	ToolConflictPolicyError ToolConflictPolicy = "error"
+	// ToolConflictPolicyPreferLocal keeps the local tool and drops
+	// colliding MCP tools.
+	ToolConflictPolicyPreferLocal ToolConflictPolicy = "prefer-local"
+	// ToolConflictPolicyPreferMCP keeps the MCP tool and drops the colliding
+	// local tool.
+	ToolConflictPolicyPreferMCP ToolConflictPolicy = "prefer-mcp"
+)
+
+// TaskStateRule maps a paused tool call to an A2A task state. Tool is a glob
+// matched against the function name; MetadataKey matches calls whose part
+// metadata carries the key (with or without the adk_/kagent_ prefix). A rule
+// with both set requires both to match.
+type TaskStateRule struct {
+	Tool        string `json:"tool,omitempty"`
+	MetadataKey string `json:"metadata_key,omitempty"`
+	State       string `json:"state"`
+}
+
+// ToolArgTransformOp names how a ToolArgTransform changes an argument.
+type ToolArgTransformOp string
+
+const (
+	// ToolArgTransformSet sets the argument to Value, whatever the model
+	// passed, e.g. to force dry_run to true.
+	ToolArgTransformSet ToolArgTransformOp = "set"
+	// ToolArgTransformDefault sets the argument to Value when the model left
+	// it out.
+	ToolArgTransformDefault ToolArgTransformOp = "default"
+	// ToolArgTransformPrefix prepends the directory Value, a string, to a
+	// path argument that is not already Value or below it. Calls whose
+	// argument contains a ".." path element are rejected, so the prefix
+	// confines paths.
+	ToolArgTransformPrefix ToolArgTransformOp = "prefix"
+	// ToolArgTransformMax caps a numeric argument at Value.
+	ToolArgTransformMax ToolArgTransformOp = "max"
+	// ToolArgTransformMin raises a numeric argument to Value.
+	ToolArgTransformMin ToolArgTransformOp = "min"
+	// ToolArgTransformRemove drops the argument.
+	ToolArgTransformRemove ToolArgTransformOp = "remove"
+)
+
+// ToolArgTransform rewrites one argument of the calls to matching tools.
+// Calls whose argument does not have the type the operation needs are
+// rejected rather than run.
+type ToolArgTransform struct {
+	// Tool is a glob matched against the tool name the model sees.
+	Tool string `json:"tool"`
+	// Arg names the argument; dots address fields of object arguments, as
+	// in "options.limit".
+	Arg   string             `json:"arg"`
+	Op    ToolArgTransformOp `json:"op"`
+	Value any                `json:"value,omitempty"`
+}
+
+// Validate reports an invalid glob, an unknown operation or a value that
+// does not suit the operation.
+func (t *ToolArgTransform) Validate() error {
+	if t.Tool == "" {
+		return fmt.Errorf("tool arg transform needs a tool")
+	}
+	if _, err := path.Match(t.Tool, ""); err != nil {
+		return fmt.Errorf("invalid tool arg transform tool %q: %w", t.Tool, err)
+	}
+	if t.Arg == "" || slices.Contains(strings.Split(t.Arg, "."), "") {
+		return fmt.Errorf("invalid tool arg transform arg %q for tool %q", t.Arg, t.Tool)
+	}
+	switch t.Op {
+	case ToolArgTransformSet, ToolArgTransformDefault:
+		if t.Value == nil {
+			return fmt.Errorf("tool arg transform %s of %s.%s needs a value", t.Op, t.Tool, t.Arg)
+		}
+	case ToolArgTransformPrefix:
+		if s, ok := t.Value.(string); !ok || s == "" {
+			return fmt.Errorf("tool arg transform prefix of %s.%s needs a non-empty string value", t.Tool, t.Arg)
+		}
+	case ToolArgTransformMax, ToolArgTransformMin:
+		switch t.Value.(type) {
+		case float64, int, int64, json.Number:
+		default:
+			return fmt.Errorf("tool arg transform %s of %s.%s needs a numeric value", t.Op, t.Tool, t.Arg)
+		}
+	case ToolArgTransformRemove:
+	default:
+		return fmt.Errorf("unknown tool arg transform op %q", t.Op)
+	}
+	return nil
 }
 
 // GetStream returns the stream value or default if not set
@@ -530,18 +1099,28 @@ func (a *AgentConfig) GetExecuteCode() bool {
 
 func (a *AgentConfig) UnmarshalJSON(data []byte) error {
 	var tmp struct {
-		Model         json.RawMessage       `json:"model"`
-		Description   string                `json:"description"`
-		Instruction   string                `json:"instruction"`
-		HttpTools     []HttpMcpServerConfig `json:"http_tools,omitempty"`
-		SseTools      []SseMcpServerConfig  `json:"sse_tools,omitempty"`
-		RemoteAgents  []RemoteAgentConfig   `json:"remote_agents,omitempty"`
-		ExecuteCode   *bool                 `json:"execute_code,omitempty"`
-		Stream        *bool                 `json:"stream,omitempty"`
-		Memory        json.RawMessage       `json:"memory"`
-		Network       *NetworkConfig        `json:"network,omitempty"`
-		ContextConfig *AgentContextConfig   `json:"context_config,omitempty"`
-		ShareTools    *bool                 `json:"share_tools,omitempty"`
+		Model              json.RawMessage       `json:"model"`
+		Description        string                `json:"description"`
+		Instruction        string                `json:"instruction"`
+		HttpTools          []HttpMcpServerConfig `json:"http_tools,omitempty"`
+		SseTools           []SseMcpServerConfig  `json:"sse_tools,omitempty"`
+		RemoteAgents       []RemoteAgentConfig   `json:"remote_agents,omitempty"`
+		ExecuteCode        *bool                 `json:"execute_code,omitempty"`
+		Stream             *bool                 `json:"stream,omitempty"`
+		Memory             json.RawMessage       `json:"memory"`
+		Knowledge          *KnowledgeConfig      `json:"knowledge,omitempty"`
+		Network            *NetworkConfig        `json:"network,omitempty"`
+		ContextConfig      *AgentContextConfig   `json:"context_config,omitempty"`
+		ShareTools         *bool                 `json:"share_tools,omitempty"`
+		TaskStateRules     []TaskStateRule       `json:"task_state_rules,omitempty"`
+		ToolConflictPolicy ToolConflictPolicy    `json:"tool_conflict_policy,omitempty"`
+		Generation         *GenerationConfig     `json:"generation,omitempty"`
+		BestOf             *BestOfConfig         `json:"best_of,omitempty"`
+		Reflection         *ReflectionConfig     `json:"reflection,omitempty"`
+		Strategy           AgentStrategy         `json:"strategy,omitempty"`
+		Langfuse           *LangfuseConfig       `json:"langfuse,omitempty"`
+		Prompts            *PromptConfig         `json:"prompts,omitempty"`
+		ToolArgTransforms  []ToolArgTransform    `json:"tool_arg_transforms,omitempty"`
 	}
 	if err := json.Unmarshal(data, &tmp); err != nil {
 		return err
@@ -569,9 +1148,19 @@ func (a *AgentConfig) UnmarshalJSON(data []byte) error {
 	a.ExecuteCode = tmp.ExecuteCode
 	a.Stream = tmp.Stream
 	a.Memory = memory
+	a.Knowledge = tmp.Knowledge
 	a.Network = tmp.Network
 	a.ContextConfig = tmp.ContextConfig
 	a.ShareTools = tmp.ShareTools
+	a.TaskStateRules = tmp.TaskStateRules
+	a.ToolConflictPolicy = tmp.ToolConflictPolicy
+	a.Generation = tmp.Generation
+	a.BestOf = tmp.BestOf
+	a.Reflection = tmp.Reflection
+	a.Strategy = tmp.Strategy
+	a.Langfuse = tmp.Langfuse
+	a.Prompts = tmp.Prompts
+	a.ToolArgTransforms = tmp.ToolArgTransforms
 	return nil
 }
 
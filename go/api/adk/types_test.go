@@ -19,6 +19,7 @@ func TestMarshalJSON_TypeDiscriminator(t *testing.T) {
 		{name: "Ollama", model: &Ollama{BaseModel: BaseModel{Model: "llama3"}}, wantType: ModelTypeOllama},
 		{name: "Gemini", model: &Gemini{BaseModel: BaseModel{Model: "gemini-pro"}}, wantType: ModelTypeGemini},
 		{name: "Bedrock", model: &Bedrock{BaseModel: BaseModel{Model: "claude-v2"}}, wantType: ModelTypeBedrock},
+		{name: "Fake", model: &Fake{ScriptPath: "script.yaml"}, wantType: ModelTypeFake},
 	}
 
 	for _, tt := range tests {
@@ -281,6 +282,201 @@ func TestAgentConfig_UnmarshalJSON_Network(t *testing.T) {
 	}
 }
 
+func TestAgentConfig_UnmarshalJSON_TaskStateRules(t *testing.T) {
+	configJSON := `{
+		"model": {
+			"type": "openai",
+			"model": "gpt-4o"
+		},
+		"instruction": "you are helpful",
+		"task_state_rules": [
+			{"tool": "oauth_*", "state": "auth-required"},
+			{"metadata_key": "needs_login", "state": "auth-required"}
+		]
+	}`
+
+	var cfg AgentConfig
+	if err := json.Unmarshal([]byte(configJSON), &cfg); err != nil {
+		t.Fatalf("failed to unmarshal config: %v", err)
+	}
+
+	want := []TaskStateRule{
+		{Tool: "oauth_*", State: "auth-required"},
+		{MetadataKey: "needs_login", State: "auth-required"},
+	}
+	if len(cfg.TaskStateRules) != len(want) {
+		t.Fatalf("task_state_rules len = %d, want %d", len(cfg.TaskStateRules), len(want))
+	}
+	for i := range want {
+		if cfg.TaskStateRules[i] != want[i] {
+			t.Errorf("task_state_rules[%d] = %+v, want %+v", i, cfg.TaskStateRules[i], want[i])
+		}
+	}
+}
+
+func TestAgentConfig_UnmarshalJSON_Generation(t *testing.T) {
+	configJSON := `{
+		"model": {"type": "openai", "model": "gpt-4o"},
+		"instruction": "you are helpful",
+		"generation": {"temperature": 0.2, "max_output_tokens": 512, "stop_sequences": ["END"]}
+	}`
+
+	var cfg AgentConfig
+	if err := json.Unmarshal([]byte(configJSON), &cfg); err != nil {
+		t.Fatalf("failed to unmarshal config: %v", err)
+	}
+	g := cfg.Generation
+	if g == nil {
+		t.Fatal("generation not parsed")
+	}
+	if g.Temperature == nil || *g.Temperature != 0.2 {
+		t.Errorf("temperature = %v, want 0.2", g.Temperature)
+	}
+	if g.MaxOutputTokens == nil || *g.MaxOutputTokens != 512 {
+		t.Errorf("max_output_tokens = %v, want 512", g.MaxOutputTokens)
+	}
+	if len(g.StopSequences) != 1 || g.StopSequences[0] != "END" {
+		t.Errorf("stop_sequences = %v, want [END]", g.StopSequences)
+	}
+	if g.TopP != nil || g.TopK != nil {
+		t.Errorf("unset fields were populated: %+v", g)
+	}
+}
+
+func TestGenerationConfig_Validate(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+	i := func(v int) *int { return &v }
+	tests := []struct {
+		name    string
+		cfg     *GenerationConfig
+		wantErr bool
+	}{
+		{name: "nil", cfg: nil},
+		{name: "valid", cfg: &GenerationConfig{Temperature: f(1), TopP: f(0.9), TopK: i(40), MaxOutputTokens: i(100), StopSequences: []string{"a", "b"}}},
+		{name: "temperature too high", cfg: &GenerationConfig{Temperature: f(2.5)}, wantErr: true},
+		{name: "negative temperature", cfg: &GenerationConfig{Temperature: f(-0.1)}, wantErr: true},
+		{name: "top_p too high", cfg: &GenerationConfig{TopP: f(1.1)}, wantErr: true},
+		{name: "zero top_k", cfg: &GenerationConfig{TopK: i(0)}, wantErr: true},
+		{name: "zero max tokens", cfg: &GenerationConfig{MaxOutputTokens: i(0)}, wantErr: true},
+		{name: "too many stop sequences", cfg: &GenerationConfig{StopSequences: []string{"a", "b", "c", "d", "e"}}, wantErr: true},
+		{name: "empty stop sequence", cfg: &GenerationConfig{StopSequences: []string{""}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGenerationConfig_Merge(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+	base := &GenerationConfig{Temperature: f(0.2), TopP: f(0.9), StopSequences: []string{"END"}}
+	override := &GenerationConfig{Temperature: f(0.7), StopSequences: []string{}}
+
+	got := base.Merge(override)
+	if *got.Temperature != 0.7 {
+		t.Errorf("temperature = %v, want the override 0.7", *got.Temperature)
+	}
+	if *got.TopP != 0.9 {
+		t.Errorf("top_p = %v, want the base 0.9", *got.TopP)
+	}
+	if got.StopSequences == nil || len(got.StopSequences) != 0 {
+		t.Errorf("stop_sequences = %v, want the override to clear them", got.StopSequences)
+	}
+	if *base.Temperature != 0.2 {
+		t.Error("Merge modified its receiver")
+	}
+	if (*GenerationConfig)(nil).Merge(override) != override || base.Merge(nil) != base {
+		t.Error("Merge with nil should return the other config")
+	}
+}
+
+func TestBestOfConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *BestOfConfig
+		wantErr bool
+	}{
+		{name: "nil", cfg: nil},
+		{name: "heuristic by default", cfg: &BestOfConfig{Candidates: 3}},
+		{name: "judge", cfg: &BestOfConfig{Candidates: MaxBestOfCandidates, Scorer: BestOfScorerJudge}},
+		{name: "one candidate", cfg: &BestOfConfig{Candidates: 1}, wantErr: true},
+		{name: "too many candidates", cfg: &BestOfConfig{Candidates: MaxBestOfCandidates + 1}, wantErr: true},
+		{name: "unknown scorer", cfg: &BestOfConfig{Candidates: 2, Scorer: "longest"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAgentConfig_UnmarshalJSON_Reflection(t *testing.T) {
+	configJSON := `{
+		"model": {"type": "openai", "model": "gpt-4o"},
+		"instruction": "you are helpful",
+		"reflection": {
+			"critique_model": {"type": "openai", "model": "gpt-4o-mini"},
+			"max_passes": 2
+		}
+	}`
+
+	var cfg AgentConfig
+	if err := json.Unmarshal([]byte(configJSON), &cfg); err != nil {
+		t.Fatalf("failed to unmarshal config: %v", err)
+	}
+	r := cfg.Reflection
+	if r == nil {
+		t.Fatal("reflection not parsed")
+	}
+	critic, ok := r.CritiqueModel.(*OpenAI)
+	if !ok || critic.Model != "gpt-4o-mini" {
+		t.Errorf("critique_model = %#v, want openai gpt-4o-mini", r.CritiqueModel)
+	}
+	if r.GetMaxPasses() != 2 {
+		t.Errorf("GetMaxPasses() = %d, want 2", r.GetMaxPasses())
+	}
+
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("failed to marshal config: %v", err)
+	}
+	var roundtrip AgentConfig
+	if err := json.Unmarshal(data, &roundtrip); err != nil {
+		t.Fatalf("failed to unmarshal roundtrip: %v", err)
+	}
+	if _, ok := roundtrip.Reflection.CritiqueModel.(*OpenAI); !ok {
+		t.Errorf("roundtrip critique_model = %#v, want *OpenAI", roundtrip.Reflection.CritiqueModel)
+	}
+
+	if (&ReflectionConfig{}).GetMaxPasses() != 1 {
+		t.Error("GetMaxPasses() should default to 1")
+	}
+	if err := (&ReflectionConfig{MaxPasses: MaxReflectionPasses + 1}).Validate(); err == nil {
+		t.Error("Validate() accepted too many passes")
+	}
+}
+
+func TestAgentConfig_UnmarshalJSON_Strategy(t *testing.T) {
+	var cfg AgentConfig
+	if err := json.Unmarshal([]byte(`{"model": {"type": "openai", "model": "gpt-4o"}, "strategy": "plan-execute"}`), &cfg); err != nil {
+		t.Fatalf("failed to unmarshal config: %v", err)
+	}
+	if cfg.Strategy != AgentStrategyPlanExecute {
+		t.Errorf("Strategy = %q, want %q", cfg.Strategy, AgentStrategyPlanExecute)
+	}
+	if err := cfg.Strategy.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	if err := AgentStrategy("tree-of-thought").Validate(); err == nil {
+		t.Error("Validate() accepted an unknown strategy")
+	}
+}
+
 func TestParseModel_Roundtrip(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -341,6 +537,11 @@ func TestParseModel_Roundtrip(t *testing.T) {
 			},
 			wantType: ModelTypeBedrock,
 		},
+		{
+			name:     "Fake roundtrip",
+			model:    &Fake{BaseModel: BaseModel{Model: "scripted"}, ScriptPath: "/etc/kagent/script.yaml"},
+			wantType: ModelTypeFake,
+		},
 	}
 
 	for _, tt := range tests {
@@ -904,3 +1105,114 @@ func TestAgentConfig_ScanAndValue(t *testing.T) {
 		t.Errorf("after Scan: Description = %q, want %q", scanned.Description, "test")
 	}
 }
+
+func TestAgentConfig_UnmarshalJSON_Langfuse(t *testing.T) {
+	var cfg AgentConfig
+	configJSON := `{
+		"model": {"type": "openai", "model": "gpt-4o"},
+		"langfuse": {"host": "https://langfuse.example.com", "public_key": "pk-lf-1", "batch_size": 20, "input_cost_per_million": 2.5}
+	}`
+	if err := json.Unmarshal([]byte(configJSON), &cfg); err != nil {
+		t.Fatalf("failed to unmarshal config: %v", err)
+	}
+	l := cfg.Langfuse
+	if l == nil || l.Host != "https://langfuse.example.com" || l.PublicKey != "pk-lf-1" || l.BatchSize != 20 || *l.InputCostPerMillion != 2.5 {
+		t.Fatalf("Langfuse = %+v", l)
+	}
+	if err := l.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	if err := (&LangfuseConfig{FlushInterval: new(0.0)}).Validate(); err == nil {
+		t.Error("Validate() accepted a zero flush interval")
+	}
+	if err := (&LangfuseConfig{MaxRetries: new(-1)}).Validate(); err == nil {
+		t.Error("Validate() accepted negative retries")
+	}
+}
+
+func TestAgentConfig_UnmarshalJSON_Prompts(t *testing.T) {
+	var cfg AgentConfig
+	configJSON := `{
+		"model": {"type": "openai", "model": "gpt-4o"},
+		"prompts": {
+			"versions": [
+				{"version": "v1", "instruction": "Be helpful."},
+				{"version": "v2", "instruction": "Be brief.", "generation": {"temperature": 0.2}}
+			],
+			"active": "v1",
+			"experiment": {"name": "brevity", "control": "v1", "candidate": "v2", "candidate_percent": 20}
+		}
+	}`
+	if err := json.Unmarshal([]byte(configJSON), &cfg); err != nil {
+		t.Fatalf("failed to unmarshal config: %v", err)
+	}
+	p := cfg.Prompts
+	if err := p.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if v := p.ActiveVersion(); v == nil || v.Instruction != "Be helpful." {
+		t.Errorf("ActiveVersion() = %+v, want v1", v)
+	}
+	if v := p.Version("v2"); v == nil || *v.Generation.Temperature != 0.2 {
+		t.Errorf("Version(v2) = %+v", v)
+	}
+	if e := p.Experiment; e.Name != "brevity" || e.CandidatePercent != 20 {
+		t.Errorf("Experiment = %+v", e)
+	}
+	if v := (&PromptConfig{Versions: p.Versions}).ActiveVersion(); v.Version != "v2" {
+		t.Errorf("ActiveVersion() = %q, want the last version by default", v.Version)
+	}
+
+	invalid := map[string]*PromptConfig{
+		"no versions":        {},
+		"duplicate version":  {Versions: []PromptVersion{{Version: "v1"}, {Version: "v1"}}},
+		"unknown active":     {Versions: p.Versions, Active: "v3"},
+		"unknown candidate":  {Versions: p.Versions, Experiment: &PromptExperiment{Name: "x", Control: "v1", Candidate: "v3"}},
+		"same versions":      {Versions: p.Versions, Experiment: &PromptExperiment{Name: "x", Control: "v1", Candidate: "v1"}},
+		"percent over 100":   {Versions: p.Versions, Experiment: &PromptExperiment{Name: "x", Control: "v1", Candidate: "v2", CandidatePercent: 101}},
+		"invalid generation": {Versions: []PromptVersion{{Version: "v1", Generation: &GenerationConfig{TopP: new(2.0)}}}},
+	}
+	for name, cfg := range invalid {
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("%s: Validate() error = nil", name)
+		}
+	}
+}
+
+func TestAgentConfig_UnmarshalJSON_KeyPool(t *testing.T) {
+	var cfg AgentConfig
+	configJSON := `{
+		"model": {
+			"type": "anthropic",
+			"model": "claude-sonnet-4",
+			"key_pool": [
+				{"api_key_env": "ANTHROPIC_KEY_A", "weight": 3},
+				{"api_key_env": "ANTHROPIC_KEY_B", "base_url": "https://eu.example.com"}
+			]
+		}
+	}`
+	if err := json.Unmarshal([]byte(configJSON), &cfg); err != nil {
+		t.Fatalf("failed to unmarshal config: %v", err)
+	}
+	m, ok := cfg.Model.(*Anthropic)
+	if !ok {
+		t.Fatalf("Model = %T, want *Anthropic", cfg.Model)
+	}
+	if err := m.ValidateKeyPool(); err != nil {
+		t.Fatalf("ValidateKeyPool() error = %v", err)
+	}
+	if len(m.KeyPool) != 2 || m.KeyPool[0].Weight != 3 || m.KeyPool[1].BaseUrl != "https://eu.example.com" {
+		t.Errorf("KeyPool = %+v", m.KeyPool)
+	}
+
+	invalid := map[string]BaseModel{
+		"no env":          {KeyPool: []ModelKey{{Weight: 1}}},
+		"negative weight": {KeyPool: []ModelKey{{APIKeyEnv: "K", Weight: -1}}},
+		"passthrough":     {KeyPool: []ModelKey{{APIKeyEnv: "K"}}, APIKeyPassthrough: true},
+	}
+	for name, b := range invalid {
+		if err := b.ValidateKeyPool(); err == nil {
+			t.Errorf("%s: ValidateKeyPool() error = nil", name)
+		}
+	}
+}
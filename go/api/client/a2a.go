@@ -0,0 +1,160 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"net"
+	"net/http"
+	"time"
+
+	a2aclient "trpc.group/trpc-go/trpc-a2a-go/client"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// a2aRetries is how many times an A2A request that never reached the agent
+// is retried.
+const a2aRetries = 3
+
+// A2A defines the operations on agents' A2A endpoints, served by the
+// controller under /api/a2a/{namespace}/{name}. Agents are referenced as
+// namespace/name.
+type A2A interface {
+	SendMessage(ctx context.Context, agentRef string, message protocol.Message) (*protocol.MessageResult, error)
+	StreamEvents(ctx context.Context, agentRef string, message protocol.Message) iter.Seq2[protocol.StreamingMessageEvent, error]
+	CancelTask(ctx context.Context, agentRef, taskID string) (*protocol.Task, error)
+}
+
+// a2aClient handles A2A requests
+type a2aClient struct {
+	client *BaseClient
+}
+
+// NewA2AClient creates a new A2A client
+func NewA2AClient(client *BaseClient) A2A {
+	return &a2aClient{client: client}
+}
+
+// SendMessage sends a message to an agent and waits for the resulting task
+// or message
+func (c *a2aClient) SendMessage(ctx context.Context, agentRef string, message protocol.Message) (*protocol.MessageResult, error) {
+	client, err := c.newClient(agentRef)
+	if err != nil {
+		return nil, err
+	}
+	return client.SendMessage(ctx, protocol.SendMessageParams{Message: message})
+}
+
+// StreamEvents sends a message to an agent and iterates over the task
+// events it streams back. Stopping the iteration closes the stream; the
+// task keeps running on the agent unless it is canceled.
+func (c *a2aClient) StreamEvents(ctx context.Context, agentRef string, message protocol.Message) iter.Seq2[protocol.StreamingMessageEvent, error] {
+	return func(yield func(protocol.StreamingMessageEvent, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		client, err := c.newClient(agentRef)
+		if err != nil {
+			yield(protocol.StreamingMessageEvent{}, err)
+			return
+		}
+		events, err := client.StreamMessage(ctx, protocol.SendMessageParams{Message: message})
+		if err != nil {
+			yield(protocol.StreamingMessageEvent{}, err)
+			return
+		}
+		for {
+			select {
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				if !yield(event, nil) {
+					return
+				}
+			case <-ctx.Done():
+				yield(protocol.StreamingMessageEvent{}, ctx.Err())
+				return
+			}
+		}
+	}
+}
+
+// CancelTask asks an agent to cancel a running task
+func (c *a2aClient) CancelTask(ctx context.Context, agentRef, taskID string) (*protocol.Task, error) {
+	client, err := c.newClient(agentRef)
+	if err != nil {
+		return nil, err
+	}
+	return client.CancelTasks(ctx, protocol.TaskIDParams{ID: taskID})
+}
+
+// newClient creates an A2A client for agentRef that authenticates like the
+// base client. It has no timeout of its own, so streams last as long as
+// their context.
+func (c *a2aClient) newClient(agentRef string) (*a2aclient.A2AClient, error) {
+	if agentRef == "" {
+		return nil, fmt.Errorf("agentRef is required")
+	}
+	base := c.client.HTTPClient.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	httpClient := &http.Client{Transport: &a2aTransport{base: base, client: c.client}}
+	client, err := a2aclient.NewA2AClient(c.client.buildURL("/api/a2a/"+agentRef), a2aclient.WithHTTPClient(httpClient))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create A2A client: %w", err)
+	}
+	return client, nil
+}
+
+// a2aTransport adds the base client's credentials to A2A requests and
+// retries the ones that did not reach the agent: failed connections and
+// 502, 503 or 504 responses from the controller.
+type a2aTransport struct {
+	base   http.RoundTripper
+	client *BaseClient
+}
+
+func (t *a2aTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	if userID := t.client.GetUserIDOrDefault(""); userID != "" {
+		req.Header.Set("X-User-ID", userID)
+	}
+	if t.client.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+t.client.Token)
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if attempt == a2aRetries || !retryable(resp, err) || (req.Body != nil && req.GetBody == nil) {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+		select {
+		case <-time.After(time.Duration(200<<attempt) * time.Millisecond):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+		if req.GetBody != nil {
+			if req.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		var opErr *net.OpError
+		return errors.As(err, &opErr) && opErr.Op == "dial"
+	}
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
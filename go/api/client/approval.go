@@ -0,0 +1,75 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	api "github.com/kagent-dev/kagent/go/api/httpapi"
+)
+
+// Approval defines the tool approval operations
+type Approval interface {
+	ListApprovals(ctx context.Context) (*api.StandardResponse[[]api.PendingApproval], error)
+	Approve(ctx context.Context, taskID string) (*api.StandardResponse[*api.ApprovalDecisionResponse], error)
+	Reject(ctx context.Context, taskID, reason string) (*api.StandardResponse[*api.ApprovalDecisionResponse], error)
+}
+
+// approvalClient handles approval-related requests
+type approvalClient struct {
+	client *BaseClient
+}
+
+// NewApprovalClient creates a new approval client
+func NewApprovalClient(client *BaseClient) Approval {
+	return &approvalClient{client: client}
+}
+
+// ListApprovals lists the user's tasks paused on tool approvals, oldest first
+func (c *approvalClient) ListApprovals(ctx context.Context) (*api.StandardResponse[[]api.PendingApproval], error) {
+	userID := c.client.GetUserIDOrDefault("")
+	if userID == "" {
+		return nil, fmt.Errorf("userID is required")
+	}
+
+	resp, err := c.client.Get(ctx, "/api/approvals", userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var response api.StandardResponse[[]api.PendingApproval]
+	if err := DecodeResponse(resp, &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// Approve approves every pending tool call of a task, resuming it
+func (c *approvalClient) Approve(ctx context.Context, taskID string) (*api.StandardResponse[*api.ApprovalDecisionResponse], error) {
+	return c.decide(ctx, taskID, api.ApprovalDecisionRequest{Decision: "approve"})
+}
+
+// Reject rejects every pending tool call of a task with an optional reason
+func (c *approvalClient) Reject(ctx context.Context, taskID, reason string) (*api.StandardResponse[*api.ApprovalDecisionResponse], error) {
+	return c.decide(ctx, taskID, api.ApprovalDecisionRequest{Decision: "reject", Reason: reason})
+}
+
+func (c *approvalClient) decide(ctx context.Context, taskID string, request api.ApprovalDecisionRequest) (*api.StandardResponse[*api.ApprovalDecisionResponse], error) {
+	userID := c.client.GetUserIDOrDefault("")
+	if userID == "" {
+		return nil, fmt.Errorf("userID is required")
+	}
+
+	resp, err := c.client.Post(ctx, "/api/approvals/"+url.PathEscape(taskID), request, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var response api.StandardResponse[*api.ApprovalDecisionResponse]
+	if err := DecodeResponse(resp, &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
@@ -41,11 +41,19 @@ func WithUserID(userID string) ClientOption {
 	}
 }
 
+// WithToken sets a bearer token sent with every request
+func WithToken(token string) ClientOption {
+	return func(c *BaseClient) {
+		c.Token = token
+	}
+}
+
 // BaseClient contains the shared HTTP functionality used by all sub-clients
 type BaseClient struct {
 	BaseURL    string
 	HTTPClient *http.Client
 	UserID     string // Default user ID for requests that require it
+	Token      string // Bearer token sent with every request, if set
 }
 
 // NewBaseClient creates a new base client with the given configuration
@@ -105,6 +113,9 @@ func (c *BaseClient) doRequest(ctx context.Context, method, path string, body an
 	if body != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
 
 	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
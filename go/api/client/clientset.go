@@ -16,6 +16,8 @@ type ClientSet struct {
 	Model               Model
 	Namespace           Namespace
 	Feedback            Feedback
+	Approval            Approval
+	A2A                 A2A
 }
 
 // New creates a new KAgent client set
@@ -36,5 +38,7 @@ func New(baseURL string, options ...ClientOption) *ClientSet {
 		Model:               NewModelClient(baseClient),
 		Namespace:           NewNamespaceClient(baseClient),
 		Feedback:            NewFeedbackClient(baseClient),
+		Approval:            NewApprovalClient(baseClient),
+		A2A:                 NewA2AClient(baseClient),
 	}
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	api "github.com/kagent-dev/kagent/go/api/httpapi"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
 )
 
 // Session defines the session operations
@@ -15,6 +16,7 @@ type Session interface {
 	UpdateSession(ctx context.Context, request *api.SessionRequest) (*api.StandardResponse[*api.Session], error)
 	DeleteSession(ctx context.Context, sessionName string) error
 	ListSessionRuns(ctx context.Context, sessionName string) (*api.StandardResponse[any], error)
+	ListSessionTasks(ctx context.Context, sessionID string) (*api.StandardResponse[[]*protocol.Task], error)
 }
 
 // sessionClient handles session-related requests
@@ -142,3 +144,24 @@ func (c *sessionClient) ListSessionRuns(ctx context.Context, sessionName string)
 
 	return &response, nil
 }
+
+// ListSessionTasks lists the A2A tasks of a session, oldest first
+func (c *sessionClient) ListSessionTasks(ctx context.Context, sessionID string) (*api.StandardResponse[[]*protocol.Task], error) {
+	userID := c.client.GetUserIDOrDefault("")
+	if userID == "" {
+		return nil, fmt.Errorf("userID is required")
+	}
+
+	path := fmt.Sprintf("/api/sessions/%s/tasks", sessionID)
+	resp, err := c.client.Get(ctx, path, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var response api.StandardResponse[[]*protocol.Task]
+	if err := DecodeResponse(resp, &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
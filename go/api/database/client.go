@@ -14,6 +14,24 @@ type QueryOptions struct {
 	After    time.Time
 	OrderAsc bool // When true, order results by created_at ASC (chronological). Default is DESC (newest first).
 }
+
+// TaskFilter narrows ListTasks. Zero-valued fields do not filter.
+type TaskFilter struct {
+	UserID string          // owner of the session the task belongs to
+	State  a2a.TaskState   // current task state
+	States []a2a.TaskState // current task state is one of these
+	Since  time.Time       // last updated at or after
+	Limit  int             // maximum number of tasks
+}
+
+// TaskWithAgent is a task along with the IDs of the agent and the user its
+// session belongs to, empty when the session is unknown.
+type TaskWithAgent struct {
+	Task    *a2a.Task
+	AgentID string
+	UserID  string
+}
+
 type LangGraphCheckpointTuple struct {
 	Checkpoint *LangGraphCheckpoint
 	Writes     []*LangGraphCheckpointWrite
@@ -36,6 +54,9 @@ type Client interface {
 	DeleteTask(ctx context.Context, taskID string) error
 	DeletePushNotification(ctx context.Context, taskID string) error
 	DeleteToolsForServer(ctx context.Context, serverName string, groupKind string) error
+	// SupersedeEventsFrom soft-deletes the event eventID of a session and every
+	// event stored after it, returning the IDs of the superseded events.
+	SupersedeEventsFrom(ctx context.Context, sessionID, userID, eventID string) ([]string, error)
 
 	// Get methods
 	GetSession(ctx context.Context, sessionID string, userID string) (*Session, error)
@@ -49,6 +70,11 @@ type Client interface {
 	ListTools(ctx context.Context) ([]Tool, error)
 	ListFeedback(ctx context.Context, userID string) ([]Feedback, error)
 	ListTasksForSession(ctx context.Context, sessionID string) ([]*a2a.Task, error)
+	// ListTasks returns the tasks matching filter, most recently updated first.
+	ListTasks(ctx context.Context, filter TaskFilter) ([]*a2a.Task, error)
+	// ListTasksWithAgents is ListTasks across all users, joined with the
+	// agent and user of each task's session. filter.UserID is ignored.
+	ListTasksWithAgents(ctx context.Context, filter TaskFilter) ([]TaskWithAgent, error)
 	ListSessions(ctx context.Context, userID string) ([]Session, error)
 	ListSessionsForAgent(ctx context.Context, agentID string, userID string) ([]SessionWithShareToken, error)
 	ListSessionsForAgentAllUsers(ctx context.Context, agentID string) ([]Session, error)
@@ -88,4 +114,10 @@ type Client interface {
 	ListAgentMemories(ctx context.Context, agentName, userID string) ([]Memory, error)
 	DeleteAgentMemory(ctx context.Context, agentName, userID string) error
 	PruneExpiredMemories(ctx context.Context) error
+
+	// Knowledge (document retrieval) methods
+	StoreKnowledgeDocument(ctx context.Context, collection, documentID string, chunks []KnowledgeChunk) error
+	SearchKnowledge(ctx context.Context, collection string, embedding pgvector.Vector, filter map[string]string, limit int) ([]KnowledgeSearchResult, error)
+	ListKnowledgeDocuments(ctx context.Context, collection string) ([]KnowledgeDocument, error)
+	DeleteKnowledgeDocument(ctx context.Context, collection, documentID string) error
 }
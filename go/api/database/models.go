@@ -234,6 +234,36 @@ type AgentMemorySearchResult struct {
 	Score float64 `json:"score"`
 }
 
+// KnowledgeChunk is a chunk of a document in a knowledge collection.
+// StartOffset and EndOffset locate the chunk in the document's text, in
+// bytes.
+type KnowledgeChunk struct {
+	ID          string            `json:"id"`
+	Collection  string            `json:"collection"`
+	DocumentID  string            `json:"document_id"`
+	ChunkIndex  int               `json:"chunk_index"`
+	Content     string            `json:"content"`
+	StartOffset int               `json:"start_offset"`
+	EndOffset   int               `json:"end_offset"`
+	Embedding   pgvector.Vector   `json:"-"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+}
+
+// KnowledgeSearchResult is the result of a vector similarity search over
+// KnowledgeChunk.
+type KnowledgeSearchResult struct {
+	KnowledgeChunk
+	Score float64 `json:"score"`
+}
+
+// KnowledgeDocument summarizes a document ingested into a collection.
+type KnowledgeDocument struct {
+	ID         string    `json:"id"`
+	ChunkCount int       `json:"chunk_count"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
 type SessionShare struct {
 	ID        int64     `json:"id"`
 	Token     string    `json:"token"`
@@ -1,6 +1,8 @@
 package httpapi
 
 import (
+	"time"
+
 	"github.com/kagent-dev/kagent/go/api/database"
 	"github.com/kagent-dev/kagent/go/api/v1alpha1"
 	"github.com/kagent-dev/kagent/go/api/v1alpha2"
@@ -288,3 +290,68 @@ type SessionRunsResponse struct {
 type SessionRunsData struct {
 	Runs []any `json:"runs"`
 }
+
+// Admin types
+
+// AdminAgentUsage summarizes the tasks and token usage of one agent.
+type AdminAgentUsage struct {
+	AgentID          string `json:"agentId"`
+	Tasks            int    `json:"tasks"`
+	FailedTasks      int    `json:"failedTasks"`
+	PromptTokens     int64  `json:"promptTokens"`
+	CandidatesTokens int64  `json:"candidatesTokens"`
+	TotalTokens      int64  `json:"totalTokens"`
+}
+
+// AdminErrorRateBucket counts the tasks that finished in one time bucket and
+// how many of them failed.
+type AdminErrorRateBucket struct {
+	Start     time.Time `json:"start"`
+	Finished  int       `json:"finished"`
+	Failed    int       `json:"failed"`
+	ErrorRate float64   `json:"errorRate"`
+}
+
+// AdminTaskSummary identifies a task that needs an operator's attention.
+type AdminTaskSummary struct {
+	TaskID    string     `json:"taskId"`
+	SessionID string     `json:"sessionId"`
+	AgentID   string     `json:"agentId,omitempty"`
+	State     string     `json:"state"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+}
+
+// Approval types
+
+// PendingApproval is a task paused on tool calls that wait for a human
+// decision.
+type PendingApproval struct {
+	TaskID      string            `json:"taskId"`
+	SessionID   string            `json:"sessionId"`
+	AgentRef    string            `json:"agentRef,omitempty"`
+	RequestedAt *time.Time        `json:"requestedAt,omitempty"`
+	ToolCalls   []PendingToolCall `json:"toolCalls"`
+	// AssignedGroups are the caller's groups the approval is assigned to,
+	// when it is in another user's session.
+	AssignedGroups []string `json:"assignedGroups,omitempty"`
+}
+
+// PendingToolCall is one tool call awaiting approval.
+type PendingToolCall struct {
+	ID   string         `json:"id,omitempty"`
+	Name string         `json:"name"`
+	Args map[string]any `json:"args,omitempty"`
+}
+
+// ApprovalDecisionRequest approves or rejects every pending tool call of a
+// task.
+type ApprovalDecisionRequest struct {
+	Decision string `json:"decision"` // "approve" or "reject"
+	Reason   string `json:"reason,omitempty"`
+}
+
+// ApprovalDecisionResponse reports the task state after a decision.
+type ApprovalDecisionResponse struct {
+	TaskID string `json:"taskId"`
+	State  string `json:"state,omitempty"`
+}
@@ -0,0 +1,208 @@
+// Package httpsecurity provides the CORS and security-header middleware
+// shared by the kagent HTTP servers.
+package httpsecurity
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Environment variables read by ConfigFromEnv.
+const (
+	EnvCORSAllowedOrigins          = "KAGENT_CORS_ALLOWED_ORIGINS"
+	EnvCORSAllowedMethods          = "KAGENT_CORS_ALLOWED_METHODS"
+	EnvCORSAllowedHeaders          = "KAGENT_CORS_ALLOWED_HEADERS"
+	EnvCORSExposedHeaders          = "KAGENT_CORS_EXPOSED_HEADERS"
+	EnvCORSAllowCredentials        = "KAGENT_CORS_ALLOW_CREDENTIALS"
+	EnvCORSMaxAge                  = "KAGENT_CORS_MAX_AGE"
+	EnvCORSStreamingAllowedOrigins = "KAGENT_CORS_STREAMING_ALLOWED_ORIGINS"
+	EnvSecurityHeaders             = "KAGENT_SECURITY_HEADERS"
+)
+
+var (
+	defaultAllowedMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions}
+	defaultAllowedHeaders = []string{"Authorization", "Content-Type", "Accept", "Last-Event-ID", "X-User-ID", "X-Request-ID", "A2A-Version"}
+)
+
+// CORS configures cross-origin access. An empty AllowedOrigins disables CORS.
+type CORS struct {
+	// AllowedOrigins lists the origins allowed to call the server; "*"
+	// allows any origin.
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	// MaxAge is how long, in seconds, browsers may cache a preflight result.
+	MaxAge int
+}
+
+// RouteOverride replaces the CORS policy for requests whose path starts
+// with PathPrefix, e.g. the streaming endpoints.
+type RouteOverride struct {
+	PathPrefix string
+	CORS       CORS
+}
+
+// Config configures Middleware.
+type Config struct {
+	CORS CORS
+	// Overrides are checked in order; the first matching prefix wins.
+	Overrides []RouteOverride
+	// SecurityHeaders adds nosniff, frame and referrer protection headers to
+	// every response.
+	SecurityHeaders bool
+}
+
+// ConfigFromEnv reads the configuration from the KAGENT_CORS_* and
+// KAGENT_SECURITY_HEADERS env vars. When KAGENT_CORS_STREAMING_ALLOWED_ORIGINS
+// is set, streamingPrefixes get an override that allows those origins
+// instead. Security headers are enabled unless KAGENT_SECURITY_HEADERS=false.
+func ConfigFromEnv(streamingPrefixes ...string) (Config, error) {
+	cfg := Config{
+		CORS: CORS{
+			AllowedOrigins: ParseList(os.Getenv(EnvCORSAllowedOrigins)),
+			AllowedMethods: ParseList(os.Getenv(EnvCORSAllowedMethods)),
+			AllowedHeaders: ParseList(os.Getenv(EnvCORSAllowedHeaders)),
+			ExposedHeaders: ParseList(os.Getenv(EnvCORSExposedHeaders)),
+		},
+		SecurityHeaders: true,
+	}
+	var err error
+	if v := strings.TrimSpace(os.Getenv(EnvCORSAllowCredentials)); v != "" {
+		if cfg.CORS.AllowCredentials, err = strconv.ParseBool(v); err != nil {
+			return Config{}, fmt.Errorf("invalid %s %q", EnvCORSAllowCredentials, v)
+		}
+	}
+	if v := strings.TrimSpace(os.Getenv(EnvCORSMaxAge)); v != "" {
+		if cfg.CORS.MaxAge, err = strconv.Atoi(v); err != nil || cfg.CORS.MaxAge < 0 {
+			return Config{}, fmt.Errorf("invalid %s %q", EnvCORSMaxAge, v)
+		}
+	}
+	if v := strings.TrimSpace(os.Getenv(EnvSecurityHeaders)); v != "" {
+		if cfg.SecurityHeaders, err = strconv.ParseBool(v); err != nil {
+			return Config{}, fmt.Errorf("invalid %s %q", EnvSecurityHeaders, v)
+		}
+	}
+	cfg.OverrideOrigins(ParseList(os.Getenv(EnvCORSStreamingAllowedOrigins)), streamingPrefixes...)
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// OverrideOrigins adds an override for each path prefix that keeps the base
+// CORS policy but allows origins instead. It does nothing when origins is
+// empty.
+func (cfg *Config) OverrideOrigins(origins []string, pathPrefixes ...string) {
+	if len(origins) == 0 {
+		return
+	}
+	policy := cfg.CORS
+	policy.AllowedOrigins = origins
+	for _, prefix := range pathPrefixes {
+		cfg.Overrides = append(cfg.Overrides, RouteOverride{PathPrefix: prefix, CORS: policy})
+	}
+}
+
+// Validate rejects policies that browsers would refuse, such as credentials
+// combined with a wildcard origin.
+func (cfg Config) Validate() error {
+	policies := []CORS{cfg.CORS}
+	for _, o := range cfg.Overrides {
+		policies = append(policies, o.CORS)
+	}
+	for _, p := range policies {
+		if p.AllowCredentials && slices.Contains(p.AllowedOrigins, "*") {
+			return fmt.Errorf("CORS credentials cannot be allowed for a wildcard origin")
+		}
+		if p.MaxAge < 0 {
+			return fmt.Errorf("CORS max age must not be negative")
+		}
+	}
+	return nil
+}
+
+// Middleware applies the CORS policy and security headers, answering CORS
+// preflight requests itself.
+func Middleware(cfg Config, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if cfg.SecurityHeaders {
+			setSecurityHeaders(w.Header())
+		}
+		policy := cfg.policyFor(r.URL.Path)
+		origin := r.Header.Get("Origin")
+		if origin == "" || !policy.allows(origin) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		h := w.Header()
+		h.Add("Vary", "Origin")
+		if slices.Contains(policy.AllowedOrigins, "*") && !policy.AllowCredentials {
+			h.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			h.Set("Access-Control-Allow-Origin", origin)
+		}
+		if policy.AllowCredentials {
+			h.Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		// Preflight requests are answered here and never reach next.
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			h.Set("Access-Control-Allow-Methods", strings.Join(orDefault(policy.AllowedMethods, defaultAllowedMethods), ", "))
+			h.Set("Access-Control-Allow-Headers", strings.Join(orDefault(policy.AllowedHeaders, defaultAllowedHeaders), ", "))
+			if policy.MaxAge > 0 {
+				h.Set("Access-Control-Max-Age", strconv.Itoa(policy.MaxAge))
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		if len(policy.ExposedHeaders) > 0 {
+			h.Set("Access-Control-Expose-Headers", strings.Join(policy.ExposedHeaders, ", "))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (cfg Config) policyFor(path string) CORS {
+	for _, o := range cfg.Overrides {
+		if strings.HasPrefix(path, o.PathPrefix) {
+			return o.CORS
+		}
+	}
+	return cfg.CORS
+}
+
+func (c CORS) allows(origin string) bool {
+	return slices.Contains(c.AllowedOrigins, "*") || slices.Contains(c.AllowedOrigins, origin)
+}
+
+func setSecurityHeaders(h http.Header) {
+	h.Set("X-Content-Type-Options", "nosniff")
+	h.Set("X-Frame-Options", "DENY")
+	h.Set("Referrer-Policy", "no-referrer")
+	h.Set("Content-Security-Policy", "default-src 'none'; frame-ancestors 'none'")
+}
+
+func orDefault(values, def []string) []string {
+	if len(values) == 0 {
+		return def
+	}
+	return values
+}
+
+// ParseList splits a comma-separated list, dropping empty items.
+func ParseList(v string) []string {
+	var out []string
+	for item := range strings.SplitSeq(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
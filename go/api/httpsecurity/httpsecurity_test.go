@@ -0,0 +1,126 @@
+package httpsecurity
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func serve(cfg Config, req *http.Request) (*httptest.ResponseRecorder, bool) {
+	reached := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached = true
+		w.WriteHeader(http.StatusOK)
+	})
+	rec := httptest.NewRecorder()
+	Middleware(cfg, next).ServeHTTP(rec, req)
+	return rec, reached
+}
+
+func TestMiddleware_SecurityHeaders(t *testing.T) {
+	rec, _ := serve(Config{SecurityHeaders: true}, httptest.NewRequest(http.MethodGet, "/api/agents", nil))
+	for header, want := range map[string]string{
+		"X-Content-Type-Options": "nosniff",
+		"X-Frame-Options":        "DENY",
+		"Referrer-Policy":        "no-referrer",
+	} {
+		if got := rec.Header().Get(header); got != want {
+			t.Errorf("%s = %q, want %q", header, got, want)
+		}
+	}
+
+	rec, _ = serve(Config{}, httptest.NewRequest(http.MethodGet, "/api/agents", nil))
+	if got := rec.Header().Get("X-Frame-Options"); got != "" {
+		t.Errorf("X-Frame-Options = %q with security headers disabled", got)
+	}
+}
+
+func TestMiddleware_CORS(t *testing.T) {
+	cfg := Config{CORS: CORS{AllowedOrigins: []string{"https://ui.example.com"}, AllowCredentials: true, MaxAge: 600}}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/agents", nil)
+	req.Header.Set("Origin", "https://ui.example.com")
+	rec, reached := serve(cfg, req)
+	if !reached {
+		t.Fatal("simple request did not reach the handler")
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://ui.example.com" {
+		t.Errorf("Access-Control-Allow-Origin = %q", got)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Credentials"); got != "true" {
+		t.Errorf("Access-Control-Allow-Credentials = %q", got)
+	}
+
+	req = httptest.NewRequest(http.MethodOptions, "/api/agents", nil)
+	req.Header.Set("Origin", "https://ui.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	rec, reached = serve(cfg, req)
+	if reached {
+		t.Error("preflight request reached the handler")
+	}
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("preflight status = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Methods"); got == "" {
+		t.Error("preflight response has no Access-Control-Allow-Methods")
+	}
+	if got := rec.Header().Get("Access-Control-Max-Age"); got != "600" {
+		t.Errorf("Access-Control-Max-Age = %q, want 600", got)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/agents", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	rec, reached = serve(cfg, req)
+	if !reached {
+		t.Error("request from a disallowed origin did not reach the handler")
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("Access-Control-Allow-Origin = %q for a disallowed origin", got)
+	}
+}
+
+func TestMiddleware_RouteOverride(t *testing.T) {
+	cfg := Config{CORS: CORS{AllowedOrigins: []string{"https://ui.example.com"}}}
+	cfg.OverrideOrigins([]string{"*"}, "/api/a2a/")
+
+	req := httptest.NewRequest(http.MethodPost, "/api/a2a/ns/agent/", nil)
+	req.Header.Set("Origin", "https://other.example.com")
+	rec, _ := serve(cfg, req)
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("streaming route Access-Control-Allow-Origin = %q, want *", got)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/agents", nil)
+	req.Header.Set("Origin", "https://other.example.com")
+	rec, _ = serve(cfg, req)
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("non-streaming route Access-Control-Allow-Origin = %q, want none", got)
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv(EnvCORSAllowedOrigins, "https://a.example.com, https://b.example.com")
+	t.Setenv(EnvCORSStreamingAllowedOrigins, "https://a.example.com")
+	t.Setenv(EnvCORSMaxAge, "120")
+	t.Setenv(EnvSecurityHeaders, "false")
+
+	cfg, err := ConfigFromEnv("/stream/")
+	if err != nil {
+		t.Fatalf("ConfigFromEnv() error = %v", err)
+	}
+	if len(cfg.CORS.AllowedOrigins) != 2 || cfg.CORS.AllowedOrigins[1] != "https://b.example.com" {
+		t.Errorf("AllowedOrigins = %v", cfg.CORS.AllowedOrigins)
+	}
+	if cfg.CORS.MaxAge != 120 || cfg.SecurityHeaders {
+		t.Errorf("MaxAge = %d, SecurityHeaders = %v", cfg.CORS.MaxAge, cfg.SecurityHeaders)
+	}
+	if len(cfg.Overrides) != 1 || cfg.Overrides[0].PathPrefix != "/stream/" || cfg.Overrides[0].CORS.MaxAge != 120 {
+		t.Errorf("Overrides = %+v", cfg.Overrides)
+	}
+
+	t.Setenv(EnvCORSAllowedOrigins, "*")
+	t.Setenv(EnvCORSAllowCredentials, "true")
+	if _, err := ConfigFromEnv(); err == nil {
+		t.Error("ConfigFromEnv() accepted credentials with a wildcard origin")
+	}
+}
@@ -0,0 +1,69 @@
+// Package requestid assigns every request an ID that is passed on to the
+// services it calls, so the logs and events of one conversation can be
+// correlated across the controller and agents.
+package requestid
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// Header carries the request ID on requests and responses.
+const Header = "X-Request-ID"
+
+// maxLength bounds caller-supplied IDs, which end up in logs and metadata.
+const maxLength = 128
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying id.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request ID carried by ctx, or "" if there is none.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// New returns a new request ID.
+func New() string {
+	return uuid.NewString()
+}
+
+// Valid reports whether id can be used as a request ID: non-empty, at most
+// 128 bytes and printable ASCII, so it is safe to log and echo in headers.
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// Middleware honors a valid X-Request-ID header or generates a new ID,
+// stores it in the request context and echoes it on the response.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(Header)
+		if !Valid(id) {
+			id = New()
+		}
+		w.Header().Set(Header, id)
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), id)))
+	})
+}
+
+// SetHeader sets the request ID carried by ctx on an outgoing request.
+func SetHeader(ctx context.Context, req *http.Request) {
+	if id := FromContext(ctx); id != "" {
+		req.Header.Set(Header, id)
+	}
+}
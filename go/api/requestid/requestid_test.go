@@ -0,0 +1,50 @@
+package requestid
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		wantSame bool
+	}{
+		{name: "honors caller ID", header: "req-123", wantSame: true},
+		{name: "generates missing ID"},
+		{name: "replaces ID with spaces", header: "req 123"},
+		{name: "replaces oversized ID", header: strings.Repeat("a", 129)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var seen string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				seen = FromContext(r.Context())
+			})
+			req := httptest.NewRequest(http.MethodGet, "/api/agents", nil)
+			if tt.header != "" {
+				req.Header.Set(Header, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			Middleware(next).ServeHTTP(rec, req)
+
+			if seen == "" || rec.Header().Get(Header) != seen {
+				t.Fatalf("context ID %q, response header %q", seen, rec.Header().Get(Header))
+			}
+			if (seen == tt.header) != tt.wantSame {
+				t.Errorf("request ID = %q, header was %q", seen, tt.header)
+			}
+		})
+	}
+}
+
+func TestSetHeader(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	SetHeader(NewContext(req.Context(), "req-123"), req)
+	if got := req.Header.Get(Header); got != "req-123" {
+		t.Errorf("X-Request-ID = %q, want req-123", got)
+	}
+}
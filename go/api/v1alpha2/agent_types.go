@@ -224,6 +224,11 @@ type DeclarativeAgentSpec struct {
 	// +optional
 	Memory *MemorySpec `json:"memory,omitempty"`
 
+	// Knowledge gives the agent a retrieve_docs tool over document collections
+	// ingested through the knowledge API.
+	// +optional
+	Knowledge *KnowledgeSpec `json:"knowledge,omitempty"`
+
 	// ShareTools enables the built-in share link tools for this agent.
 	// When true, the agent gains create_share_link, list_share_links, and delete_share_link tools
 	// that allow it to manage share tokens for the current session.
@@ -234,6 +239,108 @@ type DeclarativeAgentSpec struct {
 	// This includes event compaction (compression) and context caching.
 	// +optional
 	Context *ContextConfig `json:"context,omitempty"`
+
+	// ToolConflictPolicy decides which tool the model sees when a built-in
+	// tool and MCP server tools share a name: error fails the request,
+	// prefer-local keeps the built-in tool and prefer-mcp keeps the MCP
+	// tool. Between MCP servers the first listed server wins. Defaults to
+	// error. Only the go runtime supports tool conflict policies.
+	// +optional
+	// +kubebuilder:validation:Enum=error;prefer-local;prefer-mcp
+	ToolConflictPolicy string `json:"toolConflictPolicy,omitempty"`
+
+	// Generation sets the agent's default sampling parameters. They take
+	// precedence over the ModelConfig's settings, and a message can override
+	// them with kagent_generation metadata. Only the go runtime supports
+	// generation settings.
+	// +optional
+	Generation *GenerationSpec `json:"generation,omitempty"`
+
+	// BestOf samples several candidates for each model call and keeps the
+	// best one. Only the go runtime supports best-of sampling.
+	// +optional
+	BestOf *BestOfSpec `json:"bestOf,omitempty"`
+
+	// Reflection has each final answer critiqued and, unless the critique
+	// approves it, revised before it is returned. Only the go runtime
+	// supports reflection.
+	// +optional
+	Reflection *ReflectionSpec `json:"reflection,omitempty"`
+
+	// Strategy selects the agent loop: react lets the model alternate
+	// between reasoning and tool calls until it answers, plan-execute has it
+	// write a checklist plan first and work through it step by step,
+	// re-planning when a step fails. Defaults to react. Only the go runtime
+	// supports plan-execute.
+	// +optional
+	// +kubebuilder:validation:Enum=react;plan-execute
+	Strategy string `json:"strategy,omitempty"`
+}
+
+// ReflectionSpec configures the critique step run on draft final answers.
+type ReflectionSpec struct {
+	// CritiqueModelConfig is the name of the ModelConfig object used to
+	// critique drafts, such as a cheaper model. Defaults to the agent's
+	// ModelConfig.
+	// +optional
+	CritiqueModelConfig string `json:"critiqueModelConfig,omitempty"`
+
+	// MaxPasses is how many revisions a draft may go through. Defaults to 1.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=3
+	MaxPasses int `json:"maxPasses,omitempty"`
+
+	// CritiquePrompt replaces the built-in review instructions. The critique
+	// model must still reply APPROVED to accept a draft.
+	// +optional
+	CritiquePrompt string `json:"critiquePrompt,omitempty"`
+}
+
+// BestOfSpec configures multi-candidate sampling. Providers that can return
+// several candidates in one call are asked to; others are called once per
+// candidate, in parallel.
+type BestOfSpec struct {
+	// Candidates is the number of candidates sampled per model call.
+	// +required
+	// +kubebuilder:validation:Minimum=2
+	// +kubebuilder:validation:Maximum=8
+	Candidates int `json:"candidates"`
+
+	// Scorer chooses the candidate kept: heuristic prefers complete,
+	// non-empty answers, and judge asks the agent's model to pick one.
+	// Defaults to heuristic.
+	// +optional
+	// +kubebuilder:validation:Enum=heuristic;judge
+	Scorer string `json:"scorer,omitempty"`
+}
+
+// GenerationSpec holds sampling parameters applied to every model call of an
+// agent. Unset fields keep the ModelConfig's settings.
+type GenerationSpec struct {
+	// Temperature for sampling, between 0 and 2.
+	// +optional
+	Temperature string `json:"temperature,omitempty"`
+
+	// TopP is the nucleus sampling probability, between 0 and 1.
+	// +optional
+	TopP string `json:"topP,omitempty"`
+
+	// TopK limits sampling to the K most likely tokens.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	TopK int `json:"topK,omitempty"`
+
+	// MaxOutputTokens caps the tokens generated by each model call.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxOutputTokens int `json:"maxOutputTokens,omitempty"`
+
+	// StopSequences end generation when the model produces one of them.
+	// +optional
+	// +kubebuilder:validation:MaxItems=4
+	// +kubebuilder:validation:items:MinLength=1
+	StopSequences []string `json:"stopSequences,omitempty"`
 }
 
 // SandboxSubstrateSpec configures Agent Substrate for a SandboxAgent.
@@ -363,6 +470,27 @@ type MemorySpec struct {
 	TTLDays int `json:"ttlDays,omitempty"`
 }
 
+// KnowledgeSpec configures document retrieval for an agent.
+type KnowledgeSpec struct {
+	// ModelConfig is the name of the ModelConfig object whose embedding
+	// provider will be used to embed search queries. It must use the same
+	// embedding model the collections were ingested with.
+	// +required
+	ModelConfig string `json:"modelConfig"`
+
+	// Collections are the knowledge collections the agent may search.
+	// +required
+	// +kubebuilder:validation:MinItems=1
+	Collections []string `json:"collections"`
+
+	// TopK is the number of chunks returned for a query. Defaults to 5 when
+	// unset or zero.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=50
+	TopK int `json:"topK,omitempty"`
+}
+
 type DeclarativeDeploymentSpec struct {
 	// +optional
 	ImageRegistry string `json:"imageRegistry,omitempty"`
@@ -450,6 +578,21 @@ type SharedDeploymentSpec struct {
 	// Useful for sidecars such as token proxies, log shippers, or security agents.
 	// +optional
 	ExtraContainers []corev1.Container `json:"extraContainers,omitempty"`
+	// Workspace puts the session workspaces of the agent on a volume shared by
+	// all replicas, so a session's files are found whichever pod serves its
+	// next message. Without it each pod keeps its workspaces on local disk and
+	// an agent with more than one replica loses them between turns.
+	// +optional
+	Workspace *AgentWorkspace `json:"workspace,omitempty"`
+}
+
+// AgentWorkspace is the volume holding an agent's session workspaces.
+type AgentWorkspace struct {
+	// ClaimName is the name of a PersistentVolumeClaim in the agent's
+	// namespace. It must be ReadWriteMany when the agent has more than one
+	// replica.
+	// +kubebuilder:validation:MinLength=1
+	ClaimName string `json:"claimName"`
 }
 
 type ServiceAccountConfig struct {
@@ -527,6 +670,20 @@ type McpServerTool struct {
 	// +kubebuilder:validation:MaxItems=50
 	RequireApproval []string `json:"requireApproval,omitempty"`
 
+	// RetryPolicy configures automatic retries for tools of this server
+	// whose calls fail. When unset, failed calls are reported to the model
+	// without retrying. Only the go runtime supports retry policies.
+	// +optional
+	RetryPolicy *ToolRetryPolicy `json:"retryPolicy,omitempty"`
+
+	// ToolPrefix exposes this server's tools to the model as
+	// <toolPrefix>__<tool>, so tools of different servers with the same name
+	// do not collide. ToolNames and RequireApproval keep the server's own
+	// tool names. Only the go runtime supports tool prefixes.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_-]{1,32}$`
+	ToolPrefix string `json:"toolPrefix,omitempty"`
+
 	// AllowedHeaders specifies which headers from the A2A request should be
 	// propagated to MCP tool calls. Header names are case-insensitive.
 	//
@@ -542,6 +699,36 @@ type McpServerTool struct {
 	AllowedHeaders []string `json:"allowedHeaders,omitempty"`
 }
 
+// ToolRetryPolicy configures automatic retries for failed tool calls.
+type ToolRetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first call.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=10
+	MaxAttempts int32 `json:"maxAttempts"`
+
+	// InitialBackoff is the delay before the first retry. The delay doubles
+	// after every retry. Defaults to 1s.
+	// +optional
+	InitialBackoff *metav1.Duration `json:"initialBackoff,omitempty"`
+
+	// MaxBackoff caps the delay between retries. Defaults to 30s.
+	// +optional
+	MaxBackoff *metav1.Duration `json:"maxBackoff,omitempty"`
+
+	// RetryableErrors lists regular expressions matched against the tool
+	// error message. Only matching errors are retried. When empty, only
+	// transient errors (timeouts, dropped or refused connections) are retried.
+	// +optional
+	// +kubebuilder:validation:MaxItems=20
+	RetryableErrors []string `json:"retryableErrors,omitempty"`
+
+	// Tools restricts the policy to these tool names. When empty, the policy
+	// applies to every tool provided by the server.
+	// +optional
+	// +kubebuilder:validation:MaxItems=50
+	Tools []string `json:"tools,omitempty"`
+}
+
 type TypedLocalReference struct {
 	// +optional
 	Kind string `json:"kind,omitempty"`
@@ -114,6 +114,12 @@ type AnthropicConfig struct {
 	// Top-k sampling parameter
 	// +optional
 	TopK int `json:"topK,omitempty"`
+
+	// Token budget for extended thinking. When set, Claude reasons in thinking
+	// blocks before answering; temperature and topK are ignored while enabled.
+	// +optional
+	// +kubebuilder:validation:Minimum=1024
+	ThinkingBudgetTokens int `json:"thinkingBudgetTokens,omitempty"`
 }
 
 // TokenExchangeType identifies the token exchange mechanism
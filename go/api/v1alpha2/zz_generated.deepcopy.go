@@ -548,6 +548,21 @@ func (in *AgentStatus) DeepCopy() *AgentStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentWorkspace) DeepCopyInto(out *AgentWorkspace) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentWorkspace.
+func (in *AgentWorkspace) DeepCopy() *AgentWorkspace {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentWorkspace)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AllowedNamespaces) DeepCopyInto(out *AllowedNamespaces) {
 	*out = *in
@@ -679,6 +694,21 @@ func (in *BedrockConfig) DeepCopy() *BedrockConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BestOfSpec) DeepCopyInto(out *BestOfSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BestOfSpec.
+func (in *BestOfSpec) DeepCopy() *BestOfSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BestOfSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ByoDeploymentSpec) DeepCopyInto(out *ByoDeploymentSpec) {
 	*out = *in
@@ -839,6 +869,11 @@ func (in *DeclarativeAgentSpec) DeepCopyInto(out *DeclarativeAgentSpec) {
 		*out = new(MemorySpec)
 		**out = **in
 	}
+	if in.Knowledge != nil {
+		in, out := &in.Knowledge, &out.Knowledge
+		*out = new(KnowledgeSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ShareTools != nil {
 		in, out := &in.ShareTools, &out.ShareTools
 		*out = new(bool)
@@ -849,6 +884,21 @@ func (in *DeclarativeAgentSpec) DeepCopyInto(out *DeclarativeAgentSpec) {
 		*out = new(ContextConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Generation != nil {
+		in, out := &in.Generation, &out.Generation
+		*out = new(GenerationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BestOf != nil {
+		in, out := &in.BestOf, &out.BestOf
+		*out = new(BestOfSpec)
+		**out = **in
+	}
+	if in.Reflection != nil {
+		in, out := &in.Reflection, &out.Reflection
+		*out = new(ReflectionSpec)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeclarativeAgentSpec.
@@ -923,6 +973,26 @@ func (in *GeminiVertexAIConfig) DeepCopy() *GeminiVertexAIConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GenerationSpec) DeepCopyInto(out *GenerationSpec) {
+	*out = *in
+	if in.StopSequences != nil {
+		in, out := &in.StopSequences, &out.StopSequences
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GenerationSpec.
+func (in *GenerationSpec) DeepCopy() *GenerationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GenerationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GitRepo) DeepCopyInto(out *GitRepo) {
 	*out = *in
@@ -938,6 +1008,26 @@ func (in *GitRepo) DeepCopy() *GitRepo {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KnowledgeSpec) DeepCopyInto(out *KnowledgeSpec) {
+	*out = *in
+	if in.Collections != nil {
+		in, out := &in.Collections, &out.Collections
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KnowledgeSpec.
+func (in *KnowledgeSpec) DeepCopy() *KnowledgeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KnowledgeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MCPTool) DeepCopyInto(out *MCPTool) {
 	*out = *in
@@ -967,6 +1057,11 @@ func (in *McpServerTool) DeepCopyInto(out *McpServerTool) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(ToolRetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.AllowedHeaders != nil {
 		in, out := &in.AllowedHeaders, &out.AllowedHeaders
 		*out = make([]string, len(*in))
@@ -1380,6 +1475,21 @@ func (in *PromptTemplateSpec) DeepCopy() *PromptTemplateSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReflectionSpec) DeepCopyInto(out *ReflectionSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReflectionSpec.
+func (in *ReflectionSpec) DeepCopy() *ReflectionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ReflectionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RemoteMCPServer) DeepCopyInto(out *RemoteMCPServer) {
 	*out = *in
@@ -1802,6 +1912,11 @@ func (in *SharedDeploymentSpec) DeepCopyInto(out *SharedDeploymentSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Workspace != nil {
+		in, out := &in.Workspace, &out.Workspace
+		*out = new(AgentWorkspace)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SharedDeploymentSpec.
@@ -1948,6 +2063,41 @@ func (in *Tool) DeepCopy() *Tool {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ToolRetryPolicy) DeepCopyInto(out *ToolRetryPolicy) {
+	*out = *in
+	if in.InitialBackoff != nil {
+		in, out := &in.InitialBackoff, &out.InitialBackoff
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxBackoff != nil {
+		in, out := &in.MaxBackoff, &out.MaxBackoff
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.RetryableErrors != nil {
+		in, out := &in.RetryableErrors, &out.RetryableErrors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Tools != nil {
+		in, out := &in.Tools, &out.Tools
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ToolRetryPolicy.
+func (in *ToolRetryPolicy) DeepCopy() *ToolRetryPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ToolRetryPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TypedLocalReference) DeepCopyInto(out *TypedLocalReference) {
 	*out = *in
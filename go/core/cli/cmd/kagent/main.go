@@ -116,9 +116,37 @@ func newRootCommand(ctx context.Context, cfg *config.Config) *cobra.Command {
 	invokeCmd.Flags().BoolVarP(&invokeCfg.Stream, "stream", "S", false, "Stream the response")
 	invokeCmd.Flags().StringVarP(&invokeCfg.File, "file", "f", "", "File to read the task from")
 	invokeCmd.Flags().StringVarP(&invokeCfg.URLOverride, "url-override", "u", "", "URL override")
+	invokeCmd.Flags().StringVar(&invokeCfg.ContinueTask, "continue", "", "ID of a previous task to continue in its session")
 	invokeCmd.Flags().MarkHidden("url-override") //nolint:errcheck
 	invokeCmd.Flags().StringVar(&invokeCfg.Token, "token", "", "Bearer token to include in A2A requests (for API key passthrough)")
 
+	chatCfg := &cli.ChatCfg{
+		Config: cfg,
+	}
+
+	chatCmd := &cobra.Command{
+		Use:   "chat [agent_name]",
+		Short: "Chat with a kagent agent",
+		Long: `Open an interactive chat with a kagent agent. Responses are streamed and tool calls are shown inline.
+Type /help in the chat for the available commands (/approve, /reject, /cancel, /export).`,
+		Args:    cobra.ExactArgs(1),
+		Example: `kagent chat k8s-agent`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := cli.CheckServerConnection(cmd.Context(), cfg.Client()); err != nil {
+				pf, err := cli.NewPortForward(cmd.Context(), cfg)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error starting port-forward: %v\n", err)
+					return
+				}
+				defer pf.Stop()
+			}
+			chatCfg.Agent = args[0]
+			cli.ChatCmd(cmd.Context(), chatCfg)
+		},
+	}
+	chatCmd.Flags().StringVarP(&chatCfg.Session, "session", "s", "", "Session to continue (a new session is created by default)")
+	chatCmd.Flags().StringVar(&chatCfg.Token, "token", "", "Bearer token to include in A2A requests (for API key passthrough)")
+
 	bugReportCmd := &cobra.Command{
 		Use:   "bug-report",
 		Short: "Generate a bug report",
@@ -173,6 +201,7 @@ func newRootCommand(ctx context.Context, cfg *config.Config) *cobra.Command {
 		},
 	}
 
+	var transcript bool
 	getSessionCmd := &cobra.Command{
 		Use:   "session [session_id]",
 		Short: "Get a session or list all sessions",
@@ -190,9 +219,18 @@ func newRootCommand(ctx context.Context, cfg *config.Config) *cobra.Command {
 			if len(args) > 0 {
 				resourceName = args[0]
 			}
+			if transcript {
+				if resourceName == "" {
+					fmt.Fprintln(os.Stderr, "A session ID is required with --transcript")
+					return
+				}
+				cli.SessionTranscriptCmd(cfg, resourceName)
+				return
+			}
 			cli.GetSessionCmd(cfg, resourceName)
 		},
 	}
+	getSessionCmd.Flags().BoolVar(&transcript, "transcript", false, "Print the session conversation as text")
 
 	getAgentCmd := &cobra.Command{
 		Use:   "agent [agent_name]",
@@ -231,7 +269,50 @@ func newRootCommand(ctx context.Context, cfg *config.Config) *cobra.Command {
 		},
 	}
 
-	getCmd.AddCommand(getSessionCmd, getAgentCmd, getToolCmd)
+	getTaskCmd := &cobra.Command{
+		Use:   "task [session_id]",
+		Short: "List the tasks of a session",
+		Long:  `List the A2A tasks of a session`,
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := cli.CheckServerConnection(cmd.Context(), cfg.Client()); err != nil {
+				pf, err := cli.NewPortForward(cmd.Context(), cfg)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error starting port-forward: %v\n", err)
+					return
+				}
+				defer pf.Stop()
+			}
+			cli.GetTaskCmd(cfg, args[0])
+		},
+	}
+
+	getCmd.AddCommand(getSessionCmd, getAgentCmd, getToolCmd, getTaskCmd)
+
+	cancelTaskCfg := &cli.CancelTaskCfg{
+		Config: cfg,
+	}
+
+	cancelCmd := &cobra.Command{
+		Use:     "cancel [task_id]",
+		Short:   "Cancel a running task",
+		Long:    `Cancel a running A2A task of an agent`,
+		Args:    cobra.ExactArgs(1),
+		Example: `kagent cancel --agent "k8s-agent" 5c0f1e7a-4b8e-4a3c-9a56-2f3d9c1b7e21`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := cli.CheckServerConnection(cmd.Context(), cfg.Client()); err != nil {
+				pf, err := cli.NewPortForward(cmd.Context(), cfg)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error starting port-forward: %v\n", err)
+					return
+				}
+				defer pf.Stop()
+			}
+			cli.CancelTaskCmd(cmd.Context(), cancelTaskCfg, args[0])
+		},
+	}
+	cancelCmd.Flags().StringVarP(&cancelTaskCfg.Agent, "agent", "a", "", "Agent")
+	cancelCmd.Flags().StringVar(&cancelTaskCfg.Token, "token", "", "Bearer token to include in A2A requests (for API key passthrough)")
 
 	initCfg := &cli.InitCfg{
 		Config: cfg,
@@ -449,7 +530,7 @@ Examples:
 	runCmd.Flags().StringVar(&runCfg.ProjectDir, "project-dir", "", "Project directory (default: current directory)")
 	runCmd.Flags().BoolVar(&runCfg.Build, "build", false, "Rebuild the Docker image before running")
 
-	rootCmd.AddCommand(installCmd, uninstallCmd, invokeCmd, bugReportCmd, versionCmd, dashboardCmd, getCmd, initCmd, buildCmd, deployCmd, addMcpCmd, runCmd, mcp.NewMCPCmd(), envdoc.NewEnvCmd())
+	rootCmd.AddCommand(installCmd, uninstallCmd, invokeCmd, chatCmd, cancelCmd, bugReportCmd, versionCmd, dashboardCmd, getCmd, initCmd, buildCmd, deployCmd, addMcpCmd, runCmd, mcp.NewMCPCmd(), envdoc.NewEnvCmd())
 
 	return rootCmd
 }
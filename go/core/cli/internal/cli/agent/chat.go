@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	api "github.com/kagent-dev/kagent/go/api/httpapi"
+	"github.com/kagent-dev/kagent/go/core/cli/internal/config"
+	"github.com/kagent-dev/kagent/go/core/cli/internal/tui"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+type ChatCfg struct {
+	Config  *config.Config
+	Agent   string
+	Session string
+	Token   string
+}
+
+// ChatCmd opens an interactive chat with an agent. Without --session a new
+// session is created so the conversation also shows up in the UI.
+func ChatCmd(ctx context.Context, cfg *ChatCfg) {
+	a2aClient, err := newA2AClient(ctx, cfg.Config, cfg.Agent, "", cfg.Token)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return
+	}
+
+	sessionID := cfg.Session
+	if sessionID == "" {
+		session, err := cfg.Config.Client().Session.CreateSession(ctx, &api.SessionRequest{
+			Name:     new(fmt.Sprintf("cli chat %s", time.Now().Format(time.DateTime))),
+			AgentRef: new(fmt.Sprintf("%s/%s", cfg.Config.Namespace, cfg.Agent)),
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to create session: %v\n", err)
+			return
+		}
+		sessionID = session.Data.ID
+	}
+
+	sendFn := func(ctx context.Context, params protocol.SendMessageParams) (<-chan protocol.StreamingMessageEvent, error) {
+		return a2aClient.StreamMessage(ctx, params)
+	}
+	cancelFn := func(ctx context.Context, taskID string) error {
+		_, err := a2aClient.CancelTasks(ctx, protocol.TaskIDParams{ID: taskID})
+		return err
+	}
+
+	agentRef := fmt.Sprintf("%s/%s", cfg.Config.Namespace, cfg.Agent)
+	if err := tui.RunChat(agentRef, sessionID, sendFn, cancelFn, cfg.Config.Verbose); err != nil {
+		fmt.Fprintf(os.Stderr, "Chat session failed: %v\n", err)
+	}
+}
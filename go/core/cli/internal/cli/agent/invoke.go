@@ -25,6 +25,9 @@ type InvokeCfg struct {
 	Stream      bool
 	URLOverride string
 	Token       string
+	// ContinueTask is the ID of a prior task to continue. The message is sent
+	// in that task's session so the agent sees its history and workspace.
+	ContinueTask string
 }
 
 // bearerTokenTransport is an http.RoundTripper that injects an Authorization: Bearer header.
@@ -80,70 +83,38 @@ func InvokeCmd(ctx context.Context, cfg *InvokeCfg) {
 		return
 	}
 
-	var a2aClientOpts []a2aclient.Option
-	a2aClientOpts = append(a2aClientOpts, a2aclient.WithTimeout(cfg.Config.Timeout))
-
-	if cfg.Token != "" {
-		a2aClientOpts = append(a2aClientOpts, a2aclient.WithHTTPClient(&http.Client{
-			Transport: &bearerTokenTransport{
-				base:  http.DefaultTransport,
-				token: cfg.Token,
-			},
-		}))
+	a2aClient, err := newA2AClient(ctx, cfg.Config, cfg.Agent, cfg.URLOverride, cfg.Token)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return
 	}
 
-	var a2aClient *a2aclient.A2AClient
-	var err error
-	if cfg.URLOverride != "" {
-		a2aClient, err = a2aclient.NewA2AClient(cfg.URLOverride, a2aClientOpts...)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error creating A2A client: %v\n", err)
-			return
-		}
-	} else {
-		if cfg.Agent == "" {
-			fmt.Fprintln(os.Stderr, "Agent is required")
-			return
-		}
-
-		// Error out if the agent is provided with the namespace (e.g., namespace/agent-name)
-		if strings.Contains(cfg.Agent, "/") {
-			fmt.Fprintf(os.Stderr, "Invalid agent format: use --namespace to specify the namespace. Got'%s'\n", cfg.Agent)
-			return
-		}
-
-		agentResponse, err := clientSet.Agent.GetAgent(ctx, fmt.Sprintf("%s/%s", cfg.Config.Namespace, cfg.Agent))
+	message := protocol.Message{
+		Kind:  protocol.KindMessage,
+		Role:  protocol.MessageRoleUser,
+		Parts: []protocol.Part{protocol.NewTextPart(task)},
+	}
+	if cfg.Session != "" {
+		message.ContextID = &cfg.Session
+	}
+	if cfg.ContinueTask != "" {
+		prior, err := a2aClient.GetTasks(ctx, protocol.TaskQueryParams{ID: cfg.ContinueTask})
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error getting agent metadata: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Error getting task %s: %v\n", cfg.ContinueTask, err)
 			return
 		}
-
-		a2aURL := buildA2AURL(cfg.Config.KAgentURL, cfg.Config.Namespace, cfg.Agent, agentResponse.Data)
-		a2aClient, err = a2aclient.NewA2AClient(a2aURL, a2aClientOpts...)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error creating A2A client: %v\n", err)
+		if err := continueTask(&message, prior); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			return
 		}
 	}
 
-	var sessionID *string
-	if cfg.Session != "" {
-		sessionID = &cfg.Session
-	}
-
 	// Use A2A client to send message
 	if cfg.Stream {
 		ctx, cancel := context.WithTimeout(ctx, 300*time.Second)
 		defer cancel()
 
-		result, err := a2aClient.StreamMessage(ctx, protocol.SendMessageParams{
-			Message: protocol.Message{
-				Kind:      protocol.KindMessage,
-				Role:      protocol.MessageRoleUser,
-				ContextID: sessionID,
-				Parts:     []protocol.Part{protocol.NewTextPart(task)},
-			},
-		})
+		result, err := a2aClient.StreamMessage(ctx, protocol.SendMessageParams{Message: message})
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error invoking session: %v\n", err)
 			return
@@ -153,14 +124,7 @@ func InvokeCmd(ctx context.Context, cfg *InvokeCfg) {
 		ctx, cancel := context.WithTimeout(ctx, 300*time.Second)
 		defer cancel()
 
-		result, err := a2aClient.SendMessage(ctx, protocol.SendMessageParams{
-			Message: protocol.Message{
-				Kind:      protocol.KindMessage,
-				Role:      protocol.MessageRoleUser,
-				ContextID: sessionID,
-				Parts:     []protocol.Part{protocol.NewTextPart(task)},
-			},
-		})
+		result, err := a2aClient.SendMessage(ctx, protocol.SendMessageParams{Message: message})
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error invoking session: %v\n", err)
 			return
@@ -176,6 +140,64 @@ func InvokeCmd(ctx context.Context, cfg *InvokeCfg) {
 	}
 }
 
+// continueTask makes message a follow-up to prior: it is sent in the prior
+// task's session and references the task, so a new task picks up where the
+// previous one stopped.
+func continueTask(message *protocol.Message, prior *protocol.Task) error {
+	if prior.ContextID == "" {
+		return fmt.Errorf("task %s has no session to continue", prior.ID)
+	}
+	if message.ContextID != nil && *message.ContextID != prior.ContextID {
+		return fmt.Errorf("task %s belongs to session %s, not %s", prior.ID, prior.ContextID, *message.ContextID)
+	}
+	message.ContextID = &prior.ContextID
+	message.ReferenceTaskIDs = append(message.ReferenceTaskIDs, prior.ID)
+	return nil
+}
+
+// newA2AClient creates an A2A client for agent in the configured namespace,
+// or for urlOverride when set.
+func newA2AClient(ctx context.Context, cfg *config.Config, agent, urlOverride, token string) (*a2aclient.A2AClient, error) {
+	a2aClientOpts := []a2aclient.Option{a2aclient.WithTimeout(cfg.Timeout)}
+	if token != "" {
+		a2aClientOpts = append(a2aClientOpts, a2aclient.WithHTTPClient(&http.Client{
+			Transport: &bearerTokenTransport{
+				base:  http.DefaultTransport,
+				token: token,
+			},
+		}))
+	}
+
+	if urlOverride != "" {
+		a2aClient, err := a2aclient.NewA2AClient(urlOverride, a2aClientOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create A2A client: %w", err)
+		}
+		return a2aClient, nil
+	}
+
+	if agent == "" {
+		return nil, fmt.Errorf("agent is required")
+	}
+
+	// Error out if the agent is provided with the namespace (e.g., namespace/agent-name)
+	if strings.Contains(agent, "/") {
+		return nil, fmt.Errorf("invalid agent format: use --namespace to specify the namespace, got '%s'", agent)
+	}
+
+	agentResponse, err := cfg.Client().Agent.GetAgent(ctx, fmt.Sprintf("%s/%s", cfg.Namespace, agent))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get agent metadata: %w", err)
+	}
+
+	a2aURL := buildA2AURL(cfg.KAgentURL, cfg.Namespace, agent, agentResponse.Data)
+	a2aClient, err := a2aclient.NewA2AClient(a2aURL, a2aClientOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create A2A client: %w", err)
+	}
+	return a2aClient, nil
+}
+
 func buildA2AURL(baseURL, namespace, agent string, agentResponse *api.AgentResponse) string {
 	a2aPath := "api/a2a"
 	if agentResponse != nil && agentResponse.WorkloadMode == v1alpha2.WorkloadModeSandbox {
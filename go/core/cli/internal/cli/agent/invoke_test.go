@@ -2,16 +2,27 @@ package cli
 
 import (
 	"testing"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
 )
 
-// Note: Most InvokeCmd tests require K8s port-forwarding mock which is complex.
-// Testing InvokeCmd with URLOverride still attempts port-forward first.
-// Integration tests cover the full invoke workflow.
+func TestContinueTask(t *testing.T) {
+	prior := &protocol.Task{ID: "task-1", ContextID: "session-1"}
+
+	message := protocol.Message{Role: protocol.MessageRoleUser}
+	if err := continueTask(&message, prior); err != nil {
+		t.Fatalf("continueTask() error = %v", err)
+	}
+	if message.ContextID == nil || *message.ContextID != "session-1" {
+		t.Errorf("ContextID = %v, want session-1", message.ContextID)
+	}
+	if len(message.ReferenceTaskIDs) != 1 || message.ReferenceTaskIDs[0] != "task-1" {
+		t.Errorf("ReferenceTaskIDs = %v, want [task-1]", message.ReferenceTaskIDs)
+	}
 
-func TestInvokeCmd_ServerError(t *testing.T) {
-	// This behavior is exercised in integration tests, which can safely depend on
-	// Kubernetes port-forwarding and external tooling like kubectl.
-	// Invoking InvokeCmd here would trigger CheckServerConnection and may start a
-	// real kubectl port-forward, which is not appropriate for unit tests.
-	t.Skip("Skipping InvokeCmd server error test in unit suite; covered by integration tests without requiring kubectl/port-forwarding")
+	other := "session-2"
+	message = protocol.Message{Role: protocol.MessageRoleUser, ContextID: &other}
+	if err := continueTask(&message, prior); err == nil {
+		t.Error("continueTask() expected error for a task from another session")
+	}
 }
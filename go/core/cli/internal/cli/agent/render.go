@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kagent-dev/kagent/go/api/utils"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// renderA2AEvent writes a human-readable rendering of a streamed A2A event:
+// tool calls and tool results as they happen, and message text once it is
+// final (working status messages repeat streamed partial text). Mirrors the
+// rendering of the chat TUI.
+func renderA2AEvent(w io.Writer, ev protocol.StreamingMessageEvent) {
+	switch res := ev.Result.(type) {
+	case *protocol.TaskStatusUpdateEvent:
+		if res.Status.Message != nil {
+			renderParts(w, res.Status.Message.Role, res.Status.Message.Parts, res.Final)
+		}
+		if res.Final && res.Status.State != protocol.TaskStateCompleted {
+			fmt.Fprintf(w, "[task %s]\n", res.Status.State)
+		}
+	case *protocol.TaskArtifactUpdateEvent:
+		if res.LastChunk == nil || *res.LastChunk {
+			renderParts(w, protocol.MessageRoleAgent, res.Artifact.Parts, true)
+		}
+	case *protocol.Message:
+		renderParts(w, res.Role, res.Parts, true)
+	case *protocol.Task:
+		renderTask(w, res)
+	}
+}
+
+// renderTask writes the history and artifacts of a task.
+func renderTask(w io.Writer, task *protocol.Task) {
+	for _, msg := range task.History {
+		if partial, _ := utils.GetMetadataValue(msg.Metadata, "partial"); partial == true {
+			continue
+		}
+		renderParts(w, msg.Role, msg.Parts, true)
+	}
+	for _, artifact := range task.Artifacts {
+		renderParts(w, protocol.MessageRoleAgent, artifact.Parts, true)
+	}
+	if task.Status.State != protocol.TaskStateCompleted {
+		fmt.Fprintf(w, "[task %s]\n", task.Status.State)
+	}
+}
+
+func renderParts(w io.Writer, role protocol.MessageRole, parts []protocol.Part, showText bool) {
+	var text strings.Builder
+	for _, part := range parts {
+		switch p := part.(type) {
+		case *protocol.TextPart:
+			text.WriteString(p.Text)
+		case protocol.TextPart:
+			text.WriteString(p.Text)
+		case *protocol.DataPart:
+			renderDataPart(w, p)
+		case protocol.DataPart:
+			renderDataPart(w, &p)
+		}
+	}
+	if s := strings.TrimSpace(text.String()); showText && s != "" {
+		fmt.Fprintf(w, "%s: %s\n", role, s)
+	}
+}
+
+// renderDataPart writes tool calls and tool results; other data parts are
+// only shown with --verbose, which prints raw events instead.
+func renderDataPart(w io.Writer, p *protocol.DataPart) {
+	typeVal, _ := utils.GetMetadataValue(p.Metadata, "type")
+	data, ok := p.Data.(map[string]any)
+	if !ok {
+		return
+	}
+	switch typeVal {
+	case "function_call":
+		fmt.Fprintf(w, "→ %s(%s)\n", data["name"], compactJSON(data["args"]))
+	case "function_response":
+		fmt.Fprintf(w, "← %s: %s\n", data["name"], compactJSON(data["response"]))
+	}
+}
+
+func compactJSON(v any) string {
+	if v == nil {
+		return ""
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+func TestRenderA2AEvent(t *testing.T) {
+	toolCall := &protocol.DataPart{
+		Kind:     protocol.KindData,
+		Data:     map[string]any{"name": "get_pods", "args": map[string]any{"namespace": "default"}},
+		Metadata: map[string]any{"kagent_type": "function_call"},
+	}
+	toolResult := &protocol.DataPart{
+		Kind:     protocol.KindData,
+		Data:     map[string]any{"name": "get_pods", "response": map[string]any{"result": "pod-a"}},
+		Metadata: map[string]any{"adk_type": "function_response"},
+	}
+	working := protocol.NewMessage(protocol.MessageRoleAgent, []protocol.Part{protocol.NewTextPart("streaming..."), toolCall, toolResult})
+	final := protocol.NewMessage(protocol.MessageRoleAgent, []protocol.Part{protocol.NewTextPart("There is one pod.")})
+
+	var buf bytes.Buffer
+	renderA2AEvent(&buf, protocol.StreamingMessageEvent{Result: &protocol.TaskStatusUpdateEvent{
+		Status: protocol.TaskStatus{State: protocol.TaskStateWorking, Message: &working},
+	}})
+	renderA2AEvent(&buf, protocol.StreamingMessageEvent{Result: &protocol.TaskStatusUpdateEvent{
+		Final:  true,
+		Status: protocol.TaskStatus{State: protocol.TaskStateFailed, Message: &final},
+	}})
+
+	want := `→ get_pods({"namespace":"default"})
+← get_pods: {"result":"pod-a"}
+agent: There is one pod.
+[task failed]
+`
+	if got := buf.String(); got != want {
+		t.Errorf("rendered output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRenderTask_SkipsPartialMessages(t *testing.T) {
+	partial := protocol.NewMessage(protocol.MessageRoleAgent, []protocol.Part{protocol.NewTextPart("Hel")})
+	partial.Metadata = map[string]any{"adk_partial": true}
+	task := &protocol.Task{
+		ID:     "task-1",
+		Status: protocol.TaskStatus{State: protocol.TaskStateCompleted},
+		History: []protocol.Message{
+			protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{protocol.NewTextPart("Hi")}),
+			partial,
+			protocol.NewMessage(protocol.MessageRoleAgent, []protocol.Part{protocol.NewTextPart("Hello")}),
+		},
+	}
+
+	var buf bytes.Buffer
+	renderTask(&buf, task)
+	if want := "user: Hi\nagent: Hello\n"; buf.String() != want {
+		t.Errorf("renderTask() = %q, want %q", buf.String(), want)
+	}
+}
@@ -122,8 +122,13 @@ func RunCmd(ctx context.Context, cfg *RunCfg) error {
 		return ch, err
 	}
 
+	cancelFn := func(ctx context.Context, taskID string) error {
+		_, err := a2aClient.CancelTasks(ctx, protocol.TaskIDParams{ID: taskID})
+		return err
+	}
+
 	// Launch TUI chat directly
-	if err := tui.RunChat(manifest.Name, sessionID, sendFn, verbose); err != nil {
+	if err := tui.RunChat(manifest.Name, sessionID, sendFn, cancelFn, verbose); err != nil {
 		return fmt.Errorf("chat session failed: %v", err)
 	}
 
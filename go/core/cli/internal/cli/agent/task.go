@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/kagent-dev/kagent/go/core/cli/internal/config"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+type CancelTaskCfg struct {
+	Config *config.Config
+	Agent  string
+	Token  string
+}
+
+// GetTaskCmd lists the tasks of a session.
+func GetTaskCmd(cfg *config.Config, sessionID string) {
+	tasks, err := cfg.Client().Session.ListSessionTasks(context.Background(), sessionID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to get tasks for session %s: %v\n", sessionID, err)
+		return
+	}
+	if len(tasks.Data) == 0 {
+		fmt.Println("No tasks found")
+		return
+	}
+	if err := printTasks(tasks.Data); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to print tasks: %v\n", err)
+	}
+}
+
+// SessionTranscriptCmd prints the conversation of a session as text, one
+// task after another.
+func SessionTranscriptCmd(cfg *config.Config, sessionID string) {
+	tasks, err := cfg.Client().Session.ListSessionTasks(context.Background(), sessionID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to get tasks for session %s: %v\n", sessionID, err)
+		return
+	}
+	for _, task := range tasks.Data {
+		renderTask(os.Stdout, task)
+	}
+}
+
+// CancelTaskCmd cancels a running task through the agent's A2A endpoint.
+func CancelTaskCmd(ctx context.Context, cfg *CancelTaskCfg, taskID string) {
+	a2aClient, err := newA2AClient(ctx, cfg.Config, cfg.Agent, "", cfg.Token)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return
+	}
+	task, err := a2aClient.CancelTasks(ctx, protocol.TaskIDParams{ID: taskID})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to cancel task %s: %v\n", taskID, err)
+		return
+	}
+	fmt.Printf("Task %s %s\n", task.ID, task.Status.State)
+}
+
+func printTasks(tasks []*protocol.Task) error {
+	headers := []string{"#", "ID", "STATE", "MESSAGES", "UPDATED"}
+	rows := make([][]string, len(tasks))
+	for i, task := range tasks {
+		rows[i] = []string{
+			strconv.Itoa(i + 1),
+			task.ID,
+			string(task.Status.State),
+			strconv.Itoa(len(task.History)),
+			task.Status.Timestamp,
+		}
+	}
+
+	return printOutput(tasks, headers, rows)
+}
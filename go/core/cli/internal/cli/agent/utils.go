@@ -91,21 +91,16 @@ func (p *PortForward) Stop() {
 
 func StreamA2AEvents(ch <-chan protocol.StreamingMessageEvent, verbose bool) {
 	for event := range ch {
-		if verbose {
-			json, err := event.MarshalJSON()
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error marshaling A2A event: %v\n", err)
-				continue
-			}
-			fmt.Fprintf(os.Stdout, "%+v\n", string(json))
-		} else {
-			json, err := event.MarshalJSON()
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error marshaling A2A event: %v\n", err)
-				continue
-			}
-			fmt.Fprintf(os.Stdout, "%+v\n", string(json))
+		if !verbose {
+			renderA2AEvent(os.Stdout, event)
+			continue
+		}
+		json, err := event.MarshalJSON()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error marshaling A2A event: %v\n", err)
+			continue
 		}
+		fmt.Fprintf(os.Stdout, "%+v\n", string(json))
 	}
 	fmt.Fprintln(os.Stdout)
 }
@@ -21,9 +21,14 @@ import (
 // SendMessageFn abstracts the A2A client's StreamMessage method for easier testing.
 type SendMessageFn func(ctx context.Context, params protocol.SendMessageParams) (<-chan protocol.StreamingMessageEvent, error)
 
+// CancelTaskFn abstracts the A2A client's CancelTasks method. It may be nil,
+// in which case /cancel only stops the local stream.
+type CancelTaskFn func(ctx context.Context, taskID string) error
+
 // RunChat starts the TUI chat, blocking until the user exits.
-func RunChat(agentRef string, sessionID string, sendFn SendMessageFn, verbose bool) error {
+func RunChat(agentRef string, sessionID string, sendFn SendMessageFn, cancelFn CancelTaskFn, verbose bool) error {
 	model := newChatModel(agentRef, sessionID, sendFn, verbose)
+	model.cancelTask = cancelFn
 	p := tea.NewProgram(model, tea.WithAltScreen())
 	_, err := p.Run()
 	return err
@@ -62,17 +67,23 @@ type chatModel struct {
 
 	spin spinner.Model
 
-	send      SendMessageFn
-	streamCh  <-chan protocol.StreamingMessageEvent
-	cancel    context.CancelFunc
-	streaming bool
+	send       SendMessageFn
+	cancelTask CancelTaskFn
+	streamCh   <-chan protocol.StreamingMessageEvent
+	cancel     context.CancelFunc
+	streaming  bool
+
+	// taskID is the task of the last streamed event; inputRequired is set
+	// while that task waits for a tool approval decision.
+	taskID        string
+	inputRequired bool
 
 	showInput bool
 }
 
 func newChatModel(agentRef string, sessionID string, send SendMessageFn, verbose bool) *chatModel {
 	input := textarea.New()
-	input.Placeholder = "Type a message (Enter to send)"
+	input.Placeholder = "Type a message (Enter to send, /help for commands)"
 	input.FocusedStyle.CursorLine = lipgloss.NewStyle()
 	input.Prompt = "> "
 	input.ShowLineNumbers = false
@@ -160,15 +171,18 @@ func (m *chatModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if !m.showInput {
 				return m, nil
 			}
-			if m.streaming {
+			text := strings.TrimSpace(m.input.Value())
+			if m.streaming && text != "/cancel" {
 				return m, nil
 			}
-			text := strings.TrimSpace(m.input.Value())
 			if text == "" {
 				return m, nil
 			}
-			m.appendUser(text)
 			m.input.Reset()
+			if strings.HasPrefix(text, "/") {
+				return m, m.runSlashCommand(text)
+			}
+			m.appendUser(text)
 			return m, m.submit(text)
 		}
 	case a2aEventMsg:
@@ -216,6 +230,15 @@ func (m *chatModel) View() string {
 }
 
 func (m *chatModel) submit(text string) tea.Cmd {
+	return m.submitMessage(protocol.Message{
+		Kind:      protocol.KindMessage,
+		Role:      protocol.MessageRoleUser,
+		ContextID: &m.sessionID,
+		Parts:     []protocol.Part{protocol.NewTextPart(text)},
+	})
+}
+
+func (m *chatModel) submitMessage(message protocol.Message) tea.Cmd {
 	m.streaming = true
 	m.working = true
 	m.workStart = time.Now()
@@ -223,16 +246,7 @@ func (m *chatModel) submit(text string) tea.Cmd {
 	ctx, cancel := context.WithCancel(context.Background())
 	m.cancel = cancel
 
-	params := protocol.SendMessageParams{
-		Message: protocol.Message{
-			Kind:      protocol.KindMessage,
-			Role:      protocol.MessageRoleUser,
-			ContextID: &m.sessionID,
-			Parts:     []protocol.Part{protocol.NewTextPart(text)},
-		},
-	}
-
-	ch, err := m.send(ctx, params)
+	ch, err := m.send(ctx, protocol.SendMessageParams{Message: message})
 	if err != nil {
 		m.appendError(err)
 		m.streaming = false
@@ -264,6 +278,11 @@ func (m *chatModel) appendUser(text string) {
 func (m *chatModel) appendEvent(ev protocol.StreamingMessageEvent) {
 	switch res := ev.Result.(type) {
 	case *protocol.TaskStatusUpdateEvent:
+		m.taskID = res.TaskID
+		m.inputRequired = res.Status.State == protocol.TaskStateInputRequired
+		if m.inputRequired {
+			m.appendLine(theme.DimStyle().Render("Approval required: /approve or /reject [reason]"))
+		}
 		if res.Final {
 			m.working = false
 			m.updateStatus()
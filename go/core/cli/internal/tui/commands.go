@@ -0,0 +1,109 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/kagent-dev/kagent/go/core/cli/internal/tui/theme"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+const chatHelp = `Commands:
+  /approve           approve the pending tool call
+  /reject [reason]   reject the pending tool call
+  /cancel            cancel the running task
+  /export [file]     write the transcript to a file
+  /help              show this help`
+
+// runSlashCommand executes a chat slash command typed into the input.
+func (m *chatModel) runSlashCommand(text string) tea.Cmd {
+	name, arg, _ := strings.Cut(text, " ")
+	arg = strings.TrimSpace(arg)
+	switch name {
+	case "/approve":
+		return m.sendDecision("approve", "")
+	case "/reject":
+		return m.sendDecision("reject", arg)
+	case "/cancel":
+		m.cancelRunningTask()
+	case "/export":
+		m.exportTranscript(arg)
+	case "/model":
+		m.appendLine(theme.DimStyle().Render("The model is set by the agent's ModelConfig and cannot be changed from the chat."))
+	case "/help":
+		m.appendLine(theme.DimStyle().Render(chatHelp))
+	default:
+		m.appendError(fmt.Errorf("unknown command %s, type /help for the list of commands", name))
+	}
+	return nil
+}
+
+// sendDecision answers a pending tool approval request of the current task.
+func (m *chatModel) sendDecision(decision, reason string) tea.Cmd {
+	if !m.inputRequired || m.taskID == "" {
+		m.appendError(fmt.Errorf("no tool call is waiting for approval"))
+		return nil
+	}
+	data := map[string]any{"decision_type": decision}
+	if reason != "" {
+		data["rejection_reason"] = reason
+	}
+	display := "Approved"
+	if decision == "reject" {
+		display = "Rejected"
+		if reason != "" {
+			display += ": " + reason
+		}
+	}
+	m.appendUser(display)
+	m.inputRequired = false
+	taskID := m.taskID
+	return m.submitMessage(protocol.Message{
+		Kind:      protocol.KindMessage,
+		Role:      protocol.MessageRoleUser,
+		ContextID: &m.sessionID,
+		TaskID:    &taskID,
+		Parts:     []protocol.Part{protocol.NewDataPart(data)},
+	})
+}
+
+// cancelRunningTask stops the local stream and asks the agent to cancel the
+// task when a CancelTaskFn is configured.
+func (m *chatModel) cancelRunningTask() {
+	if m.cancel != nil {
+		m.cancel()
+	}
+	m.streaming = false
+	m.working = false
+	m.inputRequired = false
+	m.updateStatus()
+	if m.cancelTask == nil || m.taskID == "" {
+		m.appendLine(theme.DimStyle().Render("Stopped streaming."))
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := m.cancelTask(ctx, m.taskID); err != nil {
+		m.appendError(fmt.Errorf("failed to cancel task %s: %w", m.taskID, err))
+		return
+	}
+	m.appendLine(theme.DimStyle().Render(fmt.Sprintf("Canceled task %s.", m.taskID)))
+}
+
+// exportTranscript writes the chat history without styling to path, or to
+// kagent-<session>.txt in the current directory.
+func (m *chatModel) exportTranscript(path string) {
+	if path == "" {
+		path = fmt.Sprintf("kagent-%s.txt", m.sessionID)
+	}
+	if err := os.WriteFile(path, []byte(ansi.Strip(m.history)+"\n"), 0o600); err != nil {
+		m.appendError(fmt.Errorf("failed to export transcript: %w", err))
+		return
+	}
+	m.appendLine(theme.DimStyle().Render(fmt.Sprintf("Transcript written to %s", path)))
+}
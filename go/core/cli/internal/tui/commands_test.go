@@ -0,0 +1,68 @@
+package tui
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+func TestChatModel_ApproveSendsDecision(t *testing.T) {
+	var sent []protocol.SendMessageParams
+	send := func(_ context.Context, params protocol.SendMessageParams) (<-chan protocol.StreamingMessageEvent, error) {
+		sent = append(sent, params)
+		ch := make(chan protocol.StreamingMessageEvent)
+		close(ch)
+		return ch, nil
+	}
+	m := newChatModel("kagent/k8s-agent", "session-1", send, false)
+
+	// Without a pending approval the command is rejected locally.
+	m.runSlashCommand("/approve")
+	assert.Empty(t, sent)
+
+	m.appendEvent(protocol.StreamingMessageEvent{Result: &protocol.TaskStatusUpdateEvent{
+		TaskID: "task-1",
+		Status: protocol.TaskStatus{State: protocol.TaskStateInputRequired},
+	}})
+	require.True(t, m.inputRequired)
+
+	m.runSlashCommand("/reject too risky")
+	require.Len(t, sent, 1)
+	msg := sent[0].Message
+	require.NotNil(t, msg.TaskID)
+	assert.Equal(t, "task-1", *msg.TaskID)
+	assert.Equal(t, "session-1", *msg.ContextID)
+	require.Len(t, msg.Parts, 1)
+	dp, ok := msg.Parts[0].(protocol.DataPart)
+	require.True(t, ok)
+	assert.Equal(t, map[string]any{"decision_type": "reject", "rejection_reason": "too risky"}, dp.Data)
+	assert.False(t, m.inputRequired)
+}
+
+func TestChatModel_CancelAndExport(t *testing.T) {
+	m := newChatModel("kagent/k8s-agent", "session-1", nil, false)
+	var canceled string
+	m.cancelTask = func(_ context.Context, taskID string) error {
+		canceled = taskID
+		return nil
+	}
+	m.taskID = "task-1"
+	m.streaming = true
+
+	m.runSlashCommand("/cancel")
+	assert.Equal(t, "task-1", canceled)
+	assert.False(t, m.streaming)
+
+	path := filepath.Join(t.TempDir(), "chat.txt")
+	m.runSlashCommand("/export " + path)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "Chat with kagent/k8s-agent (session session-1)")
+	assert.Contains(t, string(data), "Canceled task task-1.")
+	assert.NotContains(t, string(data), "\x1b[")
+}
@@ -477,12 +477,17 @@ func (m *workspaceModel) startChat(loadHistory bool) tea.Cmd {
 	sendFn := func(ctx context.Context, params protocol.SendMessageParams) (<-chan protocol.StreamingMessageEvent, error) {
 		return client.StreamMessage(ctx, params)
 	}
+	cancelFn := func(ctx context.Context, taskID string) error {
+		_, err := client.CancelTasks(ctx, protocol.TaskIDParams{ID: taskID})
+		return err
+	}
 	// Reset chat for new session
 	if m.chat == nil {
 		m.chat = newChatModel(m.agentRef, m.current.ID, sendFn, m.verbose)
 	} else {
 		*m.chat = *newChatModel(m.agentRef, m.current.ID, sendFn, m.verbose)
 	}
+	m.chat.cancelTask = cancelFn
 	// Set header and clear transcript
 	title := theme.HeadingStyle().Render(fmt.Sprintf("Chat with %s (session %s)", m.agentRef, m.current.ID))
 	m.chat.ResetTranscript(title)
@@ -83,10 +83,14 @@ func usesMCPService(agent v1alpha2.AgentObject, obj types.NamespacedName) bool {
 
 func usesModelConfig(agent v1alpha2.AgentObject, obj types.NamespacedName) bool {
 	spec := agent.GetAgentSpec()
-	return agent.GetNamespace() == obj.Namespace &&
-		spec.Type == v1alpha2.AgentType_Declarative &&
-		spec.Declarative != nil &&
-		spec.Declarative.ModelConfig == obj.Name
+	if agent.GetNamespace() != obj.Namespace || spec.Type != v1alpha2.AgentType_Declarative || spec.Declarative == nil {
+		return false
+	}
+	if spec.Declarative.ModelConfig == obj.Name {
+		return true
+	}
+	reflection := spec.Declarative.Reflection
+	return reflection != nil && reflection.CritiqueModelConfig == obj.Name
 }
 
 func referencesConfigMap(agent v1alpha2.AgentObject, obj types.NamespacedName) bool {
@@ -546,6 +546,9 @@ func (a *adkApiTranslator) translateModel(ctx context.Context, namespace, modelC
 			if spec.TopK > 0 {
 				anthropic.TopK = &spec.TopK
 			}
+			if spec.ThinkingBudgetTokens > 0 {
+				anthropic.ThinkingBudgetTokens = &spec.ThinkingBudgetTokens
+			}
 		}
 		return anthropic, modelDeploymentData, secretHashBytes, nil
 	case v1alpha2.ModelProviderAzureOpenAI:
@@ -641,6 +644,19 @@ func (a *adkApiTranslator) translateModel(ctx context.Context, namespace, modelC
 		populateTLSFields(&gemini.BaseModel, model.Spec.TLS)
 		gemini.APIKeyPassthrough = model.Spec.APIKeyPassthrough
 
+		spec := model.Spec.GeminiVertexAI
+		gemini.Temperature = utils.ParseStringToFloat64(spec.Temperature)
+		gemini.TopP = utils.ParseStringToFloat64(spec.TopP)
+		gemini.TopK = utils.ParseStringToFloat64(spec.TopK)
+		gemini.StopSequences = spec.StopSequences
+		if spec.MaxOutputTokens > 0 {
+			gemini.MaxOutputTokens = &spec.MaxOutputTokens
+		}
+		if spec.CandidateCount > 0 {
+			gemini.CandidateCount = &spec.CandidateCount
+		}
+		gemini.ResponseMimeType = spec.ResponseMimeType
+
 		return gemini, modelDeploymentData, secretHashBytes, nil
 	case v1alpha2.ModelProviderAnthropicVertexAI:
 		if model.Spec.AnthropicVertexAI == nil {
@@ -682,6 +698,15 @@ func (a *adkApiTranslator) translateModel(ctx context.Context, namespace, modelC
 		populateTLSFields(&anthropic.BaseModel, model.Spec.TLS)
 		anthropic.APIKeyPassthrough = model.Spec.APIKeyPassthrough
 
+		spec := model.Spec.AnthropicVertexAI
+		anthropic.Temperature = utils.ParseStringToFloat64(spec.Temperature)
+		anthropic.TopP = utils.ParseStringToFloat64(spec.TopP)
+		anthropic.TopK = utils.ParseStringToInt(spec.TopK)
+		anthropic.StopSequences = spec.StopSequences
+		if spec.MaxTokens > 0 {
+			anthropic.MaxTokens = &spec.MaxTokens
+		}
+
 		return anthropic, modelDeploymentData, secretHashBytes, nil
 	case v1alpha2.ModelProviderOllama:
 		if model.Spec.Ollama == nil {
@@ -1040,6 +1065,26 @@ func (a *adkApiTranslator) translateMCPServerTarget(ctx context.Context, agent *
 	}
 }
 
+// toADKToolRetryPolicy converts a CRD retry policy into the ADK config form,
+// with durations expressed in seconds.
+func toADKToolRetryPolicy(p *v1alpha2.ToolRetryPolicy) *adk.ToolRetryPolicy {
+	if p == nil {
+		return nil
+	}
+	out := &adk.ToolRetryPolicy{
+		MaxAttempts:     int(p.MaxAttempts),
+		RetryableErrors: p.RetryableErrors,
+		Tools:           p.Tools,
+	}
+	if p.InitialBackoff != nil {
+		out.InitialBackoff = new(p.InitialBackoff.Seconds())
+	}
+	if p.MaxBackoff != nil {
+		out.MaxBackoff = new(p.MaxBackoff.Seconds())
+	}
+	return out
+}
+
 func (a *adkApiTranslator) translateRemoteMCPServerTarget(ctx context.Context, agent *adk.AgentConfig, mdd *modelDeploymentData, remoteMcpServer *v1alpha2.RemoteMCPServer, mcpServerTool *v1alpha2.McpServerTool, agentHeaders map[string]string, proxyURL string, egressRewrite bool) ([]byte, error) {
 	switch remoteMcpServer.Spec.Protocol {
 	case v1alpha2.RemoteMCPServerProtocolSse:
@@ -1052,6 +1097,8 @@ func (a *adkApiTranslator) translateRemoteMCPServerTarget(ctx context.Context, a
 			Tools:           mcpServerTool.ToolNames,
 			AllowedHeaders:  mcpServerTool.AllowedHeaders,
 			RequireApproval: mcpServerTool.RequireApproval,
+			RetryPolicy:     toADKToolRetryPolicy(mcpServerTool.RetryPolicy),
+			ToolPrefix:      mcpServerTool.ToolPrefix,
 		})
 	default:
 		tool, err := a.translateStreamableHttpTool(ctx, remoteMcpServer, agentHeaders, proxyURL, egressRewrite)
@@ -1063,6 +1110,8 @@ func (a *adkApiTranslator) translateRemoteMCPServerTarget(ctx context.Context, a
 			Tools:           mcpServerTool.ToolNames,
 			AllowedHeaders:  mcpServerTool.AllowedHeaders,
 			RequireApproval: mcpServerTool.RequireApproval,
+			RetryPolicy:     toADKToolRetryPolicy(mcpServerTool.RetryPolicy),
+			ToolPrefix:      mcpServerTool.ToolPrefix,
 		})
 	}
 	// Mount the CA Secret on the agent pod when the RemoteMCPServer pins a TLS bundle.
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"slices"
+	"strconv"
 
 	a2a "github.com/a2aproject/a2a-go/v2/a2a"
 	"github.com/kagent-dev/kagent/go/api/adk"
@@ -95,7 +96,7 @@ func toolAgentURL(agent v1alpha2.AgentObject) string {
 			utils.GetControllerName(), utils.GetResourceNamespace(),
 			sandboxA2APathPrefix, agent.GetNamespace(), agent.GetName())
 	}
-	return fmt.Sprintf("http://%s.%s:8080", agent.GetName(), agent.GetNamespace())
+	return agentServiceURL(agent)
 }
 
 func TranslateAgent(
@@ -131,6 +132,9 @@ func (a *adkApiTranslator) CompileAgent(
 		if err != nil {
 			return nil, err
 		}
+		if err := validateRuntimeSupport(agent, cfg); err != nil {
+			return nil, err
+		}
 		dep, err = resolveInlineDeployment(agent, mdd)
 		if err != nil {
 			return nil, err
@@ -167,6 +171,115 @@ func (a *adkApiTranslator) CompileAgent(
 	}, nil
 }
 
+// validateRuntimeSupport rejects settings that only the Go runtime
+// implements, rather than letting the Python runtime silently ignore them.
+func validateRuntimeSupport(agent v1alpha2.AgentObject, cfg *adk.AgentConfig) error {
+	if v1alpha2.EffectiveDeclarativeRuntime(agent.GetAgentSpec()) == v1alpha2.DeclarativeRuntime_Go {
+		return nil
+	}
+	models := []adk.Model{cfg.Model}
+	if cfg.ContextConfig != nil && cfg.ContextConfig.Compaction != nil {
+		models = append(models, cfg.ContextConfig.Compaction.SummarizerModel)
+	}
+	for _, model := range models {
+		if anthropic, ok := model.(*adk.Anthropic); ok && anthropic.ThinkingBudgetTokens != nil {
+			return NewValidationError("thinkingBudgetTokens requires the go runtime; set spec.declarative.runtime to go or remove it from ModelConfig")
+		}
+		if len(modelKeyPool(model)) > 0 {
+			return NewValidationError("key_pool requires the go runtime; set spec.declarative.runtime to go or remove it")
+		}
+	}
+	for _, ht := range cfg.HttpTools {
+		if ht.RetryPolicy != nil {
+			return NewValidationError("tool retryPolicy requires the go runtime; set spec.declarative.runtime to go or remove it from the MCP server tools")
+		}
+		if ht.ToolPrefix != "" {
+			return NewValidationError("toolPrefix requires the go runtime; set spec.declarative.runtime to go or remove it from the MCP server tools")
+		}
+	}
+	for _, st := range cfg.SseTools {
+		if st.RetryPolicy != nil {
+			return NewValidationError("tool retryPolicy requires the go runtime; set spec.declarative.runtime to go or remove it from the MCP server tools")
+		}
+		if st.ToolPrefix != "" {
+			return NewValidationError("toolPrefix requires the go runtime; set spec.declarative.runtime to go or remove it from the MCP server tools")
+		}
+	}
+	if cfg.ToolConflictPolicy != "" {
+		return NewValidationError("toolConflictPolicy requires the go runtime; set spec.declarative.runtime to go or remove it")
+	}
+	if cfg.Generation != nil {
+		return NewValidationError("generation requires the go runtime; set spec.declarative.runtime to go or remove it")
+	}
+	if cfg.BestOf != nil {
+		return NewValidationError("bestOf requires the go runtime; set spec.declarative.runtime to go or remove it")
+	}
+	if cfg.Reflection != nil {
+		return NewValidationError("reflection requires the go runtime; set spec.declarative.runtime to go or remove it")
+	}
+	if cfg.Strategy == adk.AgentStrategyPlanExecute {
+		return NewValidationError("the plan-execute strategy requires the go runtime; set spec.declarative.runtime to go or remove it")
+	}
+	if cfg.Knowledge != nil {
+		return NewValidationError("knowledge requires the go runtime; set spec.declarative.runtime to go or remove it")
+	}
+	return nil
+}
+
+// modelKeyPool returns the key pool of the model types whose clients the
+// Go runtime builds with one.
+func modelKeyPool(model adk.Model) []adk.ModelKey {
+	switch m := model.(type) {
+	case *adk.OpenAI:
+		return m.KeyPool
+	case *adk.AzureOpenAI:
+		return m.KeyPool
+	case *adk.Anthropic:
+		return m.KeyPool
+	case *adk.Gemini:
+		return m.KeyPool
+	}
+	return nil
+}
+
+// translateGeneration converts the agent's generation settings, rejecting
+// values the model providers would not accept.
+func translateGeneration(spec *v1alpha2.GenerationSpec) (*adk.GenerationConfig, error) {
+	if spec == nil {
+		return nil, nil
+	}
+	g := &adk.GenerationConfig{StopSequences: slices.Clone(spec.StopSequences)}
+	for _, f := range []struct {
+		name  string
+		value string
+		dst   **float64
+	}{
+		{"temperature", spec.Temperature, &g.Temperature},
+		{"topP", spec.TopP, &g.TopP},
+	} {
+		if f.value == "" {
+			continue
+		}
+		v, err := strconv.ParseFloat(f.value, 64)
+		if err != nil {
+			return nil, NewValidationError("invalid generation %s %q: must be a number", f.name, f.value)
+		}
+		*f.dst = &v
+	}
+	if spec.TopK > 0 {
+		topK := spec.TopK
+		g.TopK = &topK
+	}
+	if spec.MaxOutputTokens > 0 {
+		maxTokens := spec.MaxOutputTokens
+		g.MaxOutputTokens = &maxTokens
+	}
+	if err := g.Validate(); err != nil {
+		return nil, NewValidationError("invalid generation settings: %s", err.Error())
+	}
+	return g, nil
+}
+
 func (a *adkApiTranslator) validateAgent(ctx context.Context, agent v1alpha2.AgentObject, state *tState) error {
 	agentRef := utils.GetObjectRef(agent)
 	spec := agent.GetAgentSpec()
@@ -281,6 +394,50 @@ func (a *adkApiTranslator) translateInlineAgent(ctx context.Context, agent v1alp
 		cfg.ContextConfig = contextCfg
 	}
 
+	cfg.ToolConflictPolicy = adk.ToolConflictPolicy(spec.Declarative.ToolConflictPolicy)
+
+	generation, err := translateGeneration(spec.Declarative.Generation)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	cfg.Generation = generation
+
+	if bestOf := spec.Declarative.BestOf; bestOf != nil {
+		cfg.BestOf = &adk.BestOfConfig{
+			Candidates: bestOf.Candidates,
+			Scorer:     adk.BestOfScorer(bestOf.Scorer),
+		}
+		if err := cfg.BestOf.Validate(); err != nil {
+			return nil, nil, nil, NewValidationError("%s", err.Error())
+		}
+	}
+
+	cfg.Strategy = adk.AgentStrategy(spec.Declarative.Strategy)
+	if err := cfg.Strategy.Validate(); err != nil {
+		return nil, nil, nil, NewValidationError("%s", err.Error())
+	}
+
+	if reflection := spec.Declarative.Reflection; reflection != nil {
+		cfg.Reflection = &adk.ReflectionConfig{
+			MaxPasses:      reflection.MaxPasses,
+			CritiquePrompt: reflection.CritiquePrompt,
+		}
+		if err := cfg.Reflection.Validate(); err != nil {
+			return nil, nil, nil, NewValidationError("%s", err.Error())
+		}
+		if name := reflection.CritiqueModelConfig; name != "" && name != spec.Declarative.ModelConfig {
+			critiqueModel, critiqueMdd, critiqueSecretHash, err := a.translateModel(ctx, agent.GetNamespace(), name)
+			if err != nil {
+				return nil, nil, nil, fmt.Errorf("failed to translate critique model config %q: %w", name, err)
+			}
+			cfg.Reflection.CritiqueModel = critiqueModel
+			mergeDeploymentData(mdd, critiqueMdd)
+			if len(critiqueSecretHash) > 0 {
+				secretHashBytes = append(secretHashBytes, critiqueSecretHash...)
+			}
+		}
+	}
+
 	// ShareTools: pass the flag through to AgentConfig; the Python runtime injects the tools.
 	if spec.Declarative.ShareTools != nil && *spec.Declarative.ShareTools {
 		t := true
@@ -305,6 +462,24 @@ func (a *adkApiTranslator) translateInlineAgent(ctx context.Context, agent v1alp
 		}
 	}
 
+	if spec.Declarative.Knowledge != nil {
+		embCfg, embMdd, embHash, err := a.translateEmbeddingConfig(ctx, agent.GetNamespace(), spec.Declarative.Knowledge.ModelConfig)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to resolve knowledge embedding config: %w", err)
+		}
+
+		cfg.Knowledge = &adk.KnowledgeConfig{
+			Collections: spec.Declarative.Knowledge.Collections,
+			TopK:        spec.Declarative.Knowledge.TopK,
+			Embedding:   embCfg,
+		}
+
+		mergeDeploymentData(mdd, embMdd)
+		if spec.Declarative.Knowledge.ModelConfig != spec.Declarative.ModelConfig {
+			secretHashBytes = append(secretHashBytes, embHash...)
+		}
+	}
+
 	for _, tool := range spec.Declarative.Tools {
 		headers, err := tool.ResolveHeaders(ctx, a.kube, agent.GetNamespace())
 		if err != nil {
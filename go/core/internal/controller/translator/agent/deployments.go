@@ -11,6 +11,7 @@ import (
 
 	"github.com/kagent-dev/kagent/go/api/v1alpha2"
 	"github.com/kagent-dev/kagent/go/core/internal/controller/translator/labels"
+	"github.com/kagent-dev/kagent/go/core/pkg/env"
 )
 
 // Internal to translator - Data added to the deployment spec for an inline agent
@@ -222,6 +223,8 @@ func resolveInlineDeployment(agent v1alpha2.AgentObject, mdd *modelDeploymentDat
 		return nil, err
 	}
 
+	wsVolumes, wsMounts, wsEnv := workspaceVolume(spec.Workspace)
+
 	dep := &resolvedDeployment{
 		Image:                image,
 		Args:                 args,
@@ -229,11 +232,11 @@ func resolveInlineDeployment(agent v1alpha2.AgentObject, mdd *modelDeploymentDat
 		ImagePullPolicy:      imagePullPolicy,
 		Replicas:             spec.Replicas,
 		ImagePullSecrets:     slices.Clone(spec.ImagePullSecrets),
-		Volumes:              append(slices.Clone(spec.Volumes), mdd.Volumes...),
-		VolumeMounts:         append(slices.Clone(spec.VolumeMounts), mdd.VolumeMounts...),
+		Volumes:              slices.Concat(spec.Volumes, mdd.Volumes, wsVolumes),
+		VolumeMounts:         slices.Concat(spec.VolumeMounts, mdd.VolumeMounts, wsMounts),
 		Labels:               getDefaultLabels(agent.GetName(), spec.Labels),
 		Annotations:          maps.Clone(spec.Annotations),
-		Env:                  append(slices.Clone(spec.Env), mdd.EnvVars...),
+		Env:                  slices.Concat(spec.Env, mdd.EnvVars, wsEnv),
 		Resources:            getDefaultResources(spec.Resources), // Set default resources if not specified
 		Tolerations:          slices.Clone(spec.Tolerations),
 		Affinity:             spec.Affinity,
@@ -257,6 +260,33 @@ func resolveInlineDeployment(agent v1alpha2.AgentObject, mdd *modelDeploymentDat
 	return dep, nil
 }
 
+// workspaceMountPath is where the workspace volume is mounted: <tmp>/kagent,
+// the directory both runtimes create session workspaces in by default.
+const workspaceMountPath = "/tmp/kagent"
+
+// workspaceVolume returns the volume, mount and environment putting the
+// agent's session workspaces on the workspace claim, or nothing without one.
+func workspaceVolume(ws *v1alpha2.AgentWorkspace) ([]corev1.Volume, []corev1.VolumeMount, []corev1.EnvVar) {
+	if ws == nil {
+		return nil, nil, nil
+	}
+	volumes := []corev1.Volume{{
+		Name: "kagent-workspace",
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: ws.ClaimName},
+		},
+	}}
+	mounts := []corev1.VolumeMount{{
+		Name:      "kagent-workspace",
+		MountPath: workspaceMountPath,
+	}}
+	envVars := []corev1.EnvVar{
+		{Name: env.KagentWorkspaceDir.Name(), Value: workspaceMountPath},
+		{Name: env.KagentWorkspaceDriver.Name(), Value: "pvc"},
+	}
+	return volumes, mounts, envVars
+}
+
 func checkPullSecretAlreadyPresent(spec v1alpha2.DeclarativeDeploymentSpec) bool {
 	alreadyPresent := false
 	for _, secret := range spec.ImagePullSecrets {
@@ -304,6 +334,8 @@ func resolveByoDeployment(agent v1alpha2.AgentObject) (*resolvedDeployment, erro
 		return nil, err
 	}
 
+	wsVolumes, wsMounts, wsEnv := workspaceVolume(spec.Workspace)
+
 	dep := &resolvedDeployment{
 		Image:                image,
 		Cmd:                  cmd,
@@ -313,11 +345,11 @@ func resolveByoDeployment(agent v1alpha2.AgentObject) (*resolvedDeployment, erro
 		ImagePullPolicy:      imagePullPolicy,
 		Replicas:             replicas,
 		ImagePullSecrets:     slices.Clone(spec.ImagePullSecrets),
-		Volumes:              slices.Clone(spec.Volumes),
-		VolumeMounts:         slices.Clone(spec.VolumeMounts),
+		Volumes:              slices.Concat(spec.Volumes, wsVolumes),
+		VolumeMounts:         slices.Concat(spec.VolumeMounts, wsMounts),
 		Labels:               getDefaultLabels(agent.GetName(), spec.Labels),
 		Annotations:          maps.Clone(spec.Annotations),
-		Env:                  slices.Clone(spec.Env),
+		Env:                  slices.Concat(spec.Env, wsEnv),
 		Resources:            getDefaultResources(spec.Resources), // Set default resources if not specified
 		Tolerations:          slices.Clone(spec.Tolerations),
 		Affinity:             spec.Affinity,
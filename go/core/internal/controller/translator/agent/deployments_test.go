@@ -29,6 +29,43 @@ func TestResolveByoDeployment_NilReplicasPreserved(t *testing.T) {
 	}
 }
 
+func TestResolveByoDeployment_Workspace(t *testing.T) {
+	agent := &v1alpha2.Agent{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+		Spec: v1alpha2.AgentSpec{
+			Type: v1alpha2.AgentType_BYO,
+			BYO: &v1alpha2.BYOAgentSpec{
+				Deployment: &v1alpha2.ByoDeploymentSpec{
+					Image: "my-image:latest",
+					SharedDeploymentSpec: v1alpha2.SharedDeploymentSpec{
+						Replicas:  new(int32(3)),
+						Workspace: &v1alpha2.AgentWorkspace{ClaimName: "workspaces"},
+					},
+				},
+			},
+		},
+	}
+	dep, err := resolveByoDeployment(agent)
+	if err != nil {
+		t.Fatalf("resolveByoDeployment() error = %v", err)
+	}
+	if len(dep.Volumes) != 1 || dep.Volumes[0].PersistentVolumeClaim == nil || dep.Volumes[0].PersistentVolumeClaim.ClaimName != "workspaces" {
+		t.Errorf("Volumes = %+v, want the workspaces claim", dep.Volumes)
+	}
+	if len(dep.VolumeMounts) != 1 || dep.VolumeMounts[0].MountPath != "/tmp/kagent" {
+		t.Errorf("VolumeMounts = %+v, want the claim mounted at /tmp/kagent", dep.VolumeMounts)
+	}
+	want := map[string]string{"KAGENT_WORKSPACE_DIR": "/tmp/kagent", "KAGENT_WORKSPACE_DRIVER": "pvc"}
+	for _, e := range dep.Env {
+		if want[e.Name] == e.Value {
+			delete(want, e.Name)
+		}
+	}
+	if len(want) != 0 {
+		t.Errorf("Env = %+v, missing %v", dep.Env, want)
+	}
+}
+
 func TestValidateExtraContainers(t *testing.T) {
 	t.Parallel()
 
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"maps"
+	"strconv"
+	"strings"
 
 	a2a "github.com/a2aproject/a2a-go/v2/a2a"
 	"github.com/a2aproject/a2a-go/v2/a2acompat/a2av0"
@@ -536,12 +538,7 @@ func buildPodTemplate(
 				Resources:       dep.Resources,
 				Env:             runtimeInputs.envVars,
 				ReadinessProbe: &corev1.Probe{
-					ProbeHandler: corev1.ProbeHandler{
-						HTTPGet: &corev1.HTTPGetAction{
-							Path: "/.well-known/agent-card.json",
-							Port: intstr.FromString("http"),
-						},
-					},
+					ProbeHandler:        readinessProbeHandler(runtimeInputs.envVars),
 					InitialDelaySeconds: probeConf.InitialDelaySeconds,
 					TimeoutSeconds:      probeConf.TimeoutSeconds,
 					PeriodSeconds:       probeConf.PeriodSeconds,
@@ -557,6 +554,44 @@ func buildPodTemplate(
 	}
 }
 
+// readinessProbeHandler probes the agent card over the listener's scheme:
+// HTTPS when the agent serves TLS via KAGENT_TLS_CERT_FILE, HTTP otherwise.
+// The kubelet cannot present a client certificate, so when the agent
+// requires one the probe only checks that the port accepts connections.
+func readinessProbeHandler(env []corev1.EnvVar) corev1.ProbeHandler {
+	if !envSet(env, envAgentTLSCertFile) {
+		return corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{
+			Path: "/.well-known/agent-card.json",
+			Port: intstr.FromString("http"),
+		}}
+	}
+	if requireClientCert(env) {
+		return corev1.ProbeHandler{TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromString("http")}}
+	}
+	return corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{
+		Path:   "/.well-known/agent-card.json",
+		Port:   intstr.FromString("http"),
+		Scheme: corev1.URISchemeHTTPS,
+	}}
+}
+
+// requireClientCert reports whether the agent rejects clients without a
+// certificate. A value taken from a reference cannot be resolved here and
+// is treated as required.
+func requireClientCert(env []corev1.EnvVar) bool {
+	for _, e := range env {
+		if e.Name != envAgentTLSRequireClientCert {
+			continue
+		}
+		if e.ValueFrom != nil {
+			return true
+		}
+		required, err := strconv.ParseBool(strings.TrimSpace(e.Value))
+		return err == nil && required
+	}
+	return false
+}
+
 func agentRuntime(agent v1alpha2.AgentObject) v1alpha2.DeclarativeRuntime {
 	return v1alpha2.EffectiveDeclarativeRuntime(agent.GetAgentSpec())
 }
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/kagent-dev/kagent/go/api/v1alpha2"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -117,3 +118,22 @@ func TestBuildConfigSecretData_IncludesSRTSettingsWhenPresent(t *testing.T) {
 		t.Fatal("srt-settings.json should be present when non-empty")
 	}
 }
+
+func TestReadinessProbeHandler(t *testing.T) {
+	plain := readinessProbeHandler([]corev1.EnvVar{{Name: "KAGENT_URL", Value: "http://kagent"}})
+	if plain.HTTPGet == nil || plain.HTTPGet.Scheme != "" {
+		t.Errorf("readinessProbeHandler() without TLS = %+v, want an HTTP GET", plain)
+	}
+
+	tlsEnv := []corev1.EnvVar{{Name: "KAGENT_TLS_CERT_FILE", Value: "/etc/tls/tls.crt"}}
+	https := readinessProbeHandler(tlsEnv)
+	if https.HTTPGet == nil || https.HTTPGet.Scheme != corev1.URISchemeHTTPS {
+		t.Errorf("readinessProbeHandler() with TLS = %+v, want an HTTPS GET", https)
+	}
+
+	mtlsEnv := append(tlsEnv, corev1.EnvVar{Name: "KAGENT_TLS_REQUIRE_CLIENT_CERT", Value: "true"})
+	mtls := readinessProbeHandler(mtlsEnv)
+	if mtls.TCPSocket == nil || mtls.HTTPGet != nil {
+		t.Errorf("readinessProbeHandler() with required client certs = %+v, want a TCP probe", mtls)
+	}
+}
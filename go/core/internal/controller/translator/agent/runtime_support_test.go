@@ -0,0 +1,171 @@
+package agent
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kagent-dev/kagent/go/api/adk"
+	"github.com/kagent-dev/kagent/go/api/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidateRuntimeSupport_ThinkingBudget(t *testing.T) {
+	newAgent := func(runtime v1alpha2.DeclarativeRuntime) *v1alpha2.Agent {
+		return &v1alpha2.Agent{
+			ObjectMeta: metav1.ObjectMeta{Name: "thinker", Namespace: "default"},
+			Spec: v1alpha2.AgentSpec{
+				Type:        v1alpha2.AgentType_Declarative,
+				Declarative: &v1alpha2.DeclarativeAgentSpec{Runtime: runtime},
+			},
+		}
+	}
+	thinking := &adk.AgentConfig{Model: &adk.Anthropic{ThinkingBudgetTokens: new(2048)}}
+
+	assert.NoError(t, validateRuntimeSupport(newAgent(v1alpha2.DeclarativeRuntime_Go), thinking))
+	assert.NoError(t, validateRuntimeSupport(newAgent(v1alpha2.DeclarativeRuntime_Python), &adk.AgentConfig{Model: &adk.Anthropic{}}))
+
+	err := validateRuntimeSupport(newAgent(v1alpha2.DeclarativeRuntime_Python), thinking)
+	var validationErr *ValidationError
+	require.True(t, errors.As(err, &validationErr), "expected a ValidationError, got %v", err)
+
+	summarizer := &adk.AgentConfig{
+		Model: &adk.Anthropic{},
+		ContextConfig: &adk.AgentContextConfig{
+			Compaction: &adk.AgentCompressionConfig{SummarizerModel: &adk.Anthropic{ThinkingBudgetTokens: new(2048)}},
+		},
+	}
+	assert.Error(t, validateRuntimeSupport(newAgent(""), summarizer))
+}
+
+func TestValidateRuntimeSupport_RetryPolicy(t *testing.T) {
+	agent := &v1alpha2.Agent{
+		ObjectMeta: metav1.ObjectMeta{Name: "retrier", Namespace: "default"},
+		Spec: v1alpha2.AgentSpec{
+			Type:        v1alpha2.AgentType_Declarative,
+			Declarative: &v1alpha2.DeclarativeAgentSpec{Runtime: v1alpha2.DeclarativeRuntime_Python},
+		},
+	}
+	cfg := &adk.AgentConfig{
+		Model:     &adk.OpenAI{},
+		HttpTools: []adk.HttpMcpServerConfig{{RetryPolicy: &adk.ToolRetryPolicy{MaxAttempts: 3}}},
+	}
+	assert.Error(t, validateRuntimeSupport(agent, cfg))
+
+	agent.Spec.Declarative.Runtime = v1alpha2.DeclarativeRuntime_Go
+	assert.NoError(t, validateRuntimeSupport(agent, cfg))
+}
+
+func TestValidateRuntimeSupport_ToolConflicts(t *testing.T) {
+	agent := &v1alpha2.Agent{
+		ObjectMeta: metav1.ObjectMeta{Name: "prefixed", Namespace: "default"},
+		Spec: v1alpha2.AgentSpec{
+			Type:        v1alpha2.AgentType_Declarative,
+			Declarative: &v1alpha2.DeclarativeAgentSpec{Runtime: v1alpha2.DeclarativeRuntime_Python},
+		},
+	}
+	prefixed := &adk.AgentConfig{
+		Model:    &adk.OpenAI{},
+		SseTools: []adk.SseMcpServerConfig{{ToolPrefix: "k8s"}},
+	}
+	assert.Error(t, validateRuntimeSupport(agent, prefixed))
+	policy := &adk.AgentConfig{Model: &adk.OpenAI{}, ToolConflictPolicy: adk.ToolConflictPolicyPreferLocal}
+	assert.Error(t, validateRuntimeSupport(agent, policy))
+
+	agent.Spec.Declarative.Runtime = v1alpha2.DeclarativeRuntime_Go
+	assert.NoError(t, validateRuntimeSupport(agent, prefixed))
+	assert.NoError(t, validateRuntimeSupport(agent, policy))
+}
+
+func TestValidateRuntimeSupport_Sampling(t *testing.T) {
+	agent := &v1alpha2.Agent{
+		ObjectMeta: metav1.ObjectMeta{Name: "sampled", Namespace: "default"},
+		Spec: v1alpha2.AgentSpec{
+			Type:        v1alpha2.AgentType_Declarative,
+			Declarative: &v1alpha2.DeclarativeAgentSpec{Runtime: v1alpha2.DeclarativeRuntime_Python},
+		},
+	}
+	temp := 0.2
+	cfg := &adk.AgentConfig{Model: &adk.OpenAI{}, Generation: &adk.GenerationConfig{Temperature: &temp}}
+	assert.Error(t, validateRuntimeSupport(agent, cfg))
+	bestOf := &adk.AgentConfig{Model: &adk.OpenAI{}, BestOf: &adk.BestOfConfig{Candidates: 3}}
+	assert.Error(t, validateRuntimeSupport(agent, bestOf))
+	reflection := &adk.AgentConfig{Model: &adk.OpenAI{}, Reflection: &adk.ReflectionConfig{}}
+	assert.Error(t, validateRuntimeSupport(agent, reflection))
+	planExecute := &adk.AgentConfig{Model: &adk.OpenAI{}, Strategy: adk.AgentStrategyPlanExecute}
+	assert.Error(t, validateRuntimeSupport(agent, planExecute))
+	assert.NoError(t, validateRuntimeSupport(agent, &adk.AgentConfig{Model: &adk.OpenAI{}, Strategy: adk.AgentStrategyReact}))
+
+	agent.Spec.Declarative.Runtime = v1alpha2.DeclarativeRuntime_Go
+	assert.NoError(t, validateRuntimeSupport(agent, cfg))
+	assert.NoError(t, validateRuntimeSupport(agent, bestOf))
+	assert.NoError(t, validateRuntimeSupport(agent, reflection))
+	assert.NoError(t, validateRuntimeSupport(agent, planExecute))
+}
+
+func TestValidateRuntimeSupport_KeyPool(t *testing.T) {
+	agent := &v1alpha2.Agent{
+		ObjectMeta: metav1.ObjectMeta{Name: "pooled", Namespace: "default"},
+		Spec: v1alpha2.AgentSpec{
+			Type:        v1alpha2.AgentType_Declarative,
+			Declarative: &v1alpha2.DeclarativeAgentSpec{Runtime: v1alpha2.DeclarativeRuntime_Python},
+		},
+	}
+	pool := adk.BaseModel{KeyPool: []adk.ModelKey{{APIKeyEnv: "OPENAI_API_KEY_2"}}}
+	cfg := &adk.AgentConfig{Model: &adk.OpenAI{BaseModel: pool}}
+	summarized := &adk.AgentConfig{
+		Model: &adk.OpenAI{},
+		ContextConfig: &adk.AgentContextConfig{
+			Compaction: &adk.AgentCompressionConfig{SummarizerModel: &adk.Anthropic{BaseModel: pool}},
+		},
+	}
+	assert.Error(t, validateRuntimeSupport(agent, cfg))
+	assert.Error(t, validateRuntimeSupport(agent, summarized))
+
+	agent.Spec.Declarative.Runtime = v1alpha2.DeclarativeRuntime_Go
+	assert.NoError(t, validateRuntimeSupport(agent, cfg))
+	assert.NoError(t, validateRuntimeSupport(agent, summarized))
+}
+
+func TestValidateRuntimeSupport_Knowledge(t *testing.T) {
+	agent := &v1alpha2.Agent{
+		ObjectMeta: metav1.ObjectMeta{Name: "librarian", Namespace: "default"},
+		Spec: v1alpha2.AgentSpec{
+			Type:        v1alpha2.AgentType_Declarative,
+			Declarative: &v1alpha2.DeclarativeAgentSpec{Runtime: v1alpha2.DeclarativeRuntime_Python},
+		},
+	}
+	cfg := &adk.AgentConfig{Model: &adk.OpenAI{}, Knowledge: &adk.KnowledgeConfig{}}
+	assert.Error(t, validateRuntimeSupport(agent, cfg))
+
+	agent.Spec.Declarative.Runtime = v1alpha2.DeclarativeRuntime_Go
+	assert.NoError(t, validateRuntimeSupport(agent, cfg))
+}
+
+func TestTranslateGeneration(t *testing.T) {
+	g, err := translateGeneration(&v1alpha2.GenerationSpec{
+		Temperature:     "0.3",
+		TopK:            40,
+		MaxOutputTokens: 1024,
+		StopSequences:   []string{"END"},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, g.Temperature)
+	assert.Equal(t, 0.3, *g.Temperature)
+	assert.Nil(t, g.TopP)
+	require.NotNil(t, g.TopK)
+	assert.Equal(t, 40, *g.TopK)
+	require.NotNil(t, g.MaxOutputTokens)
+	assert.Equal(t, 1024, *g.MaxOutputTokens)
+	assert.Equal(t, []string{"END"}, g.StopSequences)
+
+	g, err = translateGeneration(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, g)
+
+	_, err = translateGeneration(&v1alpha2.GenerationSpec{Temperature: "hot"})
+	assert.Error(t, err)
+	_, err = translateGeneration(&v1alpha2.GenerationSpec{TopP: "1.5"})
+	assert.Error(t, err)
+}
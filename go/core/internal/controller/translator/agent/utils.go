@@ -6,8 +6,49 @@ import (
 
 	a2atype "github.com/a2aproject/a2a-go/v2/a2a"
 	"github.com/kagent-dev/kagent/go/api/v1alpha2"
+	corev1 "k8s.io/api/core/v1"
 )
 
+// Agent container env vars that switch the A2A listener to TLS, mirroring
+// the ADK tlsconfig package.
+const (
+	envAgentTLSCertFile          = "KAGENT_TLS_CERT_FILE"
+	envAgentTLSRequireClientCert = "KAGENT_TLS_REQUIRE_CLIENT_CERT"
+)
+
+// agentDeploymentEnv returns the env vars the agent spec sets on its
+// container.
+func agentDeploymentEnv(agent v1alpha2.AgentObject) []corev1.EnvVar {
+	spec := agent.GetAgentSpec()
+	switch {
+	case spec.Type == v1alpha2.AgentType_Declarative && spec.Declarative != nil && spec.Declarative.Deployment != nil:
+		return spec.Declarative.Deployment.Env
+	case spec.Type == v1alpha2.AgentType_BYO && spec.BYO != nil && spec.BYO.Deployment != nil:
+		return spec.BYO.Deployment.Env
+	}
+	return nil
+}
+
+// envSet reports whether env sets name to a non-empty value or a reference.
+func envSet(env []corev1.EnvVar, name string) bool {
+	for _, e := range env {
+		if e.Name == name && (e.Value != "" || e.ValueFrom != nil) {
+			return true
+		}
+	}
+	return false
+}
+
+// agentServiceURL returns the in-cluster A2A URL of an agent's Service,
+// using HTTPS when the agent serves TLS.
+func agentServiceURL(agent v1alpha2.AgentObject) string {
+	scheme := "http"
+	if envSet(agentDeploymentEnv(agent), envAgentTLSCertFile) {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s.%s:8080", scheme, agent.GetName(), agent.GetNamespace())
+}
+
 func GetA2AAgentCard(agent v1alpha2.AgentObject) *a2atype.AgentCard {
 	spec := agent.GetAgentSpec()
 	card := a2atype.AgentCard{
@@ -15,12 +56,12 @@ func GetA2AAgentCard(agent v1alpha2.AgentObject) *a2atype.AgentCard {
 		Description: spec.Description,
 		SupportedInterfaces: []*a2atype.AgentInterface{
 			{
-				URL:             fmt.Sprintf("http://%s.%s:8080", agent.GetName(), agent.GetNamespace()),
+				URL:             agentServiceURL(agent),
 				ProtocolBinding: a2atype.TransportProtocolJSONRPC,
 				ProtocolVersion: a2atype.ProtocolVersion("0.3"),
 			},
 			{
-				URL:             fmt.Sprintf("http://%s.%s:8080", agent.GetName(), agent.GetNamespace()),
+				URL:             agentServiceURL(agent),
 				ProtocolBinding: a2atype.TransportProtocolJSONRPC,
 				ProtocolVersion: a2atype.Version,
 			},
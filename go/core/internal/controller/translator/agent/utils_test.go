@@ -6,6 +6,7 @@ import (
 	a2atype "github.com/a2aproject/a2a-go/v2/a2a"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/kagent-dev/kagent/go/api/v1alpha2"
@@ -82,6 +83,29 @@ func TestGetA2AAgentCard(t *testing.T) {
 			wantURL:         "http://no-a2a.default:8080",
 			wantSkills:      []a2atype.AgentSkill{},
 		},
+		{
+			name: "declarative agent serving TLS",
+			agent: &v1alpha2.Agent{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "tls-agent",
+					Namespace: "default",
+				},
+				Spec: v1alpha2.AgentSpec{
+					Type: v1alpha2.AgentType_Declarative,
+					Declarative: &v1alpha2.DeclarativeAgentSpec{
+						Deployment: &v1alpha2.DeclarativeDeploymentSpec{
+							SharedDeploymentSpec: v1alpha2.SharedDeploymentSpec{
+								Env: []corev1.EnvVar{{Name: "KAGENT_TLS_CERT_FILE", Value: "/etc/tls/tls.crt"}},
+							},
+						},
+					},
+				},
+			},
+			wantName:        "tls_agent",
+			wantDescription: "",
+			wantURL:         "https://tls-agent.default:8080",
+			wantSkills:      []a2atype.AgentSkill{},
+		},
 		{
 			name: "BYO agent",
 			agent: &v1alpha2.Agent{
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -267,6 +268,21 @@ func (c *postgresClient) ListEventsForSession(ctx context.Context, sessionID, us
 	return events, nil
 }
 
+func (c *postgresClient) SupersedeEventsFrom(ctx context.Context, sessionID, userID, eventID string) ([]string, error) {
+	ids, err := c.q.SoftDeleteSessionEventsFrom(ctx, dbgen.SoftDeleteSessionEventsFromParams{
+		SessionID: strPtrIfNotEmpty(sessionID),
+		UserID:    userID,
+		ID:        eventID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to supersede events for session: %w", err)
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("event %s not found in session %s: %w", eventID, sessionID, pgx.ErrNoRows)
+	}
+	return ids, nil
+}
+
 // ── Tasks ─────────────────────────────────────────────────────────────────────
 
 // TODO(0.11.0): Switch task writes to v1 storage format and remove legacy conversion from this write path.
@@ -312,6 +328,75 @@ func (c *postgresClient) ListTasksForSession(ctx context.Context, sessionID stri
 	return tasks, nil
 }
 
+func (c *postgresClient) ListTasks(ctx context.Context, filter dbpkg.TaskFilter) ([]*a2a.Task, error) {
+	params := dbgen.ListTasksParams{
+		UserID:   strPtrIfNotEmpty(filter.UserID),
+		States:   taskStateNames(filter),
+		RowLimit: taskRowLimit(filter),
+	}
+	if !filter.Since.IsZero() {
+		params.Since = &filter.Since
+	}
+	rows, err := c.q.ListTasks(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tasks: %w", err)
+	}
+	tasks := make([]*a2a.Task, 0, len(rows))
+	for i, r := range rows {
+		task, err := parseVersionedTask(r.Data, r.ProtocolVersion)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse task row %d: %w", i, err)
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+func (c *postgresClient) ListTasksWithAgents(ctx context.Context, filter dbpkg.TaskFilter) ([]dbpkg.TaskWithAgent, error) {
+	params := dbgen.ListTasksWithAgentsParams{
+		States:   taskStateNames(filter),
+		RowLimit: taskRowLimit(filter),
+	}
+	if !filter.Since.IsZero() {
+		params.Since = &filter.Since
+	}
+	rows, err := c.q.ListTasksWithAgents(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tasks with agents: %w", err)
+	}
+	tasks := make([]dbpkg.TaskWithAgent, 0, len(rows))
+	for i, r := range rows {
+		task, err := parseVersionedTask(r.Data, r.ProtocolVersion)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse task row %d: %w", i, err)
+		}
+		tasks = append(tasks, dbpkg.TaskWithAgent{Task: task, AgentID: derefStr(r.AgentID), UserID: derefStr(r.UserID)})
+	}
+	return tasks, nil
+}
+
+// taskStateNames returns the state names a task filter matches. The state is
+// read from the task JSON, which names it in the legacy or the v1 format
+// depending on the row's protocol version, so both names are matched.
+func taskStateNames(filter dbpkg.TaskFilter) []string {
+	states := filter.States
+	if filter.State != "" {
+		states = append(slices.Clone(states), filter.State)
+	}
+	var names []string
+	for _, state := range states {
+		names = append(names, string(state), string(trpcv0.ToLegacyTaskState(state)))
+	}
+	return names
+}
+
+func taskRowLimit(filter dbpkg.TaskFilter) *int32 {
+	if filter.Limit <= 0 {
+		return nil
+	}
+	return new(int32(filter.Limit))
+}
+
 func (c *postgresClient) DeleteTask(ctx context.Context, taskID string) error {
 	return c.q.SoftDeleteTask(ctx, taskID)
 }
@@ -773,6 +858,109 @@ func (c *postgresClient) PruneExpiredMemories(ctx context.Context) error {
 	})
 }
 
+// ── Knowledge (document retrieval) ────────────────────────────────────────────
+
+// StoreKnowledgeDocument replaces the chunks of a document.
+func (c *postgresClient) StoreKnowledgeDocument(ctx context.Context, collection, documentID string, chunks []dbpkg.KnowledgeChunk) error {
+	return c.withTx(ctx, func(q *dbgen.Queries) error {
+		if err := q.DeleteKnowledgeDocument(ctx, dbgen.DeleteKnowledgeDocumentParams{Collection: collection, DocumentID: documentID}); err != nil {
+			return fmt.Errorf("failed to delete previous knowledge chunks: %w", err)
+		}
+		for i, chunk := range chunks {
+			metadata, err := knowledgeMetadataJSON(chunk.Metadata)
+			if err != nil {
+				return err
+			}
+			if err := q.InsertKnowledgeChunk(ctx, dbgen.InsertKnowledgeChunkParams{
+				Collection:  collection,
+				DocumentID:  documentID,
+				ChunkIndex:  int32(i),
+				Content:     chunk.Content,
+				StartOffset: int32(chunk.StartOffset),
+				EndOffset:   int32(chunk.EndOffset),
+				Embedding:   chunk.Embedding,
+				Metadata:    metadata,
+			}); err != nil {
+				return fmt.Errorf("failed to store knowledge chunk %d: %w", i, err)
+			}
+		}
+		return nil
+	})
+}
+
+// SearchKnowledge returns the chunks of collection closest to embedding whose
+// metadata contains every entry of filter.
+func (c *postgresClient) SearchKnowledge(ctx context.Context, collection string, embedding pgvector.Vector, filter map[string]string, limit int) ([]dbpkg.KnowledgeSearchResult, error) {
+	filterJSON, err := knowledgeMetadataJSON(filter)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := c.q.SearchKnowledgeChunks(ctx, dbgen.SearchKnowledgeChunksParams{
+		Embedding:  embedding,
+		Collection: collection,
+		Filter:     filterJSON,
+		RowLimit:   int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search knowledge: %w", err)
+	}
+
+	results := make([]dbpkg.KnowledgeSearchResult, len(rows))
+	for i, r := range rows {
+		var metadata map[string]string
+		if err := json.Unmarshal(r.Metadata, &metadata); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal metadata of knowledge chunk %s: %w", r.ID, err)
+		}
+		results[i] = dbpkg.KnowledgeSearchResult{
+			KnowledgeChunk: dbpkg.KnowledgeChunk{
+				ID:          r.ID,
+				Collection:  r.Collection,
+				DocumentID:  r.DocumentID,
+				ChunkIndex:  int(r.ChunkIndex),
+				Content:     r.Content,
+				StartOffset: int(r.StartOffset),
+				EndOffset:   int(r.EndOffset),
+				Metadata:    metadata,
+				CreatedAt:   r.CreatedAt,
+			},
+			Score: r.Score,
+		}
+	}
+	return results, nil
+}
+
+// knowledgeMetadataJSON encodes chunk metadata or a search filter, as an
+// empty object when there is none.
+func knowledgeMetadataJSON(m map[string]string) ([]byte, error) {
+	if len(m) == 0 {
+		return []byte("{}"), nil
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal knowledge metadata: %w", err)
+	}
+	return data, nil
+}
+
+func (c *postgresClient) ListKnowledgeDocuments(ctx context.Context, collection string) ([]dbpkg.KnowledgeDocument, error) {
+	rows, err := c.q.ListKnowledgeDocuments(ctx, collection)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list knowledge documents: %w", err)
+	}
+	docs := make([]dbpkg.KnowledgeDocument, len(rows))
+	for i, r := range rows {
+		docs[i] = dbpkg.KnowledgeDocument{ID: r.DocumentID, ChunkCount: int(r.ChunkCount), CreatedAt: r.CreatedAt}
+	}
+	return docs, nil
+}
+
+func (c *postgresClient) DeleteKnowledgeDocument(ctx context.Context, collection, documentID string) error {
+	if err := c.q.DeleteKnowledgeDocument(ctx, dbgen.DeleteKnowledgeDocumentParams{Collection: collection, DocumentID: documentID}); err != nil {
+		return fmt.Errorf("failed to delete knowledge document: %w", err)
+	}
+	return nil
+}
+
 // ── Conversion helpers ────────────────────────────────────────────────────────
 
 func toAgent(r dbgen.Agent) *dbpkg.Agent {
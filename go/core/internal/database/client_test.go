@@ -336,6 +336,76 @@ func TestStoreTaskTouchesSessionActivity(t *testing.T) {
 	assert.True(t, got.UpdatedAt.After(before.UpdatedAt), "session updated_at should advance after storing a task")
 }
 
+func TestListTasks(t *testing.T) {
+	db := setupTestDB(t)
+	client := NewClient(db)
+	ctx := context.Background()
+
+	for _, sess := range []*dbpkg.Session{
+		{ID: "session-a", UserID: "alice"},
+		{ID: "session-b", UserID: "bob"},
+	} {
+		require.NoError(t, client.StoreSession(ctx, sess))
+	}
+	for _, task := range []*a2a.Task{
+		{ID: "task-1", ContextID: "session-a", Status: a2a.TaskStatus{State: a2a.TaskStateCompleted}},
+		{ID: "task-2", ContextID: "session-a", Status: a2a.TaskStatus{State: a2a.TaskStateFailed}},
+		{ID: "task-3", ContextID: "session-b", Status: a2a.TaskStatus{State: a2a.TaskStateCompleted}},
+	} {
+		require.NoError(t, client.StoreTask(ctx, task))
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	taskIDs := func(filter dbpkg.TaskFilter) []string {
+		tasks, err := client.ListTasks(ctx, filter)
+		require.NoError(t, err)
+		ids := make([]string, 0, len(tasks))
+		for _, task := range tasks {
+			ids = append(ids, string(task.ID))
+		}
+		return ids
+	}
+
+	assert.Equal(t, []string{"task-3", "task-2", "task-1"}, taskIDs(dbpkg.TaskFilter{}))
+	assert.Equal(t, []string{"task-2", "task-1"}, taskIDs(dbpkg.TaskFilter{UserID: "alice"}))
+	assert.Equal(t, []string{"task-3", "task-1"}, taskIDs(dbpkg.TaskFilter{State: a2a.TaskStateCompleted}))
+	assert.Equal(t, []string{"task-3"}, taskIDs(dbpkg.TaskFilter{State: a2a.TaskStateCompleted, Limit: 1}))
+	assert.Empty(t, taskIDs(dbpkg.TaskFilter{Since: time.Now().Add(time.Hour)}))
+
+	assert.Equal(t, []string{"task-3", "task-2", "task-1"}, taskIDs(dbpkg.TaskFilter{States: []a2a.TaskState{a2a.TaskStateCompleted, a2a.TaskStateFailed}}))
+
+	require.NoError(t, client.DeleteTask(ctx, "task-3"))
+	assert.Equal(t, []string{"task-2", "task-1"}, taskIDs(dbpkg.TaskFilter{}))
+}
+
+func TestListTasksWithAgents(t *testing.T) {
+	db := setupTestDB(t)
+	client := NewClient(db)
+	ctx := context.Background()
+
+	require.NoError(t, client.StoreSession(ctx, &dbpkg.Session{ID: "session-a", UserID: "alice", AgentID: new("kagent__NS__a")}))
+	for _, task := range []*a2a.Task{
+		{ID: "task-1", ContextID: "session-a", Status: a2a.TaskStatus{State: a2a.TaskStateCompleted}},
+		{ID: "task-2", ContextID: "orphan", Status: a2a.TaskStatus{State: a2a.TaskStateFailed}},
+	} {
+		require.NoError(t, client.StoreTask(ctx, task))
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	tasks, err := client.ListTasksWithAgents(ctx, dbpkg.TaskFilter{})
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+	assert.Equal(t, a2a.TaskID("task-2"), tasks[0].Task.ID)
+	assert.Empty(t, tasks[0].AgentID)
+	assert.Equal(t, "kagent__NS__a", tasks[1].AgentID)
+	assert.Equal(t, "alice", tasks[1].UserID)
+
+	tasks, err = client.ListTasksWithAgents(ctx, dbpkg.TaskFilter{State: a2a.TaskStateCompleted, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, a2a.TaskID("task-1"), tasks[0].Task.ID)
+}
+
 // TestStoreAgentIdempotence verifies that calling StoreAgent multiple times
 // with the same data is idempotent and doesn't error. This is critical for
 // the lock-free concurrency model where concurrent upserts must succeed.
@@ -329,3 +329,39 @@ func (q *Queries) SoftDeleteEvent(ctx context.Context, id string) error {
 	_, err := q.db.Exec(ctx, softDeleteEvent, id)
 	return err
 }
+
+const softDeleteSessionEventsFrom = `-- name: SoftDeleteSessionEventsFrom :many
+UPDATE event SET deleted_at = NOW()
+WHERE session_id = $1 AND user_id = $2 AND deleted_at IS NULL
+  AND created_at >= (
+    SELECT e.created_at FROM event e
+    WHERE e.id = $3 AND e.session_id = $1 AND e.user_id = $2 AND e.deleted_at IS NULL
+  )
+RETURNING id
+`
+
+type SoftDeleteSessionEventsFromParams struct {
+	SessionID *string
+	UserID    string
+	ID        string
+}
+
+func (q *Queries) SoftDeleteSessionEventsFrom(ctx context.Context, arg SoftDeleteSessionEventsFromParams) ([]string, error) {
+	rows, err := q.db.Query(ctx, softDeleteSessionEventsFrom, arg.SessionID, arg.UserID, arg.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
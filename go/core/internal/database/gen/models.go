@@ -64,6 +64,19 @@ type Feedback struct {
 	IssueType    *database.FeedbackIssueType
 }
 
+type KnowledgeChunk struct {
+	ID          string
+	Collection  string
+	DocumentID  string
+	ChunkIndex  int32
+	Content     string
+	StartOffset int32
+	EndOffset   int32
+	Embedding   pgvector_go.Vector
+	Metadata    []byte
+	CreatedAt   time.Time
+}
+
 type LgCheckpoint struct {
 	UserID             string
 	ThreadID           string
@@ -12,6 +12,7 @@ type Querier interface {
 	CreateSessionShare(ctx context.Context, arg CreateSessionShareParams) (SessionShare, error)
 	DeleteAgentMemory(ctx context.Context, arg DeleteAgentMemoryParams) error
 	DeleteExpiredMemories(ctx context.Context) error
+	DeleteKnowledgeDocument(ctx context.Context, arg DeleteKnowledgeDocumentParams) error
 	DeleteSessionShare(ctx context.Context, arg DeleteSessionShareParams) error
 	ExtendMemoryTTL(ctx context.Context) error
 	GetAgent(ctx context.Context, id string) (Agent, error)
@@ -28,6 +29,7 @@ type Querier interface {
 	IncrementMemoryAccessCount(ctx context.Context, dollar_1 []string) error
 	InsertEvent(ctx context.Context, arg InsertEventParams) error
 	InsertFeedback(ctx context.Context, arg InsertFeedbackParams) error
+	InsertKnowledgeChunk(ctx context.Context, arg InsertKnowledgeChunkParams) error
 	InsertMemory(ctx context.Context, arg InsertMemoryParams) (string, error)
 	ListAgentMemories(ctx context.Context, arg ListAgentMemoriesParams) ([]Memory, error)
 	ListAgents(ctx context.Context) ([]Agent, error)
@@ -41,12 +43,15 @@ type Querier interface {
 	ListEventsForSessionDesc(ctx context.Context, arg ListEventsForSessionDescParams) ([]Event, error)
 	ListEventsForSessionDescLimit(ctx context.Context, arg ListEventsForSessionDescLimitParams) ([]Event, error)
 	ListFeedback(ctx context.Context, userID string) ([]Feedback, error)
+	ListKnowledgeDocuments(ctx context.Context, collection string) ([]ListKnowledgeDocumentsRow, error)
 	ListPushNotifications(ctx context.Context, taskID string) ([]PushNotification, error)
 	ListSessionSharesBySession(ctx context.Context, sessionID string) ([]SessionShare, error)
 	ListSessions(ctx context.Context, userID string) ([]Session, error)
 	ListSessionsForAgent(ctx context.Context, arg ListSessionsForAgentParams) ([]ListSessionsForAgentRow, error)
 	ListSessionsForAgentAllUsers(ctx context.Context, agentID *string) ([]Session, error)
+	ListTasks(ctx context.Context, arg ListTasksParams) ([]Task, error)
 	ListTasksForSession(ctx context.Context, sessionID *string) ([]Task, error)
+	ListTasksWithAgents(ctx context.Context, arg ListTasksWithAgentsParams) ([]ListTasksWithAgentsRow, error)
 	ListToolServers(ctx context.Context) ([]Toolserver, error)
 	ListTools(ctx context.Context) ([]Tool, error)
 	ListToolsForServer(ctx context.Context, arg ListToolsForServerParams) ([]Tool, error)
@@ -55,10 +60,13 @@ type Querier interface {
 	SearchAgentMemory(ctx context.Context, arg SearchAgentMemoryParams) ([]SearchAgentMemoryRow, error)
 	SearchCrewAIMemoryByTask(ctx context.Context, arg SearchCrewAIMemoryByTaskParams) ([]CrewaiAgentMemory, error)
 	SearchCrewAIMemoryByTaskLimit(ctx context.Context, arg SearchCrewAIMemoryByTaskLimitParams) ([]CrewaiAgentMemory, error)
+	// The filter matches chunks whose metadata contains all of its keys and values.
+	SearchKnowledgeChunks(ctx context.Context, arg SearchKnowledgeChunksParams) ([]SearchKnowledgeChunksRow, error)
 	SoftDeleteAgent(ctx context.Context, id string) error
 	SoftDeleteCheckpointWrites(ctx context.Context, arg SoftDeleteCheckpointWritesParams) error
 	SoftDeleteCheckpoints(ctx context.Context, arg SoftDeleteCheckpointsParams) error
 	SoftDeleteEvent(ctx context.Context, id string) error
+	SoftDeleteSessionEventsFrom(ctx context.Context, arg SoftDeleteSessionEventsFromParams) ([]string, error)
 	SoftDeletePushNotification(ctx context.Context, taskID string) error
 	SoftDeleteSession(ctx context.Context, arg SoftDeleteSessionParams) error
 	SoftDeleteTask(ctx context.Context, id string) error
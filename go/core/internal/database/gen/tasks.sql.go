@@ -7,6 +7,7 @@ package dbgen
 
 import (
 	"context"
+	"time"
 )
 
 const getTask = `-- name: GetTask :one
@@ -30,6 +31,61 @@ func (q *Queries) GetTask(ctx context.Context, id string) (Task, error) {
 	return i, err
 }
 
+const listTasks = `-- name: ListTasks :many
+SELECT id, created_at, updated_at, deleted_at, data, session_id, protocol_version FROM task
+WHERE deleted_at IS NULL
+  AND ($1::text IS NULL OR EXISTS (
+      SELECT 1 FROM session
+      WHERE session.id = task.session_id
+        AND session.user_id = $1
+        AND session.deleted_at IS NULL
+  ))
+  AND ($2::timestamptz IS NULL OR updated_at >= $2)
+  AND ($3::text[] IS NULL OR (data::jsonb -> 'status' ->> 'state') = ANY($3::text[]))
+ORDER BY updated_at DESC
+LIMIT $4
+`
+
+type ListTasksParams struct {
+	UserID   *string
+	Since    *time.Time
+	States   []string
+	RowLimit *int32
+}
+
+func (q *Queries) ListTasks(ctx context.Context, arg ListTasksParams) ([]Task, error) {
+	rows, err := q.db.Query(ctx, listTasks,
+		arg.UserID,
+		arg.Since,
+		arg.States,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Task
+	for rows.Next() {
+		var i Task
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.Data,
+			&i.SessionID,
+			&i.ProtocolVersion,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listTasksForSession = `-- name: ListTasksForSession :many
 SELECT id, created_at, updated_at, deleted_at, data, session_id, protocol_version FROM task
 WHERE session_id = $1 AND deleted_at IS NULL
@@ -64,6 +120,63 @@ func (q *Queries) ListTasksForSession(ctx context.Context, sessionID *string) ([
 	return items, nil
 }
 
+const listTasksWithAgents = `-- name: ListTasksWithAgents :many
+SELECT task.id, task.data, task.protocol_version, agent.agent_id, agent.user_id
+FROM task
+LEFT JOIN LATERAL (
+    SELECT session.agent_id, session.user_id FROM session
+    WHERE session.id = task.session_id
+      AND session.deleted_at IS NULL
+      AND (session.source IS NULL OR session.source != 'agent')
+    LIMIT 1
+) agent ON TRUE
+WHERE task.deleted_at IS NULL
+  AND ($1::timestamptz IS NULL OR task.updated_at >= $1)
+  AND ($2::text[] IS NULL OR (task.data::jsonb -> 'status' ->> 'state') = ANY($2::text[]))
+ORDER BY task.updated_at DESC
+LIMIT $3
+`
+
+type ListTasksWithAgentsParams struct {
+	Since    *time.Time
+	States   []string
+	RowLimit *int32
+}
+
+type ListTasksWithAgentsRow struct {
+	ID              string
+	Data            string
+	ProtocolVersion *string
+	AgentID         *string
+	UserID          *string
+}
+
+func (q *Queries) ListTasksWithAgents(ctx context.Context, arg ListTasksWithAgentsParams) ([]ListTasksWithAgentsRow, error) {
+	rows, err := q.db.Query(ctx, listTasksWithAgents, arg.Since, arg.States, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTasksWithAgentsRow
+	for rows.Next() {
+		var i ListTasksWithAgentsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Data,
+			&i.ProtocolVersion,
+			&i.AgentID,
+			&i.UserID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const softDeleteTask = `-- name: SoftDeleteTask :exec
 UPDATE task SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL
 `
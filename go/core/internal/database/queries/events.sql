@@ -57,3 +57,12 @@ LIMIT $2;
 -- name: SoftDeleteEvent :exec
 UPDATE event SET deleted_at = NOW()
 WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: SoftDeleteSessionEventsFrom :many
+UPDATE event SET deleted_at = NOW()
+WHERE session_id = $1 AND user_id = $2 AND deleted_at IS NULL
+  AND created_at >= (
+    SELECT e.created_at FROM event e
+    WHERE e.id = $3 AND e.session_id = $1 AND e.user_id = $2 AND e.deleted_at IS NULL
+  )
+RETURNING id;
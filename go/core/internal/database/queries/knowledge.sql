@@ -0,0 +1,22 @@
+-- name: InsertKnowledgeChunk :exec
+INSERT INTO knowledge_chunk (collection, document_id, chunk_index, content, start_offset, end_offset, embedding, metadata)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+
+-- name: DeleteKnowledgeDocument :exec
+DELETE FROM knowledge_chunk WHERE collection = $1 AND document_id = $2;
+
+-- name: SearchKnowledgeChunks :many
+-- The filter matches chunks whose metadata contains all of its keys and values.
+SELECT id, collection, document_id, chunk_index, content, start_offset, end_offset, metadata, created_at,
+       (1 - (embedding <=> sqlc.arg(embedding)))::float8 AS score
+FROM knowledge_chunk
+WHERE collection = sqlc.arg(collection) AND metadata @> sqlc.arg(filter)::jsonb
+ORDER BY embedding <=> sqlc.arg(embedding) ASC
+LIMIT sqlc.arg(row_limit);
+
+-- name: ListKnowledgeDocuments :many
+SELECT document_id, COUNT(*) AS chunk_count, MAX(created_at)::timestamptz AS created_at
+FROM knowledge_chunk
+WHERE collection = $1
+GROUP BY document_id
+ORDER BY document_id;
@@ -13,6 +13,36 @@ SELECT * FROM task
 WHERE session_id = $1 AND deleted_at IS NULL
 ORDER BY created_at ASC;
 
+-- name: ListTasks :many
+SELECT * FROM task
+WHERE deleted_at IS NULL
+  AND (sqlc.narg(user_id)::text IS NULL OR EXISTS (
+      SELECT 1 FROM session
+      WHERE session.id = task.session_id
+        AND session.user_id = sqlc.narg(user_id)
+        AND session.deleted_at IS NULL
+  ))
+  AND (sqlc.narg(since)::timestamptz IS NULL OR updated_at >= sqlc.narg(since))
+  AND (sqlc.narg(states)::text[] IS NULL OR (data::jsonb -> 'status' ->> 'state') = ANY(sqlc.narg(states)::text[]))
+ORDER BY updated_at DESC
+LIMIT sqlc.narg(row_limit);
+
+-- name: ListTasksWithAgents :many
+SELECT task.id, task.data, task.protocol_version, agent.agent_id, agent.user_id
+FROM task
+LEFT JOIN LATERAL (
+    SELECT session.agent_id, session.user_id FROM session
+    WHERE session.id = task.session_id
+      AND session.deleted_at IS NULL
+      AND (session.source IS NULL OR session.source != 'agent')
+    LIMIT 1
+) agent ON TRUE
+WHERE task.deleted_at IS NULL
+  AND (sqlc.narg(since)::timestamptz IS NULL OR task.updated_at >= sqlc.narg(since))
+  AND (sqlc.narg(states)::text[] IS NULL OR (task.data::jsonb -> 'status' ->> 'state') = ANY(sqlc.narg(states)::text[]))
+ORDER BY task.updated_at DESC
+LIMIT sqlc.narg(row_limit);
+
 -- name: UpsertTask :exec
 WITH upserted_task AS (
 INSERT INTO task (id, data, session_id, protocol_version, created_at, updated_at)
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/url"
 
+	"github.com/kagent-dev/kagent/go/api/requestid"
 	"github.com/kagent-dev/kagent/go/core/pkg/auth"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -113,6 +114,8 @@ func A2ARequestHandler(authProvider auth.AuthProvider, agentNns types.Namespaced
 			}
 		}
 
+		requestid.SetHeader(ctx, req)
+
 		resp, err = client.Do(req)
 		if err != nil {
 			return nil, fmt.Errorf("a2aClient.httpRequestHandler: http request failed: %w", err)
@@ -0,0 +1,347 @@
+package handlers
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	a2a "github.com/a2aproject/a2a-go/v2/a2a"
+	"github.com/kagent-dev/kagent/go/api/database"
+	api "github.com/kagent-dev/kagent/go/api/httpapi"
+	"github.com/kagent-dev/kagent/go/core/internal/httpserver/errors"
+	"github.com/kagent-dev/kagent/go/core/pkg/auth"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// defaultLongRunningThreshold is how long a task may stay submitted or
+// working before HandleLongRunningTasks reports it.
+const defaultLongRunningThreshold = 15 * time.Minute
+
+// defaultAdminWindow bounds the tasks the admin views read when no ?since=
+// is given.
+const defaultAdminWindow = 7 * 24 * time.Hour
+
+// maxAdminTasks caps the number of tasks, most recently updated first, an
+// admin view reads.
+const maxAdminTasks = 10000
+
+// AdminHandler serves read-only operational views over the task store for
+// an ops dashboard.
+type AdminHandler struct {
+	*Base
+}
+
+// NewAdminHandler creates a new AdminHandler
+func NewAdminHandler(base *Base) *AdminHandler {
+	return &AdminHandler{Base: base}
+}
+
+// HandleAgentUsage handles GET /api/admin/agents/usage?since=&limit= requests,
+// listing agents by the tokens their tasks consumed, highest first. since
+// defaults to a week ago.
+func (h *AdminHandler) HandleAgentUsage(w ErrorResponseWriter, r *http.Request) {
+	log := ctrllog.FromContext(r.Context()).WithName("admin-handler").WithValues("operation", "agent-usage")
+
+	if err := Check(h.Authorizer, r, auth.Resource{Type: "Admin"}); err != nil {
+		w.RespondWithError(err)
+		return
+	}
+	since, err := parseSinceParam(r, time.Now().Add(-defaultAdminWindow))
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Invalid since timestamp", err))
+		return
+	}
+	limit := 0
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if limit, err = strconv.Atoi(limitStr); err != nil || limit < 0 {
+			w.RespondWithError(errors.NewBadRequestError("Invalid limit", fmt.Errorf("limit must be a non-negative integer, got %q", limitStr)))
+			return
+		}
+	}
+
+	tasks, agents, err := h.tasksWithAgents(r.Context(), database.TaskFilter{Since: since})
+	if err != nil {
+		w.RespondWithError(errors.NewInternalServerError("Failed to load tasks", err))
+		return
+	}
+	usage := summarizeAgentUsage(tasks, agents)
+	if limit > 0 && len(usage) > limit {
+		usage = usage[:limit]
+	}
+
+	log.Info("Successfully summarized agent usage", "agents", len(usage))
+	RespondWithJSON(w, http.StatusOK, api.NewResponse(usage, "Successfully summarized agent usage", false))
+}
+
+// HandleErrorRates handles GET /api/admin/tasks/error-rates?since=&bucket=&agent=
+// requests. bucket is "hour" or "day" (the default); since defaults to a
+// week ago.
+func (h *AdminHandler) HandleErrorRates(w ErrorResponseWriter, r *http.Request) {
+	log := ctrllog.FromContext(r.Context()).WithName("admin-handler").WithValues("operation", "error-rates")
+
+	if err := Check(h.Authorizer, r, auth.Resource{Type: "Admin"}); err != nil {
+		w.RespondWithError(err)
+		return
+	}
+	since, err := parseSinceParam(r, time.Now().Add(-defaultAdminWindow))
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Invalid since timestamp", err))
+		return
+	}
+	var bucket time.Duration
+	switch b := r.URL.Query().Get("bucket"); b {
+	case "", "day":
+		bucket = 24 * time.Hour
+	case "hour":
+		bucket = time.Hour
+	default:
+		w.RespondWithError(errors.NewBadRequestError("Invalid bucket", fmt.Errorf("bucket must be hour or day, got %q", b)))
+		return
+	}
+	agentID := r.URL.Query().Get("agent")
+
+	tasks, agents, err := h.tasksWithAgents(r.Context(), database.TaskFilter{Since: since})
+	if err != nil {
+		w.RespondWithError(errors.NewInternalServerError("Failed to load tasks", err))
+		return
+	}
+	if agentID != "" {
+		tasks = slices.DeleteFunc(tasks, func(t *a2a.Task) bool { return agents[t.ContextID] != agentID })
+	}
+	buckets := errorRateBuckets(tasks, since, bucket)
+
+	log.Info("Successfully computed error rates", "buckets", len(buckets), "agent", agentID)
+	RespondWithJSON(w, http.StatusOK, api.NewResponse(buckets, "Successfully computed error rates", false))
+}
+
+// HandlePendingApprovals handles GET /api/admin/tasks/pending-approvals?since=
+// requests, listing every task waiting for human input, oldest first. since
+// defaults to a week ago.
+func (h *AdminHandler) HandlePendingApprovals(w ErrorResponseWriter, r *http.Request) {
+	log := ctrllog.FromContext(r.Context()).WithName("admin-handler").WithValues("operation", "pending-approvals")
+
+	if err := Check(h.Authorizer, r, auth.Resource{Type: "Admin"}); err != nil {
+		w.RespondWithError(err)
+		return
+	}
+	since, err := parseSinceParam(r, time.Now().Add(-defaultAdminWindow))
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Invalid since timestamp", err))
+		return
+	}
+	tasks, agents, err := h.tasksWithAgents(r.Context(), database.TaskFilter{State: a2a.TaskStateInputRequired, Since: since})
+	if err != nil {
+		w.RespondWithError(errors.NewInternalServerError("Failed to load tasks", err))
+		return
+	}
+	summaries := summarizeTasks(tasks, agents, func(*a2a.Task) bool { return true })
+
+	log.Info("Successfully listed pending approvals", "count", len(summaries))
+	RespondWithJSON(w, http.StatusOK, api.NewResponse(summaries, "Successfully listed pending approvals", false))
+}
+
+// HandleLongRunningTasks handles GET /api/admin/tasks/long-running?older_than=&since=
+// requests, listing submitted or working tasks whose status has not changed
+// for longer than older_than (a Go duration, 15m by default), oldest first.
+// Only tasks updated since since, a week ago by default, are considered.
+func (h *AdminHandler) HandleLongRunningTasks(w ErrorResponseWriter, r *http.Request) {
+	log := ctrllog.FromContext(r.Context()).WithName("admin-handler").WithValues("operation", "long-running-tasks")
+
+	if err := Check(h.Authorizer, r, auth.Resource{Type: "Admin"}); err != nil {
+		w.RespondWithError(err)
+		return
+	}
+	threshold := defaultLongRunningThreshold
+	if s := r.URL.Query().Get("older_than"); s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil || d < 0 {
+			w.RespondWithError(errors.NewBadRequestError("Invalid older_than duration", fmt.Errorf("older_than must be a non-negative duration, got %q", s)))
+			return
+		}
+		threshold = d
+	}
+	cutoff := time.Now().Add(-threshold)
+	since, err := parseSinceParam(r, time.Now().Add(-defaultAdminWindow))
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Invalid since timestamp", err))
+		return
+	}
+
+	tasks, agents, err := h.tasksWithAgents(r.Context(), database.TaskFilter{
+		States: []a2a.TaskState{a2a.TaskStateSubmitted, a2a.TaskStateWorking},
+		Since:  since,
+	})
+	if err != nil {
+		w.RespondWithError(errors.NewInternalServerError("Failed to load tasks", err))
+		return
+	}
+	summaries := summarizeTasks(tasks, agents, func(t *a2a.Task) bool {
+		if t.Status.State != a2a.TaskStateSubmitted && t.Status.State != a2a.TaskStateWorking {
+			return false
+		}
+		return t.Status.Timestamp != nil && t.Status.Timestamp.Before(cutoff)
+	})
+
+	log.Info("Successfully listed long-running tasks", "count", len(summaries), "olderThan", threshold)
+	RespondWithJSON(w, http.StatusOK, api.NewResponse(summaries, "Successfully listed long-running tasks", false))
+}
+
+// tasksWithAgents lists the tasks matching filter, at most maxAdminTasks of
+// them, along with a map from session ID to the ID of the agent the session
+// belongs to.
+func (h *AdminHandler) tasksWithAgents(ctx context.Context, filter database.TaskFilter) ([]*a2a.Task, map[string]string, error) {
+	filter.Limit = maxAdminTasks
+	rows, err := h.DatabaseService.ListTasksWithAgents(ctx, filter)
+	if err != nil {
+		return nil, nil, err
+	}
+	tasks := make([]*a2a.Task, 0, len(rows))
+	sessionAgents := make(map[string]string)
+	for _, row := range rows {
+		tasks = append(tasks, row.Task)
+		if row.AgentID != "" {
+			sessionAgents[row.Task.ContextID] = row.AgentID
+		}
+	}
+	return tasks, sessionAgents, nil
+}
+
+// parseSinceParam reads the RFC 3339 ?since= parameter, returning def when
+// it is absent.
+func parseSinceParam(r *http.Request, def time.Time) (time.Time, error) {
+	s := r.URL.Query().Get("since")
+	if s == "" {
+		return def, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+// taskTokenUsage is the part of kagent_usage_metadata the dashboard sums.
+type taskTokenUsage struct {
+	PromptTokenCount     int64 `json:"promptTokenCount"`
+	CandidatesTokenCount int64 `json:"candidatesTokenCount"`
+	TotalTokenCount      int64 `json:"totalTokenCount"`
+}
+
+// tokenUsageFromTask decodes the token usage the agent stamped on the task's
+// final status, if any.
+func tokenUsageFromTask(task *a2a.Task) (taskTokenUsage, bool) {
+	var usage taskTokenUsage
+	raw, ok := task.Metadata["kagent_usage_metadata"]
+	if !ok {
+		return usage, false
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return usage, false
+	}
+	return usage, json.Unmarshal(data, &usage) == nil
+}
+
+// summarizeAgentUsage groups tasks by agent and sums their token usage,
+// sorted by total tokens, highest first. Tasks whose session has no known
+// agent are skipped.
+func summarizeAgentUsage(tasks []*a2a.Task, sessionAgents map[string]string) []api.AdminAgentUsage {
+	byAgent := make(map[string]*api.AdminAgentUsage)
+	for _, task := range tasks {
+		agentID, ok := sessionAgents[task.ContextID]
+		if !ok {
+			continue
+		}
+		u := byAgent[agentID]
+		if u == nil {
+			u = &api.AdminAgentUsage{AgentID: agentID}
+			byAgent[agentID] = u
+		}
+		u.Tasks++
+		if task.Status.State == a2a.TaskStateFailed {
+			u.FailedTasks++
+		}
+		if usage, ok := tokenUsageFromTask(task); ok {
+			u.PromptTokens += usage.PromptTokenCount
+			u.CandidatesTokens += usage.CandidatesTokenCount
+			u.TotalTokens += usage.TotalTokenCount
+		}
+	}
+	out := make([]api.AdminAgentUsage, 0, len(byAgent))
+	for _, u := range byAgent {
+		out = append(out, *u)
+	}
+	slices.SortFunc(out, func(a, b api.AdminAgentUsage) int {
+		if c := cmp.Compare(b.TotalTokens, a.TotalTokens); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.AgentID, b.AgentID)
+	})
+	return out
+}
+
+// errorRateBuckets counts the tasks that reached a terminal state in each
+// bucket-sized window from since onwards. A task's finish time is the
+// timestamp of its final status; tasks without one are skipped.
+func errorRateBuckets(tasks []*a2a.Task, since time.Time, bucket time.Duration) []api.AdminErrorRateBucket {
+	start := since.Truncate(bucket)
+	byStart := make(map[time.Time]*api.AdminErrorRateBucket)
+	for _, task := range tasks {
+		switch task.Status.State {
+		case a2a.TaskStateCompleted, a2a.TaskStateFailed, a2a.TaskStateCanceled, a2a.TaskStateRejected:
+		default:
+			continue
+		}
+		ts := task.Status.Timestamp
+		if ts == nil || ts.Before(start) {
+			continue
+		}
+		key := ts.Truncate(bucket).UTC()
+		b := byStart[key]
+		if b == nil {
+			b = &api.AdminErrorRateBucket{Start: key}
+			byStart[key] = b
+		}
+		b.Finished++
+		if task.Status.State == a2a.TaskStateFailed {
+			b.Failed++
+		}
+	}
+	out := make([]api.AdminErrorRateBucket, 0, len(byStart))
+	for _, b := range byStart {
+		b.ErrorRate = float64(b.Failed) / float64(b.Finished)
+		out = append(out, *b)
+	}
+	slices.SortFunc(out, func(a, b api.AdminErrorRateBucket) int { return a.Start.Compare(b.Start) })
+	return out
+}
+
+// summarizeTasks returns the tasks selected by keep, oldest status first.
+func summarizeTasks(tasks []*a2a.Task, sessionAgents map[string]string, keep func(*a2a.Task) bool) []api.AdminTaskSummary {
+	out := make([]api.AdminTaskSummary, 0)
+	for _, task := range tasks {
+		if !keep(task) {
+			continue
+		}
+		out = append(out, api.AdminTaskSummary{
+			TaskID:    string(task.ID),
+			SessionID: task.ContextID,
+			AgentID:   sessionAgents[task.ContextID],
+			State:     string(task.Status.State),
+			UpdatedAt: task.Status.Timestamp,
+		})
+	}
+	slices.SortStableFunc(out, func(a, b api.AdminTaskSummary) int {
+		switch {
+		case a.UpdatedAt == nil && b.UpdatedAt == nil:
+			return 0
+		case a.UpdatedAt == nil:
+			return 1
+		case b.UpdatedAt == nil:
+			return -1
+		}
+		return a.UpdatedAt.Compare(*b.UpdatedAt)
+	})
+	return out
+}
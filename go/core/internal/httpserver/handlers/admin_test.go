@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	a2a "github.com/a2aproject/a2a-go/v2/a2a"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func adminTestTask(id, session string, state a2a.TaskState, at time.Time, totalTokens int) *a2a.Task {
+	task := &a2a.Task{
+		ID:        a2a.TaskID(id),
+		ContextID: session,
+		Status:    a2a.TaskStatus{State: state, Timestamp: &at},
+	}
+	if totalTokens > 0 {
+		task.Metadata = map[string]any{
+			"kagent_usage_metadata": map[string]any{
+				"promptTokenCount":     float64(totalTokens - 1),
+				"candidatesTokenCount": float64(1),
+				"totalTokenCount":      float64(totalTokens),
+			},
+		}
+	}
+	return task
+}
+
+func TestSummarizeAgentUsage(t *testing.T) {
+	now := time.Now()
+	tasks := []*a2a.Task{
+		adminTestTask("t1", "s1", a2a.TaskStateCompleted, now, 100),
+		adminTestTask("t2", "s1", a2a.TaskStateFailed, now, 50),
+		adminTestTask("t3", "s2", a2a.TaskStateCompleted, now, 500),
+		adminTestTask("t4", "orphan", a2a.TaskStateCompleted, now, 1000),
+	}
+	agents := map[string]string{"s1": "kagent__NS__a", "s2": "kagent__NS__b"}
+
+	usage := summarizeAgentUsage(tasks, agents)
+	require.Len(t, usage, 2)
+	assert.Equal(t, "kagent__NS__b", usage[0].AgentID)
+	assert.Equal(t, int64(500), usage[0].TotalTokens)
+	assert.Equal(t, "kagent__NS__a", usage[1].AgentID)
+	assert.Equal(t, 2, usage[1].Tasks)
+	assert.Equal(t, 1, usage[1].FailedTasks)
+	assert.Equal(t, int64(148), usage[1].PromptTokens)
+	assert.Equal(t, int64(150), usage[1].TotalTokens)
+}
+
+func TestErrorRateBuckets(t *testing.T) {
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	tasks := []*a2a.Task{
+		adminTestTask("t1", "s", a2a.TaskStateCompleted, day.Add(time.Hour), 0),
+		adminTestTask("t2", "s", a2a.TaskStateFailed, day.Add(2*time.Hour), 0),
+		adminTestTask("t3", "s", a2a.TaskStateFailed, day.Add(25*time.Hour), 0),
+		adminTestTask("t4", "s", a2a.TaskStateWorking, day.Add(time.Hour), 0),
+		adminTestTask("t5", "s", a2a.TaskStateFailed, day.Add(-time.Hour), 0),
+	}
+
+	buckets := errorRateBuckets(tasks, day, 24*time.Hour)
+	require.Len(t, buckets, 2)
+	assert.Equal(t, day, buckets[0].Start)
+	assert.Equal(t, 2, buckets[0].Finished)
+	assert.Equal(t, 1, buckets[0].Failed)
+	assert.InDelta(t, 0.5, buckets[0].ErrorRate, 1e-9)
+	assert.Equal(t, day.Add(24*time.Hour), buckets[1].Start)
+	assert.InDelta(t, 1.0, buckets[1].ErrorRate, 1e-9)
+}
+
+func TestSummarizeTasks(t *testing.T) {
+	now := time.Now()
+	tasks := []*a2a.Task{
+		adminTestTask("newer", "s1", a2a.TaskStateWorking, now.Add(-time.Hour), 0),
+		adminTestTask("older", "s2", a2a.TaskStateWorking, now.Add(-2*time.Hour), 0),
+		adminTestTask("done", "s1", a2a.TaskStateCompleted, now.Add(-3*time.Hour), 0),
+	}
+	agents := map[string]string{"s1": "kagent__NS__a"}
+
+	summaries := summarizeTasks(tasks, agents, func(t *a2a.Task) bool { return t.Status.State == a2a.TaskStateWorking })
+	require.Len(t, summaries, 2)
+	assert.Equal(t, "older", summaries[0].TaskID)
+	assert.Empty(t, summaries[0].AgentID)
+	assert.Equal(t, "newer", summaries[1].TaskID)
+	assert.Equal(t, "kagent__NS__a", summaries[1].AgentID)
+}
@@ -0,0 +1,459 @@
+package handlers
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	a2a "github.com/a2aproject/a2a-go/v2/a2a"
+	"github.com/kagent-dev/kagent/go/api/database"
+	api "github.com/kagent-dev/kagent/go/api/httpapi"
+	"github.com/kagent-dev/kagent/go/api/v1alpha2"
+	authimpl "github.com/kagent-dev/kagent/go/core/internal/httpserver/auth"
+	"github.com/kagent-dev/kagent/go/core/internal/httpserver/errors"
+	"github.com/kagent-dev/kagent/go/core/internal/utils"
+	"github.com/kagent-dev/kagent/go/core/pkg/auth"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// requestConfirmationName is the ADK function call that wraps a tool call
+// requiring confirmation; its args carry the original call.
+const requestConfirmationName = "adk_request_confirmation"
+
+// AgentMessenger sends A2A messages to an agent by namespace and name.
+type AgentMessenger interface {
+	SendMessage(ctx context.Context, namespace, name string, req *a2a.SendMessageRequest) (a2a.SendMessageResult, error)
+}
+
+// ApprovalsHandler serves the approval inbox: the tool calls waiting for the
+// authenticated user's decision across all of their sessions.
+type ApprovalsHandler struct {
+	*Base
+	agents AgentMessenger
+}
+
+// NewApprovalsHandler creates a new ApprovalsHandler
+func NewApprovalsHandler(base *Base, agents AgentMessenger) *ApprovalsHandler {
+	return &ApprovalsHandler{Base: base, agents: agents}
+}
+
+// HandleListApprovals handles GET /api/approvals requests, listing the tasks
+// paused on tool approvals in the caller's sessions and those assigned to
+// the caller's groups, oldest first.
+func (h *ApprovalsHandler) HandleListApprovals(w ErrorResponseWriter, r *http.Request) {
+	log := ctrllog.FromContext(r.Context()).WithName("approvals-handler").WithValues("operation", "list")
+
+	principal, err := GetPrincipal(r)
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Failed to get user ID", err))
+		return
+	}
+	userID := principal.User.ID
+	log = log.WithValues("userID", userID)
+
+	tasks, err := h.DatabaseService.ListTasks(r.Context(), database.TaskFilter{UserID: userID, State: a2a.TaskStateInputRequired})
+	if err != nil {
+		w.RespondWithError(errors.NewInternalServerError("Failed to list pending approvals", err))
+		return
+	}
+
+	// One lookup for the agents of all of the caller's sessions.
+	sessions, err := h.DatabaseService.ListSessions(r.Context(), userID)
+	if err != nil {
+		w.RespondWithError(errors.NewInternalServerError("Failed to list sessions", err))
+		return
+	}
+	sessionAgents := make(map[string]string, len(sessions))
+	for _, session := range sessions {
+		if session.AgentID != nil {
+			sessionAgents[session.ID] = *session.AgentID
+		}
+	}
+
+	approvals := make([]api.PendingApproval, 0, len(tasks))
+	for i := len(tasks) - 1; i >= 0; i-- {
+		task := tasks[i]
+		toolCalls := pendingToolCalls(task)
+		if len(toolCalls) == 0 {
+			continue
+		}
+		approval := api.PendingApproval{
+			TaskID:      string(task.ID),
+			SessionID:   task.ContextID,
+			RequestedAt: task.Status.Timestamp,
+			ToolCalls:   toolCalls,
+		}
+		if agentID, ok := sessionAgents[task.ContextID]; ok {
+			approval.AgentRef = utils.ConvertToKubernetesIdentifier(agentID)
+		}
+		approvals = append(approvals, approval)
+	}
+
+	assigned, err := h.assignedApprovals(r.Context(), principal)
+	if err != nil {
+		w.RespondWithError(errors.NewInternalServerError("Failed to list assigned approvals", err))
+		return
+	}
+	if len(assigned) > 0 {
+		approvals = append(approvals, assigned...)
+		slices.SortStableFunc(approvals, func(a, b api.PendingApproval) int {
+			return compareRequestedAt(a.RequestedAt, b.RequestedAt)
+		})
+	}
+
+	log.Info("Successfully listed pending approvals", "count", len(approvals))
+	RespondWithJSON(w, http.StatusOK, api.NewResponse(approvals, "Successfully listed pending approvals", false))
+}
+
+// HandleDecideApproval handles POST /api/approvals/{task_id} requests. The
+// decision is sent to the agent as the same HITL decision message the UI
+// sends, resuming the paused task.
+func (h *ApprovalsHandler) HandleDecideApproval(w ErrorResponseWriter, r *http.Request) {
+	log := ctrllog.FromContext(r.Context()).WithName("approvals-handler").WithValues("operation", "decide")
+
+	principal, err := GetPrincipal(r)
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Failed to get user ID", err))
+		return
+	}
+	taskID, err := GetPathParam(r, "task_id")
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Failed to get task ID from path", err))
+		return
+	}
+	log = log.WithValues("userID", principal.User.ID, "task_id", taskID)
+
+	var req api.ApprovalDecisionRequest
+	if err := DecodeJSONBody(r, &req); err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Invalid request body", err))
+		return
+	}
+
+	resp, err := h.Decide(r.Context(), principal, taskID, req)
+	if err != nil {
+		w.RespondWithError(err)
+		return
+	}
+	log.Info("Successfully sent approval decision", "decision", req.Decision, "state", resp.State)
+	RespondWithJSON(w, http.StatusOK, api.NewResponse(resp, "Successfully sent approval decision", false))
+}
+
+// approvalExpiredReason is the rejection reason sent for expired approvals.
+const approvalExpiredReason = "approval request expired"
+
+// ExpireApprovals rejects the approvals that have waited longer than ttl,
+// sending each agent the same decision message a user's rejection would so
+// the paused task resumes. It returns the number of approvals rejected.
+func ExpireApprovals(ctx context.Context, db database.Client, agents AgentMessenger, ttl time.Duration, now time.Time) (int, error) {
+	tasks, err := db.ListTasksWithAgents(ctx, database.TaskFilter{State: a2a.TaskStateInputRequired})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list pending approvals: %w", err)
+	}
+	cutoff := now.Add(-ttl)
+	expired := 0
+	var errs []error
+	for _, row := range tasks {
+		task := row.Task
+		if task.Status.Timestamp == nil || !task.Status.Timestamp.Before(cutoff) || len(pendingToolCalls(task)) == 0 {
+			continue
+		}
+		namespace, name, ok := strings.Cut(utils.ConvertToKubernetesIdentifier(row.AgentID), "/")
+		if !ok {
+			continue
+		}
+		msg := decisionMessage(task, api.ApprovalDecisionRequest{Decision: "reject", Reason: approvalExpiredReason}, "")
+		// The sweeper has no request: send the rejection as the session's
+		// user, whom the agent checks the session against.
+		sendCtx := auth.AuthSessionTo(ctx, &authimpl.SimpleSession{P: auth.Principal{User: auth.User{ID: row.UserID}}})
+		if _, err := agents.SendMessage(sendCtx, namespace, name, &a2a.SendMessageRequest{Message: msg}); err != nil {
+			errs = append(errs, fmt.Errorf("task %s: %w", task.ID, err))
+			continue
+		}
+		expired++
+	}
+	return expired, stderrors.Join(errs...)
+}
+
+// Decide sends principal's decision on the task paused on approvals, after
+// checking that principal may update the task. Errors are *errors.APIError
+// values. It backs both the inbox API and Slack buttons.
+func (h *ApprovalsHandler) Decide(ctx context.Context, principal auth.Principal, taskID string, req api.ApprovalDecisionRequest) (*api.ApprovalDecisionResponse, error) {
+	if req.Decision != "approve" && req.Decision != "reject" {
+		return nil, errors.NewValidationError("Invalid decision", fmt.Errorf("decision must be approve or reject, got %q", req.Decision))
+	}
+	if h.agents == nil {
+		return nil, errors.NewNotImplementedError("Approval decisions are not available", fmt.Errorf("no agent A2A clients configured"))
+	}
+
+	if err := h.Authorizer.Check(ctx, principal, auth.VerbUpdate, auth.Resource{Type: "Task", Name: taskID}); err != nil {
+		return nil, errors.NewForbiddenError("Not authorized", err)
+	}
+
+	task, err := h.DatabaseService.GetTask(ctx, taskID)
+	if err != nil {
+		return nil, errors.NewNotFoundError("Task not found", err)
+	}
+	owner, agentID := principal.User.ID, ""
+	if session, err := h.DatabaseService.GetSession(ctx, task.ContextID, principal.User.ID); err == nil {
+		if task.Status.State != a2a.TaskStateInputRequired {
+			return nil, errors.NewConflictError("Task is not waiting for approval", fmt.Errorf("task %s is in state %s", taskID, task.Status.State))
+		}
+		if session.AgentID == nil || *session.AgentID == "" {
+			return nil, errors.NewBadRequestError("Session has no agent", fmt.Errorf("session %s has no agent", session.ID))
+		}
+		agentID = *session.AgentID
+	} else {
+		// Tasks in other users' sessions are reported as missing unless
+		// their approval is assigned to one of principal's groups.
+		row, err := h.assignedApproval(ctx, principal, task.ID)
+		if err != nil {
+			return nil, err
+		}
+		owner, agentID = row.UserID, row.AgentID
+	}
+	namespace, name, ok := strings.Cut(utils.ConvertToKubernetesIdentifier(agentID), "/")
+	if !ok {
+		return nil, errors.NewInternalServerError("Invalid agent reference", fmt.Errorf("agent ID %q has no namespace", agentID))
+	}
+
+	// The agent checks the session against the user the A2A client forwards.
+	// A group member decides on behalf of the session's user, and Slack
+	// interactions bypass the auth middleware, so neither has the session
+	// to forward in ctx.
+	decidedBy := ""
+	if owner != principal.User.ID {
+		decidedBy = principal.User.ID
+		ctx = auth.AuthSessionTo(ctx, &authimpl.SimpleSession{P: auth.Principal{User: auth.User{ID: owner}}})
+	} else if _, ok := auth.AuthSessionFrom(ctx); !ok {
+		ctx = auth.AuthSessionTo(ctx, &authimpl.SimpleSession{P: principal})
+	}
+	result, err := h.agents.SendMessage(ctx, namespace, name, &a2a.SendMessageRequest{Message: decisionMessage(task, req, decidedBy)})
+	if err != nil {
+		return nil, errors.NewInternalServerError("Failed to send decision to agent", err)
+	}
+
+	resp := &api.ApprovalDecisionResponse{TaskID: taskID}
+	if t, ok := result.(*a2a.Task); ok {
+		resp.State = string(t.Status.State)
+	}
+	return resp, nil
+}
+
+// decisionMessage builds the HITL decision message for task: a decision
+// DataPart followed by a human-readable text part. decidedBy names the
+// approver when they are not the session's user.
+func decisionMessage(task *a2a.Task, req api.ApprovalDecisionRequest, decidedBy string) *a2a.Message {
+	data := map[string]any{"decision_type": req.Decision}
+	text := "Approved"
+	if req.Decision == "reject" {
+		text = "Rejected"
+	}
+	if decidedBy != "" {
+		text += " by " + decidedBy
+	}
+	if req.Decision == "reject" && req.Reason != "" {
+		data["rejection_reason"] = req.Reason
+		text += ": " + req.Reason
+	}
+	msg := a2a.NewMessage(a2a.MessageRoleUser, a2a.NewDataPart(data), a2a.NewTextPart(text))
+	msg.TaskID = task.ID
+	msg.ContextID = task.ContextID
+	return msg
+}
+
+// ApproverGroupsAnnotation assigns the tool approvals of an agent's tasks to
+// groups, as a comma-separated list. Members of the groups, read from the
+// groups claim of their token, find the approvals in their inbox and may
+// decide them on behalf of the session's user.
+const ApproverGroupsAnnotation = "kagent.dev/approver-groups"
+
+// assignedApprovals lists the approvals in other users' sessions assigned to
+// principal's groups.
+func (h *ApprovalsHandler) assignedApprovals(ctx context.Context, principal auth.Principal) ([]api.PendingApproval, error) {
+	groups := principalGroups(principal)
+	if len(groups) == 0 {
+		return nil, nil
+	}
+	rows, err := h.DatabaseService.ListTasksWithAgents(ctx, database.TaskFilter{State: a2a.TaskStateInputRequired})
+	if err != nil {
+		return nil, err
+	}
+	agentGroups := map[string][]string{}
+	var approvals []api.PendingApproval
+	for i := len(rows) - 1; i >= 0; i-- {
+		row := rows[i]
+		if row.UserID == "" || row.UserID == principal.User.ID {
+			continue
+		}
+		toolCalls := pendingToolCalls(row.Task)
+		if len(toolCalls) == 0 {
+			continue
+		}
+		assigned, err := h.assignedGroups(ctx, row.AgentID, groups, agentGroups)
+		if err != nil {
+			return nil, err
+		}
+		if len(assigned) == 0 {
+			continue
+		}
+		approvals = append(approvals, api.PendingApproval{
+			TaskID:         string(row.Task.ID),
+			SessionID:      row.Task.ContextID,
+			AgentRef:       utils.ConvertToKubernetesIdentifier(row.AgentID),
+			RequestedAt:    row.Task.Status.Timestamp,
+			ToolCalls:      toolCalls,
+			AssignedGroups: assigned,
+		})
+	}
+	return approvals, nil
+}
+
+// assignedApproval returns the task waiting for approval with taskID when it
+// is assigned to one of principal's groups. Errors are *errors.APIError
+// values.
+func (h *ApprovalsHandler) assignedApproval(ctx context.Context, principal auth.Principal, taskID a2a.TaskID) (*database.TaskWithAgent, error) {
+	notFound := errors.NewNotFoundError("Task not found", fmt.Errorf("task %s is not in a session of %s or assigned to their groups", taskID, principal.User.ID))
+	groups := principalGroups(principal)
+	if len(groups) == 0 {
+		return nil, notFound
+	}
+	rows, err := h.DatabaseService.ListTasksWithAgents(ctx, database.TaskFilter{State: a2a.TaskStateInputRequired})
+	if err != nil {
+		return nil, errors.NewInternalServerError("Failed to list pending approvals", err)
+	}
+	for i := range rows {
+		row := &rows[i]
+		if row.Task.ID != taskID || row.UserID == "" {
+			continue
+		}
+		assigned, err := h.assignedGroups(ctx, row.AgentID, groups, nil)
+		if err != nil {
+			return nil, errors.NewInternalServerError("Failed to get agent", err)
+		}
+		if len(assigned) > 0 {
+			return row, nil
+		}
+	}
+	return nil, notFound
+}
+
+// assignedGroups returns the groups among groups that the agent with
+// agentID assigns its approvals to. cache, when not nil, holds the groups of
+// the agents already looked up.
+func (h *ApprovalsHandler) assignedGroups(ctx context.Context, agentID string, groups []string, cache map[string][]string) ([]string, error) {
+	if agentID == "" || h.KubeClient == nil {
+		return nil, nil
+	}
+	approvers, ok := cache[agentID]
+	if !ok {
+		ref, err := utils.ParseRefString(utils.ConvertToKubernetesIdentifier(agentID), "")
+		if err != nil {
+			return nil, nil
+		}
+		agent := &v1alpha2.Agent{}
+		if err := h.KubeClient.Get(ctx, ref, agent); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, err
+			}
+		}
+		for group := range strings.SplitSeq(agent.Annotations[ApproverGroupsAnnotation], ",") {
+			if group = strings.TrimSpace(group); group != "" {
+				approvers = append(approvers, group)
+			}
+		}
+		if cache != nil {
+			cache[agentID] = approvers
+		}
+	}
+	var assigned []string
+	for _, group := range approvers {
+		if slices.Contains(groups, group) {
+			assigned = append(assigned, group)
+		}
+	}
+	return assigned, nil
+}
+
+// principalGroups returns the groups claim of principal's token.
+func principalGroups(principal auth.Principal) []string {
+	switch claim := principal.Claims["groups"].(type) {
+	case []string:
+		return claim
+	case []any:
+		groups := make([]string, 0, len(claim))
+		for _, g := range claim {
+			if group, ok := g.(string); ok {
+				groups = append(groups, group)
+			}
+		}
+		return groups
+	}
+	return nil
+}
+
+// compareRequestedAt orders approvals oldest first, those without a time
+// first.
+func compareRequestedAt(a, b *time.Time) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+	return a.Compare(*b)
+}
+
+// pendingToolCalls extracts the long-running function calls from the status
+// message of an input-required task, unwrapping adk_request_confirmation
+// calls to the tool call they guard.
+func pendingToolCalls(task *a2a.Task) []api.PendingToolCall {
+	if task.Status.Message == nil {
+		return nil
+	}
+	var calls []api.PendingToolCall
+	for _, part := range task.Status.Message.Parts {
+		if part == nil || !isLongRunningFunctionCall(part.Metadata) {
+			continue
+		}
+		data, _ := any(part.Data()).(map[string]any)
+		if data == nil {
+			continue
+		}
+		call := api.PendingToolCall{}
+		call.ID, _ = data["id"].(string)
+		call.Name, _ = data["name"].(string)
+		call.Args, _ = data["args"].(map[string]any)
+		if call.Name == requestConfirmationName {
+			if original, ok := call.Args["originalFunctionCall"].(map[string]any); ok {
+				call.ID, _ = original["id"].(string)
+				call.Name, _ = original["name"].(string)
+				call.Args, _ = original["args"].(map[string]any)
+			}
+		}
+		if call.Name != "" {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// isLongRunningFunctionCall reports whether part metadata marks a
+// long-running function call.
+func isLongRunningFunctionCall(metadata map[string]any) bool {
+	return partMetadataValue(metadata, "type") == "function_call" && partMetadataValue(metadata, "is_long_running") == true
+}
+
+// partMetadataValue reads adk_<key>, falling back to kagent_<key>.
+func partMetadataValue(metadata map[string]any, key string) any {
+	if v, ok := metadata["adk_"+key]; ok {
+		return v
+	}
+	return metadata["kagent_"+key]
+}
@@ -0,0 +1,145 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	a2a "github.com/a2aproject/a2a-go/v2/a2a"
+	a2aclient "github.com/a2aproject/a2a-go/v2/a2aclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kagent-dev/kagent/go/api/database"
+	api "github.com/kagent-dev/kagent/go/api/httpapi"
+	"github.com/kagent-dev/kagent/go/api/v1alpha2"
+	corea2a "github.com/kagent-dev/kagent/go/core/internal/a2a"
+	authimpl "github.com/kagent-dev/kagent/go/core/internal/httpserver/auth"
+	"github.com/kagent-dev/kagent/go/core/internal/httpserver/errors"
+	"github.com/kagent-dev/kagent/go/core/internal/httpserver/handlers"
+	"github.com/kagent-dev/kagent/go/core/pkg/auth"
+)
+
+type sentMessage struct {
+	namespace, name string
+	msg             *a2a.Message
+	// userID is the X-User-Id the controller's A2A client sends the agent.
+	userID string
+}
+
+type recordingMessenger struct {
+	sent []sentMessage
+}
+
+func (m *recordingMessenger) SendMessage(ctx context.Context, namespace, name string, req *a2a.SendMessageRequest) (a2a.SendMessageResult, error) {
+	upstream := &a2aclient.Request{BaseURL: "http://agent:8080", ServiceParams: a2aclient.ServiceParams{}}
+	interceptor := corea2a.NewUpstreamAuthInterceptor(&authimpl.UnsecureAuthenticator{}, types.NamespacedName{Namespace: namespace, Name: name})
+	if _, _, err := interceptor.Before(ctx, upstream); err != nil {
+		return nil, err
+	}
+	sent := sentMessage{namespace: namespace, name: name, msg: req.Message}
+	if v := upstream.ServiceParams.Get("X-User-Id"); len(v) > 0 {
+		sent.userID = v[0]
+	}
+	m.sent = append(m.sent, sent)
+	return &a2a.Task{}, nil
+}
+
+// waitingTask returns a task of session-1 paused on a tool approval since
+// since.
+func waitingTask(id string, since time.Time) *a2a.Task {
+	call := a2a.NewDataPart(map[string]any{"id": "call-" + id, "name": "k8s_delete_resource"})
+	call.Metadata = map[string]any{"adk_type": "function_call", "adk_is_long_running": true}
+	return &a2a.Task{
+		ID:        a2a.TaskID(id),
+		ContextID: "session-1",
+		Status: a2a.TaskStatus{
+			State:     a2a.TaskStateInputRequired,
+			Timestamp: &since,
+			Message:   a2a.NewMessage(a2a.MessageRoleAgent, call),
+		},
+	}
+}
+
+func TestExpireApprovals(t *testing.T) {
+	ctx := context.Background()
+	dbClient := setupTestDBClient(t)
+	require.NoError(t, dbClient.StoreSession(ctx, &database.Session{ID: "session-1", UserID: "alice", AgentID: new("kagent__NS__approver")}))
+
+	now := time.Now().UTC()
+	for _, task := range []*a2a.Task{
+		waitingTask("stale", now.Add(-2*time.Hour)),
+		waitingTask("fresh", now.Add(-time.Minute)),
+	} {
+		require.NoError(t, dbClient.StoreTask(ctx, task))
+	}
+
+	messenger := &recordingMessenger{}
+	expired, err := handlers.ExpireApprovals(ctx, dbClient, messenger, time.Hour, now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, expired)
+	require.Len(t, messenger.sent, 1)
+	sent := messenger.sent[0]
+	assert.Equal(t, "kagent", sent.namespace)
+	assert.Equal(t, "approver", sent.name)
+	assert.Equal(t, a2a.TaskID("stale"), sent.msg.TaskID)
+	assert.Equal(t, "alice", sent.userID)
+	assert.Equal(t, map[string]any{"decision_type": "reject", "rejection_reason": "approval request expired"}, sent.msg.Parts[0].Data())
+}
+
+func TestApprovalsAssignedToGroups(t *testing.T) {
+	ctx := context.Background()
+	dbClient := setupTestDBClient(t)
+	require.NoError(t, dbClient.StoreSession(ctx, &database.Session{ID: "session-1", UserID: "alice", AgentID: new("kagent__NS__approver")}))
+	require.NoError(t, dbClient.StoreTask(ctx, waitingTask("task-1", time.Now().UTC())))
+
+	kubeClient := fake.NewClientBuilder().WithScheme(setupScheme()).WithObjects(&v1alpha2.Agent{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "approver",
+			Namespace:   "kagent",
+			Annotations: map[string]string{handlers.ApproverGroupsAnnotation: "sre, platform"},
+		},
+	}).Build()
+	messenger := &recordingMessenger{}
+	approvals := handlers.NewApprovalsHandler(&handlers.Base{KubeClient: kubeClient, DatabaseService: dbClient, Authorizer: &auth.NoopAuthorizer{}}, messenger)
+	member := func(id string, groups ...any) auth.Principal {
+		return auth.Principal{User: auth.User{ID: id}, Claims: map[string]any{"groups": groups}}
+	}
+	list := func(principal auth.Principal) []api.PendingApproval {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/api/approvals", nil)
+		req = req.WithContext(auth.AuthSessionTo(req.Context(), &authimpl.SimpleSession{P: principal}))
+		rec := newMockErrorResponseWriter()
+		approvals.HandleListApprovals(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+		var response api.StandardResponse[[]api.PendingApproval]
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		return response.Data
+	}
+
+	got := list(member("bob", "sre"))
+	require.Len(t, got, 1)
+	assert.Equal(t, "task-1", got[0].TaskID)
+	assert.Equal(t, "kagent/approver", got[0].AgentRef)
+	assert.Equal(t, []string{"sre"}, got[0].AssignedGroups)
+	assert.Empty(t, list(member("carol", "finance")))
+
+	decision := api.ApprovalDecisionRequest{Decision: "approve"}
+	_, err := approvals.Decide(ctx, member("carol", "finance"), "task-1", decision)
+	var apiErr *errors.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.Code)
+
+	// A group member decides on behalf of the session's user.
+	_, err = approvals.Decide(ctx, member("bob", "platform"), "task-1", decision)
+	require.NoError(t, err)
+	require.Len(t, messenger.sent, 1)
+	assert.Equal(t, "alice", messenger.sent[0].userID)
+	assert.Equal(t, "Approved by bob", messenger.sent[0].msg.Parts[1].Text())
+}
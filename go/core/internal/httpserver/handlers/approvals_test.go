@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"testing"
+
+	a2a "github.com/a2aproject/a2a-go/v2/a2a"
+	api "github.com/kagent-dev/kagent/go/api/httpapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPendingToolCalls(t *testing.T) {
+	confirmation := a2a.NewDataPart(map[string]any{
+		"id":   "confirm-1",
+		"name": "adk_request_confirmation",
+		"args": map[string]any{
+			"originalFunctionCall": map[string]any{
+				"id":   "call-1",
+				"name": "k8s_delete_resource",
+				"args": map[string]any{"name": "nginx"},
+			},
+		},
+	})
+	confirmation.Metadata = map[string]any{"adk_type": "function_call", "adk_is_long_running": true}
+
+	askUser := a2a.NewDataPart(map[string]any{"id": "call-2", "name": "ask_user"})
+	askUser.Metadata = map[string]any{"kagent_type": "function_call", "kagent_is_long_running": true}
+
+	regularCall := a2a.NewDataPart(map[string]any{"id": "call-3", "name": "k8s_get_resources"})
+	regularCall.Metadata = map[string]any{"adk_type": "function_call"}
+
+	task := &a2a.Task{
+		ID: "task-1",
+		Status: a2a.TaskStatus{
+			State:   a2a.TaskStateInputRequired,
+			Message: a2a.NewMessage(a2a.MessageRoleAgent, a2a.NewTextPart("waiting"), confirmation, askUser, regularCall),
+		},
+	}
+
+	calls := pendingToolCalls(task)
+	require.Len(t, calls, 2)
+	assert.Equal(t, api.PendingToolCall{ID: "call-1", Name: "k8s_delete_resource", Args: map[string]any{"name": "nginx"}}, calls[0])
+	assert.Equal(t, api.PendingToolCall{ID: "call-2", Name: "ask_user"}, calls[1])
+
+	assert.Empty(t, pendingToolCalls(&a2a.Task{ID: "task-2"}))
+}
+
+func TestDecisionMessage(t *testing.T) {
+	task := &a2a.Task{ID: "task-1", ContextID: "session-1"}
+
+	msg := decisionMessage(task, api.ApprovalDecisionRequest{Decision: "reject", Reason: "too risky"}, "")
+	assert.Equal(t, a2a.TaskID("task-1"), msg.TaskID)
+	assert.Equal(t, "session-1", msg.ContextID)
+	assert.Equal(t, a2a.MessageRoleUser, msg.Role)
+	require.Len(t, msg.Parts, 2)
+	assert.Equal(t, map[string]any{"decision_type": "reject", "rejection_reason": "too risky"}, msg.Parts[0].Data())
+	assert.Equal(t, "Rejected: too risky", msg.Parts[1].Text())
+
+	msg = decisionMessage(task, api.ApprovalDecisionRequest{Decision: "approve", Reason: "ignored"}, "")
+	assert.Equal(t, map[string]any{"decision_type": "approve"}, msg.Parts[0].Data())
+	assert.Equal(t, "Approved", msg.Parts[1].Text())
+
+	msg = decisionMessage(task, api.ApprovalDecisionRequest{Decision: "reject", Reason: "too risky"}, "bob")
+	assert.Equal(t, "Rejected by bob: too risky", msg.Parts[1].Text())
+}
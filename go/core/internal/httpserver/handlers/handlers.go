@@ -6,6 +6,7 @@ import (
 
 	"github.com/kagent-dev/kagent/go/api/database"
 	"github.com/kagent-dev/kagent/go/core/internal/controller/reconciler"
+	"github.com/kagent-dev/kagent/go/core/internal/notifications"
 	"github.com/kagent-dev/kagent/go/core/pkg/auth"
 	"github.com/kagent-dev/kagent/go/core/pkg/sandboxbackend"
 	"github.com/kagent-dev/kagent/go/core/pkg/sandboxbackend/substrate"
@@ -30,10 +31,14 @@ type Handlers struct {
 	ToolServers         *ToolServersHandler
 	ToolServerTypes     *ToolServerTypesHandler
 	Memory              *MemoryHandler
+	Knowledge           *KnowledgeHandler
 	Feedback            *FeedbackHandler
 	Namespaces          *NamespacesHandler
 	PromptTemplates     *PromptTemplatesHandler
 	Tasks               *TasksHandler
+	Admin               *AdminHandler
+	Approvals           *ApprovalsHandler
+	Slack               *SlackHandler
 	Checkpoints         *CheckpointsHandler
 	CrewAI              *CrewAIHandler
 	CurrentUser         *CurrentUserHandler
@@ -67,6 +72,10 @@ func NewHandlers(
 	mcpEgressPlaintext bool,
 	substrateSandboxActorBackend *substrate.SandboxAgentActorBackend,
 	agentHarnessSessionActorBackend *substrate.AgentHarnessSessionActorBackend,
+	agentMessenger AgentMessenger,
+	notifier *notifications.Dispatcher,
+	slackSigningSecret string,
+	slackUsers map[string]string,
 ) *Handlers {
 	base := &Base{
 		KubeClient:         kubeClient,
@@ -79,6 +88,8 @@ func NewHandlers(
 		MCPEgressPlaintext: mcpEgressPlaintext,
 	}
 
+	approvals := NewApprovalsHandler(base, agentMessenger)
+
 	return &Handlers{
 		KubeClient:               kubeClient,
 		AgentHarnessGateway:      agentHarnessGateway,
@@ -93,10 +104,14 @@ func NewHandlers(
 		ToolServers:              NewToolServersHandler(base),
 		ToolServerTypes:          NewToolServerTypesHandler(base),
 		Memory:                   NewMemoryHandler(base),
+		Knowledge:                NewKnowledgeHandler(base),
 		Feedback:                 NewFeedbackHandler(base),
 		Namespaces:               NewNamespacesHandler(base),
 		PromptTemplates:          NewPromptTemplatesHandler(base),
-		Tasks:                    NewTasksHandler(base),
+		Tasks:                    NewTasksHandler(base, notifier),
+		Admin:                    NewAdminHandler(base),
+		Approvals:                approvals,
+		Slack:                    NewSlackHandler(approvals, slackSigningSecret, slackUsers),
 		Checkpoints:              NewCheckpointsHandler(base),
 		CrewAI:                   NewCrewAIHandler(base),
 		CurrentUser:              NewCurrentUserHandler(),
@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kagent-dev/kagent/go/api/database"
+	"github.com/kagent-dev/kagent/go/core/internal/httpserver/errors"
+	"github.com/kagent-dev/kagent/go/core/pkg/auth"
+	"github.com/pgvector/pgvector-go"
+)
+
+const (
+	// knowledgeMaxChunks is the maximum number of chunks accepted for one document.
+	knowledgeMaxChunks = 1000
+	// defaultKnowledgeSearchLimit is used when the caller does not supply a limit.
+	defaultKnowledgeSearchLimit = 5
+	// maxKnowledgeSearchLimit caps the number of chunks returned by a search.
+	maxKnowledgeSearchLimit = 50
+)
+
+// KnowledgeHandler handles knowledge collection requests. Documents are
+// chunked and embedded by the caller; the handler stores and searches the
+// chunk vectors.
+type KnowledgeHandler struct {
+	*Base
+}
+
+// NewKnowledgeHandler creates a new KnowledgeHandler
+func NewKnowledgeHandler(base *Base) *KnowledgeHandler {
+	return &KnowledgeHandler{Base: base}
+}
+
+// KnowledgeChunkRequest is one embedded chunk of an ingested document.
+type KnowledgeChunkRequest struct {
+	Content     string            `json:"content"`
+	StartOffset int               `json:"start_offset"`
+	EndOffset   int               `json:"end_offset"`
+	Vector      []float32         `json:"vector"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+}
+
+// IngestKnowledgeRequest represents the request body for ingesting a document.
+// Ingesting a document that already exists replaces its chunks.
+type IngestKnowledgeRequest struct {
+	DocumentID string                  `json:"document_id"`
+	Chunks     []KnowledgeChunkRequest `json:"chunks"`
+}
+
+// SearchKnowledgeRequest represents the request body for searching a collection.
+type SearchKnowledgeRequest struct {
+	Vector []float32 `json:"vector"`
+	Limit  int       `json:"limit"`
+	// Filter restricts results to chunks whose metadata contains every entry.
+	Filter map[string]string `json:"filter,omitempty"`
+}
+
+// HandleIngestDocument handles POST /api/knowledge/{collection}/documents
+func (h *KnowledgeHandler) HandleIngestDocument(w ErrorResponseWriter, r *http.Request) {
+	log := ctrllog.FromContext(r.Context()).WithName("knowledge-handler").WithValues("operation", "ingest")
+
+	collection, err := GetPathParam(r, "collection")
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Failed to get collection from path", err))
+		return
+	}
+	if err := Check(h.Authorizer, r, auth.Resource{Type: "Knowledge", Name: collection}); err != nil {
+		w.RespondWithError(err)
+		return
+	}
+
+	var req IngestKnowledgeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Invalid request body", err))
+		return
+	}
+	if req.DocumentID == "" || len(req.Chunks) == 0 {
+		w.RespondWithError(errors.NewBadRequestError("Missing required fields (document_id, chunks)", nil))
+		return
+	}
+	if len(req.Chunks) > knowledgeMaxChunks {
+		w.RespondWithError(errors.NewBadRequestError(fmt.Sprintf("document has %d chunks, exceeding the maximum of %d", len(req.Chunks), knowledgeMaxChunks), nil))
+		return
+	}
+
+	chunks := make([]database.KnowledgeChunk, len(req.Chunks))
+	for i, c := range req.Chunks {
+		if len(c.Vector) != memoryVectorDimension {
+			w.RespondWithError(errors.NewBadRequestError(fmt.Sprintf("chunk %d: vector must have exactly %d dimensions, got %d", i, memoryVectorDimension, len(c.Vector)), nil))
+			return
+		}
+		chunks[i] = database.KnowledgeChunk{
+			Content:     c.Content,
+			StartOffset: c.StartOffset,
+			EndOffset:   c.EndOffset,
+			Embedding:   pgvector.NewVector(c.Vector),
+			Metadata:    c.Metadata,
+		}
+	}
+
+	if err := h.DatabaseService.StoreKnowledgeDocument(r.Context(), collection, req.DocumentID, chunks); err != nil {
+		w.RespondWithError(errors.NewInternalServerError("Failed to store document", err))
+		return
+	}
+
+	log.Info("Ingested document", "collection", collection, "documentID", req.DocumentID, "chunks", len(chunks))
+	RespondWithJSON(w, http.StatusCreated, map[string]any{"document_id": req.DocumentID, "chunks": len(chunks)})
+}
+
+// HandleSearch handles POST /api/knowledge/{collection}/search
+func (h *KnowledgeHandler) HandleSearch(w ErrorResponseWriter, r *http.Request) {
+	collection, err := GetPathParam(r, "collection")
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Failed to get collection from path", err))
+		return
+	}
+	if err := Check(h.Authorizer, r, auth.Resource{Type: "Knowledge", Name: collection}); err != nil {
+		w.RespondWithError(err)
+		return
+	}
+
+	var req SearchKnowledgeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Invalid request body", err))
+		return
+	}
+	if len(req.Vector) != memoryVectorDimension {
+		w.RespondWithError(errors.NewBadRequestError(fmt.Sprintf("vector must have exactly %d dimensions, got %d", memoryVectorDimension, len(req.Vector)), nil))
+		return
+	}
+	if req.Limit <= 0 {
+		req.Limit = defaultKnowledgeSearchLimit
+	}
+	req.Limit = min(req.Limit, maxKnowledgeSearchLimit)
+
+	results, err := h.DatabaseService.SearchKnowledge(r.Context(), collection, pgvector.NewVector(req.Vector), req.Filter, req.Limit)
+	if err != nil {
+		w.RespondWithError(errors.NewInternalServerError("Failed to search knowledge", err))
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, results)
+}
+
+// HandleListDocuments handles GET /api/knowledge/{collection}/documents
+func (h *KnowledgeHandler) HandleListDocuments(w ErrorResponseWriter, r *http.Request) {
+	collection, err := GetPathParam(r, "collection")
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Failed to get collection from path", err))
+		return
+	}
+	if err := Check(h.Authorizer, r, auth.Resource{Type: "Knowledge", Name: collection}); err != nil {
+		w.RespondWithError(err)
+		return
+	}
+
+	docs, err := h.DatabaseService.ListKnowledgeDocuments(r.Context(), collection)
+	if err != nil {
+		w.RespondWithError(errors.NewInternalServerError("Failed to list documents", err))
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, docs)
+}
+
+// HandleDeleteDocument handles DELETE /api/knowledge/{collection}/documents/{document_id}
+func (h *KnowledgeHandler) HandleDeleteDocument(w ErrorResponseWriter, r *http.Request) {
+	log := ctrllog.FromContext(r.Context()).WithName("knowledge-handler").WithValues("operation", "delete")
+
+	collection, err := GetPathParam(r, "collection")
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Failed to get collection from path", err))
+		return
+	}
+	documentID, err := GetPathParam(r, "document_id")
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Failed to get document ID from path", err))
+		return
+	}
+	if err := Check(h.Authorizer, r, auth.Resource{Type: "Knowledge", Name: collection}); err != nil {
+		w.RespondWithError(err)
+		return
+	}
+
+	if err := h.DatabaseService.DeleteKnowledgeDocument(r.Context(), collection, documentID); err != nil {
+		w.RespondWithError(errors.NewInternalServerError("Failed to delete document", err))
+		return
+	}
+
+	log.Info("Deleted document", "collection", collection, "documentID", documentID)
+	RespondWithJSON(w, http.StatusOK, map[string]string{"status": "deleted"})
+}
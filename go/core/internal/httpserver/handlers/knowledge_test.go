@@ -0,0 +1,111 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kagent-dev/kagent/go/api/database"
+	"github.com/kagent-dev/kagent/go/core/internal/httpserver/auth"
+	"github.com/kagent-dev/kagent/go/core/internal/httpserver/handlers"
+	pkgauth "github.com/kagent-dev/kagent/go/core/pkg/auth"
+)
+
+func TestKnowledgeHandler(t *testing.T) {
+	setupHandler := func(t *testing.T, authorizer pkgauth.Authorizer) (*handlers.KnowledgeHandler, *mockErrorResponseWriter) {
+		base := &handlers.Base{
+			DefaultModelConfig: types.NamespacedName{Namespace: "default", Name: "default"},
+			DatabaseService:    setupTestDBClient(t),
+			Authorizer:         authorizer,
+		}
+		return handlers.NewKnowledgeHandler(base), newMockErrorResponseWriter()
+	}
+	newRequest := func(method, path string, body any, vars map[string]string) *http.Request {
+		jsonBody, _ := json.Marshal(body)
+		req := httptest.NewRequest(method, path, bytes.NewBuffer(jsonBody))
+		req = setUser(req, "test-user")
+		return mux.SetURLVars(req, vars)
+	}
+	ingest := func(t *testing.T, handler *handlers.KnowledgeHandler, documentID string, chunks ...handlers.KnowledgeChunkRequest) {
+		t.Helper()
+		rw := newMockErrorResponseWriter()
+		req := newRequest("POST", "/api/knowledge/docs/documents",
+			handlers.IngestKnowledgeRequest{DocumentID: documentID, Chunks: chunks},
+			map[string]string{"collection": "docs"})
+		handler.HandleIngestDocument(rw, req)
+		require.Equal(t, http.StatusCreated, rw.Code, rw.Body.String())
+	}
+
+	t.Run("IngestAndSearch", func(t *testing.T) {
+		handler, rw := setupHandler(t, &auth.NoopAuthorizer{})
+		ingest(t, handler, "guide.md",
+			handlers.KnowledgeChunkRequest{Content: "pods", EndOffset: 4, Vector: makeVector(768, 0.1), Metadata: map[string]string{"lang": "en"}},
+			handlers.KnowledgeChunkRequest{Content: "hulsen", StartOffset: 4, EndOffset: 10, Vector: makeVector(768, 0.1), Metadata: map[string]string{"lang": "nl"}},
+		)
+
+		req := newRequest("POST", "/api/knowledge/docs/search",
+			handlers.SearchKnowledgeRequest{Vector: makeVector(768, 0.1), Filter: map[string]string{"lang": "nl"}},
+			map[string]string{"collection": "docs"})
+		handler.HandleSearch(rw, req)
+
+		require.Equal(t, http.StatusOK, rw.Code, rw.Body.String())
+		var results []database.KnowledgeSearchResult
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &results))
+		require.Len(t, results, 1)
+		assert.Equal(t, "hulsen", results[0].Content)
+		assert.Equal(t, "guide.md", results[0].DocumentID)
+		assert.Equal(t, 4, results[0].StartOffset)
+	})
+
+	t.Run("ReingestReplacesChunks", func(t *testing.T) {
+		handler, rw := setupHandler(t, &auth.NoopAuthorizer{})
+		chunk := handlers.KnowledgeChunkRequest{Content: "text", Vector: makeVector(768, 0.1)}
+		ingest(t, handler, "guide.md", chunk, chunk)
+		ingest(t, handler, "guide.md", chunk)
+
+		handler.HandleListDocuments(rw, newRequest("GET", "/api/knowledge/docs/documents", nil, map[string]string{"collection": "docs"}))
+
+		require.Equal(t, http.StatusOK, rw.Code)
+		var docs []database.KnowledgeDocument
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &docs))
+		require.Len(t, docs, 1)
+		assert.Equal(t, 1, docs[0].ChunkCount)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		handler, rw := setupHandler(t, &auth.NoopAuthorizer{})
+		ingest(t, handler, "guide.md", handlers.KnowledgeChunkRequest{Content: "text", Vector: makeVector(768, 0.1)})
+
+		handler.HandleDeleteDocument(rw, newRequest("DELETE", "/api/knowledge/docs/documents/guide.md", nil,
+			map[string]string{"collection": "docs", "document_id": "guide.md"}))
+		require.Equal(t, http.StatusOK, rw.Code)
+
+		rw = newMockErrorResponseWriter()
+		handler.HandleListDocuments(rw, newRequest("GET", "/api/knowledge/docs/documents", nil, map[string]string{"collection": "docs"}))
+		var docs []database.KnowledgeDocument
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &docs))
+		assert.Empty(t, docs)
+	})
+
+	t.Run("WrongVectorDimension", func(t *testing.T) {
+		handler, rw := setupHandler(t, &auth.NoopAuthorizer{})
+		req := newRequest("POST", "/api/knowledge/docs/documents",
+			handlers.IngestKnowledgeRequest{DocumentID: "guide.md", Chunks: []handlers.KnowledgeChunkRequest{{Content: "text", Vector: makeVector(3, 0.1)}}},
+			map[string]string{"collection": "docs"})
+		handler.HandleIngestDocument(rw, req)
+		assert.Equal(t, http.StatusBadRequest, rw.Code)
+	})
+
+	t.Run("Forbidden", func(t *testing.T) {
+		handler, rw := setupHandler(t, denyAuthorizer{})
+		handler.HandleListDocuments(rw, newRequest("GET", "/api/knowledge/docs/documents", nil, map[string]string{"collection": "docs"}))
+		assert.Equal(t, http.StatusForbidden, rw.Code)
+	})
+}
@@ -505,6 +505,62 @@ func (h *SessionsHandler) HandleAddEventToSession(w ErrorResponseWriter, r *http
 	RespondWithJSON(w, http.StatusCreated, data)
 }
 
+// SupersedeEventsResponse lists the events hidden by HandleSupersedeEvents.
+type SupersedeEventsResponse struct {
+	SupersededEventIDs []string `json:"superseded_event_ids"`
+}
+
+// HandleSupersedeEvents handles POST /api/sessions/{session_id}/events/{event_id}/supersede.
+// It hides the event and everything after it from the session history so the
+// conversation can be regenerated from an edited message. Superseded events are
+// soft-deleted rather than removed.
+func (h *SessionsHandler) HandleSupersedeEvents(w ErrorResponseWriter, r *http.Request) {
+	log := ctrllog.FromContext(r.Context()).WithName("sessions-handler").WithValues("operation", "supersede-events")
+	sessionID, err := GetPathParam(r, "session_id")
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Failed to get session ID from path", err))
+		return
+	}
+	eventID, err := GetPathParam(r, "event_id")
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Failed to get event ID from path", err))
+		return
+	}
+	log = log.WithValues("session_id", sessionID, "event_id", eventID)
+
+	principal, err := GetPrincipal(r)
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Failed to get user ID", err))
+		return
+	}
+	userID, err := getEffectiveUserIDForSession(r, sessionID)
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Failed to get user ID", err))
+		return
+	}
+	log = log.WithValues("userID", userID)
+
+	session, err := h.DatabaseService.GetSession(r.Context(), sessionID, userID)
+	if err != nil {
+		w.RespondWithError(errors.NewNotFoundError("Session not found", err))
+		return
+	}
+	if principal.Agent.ID != "" && session.AgentID != nil && *session.AgentID != utils.ConvertToPythonIdentifier(principal.Agent.ID) {
+		w.RespondWithError(errors.NewForbiddenError("Session does not belong to this agent", nil))
+		return
+	}
+
+	ids, err := h.DatabaseService.SupersedeEventsFrom(r.Context(), sessionID, userID, eventID)
+	if err != nil {
+		w.RespondWithError(errors.NewNotFoundError("Event not found", err))
+		return
+	}
+
+	log.Info("Superseded session events", "count", len(ids))
+	data := api.NewResponse(SupersedeEventsResponse{SupersededEventIDs: ids}, "Events superseded successfully", false)
+	RespondWithJSON(w, http.StatusOK, data)
+}
+
 func getUserID(r *http.Request) (string, error) {
 	log := ctrllog.Log.WithName("http-helpers")
 
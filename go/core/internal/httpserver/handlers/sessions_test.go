@@ -693,4 +693,50 @@ func TestSessionsHandler(t *testing.T) {
 			assert.NotNil(t, responseRecorder.errorReceived)
 		})
 	})
+
+	t.Run("HandleSupersedeEvents", func(t *testing.T) {
+		t.Run("Success", func(t *testing.T) {
+			handler, dbClient, responseRecorder := setupHandler(t)
+			userID := "test-user"
+			sessionID := "test-session"
+			createTestSession(t, dbClient, sessionID, userID, "1")
+			for _, id := range []string{"event-1", "event-2", "event-3"} {
+				require.NoError(t, dbClient.StoreEvents(context.Background(), &database.Event{
+					ID: id, SessionID: sessionID, UserID: userID, Data: "{}",
+				}))
+			}
+
+			req := httptest.NewRequest("POST", "/api/sessions/"+sessionID+"/events/event-2/supersede", nil)
+			req = mux.SetURLVars(req, map[string]string{"session_id": sessionID, "event_id": "event-2"})
+			req = setUser(req, userID)
+
+			handler.HandleSupersedeEvents(responseRecorder, req)
+
+			require.Equal(t, http.StatusOK, responseRecorder.Code)
+			var response api.StandardResponse[handlers.SupersedeEventsResponse]
+			require.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &response))
+			assert.ElementsMatch(t, []string{"event-2", "event-3"}, response.Data.SupersededEventIDs)
+
+			events, err := dbClient.ListEventsForSession(context.Background(), sessionID, userID, database.QueryOptions{})
+			require.NoError(t, err)
+			require.Len(t, events, 1)
+			assert.Equal(t, "event-1", events[0].ID)
+		})
+
+		t.Run("EventNotFound", func(t *testing.T) {
+			handler, dbClient, responseRecorder := setupHandler(t)
+			userID := "test-user"
+			sessionID := "test-session"
+			createTestSession(t, dbClient, sessionID, userID, "1")
+
+			req := httptest.NewRequest("POST", "/api/sessions/"+sessionID+"/events/missing/supersede", nil)
+			req = mux.SetURLVars(req, map[string]string{"session_id": sessionID, "event_id": "missing"})
+			req = setUser(req, userID)
+
+			handler.HandleSupersedeEvents(responseRecorder, req)
+
+			assert.Equal(t, http.StatusNotFound, responseRecorder.Code)
+			assert.NotNil(t, responseRecorder.errorReceived)
+		})
+	})
 }
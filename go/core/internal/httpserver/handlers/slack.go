@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	api "github.com/kagent-dev/kagent/go/api/httpapi"
+	"github.com/kagent-dev/kagent/go/core/internal/httpserver/errors"
+	"github.com/kagent-dev/kagent/go/core/internal/notifications"
+	"github.com/kagent-dev/kagent/go/core/pkg/auth"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// slackMaxBodySize bounds interaction request bodies.
+	slackMaxBodySize = 1 << 20
+
+	// slackDecisionTimeout bounds sending a decision to the agent, which
+	// resumes the paused task.
+	slackDecisionTimeout = 10 * time.Minute
+)
+
+// SlackHandler receives Slack button clicks on approval notifications. It is
+// served outside the authentication middleware: requests are authenticated
+// by Slack's request signature, and the clicking Slack user is mapped to a
+// kagent user who must be allowed to decide on the task. Clicks from Slack
+// users without a mapping are rejected.
+type SlackHandler struct {
+	approvals     *ApprovalsHandler
+	signingSecret string
+	// users maps Slack user IDs to kagent user IDs.
+	users map[string]string
+	now   func() time.Time
+}
+
+// NewSlackHandler creates a new SlackHandler. Interactions are refused while
+// signingSecret is empty.
+func NewSlackHandler(approvals *ApprovalsHandler, signingSecret string, users map[string]string) *SlackHandler {
+	return &SlackHandler{approvals: approvals, signingSecret: signingSecret, users: users, now: time.Now}
+}
+
+// HandleInteraction handles POST /api/slack/interactions requests. Slack
+// expects an acknowledgement within three seconds, so the decision is sent
+// in the background and its outcome replaces the original message.
+func (h *SlackHandler) HandleInteraction(w http.ResponseWriter, r *http.Request) {
+	log := ctrllog.FromContext(r.Context()).WithName("slack-handler").WithValues("operation", "interaction")
+
+	if h.signingSecret == "" {
+		RespondWithError(w, http.StatusServiceUnavailable, "Slack interactions are not configured")
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, slackMaxBodySize))
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Failed to read request body")
+		return
+	}
+	if err := notifications.VerifySlackRequest(h.signingSecret, r.Header, body, h.now()); err != nil {
+		log.Info("Rejected Slack interaction", "reason", err.Error())
+		RespondWithError(w, http.StatusUnauthorized, "Invalid Slack signature")
+		return
+	}
+	interaction, err := notifications.ParseSlackInteraction(body)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	log = log.WithValues("task_id", interaction.TaskID, "slackUserID", interaction.SlackUserID, "slackUser", interaction.SlackUser)
+	userID, ok := h.users[interaction.SlackUserID]
+	if !ok {
+		log.Info("Rejected Slack interaction from an unmapped Slack user")
+		RespondWithError(w, http.StatusForbidden, "Slack user is not mapped to a kagent user")
+		return
+	}
+	log = log.WithValues("userID", userID)
+
+	w.WriteHeader(http.StatusOK)
+
+	ctx := context.WithoutCancel(r.Context())
+	go func() {
+		ctx, cancel := context.WithTimeout(ctx, slackDecisionTimeout)
+		defer cancel()
+
+		text := h.decide(ctx, userID, interaction)
+		if err := notifications.RespondToSlackInteraction(ctx, interaction.ResponseURL, text); err != nil {
+			log.Error(err, "Failed to update Slack message")
+		}
+	}()
+}
+
+// decide sends the decision clicked by userID and describes the outcome for
+// Slack.
+func (h *SlackHandler) decide(ctx context.Context, userID string, interaction *notifications.SlackInteraction) string {
+	log := ctrllog.FromContext(ctx).WithName("slack-handler").WithValues("task_id", interaction.TaskID)
+
+	req := api.ApprovalDecisionRequest{Decision: interaction.Decision}
+	if interaction.Decision == "reject" {
+		req.Reason = fmt.Sprintf("Rejected in Slack by %s", interaction.SlackUser)
+	}
+	resp, err := h.approvals.Decide(ctx, auth.Principal{User: auth.User{ID: userID}}, interaction.TaskID, req)
+	if err != nil {
+		log.Error(err, "Failed to send approval decision from Slack")
+		var apiErr *errors.APIError
+		if stderrors.As(err, &apiErr) {
+			return fmt.Sprintf("Could not %s task `%s`: %s", interaction.Decision, interaction.TaskID, apiErr.Message)
+		}
+		return fmt.Sprintf("Could not %s task `%s`", interaction.Decision, interaction.TaskID)
+	}
+
+	verb := "Approved"
+	if interaction.Decision == "reject" {
+		verb = "Rejected"
+	}
+	text := fmt.Sprintf("%s by %s: task `%s`", verb, interaction.SlackUser, interaction.TaskID)
+	if resp.State != "" {
+		text += fmt.Sprintf(" is now %s", resp.State)
+	}
+	log.Info("Sent approval decision from Slack", "decision", interaction.Decision, "state", resp.State)
+	return text
+}
@@ -0,0 +1,85 @@
+package handlers_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kagent-dev/kagent/go/api/database"
+	api "github.com/kagent-dev/kagent/go/api/httpapi"
+	"github.com/kagent-dev/kagent/go/core/internal/httpserver/handlers"
+	"github.com/kagent-dev/kagent/go/core/pkg/auth"
+)
+
+func slackInteractionRequest(t *testing.T, secret, slackUserID string) *http.Request {
+	t.Helper()
+	payload := `{"type":"block_actions","user":{"id":"` + slackUserID + `","username":"someone"},` +
+		`"actions":[{"action_id":"kagent_approve","value":"{\"task_id\":\"task-1\"}"}]}`
+	body := url.Values{"payload": {payload}}.Encode()
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + timestamp + ":" + body)) //nolint:errcheck
+
+	req := httptest.NewRequest(http.MethodPost, "/api/slack/interactions", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Slack-Request-Timestamp", timestamp)
+	req.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+	return req
+}
+
+func TestSlackHandleInteraction(t *testing.T) {
+	approvals := handlers.NewApprovalsHandler(&handlers.Base{Authorizer: &auth.NoopAuthorizer{}}, nil)
+	users := map[string]string{"U123": "alice"}
+
+	t.Run("unavailable without a signing secret", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handlers.NewSlackHandler(approvals, "", users).HandleInteraction(rec, slackInteractionRequest(t, "", "U123"))
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	})
+
+	t.Run("rejects bad signatures", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handlers.NewSlackHandler(approvals, "secret", users).HandleInteraction(rec, slackInteractionRequest(t, "other", "U123"))
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("rejects unmapped Slack users", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handlers.NewSlackHandler(approvals, "secret", users).HandleInteraction(rec, slackInteractionRequest(t, "secret", "U999"))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("acknowledges mapped Slack users", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handlers.NewSlackHandler(approvals, "secret", users).HandleInteraction(rec, slackInteractionRequest(t, "secret", "U123"))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+}
+
+func TestApprovalsDecide_ForwardsPrincipal(t *testing.T) {
+	ctx := context.Background()
+	dbClient := setupTestDBClient(t)
+	require.NoError(t, dbClient.StoreSession(ctx, &database.Session{ID: "session-1", UserID: "alice", AgentID: new("kagent__NS__approver")}))
+	require.NoError(t, dbClient.StoreTask(ctx, waitingTask("task-1", time.Now())))
+
+	// Slack decisions run outside the auth middleware, with no session in
+	// their context.
+	messenger := &recordingMessenger{}
+	approvals := handlers.NewApprovalsHandler(&handlers.Base{DatabaseService: dbClient, Authorizer: &auth.NoopAuthorizer{}}, messenger)
+	_, err := approvals.Decide(ctx, auth.Principal{User: auth.User{ID: "alice"}}, "task-1", api.ApprovalDecisionRequest{Decision: "approve"})
+	require.NoError(t, err)
+	require.Len(t, messenger.sent, 1)
+	assert.Equal(t, "alice", messenger.sent[0].userID)
+}
@@ -1,14 +1,21 @@
 package handlers
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	a2a "github.com/a2aproject/a2a-go/v2/a2a"
+	"github.com/kagent-dev/kagent/go/api/database"
 	api "github.com/kagent-dev/kagent/go/api/httpapi"
 	"github.com/kagent-dev/kagent/go/core/internal/httpserver/errors"
+	"github.com/kagent-dev/kagent/go/core/internal/notifications"
 	"github.com/kagent-dev/kagent/go/core/internal/utils"
 	"github.com/kagent-dev/kagent/go/core/pkg/a2acompat/trpcv0"
+	"github.com/kagent-dev/kagent/go/core/pkg/auth"
 	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
 	"trpc.group/trpc-go/trpc-a2a-go/protocol"
 )
@@ -16,11 +23,128 @@ import (
 // TasksHandler handles task-related requests
 type TasksHandler struct {
 	*Base
+	notifier *notifications.Dispatcher
 }
 
-// NewTasksHandler creates a new TasksHandler
-func NewTasksHandler(base *Base) *TasksHandler {
-	return &TasksHandler{Base: base}
+// NewTasksHandler creates a new TasksHandler. notifier, which may be nil, is
+// told when a stored task pauses on tool approvals, completes, fails or
+// reaches the token budget.
+func NewTasksHandler(base *Base, notifier *notifications.Dispatcher) *TasksHandler {
+	return &TasksHandler{Base: base, notifier: notifier}
+}
+
+// taskStatesByName maps the ?status= values accepted by HandleListTasks, the
+// state names of the legacy A2A wire format, to task states.
+var taskStatesByName = map[string]a2a.TaskState{
+	"submitted":      a2a.TaskStateSubmitted,
+	"working":        a2a.TaskStateWorking,
+	"input-required": a2a.TaskStateInputRequired,
+	"auth-required":  a2a.TaskStateAuthRequired,
+	"completed":      a2a.TaskStateCompleted,
+	"canceled":       a2a.TaskStateCanceled,
+	"failed":         a2a.TaskStateFailed,
+	"rejected":       a2a.TaskStateRejected,
+}
+
+const (
+	// defaultTaskListLimit is the number of tasks HandleListTasks returns
+	// when the request sets no limit.
+	defaultTaskListLimit = 100
+	// maxTaskListLimit caps the limit a request may ask for.
+	maxTaskListLimit = 1000
+)
+
+// HandleListTasks handles GET /api/tasks?status=&user=&since=&limit= requests.
+// It lists the caller's tasks; listing another user's tasks requires access
+// to the Admin resource. since is an RFC 3339 timestamp compared with the
+// task's last update.
+func (h *TasksHandler) HandleListTasks(w ErrorResponseWriter, r *http.Request) {
+	log := ctrllog.FromContext(r.Context()).WithName("tasks-handler").WithValues("operation", "list-tasks")
+
+	userID, err := GetUserID(r)
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Failed to get user ID", err))
+		return
+	}
+	query := r.URL.Query()
+	filter := database.TaskFilter{UserID: userID, Limit: defaultTaskListLimit}
+	if user := query.Get("user"); user != "" && user != userID {
+		if err := Check(h.Authorizer, r, auth.Resource{Type: "Admin"}); err != nil {
+			w.RespondWithError(err)
+			return
+		}
+		filter.UserID = user
+	} else if err := Check(h.Authorizer, r, auth.Resource{Type: "Task"}); err != nil {
+		w.RespondWithError(err)
+		return
+	}
+	// An empty user would match every user's tasks: callers without one,
+	// such as agents, must name the user.
+	if filter.UserID == "" {
+		w.RespondWithError(errors.NewBadRequestError("Failed to get user ID", fmt.Errorf("the caller has no user ID and no user was requested")))
+		return
+	}
+	if status := query.Get("status"); status != "" {
+		state, ok := taskStatesByName[status]
+		if !ok {
+			w.RespondWithError(errors.NewBadRequestError("Invalid status", fmt.Errorf("unknown task status %q", status)))
+			return
+		}
+		filter.State = state
+	}
+	if since := query.Get("since"); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			w.RespondWithError(errors.NewBadRequestError("Invalid since timestamp", err))
+			return
+		}
+		filter.Since = t
+	}
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > maxTaskListLimit {
+			w.RespondWithError(errors.NewBadRequestError("Invalid limit", fmt.Errorf("limit must be an integer between 1 and %d, got %q", maxTaskListLimit, limitStr)))
+			return
+		}
+		filter.Limit = limit
+	}
+	log = log.WithValues("user", filter.UserID, "status", filter.State, "since", filter.Since, "limit", filter.Limit)
+
+	wireVersion, err := utils.NegotiateA2AWireVersion(r)
+	if err != nil {
+		w.RespondWithError(errors.NewBadRequestError("Unsupported A2A version", err))
+		return
+	}
+
+	tasks, err := h.DatabaseService.ListTasks(r.Context(), filter)
+	if err != nil {
+		w.RespondWithError(errors.NewInternalServerError("Failed to list tasks", err))
+		return
+	}
+
+	log.Info("Successfully listed tasks", "count", len(tasks))
+	// TODO(0.11.0): Remove legacy API conversion after legacy wire support is no longer supported.
+	var data any
+	switch wireVersion {
+	case utils.A2AWireVersionLegacy:
+		legacyTasks := make([]any, 0, len(tasks))
+		for _, task := range tasks {
+			legacyTask, convErr := trpcv0.ToLegacyTask(task)
+			if convErr != nil {
+				w.RespondWithError(errors.NewInternalServerError("Failed to convert task", convErr))
+				return
+			}
+			legacyTasks = append(legacyTasks, legacyTask)
+		}
+		data = legacyTasks
+	case utils.A2AWireVersionV1:
+		data = tasks
+	default:
+		w.RespondWithError(errors.NewBadRequestError("Unsupported A2A version", fmt.Errorf("unknown negotiated wire version %q", wireVersion)))
+		return
+	}
+	response := api.NewResponse(data, "Successfully listed tasks", false)
+	RespondWithJSON(w, http.StatusOK, response)
 }
 
 func (h *TasksHandler) HandleGetTask(w ErrorResponseWriter, r *http.Request) {
@@ -106,10 +230,14 @@ func (h *TasksHandler) HandleCreateTask(w ErrorResponseWriter, r *http.Request)
 	}
 	log = log.WithValues("task_id", task.ID)
 
+	events := h.taskEvents(r.Context(), &task)
 	if err := h.DatabaseService.StoreTask(r.Context(), &task); err != nil {
 		w.RespondWithError(errors.NewInternalServerError("Failed to create task", err))
 		return
 	}
+	if len(events) > 0 {
+		h.notifyTaskEvents(r.Context(), &task, events)
+	}
 
 	log.Info("Successfully created task")
 	var data any
@@ -149,3 +277,89 @@ func (h *TasksHandler) HandleDeleteTask(w ErrorResponseWriter, r *http.Request)
 	log.Info("Successfully deleted task")
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// taskEvents returns the notification events raised by storing task: the
+// transition into an approval pause, completion or failure, and the first
+// save reaching the token budget. Saves that change neither are not
+// announced again.
+func (h *TasksHandler) taskEvents(ctx context.Context, task *a2a.Task) []notifications.EventType {
+	if !h.notifier.Enabled() {
+		return nil
+	}
+	previous, err := h.DatabaseService.GetTask(ctx, string(task.ID))
+	if err != nil {
+		previous = nil
+	}
+
+	var events []notifications.EventType
+	if previous == nil || previous.Status.State != task.Status.State {
+		switch task.Status.State {
+		case a2a.TaskStateInputRequired:
+			if len(pendingToolCalls(task)) > 0 {
+				events = append(events, notifications.EventApprovalRequired)
+			}
+		case a2a.TaskStateCompleted:
+			events = append(events, notifications.EventTaskCompleted)
+		case a2a.TaskStateFailed:
+			events = append(events, notifications.EventTaskFailed)
+		}
+	}
+	if budget := h.notifier.TokenBudget; budget > 0 && totalTokens(task) >= budget && (previous == nil || totalTokens(previous) < budget) {
+		events = append(events, notifications.EventBudgetExceeded)
+	}
+	return events
+}
+
+// notifyTaskEvents dispatches events for task. The owner comes from the
+// user_id metadata the agent stamps on its tasks.
+func (h *TasksHandler) notifyTaskEvents(ctx context.Context, task *a2a.Task, events []notifications.EventType) {
+	log := ctrllog.FromContext(ctx).WithName("tasks-handler").WithValues("task_id", task.ID)
+
+	userID, _ := partMetadataValue(task.Metadata, "user_id").(string)
+	if userID == "" {
+		log.Info("Not announcing task events: task has no user_id metadata", "events", events)
+		return
+	}
+	base := notifications.Event{
+		TaskID:    string(task.ID),
+		SessionID: task.ContextID,
+		UserID:    userID,
+		State:     string(task.Status.State),
+		Message:   statusText(task),
+	}
+	if session, err := h.DatabaseService.GetSession(ctx, task.ContextID, userID); err == nil && session.AgentID != nil {
+		base.AgentRef = utils.ConvertToKubernetesIdentifier(*session.AgentID)
+	}
+	for _, eventType := range events {
+		event := base
+		event.Type = eventType
+		switch eventType {
+		case notifications.EventApprovalRequired:
+			event.ToolCalls = pendingToolCalls(task)
+		case notifications.EventBudgetExceeded:
+			event.TotalTokens = totalTokens(task)
+			event.TokenBudget = h.notifier.TokenBudget
+		}
+		h.notifier.Dispatch(ctx, event)
+	}
+}
+
+// totalTokens is the token usage the agent stamped on task, zero when none.
+func totalTokens(task *a2a.Task) int64 {
+	usage, _ := tokenUsageFromTask(task)
+	return usage.TotalTokenCount
+}
+
+// statusText joins the text parts of task's status message.
+func statusText(task *a2a.Task) string {
+	if task.Status.Message == nil {
+		return ""
+	}
+	var texts []string
+	for _, part := range task.Status.Message.Parts {
+		if part != nil && part.Text() != "" {
+			texts = append(texts, part.Text())
+		}
+	}
+	return strings.Join(texts, "\n")
+}
@@ -0,0 +1,93 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	a2a "github.com/a2aproject/a2a-go/v2/a2a"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kagent-dev/kagent/go/api/database"
+	api "github.com/kagent-dev/kagent/go/api/httpapi"
+	"github.com/kagent-dev/kagent/go/core/internal/httpserver/handlers"
+	"github.com/kagent-dev/kagent/go/core/pkg/auth"
+)
+
+func TestHandleListTasks(t *testing.T) {
+	setup := func(t *testing.T, authorizer auth.Authorizer) (*handlers.TasksHandler, database.Client) {
+		t.Helper()
+		dbClient := setupTestDBClient(t)
+		for _, s := range []struct{ id, user string }{{"alice-session", "alice"}, {"bob-session", "bob"}} {
+			require.NoError(t, dbClient.StoreSession(context.Background(), &database.Session{ID: s.id, UserID: s.user}))
+		}
+		for _, task := range []*a2a.Task{
+			{ID: "alice-done", ContextID: "alice-session", Status: a2a.TaskStatus{State: a2a.TaskStateCompleted}},
+			{ID: "alice-waiting", ContextID: "alice-session", Status: a2a.TaskStatus{State: a2a.TaskStateInputRequired}},
+			{ID: "bob-done", ContextID: "bob-session", Status: a2a.TaskStatus{State: a2a.TaskStateCompleted}},
+		} {
+			require.NoError(t, dbClient.StoreTask(context.Background(), task))
+		}
+		return handlers.NewTasksHandler(&handlers.Base{DatabaseService: dbClient, Authorizer: authorizer}, nil), dbClient
+	}
+	list := func(t *testing.T, handler *handlers.TasksHandler, user, target string) (*mockErrorResponseWriter, []string) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/api/tasks"+target, nil)
+		req.Header.Set("A2A-Version", "1.0")
+		req = setUser(req, user)
+		rec := newMockErrorResponseWriter()
+		handler.HandleListTasks(rec, req)
+		if rec.Code != http.StatusOK {
+			return rec, nil
+		}
+		var response api.StandardResponse[[]*a2a.Task]
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		var ids []string
+		for _, task := range response.Data {
+			ids = append(ids, string(task.ID))
+		}
+		return rec, ids
+	}
+
+	t.Run("lists the caller's tasks", func(t *testing.T) {
+		handler, _ := setup(t, &auth.NoopAuthorizer{})
+		_, ids := list(t, handler, "alice", "")
+		assert.ElementsMatch(t, []string{"alice-done", "alice-waiting"}, ids)
+
+		_, ids = list(t, handler, "alice", "?status=input-required")
+		assert.Equal(t, []string{"alice-waiting"}, ids)
+
+		_, ids = list(t, handler, "alice", "?limit=1")
+		assert.Len(t, ids, 1)
+	})
+
+	t.Run("other users require the admin resource", func(t *testing.T) {
+		handler, _ := setup(t, &auth.NoopAuthorizer{})
+		_, ids := list(t, handler, "alice", "?user=bob")
+		assert.Equal(t, []string{"bob-done"}, ids)
+
+		handler, _ = setup(t, denyAuthorizer{})
+		rec, _ := list(t, handler, "alice", "?user=bob")
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("callers without a user must name one", func(t *testing.T) {
+		handler, _ := setup(t, &auth.NoopAuthorizer{})
+		rec, _ := list(t, handler, "", "")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		_, ids := list(t, handler, "", "?user=bob")
+		assert.Equal(t, []string{"bob-done"}, ids)
+	})
+
+	t.Run("rejects limits out of range", func(t *testing.T) {
+		handler, _ := setup(t, &auth.NoopAuthorizer{})
+		for _, limit := range []string{"0", "-1", "1001", "many"} {
+			rec, _ := list(t, handler, "alice", "?limit="+limit)
+			assert.Equal(t, http.StatusBadRequest, rec.Code, "limit=%s", limit)
+		}
+	})
+}
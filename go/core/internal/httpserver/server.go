@@ -9,10 +9,13 @@ import (
 	"github.com/gorilla/mux"
 	dbpkg "github.com/kagent-dev/kagent/go/api/database"
 	api "github.com/kagent-dev/kagent/go/api/httpapi"
+	"github.com/kagent-dev/kagent/go/api/httpsecurity"
+	"github.com/kagent-dev/kagent/go/api/requestid"
 	"github.com/kagent-dev/kagent/go/core/internal/a2a"
 	"github.com/kagent-dev/kagent/go/core/internal/controller/reconciler"
 	"github.com/kagent-dev/kagent/go/core/internal/httpserver/handlers"
 	"github.com/kagent-dev/kagent/go/core/internal/mcp"
+	"github.com/kagent-dev/kagent/go/core/internal/notifications"
 	common "github.com/kagent-dev/kagent/go/core/internal/utils"
 	"github.com/kagent-dev/kagent/go/core/internal/version"
 	"github.com/kagent-dev/kagent/go/core/pkg/auth"
@@ -33,6 +36,9 @@ const (
 	APIPathRuns                 = "/api/runs"
 	APIPathSessions             = "/api/sessions"
 	APIPathTasks                = "/api/tasks"
+	APIPathAdmin                = "/api/admin"
+	APIPathApprovals            = "/api/approvals"
+	APIPathSlackInteractions    = "/api/slack/interactions"
 	APIPathTools                = "/api/tools"
 	APIPathToolServers          = "/api/toolservers"
 	APIPathToolServerTypes      = "/api/toolservertypes"
@@ -42,6 +48,7 @@ const (
 	APIPathModelProviderConfigs = "/api/modelproviderconfigs"
 	APIPathModels               = "/api/models"
 	APIPathMemories             = "/api/memories"
+	APIPathKnowledge            = "/api/knowledge"
 	APIPathNamespaces           = "/api/namespaces"
 	APIPathPromptTemplates      = "/api/prompttemplates"
 	APIPathA2A                  = "/api/a2a"
@@ -78,6 +85,19 @@ type ServerConfig struct {
 	MCPEgressPlaintext           bool
 	SubstrateSandboxActorBackend *substrate.SandboxAgentActorBackend
 	AgentHarnessSessionActor     *substrate.AgentHarnessSessionActorBackend
+	// AgentClients sends approval decisions from the approval inbox to agents.
+	AgentClients *a2a.AgentClientRegistry
+	// Notifier announces tasks paused on tool approvals; nil disables it.
+	Notifier *notifications.Dispatcher
+	// SlackSigningSecret verifies Slack button clicks. The interactions
+	// endpoint answers 503 while it is unset.
+	SlackSigningSecret string
+	// SlackUsers maps Slack user IDs to the kagent users their clicks decide
+	// as.
+	SlackUsers map[string]string
+	// Security configures CORS and the security headers. It wraps the whole
+	// router so that CORS preflight requests are answered for every route.
+	Security httpsecurity.Config
 }
 
 // HTTPServer is the structure that manages the HTTP server
@@ -93,6 +113,12 @@ type HTTPServer struct {
 func NewHTTPServer(config ServerConfig) (*HTTPServer, error) {
 	// Initialize database
 
+	// Keep a nil registry out of the interface so handlers can detect it.
+	var agentMessenger handlers.AgentMessenger
+	if config.AgentClients != nil {
+		agentMessenger = config.AgentClients
+	}
+
 	return &HTTPServer{
 		config: config,
 		router: config.Router,
@@ -110,6 +136,10 @@ func NewHTTPServer(config ServerConfig) (*HTTPServer, error) {
 			config.MCPEgressPlaintext,
 			config.SubstrateSandboxActorBackend,
 			config.AgentHarnessSessionActor,
+			agentMessenger,
+			config.Notifier,
+			config.SlackSigningSecret,
+			config.SlackUsers,
 		),
 		authenticator: config.Authenticator,
 	}, nil
@@ -123,11 +153,22 @@ func (s *HTTPServer) Start(ctx context.Context) error {
 	// Setup routes
 	s.setupRoutes()
 
+	// Slack authenticates its interaction callbacks with a request signature
+	// rather than kagent credentials, so they bypass the router's middleware.
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.URL.Path == APIPathSlackInteractions {
+			s.handlers.Slack.HandleInteraction(w, r)
+			return
+		}
+		s.router.ServeHTTP(w, r)
+	})
+
 	// Create HTTP server, wrapping the router with otelhttp for span creation
-	// and W3C TraceContext propagation on every incoming request.
+	// and W3C TraceContext propagation on every incoming request. Every
+	// request is assigned an X-Request-ID, which is forwarded to agents.
 	s.httpServer = &http.Server{
 		Addr: s.config.BindAddr,
-		Handler: otelhttp.NewHandler(s.router, "http.server",
+		Handler: otelhttp.NewHandler(httpsecurity.Middleware(s.config.Security, requestid.Middleware(handler)), "http.server",
 			otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
 				return r.Method + " " + r.URL.Path
 			}),
@@ -197,6 +238,48 @@ func (m *MemoryCleanupRunnable) Start(ctx context.Context) error {
 	}
 }
 
+// ApprovalExpiryRunnable is a controller-runtime Runnable that periodically
+// rejects tool approvals left waiting longer than TTL. Like
+// MemoryCleanupRunnable it only runs on the elected leader, so each expired
+// approval is rejected once.
+type ApprovalExpiryRunnable struct {
+	DbClient dbpkg.Client
+	Agents   handlers.AgentMessenger
+	TTL      time.Duration
+	Interval time.Duration
+}
+
+func (e *ApprovalExpiryRunnable) NeedLeaderElection() bool { return true }
+
+// NewApprovalExpiryRunnable returns an ApprovalExpiryRunnable rejecting
+// approvals older than ttl. The sweep runs every ttl/10, at least once a
+// minute.
+func NewApprovalExpiryRunnable(dbClient dbpkg.Client, agents handlers.AgentMessenger, ttl time.Duration) *ApprovalExpiryRunnable {
+	return &ApprovalExpiryRunnable{DbClient: dbClient, Agents: agents, TTL: ttl, Interval: min(max(ttl/10, time.Second), time.Minute)}
+}
+
+// Start runs the periodic expiry loop until ctx is cancelled.
+func (e *ApprovalExpiryRunnable) Start(ctx context.Context) error {
+	log := ctrllog.FromContext(ctx).WithName("approval-expiry")
+	log.Info("Starting approval expiry loop", "ttl", e.TTL, "interval", e.Interval)
+	ticker := time.NewTicker(e.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			expired, err := handlers.ExpireApprovals(ctx, e.DbClient, e.Agents, e.TTL, time.Now())
+			if err != nil {
+				log.Error(err, "Failed to expire approvals")
+			}
+			if expired > 0 {
+				log.Info("Rejected expired approvals", "count", expired)
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
 // Stop stops the HTTP server
 func (s *HTTPServer) Stop(ctx context.Context) error {
 	if s.httpServer != nil {
@@ -247,15 +330,27 @@ func (s *HTTPServer) setupRoutes() {
 	s.router.HandleFunc(APIPathSessions+"/{session_id}", adaptHandler(s.handlers.Sessions.HandleDeleteSession)).Methods(http.MethodDelete)
 	s.router.HandleFunc(APIPathSessions+"/{session_id}", adaptHandler(s.handlers.Sessions.HandleUpdateSession)).Methods(http.MethodPut, http.MethodPatch)
 	s.router.HandleFunc(APIPathSessions+"/{session_id}/events", adaptHandler(s.handlers.Sessions.HandleAddEventToSession)).Methods(http.MethodPost)
+	s.router.HandleFunc(APIPathSessions+"/{session_id}/events/{event_id}/supersede", adaptHandler(s.handlers.Sessions.HandleSupersedeEvents)).Methods(http.MethodPost)
 	s.router.HandleFunc(APIPathSessions+"/{session_id}/shares", adaptHandler(s.handlers.SessionShares.HandleCreateSessionShare)).Methods(http.MethodPost)
 	s.router.HandleFunc(APIPathSessions+"/{session_id}/shares", adaptHandler(s.handlers.SessionShares.HandleListSessionShares)).Methods(http.MethodGet)
 	s.router.HandleFunc(APIPathSessions+"/{session_id}/shares/{token}", adaptHandler(s.handlers.SessionShares.HandleDeleteSessionShare)).Methods(http.MethodDelete)
 
 	// Tasks
+	s.router.HandleFunc(APIPathTasks, adaptHandler(s.handlers.Tasks.HandleListTasks)).Methods(http.MethodGet)
 	s.router.HandleFunc(APIPathTasks+"/{task_id}", adaptHandler(s.handlers.Tasks.HandleGetTask)).Methods(http.MethodGet)
 	s.router.HandleFunc(APIPathTasks, adaptHandler(s.handlers.Tasks.HandleCreateTask)).Methods(http.MethodPost)
 	s.router.HandleFunc(APIPathTasks+"/{task_id}", adaptHandler(s.handlers.Tasks.HandleDeleteTask)).Methods(http.MethodDelete)
 
+	// Admin - read-only operational views
+	s.router.HandleFunc(APIPathAdmin+"/agents/usage", adaptHandler(s.handlers.Admin.HandleAgentUsage)).Methods(http.MethodGet)
+	s.router.HandleFunc(APIPathAdmin+"/tasks/error-rates", adaptHandler(s.handlers.Admin.HandleErrorRates)).Methods(http.MethodGet)
+	s.router.HandleFunc(APIPathAdmin+"/tasks/pending-approvals", adaptHandler(s.handlers.Admin.HandlePendingApprovals)).Methods(http.MethodGet)
+	s.router.HandleFunc(APIPathAdmin+"/tasks/long-running", adaptHandler(s.handlers.Admin.HandleLongRunningTasks)).Methods(http.MethodGet)
+
+	// Approvals
+	s.router.HandleFunc(APIPathApprovals, adaptHandler(s.handlers.Approvals.HandleListApprovals)).Methods(http.MethodGet)
+	s.router.HandleFunc(APIPathApprovals+"/{task_id}", adaptHandler(s.handlers.Approvals.HandleDecideApproval)).Methods(http.MethodPost)
+
 	// Tools - using database handlers
 	s.router.HandleFunc(APIPathTools, adaptHandler(s.handlers.Tools.HandleListTools)).Methods(http.MethodGet)
 
@@ -298,6 +393,12 @@ func (s *HTTPServer) setupRoutes() {
 	s.router.HandleFunc(APIPathMemories, adaptHandler(s.handlers.Memory.List)).Methods(http.MethodGet)
 	s.router.HandleFunc(APIPathMemories, adaptHandler(s.handlers.Memory.Delete)).Methods(http.MethodDelete)
 
+	// Knowledge
+	s.router.HandleFunc(APIPathKnowledge+"/{collection}/documents", adaptHandler(s.handlers.Knowledge.HandleListDocuments)).Methods(http.MethodGet)
+	s.router.HandleFunc(APIPathKnowledge+"/{collection}/documents", adaptHandler(s.handlers.Knowledge.HandleIngestDocument)).Methods(http.MethodPost)
+	s.router.HandleFunc(APIPathKnowledge+"/{collection}/documents/{document_id}", adaptHandler(s.handlers.Knowledge.HandleDeleteDocument)).Methods(http.MethodDelete)
+	s.router.HandleFunc(APIPathKnowledge+"/{collection}/search", adaptHandler(s.handlers.Knowledge.HandleSearch)).Methods(http.MethodPost)
+
 	// Namespaces
 	s.router.HandleFunc(APIPathNamespaces, adaptHandler(s.handlers.Namespaces.HandleListNamespaces)).Methods(http.MethodGet)
 
@@ -0,0 +1,129 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"text/template"
+)
+
+const (
+	defaultEmailSubject = `[kagent] {{.Type}}: task {{.TaskID}}{{with .AgentRef}} of {{.}}{{end}}`
+	defaultEmailBody    = `Task {{.TaskID}} in session {{.SessionID}}{{with .AgentRef}} of agent {{.}}{{end}} raised {{.Type}}.
+State: {{.State}}
+{{- with .Message}}
+Message: {{.}}
+{{- end}}
+{{- if .TokenBudget}}
+Tokens used: {{.TotalTokens}} of a {{.TokenBudget}} token budget
+{{- end}}
+{{- range .ToolCalls}}
+Pending tool call: {{.Name}}
+{{- end}}
+`
+)
+
+// EmailConfig configures the email channel.
+type EmailConfig struct {
+	// SMTPAddress is the host:port of the SMTP server.
+	SMTPAddress string
+	// Username and Password authenticate with PLAIN auth when Username is
+	// set.
+	Username string
+	Password string
+	From     string
+	To       []string
+	// SubjectTemplate and BodyTemplate are text/templates rendered with the
+	// Event. Defaults describe the event in plain text.
+	SubjectTemplate string
+	BodyTemplate    string
+	Filter          Filter
+}
+
+// sendMailFunc matches smtp.SendMail.
+type sendMailFunc func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+
+// EmailChannel sends events as plain-text email over SMTP.
+type EmailChannel struct {
+	config   EmailConfig
+	auth     smtp.Auth
+	subject  *template.Template
+	body     *template.Template
+	sendMail sendMailFunc
+}
+
+var _ Channel = (*EmailChannel)(nil)
+
+// NewEmailChannel creates an email channel from config.
+func NewEmailChannel(config EmailConfig) (*EmailChannel, error) {
+	if config.SMTPAddress == "" || config.From == "" || len(config.To) == 0 {
+		return nil, fmt.Errorf("email requires an SMTP address, a sender and at least one recipient")
+	}
+	host, _, err := net.SplitHostPort(config.SMTPAddress)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SMTP address %q: %w", config.SMTPAddress, err)
+	}
+	if config.SubjectTemplate == "" {
+		config.SubjectTemplate = defaultEmailSubject
+	}
+	if config.BodyTemplate == "" {
+		config.BodyTemplate = defaultEmailBody
+	}
+	subject, err := template.New("subject").Parse(config.SubjectTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid email subject template: %w", err)
+	}
+	body, err := template.New("body").Parse(config.BodyTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid email body template: %w", err)
+	}
+	c := &EmailChannel{config: config, subject: subject, body: body, sendMail: smtp.SendMail}
+	if config.Username != "" {
+		c.auth = smtp.PlainAuth("", config.Username, config.Password, host)
+	}
+	return c, nil
+}
+
+// Name implements Channel.
+func (c *EmailChannel) Name() string {
+	return "email"
+}
+
+// Notify implements Channel. smtp.SendMail does not take a context, so a
+// delivery is bounded by the SMTP server's own timeouts.
+func (c *EmailChannel) Notify(_ context.Context, event Event) error {
+	if !c.config.Filter.Match(event) {
+		return nil
+	}
+	msg, err := c.message(event)
+	if err != nil {
+		return err
+	}
+	if err := c.sendMail(c.config.SMTPAddress, c.auth, c.config.From, c.config.To, msg); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// message renders event as an RFC 5322 message.
+func (c *EmailChannel) message(event Event) ([]byte, error) {
+	var subject, body bytes.Buffer
+	if err := c.subject.Execute(&subject, event); err != nil {
+		return nil, fmt.Errorf("failed to render email subject: %w", err)
+	}
+	if err := c.body.Execute(&body, event); err != nil {
+		return nil, fmt.Errorf("failed to render email body: %w", err)
+	}
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", c.config.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(c.config.To, ", "))
+	// Header values cannot span lines.
+	fmt.Fprintf(&msg, "Subject: %s\r\n", strings.Join(strings.Fields(subject.String()), " "))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(body.String(), "\n", "\r\n"))
+	return msg.Bytes(), nil
+}
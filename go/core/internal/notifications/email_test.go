@@ -0,0 +1,51 @@
+package notifications
+
+import (
+	"context"
+	"net/smtp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmailChannelNotify(t *testing.T) {
+	ch, err := NewEmailChannel(EmailConfig{
+		SMTPAddress: "smtp.example.com:587",
+		From:        "kagent@example.com",
+		To:          []string{"ops@example.com", "oncall@example.com"},
+		Filter:      Filter{Events: []EventType{EventBudgetExceeded}},
+	})
+	require.NoError(t, err)
+
+	var sent []byte
+	var recipients []string
+	ch.sendMail = func(addr string, _ smtp.Auth, from string, to []string, msg []byte) error {
+		assert.Equal(t, "smtp.example.com:587", addr)
+		assert.Equal(t, "kagent@example.com", from)
+		recipients = to
+		sent = msg
+		return nil
+	}
+
+	require.NoError(t, ch.Notify(context.Background(), Event{Type: EventTaskCompleted, TaskID: "task-0"}))
+	assert.Nil(t, sent, "filtered events are not sent")
+
+	require.NoError(t, ch.Notify(context.Background(), Event{
+		Type:        EventBudgetExceeded,
+		TaskID:      "task-1",
+		SessionID:   "session-1",
+		AgentRef:    "kagent/k8s-agent",
+		State:       "working",
+		TotalTokens: 12000,
+		TokenBudget: 10000,
+	}))
+	assert.Equal(t, []string{"ops@example.com", "oncall@example.com"}, recipients)
+	msg := string(sent)
+	assert.Contains(t, msg, "To: ops@example.com, oncall@example.com\r\n")
+	assert.Contains(t, msg, "Subject: [kagent] budget_exceeded: task task-1 of kagent/k8s-agent\r\n")
+	assert.Contains(t, msg, "Tokens used: 12000 of a 10000 token budget")
+
+	_, err = NewEmailChannel(EmailConfig{SMTPAddress: "smtp.example.com", From: "a@example.com", To: []string{"b@example.com"}})
+	assert.Error(t, err, "the SMTP address needs a port")
+}
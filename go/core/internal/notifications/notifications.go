@@ -0,0 +1,120 @@
+// Package notifications delivers task events to channels outside kagent, such
+// as Slack, a webhook or email, so that users learn about paused, finished
+// and failed tasks without polling the API.
+package notifications
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	api "github.com/kagent-dev/kagent/go/api/httpapi"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// EventType identifies what happened to a task.
+type EventType string
+
+const (
+	// EventApprovalRequired is sent when a task pauses on tool calls that
+	// need the user's approval.
+	EventApprovalRequired EventType = "approval_required"
+	// EventTaskCompleted is sent when a task completes.
+	EventTaskCompleted EventType = "task_completed"
+	// EventTaskFailed is sent when a task fails.
+	EventTaskFailed EventType = "task_failed"
+	// EventBudgetExceeded is sent when a task's token usage first reaches
+	// the configured budget.
+	EventBudgetExceeded EventType = "budget_exceeded"
+)
+
+// defaultTimeout bounds a single delivery to a channel.
+const defaultTimeout = 30 * time.Second
+
+// Event describes a task event to deliver.
+type Event struct {
+	Type      EventType
+	TaskID    string
+	SessionID string
+	// UserID owns the session; decisions made from a notification are made
+	// on their behalf.
+	UserID string
+	// AgentRef is the agent's namespace/name, empty when unknown.
+	AgentRef  string
+	ToolCalls []api.PendingToolCall
+	// State is the task state the event was raised for.
+	State string
+	// Message is the text of the task's status message, such as the error
+	// of a failed task.
+	Message string
+	// TotalTokens is the task's token usage so far, and TokenBudget the
+	// budget it was checked against.
+	TotalTokens int64
+	TokenBudget int64
+}
+
+// Filter selects the events a channel receives. Empty fields match
+// everything.
+type Filter struct {
+	// Events lists the event types to deliver.
+	Events []EventType
+	// Agents lists the namespace/name of the agents whose events are
+	// delivered.
+	Agents []string
+}
+
+// Match reports whether event passes the filter.
+func (f Filter) Match(event Event) bool {
+	if len(f.Events) > 0 && !slices.Contains(f.Events, event.Type) {
+		return false
+	}
+	return len(f.Agents) == 0 || slices.Contains(f.Agents, event.AgentRef)
+}
+
+// Channel delivers events to one destination.
+type Channel interface {
+	// Name identifies the channel in logs.
+	Name() string
+	// Notify delivers event. Channels ignore event types they do not handle.
+	Notify(ctx context.Context, event Event) error
+}
+
+// Dispatcher fans events out to its channels in the background. A nil
+// Dispatcher is valid and drops every event.
+type Dispatcher struct {
+	// TokenBudget, when positive, raises EventBudgetExceeded for tasks whose
+	// token usage reaches it.
+	TokenBudget int64
+
+	channels []Channel
+	timeout  time.Duration
+}
+
+// NewDispatcher creates a Dispatcher delivering to channels.
+func NewDispatcher(channels ...Channel) *Dispatcher {
+	return &Dispatcher{channels: channels, timeout: defaultTimeout}
+}
+
+// Enabled reports whether any channel is configured.
+func (d *Dispatcher) Enabled() bool {
+	return d != nil && len(d.channels) > 0
+}
+
+// Dispatch delivers event to every channel without blocking the caller.
+// Delivery outlives ctx's cancellation; failures are logged, not returned.
+func (d *Dispatcher) Dispatch(ctx context.Context, event Event) {
+	if !d.Enabled() {
+		return
+	}
+	log := ctrllog.FromContext(ctx).WithName("notifications").WithValues("event", event.Type, "task_id", event.TaskID)
+	ctx = context.WithoutCancel(ctx)
+	for _, ch := range d.channels {
+		go func() {
+			ctx, cancel := context.WithTimeout(ctx, d.timeout)
+			defer cancel()
+			if err := ch.Notify(ctx, event); err != nil {
+				log.Error(err, "Failed to deliver notification", "channel", ch.Name())
+			}
+		}()
+	}
+}
@@ -0,0 +1,314 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// slackPostMessageURL is the Web API method used with a bot token.
+	slackPostMessageURL = "https://slack.com/api/chat.postMessage"
+
+	// Slack action IDs of the approve and reject buttons.
+	SlackActionApprove = "kagent_approve"
+	SlackActionReject  = "kagent_reject"
+
+	// slackMaxRequestAge rejects interaction requests replayed later than
+	// Slack's recommended five minutes.
+	slackMaxRequestAge = 5 * time.Minute
+
+	// slackMaxArgsLen truncates tool arguments shown in a message.
+	slackMaxArgsLen = 500
+)
+
+// SlackConfig configures the Slack channel. Either WebhookURL or BotToken and
+// Channel must be set; a bot token takes precedence.
+type SlackConfig struct {
+	// WebhookURL is a Slack incoming webhook URL.
+	WebhookURL string
+	// BotToken is a bot token with chat:write, used with Channel.
+	BotToken string
+	// Channel is the channel ID or name messages are posted to.
+	Channel string
+	// UIBaseURL, when set, links each message to the session in the kagent UI.
+	UIBaseURL string
+}
+
+// SlackChannel posts approval requests to Slack with approve and reject
+// buttons. Button clicks come back through the interactions endpoint, which
+// must be configured as the Slack app's request URL.
+type SlackChannel struct {
+	config     SlackConfig
+	client     *http.Client
+	postURL    string
+	useWebhook bool
+}
+
+var _ Channel = (*SlackChannel)(nil)
+
+// NewSlackChannel creates a Slack channel from config.
+func NewSlackChannel(config SlackConfig) (*SlackChannel, error) {
+	c := &SlackChannel{config: config, client: &http.Client{Timeout: defaultTimeout}}
+	switch {
+	case config.BotToken != "":
+		if config.Channel == "" {
+			return nil, fmt.Errorf("slack bot token requires a channel")
+		}
+		c.postURL = slackPostMessageURL
+	case config.WebhookURL != "":
+		c.postURL = config.WebhookURL
+		c.useWebhook = true
+	default:
+		return nil, fmt.Errorf("slack requires a webhook URL or a bot token")
+	}
+	return c, nil
+}
+
+// Name implements Channel.
+func (c *SlackChannel) Name() string {
+	return "slack"
+}
+
+// Notify implements Channel, posting approval requests.
+func (c *SlackChannel) Notify(ctx context.Context, event Event) error {
+	if event.Type != EventApprovalRequired {
+		return nil
+	}
+	msg, err := c.approvalMessage(event)
+	if err != nil {
+		return err
+	}
+	if !c.useWebhook {
+		msg["channel"] = c.config.Channel
+	}
+	body, err := postSlackJSON(ctx, c.client, c.postURL, c.config.BotToken, msg)
+	if err != nil {
+		return err
+	}
+	if c.useWebhook {
+		return nil
+	}
+	// The Web API answers 200 with ok=false on failure.
+	var resp struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return fmt.Errorf("failed to decode slack response: %w", err)
+	}
+	if !resp.OK {
+		return fmt.Errorf("slack chat.postMessage failed: %s", resp.Error)
+	}
+	return nil
+}
+
+// approvalMessage builds the Block Kit message for an approval request.
+func (c *SlackChannel) approvalMessage(event Event) (map[string]any, error) {
+	value, err := json.Marshal(slackActionValue{TaskID: event.TaskID})
+	if err != nil {
+		return nil, err
+	}
+	agent := event.AgentRef
+	if agent == "" {
+		agent = "An agent"
+	}
+	summary := fmt.Sprintf("%s is waiting for approval of %d tool call(s)", agent, len(event.ToolCalls))
+
+	header := fmt.Sprintf("*%s*\nUser: `%s` · Task: `%s`", summary, event.UserID, event.TaskID)
+	if c.config.UIBaseURL != "" && event.AgentRef != "" {
+		header += fmt.Sprintf("\n<%s/agents/%s/chat/%s|Open session>", strings.TrimRight(c.config.UIBaseURL, "/"), event.AgentRef, event.SessionID)
+	}
+	blocks := []map[string]any{slackSection(header)}
+	for _, call := range event.ToolCalls {
+		args := "{}"
+		if len(call.Args) > 0 {
+			b, err := json.Marshal(call.Args)
+			if err != nil {
+				return nil, err
+			}
+			args = truncate(string(b), slackMaxArgsLen)
+		}
+		blocks = append(blocks, slackSection(fmt.Sprintf("`%s`\n```%s```", call.Name, args)))
+	}
+	blocks = append(blocks, map[string]any{
+		"type": "actions",
+		"elements": []map[string]any{
+			slackButton(SlackActionApprove, "Approve", "primary", string(value)),
+			slackButton(SlackActionReject, "Reject", "danger", string(value)),
+		},
+	})
+	return map[string]any{"text": summary, "blocks": blocks}, nil
+}
+
+// postSlackJSON sends payload as JSON to target and returns the response body.
+func postSlackJSON(ctx context.Context, client *http.Client, target, token string, payload any) ([]byte, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to post to slack: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read slack response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("slack returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
+
+func slackSection(text string) map[string]any {
+	return map[string]any{
+		"type": "section",
+		"text": map[string]any{"type": "mrkdwn", "text": text},
+	}
+}
+
+func slackButton(actionID, text, style, value string) map[string]any {
+	return map[string]any{
+		"type":      "button",
+		"action_id": actionID,
+		"text":      map[string]any{"type": "plain_text", "text": text},
+		"style":     style,
+		"value":     value,
+	}
+}
+
+// truncate shortens s to at most n runes, never splitting a multi-byte
+// character.
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n]) + "…"
+}
+
+// slackActionValue is carried in the value of the approve and reject buttons.
+// It names the task only: who decides is the user who clicked.
+type slackActionValue struct {
+	TaskID string `json:"task_id"`
+}
+
+// SlackInteraction is a click on an approve or reject button.
+type SlackInteraction struct {
+	// Decision is approve or reject.
+	Decision string
+	TaskID   string
+	// SlackUserID is the Slack user ID of whoever clicked.
+	SlackUserID string
+	// SlackUser is the Slack user name of whoever clicked.
+	SlackUser string
+	// ResponseURL updates the original message.
+	ResponseURL string
+}
+
+// VerifySlackRequest checks the X-Slack-Signature of an interaction request
+// against the app's signing secret, rejecting requests older than five
+// minutes.
+func VerifySlackRequest(signingSecret string, header http.Header, body []byte, now time.Time) error {
+	timestamp := header.Get("X-Slack-Request-Timestamp")
+	signature := header.Get("X-Slack-Signature")
+	if timestamp == "" || signature == "" {
+		return fmt.Errorf("missing slack signature headers")
+	}
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid slack request timestamp %q", timestamp)
+	}
+	if age := now.Sub(time.Unix(ts, 0)); age > slackMaxRequestAge || age < -slackMaxRequestAge {
+		return fmt.Errorf("slack request timestamp is outside the allowed window")
+	}
+	mac := hmac.New(sha256.New, []byte(signingSecret))
+	mac.Write([]byte("v0:" + timestamp + ":" + string(body))) //nolint:errcheck
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return fmt.Errorf("slack signature mismatch")
+	}
+	return nil
+}
+
+// ParseSlackInteraction decodes the form-encoded body of a block_actions
+// interaction into the kagent button that was clicked.
+func ParseSlackInteraction(body []byte) (*SlackInteraction, error) {
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, fmt.Errorf("invalid interaction body: %w", err)
+	}
+	var payload struct {
+		Type        string `json:"type"`
+		ResponseURL string `json:"response_url"`
+		User        struct {
+			ID       string `json:"id"`
+			Username string `json:"username"`
+			Name     string `json:"name"`
+		} `json:"user"`
+		Actions []struct {
+			ActionID string `json:"action_id"`
+			Value    string `json:"value"`
+		} `json:"actions"`
+	}
+	if err := json.Unmarshal([]byte(form.Get("payload")), &payload); err != nil {
+		return nil, fmt.Errorf("invalid interaction payload: %w", err)
+	}
+	if payload.Type != "block_actions" || len(payload.Actions) == 0 {
+		return nil, fmt.Errorf("unsupported interaction type %q", payload.Type)
+	}
+
+	action := payload.Actions[0]
+	if payload.User.ID == "" {
+		return nil, fmt.Errorf("interaction has no user")
+	}
+	interaction := &SlackInteraction{ResponseURL: payload.ResponseURL, SlackUserID: payload.User.ID, SlackUser: payload.User.Username}
+	if interaction.SlackUser == "" {
+		interaction.SlackUser = payload.User.Name
+	}
+	switch action.ActionID {
+	case SlackActionApprove:
+		interaction.Decision = "approve"
+	case SlackActionReject:
+		interaction.Decision = "reject"
+	default:
+		return nil, fmt.Errorf("unknown action %q", action.ActionID)
+	}
+	var value slackActionValue
+	if err := json.Unmarshal([]byte(action.Value), &value); err != nil || value.TaskID == "" {
+		return nil, fmt.Errorf("invalid action value %q", action.Value)
+	}
+	interaction.TaskID = value.TaskID
+	return interaction, nil
+}
+
+// RespondToSlackInteraction replaces the message an interaction came from
+// with text, removing its buttons.
+func RespondToSlackInteraction(ctx context.Context, responseURL, text string) error {
+	if responseURL == "" {
+		return nil
+	}
+	client := &http.Client{Timeout: defaultTimeout}
+	_, err := postSlackJSON(ctx, client, responseURL, "", map[string]any{"replace_original": true, "text": text})
+	return err
+}
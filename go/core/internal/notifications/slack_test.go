@@ -0,0 +1,110 @@
+package notifications
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	api "github.com/kagent-dev/kagent/go/api/httpapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func signSlackRequest(secret string, ts time.Time, body []byte) http.Header {
+	timestamp := strconv.FormatInt(ts.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + timestamp + ":" + string(body))) //nolint:errcheck
+	header := http.Header{}
+	header.Set("X-Slack-Request-Timestamp", timestamp)
+	header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+	return header
+}
+
+func TestVerifySlackRequest(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	body := []byte("payload=%7B%7D")
+
+	assert.NoError(t, VerifySlackRequest("secret", signSlackRequest("secret", now, body), body, now))
+	assert.Error(t, VerifySlackRequest("other", signSlackRequest("secret", now, body), body, now))
+	assert.Error(t, VerifySlackRequest("secret", signSlackRequest("secret", now, body), []byte("payload=tampered"), now))
+	assert.Error(t, VerifySlackRequest("secret", signSlackRequest("secret", now.Add(-10*time.Minute), body), body, now))
+	assert.Error(t, VerifySlackRequest("secret", http.Header{}, body, now))
+}
+
+func TestParseSlackInteraction(t *testing.T) {
+	payload := `{"type":"block_actions","response_url":"https://hooks.slack.test/r","user":{"id":"U123","username":"alice"},` +
+		`"actions":[{"action_id":"kagent_reject","value":"{\"task_id\":\"task-1\"}"}]}`
+	body := []byte(url.Values{"payload": {payload}}.Encode())
+
+	interaction, err := ParseSlackInteraction(body)
+	require.NoError(t, err)
+	assert.Equal(t, &SlackInteraction{
+		Decision:    "reject",
+		TaskID:      "task-1",
+		SlackUserID: "U123",
+		SlackUser:   "alice",
+		ResponseURL: "https://hooks.slack.test/r",
+	}, interaction)
+
+	_, err = ParseSlackInteraction([]byte(url.Values{"payload": {`{"type":"block_actions","user":{"id":"U123"},"actions":[{"action_id":"other","value":"{}"}]}`}}.Encode()))
+	assert.Error(t, err)
+	_, err = ParseSlackInteraction([]byte(url.Values{"payload": {`{"type":"block_actions","actions":[{"action_id":"kagent_approve","value":"{\"task_id\":\"task-1\"}"}]}`}}.Encode()))
+	assert.Error(t, err)
+	_, err = ParseSlackInteraction([]byte(url.Values{"payload": {`{"type":"view_submission"}`}}.Encode()))
+	assert.Error(t, err)
+}
+
+func TestSlackChannelNotify(t *testing.T) {
+	var posted map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		require.NoError(t, json.Unmarshal(body, &posted))
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	ch, err := NewSlackChannel(SlackConfig{WebhookURL: server.URL, UIBaseURL: "https://kagent.example.com/"})
+	require.NoError(t, err)
+
+	err = ch.Notify(context.Background(), Event{
+		Type:      EventApprovalRequired,
+		TaskID:    "task-1",
+		SessionID: "session-1",
+		UserID:    "user@example.com",
+		AgentRef:  "kagent/k8s-agent",
+		ToolCalls: []api.PendingToolCall{{ID: "call-1", Name: "k8s_delete_resource", Args: map[string]any{"name": "nginx"}}},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "kagent/k8s-agent is waiting for approval of 1 tool call(s)", posted["text"])
+	blocks := posted["blocks"].([]any)
+	require.Len(t, blocks, 3)
+	header := blocks[0].(map[string]any)["text"].(map[string]any)["text"].(string)
+	assert.Contains(t, header, "<https://kagent.example.com/agents/kagent/k8s-agent/chat/session-1|Open session>")
+	assert.Contains(t, blocks[1].(map[string]any)["text"].(map[string]any)["text"], `{"name":"nginx"}`)
+	buttons := blocks[2].(map[string]any)["elements"].([]any)
+	require.Len(t, buttons, 2)
+	assert.Equal(t, SlackActionApprove, buttons[0].(map[string]any)["action_id"])
+	assert.JSONEq(t, `{"task_id":"task-1"}`, buttons[0].(map[string]any)["value"].(string))
+
+	_, err = NewSlackChannel(SlackConfig{BotToken: "xoxb-token"})
+	assert.Error(t, err)
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "short", truncate("short", 10))
+	assert.Equal(t, "héllo…", truncate("héllo wörld", 5))
+	assert.Equal(t, "日本…", truncate("日本語のテキスト", 2))
+	assert.True(t, utf8.ValidString(truncate(strings.Repeat("ü", 600), slackMaxArgsLen)))
+}
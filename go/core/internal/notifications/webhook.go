@@ -0,0 +1,131 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/template"
+)
+
+// WebhookSignatureHeader carries the hex HMAC-SHA256 of the request body,
+// keyed with the webhook secret, when a secret is configured.
+const WebhookSignatureHeader = "X-Kagent-Signature"
+
+// WebhookConfig configures the webhook channel.
+type WebhookConfig struct {
+	// URL receives a POST per event.
+	URL string
+	// Secret, when set, signs each request body in WebhookSignatureHeader.
+	Secret string
+	// Template, when set, is a text/template rendered with the Event as the
+	// request body. The event is sent as JSON otherwise.
+	Template string
+	// ContentType of the rendered body; defaults to application/json.
+	ContentType string
+	Filter      Filter
+}
+
+// WebhookChannel posts events to an HTTP endpoint.
+type WebhookChannel struct {
+	config   WebhookConfig
+	template *template.Template
+	client   *http.Client
+}
+
+var _ Channel = (*WebhookChannel)(nil)
+
+// NewWebhookChannel creates a webhook channel from config.
+func NewWebhookChannel(config WebhookConfig) (*WebhookChannel, error) {
+	if config.URL == "" {
+		return nil, fmt.Errorf("webhook requires a URL")
+	}
+	if config.ContentType == "" {
+		config.ContentType = "application/json"
+	}
+	c := &WebhookChannel{config: config, client: &http.Client{Timeout: defaultTimeout}}
+	if config.Template != "" {
+		tmpl, err := template.New("webhook").Parse(config.Template)
+		if err != nil {
+			return nil, fmt.Errorf("invalid webhook template: %w", err)
+		}
+		c.template = tmpl
+	}
+	return c, nil
+}
+
+// Name implements Channel.
+func (c *WebhookChannel) Name() string {
+	return "webhook"
+}
+
+// Notify implements Channel.
+func (c *WebhookChannel) Notify(ctx context.Context, event Event) error {
+	if !c.config.Filter.Match(event) {
+		return nil
+	}
+	body, err := c.body(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", c.config.ContentType)
+	if c.config.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(c.config.Secret))
+		mac.Write(body) //nolint:errcheck
+		req.Header.Set(WebhookSignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+		return fmt.Errorf("webhook returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+func (c *WebhookChannel) body(event Event) ([]byte, error) {
+	if c.template == nil {
+		return json.Marshal(webhookPayload(event))
+	}
+	var buf bytes.Buffer
+	if err := c.template.Execute(&buf, event); err != nil {
+		return nil, fmt.Errorf("failed to render webhook template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// webhookPayload is the default JSON body of a webhook request.
+func webhookPayload(event Event) map[string]any {
+	payload := map[string]any{
+		"type":       event.Type,
+		"task_id":    event.TaskID,
+		"session_id": event.SessionID,
+		"user_id":    event.UserID,
+		"agent":      event.AgentRef,
+		"state":      event.State,
+	}
+	if event.Message != "" {
+		payload["message"] = event.Message
+	}
+	if len(event.ToolCalls) > 0 {
+		payload["tool_calls"] = event.ToolCalls
+	}
+	if event.TokenBudget > 0 {
+		payload["total_tokens"] = event.TotalTokens
+		payload["token_budget"] = event.TokenBudget
+	}
+	return payload
+}
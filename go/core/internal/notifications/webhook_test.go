@@ -0,0 +1,60 @@
+package notifications
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookChannelNotify(t *testing.T) {
+	var bodies [][]byte
+	var signatures []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, body)
+		signatures = append(signatures, r.Header.Get(WebhookSignatureHeader))
+	}))
+	defer server.Close()
+
+	failed := Event{Type: EventTaskFailed, TaskID: "task-1", AgentRef: "kagent/k8s-agent", State: "failed", Message: "model unavailable"}
+
+	ch, err := NewWebhookChannel(WebhookConfig{URL: server.URL, Secret: "secret", Filter: Filter{Events: []EventType{EventTaskFailed}}})
+	require.NoError(t, err)
+	require.NoError(t, ch.Notify(context.Background(), failed))
+	require.NoError(t, ch.Notify(context.Background(), Event{Type: EventTaskCompleted, TaskID: "task-2"}))
+	require.Len(t, bodies, 1, "filtered events are not posted")
+
+	var payload map[string]any
+	require.NoError(t, json.Unmarshal(bodies[0], &payload))
+	assert.Equal(t, "task_failed", payload["type"])
+	assert.Equal(t, "model unavailable", payload["message"])
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(bodies[0]) //nolint:errcheck
+	assert.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), signatures[0])
+
+	ch, err = NewWebhookChannel(WebhookConfig{URL: server.URL, Template: `{"text":"{{.AgentRef}}: {{.Type}} {{.TaskID}}"}`})
+	require.NoError(t, err)
+	require.NoError(t, ch.Notify(context.Background(), failed))
+	assert.JSONEq(t, `{"text":"kagent/k8s-agent: task_failed task-1"}`, string(bodies[1]))
+	assert.Empty(t, signatures[1])
+
+	_, err = NewWebhookChannel(WebhookConfig{URL: server.URL, Template: "{{.Missing"})
+	assert.Error(t, err)
+}
+
+func TestFilterMatch(t *testing.T) {
+	event := Event{Type: EventTaskCompleted, AgentRef: "kagent/a"}
+	assert.True(t, Filter{}.Match(event))
+	assert.True(t, Filter{Events: []EventType{EventTaskCompleted}, Agents: []string{"kagent/a"}}.Match(event))
+	assert.False(t, Filter{Events: []EventType{EventTaskFailed}}.Match(event))
+	assert.False(t, Filter{Agents: []string{"kagent/b"}}.Match(event))
+}
@@ -163,3 +163,14 @@ func ParseStringToFloat64(s string) *float64 {
 	}
 	return nil
 }
+
+// ParseStringToInt parses a string to int, returns nil if empty or invalid
+func ParseStringToInt(s string) *int {
+	if s == "" {
+		return nil
+	}
+	if val, err := strconv.Atoi(s); err == nil {
+		return &val
+	}
+	return nil
+}
@@ -64,7 +64,7 @@ func ToLegacyTask(task *a2av1.Task) (*trpc.Task, error) {
 		ContextID: task.ContextID,
 		Metadata:  task.Metadata,
 		Status: trpc.TaskStatus{
-			State:     toLegacyTaskState(task.Status.State),
+			State:     ToLegacyTaskState(task.Status.State),
 			Message:   message,
 			Timestamp: formatTimestamp(task.Status.Timestamp),
 		},
@@ -277,7 +277,8 @@ func toLegacyPart(part *a2av1.Part) (trpc.Part, error) {
 	}, nil
 }
 
-func toLegacyTaskState(state a2av1.TaskState) trpc.TaskState {
+// ToLegacyTaskState returns the legacy wire name of a task state.
+func ToLegacyTaskState(state a2av1.TaskState) trpc.TaskState {
 	switch state {
 	case a2av1.TaskStateSubmitted:
 		return trpc.TaskStateSubmitted
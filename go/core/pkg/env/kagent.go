@@ -74,6 +74,20 @@ var (
 		ComponentAgentRuntime,
 	)
 
+	KagentWorkspaceDir = RegisterStringVar(
+		"KAGENT_WORKSPACE_DIR",
+		"",
+		"Directory session workspaces are created in. Defaults to <tmp>/kagent.",
+		ComponentAgentRuntime,
+	)
+
+	KagentWorkspaceDriver = RegisterStringVar(
+		"KAGENT_WORKSPACE_DRIVER",
+		"local",
+		"Session workspace driver: local, or pvc to require a volume mounted at KAGENT_WORKSPACE_DIR.",
+		ComponentAgentRuntime,
+	)
+
 	KagentSRTSettingsPath = RegisterStringVar(
 		"KAGENT_SRT_SETTINGS_PATH",
 		"/config/srt-settings.json",
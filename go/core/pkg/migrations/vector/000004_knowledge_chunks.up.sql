@@ -0,0 +1,17 @@
+-- Chunks of the documents ingested into knowledge collections, searched by
+-- the retrieve_docs agent tool. A document is replaced by deleting its chunks
+-- and inserting the new ones.
+CREATE TABLE IF NOT EXISTS knowledge_chunk (
+    id           TEXT        PRIMARY KEY DEFAULT gen_random_uuid()::text,
+    collection   TEXT        NOT NULL,
+    document_id  TEXT        NOT NULL,
+    chunk_index  INTEGER     NOT NULL,
+    content      TEXT        NOT NULL,
+    start_offset INTEGER     NOT NULL,
+    end_offset   INTEGER     NOT NULL,
+    embedding    vector(768) NOT NULL,
+    metadata     JSONB       NOT NULL DEFAULT '{}',
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (collection, document_id, chunk_index)
+);
+CREATE INDEX IF NOT EXISTS idx_knowledge_chunk_embedding_hnsw ON knowledge_chunk USING hnsw (embedding vector_cosine_ops);
@@ -182,6 +182,24 @@ func requireAgentRuntime(t *testing.T, cli client.Client, agent *v1alpha2.Agent,
 	require.Equal(t, want, got.Spec.Declarative.Runtime)
 }
 
+// requireAgentNotAccepted waits for the agent's Accepted condition to turn
+// False with a message mentioning reason.
+func requireAgentNotAccepted(t *testing.T, cli client.Client, agent *v1alpha2.Agent, reason string) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		got := &v1alpha2.Agent{}
+		if err := cli.Get(t.Context(), client.ObjectKeyFromObject(agent), got); err != nil {
+			return false
+		}
+		for _, cond := range got.Status.Conditions {
+			if cond.Type == v1alpha2.AgentConditionTypeAccepted {
+				return cond.Status == metav1.ConditionFalse && strings.Contains(cond.Message, reason)
+			}
+		}
+		return false
+	}, time.Minute, time.Second, "agent %s should not be Accepted (%s)", agent.Name, reason)
+}
+
 // setupAgentWithOptions creates and returns an agent resource with custom options
 func setupAgentWithOptions(t *testing.T, cli client.Client, modelConfigName string, tools []*v1alpha2.Tool, opts AgentOptions) *v1alpha2.Agent {
 	agent := generateAgent(modelConfigName, tools, opts)
@@ -701,6 +719,53 @@ func TestE2EInvokeDeclarativeAgentWithMcpServerTool(t *testing.T) {
 	})
 }
 
+func TestE2EInvokeDeclarativeAgentWithToolRetryPolicy(t *testing.T) {
+	baseURL, stopServer := setupMockServer(t, "mocks/invoke_mcp_retry_agent.json")
+	defer stopServer()
+
+	cli := setupK8sClient(t, true)
+	mcpServer := setupMCPServer(t, cli)
+	// The mock model calls get-sum with non-numeric arguments, so every
+	// attempt fails and the model sees the retries-exhausted error code.
+	tools := []*v1alpha2.Tool{
+		{
+			Type: v1alpha2.ToolProviderType_McpServer,
+			McpServer: &v1alpha2.McpServerTool{
+				TypedReference: v1alpha2.TypedReference{
+					ApiGroup: "kagent.dev",
+					Kind:     "MCPServer",
+					Name:     mcpServer.Name,
+				},
+				ToolNames: []string{"get-sum"},
+				RetryPolicy: &v1alpha2.ToolRetryPolicy{
+					MaxAttempts:     2,
+					InitialBackoff:  &metav1.Duration{Duration: 100 * time.Millisecond},
+					RetryableErrors: []string{"(?i)fail"},
+				},
+			},
+		},
+	}
+	modelCfg := setupModelConfig(t, cli, baseURL)
+
+	t.Run("go_runtime", func(t *testing.T) {
+		agent := setupAgentWithOptions(t, cli, modelCfg.Name, tools, AgentOptions{Name: "tool-retry-test"})
+		requireAgentRuntime(t, cli, agent, v1alpha2.DeclarativeRuntime_Go)
+
+		a2aClient := setupA2AClient(t, agent)
+		runSyncTest(t, a2aClient, "add three and five", "kept failing after automatic retries", nil)
+	})
+
+	t.Run("python_runtime_rejected", func(t *testing.T) {
+		agent := generateAgent(modelCfg.Name, tools, AgentOptions{
+			Name:    "tool-retry-python-test",
+			Runtime: pythonRuntime(),
+		})
+		require.NoError(t, cli.Create(t.Context(), agent))
+		cleanup(t, cli, agent)
+		requireAgentNotAccepted(t, cli, agent, "retryPolicy")
+	})
+}
+
 // This function generates an OpenAI BYO agent that uses a mock LLM server
 // Assumes that the image is built and pushed to registry
 func generateOpenAIAgent(baseURL string) *v1alpha2.Agent {
@@ -1329,6 +1394,53 @@ func TestE2EAgentDefaultRuntimeIsGo(t *testing.T) {
 	})
 }
 
+func TestE2EAnthropicThinkingBudget(t *testing.T) {
+	baseURL, stopServer := setupMockServer(t, "mocks/invoke_anthropic_thinking_agent.json")
+	defer stopServer()
+
+	cli := setupK8sClient(t, false)
+	modelCfg := &v1alpha2.ModelConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "test-anthropic-thinking-",
+			Namespace:    "kagent",
+		},
+		Spec: v1alpha2.ModelConfigSpec{
+			Model:           "claude-sonnet-4-5",
+			APIKeySecret:    "kagent-openai",
+			APIKeySecretKey: "OPENAI_API_KEY",
+			Provider:        v1alpha2.ModelProviderAnthropic,
+			Anthropic: &v1alpha2.AnthropicConfig{
+				BaseURL:              baseURL,
+				MaxTokens:            4096,
+				ThinkingBudgetTokens: 2048,
+			},
+		},
+	}
+	require.NoError(t, cli.Create(t.Context(), modelCfg))
+	cleanup(t, cli, modelCfg)
+
+	t.Run("go_runtime", func(t *testing.T) {
+		agent := setupAgentWithOptions(t, cli, modelCfg.Name, nil, AgentOptions{
+			Name:          "thinking-budget-test",
+			SystemMessage: "You are a helpful test agent. Answer concisely.",
+		})
+		requireAgentRuntime(t, cli, agent, v1alpha2.DeclarativeRuntime_Go)
+
+		a2aClient := setupA2AClient(t, agent)
+		runSyncTest(t, a2aClient, "What is 6 times 7?", "42", nil)
+	})
+
+	t.Run("python_runtime_rejected", func(t *testing.T) {
+		agent := generateAgent(modelCfg.Name, nil, AgentOptions{
+			Name:    "thinking-budget-python-test",
+			Runtime: pythonRuntime(),
+		})
+		require.NoError(t, cli.Create(t.Context(), agent))
+		cleanup(t, cli, agent)
+		requireAgentNotAccepted(t, cli, agent, "thinkingBudgetTokens")
+	})
+}
+
 // runMemoryAgentTest is a helper that sets up an agent with memory enabled and
 // runs save/load memory subtests. extraOpts are merged into the base AgentOptions.
 func runMemoryAgentTest(t *testing.T, extraOpts AgentOptions) {
@@ -5,10 +5,10 @@ from __future__ import annotations
 import logging
 import os
 from functools import cached_property
-from typing import Optional
+from typing import Any, Optional
 
 from anthropic import AsyncAnthropic
-from google.adk.models.anthropic_llm import AnthropicLlm
+from google.adk.models.anthropic_llm import AnthropicLlm, Claude
 
 from ._ssl import KAgentTLSMixin
 
@@ -58,3 +58,58 @@ class KAgentAnthropicLlm(KAgentTLSMixin, AnthropicLlm):
             kwargs["http_client"] = http_client
 
         return AsyncAnthropic(**kwargs)
+
+
+def _with_request_defaults(client: Any, defaults: dict[str, Any]) -> Any:
+    """Fill the unset arguments of the client's message requests from defaults."""
+    if not defaults:
+        return client
+    messages = client.messages
+    create = messages.create
+
+    async def create_with_defaults(*args, **kwargs):
+        for key, value in defaults.items():
+            kwargs.setdefault(key, value)
+        return await create(*args, **kwargs)
+
+    messages.create = create_with_defaults
+
+    stream = getattr(messages, "stream", None)
+    if stream is not None:
+
+        def stream_with_defaults(*args, **kwargs):
+            for key, value in defaults.items():
+                kwargs.setdefault(key, value)
+            return stream(*args, **kwargs)
+
+        messages.stream = stream_with_defaults
+    return client
+
+
+class KAgentClaudeLlm(Claude):
+    """Anthropic on Vertex AI with the sampling settings of the model config.
+
+    ADK's Claude only sends max_tokens, so the other settings are added to
+    every messages request.
+    """
+
+    temperature: Optional[float] = None
+    top_p: Optional[float] = None
+    top_k: Optional[int] = None
+    stop_sequences: Optional[list[str]] = None
+
+    def _request_defaults(self) -> dict[str, Any]:
+        defaults: dict[str, Any] = {}
+        if self.temperature is not None:
+            defaults["temperature"] = self.temperature
+        if self.top_p is not None:
+            defaults["top_p"] = self.top_p
+        if self.top_k is not None:
+            defaults["top_k"] = self.top_k
+        if self.stop_sequences:
+            defaults["stop_sequences"] = self.stop_sequences
+        return defaults
+
+    @cached_property
+    def _anthropic_client(self):
+        return _with_request_defaults(super()._anthropic_client, self._request_defaults())
@@ -8,16 +8,16 @@ from google.adk.agents.callback_context import CallbackContext
 from google.adk.agents.llm_agent import ToolUnion
 from google.adk.agents.readonly_context import ReadonlyContext
 from google.adk.agents.remote_a2a_agent import AGENT_CARD_WELL_KNOWN_PATH, DEFAULT_TIMEOUT
-from google.adk.models.anthropic_llm import Claude as ClaudeLLM
 from google.adk.models.google_llm import Gemini as GeminiLLM
 from google.adk.tools.mcp_tool import SseConnectionParams, StreamableHTTPConnectionParams
+from google.genai import types as genai_types
 from pydantic import AliasChoices, BaseModel, Field, field_validator, model_validator
 
 from kagent.adk._approval import make_approval_callback, strip_confirmation_parts_callback
 from kagent.adk._mcp_toolset import KAgentMcpToolset
 from kagent.adk.models._ssl import create_ssl_context
 from kagent.adk._remote_a2a_tool import KAgentRemoteA2AToolset
-from kagent.adk.models._anthropic import KAgentAnthropicLlm
+from kagent.adk.models._anthropic import KAgentAnthropicLlm, KAgentClaudeLlm
 from kagent.adk.models._bedrock import KAgentBedrockLlm
 from kagent.adk.models._gemini import KAgentGeminiLlm
 from kagent.adk.models._ollama import create_ollama_llm
@@ -294,10 +294,24 @@ class Anthropic(BaseLLM):
 
 
 class GeminiVertexAI(BaseLLM):
+    temperature: float | None = None
+    top_p: float | None = None
+    top_k: float | None = None
+    stop_sequences: list[str] | None = None
+    max_output_tokens: int | None = None
+    candidate_count: int | None = None
+    response_mime_type: str | None = None
+
     type: Literal["gemini_vertex_ai"]
 
 
 class GeminiAnthropic(BaseLLM):
+    max_tokens: int | None = None
+    temperature: float | None = None
+    top_p: float | None = None
+    top_k: int | None = None
+    stop_sequences: list[str] | None = None
+
     type: Literal["gemini_anthropic"]
 
 
@@ -527,6 +541,7 @@ class AgentConfig(BaseModel):
             code_executor=code_executor,
             before_tool_callback=before_tool_callback,
             before_model_callback=before_model_callback,
+            generate_content_config=_generate_content_config(self.model),
         )
 
         # Configure memory if enabled
@@ -611,6 +626,28 @@ def _transport_kwargs(model_config: BaseLLM) -> dict[str, Any]:
     return kwargs
 
 
+def _generate_content_config(model_config: ModelUnion) -> genai_types.GenerateContentConfig | None:
+    """Build the agent's generation settings from a Gemini on Vertex AI model config.
+
+    Other model types carry their sampling settings on the model itself.
+    """
+    if model_config.type != "gemini_vertex_ai":
+        return None
+    settings = {
+        "temperature": model_config.temperature,
+        "top_p": model_config.top_p,
+        "top_k": model_config.top_k,
+        "stop_sequences": model_config.stop_sequences,
+        "max_output_tokens": model_config.max_output_tokens,
+        "candidate_count": model_config.candidate_count,
+        "response_mime_type": model_config.response_mime_type,
+    }
+    settings = {key: value for key, value in settings.items() if value is not None}
+    if not settings:
+        return None
+    return genai_types.GenerateContentConfig(**settings)
+
+
 def _create_llm_from_model_config(model_config: ModelUnion):
     extra_headers = model_config.headers or {}
     base_url = getattr(model_config, "base_url", None)
@@ -664,7 +701,17 @@ def _create_llm_from_model_config(model_config: ModelUnion):
     if model_config.type == "gemini_vertex_ai":
         return GeminiLLM(model=model_config.model)
     if model_config.type == "gemini_anthropic":
-        return ClaudeLLM(model=model_config.model)
+        claude_kwargs: dict[str, Any] = {}
+        if model_config.max_tokens is not None:
+            claude_kwargs["max_tokens"] = model_config.max_tokens
+        return KAgentClaudeLlm(
+            model=model_config.model,
+            temperature=model_config.temperature,
+            top_p=model_config.top_p,
+            top_k=model_config.top_k,
+            stop_sequences=model_config.stop_sequences,
+            **claude_kwargs,
+        )
     if model_config.type == "ollama":
         ollama_options = _convert_ollama_options(getattr(model_config, "options", None))
         # api key passthrough is not applicable for ollama
@@ -63,3 +63,32 @@ class TestKAgentAnthropicLlm:
         assert isinstance(result, KAgentAnthropicLlm)
         assert result.model == "claude-3-sonnet-20240229"
         assert result.base_url == "https://api.anthropic.com"
+
+
+class TestKAgentClaudeLlm:
+    async def test_sampling_settings_fill_message_requests(self):
+        from kagent.adk.models._anthropic import _with_request_defaults
+
+        client = mock.MagicMock()
+        create = mock.AsyncMock(return_value="response")
+        client.messages.create = create
+        _with_request_defaults(client, {"temperature": 0.2, "stop_sequences": ["END"]})
+
+        assert await client.messages.create(model="claude", max_tokens=1024, temperature=0.9) == "response"
+        create.assert_awaited_once_with(model="claude", max_tokens=1024, temperature=0.9, stop_sequences=["END"])
+
+    def test_create_llm_from_gemini_anthropic_model_config(self):
+        from kagent.adk.models._anthropic import KAgentClaudeLlm
+        from kagent.adk.types import GeminiAnthropic, _create_llm_from_model_config
+
+        config = GeminiAnthropic(
+            type="gemini_anthropic",
+            model="claude-sonnet-4@20250514",
+            max_tokens=2048,
+            temperature=0.3,
+            stop_sequences=["END"],
+        )
+        result = _create_llm_from_model_config(config)
+        assert isinstance(result, KAgentClaudeLlm)
+        assert result.max_tokens == 2048
+        assert result._request_defaults() == {"temperature": 0.3, "stop_sequences": ["END"]}
@@ -0,0 +1,30 @@
+"""Tests for the Gemini on Vertex AI generation settings."""
+
+from kagent.adk.types import GeminiVertexAI, OpenAI, _generate_content_config
+
+
+class TestGenerateContentConfig:
+    def test_vertex_settings(self):
+        config = GeminiVertexAI(
+            type="gemini_vertex_ai",
+            model="gemini-2.5-pro",
+            temperature=0.4,
+            top_k=20,
+            stop_sequences=["END"],
+            max_output_tokens=4096,
+            response_mime_type="application/json",
+        )
+        generate = _generate_content_config(config)
+        assert generate is not None
+        assert generate.temperature == 0.4
+        assert generate.top_k == 20
+        assert generate.top_p is None
+        assert generate.stop_sequences == ["END"]
+        assert generate.max_output_tokens == 4096
+        assert generate.response_mime_type == "application/json"
+
+    def test_no_settings(self):
+        assert _generate_content_config(GeminiVertexAI(type="gemini_vertex_ai", model="gemini-2.5-pro")) is None
+
+    def test_other_model_types(self):
+        assert _generate_content_config(OpenAI(type="openai", model="gpt-4.1", temperature=0.2)) is None